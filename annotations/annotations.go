@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package annotations writes the policy violations of the scan results as GitHub Actions workflow commands
+// (e.g., ::error file=main.go,line=1::...), which GitHub shows inline on the pull requests.
+package annotations
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/baseline"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/report"
+)
+
+// The annotation levels
+const (
+	Error   = "error"
+	Warning = "warning"
+)
+
+// levels are the annotation levels of the risk levels (the low risk licenses are not annotated)
+var levels = map[string]string{report.RiskHigh: Error, report.RiskMedium: Warning}
+
+// Annotation is a GitHub Actions annotation of a file (of its lines, when Line is not 0)
+type Annotation struct {
+	Level   string
+	File    string
+	Line    int
+	EndLine int
+	Title   string
+	Message string
+}
+
+// String returns the workflow command of the annotation, with the values escaped
+func (a Annotation) String() string {
+	properties := []string{"file=" + escapeProperty(a.File)}
+	if a.Line > 0 {
+		properties = append(properties, fmt.Sprintf("line=%v", a.Line), fmt.Sprintf("endLine=%v", a.EndLine))
+	}
+	if a.Title != "" {
+		properties = append(properties, "title="+escapeProperty(a.Title))
+	}
+	return fmt.Sprintf("::%v %v::%v", a.Level, strings.Join(properties, ","), escapeData(a.Message))
+}
+
+// FromResults returns an annotation for each match of the licenses with a high (error) or medium (warning)
+// risk level in the context, sorted by file and line. A high risk license which is only a choice of an allowed
+// expression (see report.RiskModel.Avoided) is a warning. The file paths are as scanned (with forward slashes), so
+// scan a directory relative to the repository root for GitHub to find the files.
+func FromResults(results []identifier.IdentifierResults, model *report.RiskModel, context report.RiskContext) []Annotation {
+	var ret []Annotation
+	for _, result := range results {
+		avoided := model.Avoided(result, context)
+		for id, matches := range result.Matches {
+			category := result.Classifications[id].Category
+			risk := model.Level(category, result.Obligations[id], context)
+			level, ok := levels[risk]
+			if !ok {
+				continue
+			}
+			if category == "" {
+				category = "uncategorized"
+			}
+			message := fmt.Sprintf("%v is a %v risk license (%v) with %v linking and %v distribution", id, risk, category, context.Linking, context.Distribution)
+			if avoided[id] {
+				level = Warning
+				message += ", a choice of the allowed " + result.Expression(id)
+			}
+			for _, m := range matches {
+				loc := identifier.Locate(result.OriginalText, m)
+				ret = append(ret, Annotation{
+					Level:   level,
+					File:    filepath.ToSlash(result.File),
+					Line:    loc.StartLine,
+					EndLine: loc.EndLine,
+					Title:   "License " + id,
+					Message: message,
+				})
+			}
+		}
+	}
+	sortAnnotations(ret)
+	return ret
+}
+
+// FromBaselineViolations returns an error annotation of each file with a license finding which is not in the
+// baseline. The file paths of the violations are relative to the root.
+func FromBaselineViolations(violations []baseline.Violation, root string) []Annotation {
+	var ret []Annotation
+	for _, v := range violations {
+		ret = append(ret, Annotation{
+			Level:   Error,
+			File:    filepath.ToSlash(filepath.Join(root, filepath.FromSlash(v.File))),
+			Title:   "License " + v.License,
+			Message: fmt.Sprintf("%v is a %v license finding which is not in the baseline", v.License, v.Status),
+		})
+	}
+	sortAnnotations(ret)
+	return ret
+}
+
+// sortAnnotations sorts the annotations by file, line, and title
+func sortAnnotations(annotations []Annotation) {
+	sort.Slice(annotations, func(i, j int) bool {
+		ai, aj := annotations[i], annotations[j]
+		if ai.File != aj.File {
+			return ai.File < aj.File
+		}
+		if ai.Line != aj.Line {
+			return ai.Line < aj.Line
+		}
+		return ai.Title < aj.Title
+	})
+}
+
+// Write writes the annotations, one workflow command per line
+func Write(w io.Writer, annotations []Annotation) error {
+	for _, a := range annotations {
+		if _, err := fmt.Fprintln(w, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// escapeData escapes the message of a workflow command
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a property value of a workflow command
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
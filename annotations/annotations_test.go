@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package annotations
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/baseline"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/report"
+)
+
+func TestFromResults(t *testing.T) {
+	t.Parallel()
+	text := "// header\n// GPL notice\n// more\ncode\n// LGPL\n"
+	results := []identifier.IdentifierResults{
+		{
+			File:            "src/main.go",
+			OriginalText:    text,
+			Matches:         map[string][]identifier.Match{"GPL-3.0-only": {{Begins: 10, Ends: 30}}, "LGPL-2.1-only": {{Begins: 39, Ends: 46}}, "MIT": {{Begins: 0, Ends: 8}}},
+			Classifications: map[string]licenses.Classification{"GPL-3.0-only": {Category: licenses.StrongCopyleft}, "LGPL-2.1-only": {Category: licenses.WeakCopyleft}, "MIT": {Category: licenses.Permissive}},
+		},
+		{
+			File:         "LICENSE",
+			OriginalText: "Acme License\n",
+			Matches:      map[string][]identifier.Match{"LicenseRef-Acme": {{Begins: 0, Ends: 11}}},
+		},
+		{
+			File:            "COPYING",
+			OriginalText:    "Dual License\n",
+			Matches:         map[string][]identifier.Match{"Acme-Dual": {{Begins: 0, Ends: 11}}},
+			Mappings:        map[string]string{"Acme-Dual": "MIT OR GPL-3.0-only"},
+			Classifications: map[string]licenses.Classification{"GPL-3.0-only": {Category: licenses.StrongCopyleft}, "MIT": {Category: licenses.Permissive}},
+		},
+	}
+	tests := []struct {
+		name    string
+		context report.RiskContext
+		want    []Annotation
+	}{
+		{
+			name:    "distributed",
+			context: report.DefaultRiskContext,
+			want: []Annotation{
+				{Level: Warning, File: "COPYING", Line: 1, EndLine: 1, Title: "License Acme-Dual", Message: "Acme-Dual is a high risk license (uncategorized) with dynamic linking and distributed distribution, a choice of the allowed MIT OR GPL-3.0-only"},
+				{Level: Error, File: "LICENSE", Line: 1, EndLine: 1, Title: "License LicenseRef-Acme", Message: "LicenseRef-Acme is a high risk license (uncategorized) with dynamic linking and distributed distribution"},
+				{Level: Error, File: "src/main.go", Line: 2, EndLine: 3, Title: "License GPL-3.0-only", Message: "GPL-3.0-only is a high risk license (strong-copyleft) with dynamic linking and distributed distribution"},
+				{Level: Warning, File: "src/main.go", Line: 5, EndLine: 5, Title: "License LGPL-2.1-only", Message: "LGPL-2.1-only is a medium risk license (weak-copyleft) with dynamic linking and distributed distribution"},
+			},
+		},
+		{
+			name:    "internal",
+			context: report.RiskContext{Linking: report.DynamicLinking, Distribution: report.InternalDistribution},
+			want: []Annotation{
+				{Level: Warning, File: "COPYING", Line: 1, EndLine: 1, Title: "License Acme-Dual", Message: "Acme-Dual is a high risk license (uncategorized) with dynamic linking and internal distribution, a choice of the allowed MIT OR GPL-3.0-only"},
+				{Level: Error, File: "LICENSE", Line: 1, EndLine: 1, Title: "License LicenseRef-Acme", Message: "LicenseRef-Acme is a high risk license (uncategorized) with dynamic linking and internal distribution"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := FromResults(results, &report.DefaultRiskModel, tt.context)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("FromResults() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestFromBaselineViolations(t *testing.T) {
+	t.Parallel()
+	violations := []baseline.Violation{
+		{Finding: baseline.Finding{File: "src/main.go", License: "GPL-3.0-only"}, Status: baseline.New},
+		{Finding: baseline.Finding{File: "LICENSE", License: "MIT"}, Status: baseline.Changed},
+	}
+	want := []Annotation{
+		{Level: Error, File: "repo/LICENSE", Title: "License MIT", Message: "MIT is a changed license finding which is not in the baseline"},
+		{Level: Error, File: "repo/src/main.go", Title: "License GPL-3.0-only", Message: "GPL-3.0-only is a new license finding which is not in the baseline"},
+	}
+	if d := cmp.Diff(want, FromBaselineViolations(violations, "repo")); d != "" {
+		t.Errorf("FromBaselineViolations() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	annotations := []Annotation{
+		{Level: Error, File: "a,b:c.go", Line: 3, EndLine: 7, Title: "License GPL-3.0-only", Message: "100% copyleft\nsecond line"},
+		{Level: Warning, File: "LICENSE", Message: "no line"},
+	}
+	want := "::error file=a%2Cb%3Ac.go,line=3,endLine=7,title=License GPL-3.0-only::100%25 copyleft%0Asecond line\n" +
+		"::warning file=LICENSE::no line\n"
+	var b bytes.Buffer
+	if err := Write(&b, annotations); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if d := cmp.Diff(want, b.String()); d != "" {
+		t.Errorf("Write() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// ErrUnknownResourceSet is returned for a resource set name which is not loaded
+var ErrUnknownResourceSet = errors.New("unknown resource set")
+
+// ResourceSet is a Scanner of a named resource configuration (e.g., the custom patterns of a team), with its
+// config (e.g., for the --riskModel policy of the team)
+type ResourceSet struct {
+	Name    string
+	Scanner *Scanner
+	Config  *viper.Viper
+}
+
+// ResourceSets are the resource sets of an application which scans for several teams, so that one instance
+// serves them all. They are safe for concurrent use.
+type ResourceSets struct {
+	sets map[string]*ResourceSet
+	// defaultName is the resource set of the scans which do not select one ("" to require one)
+	defaultName string
+}
+
+// ResourceSetsFile is a file of the settings of the resource sets (YAML or JSON)
+type ResourceSetsFile struct {
+	// Default is the resource set of the scans which do not select one ("" to require one)
+	Default string `json:"default,omitempty" yaml:"default,omitempty"`
+	// Sets are the flag settings of each resource set by name (e.g., custom, spdx, configPath, only, riskModel)
+	Sets map[string]map[string]string `json:"sets" yaml:"sets"`
+}
+
+// LoadResourceSets reads a resource sets file (YAML or JSON) and loads the license library of each resource set
+func LoadResourceSets(filePath string) (*ResourceSets, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	f := ResourceSetsFile{}
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("cannot parse the resource sets %v: %w", filePath, err)
+	}
+	sets, err := NewResourceSets(f.Sets, f.Default)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource sets %v: %w", filePath, err)
+	}
+	return sets, nil
+}
+
+// NewResourceSets loads the license library of each resource set with the default flags and its settings
+// (the values of the flags by name), e.g., {"team-a": {"custom": "team-a", "riskModel": "team-a.yaml"}}. The
+// default is the resource set of the scans which do not select one ("" to require one).
+func NewResourceSets(settings map[string]map[string]string, defaultName string) (*ResourceSets, error) {
+	if len(settings) == 0 {
+		return nil, errors.New("no resource sets")
+	}
+	if _, ok := settings[defaultName]; defaultName != "" && !ok {
+		return nil, fmt.Errorf("%w %q (the default)", ErrUnknownResourceSet, defaultName)
+	}
+	s := &ResourceSets{sets: make(map[string]*ResourceSet, len(settings)), defaultName: defaultName}
+	for name, flags := range settings {
+		if name == "" || strings.Contains(name, "/") {
+			return nil, fmt.Errorf("invalid resource set name %q", name)
+		}
+		flagSet := configurer.NewDefaultFlags()
+		for flag, value := range flags {
+			if err := flagSet.Set(flag, value); err != nil {
+				return nil, fmt.Errorf("invalid %v setting of the resource set %v: %w", flag, name, err)
+			}
+		}
+		cfg, err := configurer.InitConfig(flagSet)
+		if err != nil {
+			return nil, fmt.Errorf("resource set %v: %w", name, err)
+		}
+		sc, err := newScanner(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("resource set %v: %w", name, err)
+		}
+		s.sets[name] = &ResourceSet{Name: name, Scanner: sc, Config: cfg}
+	}
+	return s, nil
+}
+
+// Names returns the names of the resource sets (sorted)
+func (s *ResourceSets) Names() []string {
+	names := make([]string, 0, len(s.sets))
+	for name := range s.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the resource set with the name, or the default resource set when the name is ""
+func (s *ResourceSets) Get(name string) (*ResourceSet, error) {
+	if name == "" {
+		if s.defaultName == "" {
+			return nil, errors.New("no resource set was selected and there is no default resource set")
+		}
+		name = s.defaultName
+	}
+	set, ok := s.sets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q", ErrUnknownResourceSet, name)
+	}
+	return set, nil
+}
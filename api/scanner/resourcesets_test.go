@@ -0,0 +1,120 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package scanner_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/api/scanner"
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLoadResourceSets(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "resource-sets.yaml")
+	content := `default: team-a
+sets:
+  team-a:
+    configPath: ../../testdata/resources
+  team-b:
+    configPath: ../../testdata/resources
+    custom: customTest2
+    linking: static
+`
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sets, err := scanner.LoadResourceSets(file)
+	if err != nil {
+		t.Fatalf("LoadResourceSets() error = %v", err)
+	}
+	if d := cmp.Diff([]string{"team-a", "team-b"}, sets.Names()); d != "" {
+		t.Errorf("Names() mismatch (-want +got):\n%s", d)
+	}
+
+	tests := []struct {
+		name    string
+		set     string
+		wantSet string
+		wantErr bool
+	}{
+		{name: "default", wantSet: "team-a"},
+		{name: "named", set: "team-b", wantSet: "team-b"},
+		{name: "unknown", set: "team-c", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			set, err := sets.Get(tt.set)
+			if tt.wantErr {
+				if !errors.Is(err, scanner.ErrUnknownResourceSet) {
+					t.Errorf("Get() error = %v, want %v", err, scanner.ErrUnknownResourceSet)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if set.Name != tt.wantSet {
+				t.Errorf("Get() = %v, want %v", set.Name, tt.wantSet)
+			}
+		})
+	}
+
+	// Each resource set scans with its own custom patterns and has its own policy settings
+	for name, want := range map[string]string{"team-a": scanner.NOASSERTION_SPDX_NAME, "team-b": "Test 2.0 (T2-Family)"} {
+		set, err := sets.Get(name)
+		if err != nil {
+			t.Fatalf("Get(%v) error = %v", name, err)
+		}
+		results := set.Scanner.ScanLicenseText(scanner.ScanSpec{LicenseText: "test2 matches"})
+		if got := results[0].CycloneDXLicenses[0].License.Name; got != want {
+			t.Errorf("%v license = %v, want %v", name, got, want)
+		}
+	}
+	if set, _ := sets.Get("team-b"); set.Config.GetString(configurer.LinkingFlag) != "static" {
+		t.Errorf("team-b linking = %v, want static", set.Config.GetString(configurer.LinkingFlag))
+	}
+}
+
+func TestNewResourceSets_errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		settings    map[string]map[string]string
+		defaultName string
+	}{
+		{name: "no sets"},
+		{name: "unknown default", settings: map[string]map[string]string{"team-a": nil}, defaultName: "team-b"},
+		{name: "unknown flag", settings: map[string]map[string]string{"team-a": {"bogus": "x"}}},
+		{name: "invalid name", settings: map[string]map[string]string{"team/a": nil}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := scanner.NewResourceSets(tt.settings, tt.defaultName); err == nil {
+				t.Error("NewResourceSets() error = nil, want an error")
+			}
+		})
+	}
+
+	sets, err := scanner.NewResourceSets(map[string]map[string]string{"team-a": {configurer.ConfigPathFlag: "../../testdata/resources"}}, "")
+	if err != nil {
+		t.Fatalf("NewResourceSets() error = %v", err)
+	}
+	if _, err := sets.Get(""); err == nil {
+		t.Error("Get(\"\") without a default error = nil, want an error")
+	}
+	if _, err := sets.Get("team-b"); !errors.Is(err, scanner.ErrUnknownResourceSet) {
+		t.Errorf("Get(team-b) error = %v, want %v", err, scanner.ErrUnknownResourceSet)
+	}
+}
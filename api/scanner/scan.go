@@ -4,6 +4,7 @@ package scanner
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -100,6 +101,30 @@ type ScanResult struct {
 	Error error
 	// a list of LicenseMatch i.e. a list of SPDX license IDs in sequential order, the matches of the input text across the various licenses
 	CycloneDXLicenses Licenses
+	// true when the template or file timeout aborted matching, so the licenses may be incomplete
+	TimedOut bool
+}
+
+// clone returns a copy of the result which shares nothing that can be changed with it
+func (r *ScanResult) clone() *ScanResult {
+	c := *r
+	if r.Hash != nil {
+		hash := *r.Hash
+		c.Hash = &hash
+	}
+	c.CycloneDXLicenses = make(Licenses, len(r.CycloneDXLicenses))
+	for i, choice := range r.CycloneDXLicenses {
+		if choice.License != nil {
+			l := *choice.License
+			if l.Text != nil {
+				text := *l.Text
+				l.Text = &text
+			}
+			choice.License = &l
+		}
+		c.CycloneDXLicenses[i] = choice
+	}
+	return &c
 }
 
 // WithConfig sets the config to use for the scan
@@ -110,37 +135,31 @@ func (s *ScanSpecs) WithFlags(flags *pflag.FlagSet) *ScanSpecs {
 
 // ScanLicenseText scans the specified license file to retrieve license information
 func (s *ScanSpecs) ScanLicenseText() ([]*ScanResult, error) {
-	cfg, err := configurer.InitConfig(s.flags)
-	if err != nil {
-		return nil, err
-	}
-	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	sc, err := NewScanner(s.flags)
 	if err != nil {
 		return nil, err
 	}
+	return sc.ScanLicenseText(s.Specs...), nil
+}
 
-	// initialize the license data set to compare against
-	if err := licenseLibrary.AddAll(); err != nil {
-		return nil, err
-	}
-
-	var r []*ScanResult
-
-	// resultsCache is a local cache holding the results of scanned license text
-	// this cache is searched before every scan to get the scan results if they exist
-	// this cache is updated after every new license match found
-	resultsCache := make(map[normalizer.Digest]*ScanResult)
+// ScanLicenseText scans the specified license file to retrieve license information. The resultsCache
+// map is not locked, so it must not be shared by goroutines (see Scanner).
+func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resultsCache map[normalizer.Digest]*ScanResult) *ScanResult {
+	return s.scan(licenseLibrary, scanOptions(licenseLibrary), mapCache(resultsCache))
+}
 
-	for _, p := range s.Specs {
-		// identify license information for the specified license text
-		scanResult := p.ScanLicenseText(licenseLibrary, resultsCache)
-		r = append(r, scanResult)
+// scanOptions are the identifier options from the config of the library
+func scanOptions(licenseLibrary *licenses.LicenseLibrary) identifier.Options {
+	var options identifier.Options
+	if cfg := licenseLibrary.Config; cfg != nil {
+		options.TemplateTimeout = cfg.GetDuration(configurer.TemplateTimeoutFlag)
+		options.FileTimeout = cfg.GetDuration(configurer.FileTimeoutFlag)
 	}
-	return r, nil
+	return options
 }
 
-// ScanLicenseText scans the specified license file to retrieve license information
-func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resultsCache map[normalizer.Digest]*ScanResult) *ScanResult {
+// scan identifies the license text with the library, using and updating the cache of the results
+func (s *ScanSpec) scan(licenseLibrary *licenses.LicenseLibrary, options identifier.Options, cache resultsCache) *ScanResult {
 	// create a scanResult with the specifications and licenseText
 	r := &ScanResult{
 		Spec:              *s,
@@ -151,6 +170,7 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 	// instantiate normalizedData with the input license text
 	normalizedData := normalizer.NormalizationData{
 		OriginalText: s.LicenseText,
+		LinePrefixes: licenseLibrary.LinePrefixes,
 	}
 
 	// normalize the input license text
@@ -165,20 +185,27 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 
 	// check the cache in memory if we have seen the same license before
 	// return the result if it exists in the cache to avoid running identification for it
-	if cachedResult, ok := resultsCache[*r.Hash]; ok {
+	if cachedResult, ok := cache.get(*r.Hash); ok {
 		return cachedResult
 	}
 
 	// find the licenses in the normalized text and return a list of SPDX IDs
 	// in case of an error, return as much as we have along with an error
-	results, err := identifier.Identify(identifier.Options{}, licenseLibrary, normalizedData)
+	results, err := identifier.Identify(options, licenseLibrary, normalizedData)
 	if err != nil {
 		r.Error = err
 		return r
 	}
 
-	// if the results are empty, add unknown as the SPDX ID
-	if len(results.Matches) == 0 {
+	// if the results are empty, add the LicenseRef of the license-like text (if any) or unknown as the SPDX name
+	if e := results.ExtractedLicense; len(results.Matches) == 0 && e != nil {
+		r.CycloneDXLicenses = append(r.CycloneDXLicenses, LicenseChoice{
+			License: &License{
+				Name: e.ID,
+				Text: &AttachedText{Content: e.Text, ContentType: "text/plain"},
+			},
+		})
+	} else if len(results.Matches) == 0 {
 		// Add NOASSERTION to the LicenseChoice of the SPDX Name for this scan
 		r.CycloneDXLicenses = append(r.CycloneDXLicenses, LicenseChoice{
 			License: &License{
@@ -186,8 +213,13 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 			},
 		})
 	} else {
-		// iterate over the list of matches and maintain the unique list of SPDX IDs in the result
+		// iterate over the list of matches (sorted by ID) and maintain the unique list of SPDX IDs in the result
+		ids := make([]string, 0, len(results.Matches))
 		for id := range results.Matches {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
 			// Add an SPDX ID from the match
 			// update the LicenseChoice to include each new match
 
@@ -197,9 +229,18 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 			if family != "" {
 				name = fmt.Sprintf("%s (%s)", name, family)
 			}
+			// a license ID which the license map maps to a compound expression is the expression
+			spdxID, mapped := licenseLibrary.MappedExpression(id)
+			if mapped && strings.Contains(spdxID, " ") {
+				r.CycloneDXLicenses = append(r.CycloneDXLicenses, LicenseChoice{Expression: spdxID})
+				continue
+			}
+			if !mapped {
+				spdxID = id
+			}
 			r.CycloneDXLicenses = append(r.CycloneDXLicenses, LicenseChoice{
 				License: &License{
-					ID:   id,
+					ID:   spdxID,
 					Name: name,
 					// TODO: verify whether this is acceptable or just expect a single license here
 					URL: strings.Join(licenseLibrary.LicenseMap[id].LicenseInfo.URLs, ","),
@@ -214,8 +255,11 @@ func (s *ScanSpec) ScanLicenseText(licenseLibrary *licenses.LicenseLibrary, resu
 		}
 	}
 
-	// populate the results cache to keep the match in memory for next license match
-	resultsCache[*r.Hash] = r
+	// populate the results cache to keep the match in memory for next license match (unless incomplete)
+	r.TimedOut = results.TimedOut || len(results.TimedOutTemplates) > 0
+	if !r.TimedOut {
+		cache.put(*r.Hash, r)
+	}
 
 	return r
 }
@@ -5,6 +5,7 @@
 package scanner_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/IBM/license-scanner/api/scanner"
@@ -23,6 +24,7 @@ func TestScanner_Resources(t *testing.T) {
 		licName       string
 		spdxMatches   []string
 		customMatches []string
+		extracted     bool // unmatched, the license-like text has a LicenseRef
 	}{
 		{
 			name: "nada",
@@ -46,6 +48,7 @@ func TestScanner_Resources(t *testing.T) {
 			licID:         "AAL",
 			spdxMatches:   []string{doNotSet, "0.1234"}, // config is using 0.1234
 			customMatches: []string{},
+			extracted:     true,
 		},
 		{
 			name:          "both 0BSD",
@@ -54,6 +57,7 @@ func TestScanner_Resources(t *testing.T) {
 			licName:       "BSD Zero Clause License (BSD)",
 			spdxMatches:   []string{doNotSet, "0.1234"}, // config is using 0.1234
 			customMatches: []string{doNotSet},
+			extracted:     true,
 		},
 	}
 
@@ -99,9 +103,13 @@ func TestScanner_Resources(t *testing.T) {
 						if slices.Contains(tt.customMatches, custom) && lic.Name != tt.licName {
 							t.Errorf("did not find expected custom name: want %v got %v", tt.licName, lic.Name)
 						}
-						if !slices.Contains(tt.spdxMatches, spdx) && !slices.Contains(tt.customMatches, custom) && lic.Name != scanner.NOASSERTION_SPDX_NAME {
+						unmatched := !slices.Contains(tt.spdxMatches, spdx) && !slices.Contains(tt.customMatches, custom)
+						if unmatched && !tt.extracted && lic.Name != scanner.NOASSERTION_SPDX_NAME {
 							t.Errorf("did not find expected name: want %v got %v", scanner.NOASSERTION_SPDX_NAME, lic.Name)
 						}
+						if unmatched && tt.extracted && (!strings.HasPrefix(lic.Name, "LicenseRef-") || lic.Text == nil || lic.Text.Content != strings.TrimSpace(tt.text)) {
+							t.Errorf("did not find expected extracted license: want LicenseRef-<hash> with the text got %v", lic.Name)
+						}
 					}
 				})
 			}
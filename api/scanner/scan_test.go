@@ -5,9 +5,12 @@
 package scanner_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -201,6 +204,108 @@ func TestScanSpec_ScanLicenseText_With_CachedResults(t *testing.T) {
 	}
 }
 
+func TestScanner_ScanLicenseText_Concurrent(t *testing.T) {
+	resourcesFlag := configurer.NewDefaultFlags()
+	_ = resourcesFlag.Set(configurer.ConfigPathFlag, "../../testdata/config/")
+	sc, err := scanner.NewScanner(resourcesFlag)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	specs := []scanner.ScanSpec{
+		{LicenseText: "Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted."},
+		{LicenseText: "Licensed under the Apache License, Version 2.0 (the \"License\");\nyou may not use this file except in compliance with the License."},
+		{LicenseText: "no license here"},
+	}
+	want := sc.ScanLicenseText(specs...)
+
+	// one library serves the goroutines, and they share the cache of the results
+	var wg sync.WaitGroup
+	got := make([][]*scanner.ScanResult, 8)
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got[i] = sc.ScanLicenseText(specs...)
+		}(i)
+	}
+	wg.Wait()
+	for i := range got {
+		if d := cmp.Diff(want, got[i], cmpopts.EquateErrors()); d != "" {
+			t.Errorf("ScanLicenseText() of goroutine %v mismatch (-want +got):\n%s", i, d)
+		}
+	}
+}
+
+func TestScanner_ScanLicenseText_Copies(t *testing.T) {
+	resourcesFlag := configurer.NewDefaultFlags()
+	_ = resourcesFlag.Set(configurer.ConfigPathFlag, "../../testdata/config/")
+	sc, err := scanner.NewScanner(resourcesFlag)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	text := "Licensed under the Apache License, Version 2.0 (the \"License\");\nyou may not use this file except in compliance with the License."
+	want := sc.ScanText(text)
+	if len(want.CycloneDXLicenses) == 0 || want.CycloneDXLicenses[0].License == nil {
+		t.Fatalf("ScanText() = %+v, want a license", want.CycloneDXLicenses)
+	}
+	id := want.CycloneDXLicenses[0].License.ID
+
+	// a caller which changes its result does not change the cached result of the others
+	changed := sc.ScanText(text)
+	changed.CycloneDXLicenses[0].License.ID = "changed"
+	changed.Hash.Sha256 = "changed"
+	got := sc.ScanText(text)
+	if got.CycloneDXLicenses[0].License.ID != id || got.Hash.Sha256 != want.Hash.Sha256 {
+		t.Errorf("ScanText() = %+v, want the cached result unchanged", got)
+	}
+}
+
+func TestNewScannerFromFS(t *testing.T) {
+	sc, err := scanner.NewScannerFromFS(fstest.MapFS{
+		"custom/default/license_patterns/Curated/license_info.json":   {Data: []byte(`{"name": "Curated License"}`)},
+		"custom/default/license_patterns/Curated/license_Curated.txt": {Data: []byte("Permission is granted to use this software for any purpose, without a fee.")},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewScannerFromFS() error = %v", err)
+	}
+	got := sc.ScanText("Copyright 2023 Example\n\nPermission is granted to use this software for any purpose, without a fee.")
+	if got.Error != nil || len(got.CycloneDXLicenses) != 1 || got.CycloneDXLicenses[0].License.Name != "Curated License" {
+		t.Errorf("ScanText() = %+v, want the license of the FS", got)
+	}
+}
+
+func TestNewScannerFromCompiled(t *testing.T) {
+	resourcesFlag := configurer.NewDefaultFlags()
+	_ = resourcesFlag.Set(configurer.ConfigPathFlag, "../../testdata/config/")
+	sc, err := scanner.NewScanner(resourcesFlag)
+	if err != nil {
+		t.Fatalf("NewScanner() error = %v", err)
+	}
+	var compiled bytes.Buffer
+	if err := sc.LicenseLibrary().WriteCompiled(&compiled); err != nil {
+		t.Fatalf("WriteCompiled() error = %v", err)
+	}
+
+	// the scanner of the compiled library (without the resources) has the results of the scanner of the resources
+	fromCompiled, err := scanner.NewScannerFromCompiled(&compiled, resourcesFlag)
+	if err != nil {
+		t.Fatalf("NewScannerFromCompiled() error = %v", err)
+	}
+	for _, text := range []string{
+		"Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted.",
+		"Licensed under the Apache License, Version 2.0 (the \"License\");\nyou may not use this file except in compliance with the License.",
+		"no license here",
+	} {
+		if d := cmp.Diff(sc.ScanText(text), fromCompiled.ScanText(text), cmpopts.EquateErrors()); d != "" {
+			t.Errorf("ScanText(%q) mismatch (-want +got):\n%s", text, d)
+		}
+	}
+
+	if _, err := scanner.NewScannerFromCompiled(bytes.NewReader([]byte("not compiled")), resourcesFlag); err == nil {
+		t.Error("NewScannerFromCompiled() of a corrupt library error = nil, want an error")
+	}
+}
+
 func TestScanSpecs_ScanFile(t *testing.T) {
 	async_specs := scanner.ScanSpec{
 		Name:     "async",
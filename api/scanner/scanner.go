@@ -0,0 +1,201 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package scanner
+
+import (
+	"container/list"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// DefaultCacheSize is the number of the results of different normalized texts which a Scanner keeps by default
+const DefaultCacheSize = 1000
+
+// Scanner scans license texts with a license library which is loaded once. It is safe for concurrent use,
+// so a server can share one Scanner between its requests instead of loading the library for each request.
+type Scanner struct {
+	licenseLibrary *licenses.LicenseLibrary
+	options        identifier.Options
+	cache          *lockedCache
+}
+
+// NewScanner loads the license library with the config of the flags (the defaults when nil)
+func NewScanner(flags *pflag.FlagSet) (*Scanner, error) {
+	cfg, err := configurer.InitConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	return newScanner(cfg)
+}
+
+// newScanner loads the license library with the config
+func newScanner(cfg *viper.Viper) (*Scanner, error) {
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// initialize the license data set to compare against
+	if err := licenseLibrary.AddAll(); err != nil {
+		return nil, err
+	}
+	return NewScannerWithLibrary(licenseLibrary), nil
+}
+
+// NewScannerFromFS loads the license library from the spdx and custom resources in the fsys (e.g., a curated
+// dataset which is embedded with go:embed) instead of the resources dir, with the config of the flags (the
+// defaults when nil)
+func NewScannerFromFS(fsys fs.FS, flags *pflag.FlagSet) (*Scanner, error) {
+	cfg, err := configurer.InitConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	licenseLibrary.ResourcesFS = fsys
+	if err := licenseLibrary.AddAll(); err != nil {
+		return nil, err
+	}
+	return NewScannerWithLibrary(licenseLibrary), nil
+}
+
+// NewScannerFromCompiled loads the license library from a compiled library (see licenses.ReadCompiled) with the
+// config of the flags (the defaults when nil), without reading the resources, e.g., for a WebAssembly build
+func NewScannerFromCompiled(r io.Reader, flags *pflag.FlagSet) (*Scanner, error) {
+	cfg, err := configurer.InitConfig(flags)
+	if err != nil {
+		return nil, err
+	}
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := licenseLibrary.AddCompiled(r); err != nil {
+		return nil, err
+	}
+	return NewScannerWithLibrary(licenseLibrary), nil
+}
+
+// NewScannerWithLibrary returns a Scanner of a loaded license library. The library must not be changed
+// (e.g., with AddAll or Filter) while the Scanner is used.
+func NewScannerWithLibrary(licenseLibrary *licenses.LicenseLibrary) *Scanner {
+	return &Scanner{
+		licenseLibrary: licenseLibrary,
+		options:        scanOptions(licenseLibrary),
+		cache:          newLockedCache(DefaultCacheSize),
+	}
+}
+
+// WithCacheSize sets the number of the results of different normalized texts which the Scanner keeps (the
+// least recently used are dropped), and 0 to not cache the results. It is called before the Scanner is used.
+func (sc *Scanner) WithCacheSize(size int) *Scanner {
+	sc.cache = newLockedCache(size)
+	return sc
+}
+
+// LicenseLibrary returns the loaded license library (to be read, not changed)
+func (sc *Scanner) LicenseLibrary() *licenses.LicenseLibrary {
+	return sc.licenseLibrary
+}
+
+// ScanLicenseText scans the license texts of the specs, in order. The results of the same normalized text
+// are cached (see WithCacheSize), and each caller gets its own copy.
+func (sc *Scanner) ScanLicenseText(specs ...ScanSpec) []*ScanResult {
+	var r []*ScanResult
+	for _, p := range specs {
+		// identify license information for the specified license text
+		r = append(r, p.scan(sc.licenseLibrary, sc.options, sc.cache))
+	}
+	return r
+}
+
+// ScanText scans one license text (see ScanLicenseText)
+func (sc *Scanner) ScanText(text string) *ScanResult {
+	return sc.ScanLicenseText(ScanSpec{LicenseText: text})[0]
+}
+
+// resultsCache has the results of the scanned license texts by the hash of their normalized text
+type resultsCache interface {
+	get(digest normalizer.Digest) (*ScanResult, bool)
+	put(digest normalizer.Digest, r *ScanResult)
+}
+
+// mapCache is a resultsCache for one goroutine
+type mapCache map[normalizer.Digest]*ScanResult
+
+func (c mapCache) get(digest normalizer.Digest) (*ScanResult, bool) {
+	r, ok := c[digest]
+	return r, ok
+}
+
+func (c mapCache) put(digest normalizer.Digest, r *ScanResult) {
+	c[digest] = r
+}
+
+// lockedCache is a resultsCache for concurrent use, which keeps the results of the size most recently used
+// digests. It keeps and returns copies of the results, so that the callers can change theirs.
+type lockedCache struct {
+	mu   sync.Mutex
+	size int
+	// recent are the cacheEntry elements, from the most recently used
+	recent  *list.List
+	results map[normalizer.Digest]*list.Element
+}
+
+// cacheEntry is a result of the lockedCache
+type cacheEntry struct {
+	digest normalizer.Digest
+	result *ScanResult
+}
+
+func newLockedCache(size int) *lockedCache {
+	return &lockedCache{size: size, recent: list.New(), results: make(map[normalizer.Digest]*list.Element)}
+}
+
+func (c *lockedCache) get(digest normalizer.Digest) (*ScanResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.results[digest]
+	if !ok {
+		return nil, false
+	}
+	c.recent.MoveToFront(e)
+	return e.Value.(*cacheEntry).result.clone(), true
+}
+
+func (c *lockedCache) put(digest normalizer.Digest, r *ScanResult) {
+	if c.size <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.results[digest]; ok {
+		e.Value.(*cacheEntry).result = r.clone()
+		c.recent.MoveToFront(e)
+		return
+	}
+	c.results[digest] = c.recent.PushFront(&cacheEntry{digest: digest, result: r.clone()})
+	if c.recent.Len() > c.size {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.results, oldest.Value.(*cacheEntry).digest)
+	}
+}
+
+// len returns the number of the cached results
+func (c *lockedCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.recent.Len()
+}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package scanner
+
+import (
+	"testing"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func TestLockedCache(t *testing.T) {
+	t.Parallel()
+	a, b, c := normalizer.Digest{Sha256: "a"}, normalizer.Digest{Sha256: "b"}, normalizer.Digest{Sha256: "c"}
+	cache := newLockedCache(2)
+	cache.put(a, &ScanResult{OriginalText: "a"})
+	cache.put(b, &ScanResult{OriginalText: "b"})
+	if _, ok := cache.get(a); !ok {
+		t.Fatalf("get(a) expected a cached result")
+	}
+	cache.put(c, &ScanResult{OriginalText: "c"}) // drops b, the least recently used
+	if _, ok := cache.get(b); ok {
+		t.Errorf("get(b) expected the result to be dropped")
+	}
+	for _, d := range []normalizer.Digest{a, c} {
+		if r, ok := cache.get(d); !ok || r.OriginalText != d.Sha256 {
+			t.Errorf("get(%v) = %v, %v, want the cached result", d.Sha256, r, ok)
+		}
+	}
+	if n := cache.len(); n != 2 {
+		t.Errorf("len() = %v, want 2", n)
+	}
+
+	disabled := newLockedCache(0)
+	disabled.put(a, &ScanResult{})
+	if _, ok := disabled.get(a); ok {
+		t.Errorf("get() of a cache of size 0 expected no result")
+	}
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package attestation wraps the scan results in an in-toto statement (https://in-toto.io), with the digest of the
+// scanned file or directory as the subject and the license findings as the predicate, so that a license scan can
+// be attached to the supply-chain attestations (e.g., SLSA provenance) of an artifact.
+package attestation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/jsonl"
+	"github.com/IBM/license-scanner/metadata"
+)
+
+const (
+	// StatementType is the type of an in-toto statement (version 1)
+	StatementType = "https://in-toto.io/Statement/v1"
+	// PredicateType is the type of the license scan predicate
+	PredicateType = "https://github.com/IBM/license-scanner/license-scan/v1"
+)
+
+// Statement is an in-toto statement about the scanned artifact
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+// Subject is the scanned artifact with its digest
+type Subject struct {
+	// Name is the base name of the scanned file or directory
+	Name string `json:"name"`
+	// Digest has the sha256 of the file, or of the relative paths and the SHA-256 of the scanned files of the directory
+	Digest map[string]string `json:"digest"`
+}
+
+// Predicate is the license findings of the scan
+type Predicate struct {
+	// Status is the license state of the scan (the best state of the files)
+	Status string `json:"status"`
+	// Licenses are the license IDs detected in any file (sorted)
+	Licenses []string `json:"licenses"`
+	// Files are the results of the scanned files (sorted by path, relative to the scanned directory)
+	Files []jsonl.Record `json:"files"`
+	// Metadata is the metadata of the scan (see the metadata package)
+	Metadata metadata.Metadata `json:"metadata"`
+}
+
+// New returns the statement of the results of the scanned file or directory, with the metadata of the scan. The
+// digest of a directory is of the scanned files (read again from the disk), in the order of their paths.
+func New(scanned string, results []identifier.IdentifierResults, m metadata.Metadata) (Statement, error) {
+	root := scanned
+	info, err := os.Stat(scanned)
+	if err != nil {
+		return Statement{}, err
+	}
+	if !info.IsDir() {
+		root = filepath.Dir(scanned)
+	}
+	p := Predicate{Status: identifier.ScanStatus(results), Licenses: []string{}, Files: []jsonl.Record{}, Metadata: m}
+	all := make(map[string]bool)
+	for _, result := range results {
+		r := jsonl.FromResult(result, root)
+		p.Files = append(p.Files, r)
+		for _, id := range r.Licenses {
+			all[id] = true
+		}
+	}
+	for id := range all {
+		p.Licenses = append(p.Licenses, id)
+	}
+	sort.Strings(p.Licenses)
+	sort.Slice(p.Files, func(i, j int) bool { return p.Files[i].File < p.Files[j].File })
+
+	var sum string
+	if info.IsDir() {
+		sum, err = checksumFiles(p.Files, root)
+	} else {
+		sum, err = metadata.ChecksumFile(scanned)
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+	abs, err := filepath.Abs(scanned)
+	if err != nil {
+		return Statement{}, err
+	}
+	return Statement{
+		Type:          StatementType,
+		Subject:       []Subject{{Name: filepath.Base(abs), Digest: map[string]string{"sha256": sum}}},
+		PredicateType: PredicateType,
+		Predicate:     p,
+	}, nil
+}
+
+// checksumFiles returns the SHA-256 of the relative paths and the SHA-256 of the contents of the files (sorted by path)
+func checksumFiles(files []jsonl.Record, root string) (string, error) {
+	h := sha256.New()
+	for _, f := range files {
+		sum, err := metadata.ChecksumFile(filepath.Join(root, filepath.FromSlash(f.File)))
+		if err != nil {
+			return "", err
+		}
+		if _, err := io.WriteString(h, f.File+"\x00"+sum+"\n"); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Save writes the statement to the file
+func (s Statement) Save(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := s.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes the statement as indented JSON
+func (s Statement) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// Sign signs the statement file with cosign (sign-blob), with the key (a cosign key reference, or "" to sign keyless
+// with an OIDC identity), and returns the path of the signature bundle (the statement file with a .bundle suffix).
+// The cosign command must be installed.
+func Sign(filePath string, key string) (string, error) {
+	bundle := filePath + ".bundle"
+	args := []string{"sign-blob", "--yes", "--bundle", bundle}
+	if key != "" {
+		args = append(args, "--key", key)
+	}
+	cmd := exec.Command("cosign", append(args, filePath)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("cosign sign-blob: %w: %v", err, strings.TrimSpace(stderr.String()))
+	}
+	return bundle, nil
+}
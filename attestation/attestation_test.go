@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package attestation
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/jsonl"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/version"
+)
+
+// writeFile writes the text to the file in the directory and returns its path
+func writeFile(t *testing.T, dir string, name string, text string) string {
+	t.Helper()
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte(text), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// sha returns the hex SHA-256 of the text
+func sha(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "project")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	license := writeFile(t, dir, "LICENSE", "MIT License")
+	readme := writeFile(t, dir, "README", "read me")
+	results := []identifier.IdentifierResults{
+		{File: readme, Hash: normalizer.Digest{Sha256: "bbb"}},
+		{File: license, Hash: normalizer.Digest{Sha256: "aaa"}, Matches: map[string][]identifier.Match{"MIT": {{Begins: 0, Ends: 10}}}},
+	}
+	m := metadata.Metadata{Version: version.Info{Version: "1.2.3"}, ConfigHash: "ccc"}
+
+	tests := []struct {
+		name    string
+		scanned string
+		results []identifier.IdentifierResults
+		want    Statement
+	}{
+		{
+			name:    "directory",
+			scanned: dir,
+			results: results,
+			want: Statement{
+				Type:          StatementType,
+				Subject:       []Subject{{Name: "project", Digest: map[string]string{"sha256": sha("LICENSE\x00" + sha("MIT License") + "\nREADME\x00" + sha("read me") + "\n")}}},
+				PredicateType: PredicateType,
+				Predicate: Predicate{
+					Status:   identifier.Licensed,
+					Licenses: []string{"MIT"},
+					Files: []jsonl.Record{
+						{File: "LICENSE", Hash: "aaa", Status: identifier.Licensed, Licenses: []string{"MIT"}, Matches: map[string][]jsonl.Location{"MIT": {{Begins: 0, Ends: 10}}}},
+						{File: "README", Hash: "bbb", Status: identifier.NoLicense, Licenses: []string{}, Matches: map[string][]jsonl.Location{}},
+					},
+					Metadata: m,
+				},
+			},
+		},
+		{
+			name:    "file",
+			scanned: readme,
+			results: results[:1],
+			want: Statement{
+				Type:          StatementType,
+				Subject:       []Subject{{Name: "README", Digest: map[string]string{"sha256": sha("read me")}}},
+				PredicateType: PredicateType,
+				Predicate: Predicate{
+					Status:   identifier.NoLicense,
+					Licenses: []string{},
+					Files:    []jsonl.Record{{File: "README", Hash: "bbb", Status: identifier.NoLicense, Licenses: []string{}, Matches: map[string][]jsonl.Location{}}},
+					Metadata: m,
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := New(tt.scanned, tt.results, m)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("New() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestStatement_Write(t *testing.T) {
+	t.Parallel()
+	s := Statement{Type: StatementType, Subject: []Subject{{Name: "LICENSE", Digest: map[string]string{"sha256": "aaa"}}}, PredicateType: PredicateType}
+	var out bytes.Buffer
+	if err := s.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if got["_type"] != StatementType || got["predicateType"] != PredicateType {
+		t.Errorf("Write() = %v, want the in-toto statement and predicate types", out.String())
+	}
+}
+
+func TestSign(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake cosign is a shell script")
+	}
+	// A fake cosign which writes its arguments to the bundle file (after --bundle)
+	bin := t.TempDir()
+	writeFile(t, bin, "cosign", "#!/bin/sh\nargs=\"$*\"\nwhile [ \"$1\" != \"--bundle\" ]; do shift; done\necho \"$args\" > \"$2\"\n")
+	if err := os.Chmod(filepath.Join(bin, "cosign"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	statement := writeFile(t, t.TempDir(), "scan.intoto.json", "{}")
+
+	bundle, err := Sign(statement, "cosign.key")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if bundle != statement+".bundle" {
+		t.Errorf("Sign() = %v, want %v", bundle, statement+".bundle")
+	}
+	b, err := os.ReadFile(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "sign-blob --yes --bundle " + bundle + " --key cosign.key " + statement + "\n"
+	if d := cmp.Diff(want, string(b)); d != "" {
+		t.Errorf("Sign() cosign arguments mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package baseline checks the scan results against a checked-in baseline of accepted findings
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// Finding is a license detected in a file. The hash is the SHA-256 of the normalized file text, so
+// whitespace and formatting edits do not change it.
+type Finding struct {
+	File    string `json:"file"`
+	Hash    string `json:"hash"`
+	License string `json:"license"`
+}
+
+// Baseline is the accepted findings
+type Baseline struct {
+	Findings []Finding `json:"findings"`
+}
+
+// Status is why a finding is not accepted by the baseline
+type Status string
+
+const (
+	// New is a finding in a file which has no accepted findings
+	New Status = "new"
+	// Changed is a finding in a file which has accepted findings, but not with this content and license
+	Changed Status = "changed"
+)
+
+// Violation is a finding which is not accepted by the baseline
+type Violation struct {
+	Finding
+	Status Status
+}
+
+// FromResults returns the findings of the results as a baseline. The file paths are relative to the root.
+func FromResults(results []identifier.IdentifierResults, root string) *Baseline {
+	b := &Baseline{Findings: []Finding{}}
+	for _, result := range results {
+		file := relPath(result.File, root)
+		for id := range result.Matches {
+			b.Findings = append(b.Findings, Finding{File: file, Hash: result.Hash.Sha256, License: id})
+		}
+	}
+	sort.Slice(b.Findings, func(i, j int) bool {
+		if b.Findings[i].File != b.Findings[j].File {
+			return b.Findings[i].File < b.Findings[j].File
+		}
+		return b.Findings[i].License < b.Findings[j].License
+	})
+	return b
+}
+
+// Load reads a baseline file
+func Load(filePath string) (*Baseline, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b := &Baseline{}
+	if err := json.NewDecoder(f).Decode(b); err != nil {
+		return nil, fmt.Errorf("cannot parse the baseline %v: %w", filePath, err)
+	}
+	return b, nil
+}
+
+// Save writes the baseline file
+func (b *Baseline) Save(filePath string) error {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := b.Write(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Write writes the baseline as indented JSON
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// Check returns the findings of the results which are not accepted by the baseline. A finding is
+// accepted when the baseline has the same license in a file with the same hash (so an accepted file
+// may be moved or copied). Findings which are no longer detected are not violations.
+func (b *Baseline) Check(results []identifier.IdentifierResults, root string) []Violation {
+	accepted := make(map[Finding]bool)
+	files := make(map[string]bool)
+	for _, f := range b.Findings {
+		accepted[Finding{Hash: f.Hash, License: f.License}] = true
+		files[f.File] = true
+	}
+
+	var ret []Violation
+	for _, f := range FromResults(results, root).Findings {
+		if accepted[Finding{Hash: f.Hash, License: f.License}] {
+			continue
+		}
+		status := New
+		if files[f.File] {
+			status = Changed
+		}
+		ret = append(ret, Violation{Finding: f, Status: status})
+	}
+	return ret
+}
+
+// relPath returns the path relative to the root with forward slashes (or the path, when it is not under the root)
+func relPath(path string, root string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
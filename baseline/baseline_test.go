@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package baseline
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func result(file string, hash string, ids ...string) identifier.IdentifierResults {
+	r := identifier.IdentifierResults{File: file, Hash: normalizer.Digest{Sha256: hash}, Matches: make(map[string][]identifier.Match)}
+	for _, id := range ids {
+		r.Matches[id] = []identifier.Match{{Begins: 0, Ends: 10}}
+	}
+	return r
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+	accepted := []identifier.IdentifierResults{
+		result("/proj/LICENSE", "aaa", "MIT"),
+		result("/proj/vendor/lib/COPYING", "bbb", "GPL-2.0-only", "MIT"),
+		result("/proj/README", "ccc"),
+	}
+	b := FromResults(accepted, "/proj")
+	want := &Baseline{Findings: []Finding{
+		{File: "LICENSE", Hash: "aaa", License: "MIT"},
+		{File: "vendor/lib/COPYING", Hash: "bbb", License: "GPL-2.0-only"},
+		{File: "vendor/lib/COPYING", Hash: "bbb", License: "MIT"},
+	}}
+	if d := cmp.Diff(want, b); d != "" {
+		t.Fatalf("FromResults() mismatch (-want +got):\n%s", d)
+	}
+
+	tests := []struct {
+		name    string
+		results []identifier.IdentifierResults
+		want    []Violation
+	}{
+		{
+			name:    "same findings",
+			results: accepted,
+		},
+		{
+			name:    "moved file and removed finding",
+			results: []identifier.IdentifierResults{result("/proj/LICENSE.txt", "aaa", "MIT")},
+		},
+		{
+			name: "new file and changed file",
+			results: []identifier.IdentifierResults{
+				result("/proj/LICENSE", "ddd", "Apache-2.0"),
+				result("/proj/vendor/lib/COPYING", "bbb", "GPL-2.0-only", "MIT"),
+				result("/proj/src/util.c", "eee", "GPL-3.0-only"),
+			},
+			want: []Violation{
+				{Finding: Finding{File: "LICENSE", Hash: "ddd", License: "Apache-2.0"}, Status: Changed},
+				{Finding: Finding{File: "src/util.c", Hash: "eee", License: "GPL-3.0-only"}, Status: New},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, b.Check(tt.results, "/proj")); d != "" {
+				t.Errorf("Check() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+	b := &Baseline{Findings: []Finding{{File: "LICENSE", Hash: "aaa", License: "MIT"}}}
+	filePath := filepath.Join(t.TempDir(), "baseline.json")
+	if err := b.Save(filePath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if d := cmp.Diff(b, got); d != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", d)
+	}
+}
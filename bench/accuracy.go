@@ -0,0 +1,233 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bench measures the accuracy of the license detection with a labeled corpus (files with their
+// expected license IDs), to safely change the normalizer or the matcher.
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// The default labels files in the corpus directory
+var DefaultLabelsFiles = []string{"labels.yaml", "labels.yml", "labels.json"}
+
+// tolerance is the least decrease of a score which is a regression (not a rounding difference)
+const tolerance = 1e-9
+
+// Labels are the expected license IDs by file (relative to the corpus directory, with forward slashes).
+// A file with no IDs is expected to have no licenses.
+type Labels map[string][]string
+
+// LoadLabels reads a labels file: a YAML (or JSON) map of the file paths to the lists of license IDs
+func LoadLabels(file string) (Labels, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var labels Labels
+	if err := yaml.Unmarshal(b, &labels); err != nil {
+		return nil, fmt.Errorf("labels file %v error: %w", file, err)
+	}
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("labels file %v has no files", file)
+	}
+	return labels, nil
+}
+
+// FindLabels returns the first of the DefaultLabelsFiles in the corpus directory
+func FindLabels(corpus string) (string, error) {
+	for _, name := range DefaultLabelsFiles {
+		f := filepath.Join(corpus, name)
+		if _, err := os.Stat(f); err == nil {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("corpus %v has no labels file (%v)", corpus, DefaultLabelsFiles)
+}
+
+// Files returns the labeled files in the corpus directory (sorted)
+func (l Labels) Files(corpus string) []string {
+	ret := make([]string, 0, len(l))
+	for f := range l {
+		ret = append(ret, filepath.Join(corpus, filepath.FromSlash(f)))
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Metrics are the detection counts and scores of a license (or of all the licenses)
+type Metrics struct {
+	TruePositives  int     `json:"truePositives"`
+	FalsePositives int     `json:"falsePositives"`
+	FalseNegatives int     `json:"falseNegatives"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// score computes the precision, recall, and F1 from the counts (0 when undefined)
+func (m *Metrics) score() {
+	m.Precision = ratio(m.TruePositives, m.TruePositives+m.FalsePositives)
+	m.Recall = ratio(m.TruePositives, m.TruePositives+m.FalseNegatives)
+	if m.Precision+m.Recall > 0 {
+		m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+	}
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
+
+// Miss is a file with a wrong detection
+type Miss struct {
+	File string `json:"file"`
+	// Missing are the expected license IDs which were not detected, and Unexpected the detected license
+	// IDs which were not expected
+	Missing    []string `json:"missing,omitempty"`
+	Unexpected []string `json:"unexpected,omitempty"`
+}
+
+// Report is the accuracy of a scan of a labeled corpus. It is also the baseline of later scans.
+type Report struct {
+	Files int `json:"files"`
+	// Overall are the metrics of all the licenses (micro-averaged)
+	Overall  Metrics            `json:"overall"`
+	Licenses map[string]Metrics `json:"licenses"`
+	// Misses are the files with a wrong detection (sorted)
+	Misses []Miss `json:"misses,omitempty"`
+}
+
+// Evaluate compares the detected license IDs of the labeled files with their labels. The results are
+// the scan results of the labeled files in the corpus directory.
+func Evaluate(labels Labels, results []identifier.IdentifierResults, corpus string) Report {
+	detected := make(map[string]map[string]bool, len(results))
+	for _, result := range results {
+		f := result.File
+		if rel, err := filepath.Rel(corpus, result.File); err == nil {
+			f = filepath.ToSlash(rel)
+		}
+		ids := make(map[string]bool, len(result.Matches))
+		for id := range result.Matches {
+			ids[id] = true
+		}
+		detected[f] = ids
+	}
+
+	r := Report{Files: len(labels), Licenses: make(map[string]Metrics)}
+	count := func(id string, add func(*Metrics)) {
+		m := r.Licenses[id]
+		add(&m)
+		r.Licenses[id] = m
+		add(&r.Overall)
+	}
+	files := make([]string, 0, len(labels))
+	for f := range labels {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		expected := make(map[string]bool, len(labels[f]))
+		for _, id := range labels[f] {
+			expected[id] = true
+		}
+		miss := Miss{File: f}
+		for id := range expected {
+			if detected[f][id] {
+				count(id, func(m *Metrics) { m.TruePositives++ })
+			} else {
+				count(id, func(m *Metrics) { m.FalseNegatives++ })
+				miss.Missing = append(miss.Missing, id)
+			}
+		}
+		for id := range detected[f] {
+			if !expected[id] {
+				count(id, func(m *Metrics) { m.FalsePositives++ })
+				miss.Unexpected = append(miss.Unexpected, id)
+			}
+		}
+		if len(miss.Missing) > 0 || len(miss.Unexpected) > 0 {
+			sort.Strings(miss.Missing)
+			sort.Strings(miss.Unexpected)
+			r.Misses = append(r.Misses, miss)
+		}
+	}
+	for id, m := range r.Licenses {
+		m.score()
+		r.Licenses[id] = m
+	}
+	r.Overall.score()
+	return r
+}
+
+// IDs returns the license IDs of the report (sorted)
+func (r Report) IDs() []string {
+	ret := make([]string, 0, len(r.Licenses))
+	for id := range r.Licenses {
+		ret = append(ret, id)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Load reads a report written by Save (e.g., the stored baseline)
+func Load(file string) (Report, error) {
+	var r Report
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return r, err
+	}
+	if err := json.Unmarshal(b, &r); err != nil {
+		return r, fmt.Errorf("accuracy report %v error: %w", file, err)
+	}
+	return r, nil
+}
+
+// Save writes the report as JSON
+func (r Report) Save(file string) error {
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(b, '\n'), 0o644)
+}
+
+// OverallID is the license ID of the overall metrics in the regressions
+const OverallID = "(overall)"
+
+// Regression is a license which is detected less accurately than in the baseline
+type Regression struct {
+	ID     string
+	Before Metrics
+	After  Metrics
+}
+
+// Regressions compares the report with the baseline. A license (or the OverallID) regressed when its F1
+// decreased or it has more false positives or false negatives (e.g., a new false positive of a license
+// which was not in the baseline). The licenses which are only in the baseline (e.g., their files were
+// removed from the corpus) are not compared.
+func Regressions(baseline, current Report) []Regression {
+	regressed := func(before, after Metrics) bool {
+		return after.F1 < before.F1-tolerance || after.FalsePositives > before.FalsePositives || after.FalseNegatives > before.FalseNegatives
+	}
+	var ret []Regression
+	if regressed(baseline.Overall, current.Overall) {
+		ret = append(ret, Regression{ID: OverallID, Before: baseline.Overall, After: current.Overall})
+	}
+	for _, id := range current.IDs() {
+		if before, after := baseline.Licenses[id], current.Licenses[id]; regressed(before, after) {
+			ret = append(ret, Regression{ID: id, Before: before, After: after})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package bench
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// result returns a result with a match of each license
+func result(file string, ids ...string) identifier.IdentifierResults {
+	r := identifier.IdentifierResults{File: file, Matches: make(map[string][]identifier.Match)}
+	for _, id := range ids {
+		r.Matches[id] = []identifier.Match{{Begins: 0, Ends: 10}}
+	}
+	return r
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+	labels := Labels{
+		"LICENSE":       {"MIT"},
+		"dual/LICENSE":  {"Apache-2.0", "MIT"},
+		"src/main.go":   {},
+		"vendor/NOTICE": {"BSD-3-Clause"},
+	}
+	results := []identifier.IdentifierResults{
+		result("/corpus/LICENSE", "MIT"),
+		result("/corpus/dual/LICENSE", "Apache-2.0", "MIT"),
+		result("/corpus/src/main.go", "0BSD"),
+		result("/corpus/vendor/NOTICE"),
+	}
+	want := Report{
+		Files:   4,
+		Overall: Metrics{TruePositives: 3, FalsePositives: 1, FalseNegatives: 1, Precision: 0.75, Recall: 0.75, F1: 0.75},
+		Licenses: map[string]Metrics{
+			"0BSD":         {FalsePositives: 1},
+			"Apache-2.0":   {TruePositives: 1, Precision: 1, Recall: 1, F1: 1},
+			"BSD-3-Clause": {FalseNegatives: 1},
+			"MIT":          {TruePositives: 2, Precision: 1, Recall: 1, F1: 1},
+		},
+		Misses: []Miss{
+			{File: "src/main.go", Unexpected: []string{"0BSD"}},
+			{File: "vendor/NOTICE", Missing: []string{"BSD-3-Clause"}},
+		},
+	}
+	if d := cmp.Diff(want, Evaluate(labels, results, "/corpus")); d != "" {
+		t.Errorf("Evaluate() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRegressions(t *testing.T) {
+	t.Parallel()
+	perfect := Metrics{TruePositives: 2, Precision: 1, Recall: 1, F1: 1}
+	baseline := Report{
+		Overall:  perfect,
+		Licenses: map[string]Metrics{"MIT": perfect, "ISC": perfect},
+	}
+	tests := []struct {
+		name    string
+		current Report
+		want    []string
+	}{
+		{
+			name:    "same",
+			current: baseline,
+		},
+		{
+			name: "improved and removed",
+			current: Report{
+				Overall:  Metrics{TruePositives: 3, Precision: 1, Recall: 1, F1: 1},
+				Licenses: map[string]Metrics{"MIT": {TruePositives: 3, Precision: 1, Recall: 1, F1: 1}},
+			},
+		},
+		{
+			name: "false negative",
+			current: Report{
+				Overall:  Metrics{TruePositives: 3, FalseNegatives: 1, Precision: 1, Recall: 0.75, F1: 6.0 / 7},
+				Licenses: map[string]Metrics{"MIT": perfect, "ISC": {TruePositives: 1, FalseNegatives: 1, Precision: 1, Recall: 0.5, F1: 2.0 / 3}},
+			},
+			want: []string{OverallID, "ISC"},
+		},
+		{
+			name: "new false positive",
+			current: Report{
+				Overall:  Metrics{TruePositives: 4, FalsePositives: 1, Precision: 0.8, Recall: 1, F1: 8.0 / 9},
+				Licenses: map[string]Metrics{"MIT": perfect, "ISC": perfect, "0BSD": {FalsePositives: 1}},
+			},
+			want: []string{OverallID, "0BSD"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var got []string
+			for _, r := range Regressions(baseline, tt.current) {
+				got = append(got, r.ID)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Regressions() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bundle writes and verifies the air-gapped bundles of license-scanner: one archive with the executable,
+// the pinned license resources, the policy, and a config which uses them, with the SHA-256 of each file, to
+// scan in an offline or regulated environment
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+const (
+	// Dir is the directory of the files in the bundle archive
+	Dir = "license-scanner-bundle"
+	// ChecksumsFile is the file of the SHA-256 of the other files of a bundle (as written by sha256sum)
+	ChecksumsFile = "SHA256SUMS"
+	// ConfigFile is the config of a bundle, found next to its executable
+	ConfigFile = "config.json"
+	// CompiledFile is the compiled library of a bundle, instead of its resources
+	CompiledFile = "license-library.bin"
+	// ResourcesDir is the directory of the resources of a bundle
+	ResourcesDir = "resources"
+)
+
+// modTime is the time of the files of a bundle, so that the bundles of the same files are identical
+var modTime = time.Unix(0, 0).UTC()
+
+// Spec is what to bundle
+type Spec struct {
+	// Executable is the license-scanner executable file (e.g., built for the target platform)
+	Executable string
+	// Resources is the resources directory with the spdx and custom directories
+	Resources string
+	// SPDX and Custom are the resource directories to pin (a missing custom directory is left out)
+	SPDX   string
+	Custom string
+	// Compiled is a compiled library file to bundle instead of the resources
+	Compiled string
+	// RiskModel is the risk model file of the policy (none for the built-in model)
+	RiskModel string
+	// Settings are the other settings of the bundle config (e.g., the linking and the distribution of the policy)
+	Settings map[string]string
+}
+
+// Entry is a file of a bundle, relative to its directory (with forward slashes)
+type Entry struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+}
+
+// NewSpec returns the spec of a bundle of the executable with the resources, the compiled library, and the
+// policy of the config (and the offline setting, to enforce it for the scans of the bundle)
+func NewSpec(cfg *viper.Viper, executable string) Spec {
+	spec := Spec{
+		Executable: executable,
+		Resources:  cfg.GetString(licenses.Resources),
+		SPDX:       cfg.GetString(configurer.SpdxFlag),
+		Custom:     cfg.GetString(configurer.CustomFlag),
+		Compiled:   cfg.GetString(configurer.CompiledFlag),
+		RiskModel:  cfg.GetString(configurer.RiskModelFlag),
+		Settings: map[string]string{
+			configurer.LinkingFlag:      cfg.GetString(configurer.LinkingFlag),
+			configurer.DistributionFlag: cfg.GetString(configurer.DistributionFlag),
+		},
+	}
+	if cfg.GetBool(configurer.OfflineFlag) {
+		spec.Settings[configurer.OfflineFlag] = "true"
+	}
+	return spec
+}
+
+// source is a file to bundle
+type source struct {
+	name    string // in the bundle
+	file    string // on disk ("" for the content)
+	content []byte
+	mode    int64
+}
+
+// sources returns the files of the bundle, the executable first
+func (s Spec) sources() ([]source, error) {
+	exe := "license-scanner"
+	if strings.HasSuffix(strings.ToLower(s.Executable), ".exe") {
+		exe += ".exe"
+	}
+	ret := []source{{name: exe, file: s.Executable, mode: 0o755}}
+	config := map[string]string{configurer.SpdxFlag: s.SPDX, configurer.CustomFlag: s.Custom}
+	for k, v := range s.Settings {
+		config[k] = v
+	}
+
+	if s.Compiled != "" {
+		config[configurer.CompiledFlag] = CompiledFile
+		ret = append(ret, source{name: CompiledFile, file: s.Compiled, mode: 0o644})
+	} else {
+		config["resources"] = ResourcesDir
+		for _, dir := range []string{path.Join("spdx", s.SPDX), path.Join("custom", s.Custom)} {
+			files, err := resourceFiles(filepath.Join(s.Resources, filepath.FromSlash(dir)), path.Join(ResourcesDir, dir))
+			if errors.Is(err, fs.ErrNotExist) && path.Dir(dir) == "custom" {
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("cannot bundle the %v resources: %w", dir, err)
+			}
+			ret = append(ret, files...)
+		}
+	}
+
+	if s.RiskModel != "" {
+		name := "risk-model" + strings.ToLower(filepath.Ext(s.RiskModel))
+		config[configurer.RiskModelFlag] = name
+		ret = append(ret, source{name: name, file: s.RiskModel, mode: 0o644})
+	}
+
+	b, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	ret = append(ret, source{name: ConfigFile, content: append(b, '\n'), mode: 0o644})
+	return ret, nil
+}
+
+// resourceFiles returns the regular files of the resource directory, named in the bundle directory
+func resourceFiles(dir string, bundleDir string) ([]source, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	var ret []source
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		ret = append(ret, source{name: path.Join(bundleDir, filepath.ToSlash(rel)), file: p, mode: 0o644})
+		return nil
+	})
+	return ret, err
+}
+
+// Write writes the bundle of the spec to w as a gzipped tar archive of the Dir directory, with the
+// ChecksumsFile last, and returns the entries of the other files
+func Write(w io.Writer, spec Spec) ([]Entry, error) {
+	sources, err := spec.sources()
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	entries := make([]Entry, 0, len(sources))
+	for _, src := range sources {
+		content := src.content
+		if src.file != "" {
+			if content, err = os.ReadFile(src.file); err != nil {
+				return nil, err
+			}
+		}
+		if err := writeFile(tw, src.name, content, src.mode); err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(content)
+		entries = append(entries, Entry{Name: src.name, SHA256: hex.EncodeToString(sum[:]), Size: int64(len(content))})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	if err := writeFile(tw, ChecksumsFile, checksums(entries), 0o644); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return entries, gz.Close()
+}
+
+func writeFile(tw *tar.Writer, name string, content []byte, mode int64) error {
+	h := &tar.Header{Name: path.Join(Dir, name), Mode: mode, Size: int64(len(content)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(h); err != nil {
+		return err
+	}
+	_, err := tw.Write(content)
+	return err
+}
+
+// checksums returns the lines of the ChecksumsFile of the entries, like sha256sum (to check them with sha256sum -c)
+func checksums(entries []Entry) []byte {
+	var b strings.Builder
+	for _, e := range entries {
+		fmt.Fprintf(&b, "%v  %v\n", e.SHA256, e.Name)
+	}
+	return []byte(b.String())
+}
+
+// Verification is the result of verifying the files of a bundle against its checksums
+type Verification struct {
+	// Files are the files of the checksums
+	Files []string
+	// Mismatched are the files which do not match their checksums
+	Mismatched []string
+	// Missing are the files of the checksums which are not in the bundle
+	Missing []string
+	// Unlisted are the files of the bundle which are not in the checksums
+	Unlisted []string
+}
+
+// Verified returns whether every file of the bundle matches its checksum
+func (v *Verification) Verified() bool {
+	return len(v.Mismatched) == 0 && len(v.Missing) == 0 && len(v.Unlisted) == 0
+}
+
+// Verify verifies the files of a bundle archive, or of the directory of an extracted bundle, against its
+// ChecksumsFile
+func Verify(bundle string) (*Verification, error) {
+	fi, err := os.Stat(bundle)
+	if err != nil {
+		return nil, err
+	}
+	var sums map[string]string
+	if fi.IsDir() {
+		sums, err = dirSums(bundle)
+	} else {
+		sums, err = archiveSums(bundle)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	listed, ok := sums[ChecksumsFile]
+	if !ok {
+		return nil, fmt.Errorf("%v has no %v file", bundle, ChecksumsFile)
+	}
+	delete(sums, ChecksumsFile)
+	v := &Verification{Files: []string{}}
+	scanner := bufio.NewScanner(strings.NewReader(listed))
+	for scanner.Scan() {
+		sum, name, ok := strings.Cut(scanner.Text(), "  ")
+		if !ok {
+			return nil, fmt.Errorf("invalid %v line %q", ChecksumsFile, scanner.Text())
+		}
+		v.Files = append(v.Files, name)
+		got, ok := sums[name]
+		switch {
+		case !ok:
+			v.Missing = append(v.Missing, name)
+		case got != sum:
+			v.Mismatched = append(v.Mismatched, name)
+		}
+		delete(sums, name)
+	}
+	for name := range sums {
+		v.Unlisted = append(v.Unlisted, name)
+	}
+	sort.Strings(v.Unlisted)
+	return v, nil
+}
+
+// dirSums returns the SHA-256 of the files of the directory by relative path, with the content of the
+// ChecksumsFile instead of its SHA-256
+func dirSums(dir string) (map[string]string, error) {
+	sums := make(map[string]string)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		sums[filepath.ToSlash(rel)], err = sum(filepath.ToSlash(rel), f)
+		return err
+	})
+	return sums, err
+}
+
+// archiveSums returns the SHA-256 of the files of the Dir directory of the archive by relative path, with the
+// content of the ChecksumsFile instead of its SHA-256
+func archiveSums(archive string) (map[string]string, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v is not a bundle archive: %w", archive, err)
+	}
+	sums := make(map[string]string)
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return sums, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%v is not a bundle archive: %w", archive, err)
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(path.Clean(h.Name), Dir+"/")
+		if sums[name], err = sum(name, tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sum returns the SHA-256 of the file, or the content of the ChecksumsFile
+func sum(name string, r io.Reader) (string, error) {
+	if name == ChecksumsFile {
+		b, err := io.ReadAll(r)
+		return string(b), err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
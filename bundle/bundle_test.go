@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// extract extracts the bundle archive to the directory
+func extract(t *testing.T, archive []byte, dir string) {
+	t.Helper()
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		file := filepath.Join(dir, filepath.FromSlash(h.Name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		b, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, b, os.FileMode(h.Mode)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	executable := filepath.Join(tmp, "license-scanner")
+	riskModel := filepath.Join(tmp, "policy.YAML")
+	for file, content := range map[string]string{executable: "#!/bin/sh\n", riskModel: "default: high\n"} {
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	spec := Spec{
+		Executable: executable,
+		Resources:  filepath.Join("..", "testdata", "resources"),
+		SPDX:       "0.1234",
+		Custom:     "customTest2",
+		RiskModel:  riskModel,
+		Settings:   map[string]string{configurer.LinkingFlag: "static"},
+	}
+	var archive bytes.Buffer
+	entries, err := Write(&archive, spec)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	names := map[string]bool{}
+	for _, e := range entries {
+		names[e.Name] = true
+	}
+	for _, want := range []string{"license-scanner", ConfigFile, "risk-model.yaml", "resources/custom/customTest2/license_patterns/Test2/license_info.json", "resources/spdx/0.1234/template/AAL.template.txt"} {
+		if !names[want] {
+			t.Errorf("Write() entries = %v, want %v", entries, want)
+		}
+	}
+
+	// Another bundle of the same files is identical
+	var again bytes.Buffer
+	if _, err := Write(&again, spec); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !bytes.Equal(archive.Bytes(), again.Bytes()) {
+		t.Error("Write() of the same files is not identical")
+	}
+
+	archiveFile := filepath.Join(tmp, "bundle.tar.gz")
+	if err := os.WriteFile(archiveFile, archive.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err := Verify(archiveFile)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !v.Verified() || len(v.Files) != len(entries) {
+		t.Errorf("Verify() = %+v, want the %v files verified", v, len(entries))
+	}
+
+	// The config of the extracted bundle uses its resources and policy
+	extracted := filepath.Join(tmp, "extracted")
+	extract(t, archive.Bytes(), extracted)
+	dir := filepath.Join(extracted, Dir)
+	flags := configurer.NewDefaultFlags()
+	if err := flags.Set(configurer.ConfigPathFlag, dir); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := configurer.InitConfig(flags)
+	if err != nil {
+		t.Fatalf("InitConfig() error = %v", err)
+	}
+	for key, want := range map[string]string{
+		"resources":                 filepath.Join(dir, ResourcesDir),
+		configurer.RiskModelFlag:    filepath.Join(dir, "risk-model.yaml"),
+		configurer.SpdxFlag:         "0.1234",
+		configurer.CustomFlag:       "customTest2",
+		configurer.LinkingFlag:      "static",
+		configurer.DistributionFlag: "distributed",
+	} {
+		if got := cfg.GetString(key); got != want {
+			t.Errorf("bundle config %v = %v, want %v", key, got, want)
+		}
+	}
+
+	// The changed, removed, and added files of the extracted bundle are reported
+	for file, content := range map[string]string{
+		filepath.Join(dir, "risk-model.yaml"): "default: low\n",
+		filepath.Join(dir, "extra.txt"):       "extra\n",
+	} {
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, ConfigFile)); err != nil {
+		t.Fatal(err)
+	}
+	if v, err = Verify(dir); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	want := &Verification{Files: v.Files, Mismatched: []string{"risk-model.yaml"}, Missing: []string{ConfigFile}, Unlisted: []string{"extra.txt"}}
+	if d := cmp.Diff(want, v); d != "" {
+		t.Errorf("Verify() mismatch (-want +got):\n%s", d)
+	}
+	if v.Verified() {
+		t.Error("Verified() = true, want false")
+	}
+}
+
+func TestWrite_compiled(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	executable := filepath.Join(tmp, "license-scanner.exe")
+	compiled := filepath.Join(tmp, "library.bin")
+	for _, file := range []string{executable, compiled} {
+		if err := os.WriteFile(file, []byte(file), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var archive bytes.Buffer
+	entries, err := Write(&archive, Spec{Executable: executable, Compiled: compiled, SPDX: "default", Custom: "default"})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name)
+	}
+	if d := cmp.Diff([]string{ConfigFile, CompiledFile, "license-scanner.exe"}, names); d != "" {
+		t.Errorf("Write() entries mismatch (-want +got):\n%s", d)
+	}
+
+	extract(t, archive.Bytes(), tmp)
+	config, err := os.ReadFile(filepath.Join(tmp, Dir, ConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(config), `"compiled": "license-library.bin"`) || strings.Contains(string(config), `"resources"`) {
+		t.Errorf("config = %s, want the compiled library without the resources", config)
+	}
+}
+
+func TestWrite_errors(t *testing.T) {
+	t.Parallel()
+	if _, err := Write(io.Discard, Spec{Executable: "missing", Resources: filepath.Join("..", "testdata", "resources"), SPDX: "0.1234"}); err == nil {
+		t.Error("Write() of a missing executable error = nil, want an error")
+	}
+	if _, err := Write(io.Discard, Spec{Resources: filepath.Join("..", "testdata", "resources"), SPDX: "missing"}); err == nil {
+		t.Error("Write() of missing SPDX resources error = nil, want an error")
+	}
+	if _, err := Verify(filepath.Join("..", "testdata", "resources", "config.json")); err == nil {
+		t.Error("Verify() of a file which is not a bundle error = nil, want an error")
+	}
+}
@@ -0,0 +1,108 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cachedir locates the user cache directory of license-scanner ($XDG_CACHE_HOME/license-scanner), with the
+// caches which are reused across scans: the compiled license libraries and the match results
+package cachedir
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// The caches in the cache directory
+const (
+	// Compiled is the cache of the compiled license libraries of the resources
+	Compiled = "compiled"
+	// Results is the cache of the match results by normalized content hash
+	Results = "results"
+)
+
+// Caches are the caches in the cache directory
+var Caches = []string{Compiled, Results}
+
+// xdgCacheHome is the environment variable of the base directory of the user caches
+const xdgCacheHome = "XDG_CACHE_HOME"
+
+// Root returns the cache directory of license-scanner: license-scanner in $XDG_CACHE_HOME (when it is an absolute
+// path), or else in the user cache directory of the OS (e.g., ~/.cache, ~/Library/Caches, or %LocalAppData%)
+func Root() (string, error) {
+	base := os.Getenv(xdgCacheHome)
+	if !filepath.IsAbs(base) {
+		var err error
+		if base, err = os.UserCacheDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(base, "license-scanner"), nil
+}
+
+// Dir returns the directory of the cache (Compiled or Results) in the cache directory
+func Dir(cache string) (string, error) {
+	root, err := Root()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, cache), nil
+}
+
+// Usage is the size of a cache
+type Usage struct {
+	Cache string `json:"cache"`
+	Dir   string `json:"dir"`
+	Files int    `json:"files"`
+	Bytes int64  `json:"bytes"`
+}
+
+// Info returns the usage of each of the Caches in the cache directory root (empty for a missing cache)
+func Info(root string) ([]Usage, error) {
+	ret := make([]Usage, 0, len(Caches))
+	for _, cache := range Caches {
+		u := Usage{Cache: cache, Dir: filepath.Join(root, cache)}
+		err := filepath.WalkDir(u.Dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			u.Files++
+			u.Bytes += fi.Size()
+			return nil
+		})
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		ret = append(ret, u)
+	}
+	return ret, nil
+}
+
+// Clean removes the caches from the cache directory root, or the whole cache directory without caches
+func Clean(root string, caches ...string) error {
+	if len(caches) == 0 {
+		return os.RemoveAll(root)
+	}
+	for _, cache := range caches {
+		if !known(cache) {
+			return fmt.Errorf("unknown cache %q (the caches are %v)", cache, Caches)
+		}
+	}
+	for _, cache := range caches {
+		if err := os.RemoveAll(filepath.Join(root, cache)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func known(cache string) bool {
+	for _, c := range Caches {
+		if c == cache {
+			return true
+		}
+	}
+	return false
+}
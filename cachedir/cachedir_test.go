@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package cachedir
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRoot(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv(xdgCacheHome, cacheHome)
+	if got, err := Root(); err != nil || got != filepath.Join(cacheHome, "license-scanner") {
+		t.Errorf("Root() = %v, %v, want license-scanner in $XDG_CACHE_HOME", got, err)
+	}
+	if got, err := Dir(Results); err != nil || got != filepath.Join(cacheHome, "license-scanner", Results) {
+		t.Errorf("Dir(Results) = %v, %v, want the results in the cache directory", got, err)
+	}
+
+	// A relative XDG_CACHE_HOME is ignored
+	t.Setenv(xdgCacheHome, "cache")
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		t.Skipf("no user cache directory: %v", err)
+	}
+	if got, err := Root(); err != nil || got != filepath.Join(userCacheDir, "license-scanner") {
+		t.Errorf("Root() = %v, %v, want license-scanner in the user cache directory", got, err)
+	}
+}
+
+func TestInfo_Clean(t *testing.T) {
+	t.Parallel()
+	root := filepath.Join(t.TempDir(), "license-scanner")
+	for file, content := range map[string]string{
+		filepath.Join(root, Compiled, "abc.bin"):      "compiled",
+		filepath.Join(root, Results, "lib", "1.json"): "{}",
+		filepath.Join(root, Results, "lib", "2.json"): "[]",
+	} {
+		if err := os.MkdirAll(filepath.Dir(file), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	usage, err := Info(root)
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	want := []Usage{
+		{Cache: Compiled, Dir: filepath.Join(root, Compiled), Files: 1, Bytes: 8},
+		{Cache: Results, Dir: filepath.Join(root, Results), Files: 2, Bytes: 4},
+	}
+	if d := cmp.Diff(want, usage); d != "" {
+		t.Errorf("Info() mismatch (-want +got):\n%s", d)
+	}
+
+	if err := Clean(root, "bogus"); err == nil {
+		t.Error("Clean(bogus) error = nil, want an error for an unknown cache")
+	}
+	if err := Clean(root, Results); err != nil {
+		t.Fatalf("Clean(Results) error = %v", err)
+	}
+	if usage, err = Info(root); err != nil || usage[0].Files != 1 || usage[1].Files != 0 {
+		t.Errorf("Info() after Clean(Results) = %+v, %v, want only the compiled cache", usage, err)
+	}
+	if err := Clean(root); err != nil {
+		t.Fatalf("Clean() error = %v", err)
+	}
+	if _, err := os.Stat(root); !os.IsNotExist(err) {
+		t.Errorf("the cache directory was not removed: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/reuse"
+)
+
+const (
+	// holderFlag is the add-header flag of the copyright holder
+	holderFlag = "holder"
+	// yearFlag is the add-header flag of the copyright year
+	yearFlag = "year"
+	// standardHeaderFlag is the add-header flag to also insert the standard license header text
+	standardHeaderFlag = "standard-header"
+)
+
+func newAddHeaderCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-header <license> <files or dirs...>",
+		Short: "Insert a license header into the source files",
+		Long: `
+Insert a license header at the top of the source files (and of the files under the directories), in the
+comment style of each file's language: the SPDX-FileCopyrightText with the --year and --holder, and the
+SPDX-License-Identifier of the license (an SPDX license ID or expression). With --standard-header, the
+standard license header text of the license (e.g., the GPL's "This program is free software...") follows
+the tags. A shebang, an XML declaration, or an encoding declaration stays on the first lines.
+
+The files which already carry a license header (an SPDX-License-Identifier, or a license or standard
+license header found at the top of the file) and the files of an unknown language are skipped. With
+--dry-run, the files which would get the header are listed, and nothing is written.
+
+Example usage:
+
+    $ license-scanner add-header Apache-2.0 --holder "ACME Inc." src
+		`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAllSPDX(); err != nil {
+				return err
+			}
+			h := reuse.Header{Expression: args[0]}
+			h.Holder, _ = cmd.Flags().GetString(holderFlag)
+			h.Year, _ = cmd.Flags().GetString(yearFlag)
+			if h.Holder == "" {
+				return fmt.Errorf("the --%v of the copyright is required", holderFlag)
+			}
+			if standard, _ := cmd.Flags().GetBool(standardHeaderFlag); standard {
+				text, ok := licenseLibrary.HeaderText(h.Expression)
+				if !ok {
+					return fmt.Errorf("license %v has no standard license header", h.Expression)
+				}
+				h.Text = text
+			}
+			dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+			results, err := reuse.Annotate(args[1:], h, licenseLibrary, dryRun)
+			printAnnotated(cmd.OutOrStdout(), results, dryRun)
+			return err
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().String(holderFlag, "", "The copyright holder of the header (required)")
+	cmd.Flags().String(yearFlag, strconv.Itoa(time.Now().Year()), "The copyright year of the header")
+	cmd.Flags().Bool(standardHeaderFlag, false, "Also insert the standard license header text of the license (e.g., of the GPL)")
+	cmd.Flags().Bool(dryRunFlag, false, "List the files which would get the header, without writing them")
+	return cmd
+}
+
+// printAnnotated prints the files which got (or would get) the header, and the skipped files with the reason
+func printAnnotated(out io.Writer, results []reuse.AnnotateResult, dryRun bool) {
+	verb := "Added the header to"
+	if dryRun {
+		verb = "Would add the header to"
+	}
+	added := 0
+	for _, r := range results {
+		if r.Skipped == "" {
+			added++
+			fmt.Fprintf(out, "%v %v\n", verb, r.File)
+		} else {
+			fmt.Fprintf(out, "Skipped %v (%v)\n", r.File, r.Skipped)
+		}
+	}
+	fmt.Fprintf(out, "%v %v of %v files\n", verb, added, len(results))
+}
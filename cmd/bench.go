@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/bench"
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+const (
+	// labelsFlag is the bench accuracy flag for the labels file (the default is the labels file in the corpus)
+	labelsFlag = "labels"
+	// againstFlag is the bench accuracy flag for the stored accuracy report to compare with
+	againstFlag = "against"
+	// saveFlag is the bench accuracy flag to write the accuracy report (e.g., to store a new baseline)
+	saveFlag = "save"
+)
+
+// errAccuracyRegressed is returned (for a non-zero exit code) by bench accuracy when a license regressed
+var errAccuracyRegressed = errors.New("the detection accuracy regressed")
+
+func newBenchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Measure the license detection",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newBenchAccuracyCmd())
+	return cmd
+}
+
+func newBenchAccuracyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accuracy <corpus>",
+		Short: "Score the license detection against a labeled corpus",
+		Long: `
+Scan the labeled files of a corpus directory, and print the precision, recall, and F1 score of each
+license, and the files with a missing or an unexpected license. The labels file (labels.yaml,
+labels.yml, or labels.json in the corpus, or --labels) maps each file, relative to the corpus, to the
+list of its expected license IDs. A file with an empty list is expected to have no licenses.
+
+With --against, the scores are compared with a stored accuracy report (written with --save), and the
+exit code is non-zero when a license (or the overall score) regressed: a lower F1 score, or more false
+positives or false negatives.
+
+Example labels.yaml:
+
+    MIT.txt: [MIT]
+    dual/LICENSE: [Apache-2.0, MIT]
+    README.md: []
+
+Example usage to check a change of the normalizer or the matcher:
+
+    $ license-scanner bench accuracy corpus --save baseline.json
+    $ license-scanner bench accuracy corpus --against baseline.json
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			corpus := args[0]
+			labelsFile, _ := cmd.Flags().GetString(labelsFlag)
+			if labelsFile == "" {
+				if labelsFile, err = bench.FindLabels(corpus); err != nil {
+					return err
+				}
+			}
+			labels, err := bench.LoadLabels(labelsFile)
+			if err != nil {
+				return err
+			}
+			var baseline *bench.Report
+			if against, _ := cmd.Flags().GetString(againstFlag); against != "" {
+				r, err := bench.Load(against)
+				if err != nil {
+					return err
+				}
+				baseline = &r
+			}
+
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			options := identifier.Options{
+				ForceResult:     true,
+				TemplateTimeout: cfg.GetDuration(configurer.TemplateTimeoutFlag),
+				FileTimeout:     cfg.GetDuration(configurer.FileTimeoutFlag),
+				MaxMemory:       cfg.GetInt64(configurer.MaxMemoryFlag),
+			}
+			if options.Cache, err = resultCache(cfg, licenseLibrary); err != nil {
+				return err
+			}
+			results, err := identifier.IdentifyLicensesInFiles(labels.Files(corpus), options, licenseLibrary)
+			if err != nil {
+				return err
+			}
+
+			r := bench.Evaluate(labels, results, corpus)
+			colors := newPalette(cfg)
+			printAccuracy(cmd.OutOrStdout(), r, colors)
+			if save, _ := cmd.Flags().GetString(saveFlag); save != "" {
+				if err := r.Save(save); err != nil {
+					return err
+				}
+			}
+			if baseline != nil {
+				regressions := bench.Regressions(*baseline, r)
+				printRegressions(cmd.OutOrStdout(), regressions, colors)
+				if len(regressions) > 0 {
+					cmd.SilenceUsage = true
+					return errAccuracyRegressed
+				}
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().String(labelsFlag, "", "Labels file mapping the corpus files to their expected license IDs (default is labels.yaml in the corpus)")
+	cmd.Flags().String(againstFlag, "", "Accuracy report to compare with, exiting with an error when the accuracy regressed")
+	cmd.Flags().String(saveFlag, "", "Write the accuracy report to this file (e.g., to store a new baseline)")
+	return cmd
+}
+
+// printAccuracy prints the scores of each license, the overall scores, and the files with a wrong detection
+func printAccuracy(out io.Writer, r bench.Report, colors palette) {
+	fmt.Fprintf(out, "%v\n", colors.heading(fmt.Sprintf("ACCURACY OF %v LABELED FILES", r.Files)))
+	fmt.Fprintf(out, "\t%-40v %9v %9v %9v %5v %5v %5v\n", "LICENSE", "PRECISION", "RECALL", "F1", "TP", "FP", "FN")
+	for _, id := range r.IDs() {
+		printMetrics(out, colors.id(id), r.Licenses[id], len(id))
+	}
+	printMetrics(out, bench.OverallID, r.Overall, len(bench.OverallID))
+
+	if len(r.Misses) > 0 {
+		fmt.Fprintf(out, "\n%v\n", colors.heading("MISSES"))
+		for _, m := range r.Misses {
+			var parts []string
+			if len(m.Missing) > 0 {
+				parts = append(parts, "missing "+strings.Join(m.Missing, ", "))
+			}
+			if len(m.Unexpected) > 0 {
+				parts = append(parts, colors.warn("unexpected "+strings.Join(m.Unexpected, ", ")))
+			}
+			fmt.Fprintf(out, "\t%v\t%v\n", m.File, strings.Join(parts, "; "))
+		}
+	}
+}
+
+// printMetrics prints a row of scores (the width is the length of the license ID without colors)
+func printMetrics(out io.Writer, id string, m bench.Metrics, width int) {
+	pad := ""
+	if width < 40 {
+		pad = strings.Repeat(" ", 40-width)
+	}
+	fmt.Fprintf(out, "\t%v%v %9.3f %9.3f %9.3f %5d %5d %5d\n", id, pad, m.Precision, m.Recall, m.F1, m.TruePositives, m.FalsePositives, m.FalseNegatives)
+}
+
+// printRegressions prints the licenses which were detected less accurately than in the stored report
+func printRegressions(out io.Writer, regressions []bench.Regression, colors palette) {
+	if len(regressions) == 0 {
+		fmt.Fprintln(out, "\nNo accuracy regressions")
+		return
+	}
+	fmt.Fprintf(out, "\n%v\n", colors.heading("REGRESSIONS"))
+	for _, reg := range regressions {
+		fmt.Fprintf(out, "\t%v\tF1 %.3f -> %.3f\tFP %d -> %d\tFN %d -> %d\n", colors.warn(reg.ID),
+			reg.Before.F1, reg.After.F1, reg.Before.FalsePositives, reg.After.FalsePositives, reg.Before.FalseNegatives, reg.After.FalseNegatives)
+	}
+}
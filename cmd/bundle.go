@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/bundle"
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// executableFlag is the bundle create flag of the executable to bundle (instead of the running one)
+const executableFlag = "executable"
+
+// errBundleMismatch is returned (for a non-zero exit code) when the files of a bundle do not match its checksums
+var errBundleMismatch = errors.New("the bundle does not match its checksums")
+
+func newBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Build and verify self-contained bundles for offline scans",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newBundleCreateCmd())
+	cmd.AddCommand(newBundleVerifyCmd())
+	return cmd
+}
+
+func newBundleCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Write the executable, the pinned resources, the policy, and a config to one archive with checksums",
+		Long: `
+Write a gzipped tar archive of a license-scanner-bundle directory with everything a scan needs in an
+offline or regulated environment: the executable (the running one, or --executable, e.g., built for
+another platform), the --spdx and --custom resource directories (or the --compiled library), the
+--riskModel policy, a config.json which uses them (with the --linking and --distribution), and a
+SHA256SUMS file of the SHA-256 of the other files.
+
+Verify the archive (or the extracted directory) with bundle verify, or with sha256sum -c SHA256SUMS in
+the directory, and run the license-scanner of the directory: it finds the config next to it.
+
+Example usage:
+
+    $ license-scanner bundle create --riskModel policy.yaml --output license-scanner-bundle.tar.gz
+    $ tar -xzf license-scanner-bundle.tar.gz
+    $ license-scanner-bundle/license-scanner --dir .
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			executable, _ := cmd.Flags().GetString(executableFlag)
+			if executable == "" {
+				if executable, err = os.Executable(); err != nil {
+					return err
+				}
+			}
+			output, _ := cmd.Flags().GetString(outputFlag)
+			entries, err := writeBundle(bundle.NewSpec(cfg, executable), output)
+			if err != nil {
+				return err
+			}
+			var size int64
+			for _, e := range entries {
+				size += e.Size
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %v files (%v bytes) with their %v to %v\n", len(entries), size, bundle.ChecksumsFile, output)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().String(outputFlag, "license-scanner-bundle.tar.gz", "The bundle archive to write")
+	cmd.Flags().String(executableFlag, "", "The license-scanner executable to bundle (the running one by default)")
+	return cmd
+}
+
+// writeBundle writes the bundle archive (replacing it only when complete)
+func writeBundle(spec bundle.Spec, filePath string) ([]bundle.Entry, error) {
+	f, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return nil, err
+	}
+	entries, err := bundle.Write(f, spec)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return entries, os.Rename(f.Name(), filePath)
+}
+
+func newBundleVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <bundle>",
+		Short: "Verify the files of a bundle archive or directory against its checksums",
+		Long: `
+Verify every file of a bundle archive, or of the directory of an extracted bundle, against the SHA-256
+of its SHA256SUMS file. A file which does not match, a missing file, and a file which is not in the
+SHA256SUMS are reported. The exit code is non-zero when the bundle does not match its checksums.
+
+Example usage:
+
+    $ license-scanner bundle verify license-scanner-bundle.tar.gz
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			v, err := bundle.Verify(args[0])
+			if err != nil {
+				return err
+			}
+			printBundleVerification(cmd.OutOrStdout(), v, newPalette(cfg))
+			if !v.Verified() {
+				cmd.SilenceUsage = true
+				return errBundleMismatch
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// printBundleVerification prints the files which do not match the checksums and a summary
+func printBundleVerification(out io.Writer, v *bundle.Verification, colors palette) {
+	for _, f := range v.Mismatched {
+		fmt.Fprintf(out, "%v: %v\n", f, colors.warn("does not match its checksum"))
+	}
+	for _, f := range v.Missing {
+		fmt.Fprintf(out, "%v: %v\n", f, colors.warn("is missing"))
+	}
+	for _, f := range v.Unlisted {
+		fmt.Fprintf(out, "%v: %v\n", f, colors.warn("is not in the checksums"))
+	}
+	fmt.Fprintf(out, "Verified %v of %v files\n", len(v.Files)-len(v.Mismatched)-len(v.Missing), len(v.Files))
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/cachedir"
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Work with the user cache directory of the compiled libraries and the match results",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newCacheInfoCmd())
+	cmd.AddCommand(newCacheCleanCmd())
+	return cmd
+}
+
+func newCacheInfoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Show the cache directory with the number of files and bytes of each cache",
+		Long: `
+Show the cache directory ($XDG_CACHE_HOME/license-scanner, or license-scanner in the user cache
+directory of the OS) with the number of files and bytes of each cache: the compiled license libraries
+of the resources (with --cache) and the match results (with --cache, and of the hook mode).
+
+Example usage:
+
+    $ license-scanner cache info
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := cachedir.Root()
+			if err != nil {
+				return err
+			}
+			usage, err := cachedir.Info(root)
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				return writeQueryJSON(cmd.OutOrStdout(), usage)
+			}
+			printCacheInfo(cmd.OutOrStdout(), root, usage)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the caches as JSON")
+	return cmd
+}
+
+// printCacheInfo prints the cache directory with a table of its caches
+func printCacheInfo(out io.Writer, root string, usage []cachedir.Usage) {
+	fmt.Fprintf(out, "Cache directory: %v\n\n", root)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CACHE\tFILES\tBYTES")
+	for _, u := range usage {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", u.Cache, u.Files, u.Bytes)
+	}
+	_ = w.Flush()
+}
+
+func newCacheCleanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean [caches...]",
+		Short: "Remove the caches (compiled or results), or the whole cache directory",
+		Long: `
+Remove the named caches (compiled for the compiled license libraries, results for the match results)
+from the cache directory, or the whole cache directory without a cache name. The caches are created
+again by the next scans.
+
+Example usage:
+
+    $ license-scanner cache clean results
+		`,
+		ValidArgs: cachedir.Caches,
+		Args:      cobra.OnlyValidArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, err := cachedir.Root()
+			if err != nil {
+				return err
+			}
+			if err := cachedir.Clean(root, args...); err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed the cache directory %v\n", root)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Removed the %v caches from %v\n", args, root)
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// ANSI terminal escape codes
+const (
+	ansiReset     = "\x1b[0m"
+	ansiBold      = "\x1b[1m"
+	ansiRed       = "\x1b[31m"
+	ansiGreen     = "\x1b[32m"
+	ansiYellow    = "\x1b[33m"
+	ansiCyan      = "\x1b[36m"
+	ansiHighlight = "\x1b[30;43m" // black on yellow
+)
+
+// scoreBarWidth is the number of cells in a score bar
+const scoreBarWidth = 20
+
+// palette renders the human-readable output, with ANSI colors when color is enabled
+type palette struct {
+	color bool
+}
+
+// newPalette enables color when the output is a terminal, unless --no-color, NO_COLOR, or TERM=dumb is set
+func newPalette(cfg *viper.Viper) palette {
+	if cfg.GetBool(configurer.NoColorFlag) || os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return palette{}
+	}
+	return palette{color: isTerminal(os.Stdout)}
+}
+
+// isTerminal is true when the file is a character device (a TTY, not a pipe or a file)
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func (p palette) paint(code string, s string) string {
+	if !p.color || s == "" {
+		return s
+	}
+	return code + s + ansiReset
+}
+
+// heading is for the lines which start the output for a file or package
+func (p palette) heading(s string) string {
+	return p.paint(ansiBold, s)
+}
+
+// id is for license IDs
+func (p palette) id(s string) string {
+	return p.paint(ansiBold+ansiCyan, s)
+}
+
+// warn is for incomplete results (e.g., timeouts)
+func (p palette) warn(s string) string {
+	return p.paint(ansiYellow, s)
+}
+
+// scoreBar returns a bar and percentage for a score from 0 to 1, e.g., [##########----------] 50%
+func (p palette) scoreBar(score float64) string {
+	filled := int(score*scoreBarWidth + 0.5)
+	if filled > scoreBarWidth {
+		filled = scoreBarWidth
+	} else if filled < 0 {
+		filled = 0
+	}
+	percent := fmt.Sprintf("%3.0f%%", score*100)
+	if !p.color {
+		return "[" + strings.Repeat("#", filled) + strings.Repeat("-", scoreBarWidth-filled) + "] " + percent
+	}
+	code := ansiRed
+	switch {
+	case score >= identifier.SnippetCoverage:
+		code = ansiGreen
+	case score >= identifier.SnippetCoverage/2:
+		code = ansiYellow
+	}
+	return p.paint(code, strings.Repeat("█", filled)) + strings.Repeat("░", scoreBarWidth-filled) + " " + percent
+}
+
+// excerpt returns the excerpt on one line with the match highlighted
+func (p palette) excerpt(e identifier.Excerpt) string {
+	if !p.color {
+		return e.String()
+	}
+	return e.Highlight(ansiHighlight, ansiReset)
+}
+
+// highlight returns the text with the matched regions highlighted
+func (p palette) highlight(text string, matches []identifier.Match) string {
+	if !p.color {
+		return identifier.Highlight(text, matches, "[[", "]]")
+	}
+	return identifier.Highlight(text, matches, ansiHighlight, ansiReset)
+}
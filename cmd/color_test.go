@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+func Test_palette(t *testing.T) {
+	t.Parallel()
+	plain := palette{}
+	color := palette{color: true}
+	e := identifier.Excerpt{Before: "SPDX: ", Match: "MIT"}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "plain id", got: plain.id("MIT"), want: "MIT"},
+		{name: "color id", got: color.id("MIT"), want: "\x1b[1m\x1b[36mMIT\x1b[0m"},
+		{name: "color empty", got: color.warn(""), want: ""},
+		{name: "plain empty bar", got: plain.scoreBar(0), want: "[--------------------]   0%"},
+		{name: "plain half bar", got: plain.scoreBar(0.5), want: "[##########----------]  50%"},
+		{name: "plain full bar", got: plain.scoreBar(1), want: "[####################] 100%"},
+		{name: "color full bar", got: color.scoreBar(1), want: "\x1b[32m████████████████████\x1b[0m 100%"},
+		{name: "color low bar", got: color.scoreBar(0.1), want: "\x1b[31m██\x1b[0m░░░░░░░░░░░░░░░░░░  10%"},
+		{name: "plain excerpt", got: plain.excerpt(e), want: "SPDX: [[MIT]]"},
+		{name: "color excerpt", got: color.excerpt(e), want: "SPDX: \x1b[30;43mMIT\x1b[0m"},
+		{name: "plain highlight", got: plain.highlight("SPDX: MIT", []identifier.Match{{Begins: 6, Ends: 8}}), want: "SPDX: [[MIT]]"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
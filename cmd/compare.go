@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/debugger"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// compareContext is the number of words shown around each edit in the diff
+const compareContext = 8
+
+func newCompareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare <file> <license-id>",
+		Short: "Show a word-level diff between a file and a license's canonical text",
+		Long: `
+Show a word-level diff between the normalized file and the normalized canonical text of a license
+(the SPDX license list text, or the pattern of a custom license), to see the exact edits that break
+an exact match. License words missing from the file are shown as [-words-] and extra words in the
+file as {+words+}.
+
+Example usage to compare LICENSE.txt with the MIT license:
+
+    $ license-scanner compare LICENSE.txt MIT
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			input, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			licenseText, err := licenseLibrary.CanonicalText(args[1])
+			if err != nil {
+				return err
+			}
+			comparison, err := debugger.Compare(licenseText, string(input))
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if comparison.Exact() {
+				fmt.Fprintf(out, "%v matches the %v text exactly (after normalization)\n", args[0], args[1])
+				return nil
+			}
+			fmt.Fprintf(out, "%v differs from the %v text by %v missing and %v extra words (after normalization):\n\n", args[0], args[1], comparison.Deleted, comparison.Inserted)
+			fmt.Fprintln(out, comparison.String(compareContext))
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/external"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// licenseCheckTool is the name of google/licensecheck in the disagreement report
+const licenseCheckTool = "licensecheck"
+
+// licenseCheck returns the license IDs which google/licensecheck finds in the text.
+// It is only set when built with -tags licensecheck (see licensecheck.go).
+var licenseCheck func(text []byte) []string
+
+func newCompareToolsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compare-tools <dir>",
+		Short: "Compare the licenses found by license-scanner and google/licensecheck in a corpus",
+		Long: `
+Scan each file in a directory (the corpus) with both license-scanner and google/licensecheck, and
+report the files where they disagree, with the licenses found only by one of them. A summary counts
+the files which agree and conflict, to help evaluate the accuracy of each.
+
+The command needs google/licensecheck, so it is only available when built with:
+
+    $ go get github.com/google/licensecheck
+    $ go build -tags licensecheck
+
+Example usage to compare the tools on the files in ./corpus:
+
+    $ license-scanner compare-tools ./corpus
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if licenseCheck == nil {
+				return errors.New("compare-tools needs google/licensecheck (go get github.com/google/licensecheck, then build with -tags licensecheck)")
+			}
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			results, err := identifier.IdentifyLicensesInDirectory(args[0], identifier.Options{ForceResult: true}, licenseLibrary)
+			if err != nil {
+				return err
+			}
+			findings := otherToolFindings(licenseCheckTool, args[0], results, licenseCheck)
+			printReconciliations(findings.Tool, external.Reconcile(findings, results, args[0], licenseLibrary), newPalette(cfg))
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// otherToolFindings scans the text of each result with another tool, by the path relative to the root
+func otherToolFindings(tool string, root string, results []identifier.IdentifierResults, scan func(text []byte) []string) *external.Findings {
+	findings := &external.Findings{Tool: tool, Files: make(map[string][]string)}
+	for _, r := range results {
+		rel, err := filepath.Rel(root, r.File)
+		if err != nil {
+			rel = r.File
+		}
+		findings.Files[filepath.ToSlash(rel)] = scan([]byte(r.OriginalText))
+	}
+	return findings
+}
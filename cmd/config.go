@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/report"
+)
+
+// sourcesFlag is the config show flag to print where each setting came from
+const sourcesFlag = "sources"
+
+// errInvalidConfig is returned (for a non-zero exit code) when a path or a setting of the configuration is not valid
+var errInvalidConfig = errors.New("the configuration is not valid")
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Work with the configuration",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newConfigValidateCmd())
+	cmd.AddCommand(newConfigShowCmd())
+	return cmd
+}
+
+func newConfigShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration",
+		Long: `
+Print the effective configuration, with the config file, the environment, and the flags merged, as JSON.
+
+With --sources, every setting is printed with its value and where the value came from, following the
+precedence of Viper: flag (set on the command line), env (the setting name in uppercase, e.g., QUIET),
+config file, or default. Use it to find out why a setting does not have the expected value.
+
+Example usage:
+
+    $ license-scanner config show --sources --configPath /etc/license-scanner
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if sources, _ := cmd.Flags().GetBool(sourcesFlag); sources {
+				fmt.Fprintf(out, "Config file: %v\n", configFileUsed(cfg))
+				printSources(out, configurer.Sources(cfg, cmd.Flags()))
+				return nil
+			}
+			return printEffectiveConfig(out, cfg)
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(sourcesFlag, false, "Print every setting with its value and where it came from (flag, env, config file, or default)")
+	return cmd
+}
+
+func newConfigValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the configuration and print the effective configuration",
+		Long: `
+Load the configuration (the config file, the environment, and the flags), resolve the paths of the
+resources (the SPDX resources of --spdx and the custom resources of --custom, or the --compiled library),
+of the --addAll and --addAllXML imports, and of the other input files (e.g., --riskModel, --curations),
+and check that they exist and can be read. The settings with a fixed set of values (e.g., --format) are
+checked too. Then the effective configuration, with the config file, environment, and flags merged, is
+printed as JSON.
+
+The exit code is non-zero when a path or a setting is not valid.
+
+Example usage:
+
+    $ license-scanner config validate --configPath /etc/license-scanner
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			checks := configPathChecks(cfg)
+			problems := configSettingProblems(cfg)
+			if err := printConfigValidation(cmd.OutOrStdout(), cfg, checks, problems, newPalette(cfg)); err != nil {
+				return err
+			}
+			for _, c := range checks {
+				if !c.OK() {
+					problems = append(problems, c.Err)
+				}
+			}
+			if len(problems) > 0 {
+				cmd.SilenceUsage = true
+				return errInvalidConfig
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// configPathChecks resolves and checks the paths of the configuration: the resources, the imports, and the
+// other input files which are set
+func configPathChecks(cfg *viper.Viper) []licenses.ResourceCheck {
+	checks := licenses.CheckResources(cfg)
+	if addAll := cfg.GetString(configurer.AddAllFlag); addAll != "" {
+		checks = append(checks, licenses.CheckPath("--"+configurer.AddAllFlag+" license list", filepath.Join(addAll, "json", "licenses.json"), false))
+	}
+	if addAllXML := cfg.GetString(configurer.AddAllXMLFlag); addAllXML != "" {
+		checks = append(checks, licenses.CheckPath("--"+configurer.AddAllXMLFlag+" license XML", filepath.Join(addAllXML, "src"), true))
+	}
+	for _, flag := range []string{configurer.RiskModelFlag, configurer.CurationsFlag, configurer.TemplateFileFlag, configurer.BaselineFlag, configurer.ScanCodeFlag} {
+		if f := cfg.GetString(flag); f != "" {
+			checks = append(checks, licenses.CheckPath("--"+flag, f, false))
+		}
+	}
+	return checks
+}
+
+// configSettingProblems returns why the settings with a fixed set of values are not valid (none when they are)
+func configSettingProblems(cfg *viper.Viper) []error {
+	var problems []error
+	if _, err := outputs(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := deprecatedIDsMode(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := exitCodePolicy(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := licenses.CustomNamespace(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	if _, err := licenses.CollisionPolicy(cfg); err != nil {
+		problems = append(problems, err)
+	}
+	if err := walkOptions(cfg).Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	context := report.RiskContext{Linking: cfg.GetString(configurer.LinkingFlag), Distribution: cfg.GetString(configurer.DistributionFlag)}
+	if err := context.Validate(); err != nil {
+		problems = append(problems, err)
+	}
+	return problems
+}
+
+// printConfigValidation prints the config file, the checked paths, the problems of the settings, and the
+// effective configuration
+func printConfigValidation(out io.Writer, cfg *viper.Viper, checks []licenses.ResourceCheck, problems []error, colors palette) error {
+	fmt.Fprintf(out, "Config file: %v\n", configFileUsed(cfg))
+
+	fmt.Fprintf(out, "\n%v\n", colors.heading("PATHS"))
+	for _, c := range checks {
+		if c.OK() {
+			fmt.Fprintf(out, "\tok\t%v\t%v\n", c.Name, c.Path)
+		} else {
+			fmt.Fprintf(out, "\t%v\t%v\t%v\n", colors.warn("error"), c.Name, c.Err)
+		}
+	}
+	if len(problems) > 0 {
+		fmt.Fprintf(out, "\n%v\n", colors.heading("SETTINGS"))
+		for _, p := range problems {
+			fmt.Fprintf(out, "\t%v\t%v\n", colors.warn("error"), p)
+		}
+	}
+
+	fmt.Fprintf(out, "\n%v\n", colors.heading("EFFECTIVE CONFIGURATION"))
+	return printEffectiveConfig(out, cfg)
+}
+
+// printEffectiveConfig prints the merged settings as JSON
+func printEffectiveConfig(out io.Writer, cfg *viper.Viper) error {
+	b, err := json.MarshalIndent(cfg.AllSettings(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s\n", b)
+	return nil
+}
+
+// printSources prints a table of the settings with their values and sources
+func printSources(out io.Writer, settings []configurer.Setting) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SETTING\tVALUE\tSOURCE")
+	for _, s := range settings {
+		fmt.Fprintf(w, "%v\t%v\t%v\n", s.Key, s.Value, s.Source)
+	}
+	_ = w.Flush()
+}
+
+// configFileUsed returns the config file of the configuration ("none" when there is none)
+func configFileUsed(cfg *viper.Viper) string {
+	if f := cfg.ConfigFileUsed(); f != "" {
+		return f
+	}
+	return "none"
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/history"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// shortCommit is the length of the abbreviated commit hashes in the output
+const shortCommit = 12
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history <repo>",
+		Short: "Report the license changes in the commit history of a git repository",
+		Long: `
+Walk the commit history of a git repository (oldest first) and identify the licenses in each version
+of its LICENSE, LICENCE, and COPYING files (in any directory). Each commit which changed the detected
+licenses is reported, with what the license changed from and to. The git command must be installed.
+
+Example usage for the repository in the current directory:
+
+    $ license-scanner history .
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			options := identifier.Options{
+				ForceResult:     true,
+				TemplateTimeout: cfg.GetDuration(configurer.TemplateTimeoutFlag),
+				FileTimeout:     cfg.GetDuration(configurer.FileTimeoutFlag),
+			}
+			if options.Cache, err = resultCache(cfg, licenseLibrary); err != nil {
+				return err
+			}
+			changes, err := history.Changes(args[0], options, licenseLibrary)
+			if err != nil {
+				return err
+			}
+			printLicenseChanges(cmd.OutOrStdout(), args[0], changes, newPalette(cfg))
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// printLicenseChanges prints the license changes, oldest first
+func printLicenseChanges(out io.Writer, repo string, changes []history.Change, colors palette) {
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "No LICENSE or COPYING files were found in the history of %v\n", repo)
+		return
+	}
+	fmt.Fprintf(out, "%v\n", colors.heading("LICENSE CHANGES: "+repo))
+	ids := func(ids []string) string {
+		if len(ids) == 0 {
+			return "(none)"
+		}
+		colored := make([]string, len(ids))
+		for i, id := range ids {
+			colored[i] = colors.id(id)
+		}
+		return strings.Join(colored, ", ")
+	}
+	for _, c := range changes {
+		commit := abbreviate(c.Commit)
+		var change string
+		switch {
+		case c.Added():
+			change = "added: " + ids(c.To)
+		case c.Deleted():
+			change = "deleted: " + ids(c.From)
+		default:
+			change = ids(c.From) + " -> " + ids(c.To)
+		}
+		fmt.Fprintf(out, "\n%v %v %v\n", commit, c.Date, c.File)
+		fmt.Fprintf(out, "\t%v\n", change)
+		fmt.Fprintf(out, "\t%v (%v)\n", c.Subject, c.Author)
+	}
+}
+
+// abbreviate returns the commit hash abbreviated to shortCommit characters
+func abbreviate(commit string) string {
+	if len(commit) > shortCommit {
+		return commit[:shortCommit]
+	}
+	return commit
+}
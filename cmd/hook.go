@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/cachedir"
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/history"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func newHookCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hook [files...]",
+		Short: "Scan the staged files in a git pre-commit hook",
+		Long: `
+Scan the files staged for the next commit (or the given files, as passed by the pre-commit framework),
+and output the licenses found in each file. The match results are cached on disk by normalized content
+(in --cacheDir, or the user cache directory by default), so a typical commit is scanned in well under a
+second. With --baseline, the hook fails when a staged file has a license finding which is not in the
+baseline.
+
+Example .git/hooks/pre-commit script:
+
+    #!/bin/sh
+    exec license-scanner hook --quiet --baseline .license-baseline.json
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			files := args
+			if len(files) == 0 {
+				if files, err = history.StagedFiles("."); err != nil {
+					return err
+				}
+			}
+			if len(files) == 0 {
+				return nil // nothing to scan, so do not load the licenses
+			}
+
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			options := identifier.Options{
+				ForceResult:     true,
+				TemplateTimeout: cfg.GetDuration(configurer.TemplateTimeoutFlag),
+				FileTimeout:     cfg.GetDuration(configurer.FileTimeoutFlag),
+				MaxMemory:       cfg.GetInt64(configurer.MaxMemoryFlag),
+			}
+			if options.Cache, err = hookCache(cfg, licenseLibrary); err != nil {
+				return err
+			}
+			results, err := identifier.IdentifyLicensesInFiles(files, options, licenseLibrary)
+			if err != nil {
+				return err
+			}
+
+			colors := newPalette(cfg)
+			printHookResults(cmd.OutOrStdout(), results, colors)
+			if err := checkBaseline(cfg, ".", results, colors); err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// hookCache returns the --cacheDir result cache, or the result cache in the user cache directory
+func hookCache(cfg *viper.Viper, licenseLibrary *licenses.LicenseLibrary) (*identifier.ResultCache, error) {
+	dir := cfg.GetString(configurer.CacheDirFlag)
+	if dir == "" {
+		var err error
+		if dir, err = cachedir.Dir(cachedir.Results); err != nil {
+			return nil, err
+		}
+	}
+	return identifier.NewResultCache(dir, licenseLibrary)
+}
+
+// printHookResults prints one line with the licenses of each file with matches (sorted by file)
+func printHookResults(out io.Writer, results []identifier.IdentifierResults, colors palette) {
+	sort.Slice(results, func(i, j int) bool { return results[i].File < results[j].File })
+	for _, result := range results {
+		if len(result.Matches) == 0 {
+			continue
+		}
+		ids := make([]string, 0, len(result.Matches))
+		for id := range result.Matches {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for i, id := range ids {
+			ids[i] = colors.id(id)
+		}
+		fmt.Fprintf(out, "%v: %v\n", result.File, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build licensecheck
+
+package cmd
+
+import (
+	"github.com/google/licensecheck"
+	"golang.org/x/exp/slices"
+)
+
+func init() {
+	licenseCheck = func(text []byte) []string {
+		var ids []string
+		for _, m := range licensecheck.Scan(text).Match {
+			if !slices.Contains(ids, m.ID) {
+				ids = append(ids, m.ID)
+			}
+		}
+		return ids
+	}
+}
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// The licenses list flags
+const (
+	customOnlyFlag = "custom-only"
+	deprecatedFlag = "deprecated"
+	familyFlag     = "family"
+	jsonFlag       = "json"
+)
+
+func newLicensesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "licenses",
+		Short: "Work with the licenses of the license library",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newLicensesListCmd())
+	cmd.AddCommand(newLicensesShowCmd())
+	return cmd
+}
+
+func newLicensesListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the license IDs of the license library",
+		Long: `
+List every license ID of the license library (the SPDX resources of --spdx and the custom resources of
+--custom, or the --compiled library, after --only and --exclude), sorted by ID: the SPDX licenses and
+exceptions, the deprecated SPDX IDs, and the custom licenses which are not in the SPDX license list.
+
+The IDs are filtered with --custom-only (only the custom licenses), --deprecated (only the deprecated IDs),
+and --family (only the licenses of the families matching the patterns, e.g., GPL*). With --json, the
+IDs are output as a JSON array with their metadata.
+
+Example usage:
+
+    $ license-scanner licenses list --family 'GPL*,LGPL*'
+    $ license-scanner licenses list --deprecated --json
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			var filter licenses.EntryFilter
+			filter.CustomOnly, _ = cmd.Flags().GetBool(customOnlyFlag)
+			filter.Deprecated, _ = cmd.Flags().GetBool(deprecatedFlag)
+			filter.Families, _ = cmd.Flags().GetStringSlice(familyFlag)
+
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			entries, err := licenseLibrary.Entries(filter)
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(entries)
+			}
+			printEntries(cmd.OutOrStdout(), entries)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(customOnlyFlag, false, "Only list the custom licenses (which are not in the SPDX license list)")
+	cmd.Flags().Bool(deprecatedFlag, false, "Only list the deprecated SPDX IDs")
+	cmd.Flags().StringSlice(familyFlag, nil, "Only list the licenses of these families (comma-separated, wildcards like GPL* allowed)")
+	cmd.Flags().Bool(jsonFlag, false, "Output the license IDs with their metadata as JSON")
+	return cmd
+}
+
+func newLicensesShowCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show the metadata, templates, precheck blocks, and canonical text of a license",
+		Long: `
+Show what the license library has of a license ID (case-insensitive), to inspect exactly what the scanner
+tries to match: the metadata (name, kind, family, category, obligations, reference URLs, and the
+replacement of a deprecated ID), each primary pattern (the SPDX template or custom license pattern) with
+its precheck static blocks (after the --precheck settings), the associated patterns, the standard license
+headers, and the canonical text (the SPDX license list text, or the first primary pattern for a custom
+license). The library is loaded like for a scan (--spdx, --custom, or --compiled). With --json, the
+license is output as JSON.
+
+Example usage:
+
+    $ license-scanner licenses show MIT
+    $ license-scanner licenses show GPL-2.0-only --json
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			v, err := licenseLibrary.View(args[0])
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(v)
+			}
+			printView(cmd.OutOrStdout(), v, newPalette(cfg))
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the license as JSON")
+	return cmd
+}
+
+// printEntries prints a table of the license IDs with their kind, family, and name, and the number of IDs
+func printEntries(out io.Writer, entries []licenses.Entry) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tFAMILY\tNAME")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", e.ID, entryKind(e), e.Family, e.Name)
+	}
+	_ = w.Flush()
+	fmt.Fprintf(out, "\n%v license IDs\n", len(entries))
+}
+
+// entryKind returns the kind of a license ID, e.g., "SPDX exception" or "SPDX license (deprecated)"
+func entryKind(e licenses.Entry) string {
+	kind := "SPDX license"
+	switch {
+	case e.Custom:
+		kind = "custom license"
+	case e.Exception:
+		kind = "SPDX exception"
+	}
+	if e.Deprecated {
+		kind += " (deprecated)"
+	}
+	return kind
+}
+
+// printView prints the metadata of the license, then its patterns with their precheck blocks, and its canonical text
+func printView(out io.Writer, v licenses.View, colors palette) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	field := func(name string, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%v:\t%v\n", name, value)
+		}
+	}
+	field("ID", v.ID)
+	field("Name", v.Name)
+	field("Kind", entryKind(v.Entry))
+	field("Replacement", v.Replacement)
+	field("Family", v.Family)
+	field("Category", v.Category)
+	field("OSI approved", yesNo(v.OSIApproved))
+	field("FSF libre", yesNo(v.FSFLibre))
+	field("Obligations", strings.Join(v.Obligations, ", "))
+	field("See also", strings.Join(v.SeeAlso, " "))
+	field("Aliases", strings.Join(v.Aliases, ", "))
+	field("URLs", strings.Join(v.URLs, " "))
+	_ = w.Flush()
+
+	printPatterns(out, "PRIMARY PATTERN", v.PrimaryPatterns, colors)
+	printPatterns(out, "ASSOCIATED PATTERN", v.AssociatedPatterns, colors)
+	printPatterns(out, "HEADER PATTERN", v.HeaderPatterns, colors)
+	if v.CanonicalText != "" {
+		fmt.Fprintf(out, "\n%v\n%v\n", colors.heading("CANONICAL TEXT"), strings.TrimRight(v.CanonicalText, "\n"))
+	}
+}
+
+// printPatterns prints each pattern with its file, text, and precheck static blocks
+func printPatterns(out io.Writer, heading string, patterns []licenses.Pattern, colors palette) {
+	for _, p := range patterns {
+		fmt.Fprintf(out, "\n%v %v\n%v\n", colors.heading(heading), p.File, strings.TrimRight(p.Text, "\n"))
+		if len(p.PreChecks) == 0 {
+			fmt.Fprintln(out, "\tPrecheck blocks: none (always checked)")
+			continue
+		}
+		fmt.Fprintf(out, "\tPrecheck blocks (%v):\n", len(p.PreChecks))
+		for _, block := range p.PreChecks {
+			fmt.Fprintf(out, "\t\t%q\n", block)
+		}
+	}
+}
+
+// yesNo returns "yes" for true and "no" for false
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
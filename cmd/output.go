@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/annotations"
+	"github.com/IBM/license-scanner/attestation"
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/history"
+	"github.com/IBM/license-scanner/httpclient"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/jsonl"
+	"github.com/IBM/license-scanner/junit"
+	"github.com/IBM/license-scanner/licensee"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/report"
+	"github.com/IBM/license-scanner/sink"
+	"github.com/IBM/license-scanner/store"
+	"github.com/IBM/license-scanner/version"
+)
+
+// postTokenEnv is the environment variable with the bearer token of the --postResults service (when --postToken is not set)
+const postTokenEnv = "LICENSE_SCANNER_POST_TOKEN"
+
+// output is a report of the scan in a format, written to a destination (see sink.Open)
+type output struct {
+	format string
+	dest   string
+}
+
+// outputs returns the reports of the --report values (format=destination, or only the format for the standard
+// output), or else the report of the --format on the standard output, with the in-toto statements of the
+// --attestation and the --postResults
+func outputs(cfg *viper.Viper) ([]output, error) {
+	var ret []output
+	if specs := cfg.GetStringSlice(configurer.ReportFlag); len(specs) > 0 {
+		for _, spec := range specs {
+			format, dest, ok := strings.Cut(spec, "=")
+			if !ok {
+				dest = sink.Stdout
+			}
+			if err := checkFormat(cfg, configurer.ReportFlag, format, formatInToto); err != nil {
+				return nil, err
+			}
+			if format == formatText && dest != sink.Stdout {
+				return nil, fmt.Errorf("invalid --%v %q (the %v report can only be written to the standard output)", configurer.ReportFlag, spec, formatText)
+			}
+			ret = append(ret, output{format: format, dest: dest})
+		}
+	} else {
+		format, err := outputFormat(cfg)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, output{format: format, dest: sink.Stdout})
+	}
+	if attestationFile := cfg.GetString(configurer.AttestationFlag); attestationFile != "" {
+		ret = append(ret, output{format: formatInToto, dest: attestationFile})
+	}
+	if url := cfg.GetString(configurer.PostResultsFlag); url != "" {
+		ret = append(ret, output{format: formatInToto, dest: url})
+	}
+	seen := make(map[string]bool, len(ret))
+	for _, o := range ret {
+		if seen[o.dest] {
+			return nil, fmt.Errorf("more than one report is written to %v", o.dest)
+		}
+		seen[o.dest] = true
+	}
+	return ret, nil
+}
+
+// githubAnnotations is true when the github report is written to the standard output (where GitHub Actions reads
+// the annotations)
+func githubAnnotations(cfg *viper.Viper) bool {
+	outs, _ := outputs(cfg)
+	for _, o := range outs {
+		if o.format == formatGitHub && o.dest == sink.Stdout {
+			return true
+		}
+	}
+	return false
+}
+
+// reports writes the reports of a --file or --dir scan to their sinks
+type reports struct {
+	cfg     *viper.Viper
+	outputs []output
+	sinks   []sink.Sink
+	// lines are the JSON Lines writers of the jsonl reports (nil for the other formats)
+	lines          []*jsonl.Writer
+	template       *template.Template
+	riskModel      *report.RiskModel
+	riskContext    report.RiskContext
+	licenseLibrary *licenses.LicenseLibrary
+}
+
+// openReports opens the sinks of the --report reports. The JSON Lines have the file paths relative to the root and
+// the version of the scan.
+func openReports(cfg *viper.Viper, root string, scanVersion version.Info, licenseLibrary *licenses.LicenseLibrary, riskModel *report.RiskModel, riskContext report.RiskContext) (*reports, error) {
+	outs, err := outputs(cfg)
+	if err != nil {
+		return nil, err
+	}
+	r := &reports{cfg: cfg, outputs: outs, licenseLibrary: licenseLibrary, riskModel: riskModel, riskContext: riskContext}
+	client, err := httpClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts := sink.DefaultOptions
+	opts.Webhook.Client = client
+	if opts.Webhook.Token = cfg.GetString(configurer.PostTokenFlag); opts.Webhook.Token == "" {
+		opts.Webhook.Token = os.Getenv(postTokenEnv)
+	}
+	opts.S3 = sink.S3OptionsFromEnv()
+	opts.S3.Client = client
+	if opts.Offline = cfg.GetBool(configurer.OfflineFlag); opts.Offline && cfg.GetString(configurer.AttestationFlag) != "" && cfg.GetBool(configurer.AttestationSignFlag) {
+		return nil, fmt.Errorf("cannot sign the attestation with cosign (--%v): %w", configurer.AttestationSignFlag, sink.ErrOffline)
+	}
+	if cfg.GetString(configurer.DBFlag) != "" && !store.Available() {
+		return nil, fmt.Errorf("cannot record the scan in --%v: %w", configurer.DBFlag, store.ErrNoSQLite)
+	}
+	for _, o := range outs {
+		if o.format == formatTemplate && r.template == nil {
+			if r.template, err = report.LoadTemplate(cfg.GetString(configurer.TemplateFileFlag)); err != nil {
+				return nil, err
+			}
+		}
+		s, err := sink.Open(context.Background(), o.dest, opts)
+		if err != nil {
+			return nil, err
+		}
+		r.sinks = append(r.sinks, s)
+		var lines *jsonl.Writer
+		if o.format == formatJSONL {
+			lines = jsonl.NewWriter(s, root)
+			lines.Version = &scanVersion
+		}
+		r.lines = append(r.lines, lines)
+	}
+	return r, nil
+}
+
+// httpClient returns the client of the network features with the --http-timeout, --http-retries (or the deprecated
+// --postRetries, when it is not the default), --http-proxy, and --http-ca-file, which fails offline with --offline
+func httpClient(cfg *viper.Viper) (*httpclient.Client, error) {
+	retries := cfg.GetInt(configurer.HTTPRetriesFlag)
+	if postRetries := cfg.GetInt(configurer.PostRetriesFlag); postRetries != httpclient.DefaultRetries {
+		retries = postRetries
+	}
+	return httpclient.New(httpclient.Options{
+		Timeout: cfg.GetDuration(configurer.HTTPTimeoutFlag),
+		Retries: retries,
+		Backoff: httpclient.DefaultBackoff,
+		Proxy:   cfg.GetString(configurer.HTTPProxyFlag),
+		CAFile:  cfg.GetString(configurer.HTTPCAFileFlag),
+		Offline: cfg.GetBool(configurer.OfflineFlag),
+	})
+}
+
+// text is true when the text report is written (to the standard output)
+func (r *reports) text() bool {
+	for _, o := range r.outputs {
+		if o.format == formatText {
+			return true
+		}
+	}
+	return false
+}
+
+// streaming is true when a report (JSON Lines) is written as the files are matched
+func (r *reports) streaming() bool {
+	for _, lines := range r.lines {
+		if lines != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// onResult writes the JSON Lines of the result
+func (r *reports) onResult(result identifier.IdentifierResults) {
+	for _, lines := range r.lines {
+		if lines != nil {
+			lines.Write(result)
+		}
+	}
+}
+
+// write writes the reports of the results of the scanned file or directory, with the file paths relative to the
+// root and the metadata of the scan, and closes the sinks (which sends the reports to the services). The
+// --attestation is signed with --attestationSign, and the run is recorded in the --db.
+func (r *reports) write(scanned string, root string, results []identifier.IdentifierResults, m metadata.Metadata) error {
+	for i, o := range r.outputs {
+		w := r.sinks[i]
+		var err error
+		switch o.format {
+		case formatLicensee:
+			out := licensee.FromResults(results, root, r.licenseLibrary)
+			out.Metadata = &m
+			err = out.Write(w)
+		case formatJSONL:
+			r.lines[i].WriteMetadata(m)
+			err = r.lines[i].Err()
+		case formatTemplate:
+			err = renderReport(w, r.template, results, root, r.riskModel.Assess(results, root, r.riskContext), m)
+		case formatGitHub:
+			err = annotations.Write(w, annotations.FromResults(results, r.riskModel, r.riskContext))
+		case formatJUnit:
+			suites := junit.FromResults(results, root, r.riskModel, r.riskContext)
+			suites.SetMetadata(m)
+			err = suites.Write(w)
+		case formatInToto:
+			var statement attestation.Statement
+			if statement, err = attestation.New(scanned, results, m); err == nil {
+				err = statement.Write(w)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if err := w.Close(); err != nil {
+			return err
+		}
+		if o.dest != sink.Stdout {
+			ProjectLogger.Infof("Wrote the %v report to %v", o.format, w.Name())
+		}
+	}
+	if err := r.sign(); err != nil {
+		return err
+	}
+	return recordRun(r.cfg, scanned, root, results, m)
+}
+
+// recordRun records the results of the scan as a run in the --db
+func recordRun(cfg *viper.Viper, scanned string, root string, results []identifier.IdentifierResults, m metadata.Metadata) error {
+	dbFile := cfg.GetString(configurer.DBFlag)
+	if dbFile == "" {
+		return nil
+	}
+	db, err := store.Open(dbFile)
+	if err != nil {
+		return err
+	}
+	// The commit is "" when the root is not in a git repository
+	commit, _ := history.Head(root)
+	run, err := db.AddRun(scanned, commit, root, results, m)
+	if err != nil {
+		_ = db.Close()
+		return err
+	}
+	ProjectLogger.Infof("Recorded the scan as run %v in %v", run.ID, dbFile)
+	return db.Close()
+}
+
+// sign signs the --attestation file with cosign with --attestationSign
+func (r *reports) sign() error {
+	attestationFile := r.cfg.GetString(configurer.AttestationFlag)
+	if attestationFile == "" || !r.cfg.GetBool(configurer.AttestationSignFlag) {
+		return nil
+	}
+	bundle, err := attestation.Sign(attestationFile, r.cfg.GetString(configurer.AttestationKeyFlag))
+	if err != nil {
+		return err
+	}
+	ProjectLogger.Infof("Signed the attestation %v (bundle %v)", attestationFile, bundle)
+	return nil
+}
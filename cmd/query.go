@@ -0,0 +1,288 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/store"
+)
+
+// runFlag is the query license flag of the run to query (the latest run when it is 0)
+const runFlag = "run"
+
+// errNoDB is returned by the query and trend commands without a --db
+var errNoDB = fmt.Errorf("the --%v results database is required", configurer.DBFlag)
+
+func newQueryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "query",
+		Short: "Query the findings of the scans recorded in a --db results database",
+		Long: `
+Query the SQLite results database in which the --file and --dir scans with --db record their findings
+(a run per scan, with the status and the licenses of each scanned file), to track the license inventory
+over time without external infrastructure. The file paths are relative to the scanned directory.
+
+Example usage:
+
+    $ license-scanner --dir . --db results.sqlite --quiet
+    $ license-scanner query runs --db results.sqlite
+    $ license-scanner query license 'GPL-*' --db results.sqlite
+		`,
+		Args: cobra.NoArgs,
+	}
+	cmd.AddCommand(newQueryRunsCmd())
+	cmd.AddCommand(newQueryRunCmd())
+	cmd.AddCommand(newQueryLicenseCmd())
+	cmd.AddCommand(newQueryFileCmd())
+	return cmd
+}
+
+func newQueryRunsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "List the recorded scans, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := openStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			runs, err := db.Runs()
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				return writeQueryJSON(cmd.OutOrStdout(), runs)
+			}
+			printRuns(cmd.OutOrStdout(), runs)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the runs as JSON")
+	return cmd
+}
+
+func newQueryRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [<id>]",
+		Short: "Show the license inventory of a recorded scan (the latest one by default)",
+		Long: `
+Show the licenses found in a recorded scan (the latest one when no run ID is given), with the number of
+files of each license.
+
+Example usage:
+
+    $ license-scanner query run --db results.sqlite
+    $ license-scanner query run 3 --db results.sqlite --json
+		`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var id int64
+			if len(args) > 0 {
+				var err error
+				if id, err = strconv.ParseInt(args[0], 10, 64); err != nil || id <= 0 {
+					return fmt.Errorf("invalid run ID %q", args[0])
+				}
+			}
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := openStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			run, err := db.Run(id)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			inventory, err := db.Inventory(run.ID)
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				return writeQueryJSON(cmd.OutOrStdout(), struct {
+					store.Run
+					Inventory []store.LicenseCount `json:"inventory"`
+				}{run, inventory})
+			}
+			printInventory(cmd.OutOrStdout(), run, inventory)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the run with its license inventory as JSON")
+	return cmd
+}
+
+func newQueryLicenseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "license <id>",
+		Short: "List the files of a recorded scan with a license",
+		Long: `
+List the files with a license ID (case-insensitive, wildcards like GPL-* allowed) in a recorded scan
+(the --run, or the latest one), with their other licenses.
+
+Example usage:
+
+    $ license-scanner query license 'GPL-*' --db results.sqlite
+    $ license-scanner query license MIT --run 3 --db results.sqlite
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := openStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			id, _ := cmd.Flags().GetInt64(runFlag)
+			run, err := db.Run(id)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			files, err := db.FilesWithLicense(run.ID, args[0])
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				return writeQueryJSON(cmd.OutOrStdout(), files)
+			}
+			printFiles(cmd.OutOrStdout(), false, files)
+			fmt.Fprintf(cmd.OutOrStdout(), "\n%v files with %v in run %v of %v\n", len(files), args[0], run.ID, run.Scanned)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Int64(runFlag, 0, "The ID of the run to query (0 for the latest run)")
+	cmd.Flags().Bool(jsonFlag, false, "Output the files as JSON")
+	return cmd
+}
+
+func newQueryFileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "file <path>",
+		Short: "Show the licenses of a file in each recorded scan",
+		Long: `
+Show the status and the licenses of a file (relative to the scanned directory) in each recorded scan
+which scanned it, oldest first, to see when its licenses changed.
+
+Example usage:
+
+    $ license-scanner query file LICENSE --db results.sqlite
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := openStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			files, err := db.FileHistory(strings.TrimPrefix(args[0], "./"))
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				return writeQueryJSON(cmd.OutOrStdout(), files)
+			}
+			if len(files) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%v was not scanned in the recorded runs\n", args[0])
+				return nil
+			}
+			printFiles(cmd.OutOrStdout(), true, files)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the file in each run as JSON")
+	return cmd
+}
+
+// openStore opens the --db of the query and trend commands
+func openStore(cfg *viper.Viper) (*store.Store, error) {
+	dbFile := cfg.GetString(configurer.DBFlag)
+	if dbFile == "" {
+		return nil, errNoDB
+	}
+	return store.Open(dbFile)
+}
+
+// writeQueryJSON writes the query result as indented JSON
+func writeQueryJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printRuns prints a table of the runs
+func printRuns(out io.Writer, runs []store.Run) {
+	if len(runs) == 0 {
+		fmt.Fprintln(out, "No scans were recorded")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "RUN\tTIME\tCOMMIT\tVERSION\tFILES\tLICENSES\tSCANNED")
+	for _, r := range runs {
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\t%v\t%v\n", r.ID, r.Time.Format(time.RFC3339), abbreviate(r.Commit), r.Version, r.Files, r.Licenses, r.Scanned)
+	}
+	_ = w.Flush()
+}
+
+// printInventory prints the run with a table of its licenses and their numbers of files
+func printInventory(out io.Writer, run store.Run, inventory []store.LicenseCount) {
+	fmt.Fprintf(out, "Run %v of %v at %v (%v files)\n\n", run.ID, run.Scanned, run.Time.Format(time.RFC3339), run.Files)
+	if len(inventory) == 0 {
+		fmt.Fprintln(out, "No licenses were found")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LICENSE\tFILES")
+	for _, c := range inventory {
+		fmt.Fprintf(w, "%v\t%v\n", c.License, c.Files)
+	}
+	_ = w.Flush()
+}
+
+// printFiles prints a table of the files with their status and licenses (and their runs, with withRun)
+func printFiles(out io.Writer, withRun bool, files []store.File) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if withRun {
+		fmt.Fprint(w, "RUN\t")
+	}
+	fmt.Fprintln(w, "FILE\tSTATUS\tLICENSES")
+	for _, f := range files {
+		if withRun {
+			fmt.Fprintf(w, "%v\t", f.Run)
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\n", f.Path, f.Status, strings.Join(f.Licenses, ", "))
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/report"
+)
+
+// exitCodeFlag is the report diff flag to exit with an error when the detected licenses changed
+const exitCodeFlag = "exit-code"
+
+// errReportsDiffer is returned (for a non-zero exit code) by report diff --exit-code when the reports differ
+var errReportsDiffer = errors.New("the detected licenses changed")
+
+func newReportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Work with JSON scan reports",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newReportDiffCmd())
+	return cmd
+}
+
+func newReportDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Summarize the license changes between two JSON scan reports",
+		Long: `
+Compare two JSON scan reports, and summarize the new licenses, the removed licenses, and the files
+whose detected licenses changed (including new and removed files). The reports are the output of a
+scan with --format licensee, or a baseline file written with --writeBaseline.
+
+With --exit-code, the exit code is non-zero when the detected licenses changed (e.g., for a PR gate).
+
+Example usage to compare the scan of a PR with the scan of its base:
+
+    $ license-scanner --dir . --format licensee --quiet > new.json
+    $ license-scanner report diff base.json new.json
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			before, err := report.Load(args[0])
+			if err != nil {
+				return err
+			}
+			after, err := report.Load(args[1])
+			if err != nil {
+				return err
+			}
+			d := report.Compare(before, after)
+			printReportDiff(cmd.OutOrStdout(), d, newPalette(cfg))
+			if exitCode, _ := cmd.Flags().GetBool(exitCodeFlag); exitCode && !d.Empty() {
+				cmd.SilenceUsage = true
+				return errReportsDiffer
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(exitCodeFlag, false, "Exit with an error when the detected licenses changed")
+	return cmd
+}
+
+// printReportDiff prints the new and removed licenses, and the changed files
+func printReportDiff(out io.Writer, d report.Diff, colors palette) {
+	if d.Empty() {
+		fmt.Fprintln(out, "The detected licenses did not change")
+		return
+	}
+	ids := func(ids []string) string {
+		if len(ids) == 0 {
+			return "(none)"
+		}
+		colored := make([]string, len(ids))
+		for i, id := range ids {
+			colored[i] = colors.id(id)
+		}
+		return strings.Join(colored, ", ")
+	}
+	if len(d.NewLicenses) > 0 {
+		fmt.Fprintf(out, "%v %v\n", colors.warn("NEW LICENSES:"), ids(d.NewLicenses))
+	}
+	if len(d.RemovedLicenses) > 0 {
+		fmt.Fprintf(out, "%v %v\n", colors.heading("REMOVED LICENSES:"), ids(d.RemovedLicenses))
+	}
+	if len(d.Files) > 0 {
+		fmt.Fprintf(out, "\n%v\n", colors.heading("CHANGED FILES:"))
+		for _, f := range d.Files {
+			switch {
+			case f.Old == nil:
+				fmt.Fprintf(out, "\t+ %v: %v\n", f.File, ids(f.New))
+			case f.New == nil:
+				fmt.Fprintf(out, "\t- %v: %v\n", f.File, ids(f.Old))
+			default:
+				fmt.Fprintf(out, "\t~ %v: %v -> %v\n", f.File, ids(f.Old), ids(f.New))
+			}
+		}
+	}
+	fmt.Fprintf(out, "\n%v new, %v removed licenses; %v changed files\n", len(d.NewLicenses), len(d.RemovedLicenses), len(d.Files))
+}
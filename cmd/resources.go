@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/importer"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// dryRunFlag is the resources migrate flag to list the precheck files without writing them
+const dryRunFlag = "dry-run"
+
+// outputFlag is the resources compile flag of the compiled library file to write
+const outputFlag = "output"
+
+// errResourceGaps is returned when resources check finds license IDs without their files, or files without an ID
+var errResourceGaps = errors.New("the resources have gaps")
+
+func newResourcesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resources",
+		Short: "Maintain the license resources",
+		Args:  cobra.NoArgs,
+	}
+	cmd.AddCommand(newResourcesMigrateCmd())
+	cmd.AddCommand(newResourcesCompileCmd())
+	cmd.AddCommand(newResourcesCheckCmd())
+	return cmd
+}
+
+func newResourcesMigrateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Regenerate the prechecks of the license resources with the current static block extraction",
+		Long: `
+Regenerate the precheck files of the SPDX templates and the custom license patterns from the templates
+and patterns, with the current static block extraction. Run it when the prechecks are reported as
+stale (generated by an older version of the static block extraction), or after editing a template.
+Only the precheck files which changed are written. The resources are selected with --spdx and --custom.
+
+With --dry-run, the precheck files which would be written are listed, and nothing is written.
+
+Example usage:
+
+    $ license-scanner resources migrate --dry-run
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			dryRun, _ := cmd.Flags().GetBool(dryRunFlag)
+			written, err := importer.MigratePreChecks(licenseLibrary, dryRun)
+			printMigrated(cmd.OutOrStdout(), written, dryRun)
+			return err
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(dryRunFlag, false, "List the precheck files which would be written, without writing them")
+	return cmd
+}
+
+// printMigrated prints the precheck files which were (or would be) written
+func printMigrated(out io.Writer, written []string, dryRun bool) {
+	verb := "Wrote"
+	if dryRun {
+		verb = "Would write"
+	}
+	for _, f := range written {
+		fmt.Fprintf(out, "%v %v\n", verb, f)
+	}
+	fmt.Fprintf(out, "%v %v precheck files (the current prechecks version is %v)\n", verb, len(written), licenses.PreChecksVersion)
+}
+
+func newResourcesCompileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compile",
+		Short: "Write the license resources as one compiled library file, for a fast startup",
+		Long: `
+Load the SPDX templates and the custom license patterns (selected with --spdx and --custom), generate
+the regexps of all the patterns, and write the library with its prechecks, candidate index, metadata, and
+rules to one file. A scan with --compiled <file> loads the file instead of the resources, and skips the
+generation of the regexps. Compile the library again after changing the resources or upgrading.
+
+Example usage:
+
+    $ license-scanner resources compile --output license-library.bin
+    $ license-scanner --compiled license-library.bin --dir .
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAllResources(); err != nil {
+				return err
+			}
+			output, _ := cmd.Flags().GetString(outputFlag)
+			if err := writeCompiled(licenseLibrary, output); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %v licenses to %v\n", len(licenseLibrary.LicenseMap), output)
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().String(outputFlag, "license-library.bin", "The compiled library file to write")
+	return cmd
+}
+
+// writeCompiled writes the compiled library file (replacing it only when complete)
+func writeCompiled(licenseLibrary *licenses.LicenseLibrary, filePath string) error {
+	f, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if err := licenseLibrary.WriteCompiled(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filePath)
+}
+
+func newResourcesCheckCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Report the license IDs without a template, precheck, or testdata file, and the files without an ID",
+		Long: `
+Compare the license IDs of the SPDX license list (licenses.json and exceptions.json of the SPDX resources
+of --spdx) with the template, precheck, and testdata files, to make the gaps left by an import visible:
+each ID without a file (named like the template, with the deprecated_ prefix for a deprecated ID), and
+each template, precheck, testdata, or header file without an ID in the license list. The header files
+are optional, so an ID without a header file is not a gap. With --json, the coverage is output as JSON.
+
+The exit code is non-zero when there are gaps.
+
+Example usage:
+
+    $ license-scanner resources check
+    $ license-scanner resources check --spdx 3.21 --json
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			coverage, err := licenses.CheckCoverage(cfg)
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(coverage); err != nil {
+					return err
+				}
+			} else {
+				printCoverage(cmd.OutOrStdout(), coverage, newPalette(cfg))
+			}
+			if !coverage.Complete() {
+				cmd.SilenceUsage = true
+				return errResourceGaps
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the coverage of the license list as JSON")
+	return cmd
+}
+
+// printCoverage prints the IDs without a file and the files without an ID, by kind, and a summary
+func printCoverage(out io.Writer, c licenses.Coverage, colors palette) {
+	printGaps := func(heading string, gaps []licenses.CoverageGap) {
+		kind := ""
+		for _, g := range gaps {
+			if g.Kind != kind {
+				kind = g.Kind
+				fmt.Fprintf(out, "\n%v\n", colors.heading(fmt.Sprintf("%v (%v)", heading, kind)))
+			}
+			fmt.Fprintf(out, "\t%v\t%v\n", g.ID, g.File)
+		}
+	}
+	printGaps("MISSING FILES", c.Missing)
+	printGaps("FILES WITHOUT AN ID", c.Orphans)
+
+	summary := fmt.Sprintf("%v IDs of SPDX license list %v (spdx/%v): %v missing files, %v files without an ID",
+		c.IDs, c.LicenseListVersion, c.SPDX, len(c.Missing), len(c.Orphans))
+	if !c.Complete() {
+		summary = colors.warn(summary)
+	}
+	fmt.Fprintf(out, "\n%v\n", summary)
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/reuse"
+)
+
+// errNotREUSECompliant is returned (for a non-zero exit code) when the project is not REUSE compliant
+var errNotREUSECompliant = errors.New("the project is not REUSE compliant")
+
+func newREUSELintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reuse-lint <dir>",
+		Short: "Check a project for compliance with the REUSE Specification",
+		Long: `
+Check a project for compliance with the REUSE Specification (https://reuse.software/spec/), like
+reuse lint. Every file must have an SPDX-License-Identifier and a copyright notice (in the file, in
+a <file>.license file, or in .reuse/dep5), and the text of every license which is used (and only
+those) must be in LICENSES/<license-id>.txt. The license IDs must be SPDX IDs or LicenseRef- IDs.
+
+The exit code is non-zero when the project is not compliant.
+
+Example usage to check the current directory:
+
+    $ license-scanner reuse-lint .
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAllSPDX(); err != nil {
+				return err
+			}
+			report, err := reuse.Lint(args[0], licenseLibrary)
+			if err != nil {
+				return err
+			}
+			printREUSEReport(cmd.OutOrStdout(), report, newPalette(cfg))
+			if !report.Compliant() {
+				cmd.SilenceUsage = true
+				return errNotREUSECompliant
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// printREUSEReport prints the report like reuse lint
+func printREUSEReport(out io.Writer, r *reuse.Report, colors palette) {
+	list := func(heading string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(out, "%v\n", heading)
+		for _, item := range items {
+			fmt.Fprintf(out, "* %v\n", item)
+		}
+		fmt.Fprintln(out)
+	}
+
+	if len(r.BadLicenses) > 0 {
+		fmt.Fprintf(out, "%v\n\n", colors.heading("# BAD LICENSES"))
+		for _, id := range r.BadLicenses {
+			fmt.Fprintf(out, "'%v' found in: %v\n", colors.warn(id), strings.Join(r.UsedLicenses[id], ", "))
+		}
+		fmt.Fprintln(out)
+	}
+	if len(r.MissingLicenses) > 0 {
+		fmt.Fprintf(out, "%v\n\n", colors.heading("# MISSING LICENSES"))
+		for _, id := range r.MissingLicenses {
+			fmt.Fprintf(out, "'%v' found in: %v\n", colors.warn(id), strings.Join(r.UsedLicenses[id], ", "))
+		}
+		fmt.Fprintln(out)
+	}
+	if len(r.UnusedLicenses) > 0 {
+		fmt.Fprintf(out, "%v\n\n", colors.heading("# UNUSED LICENSES"))
+		list("The following licenses are not used:", r.UnusedLicenses)
+	}
+	if len(r.MissingLicenseInfo) > 0 || len(r.MissingCopyrightInfo) > 0 {
+		fmt.Fprintf(out, "%v\n\n", colors.heading("# MISSING COPYRIGHT AND LICENSING INFORMATION"))
+		list("The following files have no licensing information:", r.MissingLicenseInfo)
+		list("The following files have no copyright information:", r.MissingCopyrightInfo)
+	}
+
+	used := make([]string, 0, len(r.UsedLicenses))
+	for id := range r.UsedLicenses {
+		used = append(used, id)
+	}
+	sort.Strings(used)
+	fmt.Fprintf(out, "%v\n\n", colors.heading("# SUMMARY"))
+	fmt.Fprintf(out, "* Bad licenses: %v\n", strings.Join(r.BadLicenses, ", "))
+	fmt.Fprintf(out, "* Missing licenses: %v\n", strings.Join(r.MissingLicenses, ", "))
+	fmt.Fprintf(out, "* Unused licenses: %v\n", strings.Join(r.UnusedLicenses, ", "))
+	fmt.Fprintf(out, "* Used licenses: %v\n", strings.Join(used, ", "))
+	fmt.Fprintf(out, "* Files with copyright information: %v / %v\n", len(r.Files)-len(r.MissingCopyrightInfo), len(r.Files))
+	fmt.Fprintf(out, "* Files with license information: %v / %v\n", len(r.Files)-len(r.MissingLicenseInfo), len(r.Files))
+	fmt.Fprintln(out)
+	if r.Compliant() {
+		fmt.Fprintf(out, "Congratulations! Your project is compliant with version %v of the REUSE Specification :-)\n", reuse.SpecVersion)
+	} else {
+		fmt.Fprintf(out, "%v\n", colors.warn(fmt.Sprintf("Unfortunately, your project is not compliant with version %v of the REUSE Specification :-(", reuse.SpecVersion)))
+	}
+}
@@ -3,27 +3,70 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"sort"
+	"strings"
+	"syscall"
+	"text/template"
 	"time"
 
 	"github.com/mrutkows/sbom-utility/log"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
 	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
 
+	"github.com/IBM/license-scanner/annotations"
+	"github.com/IBM/license-scanner/baseline"
+	"github.com/IBM/license-scanner/cachedir"
 	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/curation"
 	"github.com/IBM/license-scanner/debugger"
+	"github.com/IBM/license-scanner/exitcode"
+	"github.com/IBM/license-scanner/external"
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/history"
 	"github.com/IBM/license-scanner/identifier"
 	"github.com/IBM/license-scanner/importer"
+	"github.com/IBM/license-scanner/language"
 	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/monorepo"
+	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/packages"
+	"github.com/IBM/license-scanner/report"
+	"github.com/IBM/license-scanner/repository"
+	"github.com/IBM/license-scanner/version"
 )
 
 const (
-	currentVersion = "0.0.0"
-	project        = "license-scanner"
+	project = "license-scanner"
+
+	// versionFlag prints the build information and the SPDX license list versions
+	versionFlag = "version"
+
+	// The --deprecatedIDs values
+	deprecatedIDsBoth       = "both"
+	deprecatedIDsDeprecated = "deprecated"
+	deprecatedIDsCurrent    = "current"
+
+	// The --format values
+	formatText     = "text"
+	formatLicensee = "licensee"
+	formatJSONL    = "jsonl"
+	formatTemplate = "template"
+	formatGitHub   = "github"
+	formatJUnit    = "junit"
+
+	// formatInToto is the in-toto statement of the scan (a --report format, also of --attestation and --postResults)
+	formatInToto = "intoto"
 )
 
 var (
@@ -54,10 +97,13 @@ Example usage to scan LICENSE.txt, but only print the license IDs and positions
 
     $ license-scanner --quiet -f LICENSE.txt
 
+Example usage to explain where the MIT license stopped matching LICENSE.txt:
+
+    $ license-scanner -f LICENSE.txt --explain MIT
+
 Please give us feedback at: https://github.com/IBM/license-scanner/issues
 		`,
-		Args:    cobra.NoArgs,
-		Version: currentVersion,
+		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ProjectLogger.Enter("RunCommand()")
 			defer ProjectLogger.Exit("RunCommand()")
@@ -74,19 +120,46 @@ Please give us feedback at: https://github.com/IBM/license-scanner/issues
 
 			ProjectLogger.SetQuietMode(cfg.GetBool(configurer.QuietFlag))
 
+			if v, _ := cmd.Flags().GetBool(versionFlag); v {
+				return printVersion(cmd.OutOrStdout(), cfg)
+			}
+
 			if ProjectLogger.GetLevel() >= log.TRACE {
 				ProjectLogger.Debugf(" * Flags: %+v", cfg.AllSettings())
 			}
 
+			// The --file and --dir scans exit with the codes of the --exitCodes policy
+			policy, err := exitCodePolicy(cfg)
+			if err != nil {
+				return err
+			}
 			f := cfg.GetString(configurer.FileFlag)
 			if f != "" {
-				return findLicensesInFile(cfg, f)
+				ctx, stop := interruptible(cmd.Context())
+				defer stop()
+				return scanExitCode(cmd, policy, findLicensesInFile(ctx, cfg, f))
 			} else if cfg.GetString(configurer.DirFlag) != "" {
-				return findLicensesInDirectory(cfg)
+				ctx, stop := interruptible(cmd.Context())
+				defer stop()
+				return scanExitCode(cmd, policy, findLicensesInDirectory(ctx, cfg))
+			} else if cfg.GetString(configurer.GoModFlag) != "" {
+				ctx, stop := interruptible(cmd.Context())
+				defer stop()
+				return findLicensesInGoModules(ctx, cfg)
+			} else if cfg.GetString(configurer.NPMFlag) != "" {
+				ctx, stop := interruptible(cmd.Context())
+				defer stop()
+				return findLicensesInNodeModules(ctx, cfg)
+			} else if cfg.GetString(configurer.PackagesFlag) != "" {
+				ctx, stop := interruptible(cmd.Context())
+				defer stop()
+				return findLicensesInPackages(ctx, cfg)
 			} else if cfg.GetBool(configurer.ListFlag) {
 				return listLicenses(cfg)
 			} else if cfg.GetString(configurer.AddAllFlag) != "" {
 				return importer.AddAllSPDXTemplates(cfg)
+			} else if cfg.GetString(configurer.AddAllXMLFlag) != "" {
+				return importer.AddAllSPDXXML(cfg)
 			} else if cfg.GetString(configurer.AddPatternFlag) != "" {
 				// Otherwise, if addPattern was requested, attempt to add that pattern.
 				return errors.New("add_pattern_from_spdx() is NOT-IMPLEMENTED")
@@ -97,9 +170,37 @@ Please give us feedback at: https://github.com/IBM/license-scanner/issues
 		},
 	}
 	notGlobalInit(cmd)
+	cmd.Flags().BoolP(versionFlag, "v", false, "Print the version, git commit, Go version, and SPDX license list versions of license-scanner")
+	cmd.AddCommand(newAddHeaderCmd())
+	cmd.AddCommand(newConfigCmd())
+	cmd.AddCommand(newCompareCmd())
+	cmd.AddCommand(newCompareToolsCmd())
+	cmd.AddCommand(newHistoryCmd())
+	cmd.AddCommand(newHookCmd())
+	cmd.AddCommand(newLicensesCmd())
+	cmd.AddCommand(newQueryCmd())
+	cmd.AddCommand(newReportCmd())
+	cmd.AddCommand(newTrendCmd())
+	cmd.AddCommand(newREUSELintCmd())
+	cmd.AddCommand(newVerifyCmd())
+	cmd.AddCommand(newResourcesCmd())
+	cmd.AddCommand(newSelfTestCmd())
+	cmd.AddCommand(newBenchCmd())
+	cmd.AddCommand(newBundleCmd())
+	cmd.AddCommand(newCacheCmd())
 	return cmd
 }
 
+// printVersion prints the build information with the SPDX license list version of each SPDX resources directory
+func printVersion(out io.Writer, cfg *viper.Viper) error {
+	licenseLists, err := licenses.LicenseLists(cfg)
+	if err != nil {
+		ProjectLogger.Errorf("cannot read the SPDX license list versions: %v", err)
+	}
+	_, err = fmt.Fprint(out, version.Get(licenseLists...))
+	return err
+}
+
 // y returns "Y" for true and " " for false to make readable table cells
 func y(isIt bool) string {
 	if isIt {
@@ -155,8 +256,18 @@ func listLicenses(cfg *viper.Viper) error {
 	return nil
 }
 
-func findLicensesInDirectory(cfg *viper.Viper) error {
+func findLicensesInDirectory(ctx context.Context, cfg *viper.Viper) error {
+	start := time.Now()
 	d := cfg.GetString(configurer.DirFlag)
+	deprecatedIDs, err := deprecatedIDsMode(cfg)
+	if err != nil {
+		return err
+	}
+	riskModel, riskContext, err := loadRiskModel(cfg)
+	if err != nil {
+		return err
+	}
+	colors := newPalette(cfg)
 
 	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
 	if err != nil {
@@ -165,45 +276,105 @@ func findLicensesInDirectory(cfg *viper.Viper) error {
 	if err := licenseLibrary.AddAll(); err != nil {
 		return err
 	}
+	scanVersion := version.Get(licenseLibrary.LicenseList())
 
 	options := identifier.Options{
 		ForceResult: true,
 		Enhancements: identifier.Enhancements{
-			AddNotes:       "",
-			AddTextBlocks:  true,
-			FlagAcceptable: cfg.GetBool(configurer.AcceptableFlag),
-			FlagCopyrights: cfg.GetBool(configurer.CopyrightsFlag),
-			FlagKeywords:   cfg.GetBool(configurer.KeywordsFlag),
+			AddNotes:         "",
+			AddTextBlocks:    true,
+			FlagAcceptable:   cfg.GetBool(configurer.AcceptableFlag),
+			FlagCopyrights:   cfg.GetBool(configurer.CopyrightsFlag),
+			FlagKeywords:     cfg.GetBool(configurer.KeywordsFlag),
+			CaptureVariables: cfg.GetBool(configurer.VariablesFlag),
 		},
+		Headers:         cfg.GetBool(configurer.HeadersFlag),
+		Binaries:        cfg.GetBool(configurer.BinariesFlag),
+		TemplateTimeout: cfg.GetDuration(configurer.TemplateTimeoutFlag),
+		FileTimeout:     cfg.GetDuration(configurer.FileTimeoutFlag),
+		MaxMemory:       cfg.GetInt64(configurer.MaxMemoryFlag),
+		Walk:            walkOptions(cfg),
+		Quick:           !cfg.GetBool(configurer.ThoroughFlag),
+		Context:         ctx,
+	}
+	if err := options.Walk.Validate(); err != nil {
+		return err
+	}
+	if options.Cache, err = resultCache(cfg, licenseLibrary); err != nil {
+		return err
+	}
+	if options.Ensemble, err = ensemble(cfg, licenseLibrary); err != nil {
+		return err
 	}
 
-	results, err := identifier.IdentifyLicensesInDirectory(d, options, licenseLibrary)
+	out, err := openReports(cfg, d, scanVersion, licenseLibrary, riskModel, riskContext)
+	if err != nil {
+		return err
+	}
+	// The JSON Lines are written as the files are matched (in the order of the files)
+	if out.streaming() {
+		options.OnResult = out.onResult
+	}
+
+	// The binary and media files which are skipped, by MIME type
+	skipped := make(map[string]int)
+	options.Walk.OnSkipped = func(_ string, mimeType string) {
+		skipped[mimeType]++
+	}
+
+	var results []identifier.IdentifierResults
+	if since := cfg.GetString(configurer.SinceFlag); since != "" {
+		files, err := history.ChangedFiles(d, since)
+		if err != nil {
+			return err
+		}
+		if files, err = skipFileTypes(files, options, skipped); err != nil {
+			return err
+		}
+		ProjectLogger.Infof("Scanning the %v files changed since %v", len(files), since)
+		results, err = identifier.IdentifyLicensesInFiles(files, options, licenseLibrary)
+	} else {
+		results, err = identifier.IdentifyLicensesInDirectory(d, options, licenseLibrary)
+	}
+	// An interrupted scan still writes the results of the files scanned until then
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	incomplete := err != nil || canceled(results)
+	scanMetadata, err := metadata.New(cfg, scanVersion, start)
+	if err != nil {
+		return err
+	}
+	scanMetadata.Incomplete = incomplete
+	scanMetadata.Quick = options.Quick
+	if len(skipped) > 0 {
+		scanMetadata.Skipped = skipped
+	}
+
+	if err := out.write(d, d, results, scanMetadata); err != nil {
+		return err
+	}
+	if !out.text() {
+		return finishDirectoryScan(cfg, d, results, scanMetadata, colors)
+	}
+
+	curations, err := loadCurations(cfg)
 	if err != nil {
 		return err
 	}
 
 	for _, result := range results {
-		if len(result.Matches) > 0 {
+		if len(result.Matches) > 0 || len(result.HeaderMatches) > 0 {
 
-			// Print the matches by license ID in alphabetical order
-			fmt.Printf("\nFOUND LICENSE MATCHES: %v\n", result.File)
-			var found []string
-			for id := range result.Matches {
-				found = append(found, id)
-			}
-			sort.Strings(found)
-			for _, id := range found {
-				fmt.Printf("\tLicense ID:\t%v", id)
-				fmt.Println()
-				var prev identifier.Match
-				for _, m := range result.Matches[id] {
-					// Print if not same as prev
-					if m != prev {
-						fmt.Printf("\t\tbegins: %5v\tends: %5v\n", m.Begins, m.Ends)
-						prev = m
-					}
-				}
-			}
+			fmt.Printf("\n%v\n", colors.heading("FOUND LICENSE MATCHES: "+result.File))
+			printMatches(result, deprecatedIDs, colors)
+			printHeaderMatches(result, colors)
+			printLicenseURLs(result, colors)
+			printConcluded(curations, result, d, colors)
+			printSnippets(result, deprecatedIDs)
+			printTimeouts(result, colors)
+			printHardLink(result)
+			printHighlighted(cfg, result, colors)
 			fmt.Println()
 
 			if ProjectLogger.GetLevel() >= log.INFO {
@@ -212,18 +383,939 @@ func findLicensesInDirectory(cfg *viper.Viper) error {
 				}
 			}
 		} else {
-			fmt.Printf("\nNo licenses were found: %v\n", result.File)
+			fmt.Printf("\nNo licenses were found (%v): %v\n", colors.warn(result.Status()), result.File)
+			printHints(result, colors)
+			printDeclarations(result, colors)
+			printExtractedLicense(result, colors)
+			printLicenseURLs(result, colors)
+			printConcluded(curations, result, d, colors)
+			printTimeouts(result, colors)
+			printHardLink(result)
+		}
+	}
+
+	fmt.Printf("\n%v %v\n", colors.heading("LICENSE STATUS:"), identifier.ScanStatus(results))
+
+	printDeclaredComparisons(manifest.CompareWithResults(results, licenseLibrary))
+
+	if cfg.GetBool(configurer.RepoLicenseFlag) {
+		printPrimaryLicense(repository.PrimaryLicense(results, d), colors)
+	}
+
+	if patterns, workspaces := cfg.GetStringSlice(configurer.ProjectsFlag), cfg.GetBool(configurer.WorkspacesFlag); len(patterns) > 0 || workspaces {
+		roots, err := monorepo.Roots(d, patterns, workspaces)
+		if err != nil {
+			return err
+		}
+		printProjectSummaries(monorepo.Aggregate(roots, results, d), colors)
+	}
+
+	if cfg.GetBool(configurer.UnknownsFlag) {
+		printUnknownClusters(identifier.ClusterUnknownLicenses(results))
+	}
+
+	if cfg.GetBool(configurer.ObligationsFlag) {
+		printObligations(results, colors)
+	}
+
+	if cfg.GetBool(configurer.RiskFlag) {
+		printRiskSummary(riskModel.Assess(results, d, riskContext), colors)
+	}
+
+	if scanCodeFile := cfg.GetString(configurer.ScanCodeFlag); scanCodeFile != "" {
+		findings, err := external.ParseScanCodeFile(scanCodeFile)
+		if err != nil {
+			return err
+		}
+		printReconciliations(findings.Tool, external.Reconcile(findings, results, d, licenseLibrary), colors)
+	}
+
+	printMetadata(scanMetadata, colors)
+	return finishDirectoryScan(cfg, d, results, scanMetadata, colors)
+}
+
+// finishDirectoryScan writes the --dep5 and --writeBaseline files, and checks the --baseline, the --exitCodes, and
+// the --requireLicense (unless the scan is incomplete, which is an error)
+func finishDirectoryScan(cfg *viper.Viper, d string, results []identifier.IdentifierResults, m metadata.Metadata, colors palette) error {
+	if m.Incomplete {
+		return errInterrupted(len(results))
+	}
+	if dep5 := cfg.GetString(configurer.DEP5Flag); dep5 != "" {
+		if err := writeDEP5(dep5, d, results); err != nil {
+			return err
+		}
+	}
+	if baselineFile := cfg.GetString(configurer.WriteBaselineFlag); baselineFile != "" {
+		if err := baseline.FromResults(results, d).Save(baselineFile); err != nil {
+			return err
+		}
+	}
+	if err := checkBaseline(cfg, d, results, colors); err != nil {
+		return err
+	}
+	if err := checkExitCodes(cfg, results); err != nil {
+		return err
+	}
+	return checkLicensed(cfg, d, results)
+}
+
+// checkLicensed returns an error with --requireLicense when no license matched in the scan
+func checkLicensed(cfg *viper.Viper, scanned string, results []identifier.IdentifierResults) error {
+	if !cfg.GetBool(configurer.RequireLicenseFlag) {
+		return nil
+	}
+	if status := identifier.ScanStatus(results); status != identifier.Licensed {
+		return fmt.Errorf("no license was found in %v (license status: %v)", scanned, status)
+	}
+	return nil
+}
+
+// interruptible returns a context which SIGINT and SIGTERM cancel, so the scan writes its partial results and cleans
+// up before it exits, instead of being killed. A second signal is not caught, so it kills the scan at once. Call
+// stop to stop catching the signals.
+func interruptible(parent context.Context) (ctx context.Context, stop func()) {
+	ctx, stop = signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	return ctx, stop
+}
+
+// canceled returns true when the matching of a file was canceled (e.g., by an interrupt)
+func canceled(results []identifier.IdentifierResults) bool {
+	for _, r := range results {
+		if r.Canceled {
+			return true
+		}
+	}
+	return false
+}
+
+// errInterrupted returns the error of an interrupted scan (with the exit code of an interrupt)
+func errInterrupted(scanned int) error {
+	return errInterruptedScan(scanned, "files")
+}
+
+// errInterruptedScan returns the error of an interrupted scan of the units (e.g., "packages")
+func errInterruptedScan(scanned int, units string) error {
+	return &exitcode.ExitError{Code: exitcode.InterruptedCode, Condition: exitcode.Interrupted, Err: fmt.Errorf("the scan was interrupted: the results of the %v %v scanned are incomplete", scanned, units)}
+}
+
+// exitCodePolicy returns the --exitCodes policy of the exit codes of the scan conditions
+func exitCodePolicy(cfg *viper.Viper) (exitcode.Policy, error) {
+	policy, err := exitcode.Parse(cfg.GetStringMapString(configurer.ExitCodesFlag))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%v: %w", configurer.ExitCodesFlag, err)
+	}
+	return policy, nil
+}
+
+// checkExitCodes returns an *exitcode.ExitError when the scan met a condition with an exit code in the --exitCodes
+func checkExitCodes(cfg *viper.Viper, results []identifier.IdentifierResults) error {
+	policy, err := exitCodePolicy(cfg)
+	if err != nil {
+		return err
+	}
+	riskModel, riskContext, err := loadRiskModel(cfg)
+	if err != nil {
+		return err
+	}
+	return policy.Check(exitcode.Met(results, riskModel, riskContext))
+}
+
+// scanExitCode returns the error of a scan with the exit code of the policy (without the usage, which is not the
+// problem of a scan which met a condition)
+func scanExitCode(cmd *cobra.Command, policy exitcode.Policy, err error) error {
+	var exitErr *exitcode.ExitError
+	if errors.As(err, &exitErr) {
+		cmd.SilenceUsage = true
+	}
+	return policy.Wrap(err)
+}
+
+// printHints prints the low-confidence license hints of a file in which no license matched
+func printHints(result identifier.IdentifierResults, colors palette) {
+	for _, h := range result.Hints {
+		loc := identifier.Locate(result.OriginalText, identifier.Match{Begins: h.Begins, Ends: h.Ends})
+		fmt.Printf("\tLicense hint:\t%v %v\n", h.ID, colors.warn("(low confidence: a telltale phrase, not a license match)"))
+		fmt.Printf("\t\tphrase: %q\tlines: %v:%v-%v:%v\n", h.Phrase, loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn)
+	}
+}
+
+// printDeclarations prints the UNLICENSED and All rights reserved declarations of a file in which no license matched
+func printDeclarations(result identifier.IdentifierResults, colors palette) {
+	for _, d := range result.Declarations {
+		loc := identifier.Locate(result.OriginalText, identifier.Match{Begins: d.Begins, Ends: d.Ends})
+		fmt.Printf("\tDeclaration:\t%q %v\n", d.Phrase, colors.warn("(unlicensed: the rights are reserved)"))
+		fmt.Printf("\t\tlines: %v:%v-%v:%v\n", loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn)
+	}
+}
+
+// printExtractedLicense prints the license-like text which matched no license as SPDX other licensing information,
+// so an SPDX document can refer to it by its LicenseRef
+func printExtractedLicense(result identifier.IdentifierResults, colors palette) {
+	e := result.ExtractedLicense
+	if e == nil {
+		return
+	}
+	fmt.Printf("\tExtracted license: %v %v\n", e.ID, colors.warn("(license-like text which matched no license)"))
+	fmt.Printf("\t\tLicenseID:\t%v\n", e.ID)
+	fmt.Printf("\t\tLicenseName:\tNOASSERTION\n")
+	fmt.Printf("\t\tExtractedText:\t<text>%v</text>\n", e.Text)
+}
+
+// printHeaderMatches prints the standard license headers of a file (with --headers)
+func printHeaderMatches(result identifier.IdentifierResults, colors palette) {
+	ids := make([]string, 0, len(result.HeaderMatches))
+	for id := range result.HeaderMatches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		fmt.Printf("\tLicense header:\t%v %v\n", colors.id(id), colors.warn("(a standard license header, not the full license text)"))
+		for _, m := range result.HeaderMatches[id] {
+			loc := identifier.Locate(result.OriginalText, m)
+			fmt.Printf("\t\tlines: %v:%v-%v:%v\n", loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn)
+		}
+	}
+}
+
+// printLicenseURLs prints the license reference URLs of a file with the licenses they imply
+func printLicenseURLs(result identifier.IdentifierResults, colors palette) {
+	for _, u := range result.LicenseURLs {
+		loc := identifier.Locate(result.OriginalText, identifier.Match{Begins: u.Begins, Ends: u.Ends})
+		fmt.Printf("\tLicense URL:\t%v %v\n", strings.Join(u.IDs, " or "), colors.warn("(evidence: a license reference URL, not a license match)"))
+		fmt.Printf("\t\turl: %v\tlines: %v:%v-%v:%v\n", u.URL, loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn)
+	}
+}
+
+// loadCurations reads the --curations file (nil when it is not used)
+func loadCurations(cfg *viper.Viper) (*curation.Curations, error) {
+	curationsFile := cfg.GetString(configurer.CurationsFlag)
+	if curationsFile == "" {
+		return nil, nil
+	}
+	return curation.Load(curationsFile)
+}
+
+// printConcluded prints the detected license IDs and the license concluded by the curations for the file
+func printConcluded(curations *curation.Curations, result identifier.IdentifierResults, root string, colors palette) {
+	if curations == nil {
+		return
+	}
+	detected := make([]string, 0, len(result.Matches))
+	for id := range result.Matches {
+		detected = append(detected, id)
+	}
+	sort.Strings(detected)
+	if len(detected) == 0 {
+		detected = append(detected, curation.NoAssertion)
+	}
+	fmt.Printf("\tDetected:\t%v\n", strings.Join(detected, ", "))
+	f, ok := curations.ForFile(result.File, root)
+	if !ok {
+		fmt.Printf("\tConcluded:\t%v\n", curation.NoAssertion)
+		return
+	}
+	fmt.Printf("\tConcluded:\t%v\n", colors.id(f.Concluded))
+	if f.Comment != "" {
+		fmt.Printf("\tComment:\t%v\n", f.Comment)
+	}
+}
+
+// checkBaseline returns an error when the --baseline does not accept all the findings
+func checkBaseline(cfg *viper.Viper, root string, results []identifier.IdentifierResults, colors palette) error {
+	baselineFile := cfg.GetString(configurer.BaselineFlag)
+	if baselineFile == "" {
+		return nil
+	}
+	accepted, err := baseline.Load(baselineFile)
+	if err != nil {
+		return err
+	}
+	if violations := accepted.Check(results, root); len(violations) > 0 {
+		printBaselineViolations(os.Stderr, baselineFile, violations, colors)
+		if githubAnnotations(cfg) {
+			if err := annotations.Write(os.Stdout, annotations.FromBaselineViolations(violations, root)); err != nil {
+				return err
+			}
+		}
+		return fmt.Errorf("%v license findings are not in the baseline %v", len(violations), baselineFile)
+	}
+	return nil
+}
+
+// printBaselineViolations prints the findings which are not accepted by the baseline
+func printBaselineViolations(out io.Writer, baselineFile string, violations []baseline.Violation, colors palette) {
+	fmt.Fprintf(out, "\n%v\n", colors.heading("FINDINGS NOT IN THE BASELINE: "+baselineFile))
+	for _, v := range violations {
+		fmt.Fprintf(out, "\t%v\t%v\t%v\n", colors.warn(string(v.Status)), colors.id(v.License), v.File)
+	}
+}
+
+// resultCache returns the on-disk result cache when --cacheDir or --cache is used (otherwise nil)
+func resultCache(cfg *viper.Viper, licenseLibrary *licenses.LicenseLibrary) (*identifier.ResultCache, error) {
+	dir := cfg.GetString(configurer.CacheDirFlag)
+	if dir == "" && cfg.GetBool(configurer.CacheFlag) {
+		var err error
+		if dir, err = cachedir.Dir(cachedir.Results); err != nil {
+			return nil, err
+		}
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	return identifier.NewResultCache(dir, licenseLibrary)
+}
+
+// ensemble returns the ensemble of matching algorithms when --ensemble is used (otherwise nil)
+func ensemble(cfg *viper.Viper, licenseLibrary *licenses.LicenseLibrary) (*identifier.Ensemble, error) {
+	if !cfg.GetBool(configurer.EnsembleFlag) {
+		return nil, nil
+	}
+	return identifier.NewEnsemble(licenseLibrary)
+}
+
+// writeDEP5 writes a debian/copyright skeleton for the directory scan results
+func writeDEP5(filePath string, dir string, results []identifier.IdentifierResults) error {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+	if err := manifest.WriteDEP5(f, dir, filepath.Base(absDir), results); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// outputFormat returns the --format value after checking it
+func outputFormat(cfg *viper.Viper) (string, error) {
+	format := cfg.GetString(configurer.FormatFlag)
+	if err := checkFormat(cfg, configurer.FormatFlag, format); err != nil {
+		return "", err
+	}
+	return format, nil
+}
+
+// checkFormat returns an error when the format of the flag is not a report format (or one of the other formats)
+func checkFormat(cfg *viper.Viper, flag string, format string, others ...string) error {
+	formats := append([]string{formatText, formatLicensee, formatJSONL, formatTemplate, formatGitHub, formatJUnit}, others...)
+	if !slices.Contains(formats, format) {
+		return fmt.Errorf("invalid --%v %q (expected %v, or %v)", flag, format, strings.Join(formats[:len(formats)-1], ", "), formats[len(formats)-1])
+	}
+	if format == formatTemplate && cfg.GetString(configurer.TemplateFileFlag) == "" {
+		return fmt.Errorf("--%v %v requires a --%v", flag, formatTemplate, configurer.TemplateFileFlag)
+	}
+	return nil
+}
+
+// renderReport writes the report of the results with the template, with the configured risk summary and the scan metadata
+func renderReport(w io.Writer, t *template.Template, results []identifier.IdentifierResults, root string, risk report.RiskSummary, m metadata.Metadata) error {
+	data := report.NewTemplateData(results, root)
+	data.Risk = risk
+	data.Metadata = m
+	return t.Execute(w, data)
+}
+
+// loadRiskModel reads the --riskModel file (the built-in model when it is not used) and returns the
+// risk context of the --linking and --distribution
+func loadRiskModel(cfg *viper.Viper) (*report.RiskModel, report.RiskContext, error) {
+	context := report.RiskContext{Linking: cfg.GetString(configurer.LinkingFlag), Distribution: cfg.GetString(configurer.DistributionFlag)}
+	if err := context.Validate(); err != nil {
+		return nil, context, err
+	}
+	riskModelFile := cfg.GetString(configurer.RiskModelFlag)
+	if riskModelFile == "" {
+		return &report.DefaultRiskModel, context, nil
+	}
+	m, err := report.LoadRiskModel(riskModelFile)
+	return m, context, err
+}
+
+// deprecatedIDsMode returns the --deprecatedIDs value after checking it
+func deprecatedIDsMode(cfg *viper.Viper) (string, error) {
+	mode := cfg.GetString(configurer.DeprecatedIDsFlag)
+	switch mode {
+	case deprecatedIDsBoth, deprecatedIDsDeprecated, deprecatedIDsCurrent:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid --%v %q (expected %v, %v, or %v)", configurer.DeprecatedIDsFlag, mode, deprecatedIDsBoth, deprecatedIDsDeprecated, deprecatedIDsCurrent)
+}
+
+// walkOptions returns the --symlinks, --specialFiles, --hardLinks, and --allFiles of the --dir scan
+func walkOptions(cfg *viper.Viper) identifier.WalkOptions {
+	return identifier.WalkOptions{
+		Symlinks:     cfg.GetString(configurer.SymlinksFlag),
+		SpecialFiles: cfg.GetString(configurer.SpecialFilesFlag),
+		HardLinks:    cfg.GetString(configurer.HardLinksFlag),
+		AllFiles:     cfg.GetBool(configurer.AllFilesFlag),
+	}
+}
+
+// skipFileTypes returns the files to scan without the binary and media files (unless --allFiles), counting the
+// skipped files by MIME type
+func skipFileTypes(files []string, options identifier.Options, skipped map[string]int) ([]string, error) {
+	if options.Walk.AllFiles {
+		return files, nil
+	}
+	var ret []string
+	for _, f := range files {
+		mimeType, skip, err := identifier.SkipFileType(f, options.Binaries)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			skipped[mimeType]++
+			continue
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
+
+// printMatches prints the matches by license ID in alphabetical order.
+// The deprecated IDs are printed with (both), or replaced by (current), their current expression.
+// The exceptions are printed WITH the licenses they apply to.
+func printMatches(result identifier.IdentifierResults, deprecatedIDs string, colors palette) {
+	byID := make(map[string][]identifier.Match)
+	classifications := make(map[string]licenses.Classification)
+	metadata := make(map[string]licenses.Metadata)
+	variables := make(map[string][]identifier.TemplateVariable)
+	verdicts := make(map[string]identifier.Verdict)
+	combined := make(map[string]bool)
+	for _, exceptionID := range result.Exceptions {
+		combined[exceptionID] = true
+	}
+	for id, matches := range result.Matches {
+		if combined[id] {
+			continue // printed WITH the licenses it applies to
+		}
+		c := result.Classifications[id]
+		md := result.Metadata[id]
+		vs := result.Variables[id]
+		v, hasVerdict := result.Verdicts[id]
+		replacement := result.Replacements[id]
+		mapped := result.Mappings[id]
+		if exceptionID, ok := result.Exceptions[id]; ok {
+			id = id + " " + licenses.With + " " + exceptionID
+			matches = append(append([]identifier.Match{}, matches...), result.Matches[exceptionID]...)
+			if replacement != "" {
+				replacement = replacement + " " + licenses.With + " " + exceptionID
+			}
+			if strings.Contains(mapped, " ") {
+				mapped = "(" + mapped + ")"
+			}
+			if mapped != "" {
+				mapped = mapped + " " + licenses.With + " " + exceptionID
+			}
+		}
+		if replacement != "" {
+			switch deprecatedIDs {
+			case deprecatedIDsBoth:
+				id = fmt.Sprintf("%v (deprecated, current: %v)", id, replacement)
+			case deprecatedIDsCurrent:
+				id = replacement
+			}
+		}
+		if mapped != "" {
+			id = fmt.Sprintf("%v (mapped: %v)", id, mapped)
+		}
+		byID[id] = append(byID[id], matches...)
+		classifications[id] = c
+		metadata[id] = md
+		variables[id] = append(variables[id], vs...)
+		if hasVerdict {
+			verdicts[id] = v
+		}
+	}
+
+	if result.Language != "" && result.Language != language.English {
+		fmt.Printf("\tLanguage:\t%v\n", result.Language)
+	}
+	var found []string
+	for id := range byID {
+		found = append(found, id)
+	}
+	sort.Strings(found)
+	for _, id := range found {
+		fmt.Printf("\tLicense ID:\t%v", colors.id(id))
+		fmt.Println()
+		fmt.Printf("\t\tcoverage: %v\n", colors.scoreBar(identifier.Coverage(result.OriginalText, byID[id])))
+		if v, ok := verdicts[id]; ok {
+			if v.Similarity > 0 {
+				fmt.Printf("\t\t%v (similarity %.2f)\n", v, v.Similarity)
+			} else {
+				fmt.Printf("\t\t%v\n", v)
+			}
+		}
+		if c := classifications[id]; c.Family != "" || c.Category != "" {
+			fmt.Printf("\t\tfamily: %v\tcategory: %v\n", c.Family, c.Category)
+		}
+		if md := metadata[id]; md.Name != "" {
+			fmt.Printf("\t\tname: %v\tOSI approved: %v\tFSF libre: %v\n", md.Name, md.IsOSIApproved, md.IsFSFLibre)
+			if len(md.SeeAlso) > 0 {
+				fmt.Printf("\t\tsee also: %v\n", strings.Join(md.SeeAlso, ", "))
+			}
+		}
+		for _, v := range variables[id] {
+			name := v.Name
+			if name == "" {
+				name = "var"
+			}
+			fmt.Printf("\t\t%v: %q\n", name, v.Text)
+		}
+		matches := byID[id]
+		sort.Slice(matches, func(i, j int) bool {
+			if matches[i].Begins != matches[j].Begins {
+				return matches[i].Begins < matches[j].Begins
+			}
+			return matches[i].Ends < matches[j].Ends
+		})
+		var prev identifier.Match
+		for _, m := range matches {
+			// Print if not same as prev
+			if m != prev {
+				loc := identifier.Locate(result.OriginalText, m)
+				fmt.Printf("\t\tbegins: %5v\tends: %5v\tlines: %v:%v-%v:%v\n", m.Begins, m.Ends, loc.StartLine, loc.StartColumn, loc.EndLine, loc.EndColumn)
+				fmt.Printf("\t\t\t%v\n", colors.excerpt(loc.Excerpt))
+				prev = m
+			}
+		}
+	}
+}
+
+// printSnippets prints SPDX Snippet information when the licenses are embedded in a larger file,
+// so that the licenses are not attributed to the whole file
+func printSnippets(result identifier.IdentifierResults, deprecatedIDs string) {
+	snippets := identifier.Snippets(result)
+	if len(snippets) == 0 {
+		return
+	}
+	fileRef := identifier.SPDXRef("File", result.File)
+	fmt.Printf("\tSnippets (the licenses do not cover the whole file):\n")
+	for i, s := range snippets {
+		fmt.Printf("\t\tSnippetSPDXID:\t\t%v\n", identifier.SPDXRef("Snippet", fmt.Sprintf("%v-%v", result.File, i+1)))
+		fmt.Printf("\t\tSnippetFromFileSPDXID:\t%v\n", fileRef)
+		fmt.Printf("\t\tSnippetByteRange:\t%v:%v\n", s.ByteRange[0], s.ByteRange[1])
+		fmt.Printf("\t\tSnippetLineRange:\t%v:%v\n", s.LineRange[0], s.LineRange[1])
+		for _, id := range s.LicenseIDs {
+			if mapped, ok := result.Mappings[id]; ok {
+				id = mapped
+			} else if deprecatedIDs == deprecatedIDsCurrent && result.Replacements[id] != "" {
+				id = result.Replacements[id]
+			}
+			fmt.Printf("\t\tLicenseInfoInSnippet:\t%v\n", id)
+		}
+	}
+}
+
+// explainLicense prints where the license stopped matching the text
+func explainLicense(licenseLibrary *licenses.LicenseLibrary, id string, text string) error {
+	nd := normalizer.NormalizationData{OriginalText: text, LinePrefixes: licenseLibrary.LinePrefixes}
+	if err := nd.NormalizeText(); err != nil {
+		return err
+	}
+	explanation, err := debugger.Explain(licenseLibrary, id, nd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(explanation.String(text))
+	return nil
+}
+
+// printTimeouts prints a warning when the matching timed out or was canceled or the long lines were truncated or only
+// the header was scanned, so the matches may be incomplete
+func printTimeouts(result identifier.IdentifierResults, colors palette) {
+	if result.HeaderOnly {
+		fmt.Printf("\t%v\n", colors.warn(fmt.Sprintf("HEADER ONLY: only the first %v bytes of the file were scanned (scan the whole file with --%v)", identifier.QuickHeaderSize, configurer.ThoroughFlag)))
+	}
+	if result.Truncated {
+		fmt.Printf("\t%v\n", colors.warn(fmt.Sprintf("TRUNCATED: only the text around the license markers of the lines longer than %v characters was scanned", identifier.MaxLineLength)))
+	}
+	if result.TimedOut {
+		fmt.Printf("\t%v\n", colors.warn("TIMED OUT: the file timeout stopped the matching (the matches may be incomplete)"))
+	}
+	for _, template := range result.TimedOutTemplates {
+		fmt.Printf("\t%v\n", colors.warn("TIMED OUT: template "+template))
+	}
+	if result.Canceled {
+		fmt.Printf("\t%v\n", colors.warn("CANCELED: the scan was interrupted while matching the file (the matches may be incomplete)"))
+	}
+}
+
+// printHardLink prints the first path of the same file when the results are its results (--hardLinks once)
+func printHardLink(result identifier.IdentifierResults) {
+	if result.HardLinkOf != "" {
+		fmt.Printf("\tHard link of: %v (the same file, scanned once)\n", result.HardLinkOf)
+	}
+}
+
+// printHighlighted prints the original text with the matched regions highlighted when --highlight is used
+func printHighlighted(cfg *viper.Viper, result identifier.IdentifierResults, colors palette) {
+	if !cfg.GetBool(configurer.HighlightFlag) {
+		return
+	}
+	var matches []identifier.Match
+	for _, m := range result.Matches {
+		matches = append(matches, m...)
+	}
+	fmt.Printf("\tHighlighted matches:\n%v\n", colors.highlight(result.OriginalText, matches))
+}
+
+// printMetadata prints the metadata of the scan
+func printMetadata(m metadata.Metadata, colors palette) {
+	fmt.Printf("\n%v\n", colors.heading("SCAN METADATA"))
+	commit := m.Version.Commit
+	if commit == "" {
+		commit = "unknown"
+	}
+	fmt.Printf("\tVersion:\tlicense-scanner %v (commit %v, %v)\n", m.Version.Version, commit, m.Version.GoVersion)
+	for _, l := range m.Version.LicenseLists {
+		if l.Import != nil {
+			fmt.Printf("\tLicense list:\tSPDX %v (spdx/%v, %v)\n", l.Version, l.SPDX, l.Import)
+			continue
+		}
+		fmt.Printf("\tLicense list:\tSPDX %v (spdx/%v)\n", l.Version, l.SPDX)
+	}
+	fmt.Printf("\tConfig hash:\t%v\n", m.ConfigHash)
+	for _, c := range m.Resources {
+		fmt.Printf("\tResources:\t%v %v\n", c.Name, c.SHA256)
+	}
+	if m.Start != nil && m.End != nil {
+		fmt.Printf("\tStart:\t\t%v\n", m.Start.Format(time.RFC3339))
+		fmt.Printf("\tEnd:\t\t%v (%v)\n", m.End.Format(time.RFC3339), m.End.Sub(*m.Start).Round(time.Millisecond))
+	}
+	if m.Host != nil {
+		fmt.Printf("\tHost:\t\t%v (%v/%v, %v CPUs)\n", m.Host.Hostname, m.Host.OS, m.Host.Arch, m.Host.CPUs)
+	}
+	if m.Incomplete {
+		fmt.Printf("\tIncomplete:\t%v\n", colors.warn("the scan was interrupted (the results are partial)"))
+	}
+	if m.Quick {
+		fmt.Printf("\tQuick:\t\tthe license files and the headers of the other files were scanned, scan everything with --%v\n", configurer.ThoroughFlag)
+	}
+	if len(m.Skipped) > 0 {
+		mimeTypes := make([]string, 0, len(m.Skipped))
+		total := 0
+		for mimeType, n := range m.Skipped {
+			mimeTypes = append(mimeTypes, mimeType)
+			total += n
+		}
+		sort.Strings(mimeTypes)
+		var counts []string
+		for _, mimeType := range mimeTypes {
+			counts = append(counts, fmt.Sprintf("%v %v", m.Skipped[mimeType], mimeType))
+		}
+		fmt.Printf("\tSkipped:\t%v binary and media files (%v), scan them with --%v\n", total, strings.Join(counts, ", "), configurer.AllFilesFlag)
+	}
+}
+
+// printUnknownClusters prints the clusters of files with unknown licenses, with an excerpt to triage each
+func printUnknownClusters(clusters []identifier.UnknownCluster) {
+	for i, c := range clusters {
+		fmt.Printf("\nUNKNOWN LICENSE CLUSTER %v (%v files): %v\n", i+1, len(c.Files), c.Representative)
+		for _, f := range c.Files {
+			fmt.Printf("\tFile:\t%v\n", f)
+		}
+		fmt.Printf("\tExcerpt:\n")
+		for _, line := range strings.Split(c.Excerpt, "\n") {
+			fmt.Printf("\t\t%v\n", line)
+		}
+	}
+}
+
+// printObligations prints what the detected licenses require of the user, with the licenses which require it
+func printObligations(results []identifier.IdentifierResults, colors palette) {
+	obligations, unknown := report.Obligations(results)
+	if len(obligations) == 0 && len(unknown) == 0 {
+		return
+	}
+	fmt.Printf("\n%v\n", colors.heading("LICENSE OBLIGATIONS:"))
+	for _, o := range obligations {
+		fmt.Printf("\tObligation:\t%v\n", o.Name)
+		if o.Description != "" {
+			fmt.Printf("\t\t%v\n", o.Description)
+		}
+		fmt.Printf("\t\tlicenses: %v\n", strings.Join(o.Licenses, ", "))
+	}
+	if len(unknown) > 0 {
+		fmt.Printf("\t%v\t%v\n", colors.warn("Unknown obligations:"), strings.Join(unknown, ", "))
+	}
+}
+
+// printRiskSummary prints the overall risk level and the risk level of each detected license
+func printRiskSummary(summary report.RiskSummary, colors palette) {
+	overall := summary.Overall
+	if overall != report.RiskLow {
+		overall = colors.warn(overall)
+	}
+	fmt.Printf("\n%v %v\n", colors.heading("RISK SUMMARY:"), overall)
+	fmt.Printf("\tContext:\tlinking: %v\tdistribution: %v\n", summary.Context.Linking, summary.Context.Distribution)
+	for _, r := range summary.Licenses {
+		category := r.Category
+		if category == "" {
+			category = "unclassified"
+		}
+		fmt.Printf("\tLicense ID:\t%v\n", colors.id(r.ID))
+		fmt.Printf("\t\trisk: %v\tcategory: %v\tfiles: %v\n", r.Level, category, len(r.Files))
+		if len(r.Choices) > 0 {
+			fmt.Printf("\t\tchoice of an allowed license in files: %v\n", len(r.Choices))
+		}
+	}
+}
+
+// printDeclaredComparisons prints the declared vs. detected licenses for each package manifest
+func printDeclaredComparisons(comparisons []manifest.Comparison) {
+	for _, c := range comparisons {
+		d := c.Declared
+		if c.Error != "" {
+			fmt.Printf("\nDECLARED LICENSES NOT COMPARED: %v (%v)\n", d.File, c.Error)
+			continue
+		}
+		if c.Agrees() {
+			fmt.Printf("\nDECLARED LICENSES MATCH: %v\n", d.File)
+		} else {
+			fmt.Printf("\nDECLARED LICENSE DISCREPANCY: %v\n", d.File)
+		}
+		fmt.Printf("\tPackage:\t%v %v %v\n", d.Ecosystem, d.Name, d.Version)
+		fmt.Printf("\tDeclared:\t%v\n", strings.Join(d.Licenses, ", "))
+		fmt.Printf("\tDeclared IDs:\t%v\n", strings.Join(c.DeclaredIDs, ", "))
+		fmt.Printf("\tDetected IDs:\t%v\n", strings.Join(c.Detected, ", "))
+		if len(c.Missing) > 0 {
+			fmt.Printf("\tNot detected:\t%v\n", strings.Join(c.Missing, ", "))
+		}
+		if len(c.Unresolved) > 0 {
+			fmt.Printf("\tUnresolved:\t%v\n", strings.Join(c.Unresolved, ", "))
 		}
 	}
+}
+
+// printPrimaryLicense prints the primary license of the repository, and where it was found
+func printPrimaryLicense(p repository.Primary, colors palette) {
+	fmt.Printf("\n%v %v\n", colors.heading("REPOSITORY LICENSE:"), colors.id(p.Expression))
+	if p.Source != "" {
+		fmt.Printf("\tSource:\t%v (%v)\n", p.Source, strings.Join(p.Files, ", "))
+	}
+}
+
+// printProjectSummaries prints the licenses of each project, with the number of files with each license
+func printProjectSummaries(summaries []monorepo.Summary, colors palette) {
+	for _, s := range summaries {
+		fmt.Printf("\n%v\n", colors.heading("PROJECT LICENSES: "+s.Project))
+		fmt.Printf("\tFiles:\t%v (%v with licenses)\n", s.Files, s.FilesWithLicenses)
+		for _, id := range s.IDs() {
+			fmt.Printf("\tLicense ID:\t%v (%v files)\n", colors.id(id), s.Licenses[id])
+		}
+	}
+}
+
+// printReconciliations prints the files where another scanner and license-scanner found different licenses, and a summary
+func printReconciliations(tool string, reconciliations []external.Reconciliation, colors palette) {
+	label := strings.ToUpper(tool)
+	agreed := 0
+	for _, r := range reconciliations {
+		if r.Agrees() {
+			agreed++
+			continue
+		}
+		fmt.Printf("\n%v\n", colors.warn(label+" CONFLICT: "+r.File))
+		if r.NotScanned {
+			fmt.Printf("\tNot scanned:\tthe file is not in the license-scanner results\n")
+		}
+		fmt.Printf("\t%v:\t%v\n", tool, strings.Join(r.Other, ", "))
+		fmt.Printf("\tDetected:\t%v\n", strings.Join(r.Detected, ", "))
+		if len(r.Agreed) > 0 {
+			fmt.Printf("\tAgreed:\t\t%v\n", strings.Join(r.Agreed, ", "))
+		}
+		if len(r.OnlyOther) > 0 {
+			fmt.Printf("\tOnly %v:\t%v\n", tool, strings.Join(r.OnlyOther, ", "))
+		}
+		if len(r.OnlyDetected) > 0 {
+			fmt.Printf("\tOnly detected:\t%v\n", strings.Join(r.OnlyDetected, ", "))
+		}
+		if len(r.Unresolved) > 0 {
+			fmt.Printf("\tUnresolved:\t%v\n", strings.Join(r.Unresolved, ", "))
+		}
+	}
+	fmt.Printf("\n%v RECONCILIATION: %v files agree, %v files conflict\n", label, agreed, len(reconciliations)-agreed)
+}
+
+func findLicensesInGoModules(ctx context.Context, cfg *viper.Viper) error {
+	start := time.Now()
+	d := cfg.GetString(configurer.GoModFlag)
+
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return err
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		return err
+	}
+
+	pkgs, err := packages.IdentifyGoModules(d, identifier.Options{Context: ctx}, licenseLibrary)
+	// An interrupted scan still prints the packages scanned until then
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	incomplete := err != nil
+	curations, err := loadCurations(cfg)
+	if err != nil {
+		return err
+	}
+	return printPackageScan(cfg, pkgs, incomplete, curations, licenseLibrary, start)
+}
+
+func findLicensesInNodeModules(ctx context.Context, cfg *viper.Viper) error {
+	start := time.Now()
+	d := cfg.GetString(configurer.NPMFlag)
+
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return err
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		return err
+	}
+
+	pkgs, err := packages.IdentifyNodeModules(d, identifier.Options{Context: ctx}, licenseLibrary)
+	// An interrupted scan still prints the packages scanned until then
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	incomplete := err != nil
+	curations, err := loadCurations(cfg)
+	if err != nil {
+		return err
+	}
+	return printPackageScan(cfg, pkgs, incomplete, curations, licenseLibrary, start)
+}
+
+func findLicensesInPackages(ctx context.Context, cfg *viper.Viper) error {
+	start := time.Now()
+	f := cfg.GetString(configurer.PackagesFlag)
+
+	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		return err
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		return err
+	}
+
+	limits := extractor.Limits{
+		MaxDepth:            cfg.GetInt(configurer.MaxArchiveDepthFlag),
+		MaxExtractedSize:    cfg.GetInt64(configurer.MaxExtractedSizeFlag),
+		MaxCompressionRatio: cfg.GetInt64(configurer.MaxCompressionRatioFlag),
+	}
+	pkgs, err := packages.IdentifyPackages(f, limits, identifier.Options{Context: ctx}, licenseLibrary)
+	// An interrupted scan still prints the packages scanned until then
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	incomplete := err != nil
+	curations, err := loadCurations(cfg)
+	if err != nil {
+		return err
+	}
+	return printPackageScan(cfg, pkgs, incomplete, curations, licenseLibrary, start)
+}
+
+// printPackageScan prints the license IDs found for each package, and the scan metadata. An incomplete (interrupted)
+// scan is an error.
+func printPackageScan(cfg *viper.Viper, pkgs []packages.Package, incomplete bool, curations *curation.Curations, licenseLibrary *licenses.LicenseLibrary, start time.Time) error {
+	m, err := metadata.New(cfg, version.Get(licenseLibrary.LicenseList()), start)
+	if err != nil {
+		return err
+	}
+	for _, p := range pkgs {
+		incomplete = incomplete || canceled(p.Files)
+	}
+	m.Incomplete = incomplete
+	colors := newPalette(cfg)
+	printPackages(pkgs, curations, colors)
+	printMetadata(m, colors)
+	if m.Incomplete {
+		return errInterruptedScan(len(pkgs), "packages")
+	}
 	return nil
 }
 
-func findLicensesInFile(cfg *viper.Viper, f string) error {
+// printPackages prints the license IDs found for each package along with the files used as evidence
+// (and, with curations, the detected and concluded licenses)
+func printPackages(pkgs []packages.Package, curations *curation.Curations, colors palette) {
+	for _, p := range pkgs {
+		if p.Error != "" {
+			fmt.Printf("\n%v (%v)\n", colors.warn("PACKAGE NOT SCANNED: "+p.ID()), p.Error)
+			continue
+		}
+		if len(p.Licenses) == 0 {
+			fmt.Printf("\nNo licenses were found: %v\n", p.ID())
+			printConcludedPackage(curations, p, colors)
+			continue
+		}
+		fmt.Printf("\n%v\n", colors.heading("FOUND PACKAGE LICENSES: "+p.ID()))
+		fmt.Printf("\tEcosystem:\t%v\n", p.Ecosystem)
+		fmt.Printf("\tPath:\t\t%v\n", p.Path)
+		if len(p.Declared) > 0 {
+			fmt.Printf("\tDeclared:\t%v\n", strings.Join(p.Declared, ", "))
+		}
+		fmt.Printf("\tEvidence:\t%v\n", p.Evidence)
+		for _, id := range p.Licenses {
+			fmt.Printf("\tLicense ID:\t%v\n", colors.id(id))
+		}
+		for _, f := range p.Files {
+			fmt.Printf("\t\tfile: %v\n", f.File)
+		}
+		printConcludedPackage(curations, p, colors)
+	}
+}
+
+// printConcludedPackage prints the detected license IDs and the license concluded by the curations for the package
+func printConcludedPackage(curations *curation.Curations, p packages.Package, colors palette) {
+	if curations == nil {
+		return
+	}
+	detected := curation.NoAssertion
+	if len(p.Licenses) > 0 {
+		detected = strings.Join(p.Licenses, ", ")
+	}
+	fmt.Printf("\tDetected:\t%v\n", detected)
+	c, ok := curations.ForPackage(p)
+	if !ok {
+		fmt.Printf("\tConcluded:\t%v\n", curation.NoAssertion)
+		return
+	}
+	fmt.Printf("\tConcluded:\t%v\n", colors.id(c.Concluded))
+	if c.Comment != "" {
+		fmt.Printf("\tComment:\t%v\n", c.Comment)
+	}
+}
+
+func findLicensesInFile(ctx context.Context, cfg *viper.Viper, f string) error {
 	ProjectLogger.Enter()
 	defer ProjectLogger.Exit()
-	startTime := time.Now().UnixMicro()
+	start := time.Now()
+	startTime := start.UnixMicro()
 	ProjectLogger.Info("Looking for all licences")
 
+	deprecatedIDs, err := deprecatedIDsMode(cfg)
+	if err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	riskModel, riskContext, err := loadRiskModel(cfg)
+	if err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	colors := newPalette(cfg)
+
 	licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
 	if err != nil {
 		logScanTimeMS(startTime)
@@ -233,16 +1325,37 @@ func findLicensesInFile(cfg *viper.Viper, f string) error {
 		logScanTimeMS(startTime)
 		return err
 	}
+	scanVersion := version.Get(licenseLibrary.LicenseList())
 
 	options := identifier.Options{
 		ForceResult: true,
 		Enhancements: identifier.Enhancements{
-			AddNotes:       "",
-			AddTextBlocks:  true,
-			FlagAcceptable: cfg.GetBool(configurer.AcceptableFlag),
-			FlagCopyrights: cfg.GetBool(configurer.CopyrightsFlag),
-			FlagKeywords:   cfg.GetBool(configurer.KeywordsFlag),
+			AddNotes:         "",
+			AddTextBlocks:    true,
+			FlagAcceptable:   cfg.GetBool(configurer.AcceptableFlag),
+			FlagCopyrights:   cfg.GetBool(configurer.CopyrightsFlag),
+			FlagKeywords:     cfg.GetBool(configurer.KeywordsFlag),
+			CaptureVariables: cfg.GetBool(configurer.VariablesFlag),
 		},
+		Headers:         cfg.GetBool(configurer.HeadersFlag),
+		Binaries:        cfg.GetBool(configurer.BinariesFlag),
+		TemplateTimeout: cfg.GetDuration(configurer.TemplateTimeoutFlag),
+		FileTimeout:     cfg.GetDuration(configurer.FileTimeoutFlag),
+		Context:         ctx,
+	}
+	if options.Cache, err = resultCache(cfg, licenseLibrary); err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	if options.Ensemble, err = ensemble(cfg, licenseLibrary); err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+
+	out, err := openReports(cfg, filepath.Dir(f), scanVersion, licenseLibrary, riskModel, riskContext)
+	if err != nil {
+		logScanTimeMS(startTime)
+		return err
 	}
 
 	results, err := identifier.IdentifyLicensesInFile(f, options, licenseLibrary)
@@ -250,29 +1363,28 @@ func findLicensesInFile(cfg *viper.Viper, f string) error {
 		logScanTimeMS(startTime)
 		return err
 	}
+	out.onResult(results)
+	scanMetadata, err := metadata.New(cfg, scanVersion, start)
+	if err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	scanMetadata.Incomplete = results.Canceled
 
 	licenseArg := cfg.GetString(configurer.LicenseFlag)
-	if len(results.Matches) > 0 {
+	if err := out.write(f, filepath.Dir(f), []identifier.IdentifierResults{results}, scanMetadata); err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	if out.text() && (len(results.Matches) > 0 || len(results.HeaderMatches) > 0) {
 
-		// Print the matches by license ID in alphabetical order
-		fmt.Printf("\nFOUND LICENSE MATCHES:\n")
-		var found []string
-		for id := range results.Matches {
-			found = append(found, id)
-		}
-		sort.Strings(found)
-		for _, id := range found {
-			fmt.Printf("\tLicense ID:\t%v", id)
-			fmt.Println()
-			var prev identifier.Match
-			for _, m := range results.Matches[id] {
-				// Print if not same as prev
-				if m != prev {
-					fmt.Printf("\t\tbegins: %5v\tends: %5v\n", m.Begins, m.Ends)
-					prev = m
-				}
-			}
-		}
+		fmt.Printf("\n%v\n", colors.heading("FOUND LICENSE MATCHES:"))
+		printMatches(results, deprecatedIDs, colors)
+		printHeaderMatches(results, colors)
+		printLicenseURLs(results, colors)
+		printSnippets(results, deprecatedIDs)
+		printTimeouts(results, colors)
+		printHighlighted(cfg, results, colors)
 		fmt.Println()
 
 		if licenseArg == "" {
@@ -280,8 +1392,23 @@ func findLicensesInFile(cfg *viper.Viper, f string) error {
 				ProjectLogger.Infof("%v :: %v", block.Matches, block.Text)
 			}
 		}
-	} else {
-		ProjectLogger.Info("No licenses were found")
+	} else if out.text() {
+		ProjectLogger.Infof("No licenses were found (%v)", results.Status())
+		printHints(results, colors)
+		printDeclarations(results, colors)
+		printExtractedLicense(results, colors)
+		printLicenseURLs(results, colors)
+		printTimeouts(results, colors)
+	}
+
+	if out.text() && cfg.GetBool(configurer.ObligationsFlag) {
+		printObligations([]identifier.IdentifierResults{results}, colors)
+	}
+	if out.text() && cfg.GetBool(configurer.RiskFlag) {
+		printRiskSummary(riskModel.Assess([]identifier.IdentifierResults{results}, filepath.Dir(f), riskContext), colors)
+	}
+	if out.text() {
+		printMetadata(scanMetadata, colors)
 	}
 
 	if licenseArg != "" {
@@ -298,6 +1425,13 @@ func findLicensesInFile(cfg *viper.Viper, f string) error {
 		}
 	}
 
+	if explain := cfg.GetString(configurer.ExplainFlag); explain != "" {
+		if err := explainLicense(licenseLibrary, explain, results.OriginalText); err != nil {
+			logScanTimeMS(startTime)
+			return err
+		}
+	}
+
 	if cfg.GetBool(configurer.HashFlag) {
 		ProjectLogger.Infof("File Hash: %v", results.Hash.Md5)
 	}
@@ -306,6 +1440,19 @@ func findLicensesInFile(cfg *viper.Viper, f string) error {
 		ProjectLogger.Info(results.NormalizedText)
 	}
 
+	if scanMetadata.Incomplete {
+		logScanTimeMS(startTime)
+		return errInterrupted(1)
+	}
+	if err := checkExitCodes(cfg, []identifier.IdentifierResults{results}); err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+	if err := checkLicensed(cfg, f, []identifier.IdentifierResults{results}); err != nil {
+		logScanTimeMS(startTime)
+		return err
+	}
+
 	logScanTimeMS(startTime)
 	return nil
 }
@@ -322,6 +1469,6 @@ func Execute() {
 		_ = doc.GenMarkdownTree(rootCmd, "./cmd/")
 	}
 	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(exitcode.Code(err))
 	}
 }
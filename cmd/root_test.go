@@ -6,14 +6,28 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
+	"io"
 	"io/fs"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/attestation"
+	"github.com/IBM/license-scanner/exitcode"
+	"github.com/IBM/license-scanner/external"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/sink"
+	"github.com/IBM/license-scanner/store"
 )
 
 func Test_CLI_version(t *testing.T) {
@@ -73,6 +87,230 @@ func Test_CLI_file(t *testing.T) {
 	}
 }
 
+func Test_CLI_exitCodes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no policy", args: []string{"-f", "../resources/spdx/default/testdata/GPL-3.0-only.txt"}},
+		{name: "denied", args: []string{"-f", "../resources/spdx/default/testdata/GPL-3.0-only.txt", "--exitCodes", "denied=3,none=4"}, want: 3},
+		{name: "not denied internally", args: []string{"-f", "../resources/spdx/default/testdata/GPL-3.0-only.txt", "--exitCodes", "denied=3", "--distribution", "internal"}},
+		{name: "none", args: []string{"-f", "../go.mod", "--exitCodes", "denied=3,none=4"}, want: 4},
+		{name: "error", args: []string{"-f", "FILE.TXT", "--exitCodes", "error=5"}, want: 5},
+		{name: "invalid policy", args: []string{"-f", "../go.mod", "--exitCodes", "warning=4"}, want: 1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetArgs(append(tt.args, "--quiet"))
+			if got := exitcode.Code(cmd.Execute()); got != tt.want {
+				t.Errorf("expected the exit code %v got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func Test_CLI_attestation(t *testing.T) {
+	t.Parallel()
+	out := filepath.Join(t.TempDir(), "scan.intoto.json")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet", "--attestation", out})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got attestation.Statement
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Type != attestation.StatementType || len(got.Subject) != 1 || got.Subject[0].Name != "0BSD.txt" || got.Subject[0].Digest["sha256"] == "" {
+		t.Errorf("expected the in-toto statement of 0BSD.txt got %s", b)
+	}
+	if d := cmp.Diff([]string{"0BSD"}, got.Predicate.Licenses); d != "" {
+		t.Errorf("attestation licenses mismatch (-want +got):\n%s", d)
+	}
+}
+
+func Test_CLI_postResults(t *testing.T) {
+	t.Parallel()
+	var got attestation.Statement
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet", "--postResults", srv.URL, "--postToken", "secret"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("expected the bearer token got %q", auth)
+	}
+	if d := cmp.Diff([]string{"0BSD"}, got.Predicate.Licenses); d != "" {
+		t.Errorf("posted licenses mismatch (-want +got):\n%s", d)
+	}
+
+	// Offline, the scan fails before it is posted
+	auth = ""
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet", "--postResults", srv.URL, "--postToken", "secret", "--offline"})
+	if err := cmd.Execute(); !errors.Is(err, sink.ErrOffline) {
+		t.Errorf("expected the offline error got %v", err)
+	}
+	if auth != "" {
+		t.Error("expected no post offline")
+	}
+}
+
+func Test_CLI_report(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet",
+		"--report", "licensee=" + filepath.Join(dir, "licensee.json"), "--report", "jsonl=" + filepath.Join(dir, "scan.jsonl")})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	for _, name := range []string{"licensee.json", "scan.jsonl"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(b, []byte(`"0BSD"`)) || !bytes.Contains(b, []byte(`"metadata"`)) {
+			t.Errorf("expected the 0BSD license and the metadata in %v got %s", name, b)
+		}
+	}
+
+	for _, args := range [][]string{{"--report", "text=scan.txt"}, {"--report", "bogus"}, {"--report", "licensee", "--report", "jsonl"}} {
+		cmd := NewRootCmd()
+		cmd.SetArgs(append([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--quiet"}, args...))
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("did not get expected error for %v", args)
+		}
+	}
+}
+
+func Test_CLI_query(t *testing.T) {
+	t.Parallel()
+	if !store.Available() {
+		t.Skip(store.ErrNoSQLite)
+	}
+	db := filepath.Join(t.TempDir(), "results.sqlite")
+	for i := 0; i < 2; i++ {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet", "--db", db})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Got unexpected error: %v", err)
+		}
+	}
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"runs"}, want: "0BSD.txt"},
+		{args: []string{"run", "1"}, want: "Run 1 of ../testdata/addAll/input/text/0BSD.txt"},
+		{args: []string{"license", "*bsd"}, want: "1 files with *bsd in run 2"},
+		{args: []string{"file", "0BSD.txt", "--json"}, want: `"run": 2`},
+	} {
+		cmd := NewRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(append(append([]string{"query"}, tt.args...), "--db", db))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Got unexpected error for %v: %v", tt.args, err)
+		}
+		if !strings.Contains(out.String(), tt.want) {
+			t.Errorf("expected %q in the output of %v got %v", tt.want, tt.args, out.String())
+		}
+	}
+
+	for _, args := range [][]string{{"runs"}, {"run", "3", "--db", db}, {"license", "GPL-[", "--db", db}} {
+		cmd := NewRootCmd()
+		cmd.SetArgs(append([]string{"query"}, args...))
+		cmd.SetOut(io.Discard)
+		cmd.SetErr(io.Discard)
+		if err := cmd.Execute(); err == nil {
+			t.Errorf("did not get expected error for %v", args)
+		}
+	}
+}
+
+func Test_CLI_trend(t *testing.T) {
+	t.Parallel()
+	if !store.Available() {
+		t.Skip(store.ErrNoSQLite)
+	}
+	db := filepath.Join(t.TempDir(), "results.sqlite")
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "0BSD", "--quiet", "--db", db})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	for args, want := range map[string]string{"": "| 0BSD | low |", "--html": `<td class="low">low</td>`} {
+		cmd := NewRootCmd()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetArgs(strings.Fields("trend --db " + db + " " + args))
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Got unexpected error for %v: %v", args, err)
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the trend %v got %v", want, args, out.String())
+		}
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"trend", "--db", filepath.Join(t.TempDir(), "empty.sqlite")})
+	cmd.SetErr(io.Discard)
+	if err := cmd.Execute(); err == nil {
+		t.Error("did not get expected error for a database without runs")
+	}
+}
+
+func Test_CLI_deprecatedIDs(t *testing.T) {
+	t.Parallel()
+	for _, mode := range []string{"both", "deprecated", "current"} {
+		cmd := NewRootCmd()
+		cmd.SetArgs([]string{"-f", "../resources/spdx/default/testdata/deprecated_GPL-2.0+.txt", "--deprecatedIDs", mode})
+		if err := cmd.Execute(); err != nil {
+			t.Fatalf("Got unexpected error for %v: %v", mode, err)
+		}
+	}
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--deprecatedIDs", "bogus"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("did not get expected error for --deprecatedIDs bogus")
+	}
+}
+
+func Test_CLI_only_exclude(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "*BSD*,MIT", "--exclude", "BSD-4-*"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{"-f", "../testdata/addAll/input/text/0BSD.txt", "--only", "GPL-["})
+	if err := cmd.Execute(); err == nil {
+		t.Error("did not get expected error for a bad --only pattern")
+	}
+}
+
 func Test_CLI_addAll_Bogus(t *testing.T) {
 	t.Parallel()
 	cmd := NewRootCmd()
@@ -95,11 +333,11 @@ func Test_CLI_addAll(t *testing.T) {
 	t.Parallel()
 
 	addAll := "../testdata/addAll"
-	output := path.Join(addAll, "output")
-	versionedDir := path.Join(output, "spdx/3.17")
-	newTemplate := path.Join(versionedDir, "template", "0BSD.template.txt")
-	newTestData := path.Join(versionedDir, "testdata", "0BSD.txt")
-	newPreCheck := path.Join(versionedDir, "precheck", "0BSD.json")
+	output := filepath.Join(addAll, "output")
+	versionedDir := filepath.Join(output, "spdx", "3.17")
+	newTemplate := filepath.Join(versionedDir, "template", "0BSD.template.txt")
+	newTestData := filepath.Join(versionedDir, "testdata", "0BSD.txt")
+	newPreCheck := filepath.Join(versionedDir, "precheck", "0BSD.json")
 
 	for _, newFile := range []string{newTemplate, newTestData, newPreCheck} {
 		if _, err := os.Stat(newFile); !errors.Is(err, fs.ErrNotExist) {
@@ -120,7 +358,7 @@ func Test_CLI_addAll(t *testing.T) {
 
 	cmd := NewRootCmd()
 	cmd.SetArgs([]string{
-		"--addAll", "testdata/addAll/input",
+		"--addAll", "../testdata/addAll/input",
 		"--configPath", "../testdata/addAll",
 		"--spdx", "3.17",
 	})
@@ -135,6 +373,58 @@ func Test_CLI_addAll(t *testing.T) {
 	}
 }
 
+func Test_CLI_addAllXML(t *testing.T) {
+	t.Parallel()
+
+	addAllXML := "../testdata/addAllXML"
+	output := filepath.Join(addAllXML, "output")
+	versionedDir := filepath.Join(output, "spdx", "xml")
+	var newFiles []string
+	for _, id := range []string{"0BSD", "BSD-2-Clause", "Autoconf-exception-2.0"} {
+		newFiles = append(newFiles,
+			filepath.Join(versionedDir, "template", id+".template.txt"),
+			filepath.Join(versionedDir, "testdata", id+".txt"),
+			filepath.Join(versionedDir, "precheck", id+".json"))
+	}
+	newFiles = append(newFiles, filepath.Join(versionedDir, "json", "licenses.json"), filepath.Join(versionedDir, "json", "exceptions.json"))
+
+	if err := os.Mkdir(output, 0o777); err != nil {
+		t.Fatalf("error creating output dir: %v", err)
+	}
+
+	defer func(path string) {
+		err := os.RemoveAll(path)
+		if err != nil {
+			t.Fatalf("error removing output dir: %v", err)
+		}
+	}(output)
+
+	cmd := NewRootCmd()
+	cmd.SetArgs([]string{
+		"--addAllXML", "../testdata/addAllXML/input",
+		"--configPath", addAllXML,
+	})
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("did not get expected error without --spdx")
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetArgs([]string{
+		"--addAllXML", "../testdata/addAllXML/input",
+		"--configPath", addAllXML,
+		"--spdx", "xml",
+	})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Got unexpected error: %v", err)
+	}
+
+	for _, newFile := range newFiles {
+		if _, err := os.Stat(newFile); err != nil {
+			t.Fatalf("File should exist after test creates it: %v", newFile)
+		}
+	}
+}
+
 func Test_CLI__configPath_not_found(t *testing.T) {
 	t.Parallel()
 	cmd := NewRootCmd()
@@ -193,3 +483,188 @@ func Test_CLI_list_spdx(t *testing.T) {
 		t.Fatalf("Expected nil err for valid --spdx dir and --list got: %v", err)
 	}
 }
+
+func Test_CLI_licensesList(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"licenses", "list", "--quiet", "--json", "--deprecated", "--family", "gpl"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("licenses list error = %v", err)
+	}
+	var entries []licenses.Entry
+	if err := json.Unmarshal(out.Bytes(), &entries); err != nil {
+		t.Fatalf("licenses list --json output is not JSON: %v\n%s", err, out.String())
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	want := []string{"GPL-1.0", "GPL-1.0+", "GPL-2.0", "GPL-2.0+", "GPL-2.0-with-GCC-exception"}
+	if len(ids) < len(want) {
+		t.Fatalf("licenses list IDs = %v, want at least %v", ids, want)
+	}
+	if d := cmp.Diff(want, ids[:len(want)]); d != "" {
+		t.Errorf("licenses list IDs mismatch (-want +got):\n%s", d)
+	}
+	for _, e := range entries {
+		if !e.Deprecated || e.Family != "GPL" {
+			t.Errorf("licenses list --deprecated --family gpl listed %+v", e)
+		}
+	}
+}
+
+func Test_CLI_licensesShow(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"licenses", "show", "mit", "--quiet", "--no-color"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("licenses show error = %v", err)
+	}
+	for _, want := range []string{"ID:            MIT\n", "PRIMARY PATTERN ", "MIT.template.txt", "Precheck blocks (", "CANONICAL TEXT\n"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("licenses show output does not contain %q:\n%s", want, out.String())
+		}
+	}
+
+	cmd = NewRootCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"licenses", "show", "Nope", "--quiet"})
+	if err := cmd.Execute(); err == nil {
+		t.Error("licenses show Nope expected an error")
+	}
+}
+
+func Test_CLI_compare(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "exact", args: []string{"compare", "../resources/spdx/default/testdata/MIT.txt", "MIT"}, want: "matches the MIT text exactly"},
+		{name: "diff", args: []string{"compare", "../resources/spdx/default/testdata/MIT.txt", "0BSD"}, want: "[-"},
+		{name: "unknown license", args: []string{"compare", "../resources/spdx/default/testdata/MIT.txt", "Bogus-1.0"}, wantErr: true},
+		{name: "missing args", args: []string{"compare", "../resources/spdx/default/testdata/MIT.txt"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			out := new(bytes.Buffer)
+			cmd.SetOut(out)
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("compare expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compare error = %v", err)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("compare output does not contain %q:\n%v", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func Test_CLI_benchAccuracy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "no regressions", args: []string{"--against", "../testdata/bench/baseline.json"}, want: "No accuracy regressions"},
+		{name: "regressed", args: []string{"--labels", "../testdata/bench/mislabeled.yaml", "--against", "../testdata/bench/baseline.json"}, want: "COPYING\tmissing ISC; unexpected 0BSD", wantErr: true},
+		{name: "no labels", args: []string{"--labels", "../testdata/bench/bogus.yaml"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cmd := NewRootCmd()
+			out := new(bytes.Buffer)
+			cmd.SetOut(out)
+			cmd.SetErr(new(bytes.Buffer))
+			cmd.SetArgs(append([]string{"bench", "accuracy", "../testdata/bench/corpus"}, tt.args...))
+			if err := cmd.Execute(); (err != nil) != tt.wantErr {
+				t.Fatalf("bench accuracy error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("bench accuracy output does not contain %q:\n%v", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func Test_CLI_compareTools(t *testing.T) {
+	t.Parallel()
+	if licenseCheck != nil {
+		t.Skip("built with licensecheck")
+	}
+	cmd := NewRootCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"compare-tools", "../testdata"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "-tags licensecheck") {
+		t.Errorf("compare-tools without licensecheck error = %v", err)
+	}
+}
+
+func Test_otherToolFindings(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{File: "corpus/MIT.txt", OriginalText: "MIT License"},
+		{File: "corpus/sub/readme", OriginalText: "nothing"},
+	}
+	scan := func(text []byte) []string {
+		if strings.Contains(string(text), "MIT") {
+			return []string{"MIT"}
+		}
+		return nil
+	}
+	got := otherToolFindings("fake", "corpus", results, scan)
+	want := &external.Findings{Tool: "fake", Files: map[string][]string{"MIT.txt": {"MIT"}, "sub/readme": nil}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("otherToolFindings() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func Test_CLI_resourcesCheck(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"resources", "check", "--quiet", "--no-color"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("resources check error = %v\n%s", err, out.String())
+	}
+	if want := "0 missing files, 0 files without an ID"; !strings.Contains(out.String(), want) {
+		t.Errorf("resources check output does not contain %q:\n%s", want, out.String())
+	}
+}
+
+func Test_CLI_selftest(t *testing.T) {
+	t.Parallel()
+	cmd := NewRootCmd()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"selftest", "--only", "MIT,ISC,GPL-2.0", "--quiet", "--no-color"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("selftest error = %v\n%s", err, out.String())
+	}
+	if want := "3 passed, 0 failed"; !strings.Contains(out.String(), want) {
+		t.Errorf("selftest output does not contain %q:\n%s", want, out.String())
+	}
+}
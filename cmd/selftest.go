@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/selftest"
+)
+
+// errSelfTestFailed is returned (for a non-zero exit code) when the templates of a license did not match its own text
+var errSelfTestFailed = errors.New("the self-test failed")
+
+func newSelfTestCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "selftest",
+		Short: "Verify that the templates of each license of the library match its own SPDX license list text",
+		Long: `
+Load the license library like for a scan (--spdx, --custom, or --compiled, after --only and --exclude),
+then match the templates (primary patterns) of each license with its own SPDX license list text
+(testdata), and report the licenses which no template matched. Only the templates are matched, not the
+exact hashes, aliases, or URLs, so that a broken template is not hidden. Run it to verify a deployment,
+especially with custom resource paths (--resources-root or the config file), before trusting the scan
+results. The licenses without an SPDX license list text (e.g., the custom licenses) are skipped. With
+--json, the report is output as JSON.
+
+The exit code is non-zero when the templates of a license did not match its own text.
+
+Example usage:
+
+    $ license-scanner selftest
+    $ license-scanner selftest --resources-root /opt/license-scanner/resources
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			r, err := selftest.Run(licenseLibrary)
+			if err != nil {
+				return err
+			}
+			if asJSON, _ := cmd.Flags().GetBool(jsonFlag); asJSON {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(r); err != nil {
+					return err
+				}
+			} else {
+				printSelfTest(cmd.OutOrStdout(), r, newPalette(cfg))
+			}
+			if len(r.Failures) > 0 {
+				cmd.SilenceUsage = true
+				return errSelfTestFailed
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Bool(jsonFlag, false, "Output the self-test report as JSON")
+	return cmd
+}
+
+// printSelfTest prints the licenses whose templates did not match their own text, and a summary
+func printSelfTest(out io.Writer, r selftest.Report, colors palette) {
+	if len(r.Failures) > 0 {
+		fmt.Fprintf(out, "%v\n", colors.heading("FAILURES"))
+		for _, f := range r.Failures {
+			reason := "no templates"
+			if len(f.Templates) > 0 {
+				reason = "not matched by " + strings.Join(f.Templates, ", ")
+			}
+			if f.Error != "" {
+				reason += " (" + f.Error + ")"
+			}
+			fmt.Fprintf(out, "\t%v\t%v\t%v\n", colors.warn(f.ID), f.File, reason)
+		}
+		fmt.Fprintln(out)
+	}
+	summary := fmt.Sprintf("%v passed, %v failed, %v skipped (no SPDX license list text)", r.Passed, len(r.Failures), len(r.Skipped))
+	if len(r.Failures) > 0 {
+		summary = colors.warn(summary)
+	}
+	fmt.Fprintln(out, summary)
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/report"
+)
+
+// The trend flags
+const (
+	lastFlag = "last"
+	htmlFlag = "html"
+)
+
+func newTrendCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trend",
+		Short: "Chart the license counts and policy violations across the scans recorded in a --db results database",
+		Long: `
+Chart the number of files with each license and the policy violations (the files with a high risk
+license) across the scans recorded in the --db results database (the runs, with their git commits),
+oldest first, as Markdown (e.g., for a job summary or a pull request comment) or, with --html, as an
+HTML page with SVG line charts. The risk levels are assessed with the current --riskModel, --linking,
+and --distribution, from the license library, so that every run is measured against the same policy.
+With --last, only the last runs are charted.
+
+Example usage:
+
+    $ license-scanner trend --db results.sqlite --last 30 > trend.md
+    $ license-scanner trend --db results.sqlite --html > trend.html
+		`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			db, err := openStore(cfg)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			riskModel, riskContext, err := loadRiskModel(cfg)
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAll(); err != nil {
+				return err
+			}
+			last, _ := cmd.Flags().GetInt(lastFlag)
+			t, err := report.NewTrend(db, last, riskModel, riskContext, licenseLibrary)
+			if err != nil {
+				cmd.SilenceUsage = true
+				return err
+			}
+			if asHTML, _ := cmd.Flags().GetBool(htmlFlag); asHTML {
+				return t.WriteHTML(cmd.OutOrStdout())
+			}
+			return t.WriteMarkdown(cmd.OutOrStdout())
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	cmd.Flags().Int(lastFlag, 0, "Only chart the last runs, at most this many (0 for all)")
+	cmd.Flags().Bool(htmlFlag, false, "Output the trend as an HTML page with SVG charts instead of Markdown")
+	return cmd
+}
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/reuse"
+)
+
+// errTagMismatch is returned (for a non-zero exit code) when an SPDX tag does not agree with the detected licenses
+var errTagMismatch = errors.New("the SPDX tags do not agree with the detected licenses")
+
+func newVerifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <dir>",
+		Short: "Verify the SPDX-License-Identifier tags against the licenses of the files",
+		Long: `
+Verify every SPDX-License-Identifier tag (in the file, or in a <file>.license file) against the licenses
+detected in the same file: the license texts, notices, and standard license headers. A file without any
+detected license is verified against the primary license of the repository (from its root license files
+or README). A tag is a mismatch when none of its licenses was detected, e.g., a header copied from a file
+of another license. The -only and -or-later variants of a license are not told apart.
+
+The exit code is non-zero when there are mismatches.
+
+Example usage to verify the current directory:
+
+    $ license-scanner verify .
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := configurer.InitConfig(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			licenseLibrary, err := licenses.NewLicenseLibrary(cfg)
+			if err != nil {
+				return err
+			}
+			if err := licenseLibrary.AddAllSPDX(); err != nil {
+				return err
+			}
+			v, err := reuse.Verify(args[0], licenseLibrary)
+			if err != nil {
+				return err
+			}
+			printVerification(cmd.OutOrStdout(), v, newPalette(cfg))
+			if !v.Verified() {
+				cmd.SilenceUsage = true
+				return errTagMismatch
+			}
+			return nil
+		},
+	}
+	configurer.AddDefaultFlags(cmd.Flags())
+	return cmd
+}
+
+// printVerification prints the mismatches and a summary
+func printVerification(out io.Writer, v *reuse.Verification, colors palette) {
+	for _, m := range v.Mismatches {
+		declared, detected := colors.warn(strings.Join(m.Declared, ", ")), colors.id(strings.Join(m.Detected, ", "))
+		if m.Source == reuse.SourceRepository {
+			fmt.Fprintf(out, "%v: declared %v, but the repository license is %v\n", m.File, declared, detected)
+		} else {
+			fmt.Fprintf(out, "%v: declared %v, but found %v in the file\n", m.File, declared, detected)
+		}
+	}
+	fmt.Fprintf(out, "Repository license: %v\n", v.Repository.Expression)
+	fmt.Fprintf(out, "Verified %v of %v files with SPDX tags\n", len(v.Tagged)-len(v.Mismatches), len(v.Tagged))
+}
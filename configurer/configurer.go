@@ -3,50 +3,164 @@
 package configurer
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path"
 	"path/filepath"
-	"runtime"
+	"strings"
 
 	"github.com/spf13/pflag"
 
 	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/httpclient"
 )
 
 const (
-	AcceptableFlag = "acceptable"
-	CopyrightsFlag = "copyrights"
-	NormalizedFlag = "normalized"
-	HashFlag       = "hash"
-	KeywordsFlag   = "keywords"
-	ListFlag       = "list"
-	AddAllFlag     = "addAll"
-	AddPatternFlag = "addPattern"
-	DebugFlag      = "debug"
-	QuietFlag      = "quiet"
-	LicenseFlag    = "license"
-	DirFlag        = "dir"
-	FileFlag       = "file"
-	ConfigPathFlag = "configPath"
-	ConfigNameFlag = "configName"
-	SpdxFlag       = "spdx"
-	CustomFlag     = "custom"
+	AcceptableFlag     = "acceptable"
+	CopyrightsFlag     = "copyrights"
+	NormalizedFlag     = "normalized"
+	HashFlag           = "hash"
+	KeywordsFlag       = "keywords"
+	ListFlag           = "list"
+	AddAllFlag         = "addAll"
+	AddAllXMLFlag      = "addAllXML"
+	AddPatternFlag     = "addPattern"
+	DebugFlag          = "debug"
+	QuietFlag          = "quiet"
+	LicenseFlag        = "license"
+	DirFlag            = "dir"
+	FileFlag           = "file"
+	ConfigPathFlag     = "configPath"
+	ConfigNameFlag     = "configName"
+	ResourcesRootFlag  = "resources-root"
+	SpdxFlag           = "spdx"
+	CustomFlag         = "custom"
+	CompiledFlag       = "compiled"
+	GoModFlag          = "gomod"
+	NPMFlag            = "npm"
+	PackagesFlag       = "packages"
+	DEP5Flag           = "dep5"
+	UnknownsFlag       = "unknowns"
+	ObligationsFlag    = "obligations"
+	RiskFlag           = "risk"
+	RiskModelFlag      = "riskModel"
+	LinkingFlag        = "linking"
+	DistributionFlag   = "distribution"
+	CacheDirFlag       = "cacheDir"
+	CacheFlag          = "cache"
+	OnlyFlag           = "only"
+	ExcludeFlag        = "exclude"
+	VariablesFlag      = "variables"
+	HeadersFlag        = "headers"
+	BinariesFlag       = "binaries"
+	ExplainFlag        = "explain"
+	HighlightFlag      = "highlight"
+	EnsembleFlag       = "ensemble"
+	ScanCodeFlag       = "scancode"
+	NoColorFlag        = "no-color"
+	FormatFlag         = "format"
+	BaselineFlag       = "baseline"
+	SinceFlag          = "since"
+	ProjectsFlag       = "projects"
+	WorkspacesFlag     = "workspaces"
+	RepoLicenseFlag    = "repoLicense"
+	WriteBaselineFlag  = "writeBaseline"
+	RequireLicenseFlag = "requireLicense"
+	ExitCodesFlag      = "exitCodes"
+	CurationsFlag      = "curations"
+	TemplateFileFlag   = "template-file"
+	ReportFlag         = "report"
+	DeterministicFlag  = "deterministic"
+
+	AttestationFlag     = "attestation"
+	AttestationSignFlag = "attestationSign"
+	AttestationKeyFlag  = "attestationKey"
+
+	PostResultsFlag = "postResults"
+	PostTokenFlag   = "postToken"
+	PostRetriesFlag = "postRetries"
+
+	HTTPTimeoutFlag = "http-timeout"
+	HTTPRetriesFlag = "http-retries"
+	HTTPProxyFlag   = "http-proxy"
+	HTTPCAFileFlag  = "http-ca-file"
+
+	DBFlag = "db"
+
+	OfflineFlag = "offline"
+
+	PreCheckMinLengthFlag = "precheckMinLength"
+	PreCheckMaxBlocksFlag = "precheckMaxBlocks"
+	PreCheckRequiredFlag  = "precheckRequired"
+
+	TemplateTimeoutFlag = "templateTimeout"
+	FileTimeoutFlag     = "fileTimeout"
+
+	DeprecatedIDsFlag = "deprecatedIDs"
+
+	CustomNamespaceFlag = "customNamespace"
+	CustomCollisionFlag = "customCollision"
+
+	SymlinksFlag     = "symlinks"
+	SpecialFilesFlag = "specialFiles"
+	HardLinksFlag    = "hardLinks"
+	AllFilesFlag     = "allFiles"
+	ThoroughFlag     = "thorough"
+
+	MaxArchiveDepthFlag     = "maxArchiveDepth"
+	MaxExtractedSizeFlag    = "maxExtractedSize"
+	MaxCompressionRatioFlag = "maxCompressionRatio"
+	MaxMemoryFlag           = "maxMemory"
+	MaxVariableLengthFlag   = "maxVariableLength"
 )
 
+// resourcesDir is the name of the directory of the license resources
+const resourcesDir = "resources"
+
+// configFileKeys are the settings of files which are relative to the config file (like its resources)
+var configFileKeys = []string{CompiledFlag, RiskModelFlag}
+
 var (
-	_, thisFile, _, _ = runtime.Caller(0) // Dirs/files are relative to this file
-	thisDir           = filepath.Dir(thisFile)
-	execDir, _        = os.Executable()
-	execPath          = filepath.Dir(execDir)
-	projectRoot       = path.Join(thisDir, "..")
+	execDir, _ = os.Executable()
+	execPath   = filepath.Dir(execDir)
 )
 
+// ProjectRoot returns the first of the working directory and its parents with the license resources (a
+// resources directory with an spdx directory), e.g., the root of a clone of license-scanner. Otherwise, it
+// returns the directory of the executable.
+func ProjectRoot() string {
+	if dir, err := os.Getwd(); err == nil {
+		for {
+			if fi, err := os.Stat(filepath.Join(dir, resourcesDir, "spdx")); err == nil && fi.IsDir() {
+				return dir
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	return execPath
+}
+
+// resolvePath returns the path p relative to the directory dir, or p when it is absolute (e.g., C:\resources
+// or \\server\share on Windows). The path is cleaned with the separators of the OS.
+func resolvePath(dir string, p string) string {
+	if filepath.IsAbs(p) {
+		return filepath.Clean(p)
+	}
+	return filepath.Join(dir, p)
+}
+
 func InitConfig(flags *pflag.FlagSet) (*viper.Viper, error) {
 	newViper := viper.New()
 	newViper.AutomaticEnv()
 
-	newViper.SetDefault("resources", path.Join(projectRoot, "resources"))
+	projectRoot := ProjectRoot()
+	newViper.SetDefault("resources", filepath.Join(projectRoot, resourcesDir))
 	newViper.SetDefault("configName", "config")
 
 	if flags != nil {
@@ -66,12 +180,7 @@ func InitConfig(flags *pflag.FlagSet) (*viper.Viper, error) {
 	// TODO: Deprecate configFrom in favor of configPath and configName
 	configFrom := newViper.GetString("configFrom")
 	if configFrom != "" {
-		if path.IsAbs(configFrom) {
-			newViper.SetConfigFile(configFrom)
-		} else {
-			configFrom = path.Join(thisDir, "..", configFrom)
-			newViper.SetConfigFile(configFrom)
-		}
+		newViper.SetConfigFile(configFrom) // a relative path is relative to the working directory
 	} else { // configPath (configName defaults to "config.<ext>")
 		newViper.SetConfigName(configName)
 		if configPath != "" {
@@ -82,8 +191,10 @@ func InitConfig(flags *pflag.FlagSet) (*viper.Viper, error) {
 		}
 	}
 
+	// Without a --configPath, the config file is optional (e.g., for an installed executable)
 	err := newViper.MergeInConfig()
-	if err != nil {
+	var notFound viper.ConfigFileNotFoundError
+	if err != nil && !(errors.As(err, &notFound) && configPath == "") {
 		return nil, fmt.Errorf("MergeInConfig err: %w", err)
 	}
 
@@ -94,14 +205,29 @@ func InitConfig(flags *pflag.FlagSet) (*viper.Viper, error) {
 			configDir := filepath.Dir(configFileUsed)
 
 			// Make all relative paths relative to the config file used.
-			resources := newViper.GetString("resources")
-			if resources != "" && !path.IsAbs(resources) {
-				resources = path.Join(configDir, resources)
-				newViper.Set("resources", resources) // override
+			if resources := newViper.GetString("resources"); resources != "" {
+				newViper.Set("resources", resolvePath(configDir, resources)) // override
 			}
 		}
 	}
 
+	// The relative files of the config file (e.g., of a bundle) are also relative to the config file, unless a
+	// flag or the environment sets them
+	if configFileUsed := newViper.ConfigFileUsed(); configFileUsed != "" {
+		for _, key := range configFileKeys {
+			p := newViper.GetString(key)
+			if p == "" || !newViper.InConfig(key) || os.Getenv(strings.ToUpper(key)) != "" || (flags != nil && flags.Changed(key)) {
+				continue
+			}
+			newViper.Set(key, resolvePath(filepath.Dir(configFileUsed), p))
+		}
+	}
+
+	// An explicit resources root takes precedence over the config file (a relative path is relative to the working directory)
+	if resourcesRoot := newViper.GetString(ResourcesRootFlag); resourcesRoot != "" {
+		newViper.Set("resources", filepath.Clean(resourcesRoot))
+	}
+
 	// TODO: env from a file is W-I-P.
 	// Doc and test or just use config.env with above code and remove this.
 	envFrom := newViper.GetString("envFrom")
@@ -123,19 +249,88 @@ func NewDefaultFlags() *pflag.FlagSet {
 func AddDefaultFlags(flagSet *pflag.FlagSet) {
 	flagSet.BoolP(DebugFlag, "d", false, "Enable debug logging")
 	flagSet.BoolP(QuietFlag, "q", false, "Set logging to quiet")
+	flagSet.Bool(NoColorFlag, false, "Disable colored output (color is only used when the output is a terminal and NO_COLOR is not set)")
+	flagSet.String(FormatFlag, "text", "The output format of the --file and --dir scans: text, licensee (the JSON of GitHub's licensee detect --json), jsonl (a JSON line per file as it is scanned), template (rendered with the --template-file), github (GitHub Actions annotations of the high and medium risk licenses), or junit (JUnit XML test results, a test per file)")
+	flagSet.StringSlice(ReportFlag, nil, "Write the reports of the --file and --dir scans to these destinations instead of the --format on the standard output, as format=destination (a --format or intoto, and - for the standard output, a file, an http(s) URL to POST to, or an s3://<bucket>/<key> URL), e.g., --report text --report licensee=report.json")
+	flagSet.String(TemplateFileFlag, "", "A Go text/template file to render the results of the --file and --dir scans with --format template")
+	flagSet.String(DBFlag, "", "A SQLite database file in which to record the findings of each --file and --dir scan (a run), for the query command (created when it does not exist)")
+	flagSet.Bool(DeterministicFlag, false, "Leave the start and end time and the host out of the scan metadata of the reports, so that the reports of the same files are identical")
 	flagSet.String(DirFlag, "", "A directory in which to identify licenses")
+	flagSet.String(SinceFlag, "", "Only scan the files in the --dir which were added or modified between this git ref (e.g., origin/main) and HEAD")
+	flagSet.String(CacheDirFlag, "", "A directory in which to cache the match results by normalized content hash (reused across scans)")
+	flagSet.Bool(CacheFlag, false, "Cache the compiled license library of the resources and the match results (unless --cacheDir is set) in the user cache directory ($XDG_CACHE_HOME/license-scanner), reused across scans")
+	flagSet.String(DEP5Flag, "", "Write a machine-readable debian/copyright (DEP-5) skeleton for the --dir scan to this file")
+	flagSet.String(GoModFlag, "", "A Go module directory (with go.mod) in which to identify licenses per module")
+	flagSet.String(NPMFlag, "", "A directory (with node_modules) in which to identify licenses per npm package")
+	flagSet.String(PackagesFlag, "", "A package file (Python wheel or sdist, Java jar/war/ear/aar, Ruby gem, NuGet nupkg) or a directory of package files in which to identify licenses per package")
+	flagSet.Int(MaxArchiveDepthFlag, extractor.DefaultLimits.MaxDepth, "How many archives deep to open archives in archives (0 to not open nested archives)")
+	flagSet.Int64(MaxExtractedSizeFlag, extractor.DefaultLimits.MaxExtractedSize, "The total number of bytes which may be extracted from archives")
+	flagSet.Int64(MaxCompressionRatioFlag, extractor.DefaultLimits.MaxCompressionRatio, "The largest compression ratio allowed for an archive entry (zip bomb protection)")
 	flagSet.StringP(FileFlag, "f", "", "A file in which to identify licenses")
+	flagSet.String(SymlinksFlag, "files", "How the --dir scan handles the symlinks: files (scan the symlinks to files, not to directories), follow (also walk the symlinked directories, skipping the loops), or skip")
+	flagSet.String(SpecialFilesFlag, "skip", "How the --dir scan handles the sockets, named pipes, and devices: skip, or error (fail the scan)")
+	flagSet.String(HardLinksFlag, "scan", "How the --dir scan handles the paths of the same file (hard links, or followed symlinks): scan (each path), once (scan the first path, and report the other paths with its results), or skip (the other paths)")
+	flagSet.Bool(ThoroughFlag, false, "Scan the whole of every file of the --dir scan, instead of the quick scan of the license files (LICENSE, COPYING, NOTICE, COPYRIGHT, and README) and the headers (the first 8 KB) of the other files")
+	flagSet.Bool(AllFilesFlag, false, "Scan all the files of the --dir scan, instead of skipping the binary and media files by their magic bytes (e.g., images, archives, and the executables without --binaries)")
+	flagSet.Bool(EnsembleFlag, false, "Also use hash matching and fuzzy similarity with the templates, and output which algorithms matched each license")
+	flagSet.Int(PreCheckMinLengthFlag, 0, "Only check the precheck static blocks with at least this many characters (0 for all)")
+	flagSet.Int(PreCheckMaxBlocksFlag, 0, "Only check the longest precheck static blocks of each template, at most this many (0 for all)")
+	flagSet.Int(PreCheckRequiredFlag, 0, "Match a template when the input has this many of its precheck static blocks (0 for all, slower when set)")
+	flagSet.Int(MaxVariableLengthFlag, 1000, "The most characters (repetitions) which each wildcard of a template variable may match, up to 1000 (the largest Go regexp repeat)")
+	flagSet.Duration(TemplateTimeoutFlag, 0, "Abort a single template match which takes longer than this (e.g., 10s, 0 for no timeout)")
+	flagSet.Duration(FileTimeoutFlag, 0, "Stop matching a file after this long and output the matches found so far (e.g., 1m, 0 for no timeout)")
+	flagSet.Int64(MaxMemoryFlag, 0, "The bytes of memory which the files matched at the same time may use (estimated from their size), matching a larger file alone (0 for no limit)")
 	flagSet.BoolP(AcceptableFlag, "g", false, "Flag acceptable")
 	flagSet.BoolP(KeywordsFlag, "k", false, "Flag keywords")
+	flagSet.Bool(VariablesFlag, false, "Output the text matched by the license template variables (e.g., copyright holder)")
+	flagSet.Bool(HeadersFlag, false, "Also match the standard license headers (the short notices at the top of source files, e.g., of the GPL), output apart from the full-text license matches")
+	flagSet.Bool(BinariesFlag, false, "Identify the licenses in the printable strings of the ELF, PE, and Mach-O executables and libraries (e.g., the notices compiled into them), with the headers and keywords")
+	flagSet.String(DeprecatedIDsFlag, "both", "How to output deprecated SPDX IDs: both (with the current expression), deprecated, or current")
+	flagSet.String(ScanCodeFlag, "", "A ScanCode toolkit JSON output of the same --dir to reconcile with, to flag agreements and conflicts per file")
+	flagSet.String(BaselineFlag, "", "A baseline file of accepted findings (file hash and license) to fail the --dir scan only on new or changed findings")
+	flagSet.String(WriteBaselineFlag, "", "Write the findings of the --dir scan to this baseline file (to accept them)")
+	flagSet.String(AttestationFlag, "", "Write the results of the --file or --dir scan to this file as an in-toto statement (the digest of the scanned file or directory with the license findings), for supply-chain attestations")
+	flagSet.Bool(AttestationSignFlag, false, "Sign the --attestation file with cosign (sign-blob), writing the signature bundle next to it (<file>.bundle)")
+	flagSet.String(AttestationKeyFlag, "", "The cosign key reference with which to sign the --attestation (keyless signing with an OIDC identity when it is not set)")
+	flagSet.String(PostResultsFlag, "", "POST the JSON report of the --file or --dir scan (the in-toto statement of --attestation) to this URL of a compliance service when the scan completes")
+	flagSet.String(PostTokenFlag, "", "The bearer token of the --postResults service (or set LICENSE_SCANNER_POST_TOKEN)")
+	flagSet.Int(PostRetriesFlag, httpclient.DefaultRetries, "How many times to retry the --postResults after a network error or a 5xx, 429, or 408 response (with an exponential backoff from 1s)")
+	_ = flagSet.MarkDeprecated(PostRetriesFlag, "use --http-retries")
+	flagSet.Duration(HTTPTimeoutFlag, httpclient.DefaultTimeout, "The timeout of each attempt of the HTTP requests of the network features (the http, https, and s3 --report destinations and --postResults), 0 for no timeout")
+	flagSet.Int(HTTPRetriesFlag, httpclient.DefaultRetries, "How many times to retry an HTTP request after a network error or a 5xx, 429, or 408 response (with an exponential backoff from 1s)")
+	flagSet.String(HTTPProxyFlag, "", "The URL of the proxy of the HTTP requests (the HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment variables are used when it is not set)")
+	flagSet.String(HTTPCAFileFlag, "", "A PEM file of CA certificates to trust for the HTTPS requests in addition to the system certificates (e.g., of a TLS-inspecting proxy or an internal service)")
+	flagSet.Bool(OfflineFlag, false, "Fail the features which need network access (the http, https, and s3 --report destinations, --postResults, and --attestationSign) instead of accessing the network, for air-gapped scans")
+	flagSet.Bool(RequireLicenseFlag, false, "Fail the scan when no license matched (the license status is evidence, unlicensed, partial, or no-license)")
+	flagSet.StringToString(ExitCodesFlag, nil, "The exit codes of the conditions of the --file and --dir scans (e.g., denied=3,none=4): denied (a license of the high risk level of the --riskModel), unknown (license text which matched no license), timeout (a file or template timeout), none (no license matched), and error (a failed scan, 1 by default)")
+	flagSet.String(CurationsFlag, "", "A curation file (YAML or JSON) of the licenses concluded by reviewers per file (--dir) or package, to output the concluded license next to the detected ones")
+	flagSet.Bool(UnknownsFlag, false, "Cluster the files with license-looking text which matched no license (--dir)")
+	flagSet.Bool(ObligationsFlag, false, "Output a summary of the obligations of the detected licenses (e.g., attribution, source disclosure)")
+	flagSet.Bool(RiskFlag, false, "Output a risk summary of the detected licenses (from the license categories, the --linking, and the --distribution)")
+	flagSet.String(RiskModelFlag, "", "A risk model file (YAML or JSON) mapping the license categories and contexts to risk levels (instead of the built-in model)")
+	flagSet.String(LinkingFlag, "dynamic", "How the scanned code is linked, for the risk summary: static or dynamic")
+	flagSet.String(DistributionFlag, "distributed", "How the scanned code is delivered, for the risk summary: internal, network (e.g., SaaS), or distributed")
+	flagSet.StringSlice(ProjectsFlag, nil, "Project roots in the --dir (comma-separated globs like packages/*) to output a license summary per project")
+	flagSet.Bool(WorkspacesFlag, false, "Find the project roots in the workspace files of the --dir (package.json, pnpm-workspace.yaml, lerna.json, go.work, Cargo.toml)")
+	flagSet.Bool(RepoLicenseFlag, false, "Determine the primary license of the --dir repository from its root license files and README, as an SPDX expression")
 	flagSet.BoolP(CopyrightsFlag, "c", false, "Flag copyrights")
 	flagSet.BoolP(NormalizedFlag, "n", false, "Flag normalized")
 	flagSet.BoolP(HashFlag, "x", false, "Output file hash")
+	flagSet.Bool(HighlightFlag, false, "Output the text of each file with the matched regions highlighted")
 	flagSet.StringP(LicenseFlag, "l", "", "Display match debugging for the given license")
+	flagSet.String(ExplainFlag, "", "Explain where the given license ID stopped matching the --file (the missing precheck block or regex segment)")
 	flagSet.StringP(AddPatternFlag, "a", "", "Add a new license pattern to the library, from SPDX")
 	flagSet.Bool(ListFlag, false, "List the license templates to be used")
 	flagSet.String(AddAllFlag, "", "Add the licenses from SPDX unzipped release")
+	flagSet.String(AddAllXMLFlag, "", "Add the licenses from a clone of the SPDX license-list-XML repository (with --spdx naming the version)")
 	flagSet.String(ConfigPathFlag, "", "Path to any config files")
 	flagSet.String(ConfigNameFlag, "config", "Base name for config file")
+	flagSet.String(ResourcesRootFlag, "", "The directory of the license resources (with the spdx and custom directories), instead of the resources of the config file or of the project root")
+	flagSet.StringSlice(OnlyFlag, nil, "Only match these license IDs (comma-separated, wildcards like GPL-* allowed)")
+	flagSet.StringSlice(ExcludeFlag, nil, "Do not match these license IDs (comma-separated, wildcards like GPL-* allowed)")
 	flagSet.String(SpdxFlag, "default", "SPDX templates to use")
 	flagSet.String(CustomFlag, "default", "Custom templates to use")
+	flagSet.String(CustomNamespaceFlag, "", "A namespace for the IDs of the custom licenses which are not SPDX licenses, which are then LicenseRef-<namespace>-<id>")
+	flagSet.String(CustomCollisionFlag, "error", "What to do with a custom license (not an SPDX license) with the ID of an SPDX license, without a --customNamespace: error, override (replace the SPDX license), or alias (add its patterns to the SPDX license)")
+	flagSet.String(CompiledFlag, "", "A compiled license library file (from resources compile) to load instead of the --spdx and --custom resources, for a fast startup")
 }
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package configurer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolvePath(t *testing.T) {
+	t.Parallel()
+	dir := filepath.FromSlash("/etc/license-scanner")
+	abs := t.TempDir() // absolute on all systems (with a drive letter on Windows)
+	tests := []struct {
+		name string
+		p    string
+		want string
+	}{
+		{name: "relative", p: "resources", want: filepath.FromSlash("/etc/license-scanner/resources")},
+		{name: "relative parent", p: filepath.FromSlash("../share/resources"), want: filepath.FromSlash("/etc/share/resources")},
+		{name: "relative slashes", p: "res/spdx/../custom", want: filepath.FromSlash("/etc/license-scanner/res/custom")},
+		{name: "absolute", p: abs + string(filepath.Separator) + "resources", want: filepath.Join(abs, "resources")},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := resolvePath(dir, tt.p); got != tt.want {
+				t.Errorf("resolvePath(%q, %q) = %v, want %v", dir, tt.p, got, tt.want)
+			}
+		})
+	}
+}
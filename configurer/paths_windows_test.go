@@ -0,0 +1,33 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit && windows
+
+package configurer
+
+import "testing"
+
+func TestResolvePathWindows(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		dir  string
+		p    string
+		want string
+	}{
+		{name: "relative", dir: `C:\Program Files\license-scanner`, p: "resources", want: `C:\Program Files\license-scanner\resources`},
+		{name: "relative backslashes", dir: `C:\license-scanner`, p: `..\share\resources`, want: `C:\share\resources`},
+		{name: "relative slashes", dir: `C:\license-scanner`, p: "res/spdx", want: `C:\license-scanner\res\spdx`},
+		{name: "drive letter", dir: `C:\license-scanner`, p: `D:\resources`, want: `D:\resources`},
+		{name: "drive letter slashes", dir: `C:\license-scanner`, p: "D:/resources/spdx", want: `D:\resources\spdx`},
+		{name: "UNC", dir: `C:\license-scanner`, p: `\\server\share\resources`, want: `\\server\share\resources`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := resolvePath(tt.dir, tt.p); got != tt.want {
+				t.Errorf("resolvePath(%q, %q) = %v, want %v", tt.dir, tt.p, got, tt.want)
+			}
+		})
+	}
+}
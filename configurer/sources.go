@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package configurer
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// The sources of a setting, in order of precedence
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceConfig  = "config file"
+	SourceDefault = "default"
+)
+
+// Setting is the effective value of a configuration key and where it came from
+type Setting struct {
+	// Key is the (lowercase) configuration key
+	Key   string
+	Value interface{}
+	// Source is SourceFlag, SourceEnv, SourceConfig, or SourceDefault
+	Source string
+}
+
+// Sources returns the settings of the configuration from InitConfig with the flags, sorted by key, with the
+// source each value came from, following the precedence of Viper: a flag which was set, then the environment
+// (the key in uppercase), then the config file, and then the default (the flag defaults included).
+func Sources(cfg *viper.Viper, flags *pflag.FlagSet) []Setting {
+	changed := make(map[string]bool)
+	if flags != nil {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				changed[strings.ToLower(f.Name)] = true
+			}
+		})
+	}
+	keys := cfg.AllKeys()
+	sort.Strings(keys)
+	ret := make([]Setting, 0, len(keys))
+	for _, key := range keys {
+		s := Setting{Key: key, Value: cfg.Get(key), Source: SourceDefault}
+		switch {
+		case changed[key]:
+			s.Source = SourceFlag
+		case os.Getenv(strings.ToUpper(key)) != "": // an empty variable is unset for Viper
+			s.Source = SourceEnv
+		case cfg.InConfig(key):
+			s.Source = SourceConfig
+		}
+		ret = append(ret, s)
+	}
+	return ret
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package configurer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestSources is not parallel because it sets an environment variable
+func TestSources(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"resources": "res", "quiet": true, "spdx": "3.21", "custom": "mine"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KEYWORDS", "true")
+	t.Setenv("CUSTOM", "env")
+
+	flags := NewDefaultFlags()
+	for name, value := range map[string]string{ConfigPathFlag: dir, SpdxFlag: "3.22"} {
+		if err := flags.Set(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, err := InitConfig(flags)
+	if err != nil {
+		t.Fatalf("InitConfig() error = %v", err)
+	}
+	got := make(map[string]Setting)
+	for _, s := range Sources(cfg, flags) {
+		got[s.Key] = s
+	}
+	want := map[string]Setting{
+		"spdx":      {Key: "spdx", Value: "3.22", Source: SourceFlag},
+		"custom":    {Key: "custom", Value: "env", Source: SourceEnv},
+		"keywords":  {Key: "keywords", Value: "true", Source: SourceEnv},
+		"quiet":     {Key: "quiet", Value: true, Source: SourceConfig},
+		"resources": {Key: "resources", Value: filepath.Join(dir, "res"), Source: SourceConfig},
+		"hash":      {Key: "hash", Value: false, Source: SourceDefault},
+	}
+	for key, w := range want {
+		if d := cmp.Diff(w, got[key]); d != "" {
+			t.Errorf("Sources() %v mismatch (-want +got):\n%s", key, d)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package curation reads the licenses concluded by reviewers for files and packages. Like SPDX, the
+// detected licenses (found by the scan) are kept separate from the concluded license (the human decision).
+package curation
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/IBM/license-scanner/packages"
+)
+
+// NoAssertion is the concluded license of the files and packages without a curation
+const NoAssertion = "NOASSERTION"
+
+// Curations is a curation file (YAML or JSON)
+type Curations struct {
+	Files    []File    `json:"files" yaml:"files"`
+	Packages []Package `json:"packages" yaml:"packages"`
+}
+
+// File is the concluded license of the files which match the path. The path is relative to the scanned
+// directory, and is a glob (e.g., "vendor/*.c") or a directory (all the files in it).
+type File struct {
+	Path      string `json:"path" yaml:"path"`
+	Concluded string `json:"concluded" yaml:"concluded"`
+	Comment   string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Package is the concluded license of a package. The package is its ID (e.g., name@version, or
+// group:artifact:version for Maven) or only its name (for all versions). The ecosystem is optional.
+type Package struct {
+	Package   string `json:"package" yaml:"package"`
+	Ecosystem string `json:"ecosystem,omitempty" yaml:"ecosystem,omitempty"`
+	Concluded string `json:"concluded" yaml:"concluded"`
+	Comment   string `json:"comment,omitempty" yaml:"comment,omitempty"`
+}
+
+// Load reads a curation file
+func Load(filePath string) (*Curations, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	c := &Curations{}
+	if err := yaml.Unmarshal(b, c); err != nil {
+		return nil, fmt.Errorf("cannot parse the curations %v: %w", filePath, err)
+	}
+	for i, f := range c.Files {
+		if f.Path == "" || strings.TrimSpace(f.Concluded) == "" {
+			return nil, fmt.Errorf("curation %v of files in %v needs a path and a concluded license", i+1, filePath)
+		}
+		if _, err := path.Match(f.Path, ""); err != nil {
+			return nil, fmt.Errorf("invalid curation path %q in %v: %w", f.Path, filePath, err)
+		}
+	}
+	for i, p := range c.Packages {
+		if p.Package == "" || strings.TrimSpace(p.Concluded) == "" {
+			return nil, fmt.Errorf("curation %v of packages in %v needs a package and a concluded license", i+1, filePath)
+		}
+	}
+	return c, nil
+}
+
+// ForFile returns the curation of the file (relative to the root). When more than one path matches,
+// the last one wins (like the Files paragraphs of a DEP5 file).
+func (c *Curations) ForFile(file string, root string) (File, bool) {
+	if rel, err := filepath.Rel(root, file); err == nil {
+		file = rel
+	}
+	file = filepath.ToSlash(file)
+	var ret File
+	found := false
+	for _, f := range c.Files {
+		pattern := strings.TrimSuffix(strings.TrimPrefix(f.Path, "./"), "/")
+		if ok, _ := path.Match(pattern, file); ok || strings.HasPrefix(file, pattern+"/") {
+			ret, found = f, true
+		}
+	}
+	return ret, found
+}
+
+// ForPackage returns the curation of the package. A curation of the package ID (with the version) takes
+// precedence over a curation of only its name.
+func (c *Curations) ForPackage(p packages.Package) (Package, bool) {
+	var byName *Package
+	for i, curated := range c.Packages {
+		if curated.Ecosystem != "" && curated.Ecosystem != p.Ecosystem {
+			continue
+		}
+		switch curated.Package {
+		case p.ID():
+			return curated, true
+		case p.Name:
+			if byName == nil {
+				byName = &c.Packages[i]
+			}
+		}
+	}
+	if byName != nil {
+		return *byName, true
+	}
+	return Package{}, false
+}
+
+// Concluded returns the concluded license of the file, or NoAssertion when it has no curation
+func (c *Curations) Concluded(file string, root string) string {
+	if f, ok := c.ForFile(file, root); ok {
+		return f.Concluded
+	}
+	return NoAssertion
+}
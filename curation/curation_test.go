@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package curation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/packages"
+)
+
+const curations = `
+files:
+  - path: vendor
+    concluded: MIT
+  - path: vendor/lib/*.c
+    concluded: BSD-3-Clause
+    comment: the GPL text in the headers is a quote
+  - path: ./docs/
+    concluded: CC-BY-4.0
+packages:
+  - package: left-pad
+    concluded: WTFPL
+  - package: left-pad@1.3.0
+    ecosystem: npm
+    concluded: MIT
+  - package: org.example:lib:1.0
+    ecosystem: maven
+    concluded: Apache-2.0
+`
+
+func load(t *testing.T, content string) (*Curations, error) {
+	t.Helper()
+	f := filepath.Join(t.TempDir(), "curations.yaml")
+	if err := os.WriteFile(f, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return Load(f)
+}
+
+func TestForFile(t *testing.T) {
+	t.Parallel()
+	c, err := load(t, curations)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "/repo/vendor/lib/a.c", want: "BSD-3-Clause"},
+		{file: "/repo/vendor/lib/a.h", want: "MIT"},
+		{file: "/repo/docs/guide/index.md", want: "CC-BY-4.0"},
+		{file: "/repo/vendored/a.c", want: NoAssertion},
+		{file: "/repo/main.go", want: NoAssertion},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.file, func(t *testing.T) {
+			t.Parallel()
+			if got := c.Concluded(tt.file, "/repo"); got != tt.want {
+				t.Errorf("Concluded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestForPackage(t *testing.T) {
+	t.Parallel()
+	c, err := load(t, curations)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	tests := []struct {
+		name string
+		pkg  packages.Package
+		want Package
+		ok   bool
+	}{
+		{
+			name: "ID",
+			pkg:  packages.Package{Ecosystem: "npm", Name: "left-pad", Version: "1.3.0"},
+			want: Package{Package: "left-pad@1.3.0", Ecosystem: "npm", Concluded: "MIT"},
+			ok:   true,
+		},
+		{
+			name: "name",
+			pkg:  packages.Package{Ecosystem: "npm", Name: "left-pad", Version: "1.1.0"},
+			want: Package{Package: "left-pad", Concluded: "WTFPL"},
+			ok:   true,
+		},
+		{
+			name: "maven ID",
+			pkg:  packages.Package{Ecosystem: "maven", Name: "org.example:lib", Version: "1.0"},
+			want: Package{Package: "org.example:lib:1.0", Ecosystem: "maven", Concluded: "Apache-2.0"},
+			ok:   true,
+		},
+		{
+			name: "other ecosystem",
+			pkg:  packages.Package{Ecosystem: "pypi", Name: "org.example:lib", Version: "1.0"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := c.ForPackage(tt.pkg)
+			if ok != tt.ok {
+				t.Errorf("ForPackage() ok = %v, want %v", ok, tt.ok)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ForPackage() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "missing concluded", content: "files:\n  - path: vendor\n"},
+		{name: "bad pattern", content: "files:\n  - path: '[a'\n    concluded: MIT\n"},
+		{name: "missing package", content: "packages:\n  - concluded: MIT\n"},
+		{name: "not YAML", content: "files: [\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := load(t, tt.content); err == nil {
+				t.Errorf("Load() expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debugger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// maxDiffEdits limits the word-level diff. Texts with more edits are shown as entirely replaced.
+const maxDiffEdits = 2000
+
+// diffWordRE splits the normalized text into words. Normalization removes the space after commas and
+// semicolons, so the words are also split after them.
+var diffWordRE = regexp.MustCompile(`[^\s,;]+[,;]?|[,;]`)
+
+// DiffOp is the kind of edit in a word-level diff
+type DiffOp int
+
+const (
+	// DiffEqual is text in both the license and the input
+	DiffEqual DiffOp = iota
+	// DiffDelete is license text which is missing from the input
+	DiffDelete
+	// DiffInsert is input text which is not in the license
+	DiffInsert
+)
+
+// WordDiff is a run of words with the same edit
+type WordDiff struct {
+	Op   DiffOp
+	Text string
+}
+
+// Comparison is the word-level diff of the normalized license text and the normalized input
+type Comparison struct {
+	Diffs []WordDiff
+	// Deleted and Inserted are the number of license words missing from the input and extra words in the input
+	Deleted  int
+	Inserted int
+}
+
+// Compare normalizes the license text and the input, and returns the word-level diff
+func Compare(licenseText string, input string) (Comparison, error) {
+	license := normalizer.NormalizationData{OriginalText: licenseText}
+	if err := license.NormalizeText(); err != nil {
+		return Comparison{}, fmt.Errorf("cannot normalize the license text: %w", err)
+	}
+	in := normalizer.NormalizationData{OriginalText: input}
+	if err := in.NormalizeText(); err != nil {
+		return Comparison{}, fmt.Errorf("cannot normalize the input: %w", err)
+	}
+
+	ret := Comparison{Diffs: diffWords(diffWordRE.FindAllString(license.NormalizedText, -1), diffWordRE.FindAllString(in.NormalizedText, -1))}
+	for _, d := range ret.Diffs {
+		switch d.Op {
+		case DiffDelete:
+			ret.Deleted += len(diffWordRE.FindAllString(d.Text, -1))
+		case DiffInsert:
+			ret.Inserted += len(diffWordRE.FindAllString(d.Text, -1))
+		}
+	}
+	return ret, nil
+}
+
+// Exact is true when the normalized texts are the same
+func (c Comparison) Exact() bool {
+	return c.Deleted == 0 && c.Inserted == 0
+}
+
+// String returns the diff like a word diff, with [-deleted-] and {+inserted+} words. Only the given
+// number of words of context are shown around the edits.
+func (c Comparison) String(context int) string {
+	var parts []string
+	for i, d := range c.Diffs {
+		switch d.Op {
+		case DiffDelete:
+			parts = append(parts, "[-"+d.Text+"-]")
+		case DiffInsert:
+			parts = append(parts, "{+"+d.Text+"+}")
+		default:
+			words := diffWordRE.FindAllString(d.Text, -1)
+			first, last := i == 0, i == len(c.Diffs)-1
+			switch {
+			case first && last:
+				parts = append(parts, d.Text)
+			case first && len(words) > context:
+				parts = append(parts, "...", strings.Join(words[len(words)-context:], " "))
+			case last && len(words) > context:
+				parts = append(parts, strings.Join(words[:context], " "), "...")
+			case !first && !last && len(words) > 2*context:
+				parts = append(parts, strings.Join(words[:context], " "), "...", strings.Join(words[len(words)-context:], " "))
+			default:
+				parts = append(parts, d.Text)
+			}
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// diffWords returns the shortest edit script from a to b (Myers' algorithm), as runs of words
+func diffWords(a []string, b []string) []WordDiff {
+	// Trim the common prefix and suffix
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var ops []DiffOp
+	var words []string
+	add := func(op DiffOp, word string) {
+		ops = append(ops, op)
+		words = append(words, word)
+	}
+	for _, w := range a[:prefix] {
+		add(DiffEqual, w)
+	}
+	middleOps, middleWords := myers(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])
+	ops = append(ops, middleOps...)
+	words = append(words, middleWords...)
+	for _, w := range a[len(a)-suffix:] {
+		add(DiffEqual, w)
+	}
+
+	// Join the runs of words with the same edit
+	var ret []WordDiff
+	for i, op := range ops {
+		if n := len(ret); n > 0 && ret[n-1].Op == op {
+			ret[n-1].Text += " " + words[i]
+		} else {
+			ret = append(ret, WordDiff{Op: op, Text: words[i]})
+		}
+	}
+	return ret
+}
+
+// myers returns the edits (in order) to change a into b
+func myers(a []string, b []string) ([]DiffOp, []string) {
+	n, m := len(a), len(b)
+	maxD := n + m
+	if maxD > maxDiffEdits {
+		maxD = maxDiffEdits
+	}
+	offset := maxD + 1
+	v := make([]int, 2*offset+1)
+	// trace[d] has v[-d..d] before step d
+	var trace [][]int
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // insert b[y]
+			} else {
+				x = v[offset+k-1] + 1 // delete a[x]
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d)
+			}
+		}
+	}
+
+	// Too many edits, so replace everything
+	var ops []DiffOp
+	var words []string
+	for _, w := range a {
+		ops = append(ops, DiffDelete)
+		words = append(words, w)
+	}
+	for _, w := range b {
+		ops = append(ops, DiffInsert)
+		words = append(words, w)
+	}
+	return ops, words
+}
+
+func backtrack(a []string, b []string, trace [][]int, d int) ([]DiffOp, []string) {
+	var ops []DiffOp
+	var words []string
+	add := func(op DiffOp, word string) {
+		ops = append(ops, op)
+		words = append(words, word)
+	}
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		prev := trace[d] // v[-d..d] after step d-1
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && prev[d+k-1] < prev[d+k+1]) {
+			prevK = k + 1
+		}
+		prevX := prev[d+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			add(DiffEqual, a[x-1])
+			x--
+			y--
+		}
+		if x == prevX {
+			add(DiffInsert, b[y-1])
+			y--
+		} else {
+			add(DiffDelete, a[x-1])
+			x--
+		}
+	}
+	for x > 0 && y > 0 {
+		add(DiffEqual, a[x-1])
+		x--
+		y--
+	}
+
+	// Reverse into order
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+		words[i], words[j] = words[j], words[i]
+	}
+	return ops, words
+}
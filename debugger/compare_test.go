@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package debugger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_diffWords(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want []WordDiff
+	}{
+		{name: "same", a: "a b c", b: "a b c", want: []WordDiff{{Op: DiffEqual, Text: "a b c"}}},
+		{name: "empty", a: "", b: "", want: nil},
+		{name: "deleted", a: "a b c d", b: "a d", want: []WordDiff{{DiffEqual, "a"}, {DiffDelete, "b c"}, {DiffEqual, "d"}}},
+		{name: "inserted", a: "a d", b: "a b c d", want: []WordDiff{{DiffEqual, "a"}, {DiffInsert, "b c"}, {DiffEqual, "d"}}},
+		{name: "replaced", a: "the software is free", b: "the code is free", want: []WordDiff{{DiffEqual, "the"}, {DiffDelete, "software"}, {DiffInsert, "code"}, {DiffEqual, "is free"}}},
+		{
+			name: "several edits",
+			a:    "a b c d e f g",
+			b:    "x a c d y f g z",
+			want: []WordDiff{{DiffInsert, "x"}, {DiffEqual, "a"}, {DiffDelete, "b"}, {DiffEqual, "c d"}, {DiffDelete, "e"}, {DiffInsert, "y"}, {DiffEqual, "f g"}, {DiffInsert, "z"}},
+		},
+		{name: "all different", a: "a b", b: "c d", want: []WordDiff{{DiffDelete, "a b"}, {DiffInsert, "c d"}}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := diffWords(strings.Fields(tt.a), strings.Fields(tt.b))
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("diffWords() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	license := "Permission is hereby granted, free of charge, to any person obtaining a copy of this software."
+	got, err := Compare(license, "PERMISSION is  hereby granted,\nfree of charge, to any person obtaining a copy of this software.")
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if !got.Exact() {
+		t.Errorf("Compare() is not exact after normalization: %v", got.String(3))
+	}
+
+	got, err = Compare(license, "Permission is hereby granted, for a fee, to any person obtaining a copy of this software.")
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	if got.Deleted != 3 || got.Inserted != 3 {
+		t.Errorf("Compare() deleted %v and inserted %v words, want 3 and 3", got.Deleted, got.Inserted)
+	}
+	if want := "... is hereby granted, [-free of charge,-] {+for a fee,+} to any person ..."; got.String(3) != want {
+		t.Errorf("String() = %q, want %q", got.String(3), want)
+	}
+}
@@ -0,0 +1,196 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package debugger
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// ContextLength is how many bytes of the normalized input to show where a template stopped matching
+const ContextLength = 80
+
+// Explanation explains where each primary pattern (template) of a license matched or failed to match the input
+type Explanation struct {
+	LicenseID string
+	Patterns  []PatternExplanation
+}
+
+// PatternExplanation explains where a primary pattern (template) stopped matching the normalized input
+type PatternExplanation struct {
+	FileName string
+	// Matched is true when the whole pattern matched
+	Matched bool
+	// MissingStaticBlock is the first precheck static block which is not in the input (the pattern is skipped)
+	MissingStaticBlock string
+	// Segments is the number of segments (words and <<tags>>) in the pattern regex, and MatchedSegments
+	// is how many of the first segments matched
+	Segments        int
+	MatchedSegments int
+	// Found is the input which matched the first MatchedSegments segments
+	Found string
+	// Expected is the template text of the segment which did not match
+	Expected string
+	// Context is the normalized input where the Expected segment did not match
+	Context string
+	// Offset is the position of the Context in the original input (-1 if unknown)
+	Offset int
+}
+
+// Explain runs the pipeline of one license against the normalized input, and reports for each of its
+// primary patterns which precheck static block was not found or which segment of the regex stopped matching
+func Explain(ll *licenses.LicenseLibrary, id string, nd normalizer.NormalizationData) (Explanation, error) {
+	lic, ok := ll.LicenseMap[id]
+	if !ok {
+		return Explanation{}, fmt.Errorf("license %v is not in the license library", id)
+	}
+	ret := Explanation{LicenseID: id}
+	patterns := append([]*licenses.PrimaryPatterns(nil), lic.PrimaryPatterns...)
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].FileName < patterns[j].FileName })
+	for _, pattern := range patterns {
+		pe, err := explainPattern(ll, pattern, nd)
+		if err != nil {
+			return ret, err
+		}
+		ret.Patterns = append(ret.Patterns, pe)
+	}
+	return ret, nil
+}
+
+func explainPattern(ll *licenses.LicenseLibrary, pattern *licenses.PrimaryPatterns, nd normalizer.NormalizationData) (PatternExplanation, error) {
+	pe := PatternExplanation{FileName: pattern.FileName, Offset: -1}
+
+	if preChecks := ll.PrimaryPatternPreCheckMap[licenses.LicensePatternKey{FilePath: pattern.FileName}]; preChecks != nil && !ll.PreCheckSettings.Passed(preChecks.StaticBlocks, nd.NormalizedText) {
+		for _, block := range preChecks.StaticBlocks {
+			if !strings.Contains(nd.NormalizedText, block) {
+				pe.MissingStaticBlock = block
+				explainStaticBlock(&pe, block, nd)
+				return pe, nil
+			}
+		}
+	}
+
+	template := normalizer.NewNormalizationData(pattern.Text, true)
+	if err := template.NormalizeText(); err != nil {
+		return pe, err
+	}
+	segments := licenses.RegexSegments(template.NormalizedText)
+	pe.Segments = len(segments)
+
+	// Binary search for the most segments that match (any prefix of a matching prefix also matches)
+	var found []int
+	lo, hi := 0, len(segments)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		re, err := regexp.Compile(licenses.RegexPrefix(segments, mid))
+		if err != nil {
+			return pe, fmt.Errorf("cannot compile the first %v segments of %v: %w", mid, pattern.FileName, err)
+		}
+		if loc := re.FindStringIndex(nd.NormalizedText); loc != nil {
+			lo, found = mid, loc
+		} else {
+			hi = mid - 1
+		}
+	}
+	pe.MatchedSegments = lo
+	if lo == len(segments) {
+		pe.Matched = true
+		return pe, nil
+	}
+
+	pe.Expected = segments[lo].Template
+	end := 0
+	if found != nil {
+		pe.Found = nd.NormalizedText[found[0]:found[1]]
+		end = found[1]
+	}
+	setContext(&pe, end, nd)
+	return pe, nil
+}
+
+// explainStaticBlock finds the longest start of the missing block (by words) which is in the input
+func explainStaticBlock(pe *PatternExplanation, block string, nd normalizer.NormalizationData) {
+	words := strings.SplitAfter(block, " ")
+	lo, hi := 0, len(words)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if strings.Contains(nd.NormalizedText, strings.Join(words[:mid], "")) {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 {
+		pe.Expected = block
+		return
+	}
+	pe.Found = strings.Join(words[:lo], "")
+	pe.Expected = strings.Join(words[lo:], "")
+	setContext(pe, strings.Index(nd.NormalizedText, pe.Found)+len(pe.Found), nd)
+}
+
+// setContext sets the normalized input from the end of what was found, and its position in the original input
+func setContext(pe *PatternExplanation, end int, nd normalizer.NormalizationData) {
+	contextEnd := end + ContextLength
+	if contextEnd > len(nd.NormalizedText) {
+		contextEnd = len(nd.NormalizedText)
+	}
+	pe.Context = nd.NormalizedText[end:contextEnd]
+	for i := end; i < len(nd.IndexMap); i++ {
+		if nd.IndexMap[i] >= 0 {
+			pe.Offset = nd.IndexMap[i]
+			break
+		}
+	}
+}
+
+// String returns a report of the explanation, with the line of the original input where each pattern stopped matching
+func (e Explanation) String(originalText string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Explain %v:\n", e.LicenseID)
+	if len(e.Patterns) == 0 {
+		sb.WriteString("\tno license patterns (only aliases and URLs are matched)\n")
+	}
+	for _, pe := range e.Patterns {
+		fmt.Fprintf(&sb, "\tpattern: %v\n", pe.FileName)
+		switch {
+		case pe.Matched:
+			fmt.Fprintf(&sb, "\t\tMATCHED all %v segments\n", pe.Segments)
+			continue
+		case pe.MissingStaticBlock != "":
+			fmt.Fprintf(&sb, "\t\tprecheck static block not found: %q\n", pe.MissingStaticBlock)
+		default:
+			fmt.Fprintf(&sb, "\t\tstopped matching after %v of %v segments\n", pe.MatchedSegments, pe.Segments)
+		}
+		if pe.Found != "" {
+			fmt.Fprintf(&sb, "\t\tfound:    ...%q\n", tail(pe.Found, ContextLength))
+		}
+		fmt.Fprintf(&sb, "\t\texpected: %q\n", head(pe.Expected, ContextLength))
+		fmt.Fprintf(&sb, "\t\tbut got:  %q...\n", pe.Context)
+		if pe.Offset >= 0 && pe.Offset < len(originalText) {
+			loc := identifier.Locate(originalText, identifier.Match{Begins: pe.Offset, Ends: pe.Offset})
+			fmt.Fprintf(&sb, "\t\tat line %v, column %v: %v\n", loc.StartLine, loc.StartColumn, loc.Excerpt)
+		}
+	}
+	return sb.String()
+}
+
+func head(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[len(s)-n:]
+}
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package debugger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func TestExplain(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	lic := licenses.License{SPDXLicenseID: "Test"}
+	template := "The <<var;name=\"holder\";original=\"owner\";match=.+>> grants permission to use <<beginOptional>>and copy<<endOptional>> this software free of charge."
+	if err := licenses.AddPrimaryPatternAndSource(template, "license_Test.txt", &lic); err != nil {
+		t.Fatal(err)
+	}
+	ll.LicenseMap["Test"] = lic
+	ll.PrimaryPatternPreCheckMap[licenses.LicensePatternKey{FilePath: "license_Test.txt"}] = &licenses.LicensePreChecks{
+		StaticBlocks: []string{"grants permission to use"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  PatternExplanation
+	}{
+		{
+			name:  "matched",
+			input: "The Acme Corp. grants permission to use this software free of charge.",
+			want:  PatternExplanation{Matched: true},
+		},
+		{
+			name:  "missing static block",
+			input: "The Acme Corp. grants permission to copy this software free of charge.",
+			want: PatternExplanation{
+				MissingStaticBlock: "grants permission to use",
+				Found:              "grants permission to ",
+				Expected:           "use",
+				Context:            "copy this software free of charge.",
+				Offset:             36,
+			},
+		},
+		{
+			name:  "regex segment",
+			input: "The Acme Corp. grants permission to use this software for a fee.",
+			want: PatternExplanation{
+				Found:    "the acme corp. grants permission to use this software ",
+				Expected: "free ",
+				Context:  "for a fee.",
+				Offset:   54,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			nd := normalizer.NormalizationData{OriginalText: tt.input}
+			if err := nd.NormalizeText(); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Explain(ll, "Test", nd)
+			if err != nil {
+				t.Fatalf("Explain() error = %v", err)
+			}
+			if len(got.Patterns) != 1 {
+				t.Fatalf("Explain() got %v patterns, want 1", len(got.Patterns))
+			}
+			pe := got.Patterns[0]
+			if !pe.Matched && pe.MissingStaticBlock == "" && (pe.MatchedSegments == 0 || pe.MatchedSegments >= pe.Segments) {
+				t.Errorf("Explain() matched %v of %v segments", pe.MatchedSegments, pe.Segments)
+			}
+			if tt.want.Offset == 0 {
+				tt.want.Offset = -1
+			}
+			tt.want.FileName = "license_Test.txt"
+			if d := cmp.Diff(tt.want, pe, cmpopts.IgnoreFields(PatternExplanation{}, "Segments", "MatchedSegments")); d != "" {
+				t.Errorf("Explain() mismatch (-want +got):\n%s", d)
+			}
+			if !pe.Matched && !strings.Contains(got.String(tt.input), "at line 1") {
+				t.Errorf("String() has no location:\n%v", got.String(tt.input))
+			}
+		})
+	}
+
+	if _, err := Explain(ll, "Missing", normalizer.NormalizationData{}); err == nil {
+		t.Error("Explain() expected an error for a license which is not in the library")
+	}
+}
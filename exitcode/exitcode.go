@@ -0,0 +1,157 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package exitcode maps the conditions of a scan (e.g., a denied license or no license found) to the exit codes
+// of a policy, so different CI jobs can react differently to the same scan
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/report"
+)
+
+// The conditions of a scan (from the highest precedence to the lowest)
+const (
+	// Error is a scan which failed
+	Error = "error"
+	// Denied is a scan which detected a license of the high risk level
+	Denied = "denied"
+	// Unknown is a scan which found license text that matched no license
+	Unknown = "unknown"
+	// Timeout is a scan in which a file or template timeout stopped the matching
+	Timeout = "timeout"
+	// None is a scan which found no license
+	None = "none"
+)
+
+// Conditions are the conditions of a scan, from the highest precedence to the lowest
+var Conditions = []string{Error, Denied, Unknown, Timeout, None}
+
+// DefaultErrorCode is the exit code of a failed scan when the policy has no error code
+const DefaultErrorCode = 1
+
+// Interrupted is a scan which was stopped by a signal, with the InterruptedCode (whatever the policy)
+const Interrupted = "interrupted"
+
+// InterruptedCode is the exit code of an interrupted scan (128 + SIGINT, as in the shells)
+const InterruptedCode = 130
+
+// maxCode is the largest exit code (the larger codes are used by the shells)
+const maxCode = 125
+
+// Policy is the exit code of each condition (0, or no code, to not fail the scan on the condition)
+type Policy map[string]int
+
+// Parse returns the policy of the exit codes by condition name (e.g., {"denied": "3"}), with the
+// DefaultErrorCode when it has no error code
+func Parse(codes map[string]string) (Policy, error) {
+	p := Policy{Error: DefaultErrorCode}
+	for condition, code := range codes {
+		if !known(condition) {
+			return nil, fmt.Errorf("unknown exit code condition %q (expected %v)", condition, strings.Join(Conditions, ", "))
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(code))
+		if err != nil || n < 0 || n > maxCode {
+			return nil, fmt.Errorf("invalid exit code %q of %v (expected 0 to %v)", code, condition, maxCode)
+		}
+		p[condition] = n
+	}
+	if p[Error] == 0 {
+		return nil, fmt.Errorf("invalid exit code 0 of %v (a failed scan cannot succeed)", Error)
+	}
+	return p, nil
+}
+
+func known(condition string) bool {
+	for _, c := range Conditions {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// Met returns the conditions met by the results of a scan (in the order of the Conditions), with the licenses
+// of the high risk level of the risk model in the context as denied
+func Met(results []identifier.IdentifierResults, riskModel *report.RiskModel, context report.RiskContext) []string {
+	var met []string
+	if riskModel.Assess(results, "", context).Counts[report.RiskHigh] > 0 {
+		met = append(met, Denied)
+	}
+	timedOut := false
+	for _, r := range results {
+		if identifier.IsUnknownLicense(r) && !contains(met, Unknown) {
+			met = append(met, Unknown)
+		}
+		timedOut = timedOut || r.TimedOut || len(r.TimedOutTemplates) > 0
+	}
+	if timedOut {
+		met = append(met, Timeout)
+	}
+	if identifier.ScanStatus(results) != identifier.Licensed {
+		met = append(met, None)
+	}
+	return met
+}
+
+func contains(conditions []string, condition string) bool {
+	for _, c := range conditions {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns an *ExitError with the exit code of the met condition of the highest precedence which has a
+// code in the policy (nil when the policy fails on none of them)
+func (p Policy) Check(met []string) error {
+	for _, condition := range Conditions {
+		if code := p[condition]; code != 0 && contains(met, condition) {
+			return &ExitError{Code: code, Condition: condition, Err: fmt.Errorf("the scan met the %v condition (exit code %v)", condition, code)}
+		}
+	}
+	return nil
+}
+
+// Wrap returns the error of a failed scan with the error exit code of the policy (nil without an error, and
+// an *ExitError as is)
+func (p Policy) Wrap(err error) error {
+	var exitErr *ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		return err
+	}
+	return &ExitError{Code: p[Error], Condition: Error, Err: err}
+}
+
+// ExitError is an error with the exit code of the condition which caused it
+type ExitError struct {
+	Code      int
+	Condition string
+	Err       error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Code returns the exit code of an error: the code of an *ExitError, or else DefaultErrorCode (0 without an error)
+func Code(err error) int {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		return DefaultErrorCode
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package exitcode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/report"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		codes   map[string]string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "default", want: Policy{Error: DefaultErrorCode}},
+		{name: "codes", codes: map[string]string{Denied: "3", None: " 4", Error: "2"}, want: Policy{Denied: 3, None: 4, Error: 2}},
+		{name: "unknown condition", codes: map[string]string{"warning": "3"}, wantErr: true},
+		{name: "not a number", codes: map[string]string{Denied: "three"}, wantErr: true},
+		{name: "out of range", codes: map[string]string{Denied: "126"}, wantErr: true},
+		{name: "error succeeds", codes: map[string]string{Error: "0"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse(tt.codes)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestMet(t *testing.T) {
+	t.Parallel()
+	mit := identifier.IdentifierResults{
+		Matches:         map[string][]identifier.Match{"MIT": {{}}},
+		Classifications: map[string]licenses.Classification{"MIT": {Category: licenses.Permissive}},
+	}
+	gpl := identifier.IdentifierResults{
+		Matches:         map[string][]identifier.Match{"GPL-3.0-only": {{}}},
+		Classifications: map[string]licenses.Classification{"GPL-3.0-only": {Category: licenses.StrongCopyleft}},
+		TimedOut:        true,
+	}
+	dual := identifier.IdentifierResults{
+		Matches:  map[string][]identifier.Match{"Acme-Dual": {{}}},
+		Mappings: map[string]string{"Acme-Dual": "MIT OR GPL-3.0-only"},
+		Classifications: map[string]licenses.Classification{
+			"Acme-Dual":    {},
+			"GPL-3.0-only": {Category: licenses.StrongCopyleft},
+			"MIT":          {Category: licenses.Permissive},
+		},
+	}
+	copyleft := identifier.IdentifierResults{
+		Matches:  map[string][]identifier.Match{"Acme-Copyleft": {{}}},
+		Mappings: map[string]string{"Acme-Copyleft": "GPL-2.0-only OR GPL-3.0-only"},
+		Classifications: map[string]licenses.Classification{
+			"Acme-Copyleft": {},
+			"GPL-2.0-only":  {Category: licenses.StrongCopyleft},
+			"GPL-3.0-only":  {Category: licenses.StrongCopyleft},
+		},
+	}
+	tests := []struct {
+		name    string
+		results []identifier.IdentifierResults
+		context report.RiskContext
+		want    []string
+	}{
+		{name: "permissive", results: []identifier.IdentifierResults{mit}, context: report.DefaultRiskContext},
+		{name: "denied and timed out", results: []identifier.IdentifierResults{mit, gpl}, context: report.DefaultRiskContext, want: []string{Denied, Timeout}},
+		{name: "not denied with an allowed choice", results: []identifier.IdentifierResults{dual}, context: report.DefaultRiskContext},
+		{name: "denied without an allowed choice", results: []identifier.IdentifierResults{copyleft}, context: report.DefaultRiskContext, want: []string{Denied}},
+		{name: "not denied internally", results: []identifier.IdentifierResults{gpl}, context: report.RiskContext{Linking: report.DynamicLinking, Distribution: report.InternalDistribution}, want: []string{Timeout}},
+		{name: "none", results: []identifier.IdentifierResults{{}}, context: report.DefaultRiskContext, want: []string{None}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, Met(tt.results, &report.DefaultRiskModel, tt.context)); d != "" {
+				t.Errorf("Met() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+	p := Policy{Error: 2, Denied: 3, None: 4}
+	if err := p.Check([]string{Timeout}); err != nil {
+		t.Errorf("Check() of a condition without a code error = %v, want nil", err)
+	}
+	// The denied condition has precedence over none
+	err := p.Check([]string{None, Denied})
+	if got := Code(err); got != 3 {
+		t.Errorf("Check() exit code = %v (error %v), want 3", got, err)
+	}
+}
+
+func TestPolicy_Wrap(t *testing.T) {
+	t.Parallel()
+	p := Policy{Error: 2, Denied: 3}
+	if got := Code(p.Wrap(nil)); got != 0 {
+		t.Errorf("Wrap(nil) exit code = %v, want 0", got)
+	}
+	failed := errors.New("the scan failed")
+	err := p.Wrap(failed)
+	if got := Code(err); got != 2 || !errors.Is(err, failed) {
+		t.Errorf("Wrap() = %v with the exit code %v, want the error with the exit code 2", err, got)
+	}
+	if got := Code(p.Wrap(p.Check([]string{Denied}))); got != 3 {
+		t.Errorf("Wrap() of a met condition exit code = %v, want 3", got)
+	}
+	if got := Code(failed); got != DefaultErrorCode {
+		t.Errorf("Code() of another error = %v, want %v", got, DefaultErrorCode)
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package external
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+// Reconciliation compares the licenses found by another scanner and by license-scanner in one file
+type Reconciliation struct {
+	File string
+	// Other are the license IDs resolved from the other scanner's expressions, and Detected are the license IDs found by license-scanner
+	Other    []string
+	Detected []string
+	// Agreed are found by both, OnlyOther and OnlyDetected are only found by one of them
+	Agreed       []string
+	OnlyOther    []string
+	OnlyDetected []string
+	// Unresolved are the other scanner's license IDs which are not in the license library (e.g., LicenseRef-scancode-*)
+	Unresolved []string
+	// NotScanned is true when the other scanner's file is not in the license-scanner results
+	NotScanned bool
+}
+
+// Agrees is true when both found the same licenses
+func (r Reconciliation) Agrees() bool {
+	return !r.NotScanned && len(r.OnlyOther) == 0 && len(r.OnlyDetected) == 0 && len(r.Unresolved) == 0
+}
+
+// Reconcile merges the findings of another scanner with the results of a directory scan of the same
+// root, and returns the files where either found a license, sorted by path. The paths of the findings
+// may include the name of the root directory (e.g., the ScanCode default) or not (ScanCode --strip-root).
+func Reconcile(findings *Findings, results []identifier.IdentifierResults, root string, ll *licenses.LicenseLibrary) []Reconciliation {
+	byPath := make(map[string]identifier.IdentifierResults)
+	for _, r := range results {
+		rel, err := filepath.Rel(root, r.File)
+		if err != nil {
+			rel = r.File
+		}
+		byPath[filepath.ToSlash(rel)] = r
+	}
+
+	seen := make(map[string]bool)
+	var ret []Reconciliation
+	for otherPath, expressions := range findings.Files {
+		rel := otherPath
+		r, scanned := byPath[rel]
+		if !scanned {
+			if i := strings.Index(otherPath, "/"); i >= 0 {
+				rel = otherPath[i+1:]
+				r, scanned = byPath[rel]
+			}
+		}
+		if scanned {
+			seen[rel] = true
+		}
+		rec := Reconciliation{File: rel, NotScanned: !scanned}
+		if !scanned {
+			rec.File = otherPath
+		}
+		for _, expression := range expressions {
+			ids := manifest.ResolveIDs(expression, ll)
+			if len(ids) == 0 {
+				rec.Unresolved = append(rec.Unresolved, expression)
+			}
+			for _, id := range ids {
+				if !slices.Contains(rec.Other, id) {
+					rec.Other = append(rec.Other, id)
+				}
+			}
+		}
+		rec.Detected = detectedIDs(r)
+		if len(rec.Other) == 0 && len(rec.Detected) == 0 && len(rec.Unresolved) == 0 {
+			continue
+		}
+		ret = append(ret, compareIDs(rec))
+	}
+
+	// Files where only license-scanner found licenses
+	for rel, r := range byPath {
+		if seen[rel] || len(r.Matches) == 0 {
+			continue
+		}
+		ret = append(ret, compareIDs(Reconciliation{File: rel, Detected: detectedIDs(r)}))
+	}
+
+	sort.Slice(ret, func(i, j int) bool { return ret[i].File < ret[j].File })
+	return ret
+}
+
+// detectedIDs returns the sorted license IDs matched in the results
+func detectedIDs(r identifier.IdentifierResults) []string {
+	var ids []string
+	for id := range r.Matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// compareIDs sets the agreed and the conflicting license IDs
+func compareIDs(rec Reconciliation) Reconciliation {
+	sort.Strings(rec.Other)
+	for _, id := range rec.Other {
+		if slices.Contains(rec.Detected, id) {
+			rec.Agreed = append(rec.Agreed, id)
+		} else {
+			rec.OnlyOther = append(rec.OnlyOther, id)
+		}
+	}
+	for _, id := range rec.Detected {
+		if !slices.Contains(rec.Other, id) {
+			rec.OnlyDetected = append(rec.OnlyDetected, id)
+		}
+	}
+	return rec
+}
@@ -0,0 +1,58 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package external
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	sc, err := ParseScanCode(strings.NewReader(scanCodeJSON))
+	if err != nil {
+		t.Fatalf("ParseScanCode() error = %v", err)
+	}
+	match := []identifier.Match{{Begins: 0, Ends: 10}}
+	results := []identifier.IdentifierResults{
+		{File: "/tmp/proj/LICENSE", Matches: map[string][]identifier.Match{"MIT": match}},
+		{File: "/tmp/proj/src/main.c", Matches: map[string][]identifier.Match{"GPL-2.0-or-later": match}},
+		{File: "/tmp/proj/src/util.c", Matches: map[string][]identifier.Match{"MIT": match}},
+		{File: "/tmp/proj/README"},
+		{File: "/tmp/proj/NOTICE", Matches: map[string][]identifier.Match{"Apache-2.0": match}},
+	}
+
+	want := []Reconciliation{
+		{File: "LICENSE", Other: []string{"MIT"}, Detected: []string{"MIT"}, Agreed: []string{"MIT"}},
+		{File: "NOTICE", Detected: []string{"Apache-2.0"}, OnlyDetected: []string{"Apache-2.0"}},
+		{File: "proj/build/gen.c", Other: []string{"BSD-3-Clause"}, OnlyOther: []string{"BSD-3-Clause"}, NotScanned: true},
+		{
+			File: "src/main.c", Other: []string{"GPL-2.0-or-later"}, Detected: []string{"GPL-2.0-or-later"},
+			Agreed: []string{"GPL-2.0-or-later"}, Unresolved: []string{"LicenseRef-scancode-acme-eula"},
+		},
+		{File: "src/util.c", Other: []string{"Apache-2.0", "MIT"}, Detected: []string{"MIT"}, Agreed: []string{"MIT"}, OnlyOther: []string{"Apache-2.0"}},
+	}
+	got := Reconcile(sc, results, "/tmp/proj", ll)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Reconcile() mismatch (-want +got):\n%s", d)
+	}
+	for _, r := range got {
+		if r.Agrees() != (r.File == "LICENSE") {
+			t.Errorf("%v Agrees() = %v", r.File, r.Agrees())
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package external
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+// Findings are the licenses found by another scanner (e.g., ScanCode toolkit)
+type Findings struct {
+	// Tool is the name of the scanner
+	Tool string
+	// Files has the SPDX license expressions found in each file, by the path in the scanner output
+	Files map[string][]string
+}
+
+// scanCodeOutput is the part of the ScanCode JSON output with the license findings. Newer versions
+// (32+) have license_detections with SPDX expressions, and older versions have a licenses list.
+type scanCodeOutput struct {
+	Files []struct {
+		Path                          string `json:"path"`
+		Type                          string `json:"type"`
+		DetectedLicenseExpressionSPDX string `json:"detected_license_expression_spdx"`
+		LicenseDetections             []struct {
+			LicenseExpressionSPDX string `json:"license_expression_spdx"`
+		} `json:"license_detections"`
+		Licenses []struct {
+			Key            string `json:"key"`
+			SPDXLicenseKey string `json:"spdx_license_key"`
+		} `json:"licenses"`
+	} `json:"files"`
+}
+
+// ParseScanCodeFile reads a ScanCode JSON output file
+func ParseScanCodeFile(filePath string) (*Findings, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ret, err := ParseScanCode(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the ScanCode output %v: %w", filePath, err)
+	}
+	return ret, nil
+}
+
+// ParseScanCode reads the license findings of each file from a ScanCode JSON output
+func ParseScanCode(r io.Reader) (*Findings, error) {
+	var out scanCodeOutput
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return nil, err
+	}
+	ret := &Findings{Tool: "ScanCode", Files: make(map[string][]string)}
+	for _, f := range out.Files {
+		if f.Type != "" && f.Type != "file" {
+			continue
+		}
+		var expressions []string
+		add := func(expression string) {
+			if expression = strings.TrimSpace(expression); expression != "" && !slices.Contains(expressions, expression) {
+				expressions = append(expressions, expression)
+			}
+		}
+		for _, d := range f.LicenseDetections {
+			add(d.LicenseExpressionSPDX)
+		}
+		if len(expressions) == 0 {
+			add(f.DetectedLicenseExpressionSPDX)
+		}
+		for _, l := range f.Licenses {
+			if l.SPDXLicenseKey != "" {
+				add(l.SPDXLicenseKey)
+			} else {
+				add("LicenseRef-scancode-" + l.Key)
+			}
+		}
+		ret.Files[filepath.ToSlash(f.Path)] = expressions
+	}
+	return ret, nil
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package external
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// scanCodeJSON has a file in the newer (license_detections) and the older (licenses) output formats, and a directory
+const scanCodeJSON = `{
+  "headers": [{"tool_name": "scancode-toolkit"}],
+  "files": [
+    {"path": "proj", "type": "directory", "license_detections": []},
+    {"path": "proj/LICENSE", "type": "file", "detected_license_expression_spdx": "MIT",
+     "license_detections": [{"license_expression": "mit", "license_expression_spdx": "MIT"}]},
+    {"path": "proj/src/main.c", "type": "file",
+     "licenses": [{"key": "gpl-2.0-plus", "spdx_license_key": "GPL-2.0-or-later"}, {"key": "acme-eula"}]},
+    {"path": "proj/src/util.c", "type": "file", "license_detections": [{"license_expression_spdx": "Apache-2.0 OR MIT"}]},
+    {"path": "proj/build/gen.c", "type": "file", "license_detections": [{"license_expression_spdx": "BSD-3-Clause"}]},
+    {"path": "proj/README", "type": "file", "license_detections": []}
+  ]
+}`
+
+func TestParseScanCode(t *testing.T) {
+	t.Parallel()
+	got, err := ParseScanCode(strings.NewReader(scanCodeJSON))
+	if err != nil {
+		t.Fatalf("ParseScanCode() error = %v", err)
+	}
+	want := &Findings{Tool: "ScanCode", Files: map[string][]string{
+		"proj/LICENSE":     {"MIT"},
+		"proj/src/main.c":  {"GPL-2.0-or-later", "LicenseRef-scancode-acme-eula"},
+		"proj/src/util.c":  {"Apache-2.0 OR MIT"},
+		"proj/build/gen.c": {"BSD-3-Clause"},
+		"proj/README":      nil,
+	}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("ParseScanCode() mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := ParseScanCode(strings.NewReader("not json")); err == nil {
+		t.Error("ParseScanCode() expected an error")
+	}
+}
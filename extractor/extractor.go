@@ -0,0 +1,312 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package extractor
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxEntrySize matches the largest file the identifier will scan
+	MaxEntrySize = 1000000
+	// Separator joins an archive path and the path of an entry inside of it
+	Separator = "!/"
+)
+
+var (
+	zipSuffixes = []string{".zip", ".whl", ".jar", ".war", ".ear", ".aar", ".nupkg"}
+	tarSuffixes = []string{".tar", ".tar.gz", ".tgz", ".gem"}
+
+	// DefaultLimits are used by Walk and WalkTar, and by the CLI unless overridden
+	DefaultLimits = Limits{
+		MaxDepth:            3,
+		MaxExtractedSize:    1 << 30,
+		MaxCompressionRatio: 200,
+	}
+
+	// ErrLimitExceeded is wrapped by the errors returned when an archive exceeds the Limits
+	ErrLimitExceeded = errors.New("archive limit exceeded")
+	errFound         = errors.New("found")
+)
+
+// Limits protect a scan from archive bombs and runaway nesting
+type Limits struct {
+	// MaxDepth is how many archives deep an archive entry may be opened (0 does not open nested archives)
+	MaxDepth int
+	// MaxExtractedSize is the total number of bytes which may be read (decompressed) from archives. An archive which
+	// is read again (e.g., to identify a package and then to extract its nested archives) is only charged once.
+	MaxExtractedSize int64
+	// MaxCompressionRatio is the largest uncompressed/compressed size ratio allowed for a zip entry
+	MaxCompressionRatio int64
+}
+
+// WalkFunc is called for each regular file in an archive. Read the entry from r, if needed.
+type WalkFunc func(name string, size int64, r io.Reader) error
+
+// Extractor reads archives (and archives in archives) within one extraction budget
+type Extractor struct {
+	limits Limits
+
+	mu        sync.Mutex
+	extracted int64
+	// charged is how many bytes of each tar stream (by archive path) or zip entry (by entry path) were charged, so
+	// that only the bytes beyond them are charged when it is read again
+	charged map[string]int64
+	// nested holds the archive entries which were extracted to be walked as archives
+	nested map[string][]byte
+}
+
+// New returns an Extractor which enforces the limits across all of its walks
+func New(limits Limits) *Extractor {
+	return &Extractor{limits: limits, charged: make(map[string]int64), nested: make(map[string][]byte)}
+}
+
+// IsArchive returns true if the file name has a supported archive extension
+func IsArchive(name string) bool {
+	return isZip(name) || isTar(name)
+}
+
+// EntryPath returns the display path for an entry inside an archive (archive!/entry)
+func EntryPath(archivePath string, name string) string {
+	return archivePath + Separator + name
+}
+
+// Depth returns how many archives deep the path is (0 for a file on disk)
+func Depth(archivePath string) int {
+	return strings.Count(archivePath, Separator)
+}
+
+// Walk calls fn for each regular file in a zip or tar (optionally gzipped) archive using the DefaultLimits
+func Walk(archivePath string, fn WalkFunc) error {
+	return New(DefaultLimits).Walk(archivePath, fn)
+}
+
+// WalkTar calls fn for each regular file read from a tar stream using the DefaultLimits
+func WalkTar(r io.Reader, gzipped bool, fn WalkFunc) error {
+	return New(DefaultLimits).WalkTar(r, gzipped, fn)
+}
+
+// Walk calls fn for each regular file in a zip or tar (optionally gzipped) archive.
+// The archive may be a file on disk or an entry in another archive (outer.tar.gz!/lib/inner.jar).
+func (e *Extractor) Walk(archivePath string, fn WalkFunc) error {
+	if !IsArchive(archivePath) {
+		return fmt.Errorf("unsupported archive type: %v", archivePath)
+	}
+	if Depth(archivePath) > e.limits.MaxDepth {
+		return fmt.Errorf("%w: %v is nested more than %v deep", ErrLimitExceeded, archivePath, e.limits.MaxDepth)
+	}
+
+	e.mu.Lock()
+	b, ok := e.nested[archivePath]
+	e.mu.Unlock()
+	if ok {
+		return e.walk(archivePath, bytes.NewReader(b), int64(len(b)), fn)
+	}
+
+	i := strings.LastIndex(archivePath, Separator)
+	if i < 0 {
+		f, err := os.Open(archivePath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return e.walk(archivePath, f, fi.Size(), fn)
+	}
+
+	// Read the nested archive out of its parent, then walk it
+	parent, name := archivePath[:i], archivePath[i+len(Separator):]
+	err := e.Walk(parent, func(entry string, size int64, r io.Reader) error {
+		if entry != name {
+			return nil
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if err := e.walk(archivePath, bytes.NewReader(b), int64(len(b)), fn); err != nil {
+			return err
+		}
+		return errFound
+	})
+	if errors.Is(err, errFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%v not found", archivePath)
+}
+
+// Extract reads the archive entries selected by match and keeps them in memory, so the nested
+// archives can be walked (by the returned paths) without reading the outer archive again.
+// Nothing is extracted beyond the MaxDepth. Call Release when done with a nested archive.
+func (e *Extractor) Extract(archivePath string, match func(name string) bool) ([]string, error) {
+	if Depth(archivePath) >= e.limits.MaxDepth {
+		return nil, nil
+	}
+	var ret []string
+	err := e.Walk(archivePath, func(name string, size int64, r io.Reader) error {
+		if !IsArchive(name) || !match(name) {
+			return nil
+		}
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		nestedPath := EntryPath(archivePath, name)
+		e.mu.Lock()
+		e.nested[nestedPath] = b
+		e.mu.Unlock()
+		ret = append(ret, nestedPath)
+		return nil
+	})
+	return ret, err
+}
+
+// Release frees a nested archive kept in memory by Extract
+func (e *Extractor) Release(archivePath string) {
+	e.mu.Lock()
+	delete(e.nested, archivePath)
+	e.mu.Unlock()
+}
+
+// ReadEntry reads an entry up to MaxEntrySize
+func ReadEntry(name string, r io.Reader) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, MaxEntrySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > MaxEntrySize {
+		return nil, fmt.Errorf("archive entry %v too large (> %v)", name, MaxEntrySize)
+	}
+	return b, nil
+}
+
+func (e *Extractor) walk(archivePath string, ra io.ReaderAt, size int64, fn WalkFunc) error {
+	if isZip(archivePath) {
+		return e.walkZip(archivePath, ra, size, fn)
+	}
+	return e.walkTar(archivePath, io.NewSectionReader(ra, 0, size), strings.HasSuffix(strings.ToLower(archivePath), "gz"), fn)
+}
+
+func (e *Extractor) walkZip(archivePath string, ra io.ReaderAt, size int64, fn WalkFunc) error {
+	zr, err := zip.NewReader(ra, size)
+	if err != nil {
+		return err
+	}
+	for _, zf := range zr.File {
+		if zf.FileInfo().IsDir() || !zf.Mode().IsRegular() {
+			continue
+		}
+		// A zip bomb declares a huge uncompressed size for a tiny compressed entry
+		if zf.CompressedSize64 > 0 && zf.UncompressedSize64/zf.CompressedSize64 > uint64(e.limits.MaxCompressionRatio) {
+			return fmt.Errorf("%w: %v compression ratio is more than %v", ErrLimitExceeded, EntryPath(archivePath, zf.Name), e.limits.MaxCompressionRatio)
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(zf.Name, int64(zf.UncompressedSize64), &budgetReader{e: e, r: rc, key: EntryPath(archivePath, zf.Name)})
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WalkTar calls fn for each regular file read from a tar stream
+func (e *Extractor) WalkTar(r io.Reader, gzipped bool, fn WalkFunc) error {
+	return e.walkTar("", r, gzipped, fn)
+}
+
+// walkTar walks the tar stream of the archive path ("" for a stream which is not read again)
+func (e *Extractor) walkTar(archivePath string, r io.Reader, gzipped bool, fn WalkFunc) error {
+	if gzipped {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	// The decompressed stream is charged to the budget (including the entries which are skipped)
+	tr := tar.NewReader(&budgetReader{e: e, r: r, key: archivePath})
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(hdr.Name, hdr.Size, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// budgetReader charges the bytes read to the Extractor's MaxExtractedSize, except for the bytes of the stream
+// (by its key) which were charged by an earlier read
+type budgetReader struct {
+	e *Extractor
+	r io.Reader
+	// key is the path of the tar stream or zip entry ("" to charge every byte)
+	key string
+	// read is how many bytes were read from the stream
+	read int64
+}
+
+func (b *budgetReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	b.read += int64(n)
+	b.e.mu.Lock()
+	charge := int64(n)
+	if b.key != "" {
+		charge = 0
+		if charged := b.e.charged[b.key]; b.read > charged {
+			charge = b.read - charged
+			b.e.charged[b.key] = b.read
+		}
+	}
+	b.e.extracted += charge
+	exceeded := b.e.extracted > b.e.limits.MaxExtractedSize
+	b.e.mu.Unlock()
+	if exceeded {
+		return n, fmt.Errorf("%w: more than %v bytes extracted", ErrLimitExceeded, b.e.limits.MaxExtractedSize)
+	}
+	return n, err
+}
+
+func isZip(name string) bool {
+	return hasAnySuffix(strings.ToLower(name), zipSuffixes)
+}
+
+func isTar(name string) bool {
+	return hasAnySuffix(strings.ToLower(name), tarSuffixes)
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
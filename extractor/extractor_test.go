@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package extractor
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testEntries = map[string]string{
+	"a/LICENSE":   "MIT",
+	"a/b/COPYING": "ISC",
+}
+
+func writeZip(t *testing.T, path string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if _, err := zw.Create("a/"); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range testEntries {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "a/", Typeflag: tar.TypeDir, Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "a/link", Typeflag: tar.TypeSymlink, Linkname: "LICENSE"}); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range testEntries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		write func(*testing.T, string)
+	}{
+		{name: "x.whl", write: writeZip},
+		{name: "x.tar.gz", write: writeTarGz},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			archivePath := filepath.Join(dir, tt.name)
+			tt.write(t, archivePath)
+			got := make(map[string]string)
+			err := Walk(archivePath, func(name string, size int64, r io.Reader) error {
+				b, err := ReadEntry(name, r)
+				got[name] = string(b)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Walk() error = %v", err)
+			}
+			if d := cmp.Diff(testEntries, got); d != "" {
+				t.Errorf("Walk() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestWalkChargedOnce(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tests := []struct {
+		name  string
+		write func(*testing.T, string)
+	}{
+		{name: "x.whl", write: writeZip},
+		{name: "x.tar.gz", write: writeTarGz},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			archivePath := filepath.Join(dir, tt.name)
+			tt.write(t, archivePath)
+			readAll := func(name string, size int64, r io.Reader) error {
+				_, err := io.Copy(io.Discard, r)
+				return err
+			}
+
+			// The first walk charges the archive, and reading it again (e.g., to extract the nested archives of a
+			// package which was identified) charges nothing more
+			ex := New(DefaultLimits)
+			if err := ex.Walk(archivePath, readAll); err != nil {
+				t.Fatalf("Walk() error = %v", err)
+			}
+			charged := ex.extracted
+			if charged == 0 {
+				t.Fatal("Walk() charged nothing")
+			}
+			if err := ex.Walk(archivePath, readAll); err != nil {
+				t.Fatalf("Walk() again error = %v", err)
+			}
+			if _, err := ex.Extract(archivePath, func(string) bool { return true }); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if ex.extracted != charged {
+				t.Errorf("extracted after reading again = %v, want %v", ex.extracted, charged)
+			}
+
+			// A budget for one archive is enough to read it any number of times
+			ex = New(Limits{MaxDepth: 3, MaxExtractedSize: charged, MaxCompressionRatio: 200})
+			for i := 0; i < 3; i++ {
+				if err := ex.Walk(archivePath, readAll); err != nil {
+					t.Fatalf("Walk() %v error = %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadEntryTooLarge(t *testing.T) {
+	t.Parallel()
+	_, err := ReadEntry("big", strings.NewReader(strings.Repeat("x", MaxEntrySize+1)))
+	if err == nil {
+		t.Error("ReadEntry() expected error for entry larger than MaxEntrySize")
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	t.Parallel()
+	for name, expected := range map[string]bool{
+		"a.whl": true, "a.ZIP": true, "a.jar": true, "a.war": true, "a.tar": true, "a.tar.gz": true, "a.tgz": true,
+		"a.gz": false, "a.txt": false,
+	} {
+		if got := IsArchive(name); got != expected {
+			t.Errorf("IsArchive(%v) = %v want %v", name, got, expected)
+		}
+	}
+}
+
+func TestWalkNested(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	inner := filepath.Join(dir, "inner.jar")
+	writeZip(t, inner)
+	b, err := os.ReadFile(inner)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// outer.tar.gz contains lib/inner.jar
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	if err := tw.WriteHeader(&tar.Header{Name: "lib/inner.jar", Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(b))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	outer := filepath.Join(dir, "outer.tar.gz")
+	if err := os.WriteFile(outer, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	nestedPath := EntryPath(outer, "lib/inner.jar")
+
+	walk := func(ex *Extractor, archivePath string) (map[string]string, error) {
+		got := make(map[string]string)
+		err := ex.Walk(archivePath, func(name string, size int64, r io.Reader) error {
+			b, err := ReadEntry(name, r)
+			got[name] = string(b)
+			return err
+		})
+		return got, err
+	}
+
+	t.Run("walk nested path", func(t *testing.T) {
+		t.Parallel()
+		got, err := walk(New(DefaultLimits), nestedPath)
+		if err != nil {
+			t.Fatalf("Walk() error = %v", err)
+		}
+		if d := cmp.Diff(testEntries, got); d != "" {
+			t.Errorf("Walk() mismatch (-want +got):\n%s", d)
+		}
+	})
+
+	t.Run("extract then walk", func(t *testing.T) {
+		t.Parallel()
+		ex := New(DefaultLimits)
+		paths, err := ex.Extract(outer, func(string) bool { return true })
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		if d := cmp.Diff([]string{nestedPath}, paths); d != "" {
+			t.Fatalf("Extract() mismatch (-want +got):\n%s", d)
+		}
+		got, err := walk(ex, nestedPath)
+		if err != nil {
+			t.Fatalf("Walk() error = %v", err)
+		}
+		if d := cmp.Diff(testEntries, got); d != "" {
+			t.Errorf("Walk() mismatch (-want +got):\n%s", d)
+		}
+		ex.Release(nestedPath)
+	})
+
+	t.Run("max depth", func(t *testing.T) {
+		t.Parallel()
+		ex := New(Limits{MaxDepth: 0, MaxExtractedSize: 1 << 20, MaxCompressionRatio: 200})
+		if _, err := walk(ex, nestedPath); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("Walk() expected ErrLimitExceeded got %v", err)
+		}
+		if paths, err := ex.Extract(outer, func(string) bool { return true }); err != nil || len(paths) != 0 {
+			t.Errorf("Extract() expected nothing beyond the max depth got %v, %v", paths, err)
+		}
+	})
+
+	t.Run("max extracted size", func(t *testing.T) {
+		t.Parallel()
+		ex := New(Limits{MaxDepth: 3, MaxExtractedSize: 10, MaxCompressionRatio: 200})
+		if _, err := walk(ex, nestedPath); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("Walk() expected ErrLimitExceeded got %v", err)
+		}
+	})
+}
+
+func TestZipBomb(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("zeros")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(make([]byte, 10*MaxEntrySize)); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	bomb := filepath.Join(t.TempDir(), "bomb.zip")
+	if err := os.WriteFile(bomb, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err = Walk(bomb, func(name string, size int64, r io.Reader) error {
+		t.Errorf("Walk() should not open %v", name)
+		return nil
+	})
+	if !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("Walk() expected ErrLimitExceeded got %v", err)
+	}
+}
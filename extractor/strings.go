@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package extractor
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxBinarySize is the largest executable (or library) whose strings are extracted
+	MaxBinarySize = 256 << 20
+	// MinStringLength is the fewest characters of an extracted string (the shorter runs are mostly code and
+	// data which happen to be printable)
+	MinStringLength = 8
+)
+
+// The BinaryFormat of the executables and libraries
+const (
+	ELF   = "ELF"
+	PE    = "PE"
+	MachO = "Mach-O"
+)
+
+// BinaryFormat returns the format of an executable or library (ELF, PE, or Mach-O) from the header of the file,
+// or "" when it is not a binary
+func BinaryFormat(header []byte) string {
+	if len(header) < 8 {
+		return ""
+	}
+	switch {
+	case bytes.HasPrefix(header, []byte("\x7fELF")):
+		return ELF
+	case bytes.HasPrefix(header, []byte("MZ")):
+		return PE
+	}
+	switch binary.BigEndian.Uint32(header) {
+	case 0xfeedface, 0xfeedfacf, 0xcefaedfe, 0xcffaedfe:
+		return MachO
+	case 0xcafebabe:
+		// A universal (fat) Mach-O has a few architectures, where a Java class file has its version (45 or more)
+		if n := binary.BigEndian.Uint32(header[4:]); n > 0 && n < 45 {
+			return MachO
+		}
+	}
+	return ""
+}
+
+// BinaryFileFormat returns the BinaryFormat of a file ("" when it is not a binary)
+func BinaryFileFormat(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	header := make([]byte, 8)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	return BinaryFormat(header[:n]), nil
+}
+
+// Strings returns the printable strings of a binary (like the strings command), a line each: the runs of at least
+// MinStringLength printable UTF-8 characters (and tabs) which have a space, so the words of the notices compiled
+// into the binary are kept without its symbol names and paths. The strings are cut at the max bytes (at a line),
+// and truncated is true when more strings were dropped.
+func Strings(b []byte, max int) (s string, truncated bool) {
+	var sb strings.Builder
+	add := func(run []byte) bool {
+		if utf8.RuneCount(run) < MinStringLength || !bytes.ContainsRune(run, ' ') {
+			return true
+		}
+		if sb.Len()+len(run)+1 > max {
+			return false
+		}
+		sb.Write(run)
+		sb.WriteByte('\n')
+		return true
+	}
+	begins := 0
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if (r == utf8.RuneError && size <= 1) || (r != '\t' && !unicode.IsPrint(r)) {
+			if !add(b[begins:i]) {
+				return sb.String(), true
+			}
+			begins = i + size
+		}
+		i += size
+	}
+	if !add(b[begins:]) {
+		return sb.String(), true
+	}
+	return sb.String(), false
+}
@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package extractor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBinaryFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{name: "ELF", header: "\x7fELF\x02\x01\x01\x00", want: ELF},
+		{name: "PE", header: "MZ\x90\x00\x03\x00\x00\x00", want: PE},
+		{name: "Mach-O 64-bit little-endian", header: "\xcf\xfa\xed\xfe\x07\x00\x00\x01", want: MachO},
+		{name: "Mach-O 32-bit big-endian", header: "\xfe\xed\xfa\xce\x00\x00\x00\x12", want: MachO},
+		{name: "universal Mach-O", header: "\xca\xfe\xba\xbe\x00\x00\x00\x02", want: MachO},
+		{name: "Java class", header: "\xca\xfe\xba\xbe\x00\x00\x00\x34", want: ""},
+		{name: "text", header: "MIT License\n", want: ""},
+		{name: "short", header: "\x7fELF", want: ""},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := BinaryFormat([]byte(tt.header)); got != tt.want {
+				t.Errorf("BinaryFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBinaryFileFormat(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	elf := filepath.Join(dir, "a.out")
+	if err := os.WriteFile(elf, []byte("\x7fELF\x02\x01\x01\x00\x00\x00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	empty := filepath.Join(dir, "empty")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for filePath, want := range map[string]string{elf: ELF, empty: ""} {
+		got, err := BinaryFileFormat(filePath)
+		if err != nil {
+			t.Fatalf("BinaryFileFormat(%v) error = %v", filePath, err)
+		}
+		if got != want {
+			t.Errorf("BinaryFileFormat(%v) = %q, want %q", filePath, got, want)
+		}
+	}
+	if _, err := BinaryFileFormat(filepath.Join(dir, "missing")); err == nil {
+		t.Error("BinaryFileFormat() of a missing file, want an error")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		b             string
+		max           int
+		want          string
+		wantTruncated bool
+	}{
+		{
+			name: "notice lines",
+			b:    "\x7fELF\x02\x01\x01\x00Copyright (c) 2001 ACME Inc.\nPermission is hereby granted\x00\x01",
+			max:  1000,
+			want: "Copyright (c) 2001 ACME Inc.\nPermission is hereby granted\n",
+		},
+		{
+			name: "symbols, paths, and short runs are dropped",
+			b:    "runtime.mallocgc\x00/usr/lib/libc.so\x00a b c\x00\xffGNU General Public License\x00",
+			max:  1000,
+			want: "GNU General Public License\n",
+		},
+		{
+			name: "UTF-8 and tabs",
+			b:    "\x00\tCopyright © ACME\x00",
+			max:  1000,
+			want: "\tCopyright © ACME\n",
+		},
+		{
+			name:          "cut at the max",
+			b:             "first string\x00second string\x00",
+			max:           20,
+			want:          "first string\n",
+			wantTruncated: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, truncated := Strings([]byte(tt.b), tt.max)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Strings() mismatch (-want +got):\n%s", d)
+			}
+			if truncated != tt.wantTruncated {
+				t.Errorf("Strings() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package history tracks the license changes in the commit history of a git repository
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+var (
+	// pathspecs select the candidate LICENSE and COPYING files (in any directory)
+	pathspecs = []string{
+		":(glob,icase)**/LICENSE*",
+		":(glob,icase)**/LICENCE*",
+		":(glob,icase)**/COPYING*",
+	}
+	// licenseFileRE matches the names of the license files, e.g., LICENSE, license.md, LICENSE-MIT or
+	// COPYING.LESSER (but not source files like license.go)
+	licenseFileRE = regexp.MustCompile(`^(?i:licen[cs]e|copying)(?i:\.txt|\.md|\.rst|\.markdown)?$|^(LICEN[CS]E|COPYING)[-_.].+$`)
+)
+
+// Change is a commit which changed the licenses detected in a LICENSE or COPYING file
+type Change struct {
+	Commit  string
+	Date    string
+	Author  string
+	Subject string
+	// File is the path in the repository
+	File string
+	// From and To are the license IDs detected before and after the commit (none when the file did not exist)
+	From []string
+	To   []string
+}
+
+// Added is true when the file was added by the commit
+func (c Change) Added() bool {
+	return c.From == nil
+}
+
+// Deleted is true when the file was deleted by the commit
+func (c Change) Deleted() bool {
+	return c.To == nil
+}
+
+// commit is a commit in the git log, with the license files which it changed
+type commit struct {
+	hash, date, author, subject string
+	// files has the license files which were added or modified (true) or deleted (false)
+	files map[string]bool
+}
+
+// Changes walks the commit history of the repository (from the first commit) and returns each change of
+// the licenses detected in the LICENSE and COPYING files. Commits which edit a license file without
+// changing the detected licenses are not changes. The git command is used to read the history.
+func Changes(repo string, options identifier.Options, licenseLibrary *licenses.LicenseLibrary) ([]Change, error) {
+	commits, err := licenseCommits(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Change
+	detected := make(map[string][]string)
+	for _, c := range commits {
+		files := make([]string, 0, len(c.files))
+		for file := range c.files {
+			files = append(files, file)
+		}
+		sort.Strings(files)
+		for _, file := range files {
+			var ids []string
+			if c.files[file] {
+				text, err := git(repo, "show", c.hash+":"+file)
+				if err != nil {
+					return nil, err
+				}
+				results, err := identifier.IdentifyLicensesInString(text, options, licenseLibrary)
+				if err != nil {
+					return nil, fmt.Errorf("cannot identify the licenses in %v at %v: %w", file, c.hash, err)
+				}
+				ids = make([]string, 0, len(results.Matches))
+				for id := range results.Matches {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+			}
+
+			from, existed := detected[file]
+			if existed && ids != nil && slices.Equal(from, ids) {
+				continue
+			}
+			ret = append(ret, Change{Commit: c.hash, Date: c.date, Author: c.author, Subject: c.subject, File: file, From: from, To: ids})
+			if ids == nil {
+				delete(detected, file)
+			} else {
+				detected[file] = ids
+			}
+		}
+	}
+	return ret, nil
+}
+
+// licenseCommits returns the commits which changed the license files, oldest first
+func licenseCommits(repo string) ([]commit, error) {
+	args := append([]string{"log", "--reverse", "--no-renames", "--format=%x00%H%x09%aI%x09%an%x09%s", "--name-status", "--"}, pathspecs...)
+	out, err := git(repo, args...)
+	if err != nil {
+		return nil, err
+	}
+	var ret []commit
+	for _, entry := range strings.Split(out, "\x00") {
+		lines := strings.Split(strings.TrimSpace(entry), "\n")
+		fields := strings.SplitN(lines[0], "\t", 4)
+		if len(fields) < 4 {
+			continue
+		}
+		c := commit{hash: fields[0], date: fields[1], author: fields[2], subject: fields[3], files: make(map[string]bool)}
+		for _, line := range lines[1:] {
+			status, file, found := strings.Cut(line, "\t")
+			if !found || !licenseFileRE.MatchString(path.Base(file)) {
+				continue
+			}
+			c.files[file] = status != "D"
+		}
+		if len(c.files) > 0 {
+			ret = append(ret, c)
+		}
+	}
+	return ret, nil
+}
+
+// ChangedFiles returns the paths (joined with the directory) of the files under the directory which
+// were added or modified between the ref and HEAD. Like a pull request, the changes are from the merge
+// base of the ref and HEAD, so the changes on the ref since then are not included. Deleted files and
+// empty files are left out.
+func ChangedFiles(dir string, ref string) ([]string, error) {
+	return diffFiles(dir, ref+"...HEAD")
+}
+
+// StagedFiles returns the paths (joined with the directory) of the files under the directory which are
+// added or modified in the git index (staged for the next commit). Deleted files and empty files are
+// left out.
+func StagedFiles(dir string) ([]string, error) {
+	return diffFiles(dir, "--cached")
+}
+
+// Head returns the commit hash of the HEAD of the git repository of the directory
+func Head(dir string) (string, error) {
+	out, err := git(dir, "rev-parse", "HEAD")
+	return strings.TrimSpace(out), err
+}
+
+// diffFiles returns the files under the directory in the git diff with the arguments
+func diffFiles(dir string, args ...string) ([]string, error) {
+	args = append([]string{"diff", "--name-only", "--no-renames", "--diff-filter=d", "--relative", "-z"}, args...)
+	out, err := git(dir, append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	var ret []string
+	for _, file := range strings.Split(out, "\x00") {
+		if file == "" {
+			continue
+		}
+		file = filepath.Join(dir, filepath.FromSlash(file))
+		if info, err := os.Stat(file); err == nil && info.Mode().IsRegular() && info.Size() > 0 {
+			ret = append(ret, file)
+		}
+	}
+	return ret, nil
+}
+
+// git runs a git command in the repository and returns its output
+func git(repo string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %v: %w: %v", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return string(out), nil
+}
@@ -0,0 +1,205 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package history
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestChanges(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	mit, err := os.ReadFile("../resources/spdx/default/testdata/MIT.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	apache, err := os.ReadFile("../resources/spdx/default/testdata/Apache-2.0.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, commit := newRepo(t)
+	commit("initial", map[string][]byte{"LICENSE": mit, "main.go": []byte("package main\n"), "license/license.go": []byte("package license\n")})
+	commit("edit the code", map[string][]byte{"main.go": []byte("package main\n\nfunc main() {}\n")})
+	commit("reformat the license", map[string][]byte{"LICENSE": append(mit, '\n')})
+	commit("relicense", map[string][]byte{"LICENSE": apache, "vendor/lib/COPYING": mit})
+	commit("remove vendored lib", map[string][]byte{"vendor/lib/COPYING": nil})
+
+	got, err := Changes(repo, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []Change{
+		{Subject: "initial", Author: "Tester", File: "LICENSE", To: []string{"MIT"}},
+		{Subject: "relicense", Author: "Tester", File: "LICENSE", From: []string{"MIT"}, To: []string{"Apache-2.0"}},
+		{Subject: "relicense", Author: "Tester", File: "vendor/lib/COPYING", To: []string{"MIT"}},
+		{Subject: "remove vendored lib", Author: "Tester", File: "vendor/lib/COPYING", From: []string{"MIT"}},
+	}
+	if d := cmp.Diff(want, got, cmpopts.IgnoreFields(Change{}, "Commit", "Date")); d != "" {
+		t.Errorf("Changes() mismatch (-want +got):\n%s", d)
+	}
+	if !got[0].Added() || got[0].Deleted() || !got[3].Deleted() {
+		t.Errorf("Added() and Deleted() = %v, %v, %v", got[0].Added(), got[0].Deleted(), got[3].Deleted())
+	}
+	for _, c := range got {
+		if len(c.Commit) != 40 || c.Date == "" {
+			t.Errorf("Change commit %q and date %q", c.Commit, c.Date)
+		}
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo, commit := newRepo(t)
+	commit("initial", map[string][]byte{"LICENSE": []byte("MIT"), "src/a.go": []byte("package src\n"), "docs/b.md": []byte("b")})
+	commit("base", map[string][]byte{"docs/b.md": []byte("b2")})
+	commit("change", map[string][]byte{"src/a.go": []byte("package src\n\n// A\n"), "src/c.go": []byte("package src\n"), "src/empty.go": []byte{}, "docs/b.md": nil})
+	commit("more", map[string][]byte{"src/d.go": []byte("package src\n")})
+
+	tests := []struct {
+		name string
+		dir  string
+		ref  string
+		want []string
+	}{
+		{
+			name: "repository",
+			dir:  repo,
+			ref:  "HEAD~2",
+			want: []string{filepath.Join(repo, "src", "a.go"), filepath.Join(repo, "src", "c.go"), filepath.Join(repo, "src", "d.go")},
+		},
+		{
+			name: "subdirectory",
+			dir:  filepath.Join(repo, "src"),
+			ref:  "HEAD~1",
+			want: []string{filepath.Join(repo, "src", "d.go")},
+		},
+		{
+			name: "no changes",
+			dir:  repo,
+			ref:  "HEAD",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ChangedFiles(tt.dir, tt.ref)
+			if err != nil {
+				t.Fatalf("ChangedFiles() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ChangedFiles() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+	if _, err := ChangedFiles(repo, "no-such-ref"); err == nil {
+		t.Errorf("ChangedFiles() of an unknown ref error = nil")
+	}
+}
+
+// newRepo creates a git repository, with a function to commit files (nil content deletes a file)
+func newRepo(t *testing.T) (string, func(message string, files map[string][]byte)) {
+	t.Helper()
+	repo := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Tester", "-c", "user.email=tester@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	commit := func(message string, files map[string][]byte) {
+		t.Helper()
+		for name, content := range files {
+			path := filepath.Join(repo, filepath.FromSlash(name))
+			if content == nil {
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		run("add", "-A")
+		run("commit", "-q", "-m", message)
+	}
+	run("init", "-q")
+	return repo, commit
+}
+
+func TestHead(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo, commit := newRepo(t)
+	if _, err := Head(repo); err == nil {
+		t.Error("Head() of a repository without commits error = nil")
+	}
+	commit("first", map[string][]byte{"LICENSE": []byte("MIT")})
+	head, err := Head(repo)
+	if err != nil {
+		t.Fatalf("Head() error = %v", err)
+	}
+	if len(head) != 40 {
+		t.Errorf("Head() = %q, want a commit hash", head)
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo, commit := newRepo(t)
+	commit("initial", map[string][]byte{"LICENSE": []byte("MIT"), "a.go": []byte("package a\n"), "b.go": []byte("package a\n")})
+	for name, content := range map[string]string{"a.go": "package a\n\n// A\n", "c.go": "package a\n", "unstaged.go": "package a\n"} {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(filepath.Join(repo, "b.go")); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("git", "-C", repo, "add", "a.go", "b.go", "c.go").CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	got, err := StagedFiles(repo)
+	if err != nil {
+		t.Fatalf("StagedFiles() error = %v", err)
+	}
+	want := []string{filepath.Join(repo, "a.go"), filepath.Join(repo, "c.go")}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("StagedFiles() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpclient is the HTTP client of the network features of license-scanner (e.g., the webhook and S3 report
+// destinations): it uses the proxy of the environment, a timeout, and the trusted CA certificates of the options,
+// and retries with a backoff when a service is unavailable.
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultRetries is how many times a failed request is retried
+	DefaultRetries = 3
+	// DefaultBackoff is the wait before the first retry (doubled for each retry)
+	DefaultBackoff = time.Second
+	// DefaultTimeout is the timeout of each attempt of a request
+	DefaultTimeout = 30 * time.Second
+	// UserAgent is the User-Agent header of the requests which do not set one
+	UserAgent = "license-scanner"
+)
+
+// ErrOffline is returned for every request of an offline client
+var ErrOffline = errors.New("network access is not allowed offline")
+
+// Options configure the client
+type Options struct {
+	// Timeout is the timeout of each attempt of a request (0 for no timeout)
+	Timeout time.Duration
+	// Retries is how many times a failed request is retried
+	Retries int
+	// Backoff is the wait before the first retry, doubled for each retry
+	Backoff time.Duration
+	// Proxy is the URL of the proxy of all the requests. The HTTPS_PROXY, HTTP_PROXY, and NO_PROXY environment
+	// variables are used when it is "".
+	Proxy string
+	// CAFile is a PEM file of CA certificates to trust in addition to the system certificates ("" for none)
+	CAFile string
+	// Offline fails every request with ErrOffline instead of accessing the network
+	Offline bool
+}
+
+// DefaultOptions are the options of the default client
+var DefaultOptions = Options{Timeout: DefaultTimeout, Retries: DefaultRetries, Backoff: DefaultBackoff}
+
+// Client sends the HTTP requests of the network features. It is safe for concurrent use.
+type Client struct {
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	offline bool
+}
+
+// StatusError is the response of a service to a request which it did not accept
+type StatusError struct {
+	StatusCode int
+	// Body is the start of the response body
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("the service responded %v %v", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("the service responded %v %v: %v", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// retryable is true for the responses of a service which may accept the request later
+func (e *StatusError) retryable() bool {
+	return e.StatusCode >= http.StatusInternalServerError || e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusRequestTimeout
+}
+
+// New returns a client with the options
+func New(opts Options) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil || proxy.Scheme == "" || proxy.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", opts.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	if opts.CAFile != "" {
+		pool, err := certPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &Client{
+		client:  &http.Client{Timeout: opts.Timeout, Transport: transport},
+		retries: opts.Retries,
+		backoff: opts.Backoff,
+		offline: opts.Offline,
+	}, nil
+}
+
+// Default returns a client with the DefaultOptions
+func Default() *Client {
+	c, _ := New(DefaultOptions) // the default options have no proxy or CA file which could be invalid
+	return c
+}
+
+// certPool returns the system certificates with the CA certificates of the PEM file
+func certPool(caFile string) (*x509.CertPool, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	b, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no PEM certificates in the CA file %v", caFile)
+	}
+	return pool, nil
+}
+
+// Do sends the request and returns the response of a 2xx status, which the caller closes. A network error or a 5xx,
+// 429, or 408 response is retried after the backoff (doubled for each retry), with the body of the GetBody of the
+// request (set by http.NewRequest for a bytes body), and any other response fails at once with a StatusError. The
+// error of the last attempt is returned when all the attempts failed.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if c.offline {
+		return nil, fmt.Errorf("cannot send the request to %v: %w", req.URL.Redacted(), ErrOffline)
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", UserAgent)
+	}
+	ctx := req.Context()
+	backoff := c.backoff
+	attempt := req
+	for i := 0; ; i++ {
+		resp, err := c.do(attempt)
+		if err == nil {
+			return resp, nil
+		}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && !statusErr.retryable() {
+			return nil, err
+		}
+		if i >= c.retries || (req.Body != nil && req.GetBody == nil) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if attempt, err = retry(ctx, req); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do sends the request once
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(b))}
+}
+
+// retry returns a copy of the request with a new body
+func retry(ctx context.Context, req *http.Request) (*http.Request, error) {
+	r := req.Clone(ctx)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
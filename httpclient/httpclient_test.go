@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Do(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// statuses are the responses of the service to the attempts (the last one is repeated)
+		statuses     []int
+		retries      int
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{name: "accepted", statuses: []int{http.StatusCreated}, retries: 3, wantAttempts: 1},
+		{name: "retried until accepted", statuses: []int{http.StatusServiceUnavailable, http.StatusRequestTimeout, http.StatusOK}, retries: 3, wantAttempts: 3},
+		{name: "retries exhausted", statuses: []int{http.StatusBadGateway}, retries: 2, wantAttempts: 3, wantStatus: http.StatusBadGateway},
+		{name: "not retried", statuses: []int{http.StatusForbidden}, retries: 3, wantAttempts: 1, wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&attempts, 1))
+				// Every attempt has the body
+				if b, _ := io.ReadAll(r.Body); string(b) != "report" || r.Header.Get("User-Agent") != UserAgent {
+					t.Errorf("got body %s and headers %v", b, r.Header)
+				}
+				status := tt.statuses[len(tt.statuses)-1]
+				if n <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			client, err := New(Options{Timeout: DefaultTimeout, Retries: tt.retries, Backoff: time.Millisecond})
+			if err != nil {
+				t.Fatal(err)
+			}
+			req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, srv.URL, bytes.NewReader([]byte("report")))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			var statusErr *StatusError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Errorf("Do() error = %v", err)
+			case tt.wantStatus != 0 && !errors.As(err, &statusErr):
+				t.Errorf("Do() error = %v, want the status %v", err, tt.wantStatus)
+			case tt.wantStatus != 0 && statusErr.StatusCode != tt.wantStatus:
+				t.Errorf("Do() status = %v, want %v", statusErr.StatusCode, tt.wantStatus)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("Do() attempts = %v, want %v", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestClient_Do_proxy(t *testing.T) {
+	t.Parallel()
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String() // the absolute URL of the request
+	}))
+	defer proxy.Close()
+	client, err := New(Options{Timeout: DefaultTimeout, Proxy: proxy.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, "http://compliance.example.com/api/scans", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+	if proxied != "http://compliance.example.com/api/scans" {
+		t.Errorf("proxied request = %q, want the request through the proxy", proxied)
+	}
+}
+
+func TestClient_Do_caFile(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		caFile  string
+		wantErr bool
+	}{{caFile: "", wantErr: true}, {caFile: caFile}} {
+		client, err := New(Options{Timeout: DefaultTimeout, CAFile: tt.caFile})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Do() with the CA file %q error = %v, wantErr %v", tt.caFile, err, tt.wantErr)
+		}
+	}
+}
+
+func TestClient_Do_offline(t *testing.T) {
+	t.Parallel()
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { atomic.AddInt32(&attempts, 1) }))
+	defer srv.Close()
+	client, err := New(Options{Offline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Do(req); !errors.Is(err, ErrOffline) {
+		t.Errorf("Do() error = %v, want %v", err, ErrOffline)
+	}
+	if attempts != 0 {
+		t.Errorf("got %v requests offline, want none", attempts)
+	}
+}
+
+func TestNew_errors(t *testing.T) {
+	t.Parallel()
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for name, opts := range map[string]Options{
+		"proxy without a scheme": {Proxy: "proxy.example.com:3128"},
+		"missing CA file":        {CAFile: filepath.Join(t.TempDir(), "missing.pem")},
+		"CA file without PEM":    {CAFile: notPEM},
+	} {
+		if _, err := New(opts); err == nil {
+			t.Errorf("New() with a %v error = nil, want an error", name)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// identifyLicensesInBinary identifies the licenses in the printable strings of an executable or library (with the
+// Binaries option), e.g., the notices compiled into it, also matching the license headers and flagging the keywords.
+// The results have the strings as the text, and are Truncated when the strings were more than a file to scan.
+func identifyLicensesInBinary(filePath string, format string, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return IdentifierResults{}, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(io.LimitReader(f, extractor.MaxBinarySize+1))
+	if err != nil {
+		return IdentifierResults{}, err
+	}
+	if len(b) > extractor.MaxBinarySize {
+		return IdentifierResults{}, fmt.Errorf("%v binary too large (> %v)", format, extractor.MaxBinarySize)
+	}
+
+	s, truncated := extractor.Strings(b, extractor.MaxEntrySize)
+	if s == "" {
+		return IdentifierResults{File: filePath}, nil
+	}
+	options.Headers = true
+	options.Enhancements.FlagKeywords = true
+	result, err := IdentifyLicensesInString(s, options, licenseLibrary)
+	result.File = filePath
+	if truncated && err == nil {
+		result.Truncated = true
+		addExtractedLicense(&result, result.Hash.Sha256)
+	}
+	return result, err
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_identifyLicensesInBinary(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	mitText, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An ELF with code bytes and symbol names around the notices (their lines are NUL-terminated strings)
+	dir := t.TempDir()
+	elf := filepath.Join(dir, "app")
+	content := "\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x02\x00\x3e\x00" +
+		"main.main\x00runtime.gcWriteBarrier\x00\x48\x8b\x44\x24\x08\xc3" +
+		strings.ReplaceAll(string(mitText), "\n", "\x00") + "\x00\x90\x90" +
+		"Portions are under the ACME commercial terms\x00"
+	if err := os.WriteFile(elf, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		binaries     bool
+		wantLicenses []string
+		wantKeywords bool
+		wantErr      bool
+	}{
+		{name: "strings of the binary", binaries: true, wantLicenses: []string{"MIT"}, wantKeywords: true},
+		{name: "without binaries", binaries: false, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := IdentifyLicensesInFile(elf, Options{Binaries: tt.binaries}, licenseLibrary)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IdentifyLicensesInFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var ids []string
+			for id := range got.Matches {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			if d := cmp.Diff(tt.wantLicenses, ids); d != "" {
+				t.Errorf("IdentifyLicensesInFile() licenses mismatch (-want +got):\n%s", d)
+			}
+			if (len(got.KeywordMatches) > 0) != tt.wantKeywords {
+				t.Errorf("IdentifyLicensesInFile() keywords = %v, want keywords %v", got.KeywordMatches, tt.wantKeywords)
+			}
+			if got.File != elf {
+				t.Errorf("IdentifyLicensesInFile() file = %v, want %v", got.File, elf)
+			}
+			if strings.Contains(got.OriginalText, "runtime.gcWriteBarrier") {
+				t.Errorf("IdentifyLicensesInFile() text has the symbol names: %q", got.OriginalText)
+			}
+		})
+	}
+}
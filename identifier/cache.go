@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// ResultCache keeps the license matches by the hash of the normalized text, so that files with the
+// same normalized content (e.g., thousands of LICENSE copies in a monorepo) are only matched once.
+// The matches are kept in normalized text positions and mapped to the original text of each file.
+type ResultCache struct {
+	mu      sync.Mutex
+	matches map[string]map[string][]Match
+	// dir is the optional on-disk cache for the license library (empty for in-memory only)
+	dir string
+}
+
+// NewResultCache returns a cache for the results of the license library.
+// With a dir, the results are also read from and written to files under the dir. The files are
+// under a subdir for the library, so a changed license library does not use stale results.
+func NewResultCache(dir string, licenseLibrary *licenses.LicenseLibrary) (*ResultCache, error) {
+	c := &ResultCache{matches: make(map[string]map[string][]Match)}
+	if dir == "" {
+		return c, nil
+	}
+	c.dir = filepath.Join(dir, libraryFingerprint(licenseLibrary))
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// libraryFingerprint returns a hash of the patterns (and the bound of their variables and the line prefixes of
+// their normalization), aliases, URLs, and match guards of the licenses in the library
+func libraryFingerprint(ll *licenses.LicenseLibrary) string {
+	var ids []string
+	for id := range ll.LicenseMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	write(ll.SPDXVersion)
+	write(strconv.Itoa(ll.PreCheckSettings.RequiredBlocks))
+	write(strconv.Itoa(ll.MaxVariableLength()))
+	for _, prefix := range ll.LinePrefixes.Prefixes() {
+		write(prefix)
+	}
+	for _, id := range ids {
+		l := ll.LicenseMap[id]
+		write(id)
+		for _, patterns := range [][]*licenses.PrimaryPatterns{l.PrimaryPatterns, l.AssociatedPatterns} {
+			for _, p := range patterns {
+				write(p.Text)
+				if preChecks := ll.PrimaryPatternPreCheckMap[licenses.LicensePatternKey{FilePath: p.FileName}]; preChecks != nil {
+					for _, block := range preChecks.StaticBlocks {
+						write(block)
+					}
+				}
+			}
+		}
+		for _, s := range append(append([]string{}, l.Aliases...), l.URLs...) {
+			write(s)
+		}
+		write(l.LicenseInfo.Name)
+		if guard := ll.MatchGuard(id); guard != nil {
+			write(strconv.Itoa(guard.MinLength))
+			write(strconv.Itoa(guard.Window))
+			for _, context := range guard.Context {
+				write(context)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// get returns the cached matches mapped to the original text of the normalized data
+func (c *ResultCache) get(nd normalizer.NormalizationData) (map[string][]Match, bool) {
+	key := nd.Hash.Sha256
+	c.mu.Lock()
+	cached, ok := c.matches[key]
+	c.mu.Unlock()
+	if !ok && c.dir != "" {
+		b, err := os.ReadFile(c.path(key))
+		if err != nil || json.Unmarshal(b, &cached) != nil {
+			return nil, false
+		}
+		c.mu.Lock()
+		c.matches[key] = cached
+		c.mu.Unlock()
+	}
+	if cached == nil {
+		return nil, false
+	}
+
+	ret := make(map[string][]Match)
+	for id, matches := range cached {
+		for _, m := range matches {
+			if m.Ends >= len(nd.IndexMap) {
+				return nil, false // not the same normalized text after all
+			}
+			ret[id] = append(ret[id], Match{Begins: nd.IndexMap[m.Begins], Ends: nd.IndexMap[m.Ends]})
+		}
+	}
+	return ret, true
+}
+
+// put caches the matches (in the original text of the normalized data) by normalized text position
+func (c *ResultCache) put(nd normalizer.NormalizationData, matches map[string][]Match) error {
+	cached := make(map[string][]Match)
+	for id, ms := range matches {
+		for _, m := range ms {
+			cached[id] = append(cached[id], Match{Begins: normalizedBegin(m.Begins, nd.IndexMap), Ends: normalizedEnd(m.Ends, nd.IndexMap)})
+		}
+	}
+	key := nd.Hash.Sha256
+	c.mu.Lock()
+	c.matches[key] = cached
+	c.mu.Unlock()
+	if c.dir == "" {
+		return nil
+	}
+
+	b, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	// Write and rename, so a concurrent scan never reads a partial file
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path(key)); err != nil && !errors.Is(err, fs.ErrExist) {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (c *ResultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// normalizedBegin returns the first normalized text position which maps to (or after) the original offset
+func normalizedBegin(offset int, indexMap []int) int {
+	for i, o := range indexMap {
+		if o >= offset {
+			return i
+		}
+	}
+	return len(indexMap) - 1
+}
+
+// normalizedEnd returns the last normalized text position which maps to (or before) the original offset
+func normalizedEnd(offset int, indexMap []int) int {
+	for i := len(indexMap) - 1; i >= 0; i-- {
+		if indexMap[i] <= offset {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func TestResultCache(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Not verbatim, so the matches are not exact hash matches
+	first := "The MIT code:\n" + string(b)
+	// Same normalized text, but not the same offsets in the original text
+	second := "\n\nThe   MIT code:\n\n" + strings.ReplaceAll(string(b), "\n", "\n\n")
+
+	want, err := IdentifyLicensesInString(second, defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+	if _, ok := want.Matches["MIT"]; !ok {
+		t.Fatalf("IdentifyLicensesInString() did not match MIT: %v", want.Matches)
+	}
+
+	dir := t.TempDir()
+	cache, err := NewResultCache(dir, licenseLibrary)
+	if err != nil {
+		t.Fatalf("NewResultCache() error = %v", err)
+	}
+	options := defaultOptions()
+	options.Cache = cache
+	if _, err := IdentifyLicensesInString(first, options, licenseLibrary); err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+
+	// A new cache with the same dir reads the results written by the first cache
+	diskCache, err := NewResultCache(dir, licenseLibrary)
+	if err != nil {
+		t.Fatalf("NewResultCache() error = %v", err)
+	}
+	for name, c := range map[string]*ResultCache{"in-memory": cache, "on disk": diskCache} {
+		nd := normalizer.NormalizationData{OriginalText: second}
+		if err := nd.NormalizeText(); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := c.get(nd); !ok {
+			t.Errorf("%v cache miss", name)
+		}
+		options.Cache = c
+		got, err := IdentifyLicensesInString(second, options, licenseLibrary)
+		if err != nil {
+			t.Fatalf("IdentifyLicensesInString() error = %v", err)
+		}
+		if d := cmp.Diff(want.Matches, got.Matches); d != "" {
+			t.Errorf("%v cache matches mismatch (-want +got):\n%s", name, d)
+		}
+		if d := cmp.Diff(want.Blocks, got.Blocks); d != "" {
+			t.Errorf("%v cache blocks mismatch (-want +got):\n%s", name, d)
+		}
+	}
+}
+
+func TestLibraryFingerprint(t *testing.T) {
+	t.Parallel()
+	library := func(alias string) *licenses.LicenseLibrary {
+		ll, err := licenses.NewLicenseLibrary(nil)
+		if err != nil {
+			t.Fatalf("NewLicenseLibrary() error = %v", err)
+		}
+		ll.LicenseMap["Test"] = licenses.License{Aliases: []string{alias}}
+		return ll
+	}
+	if libraryFingerprint(library("a")) != libraryFingerprint(library("a")) {
+		t.Error("libraryFingerprint() is not stable")
+	}
+	if libraryFingerprint(library("a")) == libraryFingerprint(library("b")) {
+		t.Error("libraryFingerprint() did not change with the library")
+	}
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+)
+
+// The license states of a file or a scan, from the most to the least affirmative
+const (
+	// Licensed is a file or scan with a license match (or a license header match)
+	Licensed = "licensed"
+	// Evidence is a file or scan without a license match, but with license hints or license URLs
+	Evidence = "evidence"
+	// Unlicensed is a file or scan without license evidence, but with an UNLICENSED or All rights reserved declaration
+	Unlicensed = "unlicensed"
+	// Partial is a file or scan without license evidence or a declaration in the part of the file which was scanned
+	// (see Partial), so a license may be in the rest of the file
+	Partial = "partial"
+	// NoLicense is a file or scan without license evidence or a declaration
+	NoLicense = "no-license"
+)
+
+// statusRanks rank the license states (the most affirmative state of the files is the state of a scan)
+var statusRanks = map[string]int{NoLicense: 0, Partial: 1, Unlicensed: 2, Evidence: 3, Licensed: 4}
+
+// declarationRE matches the declarations that the rights are reserved: UNLICENSED (upper case, as in
+// package.json, so the Unlicense is not matched) and All rights reserved (any case)
+var declarationRE = regexp.MustCompile(`\bUNLICENSED\b|(?i:\ball[\s*#/;]+rights[\s*#/;]+reserved\b)`)
+
+// Declaration is an UNLICENSED or All rights reserved declaration in a file without a license match
+type Declaration struct {
+	// Phrase is the text which was found (with the whitespace collapsed)
+	Phrase string
+	Begins int
+	Ends   int
+}
+
+// addDeclarations adds the UNLICENSED and All rights reserved declarations when no license matched (the
+// copyright notices of most licenses also say All rights reserved)
+func addDeclarations(licenseResults *IdentifierResults) {
+	if len(licenseResults.Matches) > 0 || len(licenseResults.HeaderMatches) > 0 {
+		return
+	}
+	for _, loc := range declarationRE.FindAllStringIndex(licenseResults.OriginalText, -1) {
+		licenseResults.Declarations = append(licenseResults.Declarations, Declaration{
+			Phrase: phrase(licenseResults.OriginalText[loc[0]:loc[1]]),
+			Begins: loc[0],
+			Ends:   loc[1] - 1,
+		})
+	}
+}
+
+// Status returns the license state of the file: Licensed, Evidence, Unlicensed, Partial, or NoLicense
+func (r IdentifierResults) Status() string {
+	switch {
+	case len(r.Matches) > 0 || len(r.HeaderMatches) > 0:
+		return Licensed
+	case len(r.Hints) > 0 || len(r.LicenseURLs) > 0:
+		return Evidence
+	case len(r.Declarations) > 0:
+		return Unlicensed
+	case r.Partial():
+		return Partial
+	default:
+		return NoLicense
+	}
+}
+
+// Partial returns true when only a part of the file was scanned (HeaderOnly or Truncated), or when the matching of the
+// file stopped (TimedOut, Canceled, or TimedOutTemplates), so the file may have licenses which were not matched
+func (r IdentifierResults) Partial() bool {
+	return r.HeaderOnly || r.Truncated || r.TimedOut || r.Canceled || len(r.TimedOutTemplates) > 0
+}
+
+// ScanStatus returns the license state of a scan, the most affirmative state of its files (NoLicense
+// when no file was scanned)
+func ScanStatus(results []IdentifierResults) string {
+	ret := NoLicense
+	for _, r := range results {
+		if status := r.Status(); statusRanks[status] > statusRanks[ret] {
+			ret = status
+		}
+	}
+	return ret
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_addDeclarations(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		matches map[string][]Match
+		want    []Declaration
+	}{
+		{
+			name: "all rights reserved",
+			text: "Copyright (c) 2023 Acme Corp. All rights reserved.",
+			want: []Declaration{{Phrase: "All rights reserved", Begins: 30, Ends: 48}},
+		},
+		{
+			name: "split across comment lines",
+			text: "// Copyright 2023 Acme Corp. All\n// Rights Reserved",
+			want: []Declaration{{Phrase: "All Rights Reserved", Begins: 29, Ends: 50}},
+		},
+		{
+			name: "UNLICENSED",
+			text: `{"name": "acme", "license": "UNLICENSED"}`,
+			want: []Declaration{{Phrase: "UNLICENSED", Begins: 29, Ends: 38}},
+		},
+		{
+			name: "not the Unlicense",
+			text: "This is free and unencumbered software released into the public domain (unlicensed).",
+		},
+		{
+			name:    "not with a license match",
+			text:    "Copyright (c) 2023 Acme Corp. All rights reserved. Permission is hereby granted...",
+			matches: map[string][]Match{"MIT": {{Begins: 51, Ends: 80}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results := IdentifierResults{OriginalText: tt.text, Matches: tt.matches}
+			addDeclarations(&results)
+			if d := cmp.Diff(tt.want, results.Declarations); d != "" {
+				t.Errorf("addDeclarations() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestScanStatus(t *testing.T) {
+	t.Parallel()
+	licensed := IdentifierResults{Matches: map[string][]Match{"MIT": {{Begins: 0, Ends: 1000}}}}
+	evidence := IdentifierResults{Hints: []Hint{{ID: "Apache-2.0"}}}
+	urls := IdentifierResults{LicenseURLs: []LicenseURL{{URL: "opensource.org/licenses/MIT", IDs: []string{"MIT"}}}}
+	unlicensed := IdentifierResults{Declarations: []Declaration{{Phrase: "UNLICENSED"}}}
+	headerOnly := IdentifierResults{HeaderOnly: true}
+	truncated := IdentifierResults{Truncated: true}
+	none := IdentifierResults{}
+	tests := []struct {
+		name    string
+		results []IdentifierResults
+		want    string
+	}{
+		{name: "no files", want: NoLicense},
+		{name: "no license", results: []IdentifierResults{none}, want: NoLicense},
+		{name: "header only", results: []IdentifierResults{none, headerOnly}, want: Partial},
+		{name: "truncated", results: []IdentifierResults{truncated, none}, want: Partial},
+		{name: "unlicensed", results: []IdentifierResults{none, unlicensed}, want: Unlicensed},
+		{name: "unlicensed header", results: []IdentifierResults{headerOnly, unlicensed}, want: Unlicensed},
+		{name: "hints", results: []IdentifierResults{unlicensed, evidence}, want: Evidence},
+		{name: "license URLs", results: []IdentifierResults{urls, none}, want: Evidence},
+		{name: "licensed", results: []IdentifierResults{none, unlicensed, licensed, evidence}, want: Licensed},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := ScanStatus(tt.results); got != tt.want {
+				t.Errorf("ScanStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,8 @@ type Enhancements struct {
 	FlagAcceptable bool
 	FlagCopyrights bool
 	FlagKeywords   bool
+	// CaptureVariables adds the text matched by the template variables (e.g., the copyright holder)
+	CaptureVariables bool
 	// FlagKeywords   []string  // TODO: JavaScript used this as a bool and later as a list
 }
 
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// The matching algorithms in a Verdict
+const (
+	AlgorithmTemplate = "template"
+	AlgorithmHash     = "hash"
+	AlgorithmFuzzy    = "fuzzy"
+)
+
+// FuzzySimilarity is the least similarity (Jaccard of the word shingles) of the normalized input and the
+// normalized text of a license for a fuzzy match
+const FuzzySimilarity = 0.8
+
+// Verdict is how the ensemble of matching algorithms detected a license
+type Verdict struct {
+	// MatchedBy are the algorithms which detected the license, in the order template, hash, fuzzy
+	MatchedBy []string
+	// Similarity is the fuzzy similarity of the input and the license text (0 when below FuzzySimilarity)
+	Similarity float64
+}
+
+// String returns the provenance of the verdict, e.g., matched-by: template+hash
+func (v Verdict) String() string {
+	return "matched-by: " + strings.Join(v.MatchedBy, "+")
+}
+
+// Ensemble runs the template matching, the hash matching, and the fuzzy similarity together, and
+// reconciles their verdicts. The template and hash matches are precise, so they are always used.
+// A fuzzy match is only used when neither of them detected a license, and then only the most similar
+// license text (or texts, when equally similar) is used. Fuzzy matches which agree with the other
+// algorithms are added to their provenance.
+type Ensemble struct {
+	// shingles has the word shingles of the normalized text of each license ID
+	shingles map[string]map[uint64]bool
+}
+
+// NewEnsemble prepares the fuzzy similarity with the canonical text of each license in the library
+// (licenses without a text are only matched by the templates and hashes)
+func NewEnsemble(licenseLibrary *licenses.LicenseLibrary) (*Ensemble, error) {
+	e := &Ensemble{shingles: make(map[string]map[uint64]bool)}
+	for id := range licenseLibrary.LicenseMap {
+		text, err := licenseLibrary.CanonicalText(id)
+		if err != nil {
+			Logger.Debugf("No fuzzy similarity for %v: %v", id, err)
+			continue
+		}
+		nd := normalizer.NormalizationData{OriginalText: text}
+		if err := nd.NormalizeText(); err != nil {
+			return nil, err
+		}
+		if s := shingle(nd.NormalizedText); len(s) > 0 {
+			e.shingles[id] = s
+		}
+	}
+	return e, nil
+}
+
+// similar returns the similarity of each license text which is at least FuzzySimilarity
+func (e *Ensemble) similar(normalizedText string) map[string]float64 {
+	s := shingle(normalizedText)
+	ret := make(map[string]float64)
+	for id, licenseShingles := range e.shingles {
+		if similarity := jaccard(s, licenseShingles); similarity >= FuzzySimilarity {
+			ret[id] = similarity
+		}
+	}
+	return ret
+}
+
+// identify finds the licenses with all the algorithms and reconciles the verdicts
+func (e *Ensemble) identify(options Options, licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData, limits *matchLimits) (IdentifierResults, error) {
+	var ret IdentifierResults
+	var err error
+	if options.Cache != nil {
+		ret, err = findAllLicensesWithCache(options.Cache, licenseLibrary, normalizedData, limits)
+	} else {
+		ret, err = findAllLicensesInNormalizedData(licenseLibrary, normalizedData, limits)
+	}
+	if err != nil {
+		return ret, err
+	}
+
+	verdicts := make(map[string]Verdict)
+	add := func(id string, algorithm string) {
+		v := verdicts[id]
+		v.MatchedBy = append(v.MatchedBy, algorithm)
+		verdicts[id] = v
+	}
+	for id := range ret.Matches {
+		add(id, AlgorithmTemplate)
+	}
+	wholeFile := []Match{{Begins: 0, Ends: len(normalizedData.OriginalText) - 1}}
+	matches := make(map[string][]Match, len(ret.Matches))
+	for id, m := range ret.Matches {
+		matches[id] = m // a copy, because the template matches may be cached
+	}
+	added := false
+	for _, id := range licenseLibrary.ExactHashMap[normalizedData.Hash.Sha256] {
+		add(id, AlgorithmHash)
+		if _, ok := matches[id]; !ok {
+			matches[id], added = wholeFile, true
+		}
+	}
+
+	similar := e.similar(normalizedData.NormalizedText)
+	if len(verdicts) == 0 {
+		for _, id := range mostSimilar(similar) {
+			matches[id], added = wholeFile, true
+			verdicts[id] = Verdict{}
+		}
+	}
+	for id, v := range verdicts {
+		if similarity, ok := similar[id]; ok {
+			v.MatchedBy = append(v.MatchedBy, AlgorithmFuzzy)
+			v.Similarity = similarity
+			verdicts[id] = v
+		}
+	}
+
+	if added {
+		// Regenerate the text blocks with the whole file matches
+		if ret, err = resultsFromMatches(matches, normalizedData); err != nil {
+			return ret, err
+		}
+	}
+	ret.Verdicts = verdicts
+	return ret, nil
+}
+
+// mostSimilar returns the license IDs with the highest similarity (sorted)
+func mostSimilar(similar map[string]float64) []string {
+	best := 0.0
+	for _, similarity := range similar {
+		if similarity > best {
+			best = similarity
+		}
+	}
+	var ret []string
+	for id, similarity := range similar {
+		if similarity == best {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// pruneVerdicts removes the verdicts of the license IDs which are no longer matched (e.g., after the mutators)
+func pruneVerdicts(licenseResults *IdentifierResults) {
+	for id := range licenseResults.Verdicts {
+		if _, ok := licenseResults.Matches[id]; !ok {
+			delete(licenseResults.Verdicts, id)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_identifyLicensesEnsemble(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	ensemble, err := NewEnsemble(licenseLibrary)
+	if err != nil {
+		t.Fatalf("NewEnsemble() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "ISC.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	isc := string(b)
+	// Drop a few words, so that the template and the hash no longer match
+	words := strings.Fields(isc)
+	edited := strings.Join(append(words[:len(words)/2:len(words)/2], words[len(words)/2+3:]...), " ")
+
+	tests := []struct {
+		name          string
+		input         string
+		wantMatchedBy map[string][]string
+	}{
+		{name: "verbatim", input: isc, wantMatchedBy: map[string][]string{"ISC": {AlgorithmTemplate, AlgorithmHash, AlgorithmFuzzy}}},
+		{name: "edited", input: edited, wantMatchedBy: map[string][]string{"ISC": {AlgorithmFuzzy}}},
+		{name: "no license", input: "This is a readme, not a license.", wantMatchedBy: map[string][]string{}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options := defaultOptions()
+			options.Ensemble = ensemble
+			got, err := IdentifyLicensesInString(tt.input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			gotMatchedBy := make(map[string][]string)
+			for id, v := range got.Verdicts {
+				gotMatchedBy[id] = v.MatchedBy
+				if _, ok := got.Matches[id]; !ok {
+					t.Errorf("verdict for %v without a match", id)
+				}
+			}
+			if d := cmp.Diff(tt.wantMatchedBy, gotMatchedBy); d != "" {
+				t.Errorf("Verdicts mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestVerdict_String(t *testing.T) {
+	t.Parallel()
+	v := Verdict{MatchedBy: []string{AlgorithmTemplate, AlgorithmHash}}
+	if got, want := v.String(), "matched-by: template+hash"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func Test_mostSimilar(t *testing.T) {
+	t.Parallel()
+	got := mostSimilar(map[string]float64{"GPL-2.0-only": 0.9, "GPL-2.0": 0.9, "LGPL-2.0-only": 0.85})
+	if d := cmp.Diff([]string{"GPL-2.0", "GPL-2.0-only"}, got); d != "" {
+		t.Errorf("mostSimilar() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// addExceptions combines each matched exception (e.g., Classpath-exception-2.0) with the licenses it
+// applies to in the same file. An exception applies to the nearest licenses matched before it (or after
+// it, when no license is matched before it). The licenses with the same nearest match (e.g., GPL-2.0-only
+// and GPL-2.0-or-later) all get the exception. A license gets at most one exception (the first one), and
+// the deprecated licenses with an exception (e.g., GPL-2.0-with-classpath-exception) get none.
+func addExceptions(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults) {
+	var exceptionIDs, licenseIDs []string
+	for id := range licenseResults.Matches {
+		if licenseLibrary.LicenseMap[id].LicenseInfo.SPDXException {
+			exceptionIDs = append(exceptionIDs, id)
+		} else if r, _ := licenses.DeprecatedReplacement(id); !strings.Contains(r, " "+licenses.With+" ") {
+			// not a deprecated license with an exception (e.g., GPL-2.0-with-classpath-exception)
+			licenseIDs = append(licenseIDs, id)
+		}
+	}
+	if len(exceptionIDs) == 0 || len(licenseIDs) == 0 {
+		return
+	}
+	begins := func(id string) int {
+		ret := -1
+		for _, m := range licenseResults.Matches[id] {
+			if ret == -1 || m.Begins < ret {
+				ret = m.Begins
+			}
+		}
+		return ret
+	}
+	sort.Slice(exceptionIDs, func(i, j int) bool {
+		bi, bj := begins(exceptionIDs[i]), begins(exceptionIDs[j])
+		if bi != bj {
+			return bi < bj
+		}
+		return exceptionIDs[i] < exceptionIDs[j]
+	})
+
+	for _, exceptionID := range exceptionIDs {
+		exceptionBegins := begins(exceptionID)
+		before, after := -1, -1
+		for _, id := range licenseIDs {
+			if _, ok := licenseResults.Exceptions[id]; ok {
+				continue
+			}
+			for _, m := range licenseResults.Matches[id] {
+				if m.Begins <= exceptionBegins && m.Begins > before {
+					before = m.Begins
+				} else if m.Begins > exceptionBegins && (after == -1 || m.Begins < after) {
+					after = m.Begins
+				}
+			}
+		}
+		nearest := before
+		if nearest == -1 {
+			nearest = after
+		}
+		if nearest == -1 {
+			continue
+		}
+		for _, id := range licenseIDs {
+			if _, ok := licenseResults.Exceptions[id]; ok {
+				continue
+			}
+			for _, m := range licenseResults.Matches[id] {
+				if m.Begins == nearest {
+					if licenseResults.Exceptions == nil {
+						licenseResults.Exceptions = make(map[string]string)
+					}
+					licenseResults.Exceptions[id] = exceptionID
+					break
+				}
+			}
+		}
+	}
+}
+
+// Expressions returns the detected licenses as SPDX expressions (sorted), with the license IDs and their
+// exceptions combined (e.g., GPL-2.0-only WITH Classpath-exception-2.0) instead of separate IDs. The
+// exceptions which were not combined with a license are kept. The mapped license IDs are their Mappings.
+func (r IdentifierResults) Expressions() []string {
+	combined := make(map[string]bool)
+	for _, exceptionID := range r.Exceptions {
+		combined[exceptionID] = true
+	}
+	var ret []string
+	for id := range r.Matches {
+		if exceptionID, ok := r.Exceptions[id]; ok {
+			ret = append(ret, r.mapped(id)+" "+licenses.With+" "+r.mapped(exceptionID))
+		} else if !combined[id] {
+			ret = append(ret, r.Mapped(id))
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Expression returns the SPDX expression of a matched license ID in the Expressions: its Mapped expression with
+// its exception (if any). An exception which was combined with a license has the expression of that license.
+func (r IdentifierResults) Expression(id string) string {
+	if _, ok := r.Exceptions[id]; !ok {
+		var combinedWith []string
+		for licenseID, exceptionID := range r.Exceptions {
+			if exceptionID == id {
+				combinedWith = append(combinedWith, licenseID)
+			}
+		}
+		if len(combinedWith) > 0 {
+			sort.Strings(combinedWith)
+			id = combinedWith[0]
+		}
+	}
+	if exceptionID, ok := r.Exceptions[id]; ok {
+		return r.mapped(id) + " " + licenses.With + " " + r.mapped(exceptionID)
+	}
+	return r.Mapped(id)
+}
+
+// Mapped returns the SPDX expression of a license ID: its mapping (see Mappings), or else the ID
+func (r IdentifierResults) Mapped(id string) string {
+	if mapped, ok := r.Mappings[id]; ok {
+		return mapped
+	}
+	return id
+}
+
+// mapped returns the Mapped expression as an operand of WITH (in parentheses when it is compound)
+func (r IdentifierResults) mapped(id string) string {
+	mapped := r.Mapped(id)
+	if strings.Contains(mapped, " ") {
+		return "(" + mapped + ")"
+	}
+	return mapped
+}
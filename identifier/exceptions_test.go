@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_addExceptions(t *testing.T) {
+	t.Parallel()
+	exception := licenses.License{LicenseInfo: licenses.LicenseInfo{SPDXException: true}}
+	licenseLibrary := &licenses.LicenseLibrary{LicenseMap: licenses.LicenseMap{
+		"GPL-2.0-only":                     {},
+		"GPL-2.0-or-later":                 {},
+		"GPL-2.0-with-classpath-exception": {},
+		"MIT":                              {},
+		"Apache-2.0":                       {},
+		"ACME-EULA-2":                      {},
+		"ACME-GPL":                         {},
+		"Classpath-exception-2.0":          exception,
+		"LLVM-exception":                   exception,
+	}}
+	tests := []struct {
+		name            string
+		matches         map[string][]Match
+		mappings        map[string]string
+		wantExceptions  map[string]string
+		wantExpressions []string
+	}{
+		{
+			name: "license and exception",
+			matches: map[string][]Match{
+				"GPL-2.0-only":            {{Begins: 0, Ends: 17000}},
+				"GPL-2.0-or-later":        {{Begins: 0, Ends: 17000}},
+				"Classpath-exception-2.0": {{Begins: 17010, Ends: 18000}},
+			},
+			wantExceptions:  map[string]string{"GPL-2.0-only": "Classpath-exception-2.0", "GPL-2.0-or-later": "Classpath-exception-2.0"},
+			wantExpressions: []string{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-or-later WITH Classpath-exception-2.0"},
+		},
+		{
+			name: "nearest license before the exception",
+			matches: map[string][]Match{
+				"MIT":            {{Begins: 0, Ends: 1000}},
+				"Apache-2.0":     {{Begins: 1010, Ends: 11000}},
+				"LLVM-exception": {{Begins: 11010, Ends: 12000}},
+			},
+			wantExceptions:  map[string]string{"Apache-2.0": "LLVM-exception"},
+			wantExpressions: []string{"Apache-2.0 WITH LLVM-exception", "MIT"},
+		},
+		{
+			name: "exception before the license",
+			matches: map[string][]Match{
+				"LLVM-exception": {{Begins: 0, Ends: 1000}},
+				"Apache-2.0":     {{Begins: 1010, Ends: 11000}},
+			},
+			wantExceptions:  map[string]string{"Apache-2.0": "LLVM-exception"},
+			wantExpressions: []string{"Apache-2.0 WITH LLVM-exception"},
+		},
+		{
+			name: "one exception per license",
+			matches: map[string][]Match{
+				"Apache-2.0":              {{Begins: 0, Ends: 10000}},
+				"LLVM-exception":          {{Begins: 10010, Ends: 11000}},
+				"Classpath-exception-2.0": {{Begins: 11010, Ends: 12000}},
+			},
+			wantExceptions:  map[string]string{"Apache-2.0": "LLVM-exception"},
+			wantExpressions: []string{"Apache-2.0 WITH LLVM-exception", "Classpath-exception-2.0"},
+		},
+		{
+			name: "deprecated license with an exception",
+			matches: map[string][]Match{
+				"GPL-2.0-with-classpath-exception": {{Begins: 0, Ends: 18000}},
+				"Classpath-exception-2.0":          {{Begins: 17010, Ends: 18000}},
+			},
+			wantExpressions: []string{"Classpath-exception-2.0", "GPL-2.0-with-classpath-exception"},
+		},
+		{
+			name:            "only an exception",
+			matches:         map[string][]Match{"Classpath-exception-2.0": {{Begins: 0, Ends: 1000}}},
+			wantExpressions: []string{"Classpath-exception-2.0"},
+		},
+		{
+			name: "mapped licenses",
+			matches: map[string][]Match{
+				"MIT":         {{Begins: 0, Ends: 1000}},
+				"ACME-EULA-2": {{Begins: 1010, Ends: 3000}},
+			},
+			mappings:        map[string]string{"ACME-EULA-2": "LicenseRef-acme-EULA-2"},
+			wantExpressions: []string{"LicenseRef-acme-EULA-2", "MIT"},
+		},
+		{
+			name: "mapped license with an exception",
+			matches: map[string][]Match{
+				"ACME-GPL":                {{Begins: 0, Ends: 17000}},
+				"Classpath-exception-2.0": {{Begins: 17010, Ends: 18000}},
+			},
+			mappings:        map[string]string{"ACME-GPL": "GPL-2.0-only OR GPL-3.0-only"},
+			wantExceptions:  map[string]string{"ACME-GPL": "Classpath-exception-2.0"},
+			wantExpressions: []string{"(GPL-2.0-only OR GPL-3.0-only) WITH Classpath-exception-2.0"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := IdentifierResults{Matches: tt.matches, Mappings: tt.mappings}
+			addExceptions(licenseLibrary, &result)
+			if d := cmp.Diff(tt.wantExceptions, result.Exceptions); d != "" {
+				t.Errorf("addExceptions() mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.wantExpressions, result.Expressions()); d != "" {
+				t.Errorf("Expressions() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"strings"
+)
+
+// extractedLicenseHashLength is how many hex digits of the SHA-256 of the normalized text are in the LicenseRef
+const extractedLicenseHashLength = 16
+
+// ExtractedLicense is the license-like text of a file which matched no license, with a LicenseRef to refer to it
+// (like the other licensing information of an SPDX document)
+type ExtractedLicense struct {
+	// ID is LicenseRef-<hash>, with the start of the SHA-256 of the normalized text, so the same text (with any
+	// whitespace and case) has the same ID in every scan
+	ID string
+	// Text is the extracted text of the file
+	Text string
+}
+
+// ExtractedLicenseRef returns the LicenseRef of the license-like text with the SHA-256 of its normalized text
+func ExtractedLicenseRef(sha256 string) string {
+	if len(sha256) > extractedLicenseHashLength {
+		sha256 = sha256[:extractedLicenseHashLength]
+	}
+	return "LicenseRef-" + sha256
+}
+
+// addExtractedLicense sets the ExtractedLicense of a text which looks like a license (see IsUnknownLicense) but
+// matched no license or license header, unless the results are partial (the whole text may have matched). It is set
+// again when the results turn out to be partial after the matching (e.g., HeaderOnly).
+func addExtractedLicense(licenseResults *IdentifierResults, sha256 string) {
+	licenseResults.ExtractedLicense = nil
+	if !IsUnknownLicense(*licenseResults) || len(licenseResults.HeaderMatches) > 0 || sha256 == "" || licenseResults.Partial() {
+		return
+	}
+	licenseResults.ExtractedLicense = &ExtractedLicense{
+		ID:   ExtractedLicenseRef(sha256),
+		Text: strings.TrimSpace(licenseResults.OriginalText),
+	}
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_addExtractedLicense(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	mitText, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	eula := "ACME End User License\n\nCopyright ACME Corp. Permission is granted to use this software internally.\n" +
+		"Redistribution is prohibited. The software comes without warranty.\n"
+	// The same text with other whitespace and case
+	eulaReformatted := "  acme end user license\n\ncopyright acme corp.  permission is granted to use this software\ninternally. " +
+		"redistribution is prohibited.  the software comes without warranty.\n"
+
+	tests := []struct {
+		name          string
+		input         string
+		wantExtracted bool
+	}{
+		{name: "license-like text", input: eula, wantExtracted: true},
+		{name: "matched license", input: string(mitText), wantExtracted: false},
+		{name: "not license-like", input: "package main\n\nfunc main() {}\n", wantExtracted: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := IdentifyLicensesInString(tt.input, Options{}, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if (got.ExtractedLicense != nil) != tt.wantExtracted {
+				t.Fatalf("IdentifyLicensesInString() ExtractedLicense = %+v, want extracted %v", got.ExtractedLicense, tt.wantExtracted)
+			}
+			if !tt.wantExtracted {
+				return
+			}
+			if !regexp.MustCompile(`^LicenseRef-[0-9a-f]{16}$`).MatchString(got.ExtractedLicense.ID) {
+				t.Errorf("ExtractedLicense.ID = %v, want LicenseRef-<hash>", got.ExtractedLicense.ID)
+			}
+			if got.ExtractedLicense.Text != "ACME End User License\n\nCopyright ACME Corp. Permission is granted to use this software internally.\nRedistribution is prohibited. The software comes without warranty." {
+				t.Errorf("ExtractedLicense.Text = %q", got.ExtractedLicense.Text)
+			}
+			again, err := IdentifyLicensesInString(eulaReformatted, Options{}, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if again.ExtractedLicense == nil || again.ExtractedLicense.ID != got.ExtractedLicense.ID {
+				t.Errorf("ExtractedLicense of the reformatted text = %+v, want the ID %v", again.ExtractedLicense, got.ExtractedLicense.ID)
+			}
+		})
+	}
+}
+
+func Test_addExtractedLicensePartial(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	eula := "ACME End User License\n\nCopyright ACME Corp. Permission is granted to use this software internally.\n" +
+		"Redistribution is prohibited. The software comes without warranty.\n"
+
+	// The license-like text with a long line of minified code, and at the top of a file larger than the largest file
+	// to scan (so only its header is scanned with the Quick option)
+	truncated, err := IdentifyLicensesInString(eula+strings.Repeat("var a=b(c);", MaxLineLength/10)+"\n", Options{}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+	if !truncated.Truncated || truncated.ExtractedLicense != nil {
+		t.Errorf("IdentifyLicensesInString() Truncated = %v, ExtractedLicense = %+v, want truncated without an extracted license", truncated.Truncated, truncated.ExtractedLicense)
+	}
+
+	filePath := filepath.Join(t.TempDir(), "eula.go")
+	if err := os.WriteFile(filePath, []byte(eula+strings.Repeat("x := 1\n", MaxFileSize/7+1)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	headerOnly, err := IdentifyLicensesInFile(filePath, Options{Quick: true}, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+	}
+	if !headerOnly.HeaderOnly || headerOnly.ExtractedLicense != nil {
+		t.Errorf("IdentifyLicensesInFile() HeaderOnly = %v, ExtractedLicense = %+v, want header only without an extracted license", headerOnly.HeaderOnly, headerOnly.ExtractedLicense)
+	}
+}
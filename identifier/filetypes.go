@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+)
+
+// sniffLength is how many bytes of a file are sniffed for its type (as http.DetectContentType)
+const sniffLength = 512
+
+// skippedMediaTypes are the media types (the prefixes of the MIME types) of the files which are skipped
+var skippedMediaTypes = []string{"image/", "audio/", "video/", "font/"}
+
+// skippedMIMETypes are the other MIME types of the files which are skipped: archives, documents, and binaries
+// (application/octet-stream is any content with binary bytes)
+var skippedMIMETypes = map[string]bool{
+	"application/ogg":               true,
+	"application/pdf":               true,
+	"application/zip":               true,
+	"application/x-gzip":            true,
+	"application/x-rar-compressed":  true,
+	"application/vnd.ms-fontobject": true,
+	"application/wasm":              true,
+	"application/octet-stream":      true,
+}
+
+// binaryMIMETypes are the MIME types of the extractor.BinaryFormat of the executables and libraries
+var binaryMIMETypes = map[string]string{
+	extractor.ELF:   "application/x-executable",
+	extractor.PE:    "application/vnd.microsoft.portable-executable",
+	extractor.MachO: "application/x-mach-binary",
+}
+
+// SkipFileType sniffs the type of the file from its magic bytes, and returns its MIME type and true when it is
+// obviously a binary or media file (e.g., an image, an archive, or an executable without the binaries option)
+// with no license text to scan
+func SkipFileType(filePath string, binaries bool) (mimeType string, skip bool, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", false, err
+	}
+	defer f.Close()
+	header := make([]byte, sniffLength)
+	n, err := io.ReadFull(f, header)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", false, err
+	}
+	mimeType, skip = skipFileType(header[:n], binaries)
+	return mimeType, skip, nil
+}
+
+// skipFileType returns the MIME type of the first bytes of a file, and true when the file is skipped
+func skipFileType(header []byte, binaries bool) (string, bool) {
+	mimeType, _, _ := strings.Cut(http.DetectContentType(header), ";")
+	if format := extractor.BinaryFormat(header); format != "" && mimeType == "application/octet-stream" {
+		return binaryMIMETypes[format], !binaries
+	}
+	if skippedMIMETypes[mimeType] {
+		return mimeType, true
+	}
+	for _, prefix := range skippedMediaTypes {
+		if strings.HasPrefix(mimeType, prefix) {
+			return mimeType, true
+		}
+	}
+	return mimeType, false
+}
@@ -0,0 +1,111 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_skipFileType(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name         string
+		header       string
+		binaries     bool
+		wantMIMEType string
+		wantSkip     bool
+	}{
+		{name: "text", header: "MIT License\n\nCopyright (c) ACME", wantMIMEType: "text/plain"},
+		{name: "HTML", header: "<!DOCTYPE html><html>", wantMIMEType: "text/html"},
+		{name: "text starting with MZ", header: "MZ is the signature of a DOS executable", wantMIMEType: "text/plain"},
+		{name: "PNG", header: "\x89PNG\x0d\x0a\x1a\x0a\x00\x00\x00\x0dIHDR", wantMIMEType: "image/png", wantSkip: true},
+		{name: "MP3", header: "ID3\x03\x00\x00\x00\x00\x00\x00", wantMIMEType: "audio/mpeg", wantSkip: true},
+		{name: "zip", header: "PK\x03\x04\x14\x00\x00\x00", wantMIMEType: "application/zip", wantSkip: true},
+		{name: "PDF", header: "%PDF-1.7\n%\xe2\xe3\xcf\xd3", wantMIMEType: "application/pdf", wantSkip: true},
+		{name: "binary data", header: "\x00\x01\x02\x03\xff\xfe", wantMIMEType: "application/octet-stream", wantSkip: true},
+		{name: "ELF", header: "\x7fELF\x02\x01\x01\x00\x00\x00", wantMIMEType: "application/x-executable", wantSkip: true},
+		{name: "ELF with binaries", header: "\x7fELF\x02\x01\x01\x00\x00\x00", binaries: true, wantMIMEType: "application/x-executable"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			mimeType, skip := skipFileType([]byte(tt.header), tt.binaries)
+			if mimeType != tt.wantMIMEType || skip != tt.wantSkip {
+				t.Errorf("skipFileType() = %v, %v, want %v, %v", mimeType, skip, tt.wantMIMEType, tt.wantSkip)
+			}
+		})
+	}
+}
+
+func Test_walkFilesSkipsFileTypes(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"LICENSE":  "MIT License",
+		"logo.png": "\x89PNG\x0d\x0a\x1a\x0a\x00\x00\x00\x0dIHDR",
+		"app":      "\x7fELF\x02\x01\x01\x00\x00\x00",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		allFiles    bool
+		binaries    bool
+		wantFiles   []string
+		wantSkipped map[string]int
+	}{
+		{
+			name:        "skip",
+			wantFiles:   []string{"LICENSE"},
+			wantSkipped: map[string]int{"application/x-executable": 1, "image/png": 1},
+		},
+		{
+			name:        "binaries",
+			binaries:    true,
+			wantFiles:   []string{"LICENSE", "app"},
+			wantSkipped: map[string]int{"image/png": 1},
+		},
+		{
+			name:      "all files",
+			allFiles:  true,
+			wantFiles: []string{"LICENSE", "app", "logo.png"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var skipped map[string]int
+			options := WalkOptions{AllFiles: tt.allFiles, OnSkipped: func(_ string, mimeType string) {
+				if skipped == nil {
+					skipped = make(map[string]int)
+				}
+				skipped[mimeType]++
+			}}
+			files, _, err := walkFiles(dir, options, tt.binaries)
+			if err != nil {
+				t.Fatalf("walkFiles() error = %v", err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, filepath.Base(f))
+			}
+			if d := cmp.Diff(tt.wantFiles, got); d != "" {
+				t.Errorf("walkFiles() files mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.wantSkipped, skipped); d != "" {
+				t.Errorf("walkFiles() skipped mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
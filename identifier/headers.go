@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"sort"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// addHeaderMatches adds the matches of the standard license headers (e.g., the "This program is free
+// software..." notice of the GPL at the top of a source file) as the HeaderMatches. A header in a license
+// match of the same license (e.g., in the How to Apply appendix of the full text) is part of that match.
+func addHeaderMatches(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults, normalizedData normalizer.NormalizationData, limits *matchLimits) error {
+	inMatch := func(id string, m Match) bool {
+		for _, lm := range licenseResults.Matches[id] {
+			if m.Begins >= lm.Begins && m.Ends <= lm.Ends {
+				return true
+			}
+		}
+		return false
+	}
+	for id, lic := range licenseLibrary.LicenseMap {
+		var found []Match
+		for _, pattern := range lic.HeaderPatterns {
+			matches, err := limits.findMatchingPattern(pattern, normalizedData)
+			if err != nil {
+				return err
+			}
+			for _, m := range matches {
+				if !inMatch(id, m) {
+					found = append(found, m)
+				}
+			}
+		}
+		if len(found) == 0 {
+			continue
+		}
+		sort.Slice(found, func(i, j int) bool {
+			if found[i].Begins != found[j].Begins {
+				return found[i].Begins < found[j].Begins
+			}
+			return found[i].Ends < found[j].Ends
+		})
+		if licenseResults.HeaderMatches == nil {
+			licenseResults.HeaderMatches = make(map[string][]Match)
+		}
+		for i, m := range found {
+			if i == 0 || m != found[i-1] {
+				licenseResults.HeaderMatches[id] = append(licenseResults.HeaderMatches[id], m)
+			}
+		}
+	}
+	return nil
+}
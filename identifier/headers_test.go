@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_identifyLicenseHeaders(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	gplText, err := os.ReadFile(path.Join(testDataDir, "GPL-2.0-or-later.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gplHeader := `// Copyright (C) 2001 ACME Inc.
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 59 Temple Place, Suite 330, Boston, MA  02111-1307  USA
+
+package main
+`
+	mplHeader := `# This Source Code Form is subject to the terms of the Mozilla Public
+# License, v. 2.0. If a copy of the MPL was not distributed with this
+# file, You can obtain one at https://mozilla.org/MPL/2.0/.
+
+import os
+`
+	tests := []struct {
+		name        string
+		input       string
+		headers     bool
+		wantHeaders []string
+		wantStatus  string
+	}{
+		{name: "GPL header", input: gplHeader, headers: true, wantHeaders: []string{"GPL-2.0-or-later"}, wantStatus: Licensed},
+		{name: "MPL header", input: mplHeader, headers: true, wantHeaders: []string{"MPL-2.0"}, wantStatus: Licensed},
+		{name: "headers not matched by default", input: mplHeader},
+		{name: "header in the full text", input: string(gplText), headers: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options := defaultOptions()
+			options.Headers = tt.headers
+			got, err := IdentifyLicensesInString(tt.input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			var gotHeaders []string
+			for id, matches := range got.HeaderMatches {
+				gotHeaders = append(gotHeaders, id)
+				for _, m := range matches {
+					if m.Begins < 0 || m.Ends >= len(tt.input) || m.Begins > m.Ends {
+						t.Errorf("IdentifyLicensesInString() %v header match %v is out of the input", id, m)
+					}
+				}
+			}
+			sort.Strings(gotHeaders)
+			if d := cmp.Diff(tt.wantHeaders, gotHeaders); d != "" {
+				t.Errorf("IdentifyLicensesInString() HeaderMatches mismatch (-want +got):\n%s", d)
+			}
+			if tt.wantStatus != "" && got.Status() != tt.wantStatus {
+				t.Errorf("IdentifyLicensesInString() Status() = %v, want %v", got.Status(), tt.wantStatus)
+			}
+		})
+	}
+}
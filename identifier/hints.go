@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Hint is a low-confidence guess of a license from a telltale phrase (e.g., "licensed under the Apache
+// License"), for a file in which no license matched. It is not a license match.
+type Hint struct {
+	// ID is the SPDX ID the phrase refers to
+	ID string
+	// Phrase is the text which was found (with the whitespace collapsed)
+	Phrase string
+	Begins int
+	Ends   int
+}
+
+// hintRule finds a telltale phrase and returns the license ID for the submatches of the phrase
+type hintRule struct {
+	re *regexp.Regexp
+	id func(submatches []string) string
+}
+
+// fixed returns a hintRule ID function for a phrase which always refers to the same license
+func fixed(id string) func([]string) string {
+	return func([]string) string { return id }
+}
+
+// phraseRE compiles a telltale phrase regex (case insensitive) in which each space matches any whitespace
+// and comment markers between the words (so a phrase is found across the lines of a comment)
+func phraseRE(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)` + strings.ReplaceAll(pattern, " ", `[\s*#/;]+`))
+}
+
+// hintRules are the telltale phrases. The phrases with a version come before the phrases without one.
+var hintRules = []hintRule{
+	{
+		// GNU General Public License version 2 (or later), and the Lesser, Library, and Affero GPL
+		re: phraseRE(`\bgnu (lesser |library |affero )?general public licen[cs]e\b[^.;]{0,80}?\bv(?:ersion)?\.?\s*(\d)(?:\.(\d))?(?: of the licen[cs]e)?(,? or (?:\(at your option\) )?(?:any )?later)?`),
+		id: func(m []string) string {
+			prefix := "GPL-"
+			switch strings.ToLower(strings.TrimSpace(m[1])) {
+			case "lesser", "library":
+				prefix = "LGPL-"
+			case "affero":
+				prefix = "AGPL-"
+			}
+			minor := m[3]
+			if minor == "" {
+				minor = "0"
+			}
+			if m[4] != "" {
+				return prefix + m[2] + "." + minor + "-or-later"
+			}
+			return prefix + m[2] + "." + minor + "-only"
+		},
+	},
+	{
+		re: phraseRE(`\bapache licen[cs]e,? v(?:ersion)?\.?\s*([12]\.[01])`),
+		id: func(m []string) string { return "Apache-" + m[1] },
+	},
+	{re: phraseRE(`\blicen[cs]ed under the apache licen[cs]e\b`), id: fixed("Apache-2.0")},
+	{
+		re: phraseRE(`\bmozilla public licen[cs]e,? v(?:ersion)?\.?\s*(1\.[01]|2\.0)`),
+		id: func(m []string) string { return "MPL-" + m[1] },
+	},
+	{
+		re: phraseRE(`\beclipse public licen[cs]e,? v(?:ersion)?\.?\s*([12]\.0)`),
+		id: func(m []string) string { return "EPL-" + m[1] },
+	},
+	{
+		re: phraseRE(`\bbsd[\s-]?([234])[\s-]clause\b`),
+		id: func(m []string) string { return "BSD-" + m[1] + "-Clause" },
+	},
+	{re: phraseRE(`\b(?:new|revised|modified) bsd licen[cs]e\b`), id: fixed("BSD-3-Clause")},
+	{re: phraseRE(`\bsimplified bsd licen[cs]e\b`), id: fixed("BSD-2-Clause")},
+	{re: phraseRE(`\blicen[cs]ed under the mit\b`), id: fixed("MIT")},
+	{re: phraseRE(`\bisc licen[cs]e\b`), id: fixed("ISC")},
+	{re: phraseRE(`\bcc0\b|\bcreative commons zero\b`), id: fixed("CC0-1.0")},
+	{re: phraseRE(`\bthe unlicense\b`), id: fixed("Unlicense")},
+}
+
+// addHints adds the hints of the telltale phrases when no license matched. A phrase which overlaps a
+// phrase of an earlier rule is not a hint.
+func addHints(licenseResults *IdentifierResults) {
+	if len(licenseResults.Matches) > 0 || len(licenseResults.HeaderMatches) > 0 {
+		return
+	}
+	var hints []Hint
+	overlaps := func(begins, ends int) bool {
+		for _, h := range hints {
+			if begins <= h.Ends && ends >= h.Begins {
+				return true
+			}
+		}
+		return false
+	}
+	for _, rule := range hintRules {
+		for _, loc := range rule.re.FindAllStringSubmatchIndex(licenseResults.OriginalText, -1) {
+			if overlaps(loc[0], loc[1]-1) {
+				continue
+			}
+			submatches := make([]string, len(loc)/2)
+			for i := range submatches {
+				if loc[2*i] >= 0 {
+					submatches[i] = licenseResults.OriginalText[loc[2*i]:loc[2*i+1]]
+				}
+			}
+			hints = append(hints, Hint{
+				ID:     rule.id(submatches),
+				Phrase: phrase(submatches[0]),
+				Begins: loc[0],
+				Ends:   loc[1] - 1,
+			})
+		}
+	}
+	sort.Slice(hints, func(i, j int) bool { return hints[i].Begins < hints[j].Begins })
+	licenseResults.Hints = hints
+}
+
+// commentMarkerRE matches the words which are only comment markers (e.g., the "*" of a C comment line)
+var commentMarkerRE = regexp.MustCompile(`^(?:[*#;]+|//+|--+)$`)
+
+// phrase returns the text of a telltale phrase with the whitespace collapsed and the comment markers removed
+func phrase(text string) string {
+	var words []string
+	for _, w := range strings.Fields(text) {
+		if !commentMarkerRE.MatchString(w) {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, " ")
+}
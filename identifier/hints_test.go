@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_addHints(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		matches map[string][]Match
+		want    []Hint
+	}{
+		{
+			name: "apache without a version",
+			text: "This project is licensed under the Apache License - see the LICENSE file.",
+			want: []Hint{{ID: "Apache-2.0", Phrase: "licensed under the Apache License", Begins: 16, Ends: 48}},
+		},
+		{
+			name: "lesser GPL or later in a comment",
+			text: "/*\n * under the terms of the GNU Lesser General Public License\n * as published by the Free Software Foundation, either version 2.1\n * of the License, or (at your option) any later version.\n */",
+			want: []Hint{{
+				ID:     "LGPL-2.1-or-later",
+				Phrase: "GNU Lesser General Public License as published by the Free Software Foundation, either version 2.1 of the License, or (at your option) any later",
+				Begins: 29,
+				Ends:   178,
+			}},
+		},
+		{
+			name: "GPL only",
+			text: "Distributed under the GNU General Public License, version 2.",
+			want: []Hint{{ID: "GPL-2.0-only", Phrase: "GNU General Public License, version 2", Begins: 22, Ends: 58}},
+		},
+		{
+			name: "several phrases",
+			text: "Dual licensed under the MIT or the Mozilla Public License, v. 2.0",
+			want: []Hint{
+				{ID: "MIT", Phrase: "licensed under the MIT", Begins: 5, Ends: 26},
+				{ID: "MPL-2.0", Phrase: "Mozilla Public License, v. 2.0", Begins: 35, Ends: 64},
+			},
+		},
+		{
+			name: "BSD variants",
+			text: "Uses the simplified BSD license and BSD-3-Clause code.",
+			want: []Hint{
+				{ID: "BSD-2-Clause", Phrase: "simplified BSD license", Begins: 9, Ends: 30},
+				{ID: "BSD-3-Clause", Phrase: "BSD-3-Clause", Begins: 36, Ends: 47},
+			},
+		},
+		{
+			name:    "no hints when a license matched",
+			text:    "licensed under the Apache License",
+			matches: map[string][]Match{"Apache-2.0": {{Begins: 0, Ends: 10}}},
+		},
+		{
+			name: "no telltale phrase",
+			text: "The license is in the LICENSE file.",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := IdentifierResults{OriginalText: tt.text, Matches: tt.matches}
+			addHints(&r)
+			if d := cmp.Diff(tt.want, r.Hints); d != "" {
+				t.Errorf("addHints() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -3,20 +3,23 @@
 package identifier
 
 import (
+	"context"
 	"fmt"
-	"io/fs"
 	"io/ioutil"
 	"os"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
 	"sync"
+	"time"
+	"unicode"
 
 	"github.com/mrutkows/sbom-utility/log"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/language"
 	"github.com/IBM/license-scanner/licenses"
 	"github.com/IBM/license-scanner/normalizer"
 )
@@ -27,8 +30,37 @@ var (
 )
 
 type Options struct {
-	ForceResult  bool
-	OmitBlocks   bool
+	ForceResult bool
+	OmitBlocks  bool
+	// NoExactHash disables the exact-match fast path, so the license patterns are always used
+	NoExactHash bool
+	// Cache reuses the matches for the same normalized text (IdentifyLicensesInDirectory uses an in-memory cache by default)
+	Cache *ResultCache
+	// TemplateTimeout aborts a single template match which takes longer (0 for no timeout)
+	TemplateTimeout time.Duration
+	// FileTimeout stops matching a file after this long, with the results found so far (0 for no timeout)
+	FileTimeout time.Duration
+	// MaxMemory limits the estimated memory of the files matched at the same time by IdentifyLicensesInFiles,
+	// matching a file which needs more alone (0 for no limit)
+	MaxMemory int64
+	// Headers also matches the standard license headers (the short notices for the top of the source files),
+	// reported as the HeaderMatches
+	Headers bool
+	// Binaries identifies the licenses in the printable strings of the ELF, PE, and Mach-O executables and
+	// libraries (e.g., the notices compiled into them), with the headers and keywords, instead of failing on them
+	Binaries bool
+	// Walk is how IdentifyLicensesInDirectory handles the symlinks, special files, and hard links
+	Walk WalkOptions
+	// Quick only scans the header (the first QuickHeaderSize bytes) of the files which are not license-likely files
+	// (see IsLicenseLikelyFile), e.g., the license notice at the top of a source file, for a fast answer
+	Quick bool
+	// Ensemble runs the template, hash, and fuzzy similarity matching together and reconciles their verdicts
+	Ensemble *Ensemble
+	// Context cancels the matching (e.g., on an interrupt): the matching of a file stops with the matches found so far,
+	// and IdentifyLicensesInFiles skips the files which are not matched yet (nil to not cancel)
+	Context context.Context
+	// OnResult is called with the result of each file as soon as it is matched (one call at a time), e.g., to stream the results
+	OnResult     func(IdentifierResults)
 	Enhancements Enhancements
 }
 
@@ -59,6 +91,51 @@ type IdentifierResults struct {
 	AcceptablePatternMatches []PatternMatch
 	KeywordMatches           []PatternMatch
 	CopyRightStatements      []PatternMatch
+	// HeaderMatches has the standard license headers of each license ID outside of its matches (with the Headers option)
+	HeaderMatches map[string][]Match
+	// Replacements has the current SPDX expression for each matched license ID which is deprecated
+	Replacements map[string]string
+	// Mappings has the SPDX expression which the license map of the library maps each matched license ID to
+	// (e.g., of an internal license), which the Expressions have instead of the ID
+	Mappings map[string]string
+	// Exceptions has the matched exception ID which applies to each matched license ID (see Expressions)
+	Exceptions map[string]string
+	// Classifications has the family and category of each matched license ID
+	Classifications map[string]licenses.Classification
+	// Obligations has what each matched license ID requires of the user (e.g., attribution, source disclosure)
+	Obligations map[string][]string
+	// Metadata has the license list information (name, OSI approved, FSF libre, URLs) of each matched license ID
+	Metadata map[string]licenses.Metadata
+	// Variables has the text matched by the template variables of each matched license ID (with CaptureVariables)
+	Variables map[string][]TemplateVariable
+	// Locations has the line and column positions and an excerpt of each match of each license ID
+	Locations map[string][]Location
+	// TimedOut is true when the file timeout stopped the matching, so the matches may be incomplete
+	TimedOut bool
+	// Canceled is true when the canceled Context of the options stopped the matching, so the matches may be incomplete
+	Canceled bool
+	// TimedOutTemplates are the templates which were aborted by the template timeout
+	TimedOutTemplates []string
+	// Truncated is true when the long lines (e.g., of minified code) were only scanned around their license-like markers,
+	// or when the strings of a binary (with the Binaries option) were cut to the largest file to scan
+	Truncated bool
+	// HeaderOnly is true when only the header of the file (its first QuickHeaderSize bytes) was scanned with the Quick
+	// option, so a license after the header is not matched
+	HeaderOnly bool
+	// Verdicts has the algorithms which detected each matched license ID (with the Ensemble option)
+	Verdicts map[string]Verdict
+	// Hints are the low-confidence guesses from telltale phrases when no license matched
+	Hints []Hint
+	// Declarations are the UNLICENSED and All rights reserved declarations when no license matched (see Status)
+	Declarations []Declaration
+	// LicenseURLs are the license reference URLs outside of the matches (a distinct evidence type: the licenses are not matched)
+	LicenseURLs []LicenseURL
+	// Language is the detected language of the text (an ISO 639-1 code, or "" when it cannot be determined)
+	Language string
+	// HardLinkOf is the first path of the same file, whose results these are (with the HardLinksOnce walk option)
+	HardLinkOf string
+	// ExtractedLicense is the license-like text which matched no license, with its LicenseRef (see IsUnknownLicense)
+	ExtractedLicense *ExtractedLicense
 }
 
 type Block struct {
@@ -69,10 +146,28 @@ type Block struct {
 func Identify(options Options, licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData) (IdentifierResults, error) {
 	// find the licenses in the normalized text and return a list of SPDX IDs
 	// in case of an error, return as much as we have along with an error
-	licenseResults, err := findAllLicensesInNormalizedData(licenseLibrary, normalizedData)
+	var licenseResults IdentifierResults
+	var err error
+	limits := newMatchLimits(options)
+	if options.Ensemble != nil {
+		licenseResults, err = options.Ensemble.identify(options, licenseLibrary, normalizedData, limits)
+	} else if ids := licenseLibrary.ExactHashMap[normalizedData.Hash.Sha256]; len(ids) > 0 && !options.NoExactHash {
+		licenseResults, err = exactLicenseMatch(ids, licenseLibrary, normalizedData, limits)
+	} else if options.Cache != nil {
+		licenseResults, err = findAllLicensesWithCache(options.Cache, licenseLibrary, normalizedData, limits)
+	} else {
+		licenseResults, err = findAllLicensesInNormalizedData(licenseLibrary, normalizedData, limits)
+	}
 	if err != nil {
 		return IdentifierResults{}, err
 	}
+	if options.Headers {
+		if err := addHeaderMatches(licenseLibrary, &licenseResults, normalizedData, limits); err != nil {
+			return IdentifierResults{}, err
+		}
+	}
+	limits.record(&licenseResults)
+	licenseResults.Language = language.Detect(normalizedData.OriginalText)
 
 	if err := FromOptions(&licenseResults, options.Enhancements, licenseLibrary); err != nil {
 		return IdentifierResults{}, err
@@ -81,6 +176,19 @@ func Identify(options Options, licenseLibrary *licenses.LicenseLibrary, normaliz
 	if err := applyMutatorLicenses(licenseLibrary.LicenseMap, &licenseResults); err != nil {
 		return IdentifierResults{}, err
 	}
+	pruneVerdicts(&licenseResults)
+	addHints(&licenseResults)
+	addDeclarations(&licenseResults)
+	addLicenseURLs(licenseLibrary, &licenseResults)
+
+	addLicenseInfo(licenseLibrary, &licenseResults)
+	addExceptions(licenseLibrary, &licenseResults)
+	addLocations(&licenseResults)
+	addExtractedLicense(&licenseResults, normalizedData.Hash.Sha256)
+
+	if options.Enhancements.CaptureVariables {
+		addTemplateVariables(licenseLibrary, &licenseResults, normalizedData)
+	}
 
 	if options.OmitBlocks {
 		licenseResults.Blocks = []Block{}
@@ -90,17 +198,26 @@ func Identify(options Options, licenseLibrary *licenses.LicenseLibrary, normaliz
 }
 
 func IdentifyLicensesInString(input string, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
-	// instantiate normalizedData with the input license text
-	normalizedData := normalizer.NormalizationData{
-		OriginalText: input,
+	// instantiate normalizedData with the input license text (only the windows around the markers of its long lines, if any)
+	normalizedData, truncated := windowLongLines(input)
+	if !truncated {
+		normalizedData = normalizer.NormalizationData{
+			OriginalText: input,
+		}
 	}
+	normalizedData.LinePrefixes = licenseLibrary.LinePrefixes
 
 	// normalize the input license text
 	if err := normalizedData.NormalizeText(); err != nil {
 		return IdentifierResults{}, err
 	}
 
-	return Identify(options, licenseLibrary, normalizedData)
+	result, err := Identify(options, licenseLibrary, normalizedData)
+	if truncated && err == nil {
+		result.Truncated = true
+		addExtractedLicense(&result, result.Hash.Sha256)
+	}
+	return result, err
 }
 
 func IdentifyLicensesInFile(filePath string, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
@@ -108,8 +225,20 @@ func IdentifyLicensesInFile(filePath string, options Options, licenseLibrary *li
 	if err != nil {
 		return IdentifierResults{}, err
 	}
-	if fi.Size() > 1000000 {
-		return IdentifierResults{}, fmt.Errorf("file too large (%v > 1000000)", fi.Size())
+	if options.Binaries {
+		format, err := extractor.BinaryFileFormat(filePath)
+		if err != nil {
+			return IdentifierResults{}, err
+		}
+		if format != "" {
+			return identifyLicensesInBinary(filePath, format, options, licenseLibrary)
+		}
+	}
+	if options.Quick && !IsLicenseLikelyFile(filePath) {
+		return identifyLicensesInHeader(filePath, fi.Size(), options, licenseLibrary)
+	}
+	if fi.Size() > MaxFileSize {
+		return IdentifierResults{}, fmt.Errorf("file too large (%v > %v)", fi.Size(), MaxFileSize)
 	}
 
 	b, err := ioutil.ReadFile(filePath)
@@ -123,67 +252,107 @@ func IdentifyLicensesInFile(filePath string, options Options, licenseLibrary *li
 	return result, err
 }
 
+// IdentifyLicensesInDirectory identifies the licenses in the non-empty files of the directory, walked with the Walk
+// options (skipping the binary and media files unless AllFiles). With HardLinksOnce, the results of the other paths of a file follow the results of its first path.
 func IdentifyLicensesInDirectory(dirPath string, options Options, licenseLibrary *licenses.LicenseLibrary) (ret []IdentifierResults, err error) {
-	var lfs []string
+	lfs, links, err := walkFiles(dirPath, options.Walk, options.Binaries)
+	if err != nil {
+		fmt.Printf("error walking the path %v: %v\n", dirPath, err)
+		return nil, err
+	}
+	if len(links) == 0 {
+		return IdentifyLicensesInFiles(lfs, options, licenseLibrary)
+	}
 
-	if err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			fmt.Printf("prevent panic by handling failure accessing a path %q: %v\n", path, err)
-			return err
+	if onResult := options.OnResult; onResult != nil {
+		options.OnResult = func(result IdentifierResults) {
+			onResult(result)
+			for _, link := range links[result.File] {
+				onResult(hardLinkResult(result, link))
+			}
 		}
-		if !d.IsDir() {
-			info, _ := d.Info()
-			if info.Size() > 0 {
-				lfs = append(lfs, path)
+	}
+	results, err := IdentifyLicensesInFiles(lfs, options, licenseLibrary)
+	for _, result := range results {
+		ret = append(ret, result)
+		for _, link := range links[result.File] {
+			ret = append(ret, hardLinkResult(result, link))
+		}
+	}
+	return ret, err
+}
+
+// IdentifyLicensesInFiles identifies the licenses in each file (in parallel). The results are returned,
+// and passed to the OnResult callback, in the order of the files (not in the order in which they are
+// matched), so that the output is the same in every run. When the Context of the options is canceled,
+// the files which are not matched yet are skipped, and the results of the matched files are returned
+// with an error wrapping the context error.
+func IdentifyLicensesInFiles(lfs []string, options Options, licenseLibrary *licenses.LicenseLibrary) (ret []IdentifierResults, err error) {
+	// Identical copies (e.g., LICENSE files) are matched once per scan
+	if options.Cache == nil {
+		if options.Cache, err = NewResultCache("", licenseLibrary); err != nil {
+			return nil, err
+		}
+	}
+
+	// The results by file index, passed on when the results of all the previous files are done
+	results := make([]IdentifierResults, len(lfs))
+	done := make([]bool, len(lfs))
+	failed := make([]bool, len(lfs))
+	next := 0
+	var mu sync.Mutex
+	finish := func(i int, ir IdentifierResults, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i], done[i], failed[i] = ir, true, err != nil
+		for ; next < len(lfs) && done[next]; next++ {
+			if !failed[next] {
+				if options.OnResult != nil {
+					options.OnResult(results[next])
+				}
+				ret = append(ret, results[next])
 			}
 		}
-		return nil
-	}); err != nil {
-		fmt.Printf("error walking the path %v: %v\n", dirPath, err)
-		return nil, err
 	}
 
-	// errGroup to do the work in parallel until error
+	// errGroup to do the work in parallel until error, with a worker for each file that fits in the memory budget
 	workers := errgroup.Group{}
 	workers.SetLimit(10)
-	ch := make(chan IdentifierResults, 10)
-
-	// WaitGroup to know when we have all the results
-	waitForResults := sync.WaitGroup{}
-	waitForResults.Add(1)
-
-	// Start receiving the results until channel closes
-	go func() {
-		for ir := range ch {
-			ret = append(ret, ir)
+	budget := newMemoryBudget(options.MaxMemory)
+	skipped := 0
+	for i, lf := range lfs {
+		if options.Context != nil && options.Context.Err() != nil {
+			skipped = len(lfs) - i
+			break
 		}
-		waitForResults.Done()
-	}()
-
-	// Loop using a worker to send results to a channel
-	for _, lf := range lfs {
-		lf := lf
+		i, lf := i, lf
+		n := budget.acquire(lf)
 		workers.Go(func() error {
+			defer budget.release(n)
 			ir, err := IdentifyLicensesInFile(lf, options, licenseLibrary)
-			if err == nil {
-				ch <- ir
-			}
+			finish(i, ir, err)
 			return err
 		})
 	}
-
-	// Close the channel when done or error
-	go func() {
-		err = workers.Wait()
-		close(ch)
-	}()
-
-	// Make sure we got all the results
-	waitForResults.Wait()
+	err = workers.Wait()
+	if skipped > 0 {
+		// The results after the skipped files are passed on too, as the partial results of the scan
+		for ; next < len(lfs); next++ {
+			if done[next] && !failed[next] {
+				if options.OnResult != nil {
+					options.OnResult(results[next])
+				}
+				ret = append(ret, results[next])
+			}
+		}
+		if err == nil {
+			err = fmt.Errorf("%w: %v of the %v files were not scanned", options.Context.Err(), skipped, len(lfs))
+		}
+	}
 	return ret, err
 }
 
-func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData) (IdentifierResults, error) {
+func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData, limits *matchLimits) (IdentifierResults, error) {
 	// initialize the result with original license text, normalized license text, and hash (md5, sha256, and sha512)
 	ret := IdentifierResults{
 		OriginalText:   normalizedData.OriginalText,
@@ -196,11 +365,21 @@ func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, no
 	// List with LicenseID and indexes for generating text blocks
 	var licensesMatched []licenseMatch
 
+	candidates := licenseLibrary.CandidateIndex.Candidates(normalizedData.NormalizedText).ForLanguages(language.Present(normalizedData.OriginalText))
+	Logger.Debugf("The candidate index selected %v patterns", candidates.Len())
+
+	checked := 0
 	for id, lic := range licenseLibrary.LicenseMap {
-		matches, err := findLicenseInNormalizedData(lic, normalizedData, licenseLibrary)
+		if limits.expired() {
+			Logger.Infof("Matching stopped (timed out or canceled) with %v of %v licenses checked", checked, len(licenseLibrary.LicenseMap))
+			break
+		}
+		checked++
+		matches, err := findLicenseInNormalizedData(lic, normalizedData, licenseLibrary, candidates, limits)
 		if err != nil {
 			return ret, err
 		}
+		matches = guardMatches(licenseLibrary.MatchGuard(id), normalizedData.OriginalText, matches)
 
 		// Sort the matches slice by start and end index.
 		sort.Slice(matches, func(i, j int) bool {
@@ -220,7 +399,17 @@ func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, no
 		}
 	}
 
-	// Generate Blocks.
+	// Generate Blocks from the matches in the order of the text (not of the license map), so that they are the same in every run
+	sort.Slice(licensesMatched, func(i, j int) bool {
+		a, b := licensesMatched[i], licensesMatched[j]
+		if a.Match.Begins != b.Match.Begins {
+			return a.Match.Begins < b.Match.Begins
+		}
+		if a.Match.Ends != b.Match.Ends {
+			return a.Match.Ends < b.Match.Ends
+		}
+		return a.LicenseId < b.LicenseId
+	})
 	blocks, err := generateTextBlocks(normalizedData.OriginalText, licensesMatched)
 	if err != nil {
 		return ret, err
@@ -230,10 +419,157 @@ func findAllLicensesInNormalizedData(licenseLibrary *licenses.LicenseLibrary, no
 	return ret, nil
 }
 
-func findLicenseInNormalizedData(lic licenses.License, normalizedData normalizer.NormalizationData, ll *licenses.LicenseLibrary) (licenseMatches []Match, err error) {
+// addLicenseInfo adds the license list metadata, classification, obligations, and any replacement and mapping of each
+// matched license ID
+func addLicenseInfo(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults) {
+	if len(licenseResults.Matches) == 0 {
+		return
+	}
+	licenseResults.Metadata = make(map[string]licenses.Metadata)
+	licenseResults.Classifications = make(map[string]licenses.Classification)
+	licenseResults.Obligations = make(map[string][]string)
+	for id := range licenseResults.Matches {
+		licenseResults.Metadata[id] = licenseLibrary.Metadata(id)
+		licenseResults.Classifications[id] = licenseLibrary.Classify(id)
+		if obligations := licenseLibrary.Obligations(id); obligations != nil {
+			licenseResults.Obligations[id] = obligations
+		}
+		if replacement, ok := licenseLibrary.Replacement(id); ok {
+			if licenseResults.Replacements == nil {
+				licenseResults.Replacements = make(map[string]string)
+			}
+			licenseResults.Replacements[id] = replacement
+		}
+		if mapped, ok := licenseLibrary.MappedExpression(id); ok {
+			if licenseResults.Mappings == nil {
+				licenseResults.Mappings = make(map[string]string)
+			}
+			licenseResults.Mappings[id] = mapped
+			addMappedLicenseInfo(licenseLibrary, licenseResults, mapped)
+		}
+	}
+}
+
+// addMappedLicenseInfo adds the classification and obligations of the license IDs of a mapped expression, so that
+// a policy can evaluate the expression (e.g., the choices of an OR)
+func addMappedLicenseInfo(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults, mapped string) {
+	e, err := licenses.ParseExpression(mapped)
+	if err != nil {
+		return
+	}
+	for _, l := range e.Licenses() {
+		if _, ok := licenseResults.Classifications[l.License]; ok {
+			continue
+		}
+		licenseResults.Classifications[l.License] = licenseLibrary.Classify(l.License)
+		if obligations := licenseLibrary.Obligations(l.License); obligations != nil {
+			licenseResults.Obligations[l.License] = obligations
+		}
+	}
+}
+
+// findAllLicensesWithCache uses the cached matches for the same normalized text, or finds and caches the matches
+func findAllLicensesWithCache(cache *ResultCache, licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData, limits *matchLimits) (IdentifierResults, error) {
+	if len(normalizedData.IndexMap) == 0 {
+		return findAllLicensesInNormalizedData(licenseLibrary, normalizedData, limits)
+	}
+	if matches, ok := cache.get(normalizedData); ok {
+		return resultsFromMatches(matches, normalizedData)
+	}
+	ret, err := findAllLicensesInNormalizedData(licenseLibrary, normalizedData, limits)
+	if err != nil || limits.timedOut() {
+		return ret, err // incomplete results are not cached
+	}
+	if err := cache.put(normalizedData, ret.Matches); err != nil {
+		Logger.Debugf("Cannot cache the results: %v", err)
+	}
+	return ret, nil
+}
+
+// resultsFromMatches returns the results (with text blocks) for the matches found in the normalized data
+func resultsFromMatches(matches map[string][]Match, normalizedData normalizer.NormalizationData) (IdentifierResults, error) {
+	ret := IdentifierResults{
+		OriginalText:   normalizedData.OriginalText,
+		NormalizedText: normalizedData.NormalizedText,
+		Hash:           normalizedData.Hash,
+		Matches:        matches,
+	}
+	var ids []string
+	for id := range matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var licensesMatched []licenseMatch
+	for _, id := range ids {
+		for _, m := range matches[id] {
+			licensesMatched = append(licensesMatched, licenseMatch{LicenseId: id, Match: m})
+		}
+	}
+	blocks, err := generateTextBlocks(normalizedData.OriginalText, licensesMatched)
+	if err != nil {
+		return ret, err
+	}
+	ret.Blocks = blocks
+	return ret, nil
+}
+
+// exactLicenseMatch returns the licenses whose verbatim text has the same normalized hash as the input.
+// The whole input is a match, and only the patterns of those licenses for a part of the text are matched for the
+// rest (e.g., an appendix, see exactMatchPatterns).
+func exactLicenseMatch(ids []string, licenseLibrary *licenses.LicenseLibrary, normalizedData normalizer.NormalizationData, limits *matchLimits) (IdentifierResults, error) {
+	candidates := licenseLibrary.CandidateIndex.Candidates(normalizedData.NormalizedText).ForLanguages(language.Present(normalizedData.OriginalText))
+	text := normalizedData.OriginalText
+	first, last := len(text)-len(strings.TrimLeftFunc(text, unicode.IsSpace)), len(strings.TrimRightFunc(text, unicode.IsSpace))-1
+	matches := make(map[string][]Match)
+	for _, id := range ids {
+		licenseMatches := []Match{{Begins: 0, Ends: len(text) - 1}}
+		if lic, ok := licenseLibrary.LicenseMap[id]; ok {
+			patterns := exactMatchPatterns(lic, len(normalizedData.NormalizedText))
+			patternMatches, err := findPatterns(patterns, normalizedData, nil, licenseLibrary, candidates, limits)
+			if err != nil {
+				return IdentifierResults{}, err
+			}
+			for _, m := range guardMatches(licenseLibrary.MatchGuard(id), text, patternMatches) {
+				if m.Begins > first || m.Ends < last {
+					licenseMatches = append(licenseMatches, m) // the whole text is already matched
+				}
+			}
+		}
+		sort.Slice(licenseMatches, func(i, j int) bool {
+			if licenseMatches[i].Begins != licenseMatches[j].Begins {
+				return licenseMatches[i].Begins < licenseMatches[j].Begins
+			}
+			return licenseMatches[i].Ends < licenseMatches[j].Ends
+		})
+		for i := 1; i < len(licenseMatches); i++ {
+			if licenseMatches[i] == licenseMatches[i-1] {
+				licenseMatches = append(licenseMatches[:i], licenseMatches[i+1:]...) // remove duplicates
+				i--
+			}
+		}
+		matches[id] = licenseMatches
+	}
+	return resultsFromMatches(matches, normalizedData)
+}
+
+// exactMatchPatterns returns the patterns of the license which are matched with its verbatim text (of the length) for
+// the rest of an exact match: the associated patterns, and the primary patterns shorter than half of the text, which
+// are parts of it (e.g., the notice in the appendix of Apache-2.0). The other primary patterns are the whole text,
+// which is already matched, so their regexps (the slowest) are not run.
+func exactMatchPatterns(lic licenses.License, textLength int) []*licenses.PrimaryPatterns {
+	var ret []*licenses.PrimaryPatterns
+	for _, p := range lic.PrimaryPatterns {
+		if len(p.Text) < textLength/2 {
+			ret = append(ret, p)
+		}
+	}
+	return append(ret, lic.AssociatedPatterns...)
+}
+
+func findLicenseInNormalizedData(lic licenses.License, normalizedData normalizer.NormalizationData, ll *licenses.LicenseLibrary, candidates licenses.Candidates, limits *matchLimits) (licenseMatches []Match, err error) {
 	// TODO: If we are not using the match blocks, etc, then do the faster alias checks first.
 	// Get the license pattern matches.
-	licenseMatches, err = findPatterns(lic.PrimaryPatterns, normalizedData, licenseMatches, ll)
+	licenseMatches, err = findPatterns(lic.PrimaryPatterns, normalizedData, licenseMatches, ll, candidates, limits)
 	if err != nil {
 		return licenseMatches, err
 	}
@@ -254,7 +590,23 @@ func findLicenseInNormalizedData(lic licenses.License, normalizedData normalizer
 	}
 
 	// If there are associated patterns, check those.
-	return findPatterns(lic.AssociatedPatterns, normalizedData, licenseMatches, ll)
+	return findPatterns(lic.AssociatedPatterns, normalizedData, licenseMatches, ll, candidates, limits)
+}
+
+// guardMatches removes the matches which do not meet the guard of the license (if any)
+func guardMatches(guard *licenses.MatchGuard, originalText string, licenseMatches []Match) []Match {
+	if guard == nil {
+		return licenseMatches
+	}
+	var ret []Match
+	for _, m := range licenseMatches {
+		if guard.Allows(originalText, m.Begins, m.Ends) {
+			ret = append(ret, m)
+		} else {
+			Logger.Debugf("The %v match at %v-%v does not meet its match guard", guard.Pattern, m.Begins, m.Ends)
+		}
+	}
+	return ret
 }
 
 // findAny finds one matching string which meets word boundary conditions (and url conditions)
@@ -406,7 +758,7 @@ func findAnyURL(urls []string, normalized normalizer.NormalizationData, licenseM
 	return findAny(urls, normalized, true, licenseMatches)
 }
 
-func findPatterns(patterns []*licenses.PrimaryPatterns, normalizedData normalizer.NormalizationData, licenseMatches []Match, ll *licenses.LicenseLibrary) ([]Match, error) {
+func findPatterns(patterns []*licenses.PrimaryPatterns, normalizedData normalizer.NormalizationData, licenseMatches []Match, ll *licenses.LicenseLibrary, candidates licenses.Candidates, limits *matchLimits) ([]Match, error) {
 	// errGroup to do the work in parallel until error
 	workers := errgroup.Group{}
 	workers.SetLimit(10)
@@ -428,17 +780,20 @@ func findPatterns(patterns []*licenses.PrimaryPatterns, normalizedData normalize
 
 	// Loop with the slow part using a worker to send results to a channel
 	for _, pattern := range patterns {
+		if !candidates.Has(pattern.FileName) {
+			continue
+		}
 		ppk := licenses.LicensePatternKey{
 			FilePath: pattern.FileName,
 		}
 		preChecksRequired := ll.PrimaryPatternPreCheckMap[ppk]
-		if preChecksRequired != nil && !PassedStaticBlocksChecks(preChecksRequired.StaticBlocks, normalizedData) {
+		if preChecksRequired != nil && !ll.PreCheckSettings.Passed(preChecksRequired.StaticBlocks, normalizedData.NormalizedText) {
 			continue
 		}
 		p := pattern
 		nD := normalizedData
 		workers.Go(func() error {
-			patternMatches, err := FindMatchingPatternInNormalizedData(p, nD)
+			patternMatches, err := limits.findMatchingPattern(p, nD)
 			if err == nil {
 				ch <- patternMatches
 			}
@@ -468,16 +823,27 @@ func FindMatchingPatternInNormalizedData(matchingPattern *licenses.PrimaryPatter
 	for _, match := range matches {
 		// Create the result object, with the start and end points in the original text.
 		if match[1] < len(normalized.IndexMap) {
-			results = append(results, Match{Begins: normalized.IndexMap[match[0]], Ends: normalized.IndexMap[match[1]-1]})
+			results = append(results, Match{Begins: mappedBegin(match[0], normalized.IndexMap), Ends: normalized.IndexMap[match[1]-1]})
 		} else {
 			// End of map is out of range, so use the last index in the map
-			results = append(results, Match{Begins: normalized.IndexMap[match[0]], Ends: normalized.IndexMap[len(normalized.IndexMap)-1]})
+			results = append(results, Match{Begins: mappedBegin(match[0], normalized.IndexMap), Ends: normalized.IndexMap[len(normalized.IndexMap)-1]})
 		}
 	}
 
 	return results, err
 }
 
+// mappedBegin returns the original position of a match beginning at the normalized position, moving to the
+// end of a replacement (e.g., copyright for (c)) when the match begins in its middle (mapped to -1)
+func mappedBegin(begins int, indexMap []int) int {
+	for i := begins; i < len(indexMap); i++ {
+		if indexMap[i] != -1 {
+			return indexMap[i]
+		}
+	}
+	return indexMap[begins]
+}
+
 // PassedStaticBlocksChecks verifies static blocks are present, if any
 func PassedStaticBlocksChecks(staticBlocks []string, nd normalizer.NormalizationData) bool {
 	for i := range staticBlocks {
@@ -23,6 +23,8 @@ const (
 var testDataDir = path.Join(resources, "spdx", spdx, "testdata")
 var options = Options{
 	ForceResult: false,
+	// The testdata would all be exact matches. Test the patterns.
+	NoExactHash: true,
 	Enhancements: Enhancements{
 		AddNotes:       "",
 		AddTextBlocks:  true,
@@ -6,7 +6,12 @@ package identifier
 
 import (
 	_ "embed"
+	"os"
+	"path"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -616,6 +621,305 @@ func Test_identifyLicensesInStringPreChecks(t *testing.T) {
 	}
 }
 
+func Test_identifyLicensesExactHash(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mit := string(b)
+
+	tests := []struct {
+		name        string
+		input       string
+		noExactHash bool
+		wantExact   bool
+	}{
+		{name: "verbatim text is an exact match", input: mit, wantExact: true},
+		{name: "whitespace differences are normalized", input: "\n  " + strings.ReplaceAll(mit, "\n", "\n\n"), wantExact: true},
+		{name: "patterns are used without the fast path", input: mit, noExactHash: true},
+		{name: "patterns are used for other text", input: "The MIT code:\n" + mit},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options := defaultOptions()
+			options.NoExactHash = tt.noExactHash
+			got, err := IdentifyLicensesInString(tt.input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if _, ok := got.Matches["MIT"]; !ok {
+				t.Fatalf("IdentifyLicensesInString() did not match MIT: %v", got.Matches)
+			}
+			exact := map[string][]Match{"MIT": {{Begins: 0, Ends: len(tt.input) - 1}}}
+			if isExact := cmp.Equal(exact, got.Matches); isExact != tt.wantExact {
+				t.Errorf("IdentifyLicensesInString() exact match = %v, want %v: %v", isExact, tt.wantExact, got.Matches)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesExactHashOtherPatterns(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	got, err := IdentifyLicensesInFile(path.Join(testDataDir, "Apache-2.0.txt"), defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+	}
+	appendix := Match{Begins: 9758, Ends: 10278}
+	if !containsMatch(got.Matches["Apache-2.0"], appendix) {
+		t.Errorf("IdentifyLicensesInFile() did not match the appendix at %v: %v", appendix, got.Matches["Apache-2.0"])
+	}
+}
+
+func Test_exactMatchPatterns(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "Apache-2.0.txt"))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	normalized := normalizer.NewNormalizationData(string(b), false)
+	if err := normalized.NormalizeText(); err != nil {
+		t.Fatalf("NormalizeText() error = %v", err)
+	}
+	if ids := licenseLibrary.ExactHashMap[normalized.Hash.Sha256]; len(ids) != 1 || ids[0] != "Apache-2.0" {
+		t.Fatalf("ExactHashMap = %v, want Apache-2.0", ids)
+	}
+
+	// Only the notice in the appendix and the associated patterns are matched, not the (primary) patterns of the whole text
+	var got []string
+	for _, p := range exactMatchPatterns(licenseLibrary.LicenseMap["Apache-2.0"], len(normalized.NormalizedText)) {
+		got = append(got, path.Base(p.FileName))
+	}
+	sort.Strings(got)
+	want := []string{"associated_jackson-header.txt", "license_Apache-2.0_header.txt", "license_alt_title.txt"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("exactMatchPatterns() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func containsMatch(matches []Match, want Match) bool {
+	for _, m := range matches {
+		if m == want {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_identifyLicensesReplacements(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	got, err := IdentifyLicensesInFile(path.Join(testDataDir, "deprecated_GPL-2.0+.txt"), defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+	}
+	if d := cmp.Diff(map[string]string{"GPL-2.0+": "GPL-2.0-or-later"}, got.Replacements); d != "" {
+		t.Errorf("Didn't get expected replacements: (-want, +got): %v", d)
+	}
+	if md := got.Metadata["GPL-2.0+"]; !md.IsDeprecated || !md.IsOSIApproved || md.Name != "GNU General Public License v2.0 or later" || len(md.SeeAlso) == 0 {
+		t.Errorf("Didn't get expected metadata: %+v", md)
+	}
+}
+
+func Test_identifyLicensesMatchGuards(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	diffmark := "1. you can do what you want with it\n2. I refuse any responsibility for the consequences\n"
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{name: "license text", input: diffmark, want: true},
+		{name: "excerpt with context", input: "The diffmark license:\n" + diffmark + "\nSee the README.", want: true},
+		{name: "excerpt without context", input: "Notes from the author:\n" + diffmark + "\nSee the README."},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options := defaultOptions()
+			options.NoExactHash = true
+			got, err := IdentifyLicensesInString(tt.input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if _, ok := got.Matches["diffmark"]; ok != tt.want {
+				t.Errorf("IdentifyLicensesInString() matched diffmark = %v, want %v: %v", ok, tt.want, got.Matches)
+			}
+		})
+	}
+}
+
+func Test_identifyTranslatedLicenses(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	tests := []struct {
+		name         string
+		input        string
+		wantLicense  string
+		wantLanguage string
+	}{
+		{
+			name:         "french creative commons notice",
+			input:        "Cette œuvre est mise à disposition selon les termes de la Licence Creative Commons Attribution 4.0 International.",
+			wantLicense:  "CC-BY-4.0",
+			wantLanguage: "fr",
+		},
+		{
+			name:         "german creative commons notice",
+			input:        "Dieses Werk ist lizenziert unter einer Creative Commons Namensnennung - Weitergabe unter gleichen Bedingungen 4.0 International Lizenz.",
+			wantLicense:  "CC-BY-SA-4.0",
+			wantLanguage: "de",
+		},
+		{
+			name:         "french EUPL",
+			input:        "LICENCE PUBLIQUE DE L'UNION EUROPÉENNE v. 1.2\nEUPL © l'Union européenne 2007, 2016\n\nLa présente licence publique de l'Union européenne s'applique à toute œuvre.",
+			wantLicense:  "EUPL-1.2",
+			wantLanguage: "fr",
+		},
+		{
+			name:         "french CeCILL",
+			input:        "CONTRAT DE LICENCE DE LOGICIEL LIBRE CeCILL\n\nVersion 2.1 du 2013-06-21\n\nAvertissement\n\nCe contrat est une licence de logiciel libre issue d'une concertation entre ses auteurs.",
+			wantLicense:  "CECILL-2.1",
+			wantLanguage: "fr",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := IdentifyLicensesInString(tt.input, defaultOptions(), licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if _, ok := got.Matches[tt.wantLicense]; !ok {
+				t.Errorf("IdentifyLicensesInString() did not match %v: %v", tt.wantLicense, got.Matches)
+			}
+			if got.Language != tt.wantLanguage {
+				t.Errorf("IdentifyLicensesInString() language = %q, want %q", got.Language, tt.wantLanguage)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesConcurrently(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("licenseLibrary.AddAll() error = %v", err)
+	}
+	inputs := make(map[string]string)
+	for _, id := range []string{"MIT", "ISC", "Apache-2.0", "BSD-3-Clause"} {
+		b, err := os.ReadFile(path.Join(testDataDir, id+".txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		inputs[id] = "Some code\n" + string(b) // not the exact text, so the patterns are matched
+	}
+
+	// one library serves the goroutines (the patterns are compiled on first use, concurrently)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		for id, input := range inputs {
+			wg.Add(1)
+			go func(id string, input string) {
+				defer wg.Done()
+				got, err := IdentifyLicensesInString(input, defaultOptions(), licenseLibrary)
+				if err != nil {
+					t.Errorf("IdentifyLicensesInString() error = %v", err)
+					return
+				}
+				if _, ok := got.Matches[id]; !ok {
+					t.Errorf("IdentifyLicensesInString() did not match %v: %v", id, got.Matches)
+				}
+			}(id, input)
+		}
+	}
+	wg.Wait()
+}
+
+func Test_identifyLicensesInFilesOrder(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	// more files than workers, the largest first so that they finish last, and a missing file in the middle
+	var files []string
+	for _, id := range []string{"GPL-3.0-only", "AGPL-3.0-only", "Apache-2.0", "MPL-2.0", "EPL-2.0", "0BSD", "MIT", "ISC", "Zlib", "BSD-2-Clause", "BSD-3-Clause", "Unlicense"} {
+		files = append(files, path.Join(testDataDir, id+".txt"))
+	}
+	missing := path.Join(testDataDir, "Missing.txt")
+	files = append(files[:6], append([]string{missing}, files[6:]...)...)
+
+	var streamed []string
+	options := defaultOptions()
+	options.OnResult = func(r IdentifierResults) { streamed = append(streamed, r.File) }
+	results, err := IdentifyLicensesInFiles(files, options, licenseLibrary)
+	if err == nil {
+		t.Errorf("IdentifyLicensesInFiles() expected an error for %v", missing)
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.File)
+	}
+	want := append(append([]string{}, files[:6]...), files[7:]...)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("IdentifyLicensesInFiles() order mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(want, streamed); d != "" {
+		t.Errorf("OnResult order mismatch (-want +got):\n%s", d)
+	}
+}
+
 func Test_mutatorsAreCompatible(t *testing.T) {
 	testId1 := "test_id_1"
 	testId2 := "test_id_2"
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// ExcerptContext is the most bytes of the same line to show before and after a match in an excerpt
+	ExcerptContext = 40
+	// ExcerptMatchLength is the most bytes of the matched text in an excerpt. Longer matches show the
+	// beginning and the end with an ellipsis in between.
+	ExcerptMatchLength = 120
+	excerptEllipsis    = " … "
+)
+
+var whitespaceRE = regexp.MustCompile(`\s+`)
+
+// Location is where a match is in the original text, so that reviewers and UIs can jump straight to it
+type Location struct {
+	// Match has the 0-based, inclusive byte offsets in the original text
+	Match
+	// StartLine and EndLine are 1-based line numbers. StartColumn and EndColumn are 1-based byte columns.
+	StartLine   int
+	StartColumn int
+	EndLine     int
+	EndColumn   int
+	Excerpt     Excerpt
+}
+
+// Excerpt is a short excerpt of the original text to highlight a match.
+// Before and After are the text around the match on the same lines.
+type Excerpt struct {
+	Before string
+	Match  string
+	After  string
+}
+
+// String returns the excerpt on one line with the match highlighted in [[ ]]
+func (e Excerpt) String() string {
+	return e.Highlight("[[", "]]")
+}
+
+// Highlight returns the excerpt on one line with the match between the begin and end markers (e.g., terminal colors)
+func (e Excerpt) Highlight(begin string, end string) string {
+	return oneLine(e.Before) + begin + oneLine(e.Match) + end + oneLine(e.After)
+}
+
+// Highlight returns the text with each matched region (overlapping matches merged) between the begin and end markers
+func Highlight(text string, matches []Match, begin string, end string) string {
+	var sb strings.Builder
+	prev := 0
+	for _, rng := range mergeMatches(matches, len(text)) {
+		begins, ends := max(prev, runeStart(text, rng[0])), runeEnd(text, rng[1]+1)
+		if begins >= ends {
+			continue
+		}
+		sb.WriteString(text[prev:begins])
+		sb.WriteString(begin)
+		sb.WriteString(text[begins:ends])
+		sb.WriteString(end)
+		prev = ends
+	}
+	sb.WriteString(text[prev:])
+	return sb.String()
+}
+
+// Locate returns the line and column positions and an excerpt for a match in the original text
+func Locate(text string, m Match) Location {
+	begins, ends := m.Begins, m.Ends
+	if ends >= len(text) {
+		ends = len(text) - 1
+	}
+	if begins < 0 || begins > ends {
+		return Location{Match: m}
+	}
+	begins = runeStart(text, begins)
+	end := runeEnd(text, ends+1)
+
+	startLine, startColumn := lineAndColumn(text, begins)
+	endLine, endColumn := lineAndColumn(text, end-1)
+
+	lineStart := strings.LastIndexByte(text[:begins], '\n') + 1
+	before := text[runeStart(text, max(lineStart, begins-ExcerptContext)):begins]
+	lineEnd := len(text)
+	if i := strings.IndexByte(text[end:], '\n'); i >= 0 {
+		lineEnd = end + i
+	}
+	after := text[end:runeEnd(text, min(lineEnd, end+ExcerptContext))]
+
+	matched := text[begins:end]
+	if len(matched) > ExcerptMatchLength {
+		half := ExcerptMatchLength / 2
+		matched = matched[:runeStart(matched, half)] + excerptEllipsis + matched[runeEnd(matched, len(matched)-half):]
+	}
+
+	return Location{
+		Match:       m,
+		StartLine:   startLine,
+		StartColumn: startColumn,
+		EndLine:     endLine,
+		EndColumn:   endColumn,
+		Excerpt:     Excerpt{Before: before, Match: matched, After: after},
+	}
+}
+
+// addLocations adds the location of each match, sorted by position
+func addLocations(licenseResults *IdentifierResults) {
+	for id, matches := range licenseResults.Matches {
+		if len(matches) == 0 {
+			continue
+		}
+		if licenseResults.Locations == nil {
+			licenseResults.Locations = make(map[string][]Location)
+		}
+		locations := make([]Location, 0, len(matches))
+		for _, m := range matches {
+			locations = append(locations, Locate(licenseResults.OriginalText, m))
+		}
+		sort.SliceStable(locations, func(i, j int) bool {
+			if locations[i].Begins != locations[j].Begins {
+				return locations[i].Begins < locations[j].Begins
+			}
+			return locations[i].Ends < locations[j].Ends
+		})
+		licenseResults.Locations[id] = locations
+	}
+}
+
+// lineAndColumn returns the 1-based line and byte column of the byte offset
+func lineAndColumn(text string, offset int) (int, int) {
+	return lineNumber(text, offset), offset - strings.LastIndexByte(text[:offset], '\n')
+}
+
+// runeStart moves a byte offset back to the start of a rune
+func runeStart(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// runeEnd moves an (exclusive) end offset forward to the end of a rune
+func runeEnd(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset++
+	}
+	return offset
+}
+
+// oneLine replaces each run of whitespace (including line breaks) with a space
+func oneLine(s string) string {
+	return whitespaceRE.ReplaceAllString(s, " ")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLocate(t *testing.T) {
+	t.Parallel()
+	text := "package main\n\n// Licensed under the MIT license.\nfunc main() {}\n"
+	long := "first line\n" + strings.Repeat("x", 200) + "\nlast line"
+	tests := []struct {
+		name string
+		text string
+		m    Match
+		want Location
+	}{
+		{
+			name: "one line",
+			text: text,
+			m:    Match{Begins: 36, Ends: 38},
+			want: Location{
+				Match:     Match{Begins: 36, Ends: 38},
+				StartLine: 3, StartColumn: 23, EndLine: 3, EndColumn: 25,
+				Excerpt: Excerpt{Before: "// Licensed under the ", Match: "MIT", After: " license."},
+			},
+		},
+		{
+			name: "multiple lines",
+			text: text,
+			m:    Match{Begins: 17, Ends: 59},
+			want: Location{
+				Match:     Match{Begins: 17, Ends: 59},
+				StartLine: 3, StartColumn: 4, EndLine: 4, EndColumn: 11,
+				Excerpt: Excerpt{Before: "// ", Match: "Licensed under the MIT license.\nfunc main()", After: " {}"},
+			},
+		},
+		{
+			name: "long match",
+			text: long,
+			m:    Match{Begins: 0, Ends: len(long) - 1},
+			want: Location{
+				Match:     Match{Begins: 0, Ends: len(long) - 1},
+				StartLine: 1, StartColumn: 1, EndLine: 3, EndColumn: 9,
+				Excerpt: Excerpt{Match: long[:60] + excerptEllipsis + long[len(long)-60:]},
+			},
+		},
+		{
+			name: "multibyte",
+			text: "© Acme — MIT",
+			m:    Match{Begins: 12, Ends: 14},
+			want: Location{
+				Match:     Match{Begins: 12, Ends: 14},
+				StartLine: 1, StartColumn: 13, EndLine: 1, EndColumn: 15,
+				Excerpt: Excerpt{Before: "© Acme — ", Match: "MIT"},
+			},
+		},
+		{
+			name: "out of range",
+			text: "MIT",
+			m:    Match{Begins: 5, Ends: 9},
+			want: Location{Match: Match{Begins: 5, Ends: 9}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, Locate(tt.text, tt.m)); d != "" {
+				t.Errorf("Locate() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestExcerpt_String(t *testing.T) {
+	t.Parallel()
+	e := Excerpt{Before: "Copyright\t", Match: "MIT\n\nLicense", After: " text"}
+	if got, want := e.String(), "Copyright [[MIT License]] text"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		matches []Match
+		want    string
+	}{
+		{name: "no matches", text: "MIT License", want: "MIT License"},
+		{
+			name:    "overlapping matches are merged",
+			text:    "SPDX: MIT License here",
+			matches: []Match{{Begins: 6, Ends: 8}, {Begins: 6, Ends: 16}},
+			want:    "SPDX: [[MIT License]] here",
+		},
+		{
+			name:    "separate matches",
+			text:    "MIT or Apache-2.0",
+			matches: []Match{{Begins: 7, Ends: 16}, {Begins: 0, Ends: 2}},
+			want:    "[[MIT]] or [[Apache-2.0]]",
+		},
+		{
+			name:    "whole runes",
+			text:    "© MIT",
+			matches: []Match{{Begins: 1, Ends: 1}},
+			want:    "[[©]] MIT",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Highlight(tt.text, tt.matches, "[[", "]]"); got != tt.want {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// MaxLineLength is the longest line which is scanned whole. The longer lines (e.g., of minified JavaScript or
+// CSS) are only scanned in the windows around their license-like markers.
+const MaxLineLength = 5000
+
+// LongLineWindow is how many characters before and after a marker of a long line are scanned
+const LongLineWindow = 3000
+
+// markerRE matches the license-like markers of the long lines (not (c), which is a common call in minified code)
+var markerRE = regexp.MustCompile(`(?i)licen[cs]e|copyright|©|@preserve|spdx-license-identifier|permission is hereby granted|all rights reserved`)
+
+// windowLongLines returns the normalization data of the input with its long lines cut to the windows around
+// their markers (the index map has the positions in the whole input), and false when no line is too long
+func windowLongLines(input string) (normalizer.NormalizationData, bool) {
+	var excerpt strings.Builder
+	var indexMap []int
+	add := func(begins int, ends int) {
+		excerpt.WriteString(input[begins:ends])
+		for i := begins; i < ends; i++ {
+			indexMap = append(indexMap, i)
+		}
+	}
+
+	truncated := false
+	firstLong := -1
+	for begins := 0; begins < len(input); {
+		ends := strings.IndexByte(input[begins:], '\n')
+		if ends == -1 {
+			ends = len(input)
+		} else {
+			ends += begins + 1 // with the newline
+		}
+		if ends-begins <= MaxLineLength {
+			add(begins, ends)
+			begins = ends
+			continue
+		}
+
+		truncated = true
+		if firstLong == -1 {
+			firstLong = begins
+		}
+		for _, w := range markerWindows(input, begins, ends) {
+			add(w[0], w[1])
+			// the windows are separate lines, with the separator at the end of the window
+			excerpt.WriteByte('\n')
+			indexMap = append(indexMap, w[1]-1)
+		}
+		begins = ends
+	}
+	if !truncated {
+		return normalizer.NormalizationData{}, false
+	}
+	if excerpt.Len() == 0 {
+		// without any marker, the beginning of the first long line is scanned (where a license banner would be)
+		add(firstLong, runeStart(input, min(len(input), firstLong+2*LongLineWindow)))
+	}
+	return normalizer.NormalizationData{
+		OriginalText:   input,
+		NormalizedText: excerpt.String(),
+		IndexMap:       indexMap,
+	}, true
+}
+
+// markerWindows returns the merged windows around the markers of the line of the input from begins to ends
+func markerWindows(input string, begins int, ends int) [][2]int {
+	var ret [][2]int
+	for _, loc := range markerRE.FindAllStringIndex(input[begins:ends], -1) {
+		from := runeStart(input, max(begins, begins+loc[0]-LongLineWindow))
+		to := runeStart(input, min(ends, begins+loc[1]+LongLineWindow))
+		if len(ret) > 0 && from <= ret[len(ret)-1][1] {
+			ret[len(ret)-1][1] = to
+		} else {
+			ret = append(ret, [2]int{from, to})
+		}
+	}
+	return ret
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_windowLongLines(t *testing.T) {
+	t.Parallel()
+	code := strings.Repeat("var a=b(c);", 1000)
+	tests := []struct {
+		name          string
+		input         string
+		wantTruncated bool
+		want          string
+	}{
+		{name: "short lines", input: "// Licensed under MIT\nvar a=b(c);\n"},
+		{
+			name:          "long line with a marker",
+			input:         "// short line\n" + code + "License" + code + "\n",
+			wantTruncated: true,
+			want:          "// short line\n" + code[len(code)-LongLineWindow:] + "License" + code[:LongLineWindow] + "\n",
+		},
+		{
+			name:          "two markers in one window",
+			input:         code + "Copyright ACME, License" + code,
+			wantTruncated: true,
+			want:          code[len(code)-LongLineWindow:] + "Copyright ACME, License" + code[:LongLineWindow] + "\n",
+		},
+		{
+			name:          "long line without a marker",
+			input:         code,
+			wantTruncated: true,
+			want:          code[:2*LongLineWindow],
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, truncated := windowLongLines(tt.input)
+			if truncated != tt.wantTruncated {
+				t.Fatalf("windowLongLines() truncated = %v, want %v", truncated, tt.wantTruncated)
+			}
+			if !truncated {
+				return
+			}
+			if d := cmp.Diff(tt.want, got.NormalizedText); d != "" {
+				t.Errorf("windowLongLines() text mismatch (-want +got):\n%s", d)
+			}
+			if len(got.IndexMap) != len(got.NormalizedText) {
+				t.Fatalf("windowLongLines() index map length = %v, want %v", len(got.IndexMap), len(got.NormalizedText))
+			}
+			// each character of the windows maps to the same character of the input
+			for i, j := range got.IndexMap {
+				if got.NormalizedText[i] != '\n' && got.NormalizedText[i] != tt.input[j] {
+					t.Fatalf("windowLongLines() index map %v -> %v: %q != %q", i, j, got.NormalizedText[i], tt.input[j])
+				}
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesInMinifiedText(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a minified file with its license banner on the same line as the code
+	banner := "/*! " + strings.Join(strings.Fields(string(b)), " ") + " */"
+	input := strings.Repeat("var a=b(c);", 20000) + banner + strings.Repeat("var a=b(c);", 20000)
+
+	got, err := IdentifyLicensesInString(input, defaultOptions(), licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInString() error = %v", err)
+	}
+	if !got.Truncated {
+		t.Error("IdentifyLicensesInString() Truncated = false, want true")
+	}
+	if len(got.Matches["MIT"]) != 1 {
+		t.Fatalf("IdentifyLicensesInString() MIT matches = %v, want 1", got.Matches["MIT"])
+	}
+	// the match is in the whole input
+	m := got.Matches["MIT"][0]
+	if m.Begins < 0 || !strings.Contains(input[m.Begins:m.Ends+1], "Permission is hereby granted") || !strings.HasSuffix(input[:m.Ends+1], "SOFTWARE.") {
+		t.Errorf("IdentifyLicensesInString() MIT match = %v, want the banner", m)
+	}
+}
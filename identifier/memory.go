@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"context"
+	"os"
+
+	"golang.org/x/sync/semaphore"
+)
+
+// MemoryPerFileByte is the estimated peak heap used to match one byte of a file (measured with files of 100KB
+// to 1MB, most of it the garbage of the regexp matching)
+const MemoryPerFileByte = 400
+
+// memoryBudget limits the estimated memory of the files being matched at the same time. A file which needs
+// the whole budget (or more) waits for the other files to finish and is then matched alone.
+type memoryBudget struct {
+	max int64
+	sem *semaphore.Weighted
+}
+
+// newMemoryBudget returns the budget of the max bytes (nil for no limit when max is 0 or less)
+func newMemoryBudget(max int64) *memoryBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &memoryBudget{max: max, sem: semaphore.NewWeighted(max)}
+}
+
+// acquire blocks until the file fits in the budget and returns the bytes to release when it has been matched
+func (b *memoryBudget) acquire(filePath string) int64 {
+	if b == nil {
+		return 0
+	}
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return 0 // the error is reported when the file is matched
+	}
+	n := fi.Size() * MemoryPerFileByte
+	if n > b.max {
+		Logger.Debugf("Matching %v alone (about %v bytes of memory needed, more than the --maxMemory %v)", filePath, n, b.max)
+		n = b.max
+	}
+	_ = b.sem.Acquire(context.Background(), n)
+	return n
+}
+
+// release returns the bytes of a matched file to the budget
+func (b *memoryBudget) release(n int64) {
+	if b != nil && n > 0 {
+		b.sem.Release(n)
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_memoryBudget(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	small, large := path.Join(dir, "small.txt"), path.Join(dir, "large.txt")
+	if err := os.WriteFile(small, make([]byte, 10), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(large, make([]byte, 100), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := newMemoryBudget(0).acquire(large); n != 0 {
+		t.Errorf("acquire() without a limit = %v, want 0", n)
+	}
+
+	budget := newMemoryBudget(50 * MemoryPerFileByte)
+	if n := budget.acquire(small); n != 10*MemoryPerFileByte {
+		t.Errorf("acquire(small) = %v, want %v", n, 10*MemoryPerFileByte)
+	}
+	if n := budget.acquire(path.Join(dir, "missing.txt")); n != 0 {
+		t.Errorf("acquire(missing) = %v, want 0", n)
+	}
+	// the large file needs the whole budget, so it waits for the small file
+	if budget.sem.TryAcquire(50 * MemoryPerFileByte) {
+		t.Fatal("TryAcquire() of the whole budget = true while the small file is matched")
+	}
+	budget.release(10 * MemoryPerFileByte)
+	if n := budget.acquire(large); n != 50*MemoryPerFileByte {
+		t.Errorf("acquire(large) = %v, want the whole budget %v", n, 50*MemoryPerFileByte)
+	}
+	if budget.sem.TryAcquire(1) {
+		t.Error("TryAcquire() = true while the large file is matched alone")
+	}
+	budget.release(50 * MemoryPerFileByte)
+	if !budget.sem.TryAcquire(50 * MemoryPerFileByte) {
+		t.Error("TryAcquire() of the whole budget = false after the release")
+	}
+}
+
+func Test_identifyLicensesInFilesMaxMemory(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	options := defaultOptions()
+	// each file needs more than the budget, so they are matched one at a time
+	options.MaxMemory = 1
+	results, err := IdentifyLicensesInFiles([]string{path.Join(testDataDir, "MIT.txt"), path.Join(testDataDir, "ISC.txt")}, options, licenseLibrary)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInFiles() error = %v", err)
+	}
+	var got []string
+	for _, r := range results {
+		for id := range r.Matches {
+			got = append(got, path.Base(r.File)+" "+id)
+		}
+	}
+	sort.Strings(got)
+	if d := cmp.Diff([]string{"ISC.txt ISC", "MIT.txt MIT"}, got); d != "" {
+		t.Errorf("IdentifyLicensesInFiles() matches (-want +got):\n%s", d)
+	}
+}
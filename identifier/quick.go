@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// QuickHeaderSize is how many bytes of the header of a file which is not a license-likely file are scanned with
+// the Quick option
+const QuickHeaderSize = 8 << 10
+
+// MaxFileSize is the largest file to scan (in bytes). A larger file fails to scan, except for its header with the
+// Quick option.
+const MaxFileSize = 1000000
+
+// licenseLikelyFileRE matches the names of the files which usually have the license text (including prefixed
+// names like MIT-LICENSE or apache_license.txt, but not sources like check_license.go)
+var licenseLikelyFileRE = regexp.MustCompile(`(?i)^(?:un)?licen[cs]e|^copying|^notice|^copyright|^readme|[-_]licen[cs]e(?:\.(?:txt|md|rst))?$`)
+
+// IsLicenseLikelyFile returns true for the file names like LICENSE, COPYING.txt, NOTICE.md, COPYRIGHT, and
+// README.md, which are scanned whole with the Quick option
+func IsLicenseLikelyFile(name string) bool {
+	return licenseLikelyFileRE.MatchString(filepath.Base(name))
+}
+
+// identifyLicensesInHeader identifies the licenses in the header of a file (with the Quick option), e.g., the
+// license notice at the top of a source file: its first QuickHeaderSize bytes, cut at a line. A header which looks like
+// a license text but matched no license (e.g., of an EPL-1.0.txt, which only matches whole) is the start of a license
+// text, so the file is scanned whole, unless it is larger than the largest file to scan. Otherwise the results of a
+// longer file are HeaderOnly.
+func identifyLicensesInHeader(filePath string, size int64, options Options, licenseLibrary *licenses.LicenseLibrary) (IdentifierResults, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return IdentifierResults{}, err
+	}
+	defer f.Close()
+	b, err := io.ReadAll(io.LimitReader(f, QuickHeaderSize+1))
+	if err != nil {
+		return IdentifierResults{}, err
+	}
+	headerOnly := len(b) > QuickHeaderSize
+	if headerOnly {
+		b = b[:QuickHeaderSize]
+		if i := bytes.LastIndexByte(b, '\n'); i >= 0 {
+			b = b[:i+1]
+		}
+	}
+
+	result, err := IdentifyLicensesInString(string(b), options, licenseLibrary)
+	result.File = filePath
+	if err != nil || !headerOnly {
+		return result, err
+	}
+	if IsUnknownLicense(result) && size <= MaxFileSize {
+		options.Quick = false
+		return IdentifyLicensesInFile(filePath, options, licenseLibrary)
+	}
+	result.HeaderOnly = true
+	addExtractedLicense(&result, result.Hash.Sha256)
+	return result, nil
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIsLicenseLikelyFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "LICENSE", want: true},
+		{name: "a/b/licence.md", want: true},
+		{name: "UNLICENSE", want: true},
+		{name: "COPYING.LESSER", want: true},
+		{name: "NOTICE.txt", want: true},
+		{name: "COPYRIGHT", want: true},
+		{name: "README.md", want: true},
+		{name: "MIT-LICENSE", want: true},
+		{name: "apache_license.txt", want: true},
+		{name: "check_license.go", want: false},
+		{name: "scripts/validate-license.rb", want: false},
+		{name: "x-licence.txt.bak", want: false},
+		{name: "main.go", want: false},
+		{name: "licenses.go/main.c", want: false},
+		{name: "src/notices/index.js", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsLicenseLikelyFile(tt.name); got != tt.want {
+				t.Errorf("IsLicenseLikelyFile(%v) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_identifyLicensesQuick(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	mitText, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eplText, err := os.ReadFile(path.Join(testDataDir, "EPL-1.0.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ccText, err := os.ReadFile(path.Join(testDataDir, "CC-BY-3.0.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The MIT license at the top of a source file, and after more than the header of a source file and a license file,
+	// and the license texts longer than a header under names which are not license-likely
+	dir := t.TempDir()
+	filler := strings.Repeat("x := 1\n", QuickHeaderSize/7+1)
+	files := map[string]string{
+		"header.go":   "/*\n" + string(mitText) + "*/\n\n" + filler,
+		"trailer.go":  filler + "/*\n" + string(mitText) + "*/\n",
+		"LICENSE.txt": filler + string(mitText),
+		"epl.txt":     string(eplText),
+		"cc-by.txt":   string(ccText),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		file           string
+		quick          bool
+		want           string
+		wantHeaderOnly bool
+		wantStatus     string
+	}{
+		{name: "source header", file: "header.go", quick: true, want: "MIT", wantHeaderOnly: true, wantStatus: Licensed},
+		{name: "after the source header", file: "trailer.go", quick: true, wantHeaderOnly: true, wantStatus: Partial},
+		{name: "thorough", file: "trailer.go", quick: false, want: "MIT", wantStatus: Licensed},
+		{name: "license file", file: "LICENSE.txt", quick: true, want: "MIT", wantStatus: Licensed},
+		{name: "EPL-1.0 text", file: "epl.txt", quick: true, want: "EPL-1.0", wantStatus: Licensed},
+		{name: "CC-BY-3.0 text", file: "cc-by.txt", quick: true, want: "CC-BY-3.0", wantStatus: Licensed},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			filePath := filepath.Join(dir, tt.file)
+			got, err := IdentifyLicensesInFile(filePath, Options{Quick: tt.quick}, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInFile() error = %v", err)
+			}
+			if tt.want != "" {
+				if _, ok := got.Matches[tt.want]; !ok {
+					t.Errorf("IdentifyLicensesInFile() quick %v matches = %v, want %v", tt.quick, got.Matches, tt.want)
+				}
+			} else if len(got.Matches) > 0 {
+				t.Errorf("IdentifyLicensesInFile() quick %v matches = %v, want none", tt.quick, got.Matches)
+			}
+			if got.HeaderOnly != tt.wantHeaderOnly {
+				t.Errorf("IdentifyLicensesInFile() quick %v HeaderOnly = %v, want %v", tt.quick, got.HeaderOnly, tt.wantHeaderOnly)
+			}
+			if status := got.Status(); status != tt.wantStatus {
+				t.Errorf("IdentifyLicensesInFile() quick %v Status() = %v, want %v", tt.quick, status, tt.wantStatus)
+			}
+			if got.File != filePath {
+				t.Errorf("IdentifyLicensesInFile() file = %v, want %v", got.File, filePath)
+			}
+		})
+	}
+}
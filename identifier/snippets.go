@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/exp/slices"
+)
+
+// SnippetCoverage is the fraction of the (non-whitespace) text of a file which the license matches
+// must cover for the licenses to be attributed to the whole file. Otherwise, the matches are snippets.
+const SnippetCoverage = 0.8
+
+// spdxRefInvalidRE matches the characters which are not allowed in an SPDX element ID
+var spdxRefInvalidRE = regexp.MustCompile(`[^a-zA-Z0-9.-]+`)
+
+// Snippet is a license match embedded in a larger file, with ranges as used by an SPDX Snippet
+type Snippet struct {
+	// LicenseIDs are the licenses matched at the same range (e.g., templates with the same text)
+	LicenseIDs []string
+	// ByteRange is the [start, end] byte offsets in the file. The offsets are 1-based and inclusive.
+	ByteRange [2]int
+	// LineRange is the [start, end] line numbers in the file. The line numbers are 1-based and inclusive.
+	LineRange [2]int
+}
+
+// Snippets returns the license matches as snippets when the matches only cover part of the file.
+// Nothing is returned when the licenses should be attributed to the whole file.
+func Snippets(r IdentifierResults) []Snippet {
+	text := r.OriginalText
+	if len(r.Matches) == 0 || text == "" {
+		return nil
+	}
+
+	var all []Match
+	byRange := make(map[[2]int][]string)
+	for id, matches := range r.Matches {
+		all = append(all, matches...)
+		for _, rng := range mergeMatches(matches, len(text)) {
+			if !slices.Contains(byRange[rng], id) {
+				byRange[rng] = append(byRange[rng], id)
+			}
+		}
+	}
+	if strings.TrimSpace(text) == "" || Coverage(text, all) >= SnippetCoverage {
+		return nil
+	}
+
+	var ret []Snippet
+	for rng, ids := range byRange {
+		sort.Strings(ids)
+		ret = append(ret, Snippet{
+			LicenseIDs: ids,
+			ByteRange:  [2]int{rng[0] + 1, rng[1] + 1},
+			LineRange:  [2]int{lineNumber(text, rng[0]), lineNumber(text, rng[1])},
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].ByteRange[0] != ret[j].ByteRange[0] {
+			return ret[i].ByteRange[0] < ret[j].ByteRange[0]
+		}
+		return ret[i].ByteRange[1] < ret[j].ByteRange[1]
+	})
+	return ret
+}
+
+// Coverage returns the fraction of the (non-whitespace) text which the matches cover
+func Coverage(text string, matches []Match) float64 {
+	covered := make([]bool, len(text))
+	for _, rng := range mergeMatches(matches, len(text)) {
+		for i := rng[0]; i <= rng[1]; i++ {
+			covered[i] = true
+		}
+	}
+	total, inMatches := 0, 0
+	for i, c := range text {
+		if unicode.IsSpace(c) {
+			continue
+		}
+		total++
+		if covered[i] {
+			inMatches++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(inMatches) / float64(total)
+}
+
+// SPDXRef returns an SPDX element ID (SPDXRef-<kind>-<name>) with the invalid characters replaced
+func SPDXRef(kind string, name string) string {
+	return "SPDXRef-" + kind + "-" + strings.Trim(spdxRefInvalidRE.ReplaceAllString(name, "-"), "-.")
+}
+
+// mergeMatches returns the 0-based, inclusive ranges of the matches within the text, with the
+// overlapping matches (e.g., an alias within the full license text) merged
+func mergeMatches(matches []Match, length int) [][2]int {
+	var ranges [][2]int
+	for _, m := range matches {
+		begins, ends := m.Begins, m.Ends
+		if begins < 0 {
+			begins = 0
+		}
+		if ends >= length {
+			ends = length - 1
+		}
+		if begins <= ends {
+			ranges = append(ranges, [2]int{begins, ends})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var ret [][2]int
+	for _, rng := range ranges {
+		if last := len(ret) - 1; last >= 0 && rng[0] <= ret[last][1]+1 {
+			if rng[1] > ret[last][1] {
+				ret[last][1] = rng[1]
+			}
+			continue
+		}
+		ret = append(ret, rng)
+	}
+	return ret
+}
+
+// lineNumber returns the 1-based line number of the byte offset
+func lineNumber(text string, offset int) int {
+	return strings.Count(text[:offset], "\n") + 1
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSnippets(t *testing.T) {
+	t.Parallel()
+	header := "// Copyright 2020 Someone\n// SPDX-License-Identifier: MIT\n"
+	code := strings.Repeat("func f() { return doSomethingUseful() }\n", 20)
+	licenseText := strings.Repeat("license words ", 10)
+
+	tests := []struct {
+		name     string
+		results  IdentifierResults
+		expected []Snippet
+	}{
+		{
+			name: "header in a source file",
+			results: IdentifierResults{
+				OriginalText: header + code,
+				Matches: map[string][]Match{
+					// The alias inside the larger match is merged with it
+					"MIT": {{Begins: 26, Ends: 57}, {Begins: 53, Ends: 55}},
+				},
+			},
+			expected: []Snippet{{LicenseIDs: []string{"MIT"}, ByteRange: [2]int{27, 58}, LineRange: [2]int{2, 2}}},
+		},
+		{
+			name: "same text matched by two licenses",
+			results: IdentifierResults{
+				OriginalText: code + licenseText + code,
+				Matches: map[string][]Match{
+					"GPL-2.0-only":     {{Begins: len(code), Ends: len(code) + len(licenseText) - 1}},
+					"GPL-2.0-or-later": {{Begins: len(code), Ends: len(code) + len(licenseText) - 1}},
+				},
+			},
+			expected: []Snippet{{
+				LicenseIDs: []string{"GPL-2.0-only", "GPL-2.0-or-later"},
+				ByteRange:  [2]int{len(code) + 1, len(code) + len(licenseText)},
+				LineRange:  [2]int{21, 21},
+			}},
+		},
+		{
+			name: "license file is not a snippet",
+			results: IdentifierResults{
+				OriginalText: "\n\n" + licenseText + "\n",
+				Matches:      map[string][]Match{"MIT": {{Begins: 2, Ends: len(licenseText) + 1}}},
+			},
+		},
+		{
+			name:    "no matches",
+			results: IdentifierResults{OriginalText: code},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.expected, Snippets(tt.results)); d != "" {
+				t.Errorf("Snippets() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSPDXRef(t *testing.T) {
+	t.Parallel()
+	if got := SPDXRef("File", "./src/my file_1.go"); got != "SPDXRef-File-src-my-file-1.go" {
+		t.Errorf("SPDXRef() got %v", got)
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		text    string
+		matches []Match
+		want    float64
+	}{
+		{name: "empty", text: "", want: 0},
+		{name: "all", text: "MIT License", matches: []Match{{Begins: 0, Ends: 10}}, want: 1},
+		{name: "whitespace is not counted", text: "MIT\n\n  code", matches: []Match{{Begins: 0, Ends: 2}}, want: 3.0 / 7},
+		{name: "overlaps are counted once", text: "abcd", matches: []Match{{Begins: 0, Ends: 1}, {Begins: 1, Ends: 1}}, want: 0.5},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Coverage(tt.text, tt.matches); got != tt.want {
+				t.Errorf("Coverage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
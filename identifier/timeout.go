@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// matchLimits aborts the template matches which take longer than the template timeout, and
+// stops matching a file after the file deadline or when the context is canceled. The timeouts
+// and the cancellation are recorded for the results.
+type matchLimits struct {
+	templateTimeout time.Duration
+	fileDeadline    time.Time
+	ctx             context.Context
+
+	mu                sync.Mutex
+	fileTimedOut      bool
+	canceled          bool
+	timedOutTemplates []string
+}
+
+// newMatchLimits returns the limits for the options, or nil when there are no timeouts and no context
+func newMatchLimits(options Options) *matchLimits {
+	if options.TemplateTimeout <= 0 && options.FileTimeout <= 0 && options.Context == nil {
+		return nil
+	}
+	limits := &matchLimits{templateTimeout: options.TemplateTimeout, ctx: options.Context}
+	if options.FileTimeout > 0 {
+		limits.fileDeadline = time.Now().Add(options.FileTimeout)
+	}
+	return limits
+}
+
+// expired returns true (and records the file timeout or the cancellation) when the file deadline has passed or
+// the context is canceled
+func (l *matchLimits) expired() bool {
+	if l == nil {
+		return false
+	}
+	if l.ctx != nil && l.ctx.Err() != nil {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.canceled = true
+		return true
+	}
+	if l.fileDeadline.IsZero() || time.Now().Before(l.fileDeadline) {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.fileTimedOut = true
+	return true
+}
+
+// findMatchingPattern matches the pattern, but gives up when the template timeout, the file deadline, or the
+// cancellation of the context comes first.
+// Go regexps cannot be interrupted, so an abandoned match finishes in the background and its matches are dropped.
+func (l *matchLimits) findMatchingPattern(pattern *licenses.PrimaryPatterns, normalizedData normalizer.NormalizationData) ([]Match, error) {
+	if l == nil {
+		return FindMatchingPatternInNormalizedData(pattern, normalizedData)
+	}
+	if l.expired() {
+		return nil, nil
+	}
+
+	timeout := l.templateTimeout
+	fileTimeout := false
+	if !l.fileDeadline.IsZero() {
+		if untilDeadline := time.Until(l.fileDeadline); timeout <= 0 || untilDeadline < timeout {
+			timeout = untilDeadline
+			fileTimeout = true
+		}
+	}
+
+	type result struct {
+		matches []Match
+		err     error
+	}
+	ch := make(chan result, 1) // buffered so that an abandoned match does not block
+	go func() {
+		matches, err := FindMatchingPatternInNormalizedData(pattern, normalizedData)
+		ch <- result{matches: matches, err: err}
+	}()
+
+	// The nil channels of a missing timeout or context are never ready
+	var timerC <-chan time.Time
+	if timeout > 0 || fileTimeout {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timerC = timer.C
+	}
+	var done <-chan struct{}
+	if l.ctx != nil {
+		done = l.ctx.Done()
+	}
+	select {
+	case r := <-ch:
+		return r.matches, r.err
+	case <-done:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.canceled = true
+		return nil, nil
+	case <-timerC:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if fileTimeout {
+			l.fileTimedOut = true
+		} else {
+			Logger.Infof("Template %v timed out after %v", pattern.FileName, timeout)
+			l.timedOutTemplates = append(l.timedOutTemplates, pattern.FileName)
+		}
+		return nil, nil
+	}
+}
+
+// timedOut returns true if anything timed out or the matching was canceled (so the results are incomplete)
+func (l *matchLimits) timedOut() bool {
+	if l == nil {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.fileTimedOut || l.canceled || len(l.timedOutTemplates) > 0
+}
+
+// record adds the timeouts to the results
+func (l *matchLimits) record(licenseResults *IdentifierResults) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	licenseResults.TimedOut = l.fileTimedOut
+	licenseResults.Canceled = l.canceled
+	if len(l.timedOutTemplates) > 0 {
+		licenseResults.TimedOutTemplates = append([]string(nil), l.timedOutTemplates...)
+		sort.Strings(licenseResults.TimedOutTemplates)
+	}
+}
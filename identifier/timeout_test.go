@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func Test_identifyLicensesTimeouts(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := string(b)
+
+	tests := []struct {
+		name                  string
+		templateTimeout       time.Duration
+		fileTimeout           time.Duration
+		wantTimedOut          bool
+		wantTimedOutTemplates bool
+		canceled              bool
+		wantMIT               bool
+	}{
+		{name: "no timeouts", wantMIT: true},
+		{name: "long timeouts", templateTimeout: time.Minute, fileTimeout: time.Hour, wantMIT: true},
+		{name: "template timeout", templateTimeout: time.Nanosecond, wantTimedOutTemplates: true},
+		{name: "file timeout", fileTimeout: time.Nanosecond, wantTimedOut: true},
+		{name: "canceled", canceled: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cache, err := NewResultCache("", licenseLibrary)
+			if err != nil {
+				t.Fatalf("NewResultCache() error = %v", err)
+			}
+			options := defaultOptions()
+			options.NoExactHash = true
+			options.Cache = cache
+			options.TemplateTimeout = tt.templateTimeout
+			options.FileTimeout = tt.fileTimeout
+			if tt.canceled {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				options.Context = ctx
+			}
+			got, err := IdentifyLicensesInString(input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if got.TimedOut != tt.wantTimedOut {
+				t.Errorf("TimedOut = %v, want %v", got.TimedOut, tt.wantTimedOut)
+			}
+			if got.Canceled != tt.canceled {
+				t.Errorf("Canceled = %v, want %v", got.Canceled, tt.canceled)
+			}
+			if (len(got.TimedOutTemplates) > 0) != tt.wantTimedOutTemplates {
+				t.Errorf("TimedOutTemplates = %v, want any %v", got.TimedOutTemplates, tt.wantTimedOutTemplates)
+			}
+			if _, ok := got.Matches["MIT"]; ok != tt.wantMIT {
+				t.Errorf("matched MIT = %v, want %v", ok, tt.wantMIT)
+			}
+
+			// Incomplete results are not cached
+			nd := normalizer.NormalizationData{OriginalText: input}
+			if err := nd.NormalizeText(); err != nil {
+				t.Fatal(err)
+			}
+			timedOut := tt.wantTimedOut || tt.wantTimedOutTemplates || tt.canceled
+			if _, ok := cache.get(nd); ok == timedOut {
+				t.Errorf("cached = %v with timed out %v", ok, timedOut)
+			}
+		})
+	}
+}
+
+func Test_IdentifyLicensesInFiles_canceled(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	options := defaultOptions()
+	options.Context = ctx
+	var streamed int
+	options.OnResult = func(IdentifierResults) { streamed++ }
+	got, err := IdentifyLicensesInFiles([]string{path.Join(testDataDir, "MIT.txt"), path.Join(testDataDir, "MIT.txt")}, options, licenseLibrary)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("IdentifyLicensesInFiles() error = %v, want %v", err, context.Canceled)
+	}
+	if len(got) != 0 || streamed != 0 {
+		t.Errorf("IdentifyLicensesInFiles() = %v results (%v streamed), want the files to be skipped", len(got), streamed)
+	}
+}
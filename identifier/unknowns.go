@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// UnknownSimilarity is the (shingle Jaccard) similarity for a file to join a cluster of unknown licenses
+	UnknownSimilarity = 0.5
+	// unknownMinTerms is how many distinct legal terms make a file look like a license
+	unknownMinTerms = 3
+	// unknownShingleSize is the number of words in each shingle that is compared
+	unknownShingleSize = 3
+	// unknownExcerptSize is the max bytes of a cluster excerpt
+	unknownExcerptSize = 400
+)
+
+// unknownTermRegexps are the legal terms that are expected in license text (and rarely elsewhere)
+var unknownTermRegexps = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\blicen[cs]`),
+	regexp.MustCompile(`(?i)\bwarrant`),
+	regexp.MustCompile(`(?i)\bpermi(?:ssion|tted)`),
+	regexp.MustCompile(`(?i)\bredistribut`),
+	regexp.MustCompile(`(?i)\bcopyright`),
+	regexp.MustCompile(`(?i)\bliabl|\bliabilit`),
+	regexp.MustCompile(`(?i)\bpublic domain`),
+}
+
+// UnknownCluster is a group of files with similar license-looking text which matched no license
+type UnknownCluster struct {
+	// Representative is the file the other files were compared with
+	Representative string
+	// Files are all the files in the cluster (including the representative), sorted
+	Files []string
+	// Excerpt is the license-looking text from the representative file
+	Excerpt string
+}
+
+// IsUnknownLicense returns true if no license was matched but the text looks like license text
+func IsUnknownLicense(r IdentifierResults) bool {
+	if len(r.Matches) > 0 {
+		return false
+	}
+	terms := 0
+	for _, re := range unknownTermRegexps {
+		if re.MatchString(r.OriginalText) {
+			terms++
+		}
+	}
+	return terms >= unknownMinTerms
+}
+
+// ClusterUnknownLicenses groups the results with unknown licenses by the similarity of their
+// normalized text, so the unknown licenses can be reviewed (and added as custom patterns) in bulk.
+// The largest clusters come first.
+func ClusterUnknownLicenses(results []IdentifierResults) []UnknownCluster {
+	var unknowns []IdentifierResults
+	for _, r := range results {
+		if IsUnknownLicense(r) {
+			unknowns = append(unknowns, r)
+		}
+	}
+	sort.Slice(unknowns, func(i, j int) bool { return unknowns[i].File < unknowns[j].File })
+
+	var clusters []UnknownCluster
+	var shingles []map[uint64]bool // shingles of each cluster representative
+	for _, r := range unknowns {
+		s := shingle(r.NormalizedText)
+		best, bestSimilarity := -1, 0.0
+		for i, rep := range shingles {
+			if similarity := jaccard(s, rep); similarity >= UnknownSimilarity && similarity > bestSimilarity {
+				best, bestSimilarity = i, similarity
+			}
+		}
+		if best >= 0 {
+			clusters[best].Files = append(clusters[best].Files, r.File)
+			continue
+		}
+		clusters = append(clusters, UnknownCluster{Representative: r.File, Files: []string{r.File}, Excerpt: excerpt(r.OriginalText)})
+		shingles = append(shingles, s)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i].Files) > len(clusters[j].Files) })
+	return clusters
+}
+
+// shingle returns the hashes of each run of unknownShingleSize words
+func shingle(text string) map[uint64]bool {
+	words := strings.Fields(text)
+	ret := make(map[uint64]bool)
+	if len(words) < unknownShingleSize {
+		if len(words) > 0 {
+			ret[hashWords(words)] = true
+		}
+		return ret
+	}
+	for i := 0; i+unknownShingleSize <= len(words); i++ {
+		ret[hashWords(words[i:i+unknownShingleSize])] = true
+	}
+	return ret
+}
+
+func hashWords(words []string) uint64 {
+	h := fnv.New64a()
+	for _, w := range words {
+		_, _ = h.Write([]byte(w))
+		_, _ = h.Write([]byte{' '})
+	}
+	return h.Sum64()
+}
+
+// jaccard returns the size of the intersection over the size of the union
+func jaccard(a map[uint64]bool, b map[uint64]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	intersection := 0
+	for k := range a {
+		if b[k] {
+			intersection++
+		}
+	}
+	return float64(intersection) / float64(len(a)+len(b)-intersection)
+}
+
+// excerpt returns the text starting at the line with the first legal term, up to unknownExcerptSize bytes
+func excerpt(text string) string {
+	start := len(text)
+	for _, re := range unknownTermRegexps {
+		if loc := re.FindStringIndex(text); loc != nil && loc[0] < start {
+			start = loc[0]
+		}
+	}
+	if start == len(text) {
+		start = 0
+	}
+	start = strings.LastIndex(text[:start], "\n") + 1
+	ret := text[start:]
+	if len(ret) > unknownExcerptSize {
+		end := unknownExcerptSize
+		for end > 0 && !utf8.RuneStart(ret[end]) {
+			end--
+		}
+		ret = ret[:end]
+	}
+	return strings.TrimSpace(ret)
+}
@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClusterUnknownLicenses(t *testing.T) {
+	t.Parallel()
+	acme := "Copyright Acme Corp. This software is licensed only to Acme customers. Redistribution is not permitted. " +
+		"The software is provided without warranty of any kind and Acme is not liable for any damages."
+	acmeVariant := strings.Replace(acme, "Acme Corp.", "Acme Corporation", 1)
+	other := "Copyright Initech. Permission is granted to use this file on Tuesdays. This license grants no warranty " +
+		"and the file may only be redistributed with a printed copy of this notice."
+	code := "func main() { license := lookup(); fmt.Println(license) }"
+
+	unknown := func(file string, text string) IdentifierResults {
+		return IdentifierResults{File: file, OriginalText: text, NormalizedText: strings.ToLower(text)}
+	}
+	matched := unknown("mit/LICENSE", acme)
+	matched.Matches = map[string][]Match{"MIT": {{Begins: 0, Ends: 10}}}
+
+	results := []IdentifierResults{
+		unknown("c/LICENSE", acmeVariant),
+		unknown("b/COPYING", other),
+		unknown("a/LICENSE", acme),
+		unknown("main.go", code),
+		matched,
+	}
+	expected := []UnknownCluster{
+		{Representative: "a/LICENSE", Files: []string{"a/LICENSE", "c/LICENSE"}, Excerpt: acme},
+		{Representative: "b/COPYING", Files: []string{"b/COPYING"}, Excerpt: other},
+	}
+	if d := cmp.Diff(expected, ClusterUnknownLicenses(results)); d != "" {
+		t.Errorf("ClusterUnknownLicenses() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "starts at the line of the first term", text: "package x\n\n// Licensed to Acme\n// only\n", expected: "// Licensed to Acme\n// only"},
+		{name: "truncated", text: strings.Repeat("license ", 100), expected: strings.TrimSpace(strings.Repeat("license ", 50))},
+		{name: "truncated at a rune boundary", text: "warranty!" + strings.Repeat("é", 300), expected: "warranty!" + strings.Repeat("é", 195)},
+	}
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if actual := excerpt(tc.text); actual != tc.expected {
+				t.Errorf("excerpt() = %q, expected %q", actual, tc.expected)
+			}
+		})
+	}
+}
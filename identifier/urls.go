@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// LicenseURL is a license reference URL found in the text (e.g., https://opensource.org/licenses/MIT). It
+// implies the licenses which the SPDX license list refers to with the URL, but it is not a license match.
+type LicenseURL struct {
+	// URL is the text which was found
+	URL string
+	// IDs are the license IDs of the URL (sorted). Some URLs refer to several licenses (e.g., GPL-2.0-only
+	// and GPL-2.0-or-later).
+	IDs    []string
+	Begins int
+	Ends   int
+}
+
+var (
+	// urlRE matches the URLs, with or without a scheme (e.g., opensource.org/licenses/MIT)
+	urlRE = regexp.MustCompile(`(?i)\b(?:https?://)?(?:[a-z0-9-]+\.)+[a-z]{2,}/[^\s"'<>()\[\]{}` + "`" + `]*`)
+	// urlTrailerRE matches the punctuation which ends a sentence rather than the URL
+	urlTrailerRE = regexp.MustCompile(`[.,;:!?*]+$`)
+)
+
+// addLicenseURLs adds the license reference URLs in the text which are not in a license or header match
+// (e.g., the URL in the Apache-2.0 header is part of the header match)
+func addLicenseURLs(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults) {
+	if len(licenseLibrary.URLIndex) == 0 {
+		return
+	}
+	inMatch := func(begins, ends int) bool {
+		for _, byID := range []map[string][]Match{licenseResults.Matches, licenseResults.HeaderMatches} {
+			for _, matches := range byID {
+				for _, m := range matches {
+					if begins <= m.Ends && ends >= m.Begins {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	var found []LicenseURL
+	for _, loc := range urlRE.FindAllStringIndex(licenseResults.OriginalText, -1) {
+		u := licenseResults.OriginalText[loc[0]:loc[1]]
+		u = strings.TrimSuffix(u, urlTrailerRE.FindString(u))
+		ids := licenseLibrary.URLIndex.Lookup(u)
+		ends := loc[0] + len(u) - 1
+		if len(ids) == 0 || inMatch(loc[0], ends) {
+			continue
+		}
+		found = append(found, LicenseURL{URL: u, IDs: ids, Begins: loc[0], Ends: ends})
+	}
+	licenseResults.LicenseURLs = found
+}
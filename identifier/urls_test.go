@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_addLicenseURLs(t *testing.T) {
+	t.Parallel()
+	licenseLibrary := &licenses.LicenseLibrary{URLIndex: licenses.NewURLIndex(licenses.LicenseMap{
+		"MIT":        {LicenseInfo: licenses.LicenseInfo{SeeAlso: []string{"https://opensource.org/licenses/MIT"}}},
+		"CC-BY-4.0":  {LicenseInfo: licenses.LicenseInfo{SeeAlso: []string{"https://creativecommons.org/licenses/by/4.0/legalcode"}}},
+		"Apache-2.0": {LicenseInfo: licenses.LicenseInfo{SeeAlso: []string{"https://www.apache.org/licenses/LICENSE-2.0"}}},
+	})}
+	tests := []struct {
+		name    string
+		text    string
+		matches map[string][]Match
+		want    []LicenseURL
+	}{
+		{
+			name: "bare URLs",
+			text: "See opensource.org/licenses/MIT. Docs: https://creativecommons.org/licenses/by/4.0/ (or https://example.com/licenses/MIT)",
+			want: []LicenseURL{
+				{URL: "opensource.org/licenses/MIT", IDs: []string{"MIT"}, Begins: 4, Ends: 30},
+				{URL: "https://creativecommons.org/licenses/by/4.0/", IDs: []string{"CC-BY-4.0"}, Begins: 39, Ends: 82},
+			},
+		},
+		{
+			name:    "URL in a license match",
+			text:    "Licensed under the Apache License, Version 2.0. You may obtain a copy at http://www.apache.org/licenses/LICENSE-2.0",
+			matches: map[string][]Match{"Apache-2.0": {{Begins: 0, Ends: 114}}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results := IdentifierResults{OriginalText: tt.text, Matches: tt.matches}
+			addLicenseURLs(licenseLibrary, &results)
+			if d := cmp.Diff(tt.want, results.LicenseURLs); d != "" {
+				t.Errorf("addLicenseURLs() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
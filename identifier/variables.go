@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"strings"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// TemplateVariable is the text which matched a replaceable <<var>> section of a license template
+// (e.g., the copyright holder or organization)
+type TemplateVariable struct {
+	// Name is the name of the variable in the template (may be empty)
+	Name string
+	// Original is the text of the variable in the template
+	Original string
+	// Text is the matched text from the original input
+	Text string
+	// Begins and Ends are the positions of the matched text in the original input
+	Begins int
+	Ends   int
+}
+
+// skippedVariableNames are template variables with no information worth reporting
+var skippedVariableNames = map[string]bool{
+	"bullet": true,
+}
+
+// addTemplateVariables captures the template variables in each match of each license with templates
+func addTemplateVariables(licenseLibrary *licenses.LicenseLibrary, licenseResults *IdentifierResults, normalizedData normalizer.NormalizationData) {
+	for id, matches := range licenseResults.Matches {
+		var variables []TemplateVariable
+		for _, m := range matches {
+			variables = append(variables, findTemplateVariables(licenseLibrary.LicenseMap[id].PrimaryPatterns, m, normalizedData)...)
+		}
+		if len(variables) == 0 {
+			continue
+		}
+		if licenseResults.Variables == nil {
+			licenseResults.Variables = make(map[string][]TemplateVariable)
+		}
+		licenseResults.Variables[id] = variables
+	}
+}
+
+// findTemplateVariables re-matches the matched text with the first primary pattern that matches it to get the variables
+func findTemplateVariables(patterns []*licenses.PrimaryPatterns, m Match, nd normalizer.NormalizationData) []TemplateVariable {
+	if len(nd.IndexMap) == 0 {
+		return nil
+	}
+	begin := normalizedBegin(m.Begins, nd.IndexMap)
+	end := normalizedEnd(m.Ends, nd.IndexMap) + 1
+	if end > len(nd.NormalizedText) {
+		end = len(nd.NormalizedText)
+	}
+	if begin >= end {
+		return nil
+	}
+	text := nd.NormalizedText[begin:end]
+
+	for _, pattern := range patterns {
+		re, err := licenses.GenerateMatchingPatternFromSourceText(pattern)
+		if err != nil || re == nil {
+			continue
+		}
+		loc := re.FindStringSubmatchIndex(text)
+		if loc == nil {
+			continue
+		}
+		var variables []TemplateVariable
+		for _, c := range pattern.CaptureGroups {
+			if skippedVariableNames[c.Name] {
+				continue
+			}
+			i := re.SubexpIndex(licenses.VariableGroupName(c.GroupNumber))
+			if i < 0 || loc[2*i] < 0 || loc[2*i] >= loc[2*i+1] {
+				continue
+			}
+			v, ok := variableText(begin+loc[2*i], begin+loc[2*i+1], nd)
+			if !ok {
+				continue
+			}
+			v.Name = c.Name
+			v.Original = c.Original
+			variables = append(variables, v)
+		}
+		return variables
+	}
+	return nil
+}
+
+// variableText returns the original text for the normalized text [from, to), without the surrounding whitespace
+func variableText(from int, to int, nd normalizer.NormalizationData) (TemplateVariable, bool) {
+	// Replaced sections have -1 in the middle of the index map
+	for from < to && nd.IndexMap[from] < 0 {
+		from++
+	}
+	for to > from && nd.IndexMap[to-1] < 0 {
+		to--
+	}
+	if from >= to {
+		return TemplateVariable{}, false
+	}
+	begins := nd.IndexMap[from]
+	ends := nd.IndexMap[to-1]
+	if ends < begins || ends >= len(nd.OriginalText) {
+		return TemplateVariable{}, false
+	}
+	original := nd.OriginalText[begins : ends+1]
+	text := strings.TrimSpace(original)
+	if text == "" {
+		return TemplateVariable{}, false
+	}
+	begins += strings.Index(original, text)
+	return TemplateVariable{Text: text, Begins: begins, Ends: begins + len(text) - 1}, true
+}
@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_identifyLicensesVariables(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := licenseLibrary.AddAllSPDX(); err != nil {
+		t.Fatalf("licenseLibrary.AddAllSPDX() error = %v", err)
+	}
+	b, err := os.ReadFile(path.Join(testDataDir, "BSD-3-Clause.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the testdata copyright line with a real one
+	lines := strings.SplitN(string(b), "\n", 2)
+	copyright := "Copyright (c) 2021 Acme Widgets, Inc."
+	input := copyright + "\n" + lines[1]
+
+	tests := []struct {
+		name             string
+		captureVariables bool
+		wantCopyright    string
+	}{
+		{name: "disabled"},
+		{name: "enabled", captureVariables: true, wantCopyright: copyright},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			options := defaultOptions()
+			options.Enhancements.CaptureVariables = tt.captureVariables
+			got, err := IdentifyLicensesInString(input, options, licenseLibrary)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInString() error = %v", err)
+			}
+			if _, ok := got.Matches["BSD-3-Clause"]; !ok {
+				t.Fatalf("IdentifyLicensesInString() did not match BSD-3-Clause: %v", got.Matches)
+			}
+			if !tt.captureVariables {
+				if got.Variables != nil {
+					t.Errorf("Variables = %v, want nil", got.Variables)
+				}
+				return
+			}
+
+			var found bool
+			for _, v := range got.Variables["BSD-3-Clause"] {
+				if v.Name == "bullet" {
+					t.Errorf("Variables has a bullet: %+v", v)
+				}
+				if v.Text != input[v.Begins:v.Ends+1] {
+					t.Errorf("Variable %v text %q is not the input at %v:%v", v.Name, v.Text, v.Begins, v.Ends)
+				}
+				if v.Name == "copyright" {
+					found = true
+					if v.Text != tt.wantCopyright {
+						t.Errorf("copyright variable = %q, want %q", v.Text, tt.wantCopyright)
+					}
+					if v.Original == "" {
+						t.Error("copyright variable has no original template text")
+					}
+				}
+			}
+			if !found {
+				t.Errorf("Variables has no copyright: %+v", got.Variables)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package identifier
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// The Symlinks policies of the directory scans
+const (
+	// SymlinksFiles scans the symlinks to files and skips the symlinks to dirs (the default)
+	SymlinksFiles = "files"
+	// SymlinksFollow also walks the symlinked dirs, skipping a symlink to a dir which is being walked (a loop)
+	SymlinksFollow = "follow"
+	// SymlinksSkip skips all the symlinks
+	SymlinksSkip = "skip"
+)
+
+// The SpecialFiles policies of the directory scans (for the sockets, named pipes, and devices)
+const (
+	// SpecialFilesSkip skips the special files (the default)
+	SpecialFilesSkip = "skip"
+	// SpecialFilesError fails the scan on a special file
+	SpecialFilesError = "error"
+)
+
+// The HardLinks policies of the directory scans (for the paths of the same file, including the followed symlinks)
+const (
+	// HardLinksScan scans each path of a file (the default)
+	HardLinksScan = "scan"
+	// HardLinksOnce scans the first path of a file, and the other paths have its results with the HardLinkOf
+	HardLinksOnce = "once"
+	// HardLinksSkip scans the first path of a file and skips the other paths
+	HardLinksSkip = "skip"
+)
+
+// specialFileModes are the file types of the special files
+const specialFileModes = fs.ModeSocket | fs.ModeNamedPipe | fs.ModeDevice | fs.ModeCharDevice | fs.ModeIrregular
+
+// WalkOptions are how IdentifyLicensesInDirectory walks the directory (the empty fields are the defaults)
+type WalkOptions struct {
+	// Symlinks is SymlinksFiles, SymlinksFollow, or SymlinksSkip
+	Symlinks string
+	// SpecialFiles is SpecialFilesSkip or SpecialFilesError
+	SpecialFiles string
+	// HardLinks is HardLinksScan, HardLinksOnce, or HardLinksSkip
+	HardLinks string
+	// AllFiles scans all the files, instead of skipping the binary and media files (see SkipFileType)
+	AllFiles bool
+	// OnSkipped is called with each file skipped by its type, with its MIME type (nil to not report them)
+	OnSkipped func(filePath string, mimeType string)
+}
+
+// Validate returns an error for an unknown policy
+func (w WalkOptions) Validate() error {
+	switch w.Symlinks {
+	case "", SymlinksFiles, SymlinksFollow, SymlinksSkip:
+	default:
+		return fmt.Errorf("invalid symlinks %q (expected %v, %v, or %v)", w.Symlinks, SymlinksFiles, SymlinksFollow, SymlinksSkip)
+	}
+	switch w.SpecialFiles {
+	case "", SpecialFilesSkip, SpecialFilesError:
+	default:
+		return fmt.Errorf("invalid special files %q (expected %v or %v)", w.SpecialFiles, SpecialFilesSkip, SpecialFilesError)
+	}
+	switch w.HardLinks {
+	case "", HardLinksScan, HardLinksOnce, HardLinksSkip:
+	default:
+		return fmt.Errorf("invalid hard links %q (expected %v, %v, or %v)", w.HardLinks, HardLinksScan, HardLinksOnce, HardLinksSkip)
+	}
+	return nil
+}
+
+// dirWalker lists the files to scan in a directory (sorted by path, as filepath.WalkDir)
+type dirWalker struct {
+	options  WalkOptions
+	binaries bool
+	files    []string
+	infos    []fs.FileInfo
+}
+
+// walkFiles returns the non-empty files to scan in the dir (with the executables and libraries for the binaries option),
+// and the other paths of the files with more than one path (by the first path) unless the HardLinks are scanned
+func walkFiles(dirPath string, options WalkOptions, binaries bool) (files []string, links map[string][]string, err error) {
+	if err := options.Validate(); err != nil {
+		return nil, nil, err
+	}
+	fi, err := os.Stat(dirPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !fi.IsDir() {
+		return nil, nil, fmt.Errorf("%v is not a directory", dirPath)
+	}
+	w := dirWalker{options: options, binaries: binaries}
+	if err := w.walk(dirPath, []fs.FileInfo{fi}); err != nil {
+		return nil, nil, err
+	}
+	if options.HardLinks == "" || options.HardLinks == HardLinksScan {
+		return w.files, nil, nil
+	}
+	files, links = w.hardLinks()
+	return files, links, nil
+}
+
+// walk adds the files of the dir, with the dirs being walked (to detect the symlink loops)
+func (w *dirWalker) walk(dirPath string, walking []fs.FileInfo) error {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		p := filepath.Join(dirPath, e.Name())
+		fi, err := e.Info()
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&fs.ModeSymlink != 0 {
+			if w.options.Symlinks == SymlinksSkip {
+				Logger.Debugf("Skipping the symlink %v", p)
+				continue
+			}
+			if fi, err = os.Stat(p); err != nil {
+				Logger.Warningf("Skipping the broken symlink %v: %v", p, err)
+				continue
+			}
+			if fi.IsDir() && w.options.Symlinks != SymlinksFollow {
+				Logger.Debugf("Skipping the symlink %v to a directory", p)
+				continue
+			}
+		}
+		switch {
+		case fi.IsDir():
+			if isWalking(walking, fi) {
+				Logger.Warningf("Skipping the symlink %v to a directory which is being walked (a loop)", p)
+				continue
+			}
+			if err := w.walk(p, append(walking, fi)); err != nil {
+				return err
+			}
+		case fi.Mode()&specialFileModes != 0:
+			if w.options.SpecialFiles == SpecialFilesError {
+				return fmt.Errorf("%v is a special file (%v)", p, fi.Mode().Type())
+			}
+			Logger.Debugf("Skipping the special file %v (%v)", p, fi.Mode().Type())
+		case fi.Size() > 0:
+			if !w.options.AllFiles {
+				mimeType, skip, err := SkipFileType(p, w.binaries)
+				if err != nil {
+					return err
+				}
+				if skip {
+					Logger.Debugf("Skipping %v (%v)", p, mimeType)
+					if w.options.OnSkipped != nil {
+						w.options.OnSkipped(p, mimeType)
+					}
+					continue
+				}
+			}
+			w.files = append(w.files, p)
+			w.infos = append(w.infos, fi)
+		}
+	}
+	return nil
+}
+
+// isWalking returns true when the dir is one of the dirs being walked
+func isWalking(walking []fs.FileInfo, dir fs.FileInfo) bool {
+	for _, w := range walking {
+		if os.SameFile(w, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// hardLinks returns the first path of each file, and the other paths of the files with more than one path (by
+// the first path) for HardLinksOnce
+func (w *dirWalker) hardLinks() (files []string, links map[string][]string) {
+	// Only the files of the same size may be the same file
+	bySize := make(map[int64][]int)
+	for i, fi := range w.infos {
+		first := -1
+		for _, j := range bySize[fi.Size()] {
+			if os.SameFile(w.infos[j], fi) {
+				first = j
+				break
+			}
+		}
+		if first == -1 {
+			bySize[fi.Size()] = append(bySize[fi.Size()], i)
+			files = append(files, w.files[i])
+			continue
+		}
+		if w.options.HardLinks == HardLinksSkip {
+			Logger.Debugf("Skipping %v (the same file as %v)", w.files[i], w.files[first])
+			continue
+		}
+		if links == nil {
+			links = make(map[string][]string)
+		}
+		links[w.files[first]] = append(links[w.files[first]], w.files[i])
+	}
+	return files, links
+}
+
+// hardLinkResult returns the results of the first path of a file as the results of another path of the file
+func hardLinkResult(result IdentifierResults, filePath string) IdentifierResults {
+	result.HardLinkOf = result.File
+	result.File = filePath
+	return result
+}
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package identifier
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_walkFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	mustWrite := func(name string, content string) {
+		t.Helper()
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mustWrite("LICENSE", "MIT")
+	mustWrite("empty", "")
+	mustWrite("lib/COPYING", "ISC")
+	if err := os.Link(filepath.Join(dir, "LICENSE"), filepath.Join(dir, "lib", "LICENSE.hard")); err != nil {
+		t.Skipf("hard links are not supported: %v", err)
+	}
+	// A symlink to a file, to a directory, to a parent directory (a loop), and to nothing
+	if err := os.Symlink(filepath.Join(dir, "lib", "COPYING"), filepath.Join(dir, "COPYING.link")); err != nil {
+		t.Skipf("symlinks are not supported: %v", err)
+	}
+	mustWrite("other/NOTICE", "Apache-2.0")
+	if err := os.Symlink(filepath.Join(dir, "other"), filepath.Join(dir, "lib", "other.link")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(dir, filepath.Join(dir, "other", "loop")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(dir, "missing"), filepath.Join(dir, "broken.link")); err != nil {
+		t.Fatal(err)
+	}
+	hasSocket := false
+	if l, err := net.Listen("unix", filepath.Join(dir, "socket")); err == nil {
+		t.Cleanup(func() { l.Close() })
+		hasSocket = true
+	}
+
+	tests := []struct {
+		name      string
+		options   WalkOptions
+		wantFiles []string
+		wantLinks map[string][]string
+		wantErr   bool
+		socket    bool
+	}{
+		{
+			name:      "defaults",
+			wantFiles: []string{"COPYING.link", "LICENSE", "lib/COPYING", "lib/LICENSE.hard", "other/NOTICE"},
+		},
+		{
+			name:      "skip symlinks",
+			options:   WalkOptions{Symlinks: SymlinksSkip},
+			wantFiles: []string{"LICENSE", "lib/COPYING", "lib/LICENSE.hard", "other/NOTICE"},
+		},
+		{
+			name:      "follow symlinks",
+			options:   WalkOptions{Symlinks: SymlinksFollow},
+			wantFiles: []string{"COPYING.link", "LICENSE", "lib/COPYING", "lib/LICENSE.hard", "lib/other.link/NOTICE", "other/NOTICE"},
+		},
+		{
+			name:      "hard links once",
+			options:   WalkOptions{Symlinks: SymlinksSkip, HardLinks: HardLinksOnce},
+			wantFiles: []string{"LICENSE", "lib/COPYING", "other/NOTICE"},
+			wantLinks: map[string][]string{"LICENSE": {"lib/LICENSE.hard"}},
+		},
+		{
+			name:      "hard links and followed symlinks once",
+			options:   WalkOptions{Symlinks: SymlinksFollow, HardLinks: HardLinksOnce},
+			wantFiles: []string{"COPYING.link", "LICENSE", "lib/other.link/NOTICE"},
+			wantLinks: map[string][]string{
+				"COPYING.link":          {"lib/COPYING"},
+				"LICENSE":               {"lib/LICENSE.hard"},
+				"lib/other.link/NOTICE": {"other/NOTICE"},
+			},
+		},
+		{
+			name:      "skip hard links",
+			options:   WalkOptions{HardLinks: HardLinksSkip},
+			wantFiles: []string{"COPYING.link", "LICENSE", "other/NOTICE"},
+		},
+		{
+			name:    "special files error",
+			options: WalkOptions{SpecialFiles: SpecialFilesError},
+			socket:  true,
+			wantErr: true,
+		},
+		{
+			name:    "invalid",
+			options: WalkOptions{Symlinks: "always"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.socket && !hasSocket {
+				t.Skip("unix sockets are not supported")
+			}
+			files, links, err := walkFiles(dir, tt.options, false)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("walkFiles() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			rel := func(p string) string {
+				r, err := filepath.Rel(dir, p)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return filepath.ToSlash(r)
+			}
+			var gotFiles []string
+			for _, f := range files {
+				gotFiles = append(gotFiles, rel(f))
+			}
+			if d := cmp.Diff(tt.wantFiles, gotFiles); d != "" {
+				t.Errorf("walkFiles() files mismatch (-want +got):\n%s", d)
+			}
+			var gotLinks map[string][]string
+			for first, others := range links {
+				if gotLinks == nil {
+					gotLinks = make(map[string][]string)
+				}
+				for _, o := range others {
+					gotLinks[rel(first)] = append(gotLinks[rel(first)], rel(o))
+				}
+			}
+			if d := cmp.Diff(tt.wantLinks, gotLinks); d != "" {
+				t.Errorf("walkFiles() links mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
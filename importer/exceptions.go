@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// exceptionsDir is the subdir of the exceptions in the template, text, and json dirs of a license-list-data release
+const exceptionsDir = "exceptions"
+
+// spdxExceptionDetails is the JSON of an exception of a license-list-data release (json/exceptions/<id>.json)
+type spdxExceptionDetails struct {
+	LicenseExceptionID       string `json:"licenseExceptionId"`
+	LicenseExceptionText     string `json:"licenseExceptionText"`
+	LicenseExceptionTemplate string `json:"licenseExceptionTemplate"`
+}
+
+// readExceptionSources returns the template and the text of an exception of a license-list-data release (the
+// name has the deprecated_ prefix of a deprecated exception), from the template and text dirs (or their
+// exceptions subdirs), or else from the json/exceptions/<id>.json details. The error is fs.ErrNotExist when the
+// release has no template or text of the exception.
+func readExceptionSources(addAllDir string, id string, name string) (templateFile string, templateBytes []byte, textBytes []byte, err error) {
+	templateFile, templateBytes, err = readFirst(
+		filepath.Join(addAllDir, "template", name+".template.txt"),
+		filepath.Join(addAllDir, "template", exceptionsDir, name+".template.txt"),
+		filepath.Join(addAllDir, "template", exceptionsDir, id+".template.txt"),
+	)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, nil, err
+	}
+	_, textBytes, err = readFirst(
+		filepath.Join(addAllDir, "text", name+".txt"),
+		filepath.Join(addAllDir, "text", id+".txt"),
+		filepath.Join(addAllDir, "text", exceptionsDir, name+".txt"),
+		filepath.Join(addAllDir, "text", exceptionsDir, id+".txt"),
+	)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", nil, nil, err
+	}
+	if templateBytes != nil && textBytes != nil {
+		return templateFile, templateBytes, textBytes, nil
+	}
+
+	detailsFile := filepath.Join(addAllDir, "json", exceptionsDir, id+".json")
+	b, err := os.ReadFile(detailsFile)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	var details spdxExceptionDetails
+	if err := json.Unmarshal(b, &details); err != nil {
+		return "", nil, nil, fmt.Errorf("unmarshal exception JSON from %v error: %w", detailsFile, err)
+	}
+	if templateBytes == nil && details.LicenseExceptionTemplate != "" {
+		templateFile, templateBytes = detailsFile, []byte(details.LicenseExceptionTemplate)
+	}
+	if textBytes == nil && details.LicenseExceptionText != "" {
+		textBytes = []byte(details.LicenseExceptionText)
+	}
+	if templateBytes == nil || textBytes == nil {
+		return "", nil, nil, fmt.Errorf("exception %v has no template or text in %v: %w", id, addAllDir, fs.ErrNotExist)
+	}
+	return templateFile, templateBytes, textBytes, nil
+}
+
+// readFirst reads the first of the files which exists
+func readFirst(files ...string) (string, []byte, error) {
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return f, b, err
+	}
+	return "", nil, fs.ErrNotExist
+}
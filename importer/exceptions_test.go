@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestAddAllSPDXTemplatesExceptions(t *testing.T) {
+	t.Parallel()
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join("../resources/spdx/default", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+	bisonText := read("testdata/Bison-exception-2.2.txt")
+	autoconfText := read("testdata/Autoconf-exception-2.0.txt")
+	autoconfDetails, err := json.Marshal(spdxExceptionDetails{
+		LicenseExceptionID:       "Autoconf-exception-2.0",
+		LicenseExceptionText:     autoconfText,
+		LicenseExceptionTemplate: read("template/Autoconf-exception-2.0.template.txt"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a license-list-data release with a license in the template dir, an exception in the exceptions subdirs,
+	// an exception only in its JSON details, and an exception without a template
+	src := t.TempDir()
+	for name, content := range map[string]string{
+		"json/licenses.json": `{"licenseListVersion": "9.98", "licenses": [{"licenseId": "0BSD", "name": "BSD Zero Clause License"}]}`,
+		"json/exceptions.json": `{"licenseListVersion": "9.98", "exceptions": [
+			{"licenseExceptionId": "Bison-exception-2.2", "name": "Bison exception 2.2"},
+			{"licenseExceptionId": "Autoconf-exception-2.0", "name": "Autoconf exception 2.0"},
+			{"licenseExceptionId": "Missing-exception", "name": "Missing exception"}]}`,
+		"template/0BSD.template.txt": read("template/0BSD.template.txt"),
+		"text/0BSD.txt":              read("testdata/0BSD.txt"),
+		"template/exceptions/Bison-exception-2.2.template.txt": read("template/Bison-exception-2.2.template.txt"),
+		"text/exceptions/Bison-exception-2.2.txt":              bisonText,
+		"json/exceptions/Autoconf-exception-2.0.json":          string(autoconfDetails),
+	} {
+		if err := os.MkdirAll(filepath.Join(src, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources := t.TempDir()
+	cfg.Set("addAll", src)
+	cfg.Set(licenses.Resources, resources)
+	if err := AddAllSPDXTemplates(cfg); err != nil {
+		t.Fatalf("AddAllSPDXTemplates() error = %v", err)
+	}
+
+	for _, f := range []string{
+		"template/Bison-exception-2.2.template.txt", "precheck/Bison-exception-2.2.json", "testdata/Bison-exception-2.2.txt",
+		"template/Autoconf-exception-2.0.template.txt", "precheck/Autoconf-exception-2.0.json", "testdata/Autoconf-exception-2.0.txt",
+	} {
+		if _, err := os.Stat(filepath.Join(resources, "spdx", "9.98", f)); err != nil {
+			t.Errorf("imported file %v error = %v", f, err)
+		}
+	}
+	b, err := os.ReadFile(filepath.Join(resources, "spdx", "9.98", licenses.ImportManifestFile))
+	if err != nil {
+		t.Fatalf("read the import manifest error = %v", err)
+	}
+	var manifest licenses.ImportManifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatalf("unmarshal the import manifest error = %v", err)
+	}
+	want := []licenses.ImportedTemplate{
+		{ID: "0BSD", Status: licenses.ImportValid},
+		{ID: "Bison-exception-2.2", Status: licenses.ImportValid},
+		{ID: "Autoconf-exception-2.0", Status: licenses.ImportValid},
+	}
+	if d := cmp.Diff(want, manifest.Templates, cmpopts.IgnoreFields(licenses.ImportedTemplate{}, "Error")); d != "" {
+		t.Errorf("import manifest templates mismatch (-want +got):\n%s", d)
+	}
+
+	// the imported exceptions are matched with their prechecks
+	cfg.Set(licenses.SPDX, "9.98")
+	ll, err := licenses.NewLicenseLibrary(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	for id, text := range map[string]string{"Bison-exception-2.2": bisonText, "Autoconf-exception-2.0": autoconfText} {
+		if !ll.LicenseMap[id].LicenseInfo.SPDXException {
+			t.Errorf("LicenseMap[%v] is not an SPDX exception", id)
+		}
+		results, err := identifier.IdentifyLicensesInString(text, identifier.Options{}, ll)
+		if err != nil {
+			t.Fatalf("IdentifyLicensesInString() error = %v", err)
+		}
+		if _, ok := results.Matches[id]; !ok {
+			t.Errorf("IdentifyLicensesInString() of the %v text has no match of %v", id, id)
+		}
+	}
+}
@@ -3,11 +3,11 @@
 package importer
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path"
 	"path/filepath"
-	"runtime"
 	"strings"
 
 	"github.com/mrutkows/sbom-utility/log"
@@ -17,25 +17,20 @@ import (
 	"github.com/IBM/license-scanner/licenses"
 )
 
-var (
-	Logger            = log.NewLogger(log.INFO)
-	_, thisFile, _, _ = runtime.Caller(0) // Dirs/files are relative to this file
-	thisDir           = filepath.Dir(thisFile)
-)
+var Logger = log.NewLogger(log.INFO)
 
+// AddAllSPDXTemplates imports the licenses and exceptions from an SPDX license-list-data release (a relative
+// --addAll path is relative to the working directory). The exceptions which are not in the template dir are
+// imported from its exceptions subdir or their json/exceptions details (see readExceptionSources).
 func AddAllSPDXTemplates(cfg *viper.Viper) error {
-	// input dir is relative to root (if not an absolute path)
 	addAllDir := cfg.GetString("addAll")
-
-	if !path.IsAbs(addAllDir) {
-		addAllDir = path.Join(thisDir, "..", addAllDir)
-	}
+	manifest := newImportManifest(addAllDir, licenses.ImportLicenseListData, "")
 
 	// sources
-	licensesJSON := path.Join(addAllDir, "json", "licenses.json")
-	exceptionsJSON := path.Join(addAllDir, "json", "exceptions.json")
-	templateSrcDir := path.Join(addAllDir, "template")
-	textSrcDir := path.Join(addAllDir, "text")
+	licensesJSON := filepath.Join(addAllDir, "json", "licenses.json")
+	exceptionsJSON := filepath.Join(addAllDir, "json", "exceptions.json")
+	templateSrcDir := filepath.Join(addAllDir, "template")
+	textSrcDir := filepath.Join(addAllDir, "text")
 
 	SPDXLicenseListBytes, err := os.ReadFile(licensesJSON)
 	if err != nil {
@@ -60,6 +55,7 @@ func AddAllSPDXTemplates(cfg *viper.Viper) error {
 	if licenseListVersion != exceptionsListVersion {
 		return fmt.Errorf("license list version '%v' does not match exception list version '%v'", licenseListVersion, exceptionsListVersion)
 	}
+	manifest.LicenseListVersion = licenseListVersion
 
 	templateDEs, err := os.ReadDir(templateSrcDir)
 	if err != nil {
@@ -81,24 +77,30 @@ func AddAllSPDXTemplates(cfg *viper.Viper) error {
 		return err
 	}
 
-	if err := os.WriteFile(path.Join(jsonDestDir, "licenses.json"), SPDXLicenseListBytes, 0o600); err != nil {
+	if err := os.WriteFile(filepath.Join(jsonDestDir, "licenses.json"), SPDXLicenseListBytes, 0o600); err != nil {
 		return err
 	}
-	if err := os.WriteFile(path.Join(jsonDestDir, "exceptions.json"), SPDXExceptionsListBytes, 0o600); err != nil {
+	if err := os.WriteFile(filepath.Join(jsonDestDir, "exceptions.json"), SPDXExceptionsListBytes, 0o600); err != nil {
 		return err
 	}
 
 	errorCount := 0
+	imported := make(map[string]bool, len(templateDEs))
 	for _, de := range templateDEs {
+		if de.IsDir() {
+			continue // e.g., the exceptions subdir of some releases, imported below
+		}
 		templateName := de.Name()
 		id := strings.TrimSuffix(templateName, ".template.txt")
-		templateFile := path.Join(templateSrcDir, templateName)
-		textFile := path.Join(textSrcDir, id+".txt")
+		imported[id] = true
+		templateFile := filepath.Join(templateSrcDir, templateName)
+		textFile := filepath.Join(textSrcDir, id+".txt")
 
-		if err := ValidateSPDXTemplateWithLicenseText(id, templateFile, textFile, templateDestDir, preCheckDestDir, textDestDir); err != nil {
+		err := ValidateSPDXTemplateWithLicenseText(id, templateFile, textFile, templateDestDir, preCheckDestDir, textDestDir)
+		if err != nil {
 			deprecatedPrefix := "deprecated_"
 			if strings.HasPrefix(id, deprecatedPrefix) {
-				altTextFile := path.Join(textSrcDir, strings.TrimPrefix(id+".txt", deprecatedPrefix))
+				altTextFile := filepath.Join(textSrcDir, strings.TrimPrefix(id+".txt", deprecatedPrefix))
 				Logger.Infof("template ID %v is not valid retrying w/o testdata prefix", id)
 				err = ValidateSPDXTemplateWithLicenseText(id, templateFile, altTextFile, templateDestDir, preCheckDestDir, textDestDir)
 			}
@@ -107,6 +109,37 @@ func AddAllSPDXTemplates(cfg *viper.Viper) error {
 				errorCount++
 			}
 		}
+		addImportedTemplate(manifest, id, err)
+	}
+
+	// the exceptions which have no template in the template dir: from its exceptions subdir, or from their
+	// JSON details, validated and prechecked like the licenses
+	for _, se := range exceptionsList.Exceptions {
+		id := se.LicenseExceptionID
+		name := id
+		if se.IsDeprecatedLicenseID {
+			name = "deprecated_" + id
+		}
+		if imported[name] {
+			continue
+		}
+		templateFile, templateBytes, textBytes, err := readExceptionSources(addAllDir, id, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			Logger.Warningf("Skipping exception %v without a template and text in %v", id, addAllDir)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = validateAndWrite(name, templateBytes, textBytes, templateFile, templateDestDir, preCheckDestDir, textDestDir); err != nil {
+			_ = Logger.Errorf("template ID %v is not valid", name)
+			errorCount++
+		}
+		addImportedTemplate(manifest, name, err)
+	}
+
+	if err := writeImportManifest(manifest, rd); err != nil {
+		return err
 	}
 	if errorCount > 0 {
 		return fmt.Errorf("%v templates could not be validated", errorCount)
@@ -131,6 +164,6 @@ func createEmptyLicenseListDataResourceDirs(dirs ...string) error {
 }
 
 func getDestPath(rd string, spdxVersionDir string, dir string) string {
-	destPath := path.Join(rd, "spdx", spdxVersionDir, dir)
+	destPath := filepath.Join(rd, "spdx", spdxVersionDir, dir)
 	return destPath
 }
@@ -6,7 +6,7 @@ package importer
 
 import (
 	"os"
-	"path"
+	"path/filepath"
 	"testing"
 
 	"github.com/mrutkows/sbom-utility/log"
@@ -131,13 +131,13 @@ func TestImporter_Validate(t *testing.T) {
 		t.Run(tt.reasons, func(t *testing.T) {
 			t.Parallel()
 			id := tt.id
-			templateFile := path.Join(testData, id+".template.txt")
+			templateFile := filepath.Join(testData, id+".template.txt")
 			templateBytes, err := os.ReadFile(templateFile)
 			if err != nil {
 				t.Errorf("ID: %v Read template file error: %v", id, err)
 				return
 			}
-			textFile := path.Join(testData, id+".txt")
+			textFile := filepath.Join(testData, id+".txt")
 			textBytes, err := os.ReadFile(textFile)
 			if err != nil {
 				t.Errorf("ID: %v Read text file error: %v", id, err)
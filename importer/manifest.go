@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/version"
+)
+
+// newImportManifest starts the import manifest of an import from the source dir (recorded as an absolute path)
+func newImportManifest(source string, format string, licenseListVersion string) *licenses.ImportManifest {
+	if abs, err := filepath.Abs(source); err == nil {
+		source = abs
+	}
+	return &licenses.ImportManifest{
+		Source:             source,
+		Format:             format,
+		LicenseListVersion: licenseListVersion,
+		ScannerVersion:     version.Get().Version,
+		Started:            time.Now().UTC(),
+		Templates:          []licenses.ImportedTemplate{},
+	}
+}
+
+// addImportedTemplate adds the validation status of a template to the import manifest (invalid with its error)
+func addImportedTemplate(m *licenses.ImportManifest, id string, err error) *licenses.ImportedTemplate {
+	t := licenses.ImportedTemplate{ID: id, Status: licenses.ImportValid}
+	if err != nil {
+		t.Status, t.Error = licenses.ImportInvalid, err.Error()
+	}
+	m.Templates = append(m.Templates, t)
+	return &m.Templates[len(m.Templates)-1]
+}
+
+// writeImportManifest finishes the import manifest and writes it in the SPDX resources directory of the import
+// (with the invalid templates too, so it is written when the import fails validation)
+func writeImportManifest(m *licenses.ImportManifest, rd string) error {
+	m.Finished = time.Now().UTC()
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(rd, "spdx", m.LicenseListVersion, licenses.ImportManifestFile), b, 0o600)
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestAddAllSPDXTemplatesImportManifest(t *testing.T) {
+	t.Parallel()
+	// a license-list-data release with a valid template and a template which does not match its text
+	src := t.TempDir()
+	template, err := os.ReadFile(filepath.Join("../testdata/validator", "0BSD.template.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(filepath.Join("../testdata/validator", "0BSD.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"json/licenses.json":            `{"licenseListVersion": "9.99", "licenses": []}`,
+		"json/exceptions.json":          `{"licenseListVersion": "9.99", "exceptions": []}`,
+		"template/0BSD.template.txt":    string(template),
+		"text/0BSD.txt":                 string(text),
+		"template/Bad-1.0.template.txt": "The Bad License permits everything.",
+		"text/Bad-1.0.txt":              "The Good License permits nothing.",
+	} {
+		if err := os.MkdirAll(filepath.Join(src, filepath.Dir(name)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resources := t.TempDir()
+	cfg := viper.New()
+	cfg.Set("addAll", src)
+	cfg.Set(licenses.Resources, resources)
+	if err := AddAllSPDXTemplates(cfg); err == nil {
+		t.Errorf("AddAllSPDXTemplates() error = nil, want the invalid template error")
+	}
+
+	b, err := os.ReadFile(filepath.Join(resources, "spdx", "9.99", licenses.ImportManifestFile))
+	if err != nil {
+		t.Fatalf("read the import manifest error = %v", err)
+	}
+	var got licenses.ImportManifest
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("unmarshal the import manifest error = %v", err)
+	}
+	want := licenses.ImportManifest{
+		Source:             src,
+		Format:             licenses.ImportLicenseListData,
+		LicenseListVersion: "9.99",
+		Templates: []licenses.ImportedTemplate{
+			{ID: "0BSD", Status: licenses.ImportValid},
+			{ID: "Bad-1.0", Status: licenses.ImportInvalid},
+		},
+	}
+	if d := cmp.Diff(want, got, cmpopts.IgnoreFields(licenses.ImportManifest{}, "ScannerVersion", "Started", "Finished"),
+		cmpopts.IgnoreFields(licenses.ImportedTemplate{}, "Error")); d != "" {
+		t.Errorf("import manifest mismatch (-want +got):\n%s", d)
+	}
+	if got.Started.IsZero() || got.Finished.Before(got.Started) {
+		t.Errorf("import manifest started %v and finished %v", got.Started, got.Finished)
+	}
+	if got.Templates[1].Error == "" {
+		t.Errorf("import manifest has no error for the invalid template")
+	}
+	if s := got.Summary(); s.Source != src || s.Valid != 1 || s.Invalid != 1 || !s.Imported.Equal(got.Finished) {
+		t.Errorf("Summary() = %+v", s)
+	}
+}
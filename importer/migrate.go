@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// PreChecksFile returns the precheck file of a pattern file: ../precheck/<id>.json for an SPDX template, or
+// prechecks_<name>.json next to a custom license pattern
+func PreChecksFile(patternFile string) string {
+	dir, base := filepath.Split(patternFile)
+	if strings.HasSuffix(base, ".template.txt") && !(strings.HasPrefix(base, licenses.PrimaryPattern) || strings.HasPrefix(base, licenses.AssociatedPattern)) {
+		return filepath.Join(dir, "..", "precheck", strings.TrimSuffix(base, ".template.txt")+".json")
+	}
+	return filepath.Join(dir, licenses.PreChecksPattern+strings.TrimSuffix(base, filepath.Ext(base))+".json")
+}
+
+// MigratePreChecks regenerates the prechecks of the SPDX templates and the custom patterns in the license
+// library with the current static block extraction (e.g., after GetStaticBlocks changed). The precheck
+// files with different static blocks or an older version are rewritten with the current PreChecksVersion
+// (keeping the Sha256 of the SPDX testdata), and missing precheck files are created for the patterns with
+// static blocks. It returns the precheck files which are (or with dryRun, would be) written.
+func MigratePreChecks(ll *licenses.LicenseLibrary, dryRun bool) ([]string, error) {
+	ids := make([]string, 0, len(ll.LicenseMap))
+	for id := range ll.LicenseMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var written []string
+	for _, id := range ids {
+		lic := ll.LicenseMap[id]
+		for _, pattern := range append(append([]*licenses.PrimaryPatterns{}, lic.PrimaryPatterns...), lic.AssociatedPatterns...) {
+			base := filepath.Base(pattern.FileName)
+			if !strings.HasSuffix(base, ".template.txt") && !strings.HasPrefix(base, licenses.PrimaryPattern) && !strings.HasPrefix(base, licenses.AssociatedPattern) {
+				continue
+			}
+			normalizedPatternData := normalizer.NewNormalizationData(pattern.Text, true)
+			normalizedPatternData.LinePrefixes = ll.LinePrefixes
+			if err := normalizedPatternData.NormalizeText(); err != nil {
+				return written, fmt.Errorf("normalize pattern %v error: %w", pattern.FileName, err)
+			}
+			staticBlocks := GetStaticBlocks(normalizedPatternData)
+
+			f := PreChecksFile(pattern.FileName)
+			var preChecks licenses.LicensePreChecks
+			b, err := os.ReadFile(f)
+			switch {
+			case errors.Is(err, fs.ErrNotExist):
+				if len(staticBlocks) == 0 {
+					continue // a regex-only pattern has nothing to precheck
+				}
+			case err != nil:
+				return written, err
+			default:
+				if err := json.Unmarshal(b, &preChecks); err != nil {
+					return written, fmt.Errorf("error on unmarshal %v: %w", f, err)
+				}
+				if preChecks.GetVersion() == licenses.PreChecksVersion && slices.Equal(preChecks.StaticBlocks, staticBlocks) {
+					continue
+				}
+			}
+
+			preChecks.Version = licenses.PreChecksVersion
+			preChecks.StaticBlocks = staticBlocks
+			if !dryRun {
+				if err := WritePreChecksFile(preChecks, f); err != nil {
+					return written, err
+				}
+			}
+			written = append(written, f)
+		}
+	}
+	return written, nil
+}
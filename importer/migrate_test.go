@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package importer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestPreChecksFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		patternFile string
+		want        string
+	}{
+		{patternFile: "resources/spdx/default/template/MIT.template.txt", want: "resources/spdx/default/precheck/MIT.json"},
+		{patternFile: "resources/spdx/default/template/deprecated_GPL-2.0.template.txt", want: "resources/spdx/default/precheck/deprecated_GPL-2.0.json"},
+		{patternFile: "license_patterns/MIT/license_MIT.txt", want: "license_patterns/MIT/prechecks_license_MIT.json"},
+		{patternFile: "license_patterns/MIT/associated_full-title.txt", want: "license_patterns/MIT/prechecks_associated_full-title.json"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.patternFile, func(t *testing.T) {
+			t.Parallel()
+			if got := PreChecksFile(filepath.FromSlash(tt.patternFile)); got != filepath.FromSlash(tt.want) {
+				t.Errorf("PreChecksFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigratePreChecks(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	pattern := func(name, text string) *licenses.PrimaryPatterns {
+		return &licenses.PrimaryPatterns{Text: text, FileName: filepath.Join(dir, name)}
+	}
+	ll := &licenses.LicenseLibrary{LicenseMap: licenses.LicenseMap{
+		"Test": {
+			PrimaryPatterns:    []*licenses.PrimaryPatterns{pattern("license_Test.txt", "Permission is granted <<match=.+>> to use this software")},
+			AssociatedPatterns: []*licenses.PrimaryPatterns{pattern("associated_title.txt", "<<match=(?i)test license>>")},
+		},
+	}}
+	preChecksFile := filepath.Join(dir, "prechecks_license_Test.json")
+	sha256 := strings.Repeat("ab", 32)
+	stale, _ := json.Marshal(licenses.LicensePreChecks{StaticBlocks: []string{"old blocks"}, Sha256: sha256})
+	if err := os.WriteFile(preChecksFile, stale, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := MigratePreChecks(ll, true)
+	if err != nil {
+		t.Fatalf("MigratePreChecks() error = %v", err)
+	}
+	if d := cmp.Diff([]string{preChecksFile}, written); d != "" {
+		t.Errorf("MigratePreChecks() dry run mismatch (-want +got):\n%s", d)
+	}
+	if b, _ := os.ReadFile(preChecksFile); string(b) != string(stale) {
+		t.Errorf("MigratePreChecks() dry run wrote %v", preChecksFile)
+	}
+
+	if _, err := MigratePreChecks(ll, false); err != nil {
+		t.Fatalf("MigratePreChecks() error = %v", err)
+	}
+	b, err := os.ReadFile(preChecksFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got licenses.LicensePreChecks
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := licenses.LicensePreChecks{Version: licenses.PreChecksVersion, StaticBlocks: []string{"permission is granted", "to use this software"}, Sha256: sha256}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("migrated prechecks mismatch (-want +got):\n%s", d)
+	}
+
+	if written, err := MigratePreChecks(ll, false); err != nil || len(written) > 0 {
+		t.Errorf("MigratePreChecks() after the migration = %v, %v, want nothing written", written, err)
+	}
+}
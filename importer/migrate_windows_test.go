@@ -0,0 +1,29 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit && windows
+
+package importer
+
+import "testing"
+
+func TestPreChecksFileWindows(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		patternFile string
+		want        string
+	}{
+		{patternFile: `C:\license-scanner\resources\spdx\default\template\MIT.template.txt`, want: `C:\license-scanner\resources\spdx\default\precheck\MIT.json`},
+		{patternFile: "D:/resources/spdx/3.21/template/Apache-2.0.template.txt", want: `D:\resources\spdx\3.21\precheck\Apache-2.0.json`},
+		{patternFile: `C:\resources\custom\default\license_patterns\MIT\license_MIT.txt`, want: `C:\resources\custom\default\license_patterns\MIT\prechecks_license_MIT.json`},
+		{patternFile: `\\server\share\resources\spdx\default\template\0BSD.template.txt`, want: `\\server\share\resources\spdx\default\precheck\0BSD.json`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.patternFile, func(t *testing.T) {
+			t.Parallel()
+			if got := PreChecksFile(tt.patternFile); got != tt.want {
+				t.Errorf("PreChecksFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
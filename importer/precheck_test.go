@@ -70,22 +70,8 @@ func TestPreChecks(t *testing.T) {
 						// Join static blocks with separator.
 						normalizedPatternData.NormalizedText = strings.Join(staticBlocks, " <<regex>> ")
 
-						dir := path.Dir(pattern.FileName)
-						base := path.Base(pattern.FileName)
-						var f string
-						// spdx template always has suffix (and never starts with license_ or associated_
-						// custom pattern requires prefix (but could also use the .template.txt suffix e.g. copied from spdx file)
-						if strings.HasSuffix(fName, ".template.txt") && !(strings.HasPrefix(fName, "license_") || strings.HasPrefix(fName, "associated_")) {
-							// ../precheck/<id>.json
-							f = path.Join(dir, "..", "precheck", base) // sibling precheck dir
-							ext := ".template.txt"
-							f = f[0:len(f)-len(ext)] + ".json" // Replace .template.txt with .json
-						} else {
-							// prechecks_license_*.json
-							f = path.Join(dir, "prechecks_"+base) // Add prefix
-							ext := path.Ext(f)
-							f = f[0:len(f)-len(ext)] + ".json" // Replace .txt with .json
-						}
+						// ../precheck/<id>.json for an SPDX template, or prechecks_license_*.json for a custom pattern
+						f := PreChecksFile(pattern.FileName)
 						trimmedStaticBlocks := []string{}
 						for i := range staticBlocks {
 							trimmed := strings.TrimSpace(staticBlocks[i])
@@ -100,7 +86,7 @@ func TestPreChecks(t *testing.T) {
 							} else {
 								if *fix {
 									// Write a new file. This was done to create the files from embedded prechecks.
-									if err := WritePreChecksFile(trimmedStaticBlocks, f); err != nil {
+									if err := WritePreChecksFile(licenses.LicensePreChecks{StaticBlocks: trimmedStaticBlocks}, f); err != nil {
 										t.Errorf("Error writing new file %v: %v", f, err)
 									}
 									t.Skipf("Wrote new file %v", f)
@@ -117,7 +103,7 @@ func TestPreChecks(t *testing.T) {
 									readPreChecks.StaticBlocks = trimmedStaticBlocks
 									if *fix {
 										// Write a new file. This was done to create the files from embedded preChecks.
-										if err := WritePreChecksFile(readPreChecks.StaticBlocks, f); err != nil {
+										if err := WritePreChecksFile(readPreChecks, f); err != nil {
 											t.Errorf("Error writing file %v: %v", f, err)
 										}
 										t.Errorf("Updated file %v", f)
@@ -16,11 +16,7 @@ import (
 
 var replaceRE = regexp.MustCompile(`(<<.*?>>)`)
 
-func WritePreChecksFile(staticBlocks []string, f string) error {
-	preChecks := licenses.LicensePreChecks{
-		StaticBlocks: staticBlocks,
-	}
-
+func WritePreChecksFile(preChecks licenses.LicensePreChecks, f string) error {
 	b, err := json.MarshalIndent(preChecks, "", "  ")
 	if err != nil {
 		return fmt.Errorf("error on MarshalIndent for %v: %w", f, err)
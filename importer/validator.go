@@ -5,7 +5,7 @@ package importer
 import (
 	"fmt"
 	"os"
-	"path"
+	"path/filepath"
 
 	"github.com/mrutkows/sbom-utility/log"
 
@@ -15,41 +15,44 @@ import (
 	"github.com/IBM/license-scanner/normalizer"
 )
 
-func ValidateSPDXTemplateWithLicenseText(id, templateFile, textFile, templateDestDir, preCheckDestDir, textDestDir string) (err error) {
-	var templateBytes []byte
-	var textBytes []byte
-	var staticBlocks []string
+func ValidateSPDXTemplateWithLicenseText(id, templateFile, textFile, templateDestDir, preCheckDestDir, textDestDir string) error {
+	textBytes, err := os.ReadFile(textFile)
+	if err != nil {
+		return err
+	}
+	templateBytes, err := os.ReadFile(templateFile)
+	if err != nil {
+		return err
+	}
+	return validateAndWrite(id, templateBytes, textBytes, templateFile, templateDestDir, preCheckDestDir, textDestDir)
+}
+
+// validateAndWrite writes the template, the license text, and the prechecks when the template matches the text
+func validateAndWrite(id string, templateBytes []byte, textBytes []byte, templateFile, templateDestDir, preCheckDestDir, textDestDir string) (err error) {
+	var preChecks licenses.LicensePreChecks
 
 	// on error, save template/text/precheck files (if available) under testdata/invalid
 	defer func() {
 		if err != nil {
-			invalid := path.Join(textDestDir, "invalid") // on error save files in testdata/invalid
+			invalid := filepath.Join(textDestDir, "invalid") // on error save files in testdata/invalid
 			_ = os.Mkdir(invalid, 0o700)
-			_ = write(id, invalid, templateBytes, invalid, textBytes, invalid, staticBlocks)
+			_ = write(id, invalid, templateBytes, invalid, textBytes, invalid, preChecks)
 		}
 	}()
 
-	textBytes, err = os.ReadFile(textFile)
-	if err != nil {
-		return
-	}
-	templateBytes, err = os.ReadFile(templateFile)
-	if err != nil {
-		return
-	}
-
-	staticBlocks, err = validate(id, templateBytes, textBytes, templateFile)
+	preChecks, err = validate(id, templateBytes, textBytes, templateFile)
 	if err != nil {
 		return err
 	}
 
-	if err = write(id, templateDestDir, templateBytes, textDestDir, textBytes, preCheckDestDir, staticBlocks); err != nil {
+	if err = write(id, templateDestDir, templateBytes, textDestDir, textBytes, preCheckDestDir, preChecks); err != nil {
 		return
 	}
 	return
 }
 
-func validate(id string, templateBytes []byte, textBytes []byte, templateFile string) (staticBlocks []string, err error) {
+// validate returns the prechecks (static blocks and the normalized testdata hash) when the template matches the testdata
+func validate(id string, templateBytes []byte, textBytes []byte, templateFile string) (preChecks licenses.LicensePreChecks, err error) {
 
 	l := &licenses.License{}
 	if err = licenses.AddPrimaryPatternAndSource(string(templateBytes), templateFile, l); err != nil {
@@ -89,26 +92,27 @@ func validate(id string, templateBytes []byte, textBytes []byte, templateFile st
 		return
 	}
 
-	staticBlocks = GetStaticBlocks(normalizedTemplate)
+	staticBlocks := GetStaticBlocks(normalizedTemplate)
 	passed := identifier.PassedStaticBlocksChecks(staticBlocks, normalizedTestData)
 	if !passed {
 		err = Logger.Errorf("%v failed testing against static blocks", id)
 		return
 	}
+	preChecks = licenses.LicensePreChecks{StaticBlocks: staticBlocks, Sha256: normalizedTestData.Hash.Sha256}
 	return
 }
 
-func write(id string, templateDestDir string, templateBytes []byte, textDestDir string, textBytes []byte, preCheckDestDir string, staticBlocks []string) error {
+func write(id string, templateDestDir string, templateBytes []byte, textDestDir string, textBytes []byte, preCheckDestDir string, preChecks licenses.LicensePreChecks) error {
 
-	if err := os.WriteFile(path.Join(templateDestDir, id+".template.txt"), templateBytes, 0o600); err != nil {
+	if err := os.WriteFile(filepath.Join(templateDestDir, id+".template.txt"), templateBytes, 0o600); err != nil {
 		return Logger.Errorf("error writing template for %v: %w", id, err)
 	}
 
-	if err := os.WriteFile(path.Join(textDestDir, id+".txt"), textBytes, 0o600); err != nil {
+	if err := os.WriteFile(filepath.Join(textDestDir, id+".txt"), textBytes, 0o600); err != nil {
 		return Logger.Errorf("error writing testdata for %v: %w", id, err)
 	}
 
-	if err := WritePreChecksFile(staticBlocks, path.Join(preCheckDestDir, id+".json")); err != nil {
+	if err := WritePreChecksFile(preChecks, filepath.Join(preCheckDestDir, id+".json")); err != nil {
 		return Logger.Errorf("error writing precheck file for %v: %w", id, err)
 	}
 	return nil
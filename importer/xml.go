@@ -0,0 +1,357 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package importer
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// xmlLicense is a license or an exception from the license-list-XML source, with the template and the
+// license text generated from its markup (and those of its standard license header, if any)
+type xmlLicense struct {
+	ID           string
+	Name         string
+	IsException  bool
+	IsDeprecated bool
+	IsOSI        bool
+	CrossRefs    []string
+	Template     string
+	Text         string
+	Header       string
+	HeaderText   string
+}
+
+// xmlNode is an element (or the character data when name is "") of the license XML, keeping the order of
+// the mixed content
+type xmlNode struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*xmlNode
+}
+
+var (
+	xmlWhitespaceRE = regexp.MustCompile(`\s+`)
+	blankLinesRE    = regexp.MustCompile(`\n{3,}`)
+)
+
+// AddAllSPDXXML imports the licenses and exceptions from a clone of the SPDX license-list-XML repository
+// (the src directory, with the test texts in test/simpleTestForGenerator). The templates, with the
+// replaceable and optional markup, are generated from the XML instead of using the pre-generated templates of
+// the license-list-data release. A license without a test text is validated with the text generated from its
+// XML. The standard license headers are written as separate templates in the header dir (a header which does
+// not match its own text is skipped). The --spdx flag names the license list version and the destination
+// directory.
+func AddAllSPDXXML(cfg *viper.Viper) error {
+	xmlDir := cfg.GetString(configurer.AddAllXMLFlag)
+	licenseListVersion := cfg.GetString(licenses.SPDX)
+	if licenseListVersion == "" || licenseListVersion == "default" {
+		return fmt.Errorf("use --spdx to name the license list version to import from %v", xmlDir)
+	}
+	manifest := newImportManifest(xmlDir, licenses.ImportLicenseListXML, licenseListVersion)
+
+	srcDir := filepath.Join(xmlDir, "src")
+	testDir := filepath.Join(xmlDir, "test", "simpleTestForGenerator")
+	list, err := readXMLLicenses(srcDir)
+	if err != nil {
+		return err
+	}
+	if len(list) < 1 {
+		return fmt.Errorf("license XML source dir %v has no licenses", srcDir)
+	}
+
+	// destinations
+	rd := cfg.GetString(licenses.Resources)
+
+	templateDestDir := getDestPath(rd, licenseListVersion, "template")
+	preCheckDestDir := getDestPath(rd, licenseListVersion, "precheck")
+	textDestDir := getDestPath(rd, licenseListVersion, "testdata")
+	jsonDestDir := getDestPath(rd, licenseListVersion, "json")
+	headerDestDir := getDestPath(rd, licenseListVersion, "header")
+
+	if err := createEmptyLicenseListDataResourceDirs(templateDestDir, preCheckDestDir, textDestDir, jsonDestDir, headerDestDir); err != nil {
+		return err
+	}
+	if err := writeLicenseListJSON(list, licenseListVersion, jsonDestDir); err != nil {
+		return err
+	}
+
+	errorCount := 0
+	for _, l := range list {
+		id := l.ID
+		if l.IsDeprecated {
+			id = "deprecated_" + id
+		}
+		textBytes, err := os.ReadFile(filepath.Join(testDir, l.ID+".txt"))
+		if errors.Is(err, fs.ErrNotExist) {
+			textBytes, err = []byte(l.Text), nil
+		}
+		if err != nil {
+			return err
+		}
+		templateFile := filepath.Join(templateDestDir, id+".template.txt")
+		err = validateAndWrite(id, []byte(l.Template), textBytes, templateFile, templateDestDir, preCheckDestDir, textDestDir)
+		if err != nil {
+			_ = Logger.Errorf("template ID %v is not valid", id)
+			errorCount++
+		}
+		imported := addImportedTemplate(manifest, id, err)
+		if l.Header != "" {
+			headerFile := filepath.Join(headerDestDir, id+".template.txt")
+			if _, err := validate(id, []byte(l.Header), []byte(l.HeaderText), headerFile); err != nil {
+				Logger.Infof("Skipping the standard license header of %v which is not valid: %v", id, err)
+				imported.Header = licenses.ImportInvalid
+				continue
+			}
+			if err := os.WriteFile(headerFile, []byte(l.Header), 0o600); err != nil {
+				return err
+			}
+			imported.Header = licenses.ImportValid
+		}
+	}
+	if err := writeImportManifest(manifest, rd); err != nil {
+		return err
+	}
+	if errorCount > 0 {
+		return fmt.Errorf("%v templates could not be validated", errorCount)
+	}
+	return nil
+}
+
+// readXMLLicenses reads the license and exception XML files in the source dir (and its exceptions dir)
+func readXMLLicenses(srcDir string) ([]xmlLicense, error) {
+	var ret []xmlLicense
+	err := filepath.WalkDir(srcDir, func(p string, de fs.DirEntry, err error) error {
+		if err != nil || de.IsDir() || !strings.HasSuffix(de.Name(), ".xml") {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		found, err := parseLicenseXML(f)
+		if err != nil {
+			return fmt.Errorf("parse license XML %v error: %w", p, err)
+		}
+		ret = append(ret, found...)
+		return nil
+	})
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, err
+}
+
+// parseLicenseXML returns the licenses and exceptions of an SPDXLicenseCollection
+func parseLicenseXML(r io.Reader) ([]xmlLicense, error) {
+	root, err := parseXMLNodes(r)
+	if err != nil {
+		return nil, err
+	}
+	var ret []xmlLicense
+	for _, n := range root.find("license", "exception") {
+		l := xmlLicense{
+			ID:           n.attrs["licenseId"],
+			Name:         n.attrs["name"],
+			IsException:  n.name == "exception",
+			IsDeprecated: n.attrs["isDeprecated"] == "true",
+			IsOSI:        n.attrs["isOsiApproved"] == "true",
+		}
+		if l.ID == "" {
+			return nil, fmt.Errorf("%v without a licenseId", n.name)
+		}
+		for _, c := range n.find("crossRef") {
+			l.CrossRefs = append(l.CrossRefs, strings.TrimSpace(c.render(false)))
+		}
+		text := n.find("text")
+		if len(text) == 0 {
+			return nil, fmt.Errorf("%v %v has no text", n.name, l.ID)
+		}
+		l.Template = tidy(text[0].render(true))
+		l.Text = tidy(text[0].render(false))
+		if h := n.standardLicenseHeader(); h != nil {
+			l.Header = tidy(h.render(true))
+			l.HeaderText = tidy(h.render(false))
+		}
+		ret = append(ret, l)
+	}
+	return ret, nil
+}
+
+// standardLicenseHeader returns the standard license header of the license element (the one outside of the
+// text, else the first one in the text, e.g., in the How to Apply appendix of the GPL), or nil when it has none
+// or it is empty
+func (n *xmlNode) standardLicenseHeader() *xmlNode {
+	var found *xmlNode
+	for _, h := range n.find("standardLicenseHeader") {
+		if strings.TrimSpace(h.render(false)) == "" {
+			continue
+		}
+		if found == nil || slices.Contains(n.children, h) {
+			found = h
+		}
+	}
+	return found
+}
+
+// parseXMLNodes reads the XML into a tree of nodes
+func parseXMLNodes(r io.Reader) (*xmlNode, error) {
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		parent := stack[len(stack)-1]
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: make(map[string]string, len(t.Attr))}
+			for _, a := range t.Attr {
+				n.attrs[a.Name.Local] = a.Value
+			}
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			parent.children = append(parent.children, &xmlNode{text: string(t)})
+		}
+	}
+}
+
+// find returns the descendant elements with one of the names (not looking inside the found elements)
+func (n *xmlNode) find(names ...string) []*xmlNode {
+	var ret []*xmlNode
+	for _, c := range n.children {
+		if slices.Contains(names, c.name) {
+			ret = append(ret, c)
+		} else if c.name != "" {
+			ret = append(ret, c.find(names...)...)
+		}
+	}
+	return ret
+}
+
+// render returns the content of the element as SPDX template text (with the <<var>> and <<beginOptional>>
+// markup) or as the license text (with the original text of the replaceable parts and the optional parts)
+func (n *xmlNode) render(template bool) string {
+	var b strings.Builder
+	for _, c := range n.children {
+		b.WriteString(c.renderNode(template))
+	}
+	return b.String()
+}
+
+func (n *xmlNode) renderNode(template bool) string {
+	switch n.name {
+	case "":
+		return xmlWhitespaceRE.ReplaceAllString(n.text, " ")
+	case "p":
+		return "\n\n" + strings.TrimSpace(n.render(template)) + "\n\n"
+	case "br":
+		return "\n"
+	case "list", "item":
+		return "\n" + strings.TrimSpace(n.render(template)) + "\n"
+	case "bullet":
+		bullet := strings.TrimSpace(n.render(false))
+		if template {
+			return fmt.Sprintf(`<<var;name="bullet";original="%v";match=".{0,20}">>`, bullet)
+		}
+		return bullet
+	case "alt":
+		if template {
+			return fmt.Sprintf(`<<var;name="%v";original="%v";match="%v">>`, n.attrs["name"], oneLine(n.render(false)), n.attrs["match"])
+		}
+		return n.render(false)
+	case "copyrightText":
+		if template {
+			return fmt.Sprintf(`<<var;name="copyright";original="%v";match=".{0,5000}">>`, oneLine(n.render(false)))
+		}
+		return n.render(false)
+	case "titleText", "optional":
+		if template {
+			return "<<beginOptional>>" + n.render(template) + "<<endOptional>>"
+		}
+		return n.render(template)
+	case "standardLicenseHeader", "notes", "crossRefs":
+		return ""
+	default:
+		return n.render(template)
+	}
+}
+
+// oneLine returns the text on one line (for the original text of a replaceable part)
+func oneLine(s string) string {
+	return strings.TrimSpace(xmlWhitespaceRE.ReplaceAllString(s, " "))
+}
+
+// tidy removes the spaces at the ends of the lines and the extra blank lines of the rendered text
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(blankLinesRE.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")) + "\n"
+}
+
+// writeLicenseListJSON writes the licenses.json and exceptions.json of the imported license list
+func writeLicenseListJSON(list []xmlLicense, licenseListVersion string, jsonDestDir string) error {
+	licensesJSON := struct {
+		LicenseListVersion string                     `json:"licenseListVersion"`
+		Licenses           []licenses.SPDXLicenceInfo `json:"licenses"`
+	}{LicenseListVersion: licenseListVersion, Licenses: []licenses.SPDXLicenceInfo{}}
+	exceptionsJSON := struct {
+		LicenseListVersion string                       `json:"licenseListVersion"`
+		Exceptions         []licenses.SPDXExceptionInfo `json:"exceptions"`
+	}{LicenseListVersion: licenseListVersion, Exceptions: []licenses.SPDXExceptionInfo{}}
+
+	for _, l := range list {
+		if l.IsException {
+			exceptionsJSON.Exceptions = append(exceptionsJSON.Exceptions, licenses.SPDXExceptionInfo{
+				Name:                  l.Name,
+				LicenseExceptionID:    l.ID,
+				IsDeprecatedLicenseID: l.IsDeprecated,
+				SeeAlso:               l.CrossRefs,
+			})
+			continue
+		}
+		licensesJSON.Licenses = append(licensesJSON.Licenses, licenses.SPDXLicenceInfo{
+			Name:                  l.Name,
+			LicenseID:             l.ID,
+			IsOSIApproved:         l.IsOSI,
+			IsDeprecatedLicenseID: l.IsDeprecated,
+			SeeAlso:               l.CrossRefs,
+		})
+	}
+
+	for f, v := range map[string]interface{}{"licenses.json": licensesJSON, "exceptions.json": exceptionsJSON} {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(jsonDestDir, f), b, 0o600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package importer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseLicenseXML(t *testing.T) {
+	t.Parallel()
+	x := `<?xml version="1.0" encoding="UTF-8"?>
+<SPDXLicenseCollection xmlns="http://www.spdx.org/license">
+   <license isOsiApproved="true" licenseId="Test-1.0" name="Test License" isDeprecated="true">
+      <crossRefs>
+         <crossRef>https://example.com/test</crossRef>
+      </crossRefs>
+      <notes>Not part of the text</notes>
+      <text>
+         <titleText><p>Test License</p></titleText>
+         <copyrightText><p>Copyright (c) &lt;year&gt;
+            &lt;owner&gt;</p></copyrightText>
+         <p>Permission is granted by <alt match=".+" name="holder">the
+            copyright holder</alt>:</p>
+         <list>
+            <item><bullet>a.</bullet> to use<optional> and copy</optional>;</item>
+            <item><bullet>b.</bullet> to share.</item>
+         </list>
+      </text>
+      <standardLicenseHeader><copyrightText><p>Copyright (c) &lt;year&gt; &lt;owner&gt;</p></copyrightText>
+         <p>Licensed under the <optional>terms of the </optional>Test License.</p></standardLicenseHeader>
+   </license>
+</SPDXLicenseCollection>`
+	want := []xmlLicense{{
+		ID:           "Test-1.0",
+		Name:         "Test License",
+		IsDeprecated: true,
+		IsOSI:        true,
+		CrossRefs:    []string{"https://example.com/test"},
+		Template: `<<beginOptional>>
+
+Test License
+
+<<endOptional>> <<var;name="copyright";original="Copyright (c) <year> <owner>";match=".{0,5000}">>
+
+Permission is granted by <<var;name="holder";original="the copyright holder";match=".+">>:
+
+<<var;name="bullet";original="a.";match=".{0,20}">> to use<<beginOptional>> and copy<<endOptional>>;
+
+<<var;name="bullet";original="b.";match=".{0,20}">> to share.
+`,
+		Text: `Test License
+
+Copyright (c) <year> <owner>
+
+Permission is granted by the copyright holder:
+
+a. to use and copy;
+
+b. to share.
+`,
+		Header: `<<var;name="copyright";original="Copyright (c) <year> <owner>";match=".{0,5000}">>
+
+Licensed under the <<beginOptional>>terms of the <<endOptional>>Test License.
+`,
+		HeaderText: `Copyright (c) <year> <owner>
+
+Licensed under the terms of the Test License.
+`,
+	}}
+	got, err := parseLicenseXML(strings.NewReader(x))
+	if err != nil {
+		t.Fatalf("parseLicenseXML() error = %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("parseLicenseXML() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,206 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package jsonl writes the scan results as JSON Lines (one JSON object per file), so the results can be
+// processed while a long scan is still running.
+//
+// A line is written as soon as the files before it are matched, so the lines are in the order of the files (not in
+// the order the files complete) and the output is the same in every run. Each line has the version of the scanner,
+// since the lines can be processed one by one, and the last line is the metadata of the scan.
+package jsonl
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/version"
+)
+
+// Record is the result of a scanned file
+type Record struct {
+	// File is relative to the root directory (with forward slashes)
+	File string `json:"file"`
+	// Hash is the SHA-256 of the normalized file text
+	Hash string `json:"hash"`
+	// Status is the license state of the file: licensed, evidence (only hints or license URLs), unlicensed
+	// (only an UNLICENSED or All rights reserved declaration), partial (nothing in the part of the file which was
+	// scanned), or no-license
+	Status string `json:"status"`
+	// Licenses are the detected license IDs (sorted)
+	Licenses []string `json:"licenses"`
+	// Expressions are the detected licenses with the exceptions WITH the licenses they apply to and the mapped
+	// license IDs as their SPDX expressions (sorted, only when an exception applies to a license or an ID is mapped)
+	Expressions []string `json:"expressions,omitempty"`
+	// Matches are the locations of each license in the file text
+	Matches map[string][]Location `json:"matches"`
+	// Headers are the locations of the standard license headers of each license (with --headers), apart from the matches
+	Headers map[string][]Location `json:"headers,omitempty"`
+	// Hints are the low-confidence guesses from telltale phrases, for a file without licenses
+	Hints []Hint `json:"hints,omitempty"`
+	// LicenseURLs are the license reference URLs outside of the matches, with the licenses they imply
+	LicenseURLs []LicenseURL `json:"licenseURLs,omitempty"`
+	// Declarations are the UNLICENSED and All rights reserved declarations, for a file without licenses
+	Declarations []Declaration `json:"declarations,omitempty"`
+	// Language is the detected language of the file text (an ISO 639-1 code, when it can be determined)
+	Language string `json:"language,omitempty"`
+	// TimedOut is true when matching the file stopped at the --fileTimeout or a --templateTimeout
+	TimedOut bool `json:"timedOut,omitempty"`
+	// Canceled is true when matching the file stopped because the scan was interrupted
+	Canceled bool `json:"canceled,omitempty"`
+	// Truncated is true when the long lines of the file (e.g., minified code) were only scanned around their license-like markers
+	Truncated bool `json:"truncated,omitempty"`
+	// HeaderOnly is true when only the header of the file was scanned (the quick --dir scan of a file which is not a license file)
+	HeaderOnly bool `json:"headerOnly,omitempty"`
+	// ExtractedLicense is the license-like text which matched no license, with its LicenseRef
+	ExtractedLicense *ExtractedLicense `json:"extractedLicense,omitempty"`
+	// HardLinkOf is the first path of the same file (relative to the root directory) when the record has its results (--hardLinks once)
+	HardLinkOf string `json:"hardLinkOf,omitempty"`
+	// Version is the build information of the scanner and the license list of the scan (when the Writer has one)
+	Version *version.Info `json:"version,omitempty"`
+}
+
+// Location is a matched region of the file text (character offsets)
+type Location struct {
+	Begins int `json:"begins"`
+	Ends   int `json:"ends"`
+}
+
+// Hint is a license ID guessed from a telltale phrase (not a license match)
+type Hint struct {
+	ID     string `json:"id"`
+	Phrase string `json:"phrase"`
+	Begins int    `json:"begins"`
+	Ends   int    `json:"ends"`
+}
+
+// LicenseURL is a license reference URL with the license IDs it implies (not a license match)
+type LicenseURL struct {
+	URL    string   `json:"url"`
+	IDs    []string `json:"ids"`
+	Begins int      `json:"begins"`
+	Ends   int      `json:"ends"`
+}
+
+// ExtractedLicense is the license-like text of a file which matched no license (like the other licensing information of
+// an SPDX document)
+type ExtractedLicense struct {
+	// ID is the LicenseRef-<hash> of the text (the same for the same normalized text)
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// Declaration is an UNLICENSED or All rights reserved declaration (not a license)
+type Declaration struct {
+	Phrase string `json:"phrase"`
+	Begins int    `json:"begins"`
+	Ends   int    `json:"ends"`
+}
+
+// FromResult converts the result of a file. The file name is relative to the root directory.
+func FromResult(result identifier.IdentifierResults, root string) Record {
+	file := result.File
+	if rel, err := filepath.Rel(root, result.File); err == nil {
+		file = rel
+	}
+	r := Record{
+		File:       filepath.ToSlash(file),
+		Hash:       result.Hash.Sha256,
+		Status:     result.Status(),
+		Licenses:   []string{},
+		Matches:    make(map[string][]Location, len(result.Matches)),
+		Language:   result.Language,
+		TimedOut:   result.TimedOut || len(result.TimedOutTemplates) > 0,
+		Canceled:   result.Canceled,
+		Truncated:  result.Truncated,
+		HeaderOnly: result.HeaderOnly,
+	}
+	for id, matches := range result.Matches {
+		r.Licenses = append(r.Licenses, id)
+		for _, m := range matches {
+			r.Matches[id] = append(r.Matches[id], Location{Begins: m.Begins, Ends: m.Ends})
+		}
+	}
+	sort.Strings(r.Licenses)
+	if result.HardLinkOf != "" {
+		r.HardLinkOf = result.HardLinkOf
+		if rel, err := filepath.Rel(root, result.HardLinkOf); err == nil {
+			r.HardLinkOf = rel
+		}
+		r.HardLinkOf = filepath.ToSlash(r.HardLinkOf)
+	}
+	for id, matches := range result.HeaderMatches {
+		if r.Headers == nil {
+			r.Headers = make(map[string][]Location, len(result.HeaderMatches))
+		}
+		for _, m := range matches {
+			r.Headers[id] = append(r.Headers[id], Location{Begins: m.Begins, Ends: m.Ends})
+		}
+	}
+	if len(result.Exceptions) > 0 || len(result.Mappings) > 0 {
+		r.Expressions = result.Expressions()
+	}
+	for _, h := range result.Hints {
+		r.Hints = append(r.Hints, Hint{ID: h.ID, Phrase: h.Phrase, Begins: h.Begins, Ends: h.Ends})
+	}
+	for _, u := range result.LicenseURLs {
+		r.LicenseURLs = append(r.LicenseURLs, LicenseURL{URL: u.URL, IDs: u.IDs, Begins: u.Begins, Ends: u.Ends})
+	}
+	for _, d := range result.Declarations {
+		r.Declarations = append(r.Declarations, Declaration{Phrase: d.Phrase, Begins: d.Begins, Ends: d.Ends})
+	}
+	if e := result.ExtractedLicense; e != nil {
+		r.ExtractedLicense = &ExtractedLicense{ID: e.ID, Text: e.Text}
+	}
+	return r
+}
+
+// Writer writes a line for each result. It is safe to use from the scan workers.
+type Writer struct {
+	// Version is added to each line when it is set (before the first Write)
+	Version *version.Info
+	root    string
+	mu      sync.Mutex
+	enc     *json.Encoder
+	err     error
+}
+
+// NewWriter returns a Writer with the file names relative to the root directory
+func NewWriter(w io.Writer, root string) *Writer {
+	return &Writer{root: root, enc: json.NewEncoder(w)}
+}
+
+// Write writes the line of the result. After an error, nothing more is written and Err returns the error.
+func (w *Writer) Write(result identifier.IdentifierResults) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		r := FromResult(result, w.root)
+		r.Version = w.Version
+		w.err = w.enc.Encode(r)
+	}
+}
+
+// metadataLine is the last line, with the metadata of the scan
+type metadataLine struct {
+	Metadata metadata.Metadata `json:"metadata"`
+}
+
+// WriteMetadata writes the metadata of the scan as the last line (which has no file), after the lines of the files
+func (w *Writer) WriteMetadata(m metadata.Metadata) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.err == nil {
+		w.err = w.enc.Encode(metadataLine{Metadata: m})
+	}
+}
+
+// Err returns the first write error
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
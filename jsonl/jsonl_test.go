@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package jsonl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/version"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{
+			File:    "/repo/LICENSE",
+			Hash:    normalizer.Digest{Sha256: "aaa"},
+			Matches: map[string][]identifier.Match{"MIT": {{Begins: 0, Ends: 1077}}},
+		},
+		{
+			File:     "/repo/src/main.go",
+			Hash:     normalizer.Digest{Sha256: "bbb"},
+			Matches:  map[string][]identifier.Match{"Apache-2.0": {{Begins: 3, Ends: 90}}, "0BSD": {{Begins: 100, Ends: 200}, {Begins: 300, Ends: 400}}},
+			TimedOut: true,
+		},
+		{File: "/repo/README.md", Hash: normalizer.Digest{Sha256: "ccc"}, Truncated: true},
+		{
+			File:         "/repo/NOTICE",
+			Hash:         normalizer.Digest{Sha256: "ddd"},
+			Declarations: []identifier.Declaration{{Phrase: "All rights reserved", Begins: 20, Ends: 38}},
+		},
+	}
+	want := `{"file":"LICENSE","hash":"aaa","status":"licensed","licenses":["MIT"],"matches":{"MIT":[{"begins":0,"ends":1077}]}}
+{"file":"src/main.go","hash":"bbb","status":"licensed","licenses":["0BSD","Apache-2.0"],"matches":{"0BSD":[{"begins":100,"ends":200},{"begins":300,"ends":400}],"Apache-2.0":[{"begins":3,"ends":90}]},"timedOut":true}
+{"file":"README.md","hash":"ccc","status":"partial","licenses":[],"matches":{},"truncated":true}
+{"file":"NOTICE","hash":"ddd","status":"unlicensed","licenses":[],"matches":{},"declarations":[{"phrase":"All rights reserved","begins":20,"ends":38}]}
+`
+	var out bytes.Buffer
+	w := NewWriter(&out, "/repo")
+	for _, r := range results {
+		w.Write(r)
+	}
+	if err := w.Err(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if d := cmp.Diff(want, out.String()); d != "" {
+		t.Errorf("Write() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestWriterVersion(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	w := NewWriter(&out, "/repo")
+	w.Version = &version.Info{Version: "1.2.3", Commit: "abc", GoVersion: "go1.21.0", LicenseLists: []version.LicenseList{{SPDX: "default", Version: "3.21"}}}
+	w.Write(identifier.IdentifierResults{File: "/repo/LICENSE", Hash: normalizer.Digest{Sha256: "aaa"}})
+	if err := w.Err(); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `{"file":"LICENSE","hash":"aaa","status":"no-license","licenses":[],"matches":{},"version":{"version":"1.2.3","commit":"abc","goVersion":"go1.21.0","licenseLists":[{"spdx":"default","version":"3.21"}]}}
+`
+	if d := cmp.Diff(want, out.String()); d != "" {
+		t.Errorf("Write() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestWriterMetadata(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	w := NewWriter(&out, "/repo")
+	w.Write(identifier.IdentifierResults{File: "/repo/LICENSE", Hash: normalizer.Digest{Sha256: "aaa"}})
+	w.WriteMetadata(metadata.Metadata{
+		Version:    version.Info{Version: "1.2.3"},
+		ConfigHash: "ccc",
+		Resources:  []metadata.Checksum{{Name: "spdx/default", SHA256: "bbb"}},
+	})
+	if err := w.Err(); err != nil {
+		t.Fatalf("WriteMetadata() error = %v", err)
+	}
+	want := `{"file":"LICENSE","hash":"aaa","status":"no-license","licenses":[],"matches":{}}
+{"metadata":{"version":{"version":"1.2.3","goVersion":""},"configHash":"ccc","resources":[{"name":"spdx/default","sha256":"bbb"}]}}
+`
+	if d := cmp.Diff(want, out.String()); d != "" {
+		t.Errorf("WriteMetadata() mismatch (-want +got):\n%s", d)
+	}
+}
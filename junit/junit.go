@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package junit writes the scan results as JUnit XML test results (a test case per file, which fails on the
+// policy violations), so CI servers such as Jenkins and GitLab CI show the license failures in their test reports.
+//
+// The test cases are named by the paths of the files relative to the scanned directory. A failure has the high risk
+// licenses and their lines, and the system-out has the detected and the medium risk licenses, which do not fail the
+// test. The properties of the test suite are the metadata of the scan (see SetMetadata).
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/report"
+)
+
+// SuiteName is the name of the test suite of a scan
+const SuiteName = "license-scanner"
+
+// FailureType is the type of the failure of a file with a high risk license
+const FailureType = "high-risk-license"
+
+// TestSuites is the JUnit XML document
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite is the test suite of a scan
+type TestSuite struct {
+	Name     string `xml:"name,attr"`
+	Tests    int    `xml:"tests,attr"`
+	Failures int    `xml:"failures,attr"`
+	// Properties are the metadata of the scan (when it is set)
+	Properties *Properties `xml:"properties,omitempty"`
+	TestCases  []TestCase  `xml:"testcase"`
+}
+
+// Properties are the properties of a test suite
+type Properties struct {
+	Properties []Property `xml:"property"`
+}
+
+// Property is a name and value of a test suite
+type Property struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// TestCase is the test of a scanned file
+type TestCase struct {
+	// Name is the file path, relative to the root (with forward slashes)
+	Name      string   `xml:"name,attr"`
+	ClassName string   `xml:"classname,attr"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	// SystemOut has the detected licenses, and the medium risk licenses (which do not fail the test)
+	SystemOut string `xml:"system-out,omitempty"`
+}
+
+// Failure is why the test of a file failed: its high risk licenses
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// FromResults returns a test case for each file, sorted by path. The test of a file with a license of a high
+// risk level in the context fails, unless the license is only a choice of an allowed expression (see
+// report.RiskModel.Avoided). The medium risk licenses and the choices are in the output of the test. The file
+// paths are relative to the root.
+func FromResults(results []identifier.IdentifierResults, root string, model *report.RiskModel, context report.RiskContext) *TestSuites {
+	suite := TestSuite{Name: SuiteName, TestCases: []TestCase{}}
+	for _, result := range results {
+		tc := TestCase{Name: relativePath(result.File, root), ClassName: SuiteName}
+		ids := make([]string, 0, len(result.Matches))
+		for id := range result.Matches {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		var out, high, failures []string
+		if len(ids) > 0 {
+			out = append(out, "Licenses: "+strings.Join(ids, ", "))
+		}
+		avoided := model.Avoided(result, context)
+		for _, id := range ids {
+			category := result.Classifications[id].Category
+			risk := model.Level(category, result.Obligations[id], context)
+			if category == "" {
+				category = "uncategorized"
+			}
+			var lines []string
+			for _, m := range result.Matches[id] {
+				loc := identifier.Locate(result.OriginalText, m)
+				lines = append(lines, fmt.Sprintf("%v-%v", loc.StartLine, loc.EndLine))
+			}
+			finding := fmt.Sprintf("%v is a %v risk license (%v), lines %v", id, risk, category, strings.Join(lines, ", "))
+			switch {
+			case avoided[id]:
+				out = append(out, fmt.Sprintf("%v, a choice of the allowed %v", finding, result.Expression(id)))
+			case risk == report.RiskHigh:
+				high = append(high, id)
+				failures = append(failures, finding)
+			case risk == report.RiskMedium:
+				out = append(out, finding)
+			}
+		}
+		if len(high) > 0 {
+			tc.Failure = &Failure{
+				Message: fmt.Sprintf("high risk licenses with %v linking and %v distribution: %v", context.Linking, context.Distribution, strings.Join(high, ", ")),
+				Type:    FailureType,
+				Text:    strings.Join(failures, "\n"),
+			}
+			suite.Failures++
+		}
+		tc.SystemOut = strings.Join(out, "\n")
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+	sort.Slice(suite.TestCases, func(i, j int) bool { return suite.TestCases[i].Name < suite.TestCases[j].Name })
+	suite.Tests = len(suite.TestCases)
+	return &TestSuites{Tests: suite.Tests, Failures: suite.Failures, Suites: []TestSuite{suite}}
+}
+
+// SetMetadata sets the properties of the test suites to the metadata of the scan: the build information and
+// the license lists (with their imports), the config hash, the resource checksums, the times and host (when
+// they are set), and whether the scan is incomplete
+func (s *TestSuites) SetMetadata(m metadata.Metadata) {
+	info := m.Version
+	properties := []Property{{Name: "license-scanner.version", Value: info.Version}}
+	if info.Commit != "" {
+		properties = append(properties, Property{Name: "license-scanner.commit", Value: info.Commit})
+	}
+	properties = append(properties, Property{Name: "go.version", Value: info.GoVersion})
+	for _, l := range info.LicenseLists {
+		properties = append(properties, Property{Name: "spdx." + l.SPDX + ".licenseListVersion", Value: l.Version})
+		if l.Import != nil {
+			properties = append(properties,
+				Property{Name: "spdx." + l.SPDX + ".import.source", Value: l.Import.Source},
+				Property{Name: "spdx." + l.SPDX + ".import.time", Value: l.Import.Imported.Format(time.RFC3339Nano)},
+				Property{Name: "spdx." + l.SPDX + ".import.valid", Value: strconv.Itoa(l.Import.Valid)},
+				Property{Name: "spdx." + l.SPDX + ".import.invalid", Value: strconv.Itoa(l.Import.Invalid)})
+		}
+	}
+	properties = append(properties, Property{Name: "config.sha256", Value: m.ConfigHash})
+	for _, c := range m.Resources {
+		properties = append(properties, Property{Name: "resources." + c.Name + ".sha256", Value: c.SHA256})
+	}
+	if m.Start != nil && m.End != nil {
+		properties = append(properties,
+			Property{Name: "scan.start", Value: m.Start.Format(time.RFC3339Nano)},
+			Property{Name: "scan.end", Value: m.End.Format(time.RFC3339Nano)})
+	}
+	if m.Host != nil {
+		properties = append(properties,
+			Property{Name: "host.name", Value: m.Host.Hostname},
+			Property{Name: "host.os", Value: m.Host.OS},
+			Property{Name: "host.arch", Value: m.Host.Arch},
+			Property{Name: "host.cpus", Value: strconv.Itoa(m.Host.CPUs)})
+	}
+	if m.Incomplete {
+		properties = append(properties, Property{Name: "scan.incomplete", Value: "true"})
+	}
+	for i := range s.Suites {
+		s.Suites[i].Properties = &Properties{Properties: properties}
+	}
+}
+
+// Write writes the JUnit XML document
+func (s *TestSuites) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(s); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// relativePath returns the file path relative to the root (with forward slashes)
+func relativePath(file string, root string) string {
+	if rel, err := filepath.Rel(root, file); err == nil {
+		file = rel
+	}
+	return filepath.ToSlash(file)
+}
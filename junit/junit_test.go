@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package junit
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/report"
+	"github.com/IBM/license-scanner/version"
+)
+
+func TestFromResults(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{
+			File:            "/repo/src/main.go",
+			OriginalText:    "// header\n// GPL notice\n// more\ncode\n// LGPL\n",
+			Matches:         map[string][]identifier.Match{"GPL-3.0-only": {{Begins: 10, Ends: 30}}, "LGPL-2.1-only": {{Begins: 39, Ends: 46}}},
+			Classifications: map[string]licenses.Classification{"GPL-3.0-only": {Category: licenses.StrongCopyleft}, "LGPL-2.1-only": {Category: licenses.WeakCopyleft}},
+		},
+		{
+			File:            "/repo/LICENSE",
+			OriginalText:    "MIT License\n",
+			Matches:         map[string][]identifier.Match{"MIT": {{Begins: 0, Ends: 10}}},
+			Classifications: map[string]licenses.Classification{"MIT": {Category: licenses.Permissive}},
+		},
+		{File: "/repo/README.md"},
+	}
+	tests := []struct {
+		name    string
+		context report.RiskContext
+		want    string
+	}{
+		{
+			name:    "distributed",
+			context: report.DefaultRiskContext,
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="1">
+  <testsuite name="license-scanner" tests="3" failures="1">
+    <testcase name="LICENSE" classname="license-scanner">
+      <system-out>Licenses: MIT</system-out>
+    </testcase>
+    <testcase name="README.md" classname="license-scanner"></testcase>
+    <testcase name="src/main.go" classname="license-scanner">
+      <failure message="high risk licenses with dynamic linking and distributed distribution: GPL-3.0-only" type="high-risk-license">GPL-3.0-only is a high risk license (strong-copyleft), lines 2-3</failure>
+      <system-out>Licenses: GPL-3.0-only, LGPL-2.1-only&#xA;LGPL-2.1-only is a medium risk license (weak-copyleft), lines 5-5</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+		{
+			name:    "internal",
+			context: report.RiskContext{Linking: report.DynamicLinking, Distribution: report.InternalDistribution},
+			want: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="3" failures="0">
+  <testsuite name="license-scanner" tests="3" failures="0">
+    <testcase name="LICENSE" classname="license-scanner">
+      <system-out>Licenses: MIT</system-out>
+    </testcase>
+    <testcase name="README.md" classname="license-scanner"></testcase>
+    <testcase name="src/main.go" classname="license-scanner">
+      <system-out>Licenses: GPL-3.0-only, LGPL-2.1-only</system-out>
+    </testcase>
+  </testsuite>
+</testsuites>
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			if err := FromResults(results, "/repo", &report.DefaultRiskModel, tt.context).Write(&b); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, b.String()); d != "" {
+				t.Errorf("FromResults() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestTestSuites_SetMetadata(t *testing.T) {
+	t.Parallel()
+	suites := FromResults(nil, "/repo", &report.DefaultRiskModel, report.DefaultRiskContext)
+	start, end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), time.Date(2024, 5, 1, 12, 0, 1, 500000000, time.UTC)
+	suites.SetMetadata(metadata.Metadata{
+		Version: version.Info{Version: "1.2.3", GoVersion: "go1.21.0", LicenseLists: []version.LicenseList{{
+			SPDX:    "default",
+			Version: "3.21",
+			Import:  &version.Import{Source: "/src/license-list-data", Imported: time.Date(2024, 4, 30, 8, 0, 0, 0, time.UTC), Valid: 600, Invalid: 2},
+		}}},
+		ConfigHash: "ccc",
+		Resources:  []metadata.Checksum{{Name: "spdx/default", SHA256: "aaa"}, {Name: "custom/default", SHA256: "bbb"}},
+		Start:      &start,
+		End:        &end,
+		Host:       &metadata.Host{Hostname: "ci", OS: "linux", Arch: "amd64", CPUs: 4},
+	})
+	var b bytes.Buffer
+	if err := suites.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites tests="0" failures="0">
+  <testsuite name="license-scanner" tests="0" failures="0">
+    <properties>
+      <property name="license-scanner.version" value="1.2.3"></property>
+      <property name="go.version" value="go1.21.0"></property>
+      <property name="spdx.default.licenseListVersion" value="3.21"></property>
+      <property name="spdx.default.import.source" value="/src/license-list-data"></property>
+      <property name="spdx.default.import.time" value="2024-04-30T08:00:00Z"></property>
+      <property name="spdx.default.import.valid" value="600"></property>
+      <property name="spdx.default.import.invalid" value="2"></property>
+      <property name="config.sha256" value="ccc"></property>
+      <property name="resources.spdx/default.sha256" value="aaa"></property>
+      <property name="resources.custom/default.sha256" value="bbb"></property>
+      <property name="scan.start" value="2024-05-01T12:00:00Z"></property>
+      <property name="scan.end" value="2024-05-01T12:00:01.5Z"></property>
+      <property name="host.name" value="ci"></property>
+      <property name="host.os" value="linux"></property>
+      <property name="host.arch" value="amd64"></property>
+      <property name="host.cpus" value="4"></property>
+    </properties>
+  </testsuite>
+</testsuites>
+`
+	if d := cmp.Diff(want, b.String()); d != "" {
+		t.Errorf("SetMetadata() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package language detects the natural language of a text from its most common words, so that translated
+// license texts are matched with the patterns of their language.
+package language
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// The detected languages (ISO 639-1 codes)
+const (
+	English    = "en"
+	French     = "fr"
+	German     = "de"
+	Spanish    = "es"
+	Italian    = "it"
+	Portuguese = "pt"
+	Dutch      = "nl"
+)
+
+const (
+	// minWords is the least number of common words of a language for a detection
+	minWords = 2
+	// minPresentWords is the least number of common words of the most common language to tell which
+	// languages are present in a text
+	minPresentWords = 5
+)
+
+// commonWords are frequent words (articles, prepositions, pronouns, etc.) which are specific to each language
+var commonWords = map[string][]string{
+	English:    {"the", "and", "of", "to", "in", "is", "are", "it", "this", "that", "with", "for", "under", "any", "you", "or", "by", "not", "shall", "which", "be"},
+	French:     {"le", "la", "les", "l", "de", "d", "des", "du", "au", "aux", "à", "et", "est", "un", "une", "ce", "cette", "dans", "pour", "par", "sur", "selon", "que", "qui", "vous", "sont", "ou"},
+	German:     {"der", "die", "das", "den", "dem", "und", "ist", "dieses", "dieser", "diese", "ein", "eine", "einer", "unter", "nicht", "mit", "für", "auf", "im", "sie", "oder", "von", "zu", "werk"},
+	Spanish:    {"el", "la", "los", "las", "de", "del", "al", "y", "es", "un", "una", "esta", "este", "bajo", "en", "para", "con", "por", "que", "se", "obra", "usted"},
+	Italian:    {"il", "lo", "la", "gli", "le", "l", "di", "del", "della", "delle", "dell", "e", "è", "un", "una", "questa", "questo", "sotto", "per", "con", "che", "opera", "sono"},
+	Portuguese: {"o", "os", "a", "as", "de", "da", "do", "das", "dos", "e", "é", "em", "um", "uma", "este", "esta", "sob", "para", "com", "que", "não", "trabalho"},
+	Dutch:      {"de", "het", "een", "en", "van", "is", "dit", "deze", "onder", "op", "te", "voor", "met", "niet", "zijn", "valt", "werk", "u"},
+}
+
+// wordLanguages has the languages of each common word
+var wordLanguages = func() map[string][]string {
+	ret := make(map[string][]string)
+	for lang, words := range commonWords {
+		for _, w := range words {
+			ret[w] = append(ret[w], lang)
+		}
+	}
+	return ret
+}()
+
+// Detect returns the language of the text, or "" when it cannot be determined (too few common words, or
+// a tie between languages)
+func Detect(text string) string {
+	best, bestCount, tie := "", 0, false
+	for lang, n := range counts(text) {
+		switch {
+		case n > bestCount:
+			best, bestCount, tie = lang, n, false
+		case n == bestCount:
+			tie = true
+		}
+	}
+	if bestCount < minWords || tie {
+		return ""
+	}
+	return best
+}
+
+// Present returns the languages with enough common words in the text (e.g., both languages of a
+// bilingual license), sorted. It is empty for a text which is too short to tell.
+func Present(text string) []string {
+	c := counts(text)
+	most := 0
+	for _, n := range c {
+		if n > most {
+			most = n
+		}
+	}
+	if most < minPresentWords {
+		return nil
+	}
+	var ret []string
+	for lang, n := range c {
+		if n >= minWords {
+			ret = append(ret, lang)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// counts returns the number of common words of each language in the text
+func counts(text string) map[string]int {
+	ret := make(map[string]int)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for _, lang := range wordLanguages[word] {
+			ret[lang]++
+		}
+	}
+	return ret
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package language
+
+import (
+	"testing"
+
+	"golang.org/x/exp/slices"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		text        string
+		want        string
+		wantPresent []string
+	}{
+		{
+			name: "english",
+			text: "Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the \"Software\"), to deal in the Software without restriction.",
+			want: English, wantPresent: []string{English},
+		},
+		{
+			name: "french",
+			text: "Licence Publique de l'Union européenne v. 1.2\nLa présente licence publique de l'Union européenne s'applique à toute œuvre.",
+			want: French, wantPresent: []string{French},
+		},
+		{
+			name: "german",
+			text: "Dieses Werk ist lizenziert unter einer Creative Commons Namensnennung 4.0 International Lizenz.",
+			want: German, wantPresent: []string{German},
+		},
+		{
+			name: "spanish",
+			text: "Esta obra está bajo una Licencia Creative Commons Atribución 4.0 Internacional.",
+			want: Spanish,
+		},
+		{
+			name: "bilingual",
+			text: "Ce logiciel est régi par la licence CeCILL soumise au droit français et respectant les principes de diffusion des logiciels libres. " +
+				"This software is governed by the CeCILL license under French law and abiding by the rules of distribution of free software.",
+			want: English, wantPresent: []string{English, French},
+		},
+		{
+			name: "too short to tell the languages present",
+			text: "Licence Publique de l'Union européenne v. 1.2",
+			want: French,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := Detect(tt.text); got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+			present := Present(tt.text)
+			if tt.wantPresent == nil && present != nil {
+				t.Errorf("Present() = %v, want none", present)
+			}
+			for _, lang := range tt.wantPresent {
+				if !slices.Contains(present, lang) {
+					t.Errorf("Present() = %v, want it to contain %v", present, lang)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package licensee converts the scan results to the JSON output of GitHub's licensee
+// (licensee detect --json), so that tooling built around licensee can use license-scanner instead.
+package licensee
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/metadata"
+)
+
+const (
+	// OtherKey is the licensee key of a file with an unknown license, or with more than one license
+	OtherKey = "other"
+	// NoAssertion is the matched license of a file with an unknown license, or with more than one license
+	NoAssertion = "NOASSERTION"
+
+	// The licensee matcher names
+	MatcherExact = "exact"
+	MatcherDice  = "dice"
+
+	spdxURL = "https://spdx.org/licenses/"
+)
+
+// Output is the licensee detect --json output
+type Output struct {
+	Licenses     []License     `json:"licenses"`
+	MatchedFiles []MatchedFile `json:"matched_files"`
+	// Metadata is the metadata of the scan: the scanner and license list versions, the config hash, the resource
+	// checksums, the times, and the host (not in licensee's output)
+	Metadata *metadata.Metadata `json:"metadata,omitempty"`
+}
+
+// License is a license detected in the project
+type License struct {
+	Key    string `json:"key"`
+	SPDXID string `json:"spdx_id"`
+	Meta   Meta   `json:"meta"`
+	URL    string `json:"url"`
+	Other  bool   `json:"other"`
+	GPL    bool   `json:"gpl"`
+	LGPL   bool   `json:"lgpl"`
+	CC     bool   `json:"cc"`
+}
+
+// Meta has the license metadata which licensee reads from choosealicense.com (only the title here)
+type Meta struct {
+	Title string `json:"title"`
+}
+
+// MatchedFile is a file in which a license was detected
+type MatchedFile struct {
+	Filename          string   `json:"filename"`
+	Content           string   `json:"content"`
+	ContentHash       string   `json:"content_hash"`
+	ContentNormalized string   `json:"content_normalized"`
+	Matcher           *Matcher `json:"matcher"`
+	MatchedLicense    string   `json:"matched_license"`
+	Attribution       *string  `json:"attribution"`
+}
+
+// Matcher is how the license of a file was detected, with the confidence (0 to 100)
+type Matcher struct {
+	Name       string `json:"name"`
+	Confidence int    `json:"confidence"`
+}
+
+// FromResults converts the results of the files with license matches. The file names are relative to
+// the root directory.
+//
+// A file with one license has that license with the exact matcher (100% confidence), or with the dice
+// matcher and the similarity as the confidence when the ensemble only matched it by fuzzy similarity.
+// A file with more than one license is matched as "NOASSERTION" (licensee's "other" license).
+func FromResults(results []identifier.IdentifierResults, root string, licenseLibrary *licenses.LicenseLibrary) *Output {
+	ret := &Output{Licenses: []License{}, MatchedFiles: []MatchedFile{}}
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if len(result.Matches) == 0 {
+			continue
+		}
+		ids := make([]string, 0, len(result.Matches))
+		for id := range result.Matches {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		f := MatchedFile{
+			Filename:          filename(result.File, root),
+			Content:           result.OriginalText,
+			ContentHash:       contentHash(result.NormalizedText),
+			ContentNormalized: result.NormalizedText,
+			Matcher:           &Matcher{Name: MatcherExact, Confidence: 100},
+			MatchedLicense:    NoAssertion,
+		}
+		if len(ids) == 1 {
+			f.MatchedLicense = ids[0]
+			if v, ok := result.Verdicts[ids[0]]; ok && len(v.MatchedBy) == 1 && v.MatchedBy[0] == identifier.AlgorithmFuzzy {
+				f.Matcher = &Matcher{Name: MatcherDice, Confidence: int(v.Similarity * 100)}
+			}
+		}
+		if len(result.CopyRightStatements) > 0 {
+			attribution := strings.TrimSpace(result.CopyRightStatements[0].Text)
+			f.Attribution = &attribution
+		}
+		ret.MatchedFiles = append(ret.MatchedFiles, f)
+
+		if len(ids) > 1 {
+			ids = []string{NoAssertion}
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				ret.Licenses = append(ret.Licenses, license(id, licenseLibrary))
+			}
+		}
+	}
+	sort.Slice(ret.Licenses, func(i, j int) bool { return ret.Licenses[i].Key < ret.Licenses[j].Key })
+	return ret
+}
+
+// Write writes the output as indented JSON
+func (o *Output) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(o)
+}
+
+// license returns the licensee license of an SPDX ID (licensee uses the lowercase SPDX ID as the key), with the
+// SPDX expression of the license map of the library as the spdx_id of a mapped ID
+func license(id string, licenseLibrary *licenses.LicenseLibrary) License {
+	if id == NoAssertion {
+		return License{Key: OtherKey, SPDXID: NoAssertion, Meta: Meta{Title: "Other"}, Other: true}
+	}
+	key := strings.ToLower(id)
+	title := licenseLibrary.LicenseMap[id].LicenseInfo.Name
+	if title == "" {
+		title = id
+	}
+	spdxID := id
+	if mapped, ok := licenseLibrary.MappedExpression(id); ok {
+		spdxID = mapped
+	}
+	return License{
+		Key:    key,
+		SPDXID: spdxID,
+		Meta:   Meta{Title: title},
+		URL:    spdxURL + id + ".html",
+		GPL:    strings.HasPrefix(key, "gpl-"),
+		LGPL:   strings.HasPrefix(key, "lgpl-"),
+		CC:     strings.HasPrefix(key, "cc-") || strings.HasPrefix(key, "cc0-"),
+	}
+}
+
+// filename returns the path relative to the root (or the path, when it is not under the root)
+func filename(path string, root string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// contentHash is the SHA-1 of the normalized content, like licensee's content_hash
+func contentHash(normalized string) string {
+	h := sha1.Sum([]byte(normalized)) //nolint:gosec
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licensee
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestFromResults(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	match := []identifier.Match{{Begins: 0, Ends: 10}}
+	results := []identifier.IdentifierResults{
+		{
+			File: "/proj/LICENSE", OriginalText: "MIT License", NormalizedText: "mit license",
+			Matches:             map[string][]identifier.Match{"MIT": match},
+			CopyRightStatements: []identifier.PatternMatch{{Text: "Copyright (c) 2023 Acme "}},
+		},
+		{
+			File: "/proj/COPYING", NormalizedText: "gnu",
+			Matches:  map[string][]identifier.Match{"GPL-3.0-only": match},
+			Verdicts: map[string]identifier.Verdict{"GPL-3.0-only": {MatchedBy: []string{identifier.AlgorithmFuzzy}, Similarity: 0.874}},
+		},
+		{
+			File:    "/proj/NOTICE",
+			Matches: map[string][]identifier.Match{"MIT": match, "Apache-2.0": match},
+		},
+		{File: "/proj/README"},
+	}
+	attribution := "Copyright (c) 2023 Acme"
+	want := &Output{
+		Licenses: []License{
+			{
+				Key: "gpl-3.0-only", SPDXID: "GPL-3.0-only", Meta: Meta{Title: "GNU General Public License v3.0 only"},
+				URL: "https://spdx.org/licenses/GPL-3.0-only.html", GPL: true,
+			},
+			{Key: "mit", SPDXID: "MIT", Meta: Meta{Title: "MIT License"}, URL: "https://spdx.org/licenses/MIT.html"},
+			{Key: "other", SPDXID: "NOASSERTION", Meta: Meta{Title: "Other"}, Other: true},
+		},
+		MatchedFiles: []MatchedFile{
+			{
+				Filename: "LICENSE", Content: "MIT License", ContentNormalized: "mit license",
+				ContentHash: contentHash("mit license"),
+				Matcher:     &Matcher{Name: MatcherExact, Confidence: 100}, MatchedLicense: "MIT", Attribution: &attribution,
+			},
+			{
+				Filename: "COPYING", ContentNormalized: "gnu", ContentHash: contentHash("gnu"),
+				Matcher: &Matcher{Name: MatcherDice, Confidence: 87}, MatchedLicense: "GPL-3.0-only",
+			},
+			{
+				Filename: "NOTICE", ContentHash: contentHash(""),
+				Matcher: &Matcher{Name: MatcherExact, Confidence: 100}, MatchedLicense: NoAssertion,
+			},
+		},
+	}
+	got := FromResults(results, "/proj", ll)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("FromResults() mismatch (-want +got):\n%s", d)
+	}
+
+	var buf bytes.Buffer
+	if err := got.Write(&buf); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Write() invalid JSON: %v", err)
+	}
+	for _, key := range []string{"licenses", "matched_files"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("Write() missing %q", key)
+		}
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	t.Parallel()
+	// SHA-1 of the empty string
+	if got, want := contentHash(""), "da39a3ee5e6b4b0d3255bfef95601890afd80709"; got != want {
+		t.Errorf("contentHash() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"hash/fnv"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+)
+
+const (
+	// ShingleSize is the number of words in each shingle (n-gram) of the candidate index
+	ShingleSize = 3
+	// SketchSize is the number of shingle hashes kept in the MinHash (bottom-k) sketch of each pattern
+	SketchSize = 16
+)
+
+// CandidateIndex is a MinHash index of the word shingles of the precheck static blocks of the primary
+// patterns. It selects the few patterns which may match an input before the static block checks and the
+// regex matching, instead of scanning all the patterns.
+//
+// The static blocks must be in the normalized input for a pattern to match, so every shingle of the
+// whole words of a static block is also a shingle of a matching input. A pattern is a candidate when all
+// the shingles in its sketch are in the input, so no pattern which could match is left out.
+type CandidateIndex struct {
+	// sketches has the shingle hashes in the sketch of each indexed pattern (by pattern file name)
+	sketches map[string][]uint64
+	// postings has the indexed pattern file names by shingle hash
+	postings map[uint64][]string
+}
+
+// Candidates are the primary patterns selected by the CandidateIndex for an input.
+// The zero value (no index) has all the patterns.
+type Candidates struct {
+	index *CandidateIndex
+	found map[string]bool
+	// languages are the languages of the input (see ForLanguages)
+	languages []string
+}
+
+// NewCandidateIndex builds the index from the static blocks of the primary pattern prechecks.
+// Patterns without a static block of more than ShingleSize words are not indexed (always candidates).
+func NewCandidateIndex(preChecks PrimaryPatternPreCheckMap) *CandidateIndex {
+	ci := &CandidateIndex{
+		sketches: make(map[string][]uint64),
+		postings: make(map[uint64][]string),
+	}
+	for key, pc := range preChecks {
+		if pc == nil {
+			continue
+		}
+		hashes := make(map[uint64]bool)
+		for _, block := range pc.StaticBlocks {
+			words := strings.Fields(block)
+			// The first and last words may be partial words (the blocks are substrings of the normalized text)
+			if len(words) < ShingleSize+2 {
+				continue
+			}
+			for _, h := range shingleHashes(words[1 : len(words)-1]) {
+				hashes[h] = true
+			}
+		}
+		if len(hashes) == 0 {
+			continue
+		}
+		sketch := make([]uint64, 0, len(hashes))
+		for h := range hashes {
+			sketch = append(sketch, h)
+		}
+		sort.Slice(sketch, func(i, j int) bool { return sketch[i] < sketch[j] })
+		if len(sketch) > SketchSize {
+			sketch = sketch[:SketchSize]
+		}
+		ci.sketches[key.FilePath] = sketch
+		for _, h := range sketch {
+			ci.postings[h] = append(ci.postings[h], key.FilePath)
+		}
+	}
+	return ci
+}
+
+// Candidates returns the patterns which may match the normalized text
+func (ci *CandidateIndex) Candidates(normalizedText string) Candidates {
+	if ci == nil {
+		return Candidates{}
+	}
+	counts := make(map[string]int)
+	seen := make(map[uint64]bool)
+	for _, h := range shingleHashes(strings.Fields(normalizedText)) {
+		patterns, ok := ci.postings[h]
+		if !ok || seen[h] {
+			continue
+		}
+		seen[h] = true
+		for _, p := range patterns {
+			counts[p]++
+		}
+	}
+	ret := Candidates{index: ci, found: make(map[string]bool)}
+	for p, n := range counts {
+		if n == len(ci.sketches[p]) {
+			ret.found[p] = true
+		}
+	}
+	return ret
+}
+
+// ForLanguages returns the candidates without the translated patterns in other languages than the
+// languages of the input. When the languages are not known (nil), the translated patterns are kept.
+func (c Candidates) ForLanguages(languages []string) Candidates {
+	c.languages = languages
+	return c
+}
+
+// Has is true when the pattern may match (a candidate, or a pattern which is not indexed)
+func (c Candidates) Has(fileName string) bool {
+	if lang := PatternLanguage(fileName); lang != "" && len(c.languages) > 0 && !slices.Contains(c.languages, lang) {
+		return false
+	}
+	if c.index == nil {
+		return true
+	}
+	if _, indexed := c.index.sketches[fileName]; !indexed {
+		return true
+	}
+	return c.found[fileName]
+}
+
+// Len returns the number of indexed patterns which are candidates (-1 without an index)
+func (c Candidates) Len() int {
+	if c.index == nil {
+		return -1
+	}
+	return len(c.found)
+}
+
+// PatternLanguage returns the language of a translated pattern file, from the language code before the
+// extension (e.g., "fr" for license_notice.fr.txt), or "" for the patterns without a language code (English).
+// The file name may have either path separator, since a compiled library may be used on another OS.
+func PatternLanguage(fileName string) string {
+	name := fileName[strings.LastIndexAny(fileName, `/\`)+1:]
+	name = strings.TrimSuffix(name, path.Ext(name))
+	ext := path.Ext(name)
+	if len(ext) != 3 || ext[1] < 'a' || ext[1] > 'z' || ext[2] < 'a' || ext[2] > 'z' {
+		return ""
+	}
+	return ext[1:]
+}
+
+// shingleHashes returns the hash of each run of ShingleSize words
+func shingleHashes(words []string) []uint64 {
+	if len(words) < ShingleSize {
+		return nil
+	}
+	ret := make([]uint64, 0, len(words)-ShingleSize+1)
+	for i := 0; i+ShingleSize <= len(words); i++ {
+		h := fnv.New64a()
+		for j, w := range words[i : i+ShingleSize] {
+			if j > 0 {
+				_, _ = h.Write([]byte{' '})
+			}
+			_, _ = h.Write([]byte(w))
+		}
+		ret = append(ret, h.Sum64())
+	}
+	return ret
+}
@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+func TestCandidateIndex(t *testing.T) {
+	t.Parallel()
+	preChecks := PrimaryPatternPreCheckMap{
+		{FilePath: "mit"}:   {StaticBlocks: []string{"ssion is hereby granted,free of charge,to any person obtaining a copy"}},
+		{FilePath: "bsd"}:   {StaticBlocks: []string{"redistribution and use in source and binary forms,with or without modification,are permitted"}},
+		{FilePath: "short"}: {StaticBlocks: []string{"all rights reserved"}},
+	}
+	ci := NewCandidateIndex(preChecks)
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]bool
+	}{
+		{
+			name:  "mit",
+			input: "copyright 2022 acme. permission is hereby granted,free of charge,to any person obtaining a copy of this software",
+			want:  map[string]bool{"mit": true, "bsd": false, "short": true, "not indexed": true},
+		},
+		{
+			name:  "partial",
+			input: "permission is hereby granted,free of charge,to any person",
+			want:  map[string]bool{"mit": false, "bsd": false, "short": true},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  map[string]bool{"mit": false, "bsd": false, "short": true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			candidates := ci.Candidates(tt.input)
+			for fileName, want := range tt.want {
+				if got := candidates.Has(fileName); got != want {
+					t.Errorf("Has(%v) = %v, want %v", fileName, got, want)
+				}
+			}
+		})
+	}
+
+	var noIndex *CandidateIndex
+	if c := noIndex.Candidates("anything"); !c.Has("mit") || c.Len() != -1 {
+		t.Errorf("Candidates() without an index should have all the patterns")
+	}
+}
+
+// TestCandidateIndex_SPDX checks that the index never leaves out a pattern whose static blocks are all in the input
+func TestCandidateIndex_SPDX(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	if ll.CandidateIndex == nil {
+		t.Fatal("AddAllSPDX() did not build the CandidateIndex")
+	}
+
+	testDataDir := filepath.Join(ll.Config.GetString(Resources), "spdx", ll.Config.GetString(SPDX), "testdata")
+	files, err := os.ReadDir(testDataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".txt") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(testDataDir, f.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		nd := normalizer.NewNormalizationData(string(b), false)
+		if err := nd.NormalizeText(); err != nil {
+			t.Fatalf("NormalizeText(%v) error = %v", f.Name(), err)
+		}
+		candidates := ll.CandidateIndex.Candidates(nd.NormalizedText)
+		if candidates.Len() > len(ll.PrimaryPatternPreCheckMap)/2 {
+			t.Errorf("%v has %v candidates", f.Name(), candidates.Len())
+		}
+		for key, pc := range ll.PrimaryPatternPreCheckMap {
+			passed := true
+			for _, block := range pc.StaticBlocks {
+				if !strings.Contains(nd.NormalizedText, block) {
+					passed = false
+					break
+				}
+			}
+			if passed && !candidates.Has(key.FilePath) {
+				t.Errorf("%v passed the prechecks of %v, but it is not a candidate", f.Name(), key.FilePath)
+			}
+		}
+	}
+}
+
+func TestCandidatesForLanguages(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		languages []string
+		want      map[string]bool
+	}{
+		{
+			name:      "french",
+			languages: []string{"en", "fr"},
+			want:      map[string]bool{"license_MIT.txt": true, "license_notice.fr.txt": true, "license_notice.de.txt": false},
+		},
+		{
+			name: "unknown",
+			want: map[string]bool{"license_MIT.txt": true, "license_notice.fr.txt": true, "license_notice.de.txt": true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			candidates := Candidates{}.ForLanguages(tt.languages)
+			for fileName, want := range tt.want {
+				if got := candidates.Has("/resources/CC-BY-4.0/" + fileName); got != want {
+					t.Errorf("Has(%v) = %v, want %v", fileName, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPatternLanguage(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"license_notice.fr.txt":               "fr",
+		"/resources/EUPL-1.2/title.de.txt":    "de",
+		"license_Apache-2.0.txt":              "",
+		"license_GPL-2.0.txt":                 "",
+		"EUPL-1.2.template.txt":               "",
+		"/resources/spdx/template/MIT.Mx.txt": "",
+		`C:\resources\EUPL-1.2\title.it.txt`:  "it",
+		`C:\resources.fr\EUPL-1.2\title.txt`:  "",
+	}
+	for fileName, want := range tests {
+		if got := PatternLanguage(fileName); got != want {
+			t.Errorf("PatternLanguage(%v) = %q, want %q", fileName, got, want)
+		}
+	}
+}
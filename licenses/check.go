@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// ResourceCheck is a path of the configuration and whether it can be read
+type ResourceCheck struct {
+	// Name is what the path is for (e.g., the SPDX templates)
+	Name string
+	Path string
+	// Dir is true when the path must be a directory (otherwise a file)
+	Dir bool
+	// Err is why the path cannot be used (nil when it can be read)
+	Err error
+}
+
+// OK is true when the path can be read
+func (c ResourceCheck) OK() bool {
+	return c.Err == nil
+}
+
+// CheckPath checks that the path exists, is a directory (or a file), and can be read
+func CheckPath(name string, p string, dir bool) ResourceCheck {
+	c := ResourceCheck{Name: name, Path: p, Dir: dir}
+	fi, err := os.Stat(p)
+	if err != nil {
+		c.Err = err
+		return c
+	}
+	switch {
+	case dir && !fi.IsDir():
+		c.Err = fmt.Errorf("%v is not a directory", p)
+	case dir:
+		_, c.Err = os.ReadDir(p)
+	case fi.IsDir():
+		c.Err = fmt.Errorf("%v is a directory", p)
+	default:
+		var f *os.File
+		if f, c.Err = os.Open(p); c.Err == nil {
+			c.Err = f.Close()
+		}
+	}
+	return c
+}
+
+// CheckResources resolves the paths of the resources of the configuration, and checks that they exist and
+// can be read: the --compiled library file, or the SPDX resources (of --spdx) and the custom resources (of
+// --custom) under the resources directory.
+func CheckResources(cfg *viper.Viper) []ResourceCheck {
+	if compiled := cfg.GetString(configurer.CompiledFlag); compiled != "" {
+		return []ResourceCheck{CheckPath("compiled library", compiled, false)}
+	}
+	resourcesPath := filepath.Clean(cfg.GetString(Resources))
+	spdxPath := filepath.Join(resourcesPath, "spdx", cfg.GetString(SPDX))
+	customPath := filepath.Join(resourcesPath, customDir, cfg.GetString(configurer.CustomFlag))
+	return []ResourceCheck{
+		CheckPath("resources", resourcesPath, true),
+		CheckPath("SPDX resources", spdxPath, true),
+		CheckPath("SPDX license list", filepath.Join(spdxPath, jsonDir, "licenses.json"), false),
+		CheckPath("SPDX exception list", filepath.Join(spdxPath, jsonDir, "exceptions.json"), false),
+		CheckPath("SPDX templates", filepath.Join(spdxPath, template), true),
+		CheckPath("SPDX prechecks", filepath.Join(spdxPath, precheck), true),
+		CheckPath("custom resources", customPath, true),
+		CheckPath("custom license patterns", filepath.Join(customPath, LicensePatterns), true),
+	}
+}
@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestCheckPath(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "risk.yaml")
+	if err := os.WriteFile(file, []byte("default: high\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		path   string
+		dir    bool
+		wantOK bool
+	}{
+		{name: "file", path: file, wantOK: true},
+		{name: "dir", path: dir, dir: true, wantOK: true},
+		{name: "missing", path: filepath.Join(dir, "missing.yaml")},
+		{name: "dir not a file", path: dir},
+		{name: "file not a dir", path: file, dir: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := CheckPath(tt.name, tt.path, tt.dir); got.OK() != tt.wantOK {
+				t.Errorf("CheckPath() = %v, want OK %v", got, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestCheckResources(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		flags    map[string]string
+		wantFail []string
+	}{
+		{name: "default"},
+		{name: "missing custom", flags: map[string]string{configurer.CustomFlag: "missing"}, wantFail: []string{"custom resources", "custom license patterns"}},
+		{name: "missing compiled", flags: map[string]string{configurer.CompiledFlag: "missing.bin"}, wantFail: []string{"compiled library"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flags := configurer.NewDefaultFlags()
+			for name, value := range tt.flags {
+				if err := flags.Set(name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			config, err := configurer.InitConfig(flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var failed []string
+			for _, c := range CheckResources(config) {
+				if !c.OK() {
+					failed = append(failed, c.Name)
+				}
+			}
+			if d := cmp.Diff(tt.wantFail, failed); d != "" {
+				t.Errorf("CheckResources() failed mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit && windows
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestCheckResourcesPathsWindows(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		resources string
+		want      map[string]string
+	}{
+		{
+			name:      "drive letter",
+			resources: `C:\license-scanner\resources`,
+			want: map[string]string{
+				"resources":               `C:\license-scanner\resources`,
+				"SPDX license list":       `C:\license-scanner\resources\spdx\3.21\json\licenses.json`,
+				"SPDX templates":          `C:\license-scanner\resources\spdx\3.21\template`,
+				"custom license patterns": `C:\license-scanner\resources\custom\default\license_patterns`,
+			},
+		},
+		{
+			name:      "slashes",
+			resources: "D:/resources",
+			want: map[string]string{
+				"resources":               `D:\resources`,
+				"SPDX license list":       `D:\resources\spdx\3.21\json\licenses.json`,
+				"SPDX templates":          `D:\resources\spdx\3.21\template`,
+				"custom license patterns": `D:\resources\custom\default\license_patterns`,
+			},
+		},
+		{
+			name:      "UNC",
+			resources: `\\server\share\resources`,
+			want: map[string]string{
+				"resources":               `\\server\share\resources`,
+				"SPDX license list":       `\\server\share\resources\spdx\3.21\json\licenses.json`,
+				"SPDX templates":          `\\server\share\resources\spdx\3.21\template`,
+				"custom license patterns": `\\server\share\resources\custom\default\license_patterns`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := viper.New()
+			cfg.Set(Resources, tt.resources)
+			cfg.Set(SPDX, "3.21")
+			cfg.Set(configurer.CustomFlag, "default")
+			got := make(map[string]string)
+			for _, c := range CheckResources(cfg) {
+				if _, ok := tt.want[c.Name]; ok {
+					got[c.Name] = c.Path
+				}
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("CheckResources() paths mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
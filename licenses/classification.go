@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// ClassificationsJSON is the optional file (in the custom resources) with classification rules
+// which take precedence over the DefaultClassificationRules
+const ClassificationsJSON = "classifications.json"
+
+// The license categories
+const (
+	Permissive     = "permissive"
+	WeakCopyleft   = "weak-copyleft"
+	StrongCopyleft = "strong-copyleft"
+	Proprietary    = "proprietary"
+	PublicDomain   = "public-domain"
+)
+
+// Classification is the family (e.g., GPL, BSD, CC) and the category (e.g., permissive) of a license
+type Classification struct {
+	Family   string `json:"family,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// ClassificationRule classifies the license IDs matching the pattern (wildcards allowed, case-insensitive).
+// A rule may set only the family or only the category. The first matching rule with a value wins.
+type ClassificationRule struct {
+	Pattern string `json:"pattern"`
+	Classification
+}
+
+// DefaultClassificationRules are the built-in rules. The more specific patterns come first.
+var DefaultClassificationRules = []ClassificationRule{
+	// Exceptions to the family category
+	{Pattern: "GPL-*-with-classpath-exception", Classification: Classification{Category: WeakCopyleft}},
+	{Pattern: "GPL-*-with-GCC-exception", Classification: Classification{Category: WeakCopyleft}},
+	{Pattern: "CC0-*", Classification: Classification{Family: "CC", Category: PublicDomain}},
+	{Pattern: "CC-PDDC", Classification: Classification{Family: "CC", Category: PublicDomain}},
+	{Pattern: "CC-BY-NC*", Classification: Classification{Family: "CC", Category: Proprietary}},
+	{Pattern: "CC-BY-SA-*", Classification: Classification{Family: "CC", Category: StrongCopyleft}},
+	{Pattern: "CC-BY-ND-*", Classification: Classification{Family: "CC", Category: Proprietary}},
+	{Pattern: "CC-BY-[0-9]*", Classification: Classification{Family: "CC", Category: Permissive}},
+	{Pattern: "CECILL-B", Classification: Classification{Family: "CeCILL", Category: Permissive}},
+	{Pattern: "CECILL-C", Classification: Classification{Family: "CeCILL", Category: WeakCopyleft}},
+
+	// Strong copyleft
+	{Pattern: "AGPL-*", Classification: Classification{Family: "AGPL", Category: StrongCopyleft}},
+	{Pattern: "GPL-*", Classification: Classification{Family: "GPL", Category: StrongCopyleft}},
+	{Pattern: "GFDL-*", Classification: Classification{Family: "GFDL", Category: StrongCopyleft}},
+	{Pattern: "CECILL-*", Classification: Classification{Family: "CeCILL", Category: StrongCopyleft}},
+	{Pattern: "EUPL-*", Classification: Classification{Family: "EUPL", Category: StrongCopyleft}},
+	{Pattern: "OSL-*", Classification: Classification{Family: "OSL", Category: StrongCopyleft}},
+	{Pattern: "RPL-*", Classification: Classification{Family: "RPL", Category: StrongCopyleft}},
+	{Pattern: "SSPL-*", Classification: Classification{Family: "SSPL", Category: StrongCopyleft}},
+	{Pattern: "Sleepycat", Classification: Classification{Category: StrongCopyleft}},
+
+	// Weak copyleft
+	{Pattern: "LGPL*", Classification: Classification{Family: "LGPL", Category: WeakCopyleft}},
+	{Pattern: "MPL-*", Classification: Classification{Family: "MPL", Category: WeakCopyleft}},
+	{Pattern: "EPL-*", Classification: Classification{Family: "EPL", Category: WeakCopyleft}},
+	{Pattern: "CDDL-*", Classification: Classification{Family: "CDDL", Category: WeakCopyleft}},
+	{Pattern: "CPL-*", Classification: Classification{Family: "CPL", Category: WeakCopyleft}},
+	{Pattern: "APSL-*", Classification: Classification{Family: "APSL", Category: WeakCopyleft}},
+	{Pattern: "OFL-*", Classification: Classification{Family: "OFL", Category: WeakCopyleft}},
+	{Pattern: "Ms-RL", Classification: Classification{Family: "Ms", Category: WeakCopyleft}},
+	{Pattern: "IPL-*", Classification: Classification{Category: WeakCopyleft}},
+	{Pattern: "ErlPL-*", Classification: Classification{Category: WeakCopyleft}},
+
+	// Proprietary (source available, non-commercial, etc.)
+	{Pattern: "BUSL-*", Classification: Classification{Category: Proprietary}},
+	{Pattern: "Elastic-*", Classification: Classification{Category: Proprietary}},
+	{Pattern: "PolyForm-*", Classification: Classification{Family: "PolyForm", Category: Proprietary}},
+	{Pattern: "Commons-Clause", Classification: Classification{Category: Proprietary}},
+
+	// Public domain
+	{Pattern: "Unlicense", Classification: Classification{Category: PublicDomain}},
+	{Pattern: "PDDL-*", Classification: Classification{Category: PublicDomain}},
+	{Pattern: "SAX-PD", Classification: Classification{Category: PublicDomain}},
+	{Pattern: "blessing", Classification: Classification{Category: PublicDomain}},
+
+	// Permissive
+	{Pattern: "0BSD", Classification: Classification{Family: "BSD", Category: Permissive}},
+	{Pattern: "BSD-*", Classification: Classification{Family: "BSD", Category: Permissive}},
+	{Pattern: "MIT*", Classification: Classification{Family: "MIT", Category: Permissive}},
+	{Pattern: "X11*", Classification: Classification{Family: "MIT", Category: Permissive}},
+	{Pattern: "Apache-*", Classification: Classification{Family: "Apache", Category: Permissive}},
+	{Pattern: "AFL-*", Classification: Classification{Family: "AFL", Category: Permissive}},
+	{Pattern: "Artistic-2.0", Classification: Classification{Family: "Artistic", Category: Permissive}},
+	{Pattern: "Artistic-*", Classification: Classification{Family: "Artistic"}},
+	{Pattern: "Python-*", Classification: Classification{Family: "Python", Category: Permissive}},
+	{Pattern: "PSF-*", Classification: Classification{Family: "Python", Category: Permissive}},
+	{Pattern: "ZPL-*", Classification: Classification{Family: "ZPL", Category: Permissive}},
+	{Pattern: "PHP-*", Classification: Classification{Family: "PHP", Category: Permissive}},
+	{Pattern: "W3C*", Classification: Classification{Family: "W3C", Category: Permissive}},
+	{Pattern: "Unicode-*", Classification: Classification{Family: "Unicode", Category: Permissive}},
+	{Pattern: "HPND*", Classification: Classification{Family: "HPND", Category: Permissive}},
+	{Pattern: "ECL-*", Classification: Classification{Family: "ECL", Category: Permissive}},
+	{Pattern: "Zlib", Classification: Classification{Family: "zlib", Category: Permissive}},
+	{Pattern: "zlib-*", Classification: Classification{Family: "zlib", Category: Permissive}},
+	{Pattern: "bzip2-*", Classification: Classification{Category: Permissive}},
+	{Pattern: "BSL-1.0", Classification: Classification{Category: Permissive}},
+	{Pattern: "ISC", Classification: Classification{Category: Permissive}},
+	{Pattern: "NCSA", Classification: Classification{Category: Permissive}},
+	{Pattern: "PostgreSQL", Classification: Classification{Category: Permissive}},
+	{Pattern: "UPL-1.0", Classification: Classification{Category: Permissive}},
+	{Pattern: "OpenSSL", Classification: Classification{Category: Permissive}},
+	{Pattern: "curl", Classification: Classification{Category: Permissive}},
+	{Pattern: "Libpng", Classification: Classification{Category: Permissive}},
+	{Pattern: "libpng-*", Classification: Classification{Category: Permissive}},
+	{Pattern: "ICU", Classification: Classification{Category: Permissive}},
+	{Pattern: "FTL", Classification: Classification{Category: Permissive}},
+	{Pattern: "WTFPL", Classification: Classification{Category: Permissive}},
+	{Pattern: "Beerware", Classification: Classification{Category: Permissive}},
+}
+
+// Classify returns the family and category of a license. The family and category in the license
+// info (license_info.json) are used first, then the library's ClassificationRules.
+func (ll *LicenseLibrary) Classify(id string) Classification {
+	info := ll.LicenseMap[id].LicenseInfo
+	ret := Classification{Family: info.Family, Category: info.Category}
+	rules := ll.ClassificationRules
+	if rules == nil {
+		rules = DefaultClassificationRules
+	}
+	for _, rule := range rules {
+		if ret.Family != "" && ret.Category != "" {
+			break
+		}
+		if matched, _ := matchAny([]string{rule.Pattern}, id); !matched {
+			continue
+		}
+		if ret.Family == "" {
+			ret.Family = rule.Family
+		}
+		if ret.Category == "" {
+			ret.Category = rule.Category
+		}
+	}
+	return ret
+}
+
+// addClassificationRules puts the rules from the custom classifications.json (if any) before the default rules
+func (ll *LicenseLibrary) addClassificationRules() error {
+	f := filepath.Join(ll.resourcesDir(), customDir, ll.Config.GetString(configurer.CustomFlag), ClassificationsJSON)
+	b, err := ll.resourceFiles().ReadFile(f)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var rules []ClassificationRule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return fmt.Errorf("cannot unmarshal %v: %w", f, err)
+	}
+	for _, rule := range rules {
+		if _, err := matchAny([]string{rule.Pattern}, ""); err != nil || strings.TrimSpace(rule.Pattern) == "" {
+			return fmt.Errorf("invalid classification rule in %v: %q", f, rule.Pattern)
+		}
+	}
+	ll.ClassificationRules = append(rules, DefaultClassificationRules...)
+	return nil
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_Classify(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+	}
+	ll.LicenseMap["Custom"] = License{LicenseInfo: LicenseInfo{Family: "Acme", Category: Proprietary}}
+	ll.LicenseMap["Apache-2.0"] = License{LicenseInfo: LicenseInfo{Family: "Apache"}}
+
+	tests := []struct {
+		id   string
+		want Classification
+	}{
+		{id: "GPL-2.0-only", want: Classification{Family: "GPL", Category: StrongCopyleft}},
+		{id: "GPL-2.0-with-classpath-exception", want: Classification{Family: "GPL", Category: WeakCopyleft}},
+		{id: "AGPL-3.0-or-later", want: Classification{Family: "AGPL", Category: StrongCopyleft}},
+		{id: "LGPL-2.1-only", want: Classification{Family: "LGPL", Category: WeakCopyleft}},
+		{id: "BSD-3-Clause", want: Classification{Family: "BSD", Category: Permissive}},
+		{id: "CC-BY-4.0", want: Classification{Family: "CC", Category: Permissive}},
+		{id: "CC-BY-SA-4.0", want: Classification{Family: "CC", Category: StrongCopyleft}},
+		{id: "CC-BY-NC-4.0", want: Classification{Family: "CC", Category: Proprietary}},
+		{id: "CC0-1.0", want: Classification{Family: "CC", Category: PublicDomain}},
+		{id: "zlib", want: Classification{Family: "zlib", Category: Permissive}},
+		{id: "Apache-2.0", want: Classification{Family: "Apache", Category: Permissive}},
+		{id: "Custom", want: Classification{Family: "Acme", Category: Proprietary}},
+		{id: "Unknown-1.0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, ll.Classify(tt.id)); d != "" {
+				t.Errorf("Classify(%v) mismatch (-want +got):\n%s", tt.id, d)
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_addClassificationRules(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	customPath := filepath.Join(resources, customDir, "test")
+	if err := os.MkdirAll(customPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rules := `[{"pattern": "MIT", "category": "proprietary"}, {"pattern": "Acme-*", "family": "Acme"}]`
+	if err := os.WriteFile(filepath.Join(customPath, ClassificationsJSON), []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Set(Resources, resources)
+	config.Set(configurer.CustomFlag, "test")
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.addClassificationRules(); err != nil {
+		t.Fatalf("addClassificationRules() error = %v", err)
+	}
+
+	for id, want := range map[string]Classification{
+		"MIT":          {Family: "MIT", Category: Proprietary},
+		"Acme-1.0":     {Family: "Acme"},
+		"BSD-2-Clause": {Family: "BSD", Category: Permissive},
+	} {
+		if d := cmp.Diff(want, ll.Classify(id)); d != "" {
+			t.Errorf("Classify(%v) mismatch (-want +got):\n%s", id, d)
+		}
+	}
+}
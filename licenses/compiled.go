@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"runtime"
+	"sync"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/version"
+)
+
+// CompiledLibraryVersion is the format version of the compiled library files. A file of another version
+// must be compiled again.
+const CompiledLibraryVersion = 4
+
+// compiledMagic starts a compiled library file
+var compiledMagic = []byte("license-scanner library\n")
+
+// compiledLibrary is a loaded license library with the regexps of the patterns already generated (the Go
+// regexp programs cannot be serialized, so the generated regexps are compiled on first use, which skips
+// the normalization and generation of the templates, most of the cost)
+type compiledLibrary struct {
+	Version     int
+	SPDX        string
+	Custom      string
+	SPDXVersion string
+	SPDXImport  *version.Import
+	// MaxVariableLength is the --maxVariableLength of the generated regexps
+	MaxVariableLength int
+	// CustomNamespace and CustomCollision are the --customNamespace and --customCollision of the license IDs
+	CustomNamespace string
+	CustomCollision string
+	Licenses        map[string]compiledLicense
+	// PreChecks are by pattern file name
+	PreChecks           map[string]*LicensePreChecks
+	AcceptablePatterns  map[string]string
+	ExactHashMap        ExactHashMap
+	ClassificationRules []ClassificationRule
+	ObligationRules     []ObligationRule
+	MatchGuards         []MatchGuard
+	LicenseMappings     map[string]string
+	// LinePrefixes are the line prefixes of the custom line_prefixes.json, to normalize the texts like the patterns
+	LinePrefixes []string
+	// Sketches and Postings are the candidate index
+	Sketches map[string][]uint64
+	Postings map[uint64][]string
+}
+
+type compiledLicense struct {
+	SPDXLicenseID             string
+	LicenseInfo               LicenseInfo
+	PrimaryPatterns           []compiledPattern
+	PrimaryPatternsSources    []PrimaryPatternsSources
+	AssociatedPatterns        []compiledPattern
+	AssociatedPatternsSources []PrimaryPatternsSources
+	HeaderPatterns            []compiledPattern
+	Aliases                   []string
+	URLs                      []string
+	Text                      LicenseText
+	// NoObligations is true for the empty (not nil) obligations of the license info, which gob does not keep
+	NoObligations bool
+}
+
+type compiledPattern struct {
+	Text     string
+	FileName string
+	// Regexp is the generated regexp ("" when it cannot be generated, to report the error on first use)
+	Regexp        string
+	CaptureGroups []*normalizer.CaptureGroup
+}
+
+// WriteCompiled writes the library (loaded with AddAllResources, before any Filter) as a compiled library,
+// generating the regexps of all the patterns
+func (ll *LicenseLibrary) WriteCompiled(w io.Writer) error {
+	ll.generateAllRegexps()
+	c := compiledLibrary{
+		Version:             CompiledLibraryVersion,
+		SPDX:                ll.Config.GetString(configurer.SpdxFlag),
+		Custom:              ll.Config.GetString(configurer.CustomFlag),
+		SPDXVersion:         ll.SPDXVersion,
+		SPDXImport:          ll.SPDXImport,
+		MaxVariableLength:   ll.MaxVariableLength(),
+		CustomNamespace:     ll.Config.GetString(configurer.CustomNamespaceFlag),
+		CustomCollision:     ll.Config.GetString(configurer.CustomCollisionFlag),
+		Licenses:            make(map[string]compiledLicense, len(ll.LicenseMap)),
+		PreChecks:           make(map[string]*LicensePreChecks, len(ll.PrimaryPatternPreCheckMap)),
+		AcceptablePatterns:  make(map[string]string, len(ll.AcceptablePatternsMap)),
+		ExactHashMap:        ll.ExactHashMap,
+		ClassificationRules: ll.ClassificationRules,
+		ObligationRules:     ll.ObligationRules,
+		MatchGuards:         ll.MatchGuards,
+		LicenseMappings:     ll.LicenseMappings,
+		LinePrefixes:        ll.LinePrefixes.Prefixes(),
+	}
+	for id, l := range ll.LicenseMap {
+		c.Licenses[id] = compiledLicense{
+			SPDXLicenseID:             l.SPDXLicenseID,
+			LicenseInfo:               l.LicenseInfo,
+			PrimaryPatterns:           compilePatterns(l.PrimaryPatterns),
+			PrimaryPatternsSources:    l.PrimaryPatternsSources,
+			AssociatedPatterns:        compilePatterns(l.AssociatedPatterns),
+			AssociatedPatternsSources: l.AssociatedPatternsSources,
+			HeaderPatterns:            compilePatterns(l.HeaderPatterns),
+			Aliases:                   l.Aliases,
+			URLs:                      l.URLs,
+			Text:                      l.Text,
+			NoObligations:             l.LicenseInfo.Obligations != nil && len(l.LicenseInfo.Obligations) == 0,
+		}
+	}
+	for key, preChecks := range ll.PrimaryPatternPreCheckMap {
+		c.PreChecks[key.FilePath] = preChecks
+	}
+	for id, re := range ll.AcceptablePatternsMap {
+		c.AcceptablePatterns[id] = re.String()
+	}
+	if ll.CandidateIndex != nil {
+		c.Sketches, c.Postings = ll.CandidateIndex.sketches, ll.CandidateIndex.postings
+	}
+
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(compiledMagic); err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(bw).Encode(c); err != nil {
+		return fmt.Errorf("cannot encode the compiled library: %w", err)
+	}
+	return bw.Flush()
+}
+
+// generateAllRegexps generates the regexps of all the patterns (concurrently)
+func (ll *LicenseLibrary) generateAllRegexps() {
+	patterns := make(chan *PrimaryPatterns)
+	var wg sync.WaitGroup
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pp := range patterns {
+				if _, err := GenerateMatchingPatternFromSourceText(pp); err != nil {
+					Logger.Debugf("Cannot generate the regexp of %v: %v", pp.FileName, err)
+				}
+			}
+		}()
+	}
+	for _, l := range ll.LicenseMap {
+		for _, pp := range append(append(append([]*PrimaryPatterns{}, l.PrimaryPatterns...), l.AssociatedPatterns...), l.HeaderPatterns...) {
+			patterns <- pp
+		}
+	}
+	close(patterns)
+	wg.Wait()
+}
+
+func compilePatterns(patterns []*PrimaryPatterns) []compiledPattern {
+	var ret []compiledPattern
+	for _, pp := range patterns {
+		cp := compiledPattern{Text: pp.Text, FileName: pp.FileName}
+		if re, err := GenerateMatchingPatternFromSourceText(pp); err == nil && re != nil {
+			cp.Regexp = re.String()
+			cp.CaptureGroups = pp.CaptureGroups
+		}
+		ret = append(ret, cp)
+	}
+	return ret
+}
+
+// ReadCompiled loads a compiled library (see WriteCompiled) into an empty library, instead of
+// AddAllResources. The SPDX and custom resources of the compiled library must be the ones of the config.
+func (ll *LicenseLibrary) ReadCompiled(r io.Reader) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(compiledMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, compiledMagic) {
+		return fmt.Errorf("not a compiled license library")
+	}
+	var c compiledLibrary
+	if err := gob.NewDecoder(br).Decode(&c); err != nil {
+		return fmt.Errorf("cannot decode the compiled library: %w", err)
+	}
+	if c.Version != CompiledLibraryVersion {
+		return fmt.Errorf("the compiled library version is %v instead of %v (compile it again)", c.Version, CompiledLibraryVersion)
+	}
+	if spdx, custom := ll.Config.GetString(configurer.SpdxFlag), ll.Config.GetString(configurer.CustomFlag); c.SPDX != spdx || c.Custom != custom {
+		return fmt.Errorf("the library was compiled with --spdx %v and --custom %v instead of --spdx %v and --custom %v", c.SPDX, c.Custom, spdx, custom)
+	}
+	if max := ll.MaxVariableLength(); c.MaxVariableLength != max {
+		return fmt.Errorf("the library was compiled with --%v %v instead of %v", configurer.MaxVariableLengthFlag, c.MaxVariableLength, max)
+	}
+	if ns, collision := ll.Config.GetString(configurer.CustomNamespaceFlag), ll.Config.GetString(configurer.CustomCollisionFlag); c.CustomNamespace != ns || c.CustomCollision != collision {
+		return fmt.Errorf("the library was compiled with --%v %q and --%v %q instead of %q and %q", configurer.CustomNamespaceFlag, c.CustomNamespace, configurer.CustomCollisionFlag, c.CustomCollision, ns, collision)
+	}
+
+	ll.SPDXVersion = c.SPDXVersion
+	ll.SPDXImport = c.SPDXImport
+	for id, cl := range c.Licenses {
+		l := License{
+			SPDXLicenseID:             cl.SPDXLicenseID,
+			LicenseInfo:               cl.LicenseInfo,
+			PrimaryPatterns:           loadPatterns(cl.PrimaryPatterns),
+			PrimaryPatternsSources:    cl.PrimaryPatternsSources,
+			AssociatedPatterns:        loadPatterns(cl.AssociatedPatterns),
+			AssociatedPatternsSources: cl.AssociatedPatternsSources,
+			HeaderPatterns:            loadPatterns(cl.HeaderPatterns),
+			Aliases:                   cl.Aliases,
+			URLs:                      cl.URLs,
+			Text:                      cl.Text,
+		}
+		if cl.NoObligations {
+			l.LicenseInfo.Obligations = SliceOfStrings{}
+		}
+		ll.LicenseMap[id] = l
+	}
+	for filePath, preChecks := range c.PreChecks {
+		ll.PrimaryPatternPreCheckMap[LicensePatternKey{FilePath: filePath}] = preChecks
+	}
+	for id, source := range c.AcceptablePatterns {
+		re, err := regexp.Compile(source)
+		if err != nil {
+			return fmt.Errorf("invalid acceptable pattern %v in the compiled library: %w", id, err)
+		}
+		ll.AcceptablePatternsMap[id] = re
+	}
+	if c.ExactHashMap != nil {
+		ll.ExactHashMap = c.ExactHashMap
+	}
+	ll.ClassificationRules = c.ClassificationRules
+	ll.ObligationRules = c.ObligationRules
+	ll.MatchGuards = c.MatchGuards
+	ll.LicenseMappings = c.LicenseMappings
+	linePrefixes, err := normalizer.NewLinePrefixes(c.LinePrefixes)
+	if err != nil {
+		return fmt.Errorf("invalid line prefixes of the compiled library: %w", err)
+	}
+	ll.LinePrefixes = linePrefixes
+	ll.setLinePrefixes()
+	if c.Sketches != nil {
+		ll.CandidateIndex = &CandidateIndex{sketches: c.Sketches, postings: c.Postings}
+	}
+	return nil
+}
+
+func loadPatterns(patterns []compiledPattern) []*PrimaryPatterns {
+	var ret []*PrimaryPatterns
+	for _, cp := range patterns {
+		ret = append(ret, &PrimaryPatterns{Text: cp.Text, FileName: cp.FileName, source: cp.Regexp, CaptureGroups: cp.CaptureGroups})
+	}
+	return ret
+}
+
+// LoadCompiled loads a compiled library file (see ReadCompiled)
+func (ll *LicenseLibrary) LoadCompiled(filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := ll.ReadCompiled(f); err != nil {
+		return fmt.Errorf("%v: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_ReadCompiled(t *testing.T) {
+	t.Parallel()
+	resourcesFlag := configurer.NewDefaultFlags()
+	if err := resourcesFlag.Set(configurer.ConfigPathFlag, "../testdata/config/"); err != nil {
+		t.Fatal(err)
+	}
+	config, err := configurer.InitConfig(resourcesFlag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllResources(); err != nil {
+		t.Fatalf("AddAllResources() error = %v", err)
+	}
+	var compiled bytes.Buffer
+	if err := ll.WriteCompiled(&compiled); err != nil {
+		t.Fatalf("WriteCompiled() error = %v", err)
+	}
+
+	loaded, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := loaded.ReadCompiled(bytes.NewReader(compiled.Bytes())); err != nil {
+		t.Fatalf("ReadCompiled() error = %v", err)
+	}
+
+	// the patterns have the same regexps, without generating them again
+	regexps := func(ll *LicenseLibrary) map[string]string {
+		ret := make(map[string]string)
+		for _, l := range ll.LicenseMap {
+			for _, pp := range append(append([]*PrimaryPatterns{}, l.PrimaryPatterns...), l.AssociatedPatterns...) {
+				if pp.source != "" {
+					ret[pp.FileName] = pp.source
+					continue
+				}
+				re, err := GenerateMatchingPatternFromSourceText(pp)
+				if err != nil {
+					t.Fatalf("GenerateMatchingPatternFromSourceText(%v) error = %v", pp.FileName, err)
+				}
+				ret[pp.FileName] = re.String()
+			}
+		}
+		return ret
+	}
+	want, got := regexps(ll), regexps(loaded)
+	if len(want) == 0 {
+		t.Fatalf("no patterns in the library")
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("ReadCompiled() patterns mismatch (-want +got):\n%s", d)
+	}
+	for id, l := range loaded.LicenseMap {
+		for _, pp := range l.PrimaryPatterns {
+			if pp.source == "" {
+				t.Errorf("ReadCompiled() pattern %v of %v has no generated regexp", pp.FileName, id)
+			}
+		}
+	}
+
+	ignore := cmpopts.IgnoreFields(License{}, "PrimaryPatterns", "AssociatedPatterns")
+	if d := cmp.Diff(ll.LicenseMap, loaded.LicenseMap, ignore, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("ReadCompiled() licenses mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(ll.PrimaryPatternPreCheckMap, loaded.PrimaryPatternPreCheckMap, cmpopts.EquateEmpty()); d != "" {
+		t.Errorf("ReadCompiled() prechecks mismatch (-want +got):\n%s", d)
+	}
+	if len(loaded.AcceptablePatternsMap) != len(ll.AcceptablePatternsMap) {
+		t.Errorf("ReadCompiled() has %v acceptable patterns, want %v", len(loaded.AcceptablePatternsMap), len(ll.AcceptablePatternsMap))
+	}
+
+	otherCustom := configurer.NewDefaultFlags()
+	_ = otherCustom.Set(configurer.ConfigPathFlag, "../testdata/config/")
+	_ = otherCustom.Set(configurer.CustomFlag, "other")
+	otherConfig, err := configurer.InitConfig(otherCustom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, tt := range map[string]struct {
+		config *LicenseLibrary
+		input  []byte
+		want   string
+	}{
+		"other custom resources": {config: mustLibrary(t, otherConfig), input: compiled.Bytes(), want: "--custom other"},
+		"not compiled":           {config: mustLibrary(t, config), input: []byte("{}"), want: "not a compiled license library"},
+		"truncated":              {config: mustLibrary(t, config), input: compiled.Bytes()[:compiled.Len()/2], want: "cannot decode"},
+	} {
+		if err := tt.config.ReadCompiled(bytes.NewReader(tt.input)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ReadCompiled() %v error = %v, want %q", name, err, tt.want)
+		}
+	}
+}
+
+func mustLibrary(t *testing.T, config *viper.Viper) *LicenseLibrary {
+	t.Helper()
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	return ll
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/IBM/license-scanner/cachedir"
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// addAllCached adds the licenses from the compiled library of the resources in the compiled cache of the user
+// cache directory, or else from the resources, writing their compiled library to the cache for the next scans.
+// The compiled libraries are by a fingerprint of the resources, so changed resources are compiled again.
+func (ll *LicenseLibrary) addAllCached() error {
+	dir, err := cachedir.Dir(cachedir.Compiled)
+	if err != nil {
+		Logger.Infof("Cannot cache the compiled library: %v", err)
+		return ll.AddAllResources()
+	}
+	key, err := ll.resourcesFingerprint()
+	if err != nil {
+		return err
+	}
+	cached := filepath.Join(dir, key+".bin")
+	err = ll.LoadCompiled(cached)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		Logger.Infof("Compiling the license library to %v (once for these resources)", dir)
+	} else {
+		Logger.Infof("Compiling the license library again: %v", err)
+	}
+	if err := ll.AddAllResources(); err != nil {
+		return err
+	}
+	if err := ll.writeCompiledFile(cached); err != nil {
+		Logger.Infof("Cannot cache the compiled library: %v", err)
+	}
+	return nil
+}
+
+// resourcesFingerprint returns a hash of the compiled library version, the --spdx and --custom config with the
+// paths, sizes, and modification times of their resource files, the --maxVariableLength, and the
+// --customNamespace and --customCollision
+func (ll *LicenseLibrary) resourcesFingerprint() (string, error) {
+	h := sha256.New()
+	spdx, custom := ll.Config.GetString(configurer.SpdxFlag), ll.Config.GetString(configurer.CustomFlag)
+	fmt.Fprintf(h, "%v\x00%v\x00%v\x00%v\n", CompiledLibraryVersion, spdx, custom, ll.MaxVariableLength())
+	fmt.Fprintf(h, "%v\x00%v\n", ll.Config.GetString(configurer.CustomNamespaceFlag), ll.Config.GetString(configurer.CustomCollisionFlag))
+	resources := ll.Config.GetString(Resources)
+	for _, dir := range []string{filepath.Join(resources, "spdx", spdx), filepath.Join(resources, "custom", custom)} {
+		err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+			if err != nil || !d.Type().IsRegular() {
+				return err
+			}
+			fi, err := d.Info()
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(h, "%v\x00%v\x00%v\n", filepath.ToSlash(p), fi.Size(), fi.ModTime().UnixNano())
+			return err
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeCompiledFile writes the compiled library file (replacing it only when complete)
+func (ll *LicenseLibrary) writeCompiledFile(filePath string) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o700); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := ll.WriteCompiled(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), filePath)
+}
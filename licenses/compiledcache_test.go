@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_AddAll_cache(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	newConfig := func(flags map[string]string) *viper.Viper {
+		t.Helper()
+		flagSet := configurer.NewDefaultFlags()
+		flags[configurer.ConfigPathFlag] = "../testdata/config/"
+		flags[configurer.CacheFlag] = "true"
+		for flag, value := range flags {
+			if err := flagSet.Set(flag, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		config, err := configurer.InitConfig(flagSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return config
+	}
+	addAll := func(config *viper.Viper) *LicenseLibrary {
+		t.Helper()
+		ll, err := NewLicenseLibrary(config)
+		if err != nil {
+			t.Fatalf("NewLicenseLibrary() error = %v", err)
+		}
+		if err := ll.AddAll(); err != nil {
+			t.Fatalf("AddAll() error = %v", err)
+		}
+		return ll
+	}
+
+	// The first scan compiles the library of the resources to the cache
+	config := newConfig(map[string]string{})
+	ll := addAll(config)
+	key, err := ll.resourcesFingerprint()
+	if err != nil {
+		t.Fatalf("resourcesFingerprint() error = %v", err)
+	}
+	cached := filepath.Join(cacheHome, "license-scanner", "compiled", key+".bin")
+	if _, err := os.Stat(cached); err != nil {
+		t.Fatalf("the compiled library was not cached: %v", err)
+	}
+
+	// The next scans load the cached library (here, without one of the licenses)
+	var removed string
+	for id := range ll.LicenseMap {
+		removed = id
+		break
+	}
+	delete(ll.LicenseMap, removed)
+	if err := ll.writeCompiledFile(cached); err != nil {
+		t.Fatal(err)
+	}
+	if got := addAll(config); len(got.LicenseMap) != len(ll.LicenseMap) {
+		t.Errorf("AddAll() with the cache has %v licenses, want the %v licenses of the cached library", len(got.LicenseMap), len(ll.LicenseMap))
+	}
+
+	// A corrupt cached library is compiled again
+	if err := os.WriteFile(cached, []byte("corrupt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if got := addAll(config); len(got.LicenseMap) != len(ll.LicenseMap)+1 {
+		t.Errorf("AddAll() with a corrupt cache has %v licenses, want %v", len(got.LicenseMap), len(ll.LicenseMap)+1)
+	}
+	if err := emptyLibrary(t, config).LoadCompiled(cached); err != nil {
+		t.Errorf("the compiled library was not cached again: %v", err)
+	}
+
+	// Another --maxVariableLength is another compiled library
+	other, err := addAll(newConfig(map[string]string{configurer.MaxVariableLengthFlag: "100"})).resourcesFingerprint()
+	if err != nil || other == key {
+		t.Errorf("resourcesFingerprint() with another --maxVariableLength = %v, %v, want another fingerprint than %v", other, err, key)
+	}
+}
+
+// emptyLibrary returns an empty library of the config
+func emptyLibrary(t *testing.T, config *viper.Viper) *LicenseLibrary {
+	t.Helper()
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ll
+}
@@ -0,0 +1,140 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// The kinds of files of the SPDX resources, by directory
+const (
+	TemplateFiles = template
+	PreCheckFiles = precheck
+	TestdataFiles = "testdata"
+	HeaderFiles   = header
+)
+
+// coverageSuffixes are the file name suffixes of each kind of file (the name is the ID, with a deprecated_
+// prefix for the deprecated IDs)
+var coverageSuffixes = map[string]string{
+	TemplateFiles: ".template.txt",
+	PreCheckFiles: ".json",
+	TestdataFiles: ".txt",
+	HeaderFiles:   ".template.txt",
+}
+
+// CoverageGap is an ID of the license list without a file of a kind, or a file without an ID
+type CoverageGap struct {
+	// ID is the license or exception ID (of the file name, for a file without an ID)
+	ID string `json:"id"`
+	// Kind is the kind of file (TemplateFiles, PreCheckFiles, TestdataFiles, or HeaderFiles)
+	Kind string `json:"kind"`
+	// File is the missing file, or the file without an ID
+	File string `json:"file"`
+}
+
+// Coverage is how the files of the SPDX resources cover the IDs of the license list
+type Coverage struct {
+	// SPDX is the directory of the SPDX resources (the --spdx)
+	SPDX               string `json:"spdx"`
+	LicenseListVersion string `json:"licenseListVersion"`
+	// IDs is the number of licenses and exceptions of the license list
+	IDs int `json:"ids"`
+	// Missing are the IDs without a template, precheck, or testdata file (sorted by kind and ID)
+	Missing []CoverageGap `json:"missing"`
+	// Orphans are the template, precheck, testdata, and header files without an ID (sorted by kind and ID)
+	Orphans []CoverageGap `json:"orphans"`
+}
+
+// Complete is true when every ID has its files, and every file has an ID
+func (c Coverage) Complete() bool {
+	return len(c.Missing) == 0 && len(c.Orphans) == 0
+}
+
+// CheckCoverage compares the IDs of the license list (licenses.json and exceptions.json) of the SPDX
+// resources (of --spdx) with their template, precheck, and testdata files, e.g., to find the gaps left by
+// an import. The header files are optional, so only the header files without an ID are gaps.
+func CheckCoverage(cfg *viper.Viper) (Coverage, error) {
+	spdxPath := filepath.Join(cfg.GetString(Resources), "spdx", cfg.GetString(SPDX))
+	c := Coverage{SPDX: cfg.GetString(SPDX), Missing: []CoverageGap{}, Orphans: []CoverageGap{}}
+
+	names := make(map[string]string) // the file names (without the suffix) of the IDs
+	for _, f := range []string{"licenses.json", "exceptions.json"} {
+		b, err := os.ReadFile(filepath.Join(spdxPath, jsonDir, f))
+		if err != nil {
+			return c, err
+		}
+		list, err := ReadSPDXLicenseListJSON(b)
+		if err != nil {
+			return c, fmt.Errorf("cannot read %v: %w", f, err)
+		}
+		if c.LicenseListVersion == "" {
+			c.LicenseListVersion = list.LicenseListVersion
+		}
+		for _, l := range list.Licenses {
+			names[coverageName(l.LicenseID, l.IsDeprecatedLicenseID)] = l.LicenseID
+		}
+		for _, e := range list.Exceptions {
+			names[coverageName(e.LicenseExceptionID, e.IsDeprecatedLicenseID)] = e.LicenseExceptionID
+		}
+	}
+	c.IDs = len(names)
+
+	for _, kind := range []string{TemplateFiles, PreCheckFiles, TestdataFiles, HeaderFiles} {
+		dir := filepath.Join(spdxPath, kind)
+		suffix := coverageSuffixes[kind]
+		files := make(map[string]bool)
+		des, err := os.ReadDir(dir)
+		if err != nil && !(os.IsNotExist(err) && kind == HeaderFiles) {
+			return c, err
+		}
+		for _, de := range des {
+			if de.IsDir() || !strings.HasSuffix(de.Name(), suffix) {
+				continue
+			}
+			name := strings.TrimSuffix(de.Name(), suffix)
+			files[name] = true
+			if _, ok := names[name]; !ok {
+				c.Orphans = append(c.Orphans, CoverageGap{ID: strings.TrimPrefix(name, deprecatedPrefix), Kind: kind, File: filepath.Join(dir, de.Name())})
+			}
+		}
+		if kind == HeaderFiles {
+			continue
+		}
+		for name, id := range names {
+			if !files[name] {
+				c.Missing = append(c.Missing, CoverageGap{ID: id, Kind: kind, File: filepath.Join(dir, name+suffix)})
+			}
+		}
+	}
+	sortGaps(c.Missing)
+	sortGaps(c.Orphans)
+	return c, nil
+}
+
+// deprecatedPrefix is the file name prefix of the deprecated IDs
+const deprecatedPrefix = "deprecated_"
+
+// coverageName returns the file name (without the suffix) of an ID
+func coverageName(id string, deprecated bool) string {
+	if deprecated {
+		return deprecatedPrefix + id
+	}
+	return id
+}
+
+// sortGaps sorts the gaps by kind and ID
+func sortGaps(gaps []CoverageGap) {
+	sort.Slice(gaps, func(i, j int) bool {
+		if gaps[i].Kind != gaps[j].Kind {
+			return gaps[i].Kind < gaps[j].Kind
+		}
+		return gaps[i].ID < gaps[j].ID
+	})
+}
@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+)
+
+func TestCheckCoverage(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	spdxPath := filepath.Join(resources, "spdx", "test")
+	for f, content := range map[string]string{
+		"json/licenses.json": `{"licenseListVersion": "3.21", "licenses": [
+			{"licenseId": "MIT"}, {"licenseId": "GPL-2.0", "isDeprecatedLicenseId": true}, {"licenseId": "0BSD"}]}`,
+		"json/exceptions.json":                     `{"licenseListVersion": "3.21", "exceptions": [{"licenseExceptionId": "LLVM-exception"}]}`,
+		"template/MIT.template.txt":                "MIT",
+		"template/deprecated_GPL-2.0.template.txt": "GPL",
+		"template/LLVM-exception.template.txt":     "LLVM",
+		"template/Removed.template.txt":            "removed",
+		"template/README.md":                       "not a template",
+		"precheck/MIT.json":                        "{}",
+		"precheck/GPL-2.0.json":                    "{}",
+		"precheck/0BSD.json":                       "{}",
+		"precheck/LLVM-exception.json":             "{}",
+		"testdata/MIT.txt":                         "MIT",
+		"testdata/deprecated_GPL-2.0.txt":          "GPL",
+		"testdata/0BSD.txt":                        "0BSD",
+		"testdata/LLVM-exception.txt":              "LLVM",
+		"header/deprecated_GPL-2.0.template.txt":   "GPL header",
+		"header/deprecated_Old.template.txt":       "old header",
+	} {
+		p := filepath.Join(spdxPath, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := viper.New()
+	cfg.Set(Resources, resources)
+	cfg.Set(SPDX, "test")
+	got, err := CheckCoverage(cfg)
+	if err != nil {
+		t.Fatalf("CheckCoverage() error = %v", err)
+	}
+	want := Coverage{
+		SPDX:               "test",
+		LicenseListVersion: "3.21",
+		IDs:                4,
+		Missing: []CoverageGap{
+			{ID: "GPL-2.0", Kind: PreCheckFiles, File: filepath.Join(spdxPath, precheck, "deprecated_GPL-2.0.json")},
+			{ID: "0BSD", Kind: TemplateFiles, File: filepath.Join(spdxPath, template, "0BSD.template.txt")},
+		},
+		Orphans: []CoverageGap{
+			{ID: "Old", Kind: HeaderFiles, File: filepath.Join(spdxPath, header, "deprecated_Old.template.txt")},
+			{ID: "GPL-2.0", Kind: PreCheckFiles, File: filepath.Join(spdxPath, precheck, "GPL-2.0.json")},
+			{ID: "Removed", Kind: TemplateFiles, File: filepath.Join(spdxPath, template, "Removed.template.txt")},
+		},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("CheckCoverage() mismatch (-want +got):\n%s", d)
+	}
+	if got.Complete() {
+		t.Error("Complete() = true, want false")
+	}
+
+	cfg.Set(SPDX, "missing")
+	if _, err := CheckCoverage(cfg); err == nil {
+		t.Error("CheckCoverage() of missing resources expected an error")
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"regexp"
+	"strings"
+)
+
+// gnuDeprecatedRE matches the deprecated GNU IDs without the -only or -or-later suffix (e.g., GPL-2.0, LGPL-2.1+)
+var gnuDeprecatedRE = regexp.MustCompile(`(?i)^((?:A|L)?GPL|GFDL)-(\d\.\d)(\+)?$`)
+
+// deprecatedReplacements are the current expressions for the deprecated IDs which do not follow the GNU rule
+var deprecatedReplacements = map[string]string{
+	"BSD-2-Clause-FreeBSD":             "BSD-2-Clause",
+	"BSD-2-Clause-NetBSD":              "BSD-2-Clause",
+	"bzip2-1.0.5":                      "bzip2-1.0.6",
+	"eCos-2.0":                         "GPL-2.0-or-later WITH eCos-exception-2.0",
+	"GPL-2.0-with-autoconf-exception":  "GPL-2.0-only WITH Autoconf-exception-2.0",
+	"GPL-2.0-with-bison-exception":     "GPL-2.0-or-later WITH Bison-exception-2.2",
+	"GPL-2.0-with-classpath-exception": "GPL-2.0-only WITH Classpath-exception-2.0",
+	"GPL-2.0-with-font-exception":      "GPL-2.0-only WITH Font-exception-2.0",
+	"GPL-2.0-with-GCC-exception":       "GPL-2.0-only WITH GCC-exception-2.0",
+	"GPL-3.0-with-autoconf-exception":  "GPL-3.0-only WITH Autoconf-exception-3.0",
+	"GPL-3.0-with-GCC-exception":       "GPL-3.0-only WITH GCC-exception-3.1",
+	"Nokia-Qt-exception-1.1":           "Qt-LGPL-exception-1.1",
+	"Nunit":                            "zlib-acknowledgement",
+	"StandardML-NJ":                    "SMLNJ",
+	"wxWindows":                        "LGPL-2.0-or-later WITH WxWindows-exception-3.1",
+}
+
+// lowerDeprecatedReplacements are the deprecatedReplacements by the lowercase IDs, since the SPDX IDs are case-insensitive
+var lowerDeprecatedReplacements = func() map[string]string {
+	m := make(map[string]string, len(deprecatedReplacements))
+	for id, r := range deprecatedReplacements {
+		m[strings.ToLower(id)] = r
+	}
+	return m
+}()
+
+// DeprecatedReplacement returns the current SPDX ID or expression to use instead of a deprecated ID (in any case).
+// The GNU IDs get the -only or -or-later (for +) suffix (e.g., GPL-2.0+ is GPL-2.0-or-later).
+func DeprecatedReplacement(id string) (string, bool) {
+	if r, ok := lowerDeprecatedReplacements[strings.ToLower(id)]; ok {
+		return r, true
+	}
+	if m := gnuDeprecatedRE.FindStringSubmatch(id); m != nil {
+		if m[3] == "+" {
+			return strings.ToUpper(m[1]) + "-" + m[2] + "-or-later", true
+		}
+		return strings.ToUpper(m[1]) + "-" + m[2] + "-only", true
+	}
+	return "", false
+}
+
+// Replacement returns the current SPDX expression for a license ID which is deprecated in the
+// license list (licenses.json or exceptions.json). It returns false for the current IDs.
+func (ll *LicenseLibrary) Replacement(id string) (string, bool) {
+	if !ll.LicenseMap[id].LicenseInfo.IsDeprecated {
+		return "", false
+	}
+	return DeprecatedReplacement(id)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedReplacement(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		id   string
+		want string
+		ok   bool
+	}{
+		{id: "GPL-2.0", want: "GPL-2.0-only", ok: true},
+		{id: "GPL-2.0+", want: "GPL-2.0-or-later", ok: true},
+		{id: "LGPL-2.1+", want: "LGPL-2.1-or-later", ok: true},
+		{id: "AGPL-3.0", want: "AGPL-3.0-only", ok: true},
+		{id: "GFDL-1.3", want: "GFDL-1.3-only", ok: true},
+		{id: "GPL-2.0-with-classpath-exception", want: "GPL-2.0-only WITH Classpath-exception-2.0", ok: true},
+		{id: "StandardML-NJ", want: "SMLNJ", ok: true},
+		{id: "gpl-2.0+", want: "GPL-2.0-or-later", ok: true},
+		{id: "standardml-nj", want: "SMLNJ", ok: true},
+		{id: "GPL-2.0-only"},
+		{id: "MIT"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			got, ok := DeprecatedReplacement(tt.id)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("DeprecatedReplacement(%v) = %q, %v, want %q, %v", tt.id, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_Replacement(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+
+	// Every deprecated ID has a replacement made of current IDs
+	for id, l := range ll.LicenseMap {
+		if !l.LicenseInfo.IsDeprecated {
+			if _, ok := ll.Replacement(id); ok {
+				t.Errorf("Replacement(%v) is not deprecated", id)
+			}
+			continue
+		}
+		replacement, ok := ll.Replacement(id)
+		if !ok {
+			t.Errorf("Replacement(%v) not found", id)
+			continue
+		}
+		for _, token := range strings.Fields(replacement) {
+			if token == "WITH" {
+				continue
+			}
+			if current, ok := ll.LicenseMap[token]; !ok || current.LicenseInfo.IsDeprecated {
+				t.Errorf("Replacement(%v) = %v, but %v is not a current ID", id, replacement, token)
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Entry is a license ID of the library, with its metadata
+type Entry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Family   string `json:"family,omitempty"`
+	Category string `json:"category,omitempty"`
+	// Exception is true for a license exception (e.g., Classpath-exception-2.0)
+	Exception bool `json:"exception"`
+	// Deprecated is true for a deprecated SPDX ID (e.g., GPL-2.0)
+	Deprecated bool `json:"deprecated"`
+	// Custom is true for a license of the custom resources which is not in the SPDX license list
+	Custom      bool `json:"custom"`
+	OSIApproved bool `json:"osiApproved"`
+	FSFLibre    bool `json:"fsfLibre"`
+	// Templates is the number of primary patterns (SPDX templates and custom license patterns)
+	Templates int `json:"templates"`
+}
+
+// EntryFilter selects the entries of the library (all of them when it is empty)
+type EntryFilter struct {
+	// CustomOnly selects the custom licenses (not in the SPDX license list)
+	CustomOnly bool
+	// Deprecated selects the deprecated IDs
+	Deprecated bool
+	// Families are patterns (with wildcards like GPL*, case-insensitive) which the family must match (any of them)
+	Families []string
+}
+
+// Entries returns the license IDs of the library which the filter selects, sorted by ID. The family and
+// category are the classification of the license (see Classify).
+func (ll *LicenseLibrary) Entries(filter EntryFilter) ([]Entry, error) {
+	families := splitPatterns(filter.Families)
+	for _, p := range families {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid family pattern %q: %w", p, err)
+		}
+	}
+	ret := []Entry{}
+	for id := range ll.LicenseMap {
+		e := ll.entry(id)
+		if filter.CustomOnly && !e.Custom || filter.Deprecated && !e.Deprecated || len(families) > 0 && !matchFamily(families, e.Family) {
+			continue
+		}
+		ret = append(ret, e)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
+
+// entry returns the entry of a license ID of the library
+func (ll *LicenseLibrary) entry(id string) Entry {
+	l := ll.LicenseMap[id]
+	info := l.LicenseInfo
+	classification := ll.Classify(id)
+	return Entry{
+		ID:          id,
+		Name:        info.Name,
+		Family:      classification.Family,
+		Category:    classification.Category,
+		Exception:   info.SPDXException,
+		Deprecated:  info.IsDeprecated,
+		Custom:      !info.SPDXStandard,
+		OSIApproved: info.OSIApproved,
+		FSFLibre:    info.IsFSFLibre,
+		Templates:   len(l.PrimaryPatterns),
+	}
+}
+
+// matchFamily returns true when the family matches any of the (valid) patterns, case-insensitive
+func matchFamily(patterns []string, family string) bool {
+	if family == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if matched, _ := path.Match(strings.ToLower(p), strings.ToLower(family)); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLicenseLibrary_Entries(t *testing.T) {
+	t.Parallel()
+	ll := &LicenseLibrary{
+		LicenseMap: LicenseMap{
+			"GPL-2.0-only":            {LicenseInfo: LicenseInfo{Name: "GNU General Public License v2.0 only", SPDXStandard: true, OSIApproved: true, IsFSFLibre: true}, PrimaryPatterns: []*PrimaryPatterns{{}}},
+			"GPL-2.0":                 {LicenseInfo: LicenseInfo{Name: "GNU General Public License v2.0 only", SPDXStandard: true, IsDeprecated: true}},
+			"LGPL-2.1-only":           {LicenseInfo: LicenseInfo{Name: "GNU Lesser General Public License v2.1 only", SPDXStandard: true}},
+			"Classpath-exception-2.0": {LicenseInfo: LicenseInfo{Name: "Classpath exception 2.0", SPDXStandard: true, SPDXException: true}},
+			"Acme-Internal":           {LicenseInfo: LicenseInfo{Name: "Acme Internal License", Family: "Acme", Category: "proprietary"}, PrimaryPatterns: []*PrimaryPatterns{{}, {}}},
+		},
+	}
+	tests := []struct {
+		name    string
+		filter  EntryFilter
+		want    []string
+		wantErr bool
+	}{
+		{name: "all", want: []string{"Acme-Internal", "Classpath-exception-2.0", "GPL-2.0", "GPL-2.0-only", "LGPL-2.1-only"}},
+		{name: "custom only", filter: EntryFilter{CustomOnly: true}, want: []string{"Acme-Internal"}},
+		{name: "deprecated", filter: EntryFilter{Deprecated: true}, want: []string{"GPL-2.0"}},
+		{name: "family", filter: EntryFilter{Families: []string{"GPL*"}}, want: []string{"GPL-2.0", "GPL-2.0-only"}},
+		{name: "families", filter: EntryFilter{Families: []string{"gpl,acme"}}, want: []string{"Acme-Internal", "GPL-2.0", "GPL-2.0-only"}},
+		{name: "family and deprecated", filter: EntryFilter{Families: []string{"*GPL"}, Deprecated: true}, want: []string{"GPL-2.0"}},
+		{name: "none", filter: EntryFilter{CustomOnly: true, Deprecated: true}, want: []string{}},
+		{name: "invalid family", filter: EntryFilter{Families: []string{"["}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			entries, err := ll.Entries(tt.filter)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Entries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			ids := []string{}
+			for _, e := range entries {
+				ids = append(ids, e.ID)
+			}
+			if d := cmp.Diff(tt.want, ids); d != "" {
+				t.Errorf("Entries() IDs mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_Entries_metadata(t *testing.T) {
+	t.Parallel()
+	ll := &LicenseLibrary{
+		LicenseMap: LicenseMap{
+			"GPL-2.0-only":  {LicenseInfo: LicenseInfo{Name: "GNU General Public License v2.0 only", SPDXStandard: true, OSIApproved: true, IsFSFLibre: true}, PrimaryPatterns: []*PrimaryPatterns{{}}},
+			"Acme-Internal": {LicenseInfo: LicenseInfo{Name: "Acme Internal License", Family: "Acme", Category: "proprietary"}},
+		},
+	}
+	entries, err := ll.Entries(EntryFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Entry{
+		{ID: "Acme-Internal", Name: "Acme Internal License", Family: "Acme", Category: "proprietary", Custom: true},
+		{ID: "GPL-2.0-only", Name: "GNU General Public License v2.0 only", Family: "GPL", Category: StrongCopyleft, OSIApproved: true, FSFLibre: true, Templates: 1},
+	}
+	if d := cmp.Diff(want, entries); d != "" {
+		t.Errorf("Entries() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,316 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// The SPDX expression operators
+const (
+	And  = "AND"
+	Or   = "OR"
+	With = "WITH"
+)
+
+// expressionTokenRE matches the parentheses and the words (IDs and operators) of an SPDX expression
+var expressionTokenRE = regexp.MustCompile(`\(|\)|[^\s()]+`)
+
+// expressionIDRE matches a license or exception ID (including LicenseRef-, DocumentRef-...:LicenseRef-, and the + suffix)
+var expressionIDRE = regexp.MustCompile(`^[A-Za-z0-9.\-:]+\+?$`)
+
+// Expression is a parsed SPDX license expression. It is either a license (with an optional exception)
+// or an AND or OR of two or more expressions.
+type Expression struct {
+	// Operator is And or Or for a compound expression, and "" for a license
+	Operator string
+	// Operands are the expressions combined by the Operator
+	Operands []*Expression
+	// License is the license ID (with a + suffix for "or later")
+	License string
+	// Exception is the exception ID of a license WITH an exception
+	Exception string
+}
+
+// ParseExpression parses an SPDX license expression. The operators are case-insensitive. WITH takes
+// precedence over AND, and AND over OR.
+func ParseExpression(s string) (*Expression, error) {
+	p := &expressionParser{tokens: expressionTokenRE.FindAllString(s, -1)}
+	if len(p.tokens) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression %q: %w", s, err)
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression %q: unexpected %q", s, p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+// String returns the expression with the nested AND and OR expressions in parentheses
+func (e *Expression) String() string {
+	if e.Operator == "" {
+		if e.Exception != "" {
+			return e.License + " " + With + " " + e.Exception
+		}
+		return e.License
+	}
+	parts := make([]string, len(e.Operands))
+	for i, o := range e.Operands {
+		parts[i] = o.String()
+		if o.Operator != "" {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, " "+e.Operator+" ")
+}
+
+// Licenses returns the license IDs in the expression (with their exceptions), in order
+func (e *Expression) Licenses() []*Expression {
+	if e.Operator == "" {
+		return []*Expression{e}
+	}
+	var ret []*Expression
+	for _, o := range e.Operands {
+		ret = append(ret, o.Licenses()...)
+	}
+	return ret
+}
+
+// Allowed evaluates the expression with a policy: an OR is a choice (allowed when any operand is allowed),
+// an AND is a conjunction (allowed when every operand is allowed), and a license is allowed when the policy
+// allows it with its exception (if any), so the policy can allow an exception which lifts a restriction
+// (e.g., GPL-2.0-only WITH Classpath-exception-2.0) without allowing the license alone.
+func (e *Expression) Allowed(allowed func(license *Expression) bool) bool {
+	return len(e.Violations(allowed)) == 0
+}
+
+// Violations returns the licenses which make the expression not allowed by the policy (none when it is
+// allowed). An OR is only violated when every operand is, and then the violations of all of them are returned.
+func (e *Expression) Violations(allowed func(license *Expression) bool) []*Expression {
+	switch e.Operator {
+	case "":
+		if allowed(e) {
+			return nil
+		}
+		return []*Expression{e}
+	case Or:
+		var ret []*Expression
+		for _, o := range e.Operands {
+			violations := o.Violations(allowed)
+			if len(violations) == 0 {
+				return nil // the allowed choice
+			}
+			ret = append(ret, violations...)
+		}
+		return ret
+	default:
+		var ret []*Expression
+		for _, o := range e.Operands {
+			ret = append(ret, o.Violations(allowed)...)
+		}
+		return ret
+	}
+}
+
+// SimplifyExpression normalizes and minimizes an SPDX license expression for consistent reporting and
+// policy evaluation: the operators are upper case, the deprecated IDs are replaced (e.g., GPL-2.0+ is
+// GPL-2.0-or-later), nested ANDs and ORs are flattened, the duplicate operands are removed (A OR A is A),
+// absorbed operands are removed (A OR (A AND B) is A, and A AND (A OR B) is A), and the operands are sorted
+// (the licenses before the nested expressions).
+func SimplifyExpression(s string) (string, error) {
+	e, err := ParseExpression(s)
+	if err != nil {
+		return "", err
+	}
+	e, err = replaceDeprecated(e)
+	if err != nil {
+		return "", err
+	}
+	return simplify(e).String(), nil
+}
+
+// replaceDeprecated replaces the deprecated license and exception IDs with the current expressions
+func replaceDeprecated(e *Expression) (*Expression, error) {
+	if e.Operator != "" {
+		ret := &Expression{Operator: e.Operator}
+		for _, o := range e.Operands {
+			r, err := replaceDeprecated(o)
+			if err != nil {
+				return nil, err
+			}
+			ret.Operands = append(ret.Operands, r)
+		}
+		return ret, nil
+	}
+	if e.Exception != "" {
+		if r, ok := DeprecatedReplacement(e.Exception); ok && !strings.Contains(r, " ") {
+			e = &Expression{License: e.License, Exception: r}
+		}
+	}
+	r, ok := DeprecatedReplacement(e.License)
+	if !ok {
+		return e, nil
+	}
+	replacement, err := ParseExpression(r)
+	if err != nil {
+		return nil, err
+	}
+	if e.Exception != "" && replacement.Operator == "" && replacement.Exception == "" {
+		replacement.Exception = e.Exception
+	}
+	return replacement, nil
+}
+
+// simplify flattens, deduplicates, absorbs, and sorts the operands of the expression
+func simplify(e *Expression) *Expression {
+	if e.Operator == "" {
+		return e
+	}
+	var operands []*Expression
+	for _, o := range e.Operands {
+		o = simplify(o)
+		if o.Operator == e.Operator {
+			operands = append(operands, o.Operands...) // (A AND B) AND C is A AND B AND C
+		} else {
+			operands = append(operands, o)
+		}
+	}
+
+	// A OR A is A
+	seen := make(map[string]bool)
+	var unique []*Expression
+	for _, o := range operands {
+		if key := o.key(); !seen[key] {
+			seen[key] = true
+			unique = append(unique, o)
+		}
+	}
+
+	// A OR (A AND B) is A, and A AND (A OR B) is A
+	var kept []*Expression
+	for _, o := range unique {
+		if !o.absorbedBy(unique) {
+			kept = append(kept, o)
+		}
+	}
+
+	if len(kept) == 1 {
+		return kept[0]
+	}
+	sort.SliceStable(kept, func(i, j int) bool {
+		if (kept[i].Operator == "") != (kept[j].Operator == "") {
+			return kept[i].Operator == "" // the licenses before the nested expressions
+		}
+		return kept[i].key() < kept[j].key()
+	})
+	return &Expression{Operator: e.Operator, Operands: kept}
+}
+
+// absorbedBy is true when one of the other operands is also an operand of this nested expression
+func (e *Expression) absorbedBy(operands []*Expression) bool {
+	if e.Operator == "" {
+		return false
+	}
+	for _, other := range operands {
+		if other == e {
+			continue
+		}
+		for _, o := range e.Operands {
+			if o.key() == other.key() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key compares the expressions (the license IDs are case-insensitive)
+func (e *Expression) key() string {
+	return strings.ToLower(e.String())
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the next token (the operators in upper case), or "" at the end
+func (p *expressionParser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	t := p.tokens[p.pos]
+	if upper := strings.ToUpper(t); upper == And || upper == Or || upper == With {
+		return upper
+	}
+	return t
+}
+
+func (p *expressionParser) parseOr() (*Expression, error) {
+	return p.parseCompound(Or, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd() (*Expression, error) {
+	return p.parseCompound(And, p.parseWith)
+}
+
+// parseCompound parses the operands combined with the operator
+func (p *expressionParser) parseCompound(operator string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	e, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{e}
+	for p.next() == operator {
+		p.pos++
+		e, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, e)
+	}
+	if len(operands) == 1 {
+		return operands[0], nil
+	}
+	return &Expression{Operator: operator, Operands: operands}, nil
+}
+
+// parseWith parses a license with an optional exception, or an expression in parentheses
+func (p *expressionParser) parseWith() (*Expression, error) {
+	t := p.next()
+	switch {
+	case t == "":
+		return nil, fmt.Errorf("missing license ID at the end")
+	case t == "(":
+		p.pos++
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.pos++
+		return e, nil
+	case t == ")" || t == And || t == Or || t == With || !expressionIDRE.MatchString(t):
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	p.pos++
+	e := &Expression{License: t}
+	if p.next() == With {
+		p.pos++
+		exception := p.next()
+		if exception == "" || exception == "(" || exception == ")" || exception == And || exception == Or || exception == With || !expressionIDRE.MatchString(exception) {
+			return nil, fmt.Errorf("missing exception ID after %v %v", t, With)
+		}
+		p.pos++
+		e.Exception = exception
+	}
+	return e, nil
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+)
+
+func TestSimplifyExpression(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expression string
+		want       string
+		wantErr    bool
+	}{
+		{expression: "MIT", want: "MIT"},
+		{expression: "  mit  ", want: "mit"},
+		{expression: "MIT or Apache-2.0", want: "Apache-2.0 OR MIT"},
+		{expression: "MIT OR MIT", want: "MIT"},
+		{expression: "MIT OR mit", want: "MIT"},
+		{expression: "MIT AND (Apache-2.0 AND MIT)", want: "Apache-2.0 AND MIT"},
+		{expression: "(MIT OR BSD-3-Clause) OR ISC", want: "BSD-3-Clause OR ISC OR MIT"},
+		{expression: "MIT OR (MIT AND Apache-2.0)", want: "MIT"},
+		{expression: "MIT AND (MIT OR Apache-2.0)", want: "MIT"},
+		{expression: "(MIT AND Apache-2.0) OR ISC", want: "ISC OR (Apache-2.0 AND MIT)"},
+		{expression: "MIT AND Apache-2.0 OR ISC", want: "ISC OR (Apache-2.0 AND MIT)"},
+		{expression: "GPL-2.0+", want: "GPL-2.0-or-later"},
+		{expression: "GPL-2.0", want: "GPL-2.0-only"},
+		{expression: "LGPL-2.1+ OR LGPL-2.1-or-later", want: "LGPL-2.1-or-later"},
+		{expression: "gpl-2.0+ OR GPL-2.0-or-later", want: "GPL-2.0-or-later"},
+		{expression: "gpl-2.0-with-classpath-exception", want: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{expression: "Apache-2.0+", want: "Apache-2.0+"},
+		{expression: "GPL-2.0 with Classpath-exception-2.0", want: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{expression: "GPL-2.0-with-classpath-exception", want: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{expression: "(GPL-2.0-only WITH Classpath-exception-2.0) OR GPL-2.0-only", want: "GPL-2.0-only OR GPL-2.0-only WITH Classpath-exception-2.0"},
+		{expression: "LicenseRef-Acme AND DocumentRef-spdx:LicenseRef-Other", want: "DocumentRef-spdx:LicenseRef-Other AND LicenseRef-Acme"},
+		{expression: "", wantErr: true},
+		{expression: "MIT OR", wantErr: true},
+		{expression: "(MIT", wantErr: true},
+		{expression: "MIT)", wantErr: true},
+		{expression: "MIT Apache-2.0", wantErr: true},
+		{expression: "MIT WITH", wantErr: true},
+		{expression: "(MIT OR ISC) WITH Classpath-exception-2.0", wantErr: true},
+		{expression: "MIT/X11", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+			got, err := SimplifyExpression(tt.expression)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SimplifyExpression(%q) error = %v, wantErr %v", tt.expression, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SimplifyExpression(%q) = %q, want %q", tt.expression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+	e, err := ParseExpression("MIT OR (GPL-2.0-only WITH Classpath-exception-2.0 AND BSD-3-Clause)")
+	if err != nil {
+		t.Fatalf("ParseExpression() error = %v", err)
+	}
+	if e.Operator != Or || len(e.Operands) != 2 || e.Operands[1].Operator != And {
+		t.Fatalf("ParseExpression() = %v, want MIT OR an AND", e)
+	}
+	var got []string
+	for _, l := range e.Licenses() {
+		got = append(got, l.License+"|"+l.Exception)
+	}
+	want := []string{"MIT|", "GPL-2.0-only|Classpath-exception-2.0", "BSD-3-Clause|"}
+	if len(got) != len(want) {
+		t.Fatalf("Licenses() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Licenses()[%v] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExpression_Violations(t *testing.T) {
+	t.Parallel()
+	// The policy restricts the GPL, but allows it with the Classpath exception
+	allowed := func(l *Expression) bool {
+		if l.License == "GPL-2.0-only" {
+			return l.Exception == "Classpath-exception-2.0"
+		}
+		return l.License != "AGPL-3.0-only"
+	}
+	tests := []struct {
+		expression string
+		want       []string
+	}{
+		{expression: "MIT"},
+		{expression: "GPL-2.0-only", want: []string{"GPL-2.0-only"}},
+		{expression: "GPL-2.0-only OR MIT"},
+		{expression: "GPL-2.0-only AND MIT", want: []string{"GPL-2.0-only"}},
+		{expression: "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{expression: "GPL-2.0-only WITH GCC-exception-2.0", want: []string{"GPL-2.0-only WITH GCC-exception-2.0"}},
+		{expression: "GPL-2.0-only OR AGPL-3.0-only", want: []string{"GPL-2.0-only", "AGPL-3.0-only"}},
+		{expression: "MIT AND (GPL-2.0-only OR Apache-2.0)"},
+		{expression: "(MIT AND GPL-2.0-only) OR (ISC AND AGPL-3.0-only)", want: []string{"GPL-2.0-only", "AGPL-3.0-only"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+			e, err := ParseExpression(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) error = %v", tt.expression, err)
+			}
+			var got []string
+			for _, v := range e.Violations(allowed) {
+				got = append(got, v.String())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Violations(%q) = %v, want %v", tt.expression, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Violations(%q)[%v] = %v, want %v", tt.expression, i, got[i], tt.want[i])
+				}
+			}
+			if e.Allowed(allowed) != (len(tt.want) == 0) {
+				t.Errorf("Allowed(%q) = %v, want %v", tt.expression, e.Allowed(allowed), len(tt.want) == 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter removes the licenses which are not selected from the library, so that they are never matched.
+// With only patterns, just the matching license IDs are kept. Then, the license IDs matching an exclude
+// pattern are removed. Patterns may use wildcards (e.g., GPL-* or *-only) and may be comma-separated.
+// The IDs are compared case-insensitively.
+func (ll *LicenseLibrary) Filter(only []string, exclude []string) error {
+	only = splitPatterns(only)
+	exclude = splitPatterns(exclude)
+	if len(only) == 0 && len(exclude) == 0 {
+		return nil
+	}
+
+	for id := range ll.LicenseMap {
+		keep := true
+		if len(only) > 0 {
+			matched, err := matchAny(only, id)
+			if err != nil {
+				return err
+			}
+			keep = matched
+		}
+		if keep {
+			matched, err := matchAny(exclude, id)
+			if err != nil {
+				return err
+			}
+			keep = !matched
+		}
+		if !keep {
+			delete(ll.LicenseMap, id)
+		}
+	}
+
+	for hash, ids := range ll.ExactHashMap {
+		var kept []string
+		for _, id := range ids {
+			if _, ok := ll.LicenseMap[id]; ok {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == 0 {
+			delete(ll.ExactHashMap, hash)
+		} else {
+			ll.ExactHashMap[hash] = kept
+		}
+	}
+
+	if len(ll.LicenseMap) == 0 {
+		Logger.Infof("No licenses are selected by only %v and exclude %v", only, exclude)
+	}
+	return nil
+}
+
+// splitPatterns splits any comma-separated patterns (e.g., from an env var) and drops the empty ones
+func splitPatterns(patterns []string) []string {
+	var ret []string
+	for _, p := range patterns {
+		for _, s := range strings.Split(p, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				ret = append(ret, s)
+			}
+		}
+	}
+	return ret
+}
+
+func matchAny(patterns []string, id string) (bool, error) {
+	for _, p := range patterns {
+		// SPDX license IDs are case-insensitive
+		matched, err := path.Match(strings.ToLower(p), strings.ToLower(id))
+		if err != nil {
+			return false, fmt.Errorf("invalid license ID pattern %q: %w", p, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
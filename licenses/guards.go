@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// MatchGuardsJSON is the optional file (in the custom resources) with match guards which take precedence
+// over the DefaultMatchGuards
+const MatchGuardsJSON = "match_guards.json"
+
+// DefaultGuardWindow is how many characters before and after a match are searched for the guard context
+const DefaultGuardWindow = 500
+
+// MatchGuard has the requirements of a match of the license IDs matching the pattern (wildcards allowed,
+// case-insensitive), to reject the false positives of very short templates. The first matching guard wins.
+type MatchGuard struct {
+	Pattern string `json:"pattern"`
+	// MinLength is the fewest characters of matched text (0 for any)
+	MinLength int `json:"min_length,omitempty"`
+	// Context are regexps (case-insensitive), one of which must be found near the match (none for any), unless
+	// the match is the whole text
+	Context []string `json:"context,omitempty"`
+	// Window is how many characters before and after the match are searched for the context (DefaultGuardWindow when 0)
+	Window int `json:"window,omitempty"`
+}
+
+// DefaultMatchGuards are the built-in guards
+var DefaultMatchGuards = []MatchGuard{
+	// The two sentences of diffmark ("you can do what you want with it" and "I refuse any responsibility for
+	// the consequences") are also found in informal notices which are not the diffmark license
+	{Pattern: "diffmark", Context: []string{`\bdiffmark\b`}},
+}
+
+// MatchGuard returns the guard of a license (nil when the matches of the license are not guarded)
+func (ll *LicenseLibrary) MatchGuard(id string) *MatchGuard {
+	guards := ll.MatchGuards
+	if guards == nil {
+		guards = DefaultMatchGuards
+	}
+	for i, guard := range guards {
+		if matched, _ := matchAny([]string{guard.Pattern}, id); matched {
+			return &guards[i]
+		}
+	}
+	return nil
+}
+
+// Allows is true when the match of the text from begins to ends (inclusive) meets the guard
+func (g *MatchGuard) Allows(text string, begins int, ends int) bool {
+	if ends-begins+1 < g.MinLength {
+		return false
+	}
+	if len(g.Context) == 0 || strings.TrimSpace(text[:begins]) == "" && strings.TrimSpace(text[ends+1:]) == "" {
+		return true // the whole text is the license, not an excerpt of a larger text
+	}
+	window := g.Window
+	if window == 0 {
+		window = DefaultGuardWindow
+	}
+	from, to := begins-window, ends+1+window
+	if from < 0 {
+		from = 0
+	}
+	if to > len(text) {
+		to = len(text)
+	}
+	for _, context := range g.Context {
+		re, err := regexp.Compile(`(?i)` + context)
+		if err == nil && re.MatchString(text[from:to]) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate returns an error for an invalid pattern, context, or length
+func (g *MatchGuard) validate() error {
+	if _, err := matchAny([]string{g.Pattern}, ""); err != nil || strings.TrimSpace(g.Pattern) == "" {
+		return fmt.Errorf("invalid pattern %q", g.Pattern)
+	}
+	if g.MinLength < 0 || g.Window < 0 {
+		return fmt.Errorf("negative min_length or window for %q", g.Pattern)
+	}
+	for _, context := range g.Context {
+		if _, err := regexp.Compile(`(?i)` + context); err != nil {
+			return fmt.Errorf("invalid context of %q: %w", g.Pattern, err)
+		}
+	}
+	return nil
+}
+
+// addMatchGuards puts the guards from the custom match_guards.json (if any) before the default guards
+func (ll *LicenseLibrary) addMatchGuards() error {
+	f := filepath.Join(ll.resourcesDir(), customDir, ll.Config.GetString(configurer.CustomFlag), MatchGuardsJSON)
+	b, err := ll.resourceFiles().ReadFile(f)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var guards []MatchGuard
+	if err := json.Unmarshal(b, &guards); err != nil {
+		return fmt.Errorf("cannot unmarshal %v: %w", f, err)
+	}
+	for i := range guards {
+		if err := guards[i].validate(); err != nil {
+			return fmt.Errorf("invalid match guard in %v: %w", f, err)
+		}
+	}
+	ll.MatchGuards = append(guards, DefaultMatchGuards...)
+	return nil
+}
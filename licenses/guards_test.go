@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestMatchGuard_Allows(t *testing.T) {
+	t.Parallel()
+	text := "diffmark notice: 1. you can do what you want with it 2. I refuse any responsibility for the consequences"
+	tests := []struct {
+		name   string
+		guard  MatchGuard
+		begins int
+		ends   int
+		want   bool
+	}{
+		{name: "no requirements", guard: MatchGuard{Pattern: "*"}, begins: 17, ends: 103, want: true},
+		{name: "long enough", guard: MatchGuard{Pattern: "*", MinLength: 87}, begins: 17, ends: 103, want: true},
+		{name: "too short", guard: MatchGuard{Pattern: "*", MinLength: 88}, begins: 17, ends: 103},
+		{name: "context before", guard: MatchGuard{Pattern: "*", Context: []string{`\bDIFFMARK\b`}}, begins: 17, ends: 103, want: true},
+		{name: "context outside the window", guard: MatchGuard{Pattern: "*", Context: []string{`\bdiffmark\b`}, Window: 5}, begins: 17, ends: 103},
+		{name: "any context", guard: MatchGuard{Pattern: "*", Context: []string{`acme`, `consequences`}}, begins: 0, ends: 60, want: true},
+		{name: "no context", guard: MatchGuard{Pattern: "*", Context: []string{`acme`}}, begins: 17, ends: 103},
+		{name: "whole text", guard: MatchGuard{Pattern: "*", Context: []string{`acme`}}, begins: 0, ends: 103, want: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.guard.Allows(text, tt.begins, tt.ends); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultMatchGuards(t *testing.T) {
+	t.Parallel()
+	for _, guard := range DefaultMatchGuards {
+		if err := guard.validate(); err != nil {
+			t.Errorf("validate() error = %v", err)
+		}
+	}
+}
+
+func TestLicenseLibrary_addMatchGuards(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	customPath := filepath.Join(resources, customDir, "test")
+	if err := os.MkdirAll(customPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	guards := `[{"pattern": "Beerware", "min_length": 200}, {"pattern": "Acme-*", "context": ["acme corp"], "window": 100}]`
+	if err := os.WriteFile(filepath.Join(customPath, MatchGuardsJSON), []byte(guards), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Set(Resources, resources)
+	config.Set(configurer.CustomFlag, "test")
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.addMatchGuards(); err != nil {
+		t.Fatalf("addMatchGuards() error = %v", err)
+	}
+
+	for id, want := range map[string]string{"beerware": "Beerware", "Acme-1.0": "Acme-*", "diffmark": "diffmark", "MIT": ""} {
+		got := ""
+		if guard := ll.MatchGuard(id); guard != nil {
+			got = guard.Pattern
+		}
+		if got != want {
+			t.Errorf("MatchGuard(%v) = %q, want %q", id, got, want)
+		}
+	}
+
+	for _, invalid := range []string{`[{"pattern": "["}]`, `[{"pattern": "MIT", "context": ["("]}]`, `[{"pattern": "MIT", "min_length": -1}]`} {
+		if err := os.WriteFile(filepath.Join(customPath, MatchGuardsJSON), []byte(invalid), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ll.addMatchGuards(); err == nil {
+			t.Errorf("addMatchGuards() expected an error for %v", invalid)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// addSPDXHeaders adds the standard license headers of the SPDX licenses (the short notices which the license
+// asks to put at the top of each source file, e.g., "This program is free software; you can redistribute
+// it...") from the header dir as the HeaderPatterns of the licenses. The header dir is optional. A header
+// has the file name of the template of its license (e.g., header/GPL-2.0-or-later.template.txt).
+func (ll *LicenseLibrary) addSPDXHeaders(headerPath string) error {
+	des, err := ll.resourceFiles().ReadDir(headerPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".template.txt") {
+			continue
+		}
+		id := strings.TrimPrefix(strings.TrimSuffix(de.Name(), ".template.txt"), "deprecated_")
+		l, ok := ll.LicenseMap[id]
+		if !ok {
+			Logger.Debugf("Skipping the header of the unknown license '%v'", id)
+			continue
+		}
+		f := filepath.Join(headerPath, de.Name())
+		b, err := ll.resourceFiles().ReadFile(f)
+		if err != nil {
+			return err
+		}
+		l.HeaderPatterns = append(l.HeaderPatterns, &PrimaryPatterns{Text: string(b), FileName: f})
+		ll.LicenseMap[id] = l
+	}
+	return nil
+}
+
+var (
+	headerVarRE     = regexp.MustCompile(`<<var;(.*?)>>`)
+	headerVarAttrRE = regexp.MustCompile(`(\w+)="(.*?)"(?:;|$)`)
+	headerMarkupRE  = regexp.MustCompile(`<<.*?>>`)
+	blankLinesRE    = regexp.MustCompile(`\n{3,}`)
+)
+
+// HeaderText returns the text of the standard license header of the license (to insert it into a source
+// file), with the original text of its variables, and without its copyright line (which is the copyright
+// holder's). It returns false when the license has no standard license header.
+func (ll *LicenseLibrary) HeaderText(id string) (string, bool) {
+	l, ok := ll.LicenseMap[id]
+	if !ok || len(l.HeaderPatterns) == 0 {
+		return "", false
+	}
+	text := headerVarRE.ReplaceAllStringFunc(l.HeaderPatterns[0].Text, func(v string) string {
+		attrs := make(map[string]string)
+		for _, m := range headerVarAttrRE.FindAllStringSubmatch(headerVarRE.FindStringSubmatch(v)[1], -1) {
+			attrs[m[1]] = m[2]
+		}
+		if attrs["name"] == "copyright" {
+			return ""
+		}
+		return attrs["original"]
+	})
+	text = headerMarkupRE.ReplaceAllString(text, "")
+	return strings.TrimSpace(blankLinesRE.ReplaceAllString(text, "\n\n")), true
+}
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+)
+
+func TestLicenseLibrary_HeaderText(t *testing.T) {
+	t.Parallel()
+	ll := &LicenseLibrary{LicenseMap: LicenseMap{
+		"Test-1.0": {HeaderPatterns: []*PrimaryPatterns{{Text: `<<var;name="copyright";original="Copyright (c) <year> <owner>";match=".{0,5000}">>
+
+Licensed under the <<beginOptional>>terms of the <<endOptional>>Test License, see <<var;name="url";original="<https://example.com/test>";match=".+">>.
+`}}},
+		"Test-2.0": {},
+	}}
+	tests := []struct {
+		id     string
+		want   string
+		wantOK bool
+	}{
+		{id: "Test-1.0", want: "Licensed under the terms of the Test License, see <https://example.com/test>.", wantOK: true},
+		{id: "Test-2.0"},
+		{id: "Unknown"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			got, ok := ll.HeaderText(tt.id)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("HeaderText() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
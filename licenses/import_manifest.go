@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/IBM/license-scanner/version"
+)
+
+// ImportManifestFile is the provenance of the imported SPDX resources, written by the importer in the SPDX
+// resources directory (e.g., resources/spdx/3.21/import-manifest.json)
+const ImportManifestFile = "import-manifest.json"
+
+// Import formats of the ImportManifest
+const (
+	ImportLicenseListData = "license-list-data"
+	ImportLicenseListXML  = "license-list-XML"
+)
+
+// Import statuses of the templates of the ImportManifest
+const (
+	ImportValid   = "valid"
+	ImportInvalid = "invalid"
+)
+
+// ImportManifest is the provenance of imported SPDX resources: where and when they were imported from, and
+// which templates were validated
+type ImportManifest struct {
+	// Source is the (absolute) license-list-data (--addAll) or license-list-XML (--addAllXML) directory
+	Source string `json:"source"`
+	// Format is ImportLicenseListData or ImportLicenseListXML
+	Format             string `json:"format"`
+	LicenseListVersion string `json:"licenseListVersion"`
+	// ScannerVersion is the license-scanner version of the import
+	ScannerVersion string    `json:"scannerVersion"`
+	Started        time.Time `json:"started"`
+	Finished       time.Time `json:"finished"`
+	// Templates are the validation statuses of the license and exception templates, in the order of the import
+	Templates []ImportedTemplate `json:"templates"`
+}
+
+// ImportedTemplate is the validation status of an imported template
+type ImportedTemplate struct {
+	// ID is the license or exception ID (with the deprecated_ prefix of a deprecated ID)
+	ID string `json:"id"`
+	// Status is ImportValid, or ImportInvalid when the template did not match its text (and was not imported)
+	Status string `json:"status"`
+	// Error is why the template is invalid
+	Error string `json:"error,omitempty"`
+	// Header is the status of the standard license header (imported from the license-list-XML), "" without one
+	Header string `json:"header,omitempty"`
+}
+
+// Summary returns the summary of the import for the version information
+func (m ImportManifest) Summary() *version.Import {
+	s := &version.Import{Source: m.Source, Imported: m.Finished}
+	for _, t := range m.Templates {
+		if t.Status == ImportValid {
+			s.Valid++
+		} else {
+			s.Invalid++
+		}
+	}
+	return s
+}
+
+// readImportManifest reads the import manifest of the SPDX resources directory, nil when there is none (e.g.,
+// the resources were not imported by the importer, or before it wrote the manifest)
+func readImportManifest(files resourceFiles, spdxPath string) (*ImportManifest, error) {
+	manifestFile := filepath.Join(spdxPath, ImportManifestFile)
+	b, err := files.ReadFile(manifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m ImportManifest
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal the import manifest %v error: %w", manifestFile, err)
+	}
+	return &m, nil
+}
@@ -6,10 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
-	"io/ioutil"
-	"os"
-	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -23,6 +21,7 @@ import (
 	"github.com/mrutkows/sbom-utility/log"
 
 	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/version"
 )
 
 const (
@@ -32,6 +31,7 @@ const (
 	template           = "template"
 	precheck           = "precheck"
 	jsonDir            = "json"
+	header             = "header"
 	LicenseInfoJSON    = "license_info.json"
 	PreChecksPattern   = "prechecks_"
 	PrimaryPattern     = "license_"
@@ -41,9 +41,14 @@ const (
 	AcceptablePatterns = "acceptable_patterns"
 )
 
+// MaxRegexLength is the longest regexp generated from a template or a pattern (the longest SPDX template regexp
+// is under 50 KB), so an untrusted pattern cannot compile to an unbounded regexp
+const MaxRegexLength = 256 * 1024
+
 var (
 	Logger                 = log.NewLogger(log.INFO)
 	pointyBracketSegmentRE = regexp.MustCompile(` *<<(.*?)>> *`)
+	tagSegmentRE           = regexp.MustCompile(`<<.*?>>`)
 	RegexUnsafePattern     = regexp.MustCompile(`([\\.*+?^${}()|[\]])`)
 	spaceTagReplacer       = strings.NewReplacer(
 		" <<", "<<",
@@ -61,18 +66,53 @@ var (
 	)
 )
 
+// LicenseLibrary is the loaded licenses and resources. Once loaded (e.g., with AddAll), it is only read by
+// the identifier, so one library can serve many goroutines. It must not be changed (e.g., with AddAll,
+// Filter, or by setting its fields) while it is used.
 type LicenseLibrary struct {
-	SPDXVersion               string
+	SPDXVersion string
+	// SPDXImport is the summary of the import manifest of the SPDX resources (nil without one)
+	SPDXImport                *version.Import
 	LicenseMap                LicenseMap
 	PrimaryPatternPreCheckMap PrimaryPatternPreCheckMap
 	AcceptablePatternsMap     PatternsMap
-	Config                    *viper.Viper
+	// ExactHashMap has the license IDs by the SHA-256 of their normalized SPDX testdata (verbatim) text
+	ExactHashMap ExactHashMap
+	// ClassificationRules classify the licenses by family and category (the DefaultClassificationRules if nil)
+	ClassificationRules []ClassificationRule
+	// ObligationRules have the obligations of the licenses (the DefaultObligationRules if nil)
+	ObligationRules []ObligationRule
+	// MatchGuards reject the matches of licenses which do not meet their requirements (the DefaultMatchGuards if nil)
+	MatchGuards []MatchGuard
+	// LicenseMappings map license IDs (e.g., of internal licenses) to the SPDX expressions to output instead (none if nil)
+	LicenseMappings map[string]string
+	// LinePrefixes are the comment-like line prefixes which the normalizer also removes from the texts and the
+	// patterns (none if nil), see NormalizationData
+	LinePrefixes *normalizer.LinePrefixes
+	// CandidateIndex selects the primary patterns to check for an input (all patterns if nil)
+	CandidateIndex *CandidateIndex
+	// URLIndex has the license IDs by their reference URLs, to detect bare license URLs (none if nil)
+	URLIndex URLIndex
+	// PreCheckSettings select the static blocks of the prechecks which are checked (all by default)
+	PreCheckSettings PreCheckSettings
+	// ResourcesFS has the spdx and custom resources to load instead of the resources dir of the config (if set),
+	// e.g., a curated dataset which is embedded with go:embed
+	ResourcesFS fs.FS
+	Config      *viper.Viper
 }
 
 type LicensePreChecks struct {
+	// Version is the version of the static block extraction which generated the prechecks (0 for the
+	// unversioned files of version 1)
+	Version      int `json:",omitempty"`
 	StaticBlocks []string
+	// Sha256 is the hash of the normalized SPDX testdata text, precomputed for exact matching
+	Sha256 string `json:",omitempty"`
 }
 
+// ExactHashMap maps the SHA-256 of normalized license text to the license IDs with that text
+type ExactHashMap map[string][]string
+
 type LicensePatternKey struct {
 	FilePath string // Each ID may have multiple license_*.txt primary patterns
 }
@@ -108,6 +148,7 @@ func NewLicenseLibrary(config *viper.Viper) (*LicenseLibrary, error) {
 		LicenseMap:                make(LicenseMap),
 		PrimaryPatternPreCheckMap: make(PrimaryPatternPreCheckMap),
 		AcceptablePatternsMap:     make(PatternsMap),
+		ExactHashMap:              make(ExactHashMap),
 		Config:                    config,
 	}
 
@@ -125,6 +166,9 @@ type License struct {
 	PrimaryPatternsSources    []PrimaryPatternsSources
 	AssociatedPatterns        []*PrimaryPatterns
 	AssociatedPatternsSources []PrimaryPatternsSources
+	// HeaderPatterns are the standard license headers (the short notices for the top of the source files), which
+	// are only matched in the headers mode and reported apart from the license matches
+	HeaderPatterns []*PrimaryPatterns
 	// Aliases (and names and IDs) can be used like primary patterns (unless disabled), but are simple strings not regex. They also require word boundaries.
 	Aliases []string
 	// URLs can be used like primary patterns (unless disabled), but are simple strings not regex with URL matching.
@@ -136,9 +180,16 @@ type License struct {
 type PatternsMap map[string]*regexp.Regexp
 
 type PrimaryPatterns struct {
-	Text          string
-	doOnce        sync.Once
-	re            *regexp.Regexp
+	Text   string
+	doOnce sync.Once
+	re     *regexp.Regexp
+	err    error
+	// source is the generated regexp of a compiled library (with the CaptureGroups), compiled on first use
+	source string
+	// maxVariableLength bounds the repeats of the <<segment>>s of the generated regexp (see boundSegment)
+	maxVariableLength int
+	// linePrefixes are the line prefixes of the library which are removed when the pattern is normalized
+	linePrefixes  *normalizer.LinePrefixes
 	CaptureGroups []*normalizer.CaptureGroup
 	FileName      string
 }
@@ -159,17 +210,19 @@ type LicenseText struct {
 }
 
 type SPDXLicenceInfo struct {
-	Name                  string `json:"name"`
-	LicenseID             string `json:"licenseId"`
-	IsOSIApproved         bool   `json:"isOsiApproved"`
-	IsFSFLibre            bool   `json:"isFsfLibre"`
-	IsDeprecatedLicenseID bool   `json:"isDeprecatedLicenseId"`
+	Name                  string   `json:"name"`
+	LicenseID             string   `json:"licenseId"`
+	IsOSIApproved         bool     `json:"isOsiApproved"`
+	IsFSFLibre            bool     `json:"isFsfLibre"`
+	IsDeprecatedLicenseID bool     `json:"isDeprecatedLicenseId"`
+	SeeAlso               []string `json:"seeAlso"`
 }
 
 type SPDXExceptionInfo struct {
-	Name                  string `json:"name"`
-	LicenseExceptionID    string `json:"licenseExceptionId"`
-	IsDeprecatedLicenseID bool   `json:"isDeprecatedLicenseId"`
+	Name                  string   `json:"name"`
+	LicenseExceptionID    string   `json:"licenseExceptionId"`
+	IsDeprecatedLicenseID bool     `json:"isDeprecatedLicenseId"`
+	SeeAlso               []string `json:"seeAlso"`
 }
 
 type SPDXLicenceList struct {
@@ -181,6 +234,7 @@ type SPDXLicenceList struct {
 type LicenseInfo struct {
 	Name             string         `json:"name"`
 	Family           string         `json:"family"`
+	Category         string         `json:"category"`
 	SPDXStandard     bool           `json:"spdx_standard"`
 	SPDXException    bool           `json:"spdx_exception"`
 	OSIApproved      bool           `json:"osi_approved"`
@@ -192,6 +246,10 @@ type LicenseInfo struct {
 	IsMutator        bool           `json:"is_mutator"`
 	IsDeprecated     bool           `json:"is_deprecated"`
 	IsFSFLibre       bool           `json:"is_fsf_libre"`
+	// SeeAlso are the reference URLs from the SPDX license list (urls are for matching)
+	SeeAlso SliceOfStrings `json:"see_also"`
+	// Obligations take precedence over the obligation rules (see obligations.go)
+	Obligations SliceOfStrings `json:"obligations"`
 }
 
 // SliceOfStrings gives us []string with special UnmarshalJSON
@@ -254,23 +312,128 @@ func (l License) GetID() string {
 	}
 }
 
+// Metadata is the license list information for a license
+type Metadata struct {
+	Name          string
+	IsOSIApproved bool
+	IsFSFLibre    bool
+	IsDeprecated  bool
+	IsException   bool
+	// SeeAlso are the reference URLs for the license
+	SeeAlso []string
+}
+
+// Metadata returns the license list information (from licenses.json, exceptions.json, and license_info.json)
+func (ll *LicenseLibrary) Metadata(id string) Metadata {
+	info := ll.LicenseMap[id].LicenseInfo
+	return Metadata{
+		Name:          info.Name,
+		IsOSIApproved: info.OSIApproved,
+		IsFSFLibre:    info.IsFSFLibre,
+		IsDeprecated:  info.IsDeprecated,
+		IsException:   info.SPDXException,
+		SeeAlso:       info.SeeAlso,
+	}
+}
+
+// CanonicalText returns the canonical text of a license: the SPDX license list text (testdata), or the
+// text of the first primary pattern when there is no SPDX text (e.g., custom licenses)
+func (ll *LicenseLibrary) CanonicalText(id string) (string, error) {
+	lic, ok := ll.LicenseMap[id]
+	if !ok {
+		return "", fmt.Errorf("license %v is not in the license library", id)
+	}
+	b, err := ll.resourceFiles().ReadFile(ll.TestdataFile(id))
+	if err == nil {
+		return string(b), nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	if len(lic.PrimaryPatterns) == 0 {
+		return "", fmt.Errorf("license %v has no text", id)
+	}
+	return lic.PrimaryPatterns[0].Text, nil
+}
+
+// TestdataFile returns the SPDX license list text file of a license ID (with the deprecated_ prefix for a
+// deprecated ID), which only the SPDX licenses have
+func (ll *LicenseLibrary) TestdataFile(id string) string {
+	f := id + ".txt"
+	if lic, ok := ll.LicenseMap[id]; ok && lic.LicenseInfo.IsDeprecated {
+		f = "deprecated_" + f
+	}
+	return filepath.Join(ll.resourcesDir(), "spdx", ll.Config.GetString(SPDX), "testdata", f)
+}
+
+// AddAll adds the SPDX and custom licenses (from the --compiled library, if any, or from the compiled library
+// in the user cache with --cache, unless the resources are the ResourcesFS), then keeps only the licenses
+// selected by the --only and --exclude config (if any)
 func (ll *LicenseLibrary) AddAll() error {
+	if compiled := ll.Config.GetString(configurer.CompiledFlag); compiled != "" {
+		if err := ll.LoadCompiled(compiled); err != nil {
+			return err
+		}
+	} else if ll.Config.GetBool(configurer.CacheFlag) && ll.ResourcesFS == nil {
+		if err := ll.addAllCached(); err != nil {
+			return err
+		}
+	} else if err := ll.AddAllResources(); err != nil {
+		return err
+	}
+	return ll.selectLicenses()
+}
+
+// AddCompiled adds the licenses from a compiled library (see ReadCompiled), like AddAll with --compiled, but
+// without any file access (e.g., for a library which is embedded in a WebAssembly build)
+func (ll *LicenseLibrary) AddCompiled(r io.Reader) error {
+	if err := ll.ReadCompiled(r); err != nil {
+		return err
+	}
+	return ll.selectLicenses()
+}
+
+// selectLicenses applies the pre-check settings to the added licenses, then keeps only the licenses selected by
+// the --only and --exclude config (if any), with their URL index
+func (ll *LicenseLibrary) selectLicenses() error {
+	if err := ll.applyPreCheckSettings(); err != nil {
+		return err
+	}
+	if err := ll.Filter(ll.Config.GetStringSlice(configurer.OnlyFlag), ll.Config.GetStringSlice(configurer.ExcludeFlag)); err != nil {
+		return err
+	}
+	ll.URLIndex = NewURLIndex(ll.LicenseMap)
+	return nil
+}
+
+// AddAllResources adds the SPDX and custom licenses from the resources (the ResourcesFS, if set), with the
+// candidate index
+func (ll *LicenseLibrary) AddAllResources() error {
 	if err := ll.AddAllSPDX(); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		// not exist is okay for now. Assuming legacy resources
 		return err
 	}
-	return ll.AddAllLegacy()
+	if err := ll.AddAllLegacy(); err != nil {
+		return err
+	}
+	if err := ll.setMaxVariableLength(); err != nil {
+		return err
+	}
+	ll.setLinePrefixes()
+	ll.CandidateIndex = NewCandidateIndex(ll.PrimaryPatternPreCheckMap)
+	return nil
 }
 
 func (ll *LicenseLibrary) AddAllSPDX() error {
-	resourcesPath := ll.Config.GetString(Resources)
+	files := ll.resourceFiles()
+	resourcesPath := ll.resourcesDir()
 	SPDXDir := ll.Config.GetString(SPDX)
 	// templateMap := make(map[string]string)
-	templatePath := path.Join(resourcesPath, "spdx", SPDXDir, template)
-	jsonPath := path.Join(resourcesPath, "spdx", SPDXDir, jsonDir)
+	templatePath := filepath.Join(resourcesPath, "spdx", SPDXDir, template)
+	jsonPath := filepath.Join(resourcesPath, "spdx", SPDXDir, jsonDir)
 
-	licensesJSON := path.Join(jsonPath, "licenses.json")
-	SPDXLicenseListBytes, err := os.ReadFile(licensesJSON)
+	licensesJSON := filepath.Join(jsonPath, "licenses.json")
+	SPDXLicenseListBytes, err := files.ReadFile(licensesJSON)
 	if err != nil {
 		return fmt.Errorf("read SPDXLicenseListJSON from %v error: %w", licensesJSON, err)
 	}
@@ -280,9 +443,16 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 	}
 
 	ll.SPDXVersion = licenseList.LicenseListVersion
+	importManifest, err := readImportManifest(files, filepath.Join(resourcesPath, "spdx", SPDXDir))
+	if err != nil {
+		return err
+	}
+	if importManifest != nil {
+		ll.SPDXImport = importManifest.Summary()
+	}
 
-	exceptionsJSON := path.Join(jsonPath, "exceptions.json")
-	SPDXExceptionsListBytes, err := os.ReadFile(exceptionsJSON)
+	exceptionsJSON := filepath.Join(jsonPath, "exceptions.json")
+	SPDXExceptionsListBytes, err := files.ReadFile(exceptionsJSON)
 	if err != nil {
 		return fmt.Errorf("read exceptions JSON from %v error: %w", exceptionsJSON, err)
 	}
@@ -294,9 +464,9 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 	for _, sl := range licenseList.Licenses {
 		id := sl.LicenseID
 		f := getTemplateFilePath(id, sl.IsDeprecatedLicenseID, templatePath)
-		tBytes, err := os.ReadFile(f)
+		tBytes, err := files.ReadFile(f)
 		if err != nil {
-			if os.IsNotExist(err) {
+			if errors.Is(err, fs.ErrNotExist) {
 				Logger.Debugf("Skipping missing template file '%v'", f)
 				continue
 			}
@@ -314,15 +484,16 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 		l.LicenseInfo.IsDeprecated = sl.IsDeprecatedLicenseID
 		l.LicenseInfo.OSIApproved = sl.IsOSIApproved
 		l.LicenseInfo.IsFSFLibre = sl.IsFSFLibre
+		l.LicenseInfo.SeeAlso = sl.SeeAlso
 		ll.LicenseMap[id] = l
 	}
 
 	for _, se := range exceptionsList.Exceptions {
 		id := se.LicenseExceptionID
 		f := getTemplateFilePath(id, se.IsDeprecatedLicenseID, templatePath)
-		tBytes, err := os.ReadFile(f)
+		tBytes, err := files.ReadFile(f)
 		if err != nil {
-			if os.IsNotExist(err) {
+			if errors.Is(err, fs.ErrNotExist) {
 				Logger.Debugf("Skipping missing template file '%v'", f)
 				continue
 			}
@@ -338,12 +509,17 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 		l.LicenseInfo.SPDXStandard = true
 		l.LicenseInfo.SPDXException = true
 		l.LicenseInfo.IsDeprecated = se.IsDeprecatedLicenseID
+		l.LicenseInfo.SeeAlso = se.SeeAlso
 		ll.LicenseMap[id] = l
 	}
 
+	if err := ll.addSPDXHeaders(filepath.Join(resourcesPath, "spdx", SPDXDir, header)); err != nil {
+		return err
+	}
+
 	preCheckMap := make(map[string]string)
-	preCheckPath := path.Join(resourcesPath, "spdx", SPDXDir, precheck)
-	if err := filepath.WalkDir(preCheckPath, func(path string, de fs.DirEntry, err error) error {
+	preCheckPath := filepath.Join(resourcesPath, "spdx", SPDXDir, precheck)
+	if err := files.WalkDir(preCheckPath, func(path string, de fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
@@ -365,7 +541,7 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 
 	for id, f := range preCheckMap {
 
-		fileContents, err := os.ReadFile(f)
+		fileContents, err := files.ReadFile(f)
 		if err != nil {
 			return err
 		}
@@ -375,7 +551,18 @@ func (ll *LicenseLibrary) AddAllSPDX() error {
 		if err := addPreChecks(fileContents, templateFilePath, ll); err != nil {
 			return err
 		}
+
+		// The precheck file names are the template names (with any deprecated_ prefix)
+		licenseID := strings.TrimPrefix(id, "deprecated_")
+		preChecks := ll.PrimaryPatternPreCheckMap[LicensePatternKey{FilePath: templateFilePath}]
+		if _, ok := ll.LicenseMap[licenseID]; ok && preChecks.Sha256 != "" {
+			ll.ExactHashMap[preChecks.Sha256] = append(ll.ExactHashMap[preChecks.Sha256], licenseID)
+		}
+	}
+	for _, ids := range ll.ExactHashMap {
+		sort.Strings(ids)
 	}
+	ll.CandidateIndex = NewCandidateIndex(ll.PrimaryPatternPreCheckMap)
 
 	return nil
 }
@@ -385,7 +572,7 @@ func getTemplateFilePath(id string, isDeprecated bool, templatePath string) stri
 	if isDeprecated {
 		f = "deprecated_" + f
 	}
-	f = path.Join(templatePath, f)
+	f = filepath.Join(templatePath, f)
 	return f
 }
 
@@ -400,7 +587,19 @@ func (ll *LicenseLibrary) AddAllLegacy() error {
 	}
 	Logger.Debugf("Loaded %v licenses", len(ll.LicenseMap))
 
-	return nil
+	if err := ll.addClassificationRules(); err != nil {
+		return err
+	}
+	if err := ll.addObligationRules(); err != nil {
+		return err
+	}
+	if err := ll.addLicenseMappings(); err != nil {
+		return err
+	}
+	if err := ll.addLinePrefixes(); err != nil {
+		return err
+	}
+	return ll.addMatchGuards()
 }
 
 func (ll *LicenseLibrary) addAcceptablePattern(patternId string, source string) error {
@@ -417,8 +616,8 @@ func (ll *LicenseLibrary) addAcceptablePattern(patternId string, source string)
 }
 
 func (ll *LicenseLibrary) addAcceptablePatternsFromBundledLibrary() error {
-	_, acceptablePatternsPath := getResourcePaths(ll.Config)
-	if err := ll.addRegexFromSourceToLibrary(acceptablePatternsPath, ll.addAcceptablePattern); err != nil && !os.IsNotExist(err) {
+	_, acceptablePatternsPath := ll.resourcePaths()
+	if err := ll.addRegexFromSourceToLibrary(acceptablePatternsPath, ll.addAcceptablePattern); err != nil && !errors.Is(err, fs.ErrNotExist) {
 		// Ignoring IsNotExist to make acceptable patterns optional, but other errs are not ok
 		return err
 	}
@@ -426,9 +625,9 @@ func (ll *LicenseLibrary) addAcceptablePatternsFromBundledLibrary() error {
 }
 
 func (ll *LicenseLibrary) addRegexFromSourceToLibrary(sourceDir string, addFunction addFunc) error {
-	files, err := ioutil.ReadDir(sourceDir)
+	files, err := ll.resourceFiles().ReadDir(sourceDir)
 	if err != nil {
-		if !os.IsNotExist(err) {
+		if !errors.Is(err, fs.ErrNotExist) {
 			return err
 		} else {
 			return nil
@@ -441,7 +640,7 @@ func (ll *LicenseLibrary) addRegexFromSourceToLibrary(sourceDir string, addFunct
 		}
 		fileName := file.Name()
 		patternId := fileName[:len(fileName)-len(filepath.Ext(fileName))]
-		source, err := ioutil.ReadFile(path.Join(sourceDir, fileName))
+		source, err := ll.resourceFiles().ReadFile(filepath.Join(sourceDir, fileName))
 		if err != nil {
 			return err
 		}
@@ -453,19 +652,19 @@ func (ll *LicenseLibrary) addRegexFromSourceToLibrary(sourceDir string, addFunct
 	return nil
 }
 
-func getResourcePaths(cfg *viper.Viper) (licensePatternsPath, acceptablePatternsPath string) {
-	rd := cfg.GetString(Resources)
-	customVersionedDir := cfg.GetString(configurer.CustomFlag)
-	licensePatternsPath = path.Join(rd, customDir, customVersionedDir, LicensePatterns)
-	acceptablePatternsPath = path.Join(rd, customDir, customVersionedDir, AcceptablePatterns)
+func (ll *LicenseLibrary) resourcePaths() (licensePatternsPath, acceptablePatternsPath string) {
+	rd := ll.resourcesDir()
+	customVersionedDir := ll.Config.GetString(configurer.CustomFlag)
+	licensePatternsPath = filepath.Join(rd, customDir, customVersionedDir, LicensePatterns)
+	acceptablePatternsPath = filepath.Join(rd, customDir, customVersionedDir, AcceptablePatterns)
 	return
 }
 
 // AddLicenses initializes the license data set to scan the input license file against
 // all the possible licenses available in the resources are read
 func (ll *LicenseLibrary) AddLicenses() error {
-	licensePatternsPath, _ := getResourcePaths(ll.Config)
-	licenseIds, err := ioutil.ReadDir(licensePatternsPath)
+	licensePatternsPath, _ := ll.resourcePaths()
+	licenseIds, err := ll.resourceFiles().ReadDir(licensePatternsPath)
 	if err != nil {
 		return err
 	}
@@ -483,12 +682,16 @@ func (ll *LicenseLibrary) AddLicenses() error {
 }
 
 func AddLicense(id string, ll *LicenseLibrary) error {
-	l, existed := ll.LicenseMap[id]
-
-	licensePatternsPath, _ := getResourcePaths(ll.Config)
+	licensePatternsPath, _ := ll.resourcePaths()
 	// license directory is at the LicensePatternsPath/id
-	licenseDirectory := path.Join(licensePatternsPath, id)
-	directoryContents, err := ioutil.ReadDir(licenseDirectory)
+	licenseDirectory := filepath.Join(licensePatternsPath, id)
+	key, alias, err := ll.customLicenseID(id, licenseDirectory)
+	if err != nil {
+		return err
+	}
+	l, existed := ll.LicenseMap[key]
+
+	directoryContents, err := ll.resourceFiles().ReadDir(licenseDirectory)
 	if err != nil {
 		return err
 	}
@@ -501,12 +704,12 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 			continue
 		}
 		// read the file contents, determine the file path by joining licenseDirectory (LicensePatternsPath/id) and file name
-		fileContents, err := ioutil.ReadFile(path.Join(licenseDirectory, file.Name()))
+		fileContents, err := ll.resourceFiles().ReadFile(filepath.Join(licenseDirectory, file.Name()))
 		if err != nil {
 			return err
 		}
 		fileName := file.Name()
-		filePath := path.Join(licenseDirectory, fileName)
+		filePath := filepath.Join(licenseDirectory, fileName)
 		lowerFileName := strings.ToLower(fileName)
 
 		switch {
@@ -516,6 +719,9 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 			if err != nil {
 				return Logger.Errorf("Unmarshal LicenseInfo from %v using LicenseReader error: %v", file.Name(), err)
 			}
+			if alias {
+				payload.SPDXStandard = true // merged into the SPDX license (--customCollision alias)
+			}
 
 			if l.SPDXLicenseID == "" {
 				if payload.SPDXStandard {
@@ -567,6 +773,9 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 				payload.IsDeprecated = payload.IsDeprecated || l.LicenseInfo.IsDeprecated
 				payload.OSIApproved = payload.OSIApproved || l.LicenseInfo.OSIApproved
 				payload.IsFSFLibre = payload.IsFSFLibre || l.LicenseInfo.IsFSFLibre
+				if len(payload.SeeAlso) == 0 {
+					payload.SeeAlso = l.LicenseInfo.SeeAlso
+				}
 			}
 			l.LicenseInfo = *payload
 
@@ -579,9 +788,9 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 		// all other files starting with "prechecks_" are prechecks for license patterns
 		case strings.HasPrefix(lowerFileName, PreChecksPattern):
 			sourceFile := strings.TrimPrefix(fileName, PreChecksPattern)
-			ext := path.Ext(sourceFile)
+			ext := filepath.Ext(sourceFile)
 			sourceFile = sourceFile[0:len(sourceFile)-len(ext)] + ".txt" // Replace .json with .txt
-			filePath := path.Join(licenseDirectory, sourceFile)
+			filePath := filepath.Join(licenseDirectory, sourceFile)
 			if err := addPreChecks(fileContents, filePath, ll); err != nil {
 				return err
 			}
@@ -602,7 +811,7 @@ func AddLicense(id string, ll *LicenseLibrary) error {
 			Logger.Info(fmt.Sprintf("found an invalid file name %s", filePath))
 		}
 	}
-	ll.LicenseMap[id] = l
+	ll.LicenseMap[key] = l
 	return nil
 }
 
@@ -611,7 +820,12 @@ func addPreChecks(fileContents []byte, templatePath string, ll *LicenseLibrary)
 	err := json.Unmarshal(fileContents, readPreChecks)
 	if err != nil {
 		return fmt.Errorf("error on unmarshal %v: %w", templatePath, err)
+	} else if err := readPreChecks.Validate(); err != nil {
+		return fmt.Errorf("invalid prechecks for %v: %w", templatePath, err)
 	} else {
+		if readPreChecks.Stale() {
+			Logger.Infof("The prechecks for %v are version %v (the current version is %v). Run 'license-scanner resources migrate' to regenerate them.", templatePath, readPreChecks.GetVersion(), PreChecksVersion)
+		}
 		licensePatternKey := LicensePatternKey{
 			FilePath: templatePath,
 		}
@@ -634,28 +848,65 @@ func AddPrimaryPatternAndSource(fileContents string, filePath string, l *License
 	return nil
 }
 
-// GenerateMatchingPatternFromSourceText normalizes and compiles a pattern once with sync
+// GenerateMatchingPatternFromSourceText normalizes and compiles a pattern once with sync. It is safe for
+// concurrent use: every caller gets the regexp (and the CaptureGroups) or the error of the one compilation.
 func GenerateMatchingPatternFromSourceText(pp *PrimaryPatterns) (*regexp.Regexp, error) {
-	var err error
 	pp.doOnce.Do(func() {
+		if pp.source != "" {
+			pp.re, pp.err = regexp.Compile(pp.source)
+			return
+		}
 		// Normalize the input text.
 		normalizedData := normalizer.NewNormalizationData(pp.Text, true)
-		err = normalizedData.NormalizeText()
-		if err == nil {
-			var re *regexp.Regexp
-			re, err = GenerateRegexFromNormalizedText(normalizedData.NormalizedText)
-			if err == nil {
-				pp.re = re
-				pp.CaptureGroups = normalizedData.CaptureGroups
-			} else {
-				err = fmt.Errorf("cannot generate re: %v", err)
-			}
+		normalizedData.LinePrefixes = pp.linePrefixes
+		if pp.err = normalizedData.NormalizeText(); pp.err != nil {
+			return
 		}
+		re, err := generateRegex(normalizedData.NormalizedText, variableGroupNames(normalizedData), pp.maxVariableLength)
+		if err != nil {
+			pp.err = fmt.Errorf("cannot generate re: %v", err)
+			return
+		}
+		pp.re = re
+		pp.CaptureGroups = normalizedData.CaptureGroups
 	})
-	return pp.re, err
+	return pp.re, pp.err
 }
 
 func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, error) {
+	return generateRegex(normalizedText, nil, DefaultMaxVariableLength)
+}
+
+// VariableGroupName is the name of the regexp group which captures the text matched by a template variable
+func VariableGroupName(groupNumber int) string {
+	return fmt.Sprintf("var%d", groupNumber)
+}
+
+// variableGroupNames returns the regexp group name of each <<segment>> in a normalized template.
+// The segments which are not replaceable text sections (e.g., bullets) are not named.
+func variableGroupNames(nd *normalizer.NormalizationData) []string {
+	groupNumbers := make(map[int]int)
+	for _, c := range nd.CaptureGroups {
+		groupNumbers[c.Offset] = c.GroupNumber
+	}
+	var names []string
+	for _, ii := range tagSegmentRE.FindAllStringIndex(nd.NormalizedText, -1) {
+		switch nd.NormalizedText[ii[0]:ii[1]] {
+		case normalizer.Omitable, normalizer.ReplaceEndPattern, "<<copyright>>":
+			continue // replaced by tokens, not segments
+		}
+		name := ""
+		if groupNumber, ok := groupNumbers[nd.IndexMap[ii[0]]]; ok {
+			name = VariableGroupName(groupNumber)
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// generateRegex compiles the normalized text with the (optional) names for the groups of the <<segment>>s,
+// whose repeats are bounded to maxVariableLength
+func generateRegex(normalizedText string, groupNames []string, maxVariableLength int) (*regexp.Regexp, error) {
 	// Eat optional single space before "<<" and after ">>" (just refactoring what was in regex)
 	text := spaceTagReplacer.Replace(normalizedText)
 	// Replace simple tags with tokens, so we can attack the not-simple tags which might be nested in these
@@ -665,10 +916,13 @@ func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, err
 	// Escape regex-unsafe characters outside of tags.
 	// Then put the segments back together
 	matches := pointyBracketSegmentRE.FindAllStringSubmatchIndex(text, -1)
+	if len(groupNames) != len(matches) {
+		groupNames = nil // cannot line up the names with the segments
+	}
 
 	var segments []string
 	prev := 0
-	for _, ii := range matches {
+	for i, ii := range matches {
 
 		start := ii[0]
 		end := ii[1]
@@ -684,10 +938,14 @@ func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, err
 		// Handle the sub-matched chars (inside the <<>>)
 		submatchStart := ii[2]
 		submatchEnd := ii[3]
-		segment := text[submatchStart:submatchEnd]
+		segment := boundSegment(text[submatchStart:submatchEnd], maxVariableLength)
 
 		prev = end
-		segments = append(segments, ` *(?:(`+segment+`) *)`)
+		if groupNames != nil && groupNames[i] != "" {
+			segments = append(segments, ` *(?:(?P<`+groupNames[i]+`>`+segment+`) *)`)
+		} else {
+			segments = append(segments, ` *(?:(`+segment+`) *)`)
+		}
 	}
 	if prev < len(text) {
 		segment := text[prev:]
@@ -698,6 +956,9 @@ func GenerateRegexFromNormalizedText(normalizedText string) (*regexp.Regexp, err
 	// Rejoin segments, replace tokens, compile, and return (*re, err)
 	text = strings.Join(segments, "")
 	text = tokenReplacer.Replace(text)
+	if len(text) > MaxRegexLength {
+		return nil, fmt.Errorf("regexp length %v exceeds the maximum of %v", len(text), MaxRegexLength)
+	}
 	return regexp.Compile(text)
 }
 
@@ -0,0 +1,44 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// FuzzGenerateRegexFromNormalizedText checks that a regexp is generated (or an error is returned) without a
+// panic for any normalized template, and that the generated regexp is bounded.
+//
+// Run it with: go test -tags unit -run '^$' -fuzz FuzzGenerateRegexFromNormalizedText ./licenses
+// The inputs which failed are written to testdata/fuzz/FuzzGenerateRegexFromNormalizedText, and are run by the
+// unit tests.
+func FuzzGenerateRegexFromNormalizedText(f *testing.F) {
+	for _, seed := range []string{
+		"permission is hereby granted,free of charge",
+		"<<omitable>>the <<.{1,144}?>> license<</omitable>> copyright <<copyright>>",
+		"<<this|the>> <<.{0,144}>> <<'?software'?|'?materials'?>>",
+		"<<var;name=copyright;match=.{0,5000}>>",
+		"<<(>> <<[a-z>> <<.{1000}{1000}>> <<a{2000}>>",
+		"<<<<nested>>>> >>",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		re, err := GenerateRegexFromNormalizedText(text)
+		if err != nil {
+			return
+		}
+		if len(re.String()) > MaxRegexLength {
+			t.Fatalf("regexp length %v > %v", len(re.String()), MaxRegexLength)
+		}
+		// The template is normalized before the regexp is generated
+		n := normalizer.NewNormalizationData(text, true)
+		if err := n.NormalizeText(); err == nil {
+			_, _ = GenerateRegexFromNormalizedText(n.NormalizedText)
+		}
+	})
+}
@@ -6,6 +6,7 @@ package licenses
 
 import (
 	"fmt"
+	"os"
 	"regexp"
 	"sort"
 	"testing"
@@ -13,11 +14,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/normalizer"
 )
 
 const (
 	expectedLicenseCount    = 540
-	expectedPrecheckCount   = 556
+	expectedPrecheckCount   = 572
 	acceptablePatternsCount = 0
 )
 
@@ -256,6 +258,18 @@ func TestLicenseLibrary_AddAll(t *testing.T) {
 		t.Fatalf("NewLicenseLibrary(configWithResources) error = %v", err)
 	}
 
+	resourcesFSLL, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(config) error = %v", err)
+	}
+	resourcesFSLL.ResourcesFS = os.DirFS("../resources")
+
+	testResourcesFSLL, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(config) error = %v", err)
+	}
+	testResourcesFSLL.ResourcesFS = os.DirFS("../testdata/test-resources")
+
 	tests := []struct {
 		name          string
 		ll            *LicenseLibrary
@@ -288,6 +302,24 @@ func TestLicenseLibrary_AddAll(t *testing.T) {
 				"AcceptablePatternsMap":     1,
 			},
 		},
+		{
+			name: "resources FS",
+			ll:   resourcesFSLL,
+			expectedSizes: map[string]int{
+				"LicenseMap":                expectedLicenseCount,
+				"PrimaryPatternPreCheckMap": expectedPrecheckCount,
+				"AcceptablePatternsMap":     acceptablePatternsCount,
+			},
+		},
+		{
+			name: "test resources FS",
+			ll:   testResourcesFSLL,
+			expectedSizes: map[string]int{
+				"LicenseMap":                1,
+				"PrimaryPatternPreCheckMap": 0,
+				"AcceptablePatternsMap":     1,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -408,3 +440,110 @@ func TestLicenseUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestLicenseLibrary_Filter(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		only    []string
+		exclude []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "no filter", want: []string{"Apache-2.0", "GPL-2.0-only", "GPL-3.0-or-later", "MIT"}},
+		{name: "only", only: []string{"MIT,apache-2.0"}, want: []string{"Apache-2.0", "MIT"}},
+		{name: "only wildcard", only: []string{"GPL-*"}, want: []string{"GPL-2.0-only", "GPL-3.0-or-later"}},
+		{name: "exclude wildcard", exclude: []string{"*-only", "MIT"}, want: []string{"Apache-2.0", "GPL-3.0-or-later"}},
+		{name: "only and exclude", only: []string{"GPL-*"}, exclude: []string{"GPL-3.0-*"}, want: []string{"GPL-2.0-only"}},
+		{name: "bad pattern", only: []string{"GPL-["}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ll, err := NewLicenseLibrary(nil)
+			if err != nil {
+				t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+			}
+			for _, id := range []string{"Apache-2.0", "GPL-2.0-only", "GPL-3.0-or-later", "MIT"} {
+				ll.LicenseMap[id] = License{SPDXLicenseID: id}
+			}
+			ll.ExactHashMap["hash"] = []string{"GPL-2.0-only", "MIT"}
+
+			err = ll.Filter(tt.only, tt.exclude)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Filter() expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Filter() error = %v", err)
+			}
+			var got []string
+			for id := range ll.LicenseMap {
+				got = append(got, id)
+			}
+			sort.Strings(got)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Filter() mismatch (-want +got):\n%s", d)
+			}
+			for _, id := range ll.ExactHashMap["hash"] {
+				if _, ok := ll.LicenseMap[id]; !ok {
+					t.Errorf("Filter() kept the exact hash for %v", id)
+				}
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_Metadata(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	tests := []struct {
+		id   string
+		want Metadata
+	}{
+		{id: "MIT", want: Metadata{Name: "MIT License", IsOSIApproved: true, IsFSFLibre: true, SeeAlso: []string{"https://opensource.org/licenses/MIT"}}},
+		{id: "GPL-2.0+", want: Metadata{Name: "GNU General Public License v2.0 or later", IsOSIApproved: true, IsFSFLibre: true, IsDeprecated: true, SeeAlso: []string{"https://www.gnu.org/licenses/old-licenses/gpl-2.0-standalone.html", "https://opensource.org/licenses/GPL-2.0"}}},
+		{id: "Classpath-exception-2.0", want: Metadata{Name: "Classpath exception 2.0", IsException: true, SeeAlso: []string{"http://www.gnu.org/software/classpath/license.html", "https://fedoraproject.org/wiki/Licensing/GPL_Classpath_Exception"}}},
+		{id: "Unknown-1.0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, ll.Metadata(tt.id)); d != "" {
+				t.Errorf("Metadata(%v) mismatch (-want +got):\n%s", tt.id, d)
+			}
+		})
+	}
+}
+
+func TestRegexSegments(t *testing.T) {
+	t.Parallel()
+	template := "Copyright <<var;name=\"copyright\";original=\"(c) <year>\";match=.+>>\n<<beginOptional>>All rights reserved.<<endOptional>> Permission is granted (to <<match=use|copy>>)."
+	nd := normalizer.NewNormalizationData(template, true)
+	if err := nd.NormalizeText(); err != nil {
+		t.Fatal(err)
+	}
+	re, err := GenerateRegexFromNormalizedText(nd.NormalizedText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segments := RegexSegments(nd.NormalizedText)
+	if got := RegexPrefix(segments, len(segments)); got != re.String() {
+		t.Errorf("RegexPrefix(all) = %q, want %q", got, re.String())
+	}
+	for n := range segments {
+		if _, err := regexp.Compile(RegexPrefix(segments, n)); err != nil {
+			t.Errorf("RegexPrefix(%v) does not compile: %v", n, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// LicenseMapJSON is the custom resource file which maps license IDs (e.g., of internal licenses) to the SPDX
+// expressions to output instead, e.g., {"ACME-EULA-2": "LicenseRef-acme-EULA-2"}
+const LicenseMapJSON = "license_map.json"
+
+// MappedExpression returns the SPDX expression which the license map of the library maps a license ID to
+func (ll *LicenseLibrary) MappedExpression(id string) (string, bool) {
+	expression, ok := ll.LicenseMappings[id]
+	return expression, ok
+}
+
+// addLicenseMappings adds the license map from the custom license_map.json (if any)
+func (ll *LicenseLibrary) addLicenseMappings() error {
+	f := filepath.Join(ll.resourcesDir(), customDir, ll.Config.GetString(configurer.CustomFlag), LicenseMapJSON)
+	b, err := ll.resourceFiles().ReadFile(f)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var mappings map[string]string
+	if err := json.Unmarshal(b, &mappings); err != nil {
+		return fmt.Errorf("cannot unmarshal %v: %w", f, err)
+	}
+	for id, expression := range mappings {
+		if strings.TrimSpace(id) == "" {
+			return fmt.Errorf("invalid license mapping in %v: an empty license ID", f)
+		}
+		e, err := ParseExpression(expression)
+		if err != nil {
+			return fmt.Errorf("invalid license mapping of %v in %v: %w", id, f, err)
+		}
+		mappings[id] = e.String()
+	}
+	ll.LicenseMappings = mappings
+	return nil
+}
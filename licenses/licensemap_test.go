@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLicenseLibrary_addLicenseMappings(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		json    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "license refs and expressions",
+			json: `{"ACME-EULA-2": "LicenseRef-acme-EULA-2", "ACME-DUAL": "mit or (Apache-2.0 and BSD-3-Clause)"}`,
+			want: map[string]string{"ACME-EULA-2": "LicenseRef-acme-EULA-2", "ACME-DUAL": "mit OR (Apache-2.0 AND BSD-3-Clause)"},
+		},
+		{name: "no license map"},
+		{name: "invalid expression", json: `{"ACME-EULA-2": "MIT OR"}`, wantErr: true},
+		{name: "empty license ID", json: `{" ": "MIT"}`, wantErr: true},
+		{name: "not a map", json: `["MIT"]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ll := emptyLibrary(t, nil)
+			resources := fstest.MapFS{}
+			if tt.json != "" {
+				resources["custom/default/"+LicenseMapJSON] = &fstest.MapFile{Data: []byte(tt.json)}
+			}
+			ll.ResourcesFS = resources
+			err := ll.addLicenseMappings()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("addLicenseMappings() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, ll.LicenseMappings); d != "" {
+				t.Errorf("LicenseMappings mismatch (-want +got):\n%s", d)
+			}
+			if got, ok := ll.MappedExpression("ACME-EULA-2"); ok != (tt.want != nil) || got != tt.want["ACME-EULA-2"] {
+				t.Errorf("MappedExpression() = %q, %v", got, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// LinePrefixesJSON is the optional file (in the custom resources) with more comment-like line prefixes which
+// the normalizer removes with the built-in comment indicators, e.g., ["%", "rem"]
+const LinePrefixesJSON = "line_prefixes.json"
+
+// addLinePrefixes adds the line prefixes from the custom line_prefixes.json (if any)
+func (ll *LicenseLibrary) addLinePrefixes() error {
+	f := filepath.Join(ll.resourcesDir(), customDir, ll.Config.GetString(configurer.CustomFlag), LinePrefixesJSON)
+	b, err := ll.resourceFiles().ReadFile(f)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var prefixes []string
+	if err := json.Unmarshal(b, &prefixes); err != nil {
+		return fmt.Errorf("cannot unmarshal %v: %w", f, err)
+	}
+	if ll.LinePrefixes, err = normalizer.NewLinePrefixes(prefixes); err != nil {
+		return fmt.Errorf("invalid line prefixes in %v: %w", f, err)
+	}
+	return nil
+}
+
+// setLinePrefixes sets the line prefixes of the library on its patterns, before their regexps are generated
+func (ll *LicenseLibrary) setLinePrefixes() {
+	for _, l := range ll.LicenseMap {
+		for _, patterns := range [][]*PrimaryPatterns{l.PrimaryPatterns, l.AssociatedPatterns, l.HeaderPatterns} {
+			for _, pp := range patterns {
+				pp.linePrefixes = ll.LinePrefixes
+			}
+		}
+	}
+}
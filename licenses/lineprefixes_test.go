@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestAddLinePrefixes(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	customPath := filepath.Join(resources, customDir, "test")
+	if err := os.MkdirAll(customPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Set(Resources, resources)
+	config.Set(configurer.CustomFlag, "test")
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.addLinePrefixes(); err != nil || ll.LinePrefixes != nil {
+		t.Fatalf("addLinePrefixes() without %v = %v, error = %v", LinePrefixesJSON, ll.LinePrefixes, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(customPath, LinePrefixesJSON), []byte(`["%", "REM"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ll.addLinePrefixes(); err != nil {
+		t.Fatalf("addLinePrefixes() error = %v", err)
+	}
+	if d := cmp.Diff([]string{"%", "rem"}, ll.LinePrefixes.Prefixes()); d != "" {
+		t.Errorf("addLinePrefixes() mismatch (-want +got):\n%s", d)
+	}
+
+	// a pattern is normalized with the line prefixes of the library
+	l := License{}
+	if err := AddPrimaryPatternAndSource("% Permission is granted\n% to use it", "test.template.txt", &l); err != nil {
+		t.Fatal(err)
+	}
+	ll.LicenseMap["Test"] = l
+	ll.setLinePrefixes()
+	re, err := GenerateMatchingPatternFromSourceText(l.PrimaryPatterns[0])
+	if err != nil {
+		t.Fatalf("GenerateMatchingPatternFromSourceText() error = %v", err)
+	}
+	if !re.MatchString("permission is granted to use it") {
+		t.Errorf("GenerateMatchingPatternFromSourceText() = %v, want the pattern without the line prefixes", re)
+	}
+
+	for _, invalid := range []string{`["%", ""]`, `{"prefixes": ["%"]}`} {
+		if err := os.WriteFile(filepath.Join(customPath, LinePrefixesJSON), []byte(invalid), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ll.addLinePrefixes(); err == nil {
+			t.Errorf("addLinePrefixes() expected an error for %v", invalid)
+		}
+	}
+}
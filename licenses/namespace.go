@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// The --customCollision policies of a custom license (which is not an SPDX license) with the ID of an SPDX license
+const (
+	// CollisionError fails to load the library
+	CollisionError = "error"
+	// CollisionOverride replaces the SPDX license with the custom license
+	CollisionOverride = "override"
+	// CollisionAlias adds the patterns of the custom license to the SPDX license (like a custom SPDX license)
+	CollisionAlias = "alias"
+)
+
+// CollisionPolicies are the --customCollision policies
+var CollisionPolicies = []string{CollisionError, CollisionOverride, CollisionAlias}
+
+// licenseRefInvalidRE matches the characters which are not valid in an SPDX license ref
+var licenseRefInvalidRE = regexp.MustCompile(`[^A-Za-z0-9.-]+`)
+
+// LicenseRef returns the SPDX license ref of a custom license ID in a namespace (LicenseRef-<namespace>-<id>),
+// with the invalid characters replaced
+func LicenseRef(namespace string, id string) string {
+	return "LicenseRef-" + licenseRefInvalidRE.ReplaceAllString(namespace, "-") + "-" + licenseRefInvalidRE.ReplaceAllString(id, "-")
+}
+
+// CustomNamespace returns the --customNamespace after checking it
+func CustomNamespace(cfg *viper.Viper) (string, error) {
+	ns := cfg.GetString(configurer.CustomNamespaceFlag)
+	if licenseRefInvalidRE.MatchString(ns) || strings.HasPrefix(ns, "-") || strings.HasSuffix(ns, "-") {
+		return "", fmt.Errorf("invalid --%v %q (letters, digits, '.', and '-')", configurer.CustomNamespaceFlag, ns)
+	}
+	return ns, nil
+}
+
+// CollisionPolicy returns the --customCollision policy after checking it
+func CollisionPolicy(cfg *viper.Viper) (string, error) {
+	policy := cfg.GetString(configurer.CustomCollisionFlag)
+	switch policy {
+	case "":
+		return CollisionError, nil
+	case CollisionError, CollisionOverride, CollisionAlias:
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid --%v %q (expected %v, %v, or %v)", configurer.CustomCollisionFlag, policy, CollisionError, CollisionOverride, CollisionAlias)
+}
+
+// customLicenseID returns the ID in the library of the custom license in the license dir: the ID of an SPDX license
+// which the custom license extends (its license_info.json has spdx_standard, or it has none, e.g., translated
+// patterns) or aliases, the LicenseRef of the --customNamespace, or else the dir name. A custom license with the
+// ID of an SPDX license is handled with the --customCollision policy, and alias is true when its patterns are
+// added to the SPDX license.
+func (ll *LicenseLibrary) customLicenseID(id string, licenseDirectory string) (key string, alias bool, err error) {
+	b, err := ll.resourceFiles().ReadFile(filepath.Join(licenseDirectory, LicenseInfoJSON))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", false, err
+	}
+	spdx, ok := ll.LicenseMap[id]
+	isSPDX := ok && spdx.SPDXLicenseID != ""
+	var info LicenseInfo
+	if b != nil {
+		if err := json.Unmarshal(b, &info); err != nil {
+			return "", false, fmt.Errorf("unmarshal %v: %w", LicenseInfoJSON, err)
+		}
+	}
+	if info.SPDXStandard || (b == nil && isSPDX) {
+		return id, false, nil
+	}
+	ns, err := CustomNamespace(ll.Config)
+	if err != nil {
+		return "", false, err
+	}
+	if ns != "" {
+		return LicenseRef(ns, id), false, nil
+	}
+	if !isSPDX {
+		return id, false, nil
+	}
+	policy, err := CollisionPolicy(ll.Config)
+	if err != nil {
+		return "", false, err
+	}
+	switch policy {
+	case CollisionOverride:
+		Logger.Infof("The custom license %v overrides the SPDX license", id)
+		ll.removeSPDXLicense(id)
+		return id, false, nil
+	case CollisionAlias:
+		return id, true, nil
+	}
+	return "", false, fmt.Errorf("the custom license %v has the ID of an SPDX license (set --%v, or --%v %v or %v)", id, configurer.CustomNamespaceFlag, configurer.CustomCollisionFlag, CollisionOverride, CollisionAlias)
+}
+
+// removeSPDXLicense removes an SPDX license with the prechecks and exact hashes of its templates
+func (ll *LicenseLibrary) removeSPDXLicense(id string) {
+	for _, pp := range ll.LicenseMap[id].PrimaryPatterns {
+		delete(ll.PrimaryPatternPreCheckMap, LicensePatternKey{FilePath: pp.FileName})
+	}
+	for hash, ids := range ll.ExactHashMap {
+		kept := ids[:0]
+		for _, other := range ids {
+			if other != id {
+				kept = append(kept, other)
+			}
+		}
+		if len(kept) == 0 {
+			delete(ll.ExactHashMap, hash)
+		} else {
+			ll.ExactHashMap[hash] = kept
+		}
+	}
+	delete(ll.LicenseMap, id)
+}
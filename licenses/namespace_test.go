@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_AddAll_customNamespace(t *testing.T) {
+	t.Parallel()
+	const sha = "80682d76128cb2f94e2cbf1147f12254c12a8d86d40f7cc01429238978883604"
+	// the custom MIT and Acme_1 licenses are not SPDX licenses, but the custom MIT has the ID of the SPDX MIT
+	resources := fstest.MapFS{
+		"spdx/default/json/licenses.json":                          {Data: []byte(`{"licenseListVersion": "3.21", "licenses": [{"licenseId": "MIT", "name": "MIT License"}]}`)},
+		"spdx/default/json/exceptions.json":                        {Data: []byte(`{"exceptions": []}`)},
+		"spdx/default/template/MIT.template.txt":                   {Data: []byte("Permission is hereby granted, free of charge, to any person obtaining a copy")},
+		"spdx/default/precheck/MIT.json":                           {Data: []byte(`{"StaticBlocks": ["permission is hereby granted"], "Sha256": "` + sha + `"}`)},
+		"custom/default/license_patterns/MIT/license_info.json":    {Data: []byte(`{"name": "Acme MIT"}`)},
+		"custom/default/license_patterns/MIT/license_MIT.txt":      {Data: []byte("Acme grants the MIT permissions")},
+		"custom/default/license_patterns/Acme_1/license_info.json": {Data: []byte(`{"name": "Acme One"}`)},
+		"custom/default/license_patterns/Acme_1/license_Acme.txt":  {Data: []byte("Acme grants the permissions of Acme One")},
+	}
+	tests := []struct {
+		name      string
+		settings  map[string]string
+		wantIDs   []string
+		wantMIT   string // the name of the MIT license
+		wantMITPP int    // the primary patterns of the MIT license
+		wantHash  bool   // the exact hash of the SPDX MIT text is kept
+		wantErr   bool
+	}{
+		{name: "collision error", settings: map[string]string{}, wantErr: true},
+		{name: "namespace", settings: map[string]string{configurer.CustomNamespaceFlag: "acme"}, wantIDs: []string{"LicenseRef-acme-Acme-1", "LicenseRef-acme-MIT", "MIT"}, wantMIT: "MIT License", wantMITPP: 1, wantHash: true},
+		{name: "override", settings: map[string]string{configurer.CustomCollisionFlag: CollisionOverride}, wantIDs: []string{"Acme_1", "MIT"}, wantMIT: "Acme MIT", wantMITPP: 1},
+		{name: "alias", settings: map[string]string{configurer.CustomCollisionFlag: CollisionAlias}, wantIDs: []string{"Acme_1", "MIT"}, wantMIT: "MIT License", wantMITPP: 2, wantHash: true},
+		{name: "invalid namespace", settings: map[string]string{configurer.CustomNamespaceFlag: "acme-"}, wantErr: true},
+		{name: "invalid collision policy", settings: map[string]string{configurer.CustomCollisionFlag: "ignore"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flags := configurer.NewDefaultFlags()
+			for flag, value := range tt.settings {
+				if err := flags.Set(flag, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			config, err := configurer.InitConfig(flags)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ll := emptyLibrary(t, config)
+			ll.ResourcesFS = resources
+			err = ll.AddAll()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var ids []string
+			for id := range ll.LicenseMap {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			if d := cmp.Diff(tt.wantIDs, ids); d != "" {
+				t.Errorf("AddAll() license IDs mismatch (-want +got):\n%s", d)
+			}
+			mit := ll.LicenseMap["MIT"]
+			if mit.LicenseInfo.Name != tt.wantMIT || len(mit.PrimaryPatterns) != tt.wantMITPP {
+				t.Errorf("MIT is %q with %v primary patterns, want %q with %v", mit.LicenseInfo.Name, len(mit.PrimaryPatterns), tt.wantMIT, tt.wantMITPP)
+			}
+			if _, ok := ll.ExactHashMap[sha]; ok != tt.wantHash {
+				t.Errorf("exact hash of the SPDX MIT kept = %v, want %v", ok, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestLicenseRef(t *testing.T) {
+	t.Parallel()
+	if got := LicenseRef("acme", "Acme_License 1.0"); got != "LicenseRef-acme-Acme-License-1.0" {
+		t.Errorf("LicenseRef() = %v", got)
+	}
+}
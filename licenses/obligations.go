@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// ObligationsJSON is the optional file (in the custom resources) with obligation rules
+// which take precedence over the DefaultObligationRules
+const ObligationsJSON = "obligations.json"
+
+// The license obligations (what a license requires of the user, or grants to the user)
+const (
+	Attribution       = "attribution"
+	IncludeLicense    = "include-license"
+	IncludeNotice     = "include-notice"
+	StateChanges      = "state-changes"
+	SourceDisclosure  = "source-disclosure"
+	SameLicense       = "same-license"
+	NetworkCopyleft   = "network-copyleft"
+	PatentGrant       = "patent-grant"
+	PatentRetaliation = "patent-retaliation"
+	NoEndorsement     = "no-endorsement"
+	NonCommercial     = "non-commercial"
+	NoDerivatives     = "no-derivatives"
+)
+
+// ObligationDescriptions describe the obligations for the reports. A custom obligation without a
+// description is reported by its name only.
+var ObligationDescriptions = map[string]string{
+	Attribution:       "Keep the copyright notices and give credit to the authors",
+	IncludeLicense:    "Include the license text with copies and distributions",
+	IncludeNotice:     "Include the NOTICE file (if any) with distributions",
+	StateChanges:      "State the changes made to the licensed material",
+	SourceDisclosure:  "Make the source code available when distributing",
+	SameLicense:       "License modifications or derived works under the same license",
+	NetworkCopyleft:   "Make the source code available to users interacting with it over a network",
+	PatentGrant:       "The contributors grant a license to their patents",
+	PatentRetaliation: "The patent license ends for those who sue over patents in the licensed material",
+	NoEndorsement:     "Do not use the names of the authors to endorse derived products",
+	NonCommercial:     "Do not use the licensed material for commercial purposes",
+	NoDerivatives:     "Do not distribute modified versions of the licensed material",
+}
+
+// ObligationRule has the obligations of the license IDs matching the pattern (wildcards allowed,
+// case-insensitive). The first matching rule wins, so a license has the obligations of one rule.
+type ObligationRule struct {
+	Pattern     string   `json:"pattern"`
+	Obligations []string `json:"obligations"`
+}
+
+// DefaultObligationRules are the built-in rules. The more specific patterns come first.
+var DefaultObligationRules = []ObligationRule{
+	// Network copyleft
+	{Pattern: "AGPL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant}},
+	{Pattern: "SSPL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant}},
+	{Pattern: "OSL-3.0", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant, PatentRetaliation}},
+	{Pattern: "EUPL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant}},
+	{Pattern: "RPL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant}},
+
+	// Strong copyleft
+	{Pattern: "GPL-3.0*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, PatentGrant}},
+	{Pattern: "GPL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense}},
+	{Pattern: "OSL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "CECILL-B", Obligations: []string{Attribution, IncludeLicense}},
+	{Pattern: "CECILL-*", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense}},
+	{Pattern: "GFDL-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense}},
+	{Pattern: "Sleepycat", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure}},
+
+	// Weak copyleft
+	{Pattern: "LGPL-3.0*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, PatentGrant}},
+	{Pattern: "LGPL*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense}},
+	{Pattern: "MPL-*", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "EPL-*", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "CDDL-*", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "CPL-*", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "Ms-RL", Obligations: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+	{Pattern: "OFL-*", Obligations: []string{Attribution, IncludeLicense, SameLicense}},
+
+	// Creative Commons
+	{Pattern: "CC0-*", Obligations: []string{}},
+	{Pattern: "CC-BY-NC-SA-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SameLicense, NonCommercial}},
+	{Pattern: "CC-BY-NC-ND-*", Obligations: []string{Attribution, IncludeLicense, NonCommercial, NoDerivatives}},
+	{Pattern: "CC-BY-NC-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, NonCommercial}},
+	{Pattern: "CC-BY-ND-*", Obligations: []string{Attribution, IncludeLicense, NoDerivatives}},
+	{Pattern: "CC-BY-SA-*", Obligations: []string{Attribution, IncludeLicense, StateChanges, SameLicense}},
+	{Pattern: "CC-BY-[0-9]*", Obligations: []string{Attribution, IncludeLicense, StateChanges}},
+
+	// Permissive
+	{Pattern: "Apache-2.0", Obligations: []string{Attribution, IncludeLicense, IncludeNotice, StateChanges, PatentGrant, PatentRetaliation}},
+	{Pattern: "Apache-*", Obligations: []string{Attribution, IncludeLicense, NoEndorsement}},
+	{Pattern: "BSD-3-Clause*", Obligations: []string{Attribution, IncludeLicense, NoEndorsement}},
+	{Pattern: "BSD-4-Clause*", Obligations: []string{Attribution, IncludeLicense, NoEndorsement}},
+	{Pattern: "BSD-*", Obligations: []string{Attribution, IncludeLicense}},
+	{Pattern: "0BSD", Obligations: []string{}},
+	{Pattern: "MIT-0", Obligations: []string{}},
+	{Pattern: "MIT*", Obligations: []string{Attribution, IncludeLicense}},
+	{Pattern: "X11*", Obligations: []string{Attribution, IncludeLicense, NoEndorsement}},
+	{Pattern: "ISC", Obligations: []string{Attribution, IncludeLicense}},
+	{Pattern: "Zlib", Obligations: []string{Attribution, StateChanges}},
+	{Pattern: "BSL-1.0", Obligations: []string{IncludeLicense}},
+	{Pattern: "UPL-1.0", Obligations: []string{Attribution, IncludeLicense, PatentGrant}},
+	{Pattern: "Artistic-2.0", Obligations: []string{Attribution, IncludeLicense, StateChanges, PatentGrant, PatentRetaliation}},
+	{Pattern: "ECL-2.0", Obligations: []string{Attribution, IncludeLicense, IncludeNotice, StateChanges, PatentGrant, PatentRetaliation}},
+	{Pattern: "AFL-*", Obligations: []string{Attribution, IncludeLicense, NoEndorsement, PatentGrant, PatentRetaliation}},
+	{Pattern: "PostgreSQL", Obligations: []string{Attribution, IncludeLicense}},
+	{Pattern: "NCSA", Obligations: []string{Attribution, IncludeLicense, NoEndorsement}},
+	{Pattern: "Python-*", Obligations: []string{Attribution, IncludeLicense, StateChanges}},
+	{Pattern: "PSF-*", Obligations: []string{Attribution, IncludeLicense, StateChanges}},
+
+	// Public domain
+	{Pattern: "Unlicense", Obligations: []string{}},
+	{Pattern: "WTFPL", Obligations: []string{}},
+	{Pattern: "PDDL-*", Obligations: []string{}},
+}
+
+// Obligations returns the obligations of a license (nil when there is no rule for it). The obligations
+// in the license info (license_info.json) are used first, then the library's ObligationRules.
+func (ll *LicenseLibrary) Obligations(id string) []string {
+	if info := ll.LicenseMap[id].LicenseInfo; info.Obligations != nil {
+		return append([]string{}, info.Obligations...)
+	}
+	rules := ll.ObligationRules
+	if rules == nil {
+		rules = DefaultObligationRules
+	}
+	for _, rule := range rules {
+		if matched, _ := matchAny([]string{rule.Pattern}, id); matched {
+			return append([]string{}, rule.Obligations...)
+		}
+	}
+	return nil
+}
+
+// addObligationRules puts the rules from the custom obligations.json (if any) before the default rules
+func (ll *LicenseLibrary) addObligationRules() error {
+	f := filepath.Join(ll.resourcesDir(), customDir, ll.Config.GetString(configurer.CustomFlag), ObligationsJSON)
+	b, err := ll.resourceFiles().ReadFile(f)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var rules []ObligationRule
+	if err := json.Unmarshal(b, &rules); err != nil {
+		return fmt.Errorf("cannot unmarshal %v: %w", f, err)
+	}
+	for _, rule := range rules {
+		if _, err := matchAny([]string{rule.Pattern}, ""); err != nil || strings.TrimSpace(rule.Pattern) == "" {
+			return fmt.Errorf("invalid obligation rule in %v: %q", f, rule.Pattern)
+		}
+	}
+	ll.ObligationRules = append(rules, DefaultObligationRules...)
+	return nil
+}
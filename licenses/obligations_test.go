@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestLicenseLibrary_Obligations(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary(nil) error = %v", err)
+	}
+	ll.LicenseMap["Custom"] = License{LicenseInfo: LicenseInfo{Obligations: SliceOfStrings{Attribution, NonCommercial}}}
+
+	tests := []struct {
+		id   string
+		want []string
+	}{
+		{id: "AGPL-3.0-only", want: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, NetworkCopyleft, PatentGrant}},
+		{id: "GPL-2.0-or-later", want: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense}},
+		{id: "GPL-3.0-only", want: []string{Attribution, IncludeLicense, StateChanges, SourceDisclosure, SameLicense, PatentGrant}},
+		{id: "MPL-2.0", want: []string{Attribution, IncludeLicense, SourceDisclosure, SameLicense, PatentGrant, PatentRetaliation}},
+		{id: "Apache-2.0", want: []string{Attribution, IncludeLicense, IncludeNotice, StateChanges, PatentGrant, PatentRetaliation}},
+		{id: "mit", want: []string{Attribution, IncludeLicense}},
+		{id: "BSD-3-Clause", want: []string{Attribution, IncludeLicense, NoEndorsement}},
+		{id: "CC-BY-NC-SA-4.0", want: []string{Attribution, IncludeLicense, StateChanges, SameLicense, NonCommercial}},
+		{id: "CC0-1.0", want: []string{}},
+		{id: "Custom", want: []string{Attribution, NonCommercial}},
+		{id: "Unknown-1.0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.id, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, ll.Obligations(tt.id)); d != "" {
+				t.Errorf("Obligations(%v) mismatch (-want +got):\n%s", tt.id, d)
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_addObligationRules(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	customPath := filepath.Join(resources, customDir, "test")
+	if err := os.MkdirAll(customPath, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rules := `[{"pattern": "MIT", "obligations": ["attribution", "export-control"]}, {"pattern": "Acme-*", "obligations": ["non-commercial"]}]`
+	if err := os.WriteFile(filepath.Join(customPath, ObligationsJSON), []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := configurer.InitConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Set(Resources, resources)
+	config.Set(configurer.CustomFlag, "test")
+	ll, err := NewLicenseLibrary(config)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.addObligationRules(); err != nil {
+		t.Fatalf("addObligationRules() error = %v", err)
+	}
+
+	for id, want := range map[string][]string{
+		"MIT":          {Attribution, "export-control"},
+		"Acme-1.0":     {NonCommercial},
+		"BSD-2-Clause": {Attribution, IncludeLicense},
+	} {
+		if d := cmp.Diff(want, ll.Obligations(id)); d != "" {
+			t.Errorf("Obligations(%v) mismatch (-want +got):\n%s", id, d)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(customPath, ObligationsJSON), []byte(`[{"pattern": "[", "obligations": []}]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ll.addObligationRules(); err == nil {
+		t.Errorf("addObligationRules() expected an error for an invalid pattern")
+	}
+}
@@ -0,0 +1,145 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// PreChecksVersion is the version of the static block extraction (importer.GetStaticBlocks) which
+// generates the prechecks. Increment it when the extraction changes, so that the prechecks generated by
+// the old extraction are reported as stale until they are regenerated with `resources migrate`.
+const PreChecksVersion = 1
+
+var sha256RE = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// GetVersion returns the version of the prechecks (the files without a version are version 1)
+func (p LicensePreChecks) GetVersion() int {
+	if p.Version == 0 {
+		return 1
+	}
+	return p.Version
+}
+
+// Stale is true when the prechecks were generated by an older static block extraction
+func (p LicensePreChecks) Stale() bool {
+	return p.GetVersion() < PreChecksVersion
+}
+
+// Validate returns an error when the prechecks do not have the schema of a supported version
+func (p LicensePreChecks) Validate() error {
+	if p.Version < 0 || p.Version > PreChecksVersion {
+		return fmt.Errorf("prechecks version %v is not supported (the current version is %v)", p.Version, PreChecksVersion)
+	}
+	for i, block := range p.StaticBlocks {
+		if strings.TrimSpace(block) == "" {
+			return fmt.Errorf("static block %v is empty", i)
+		}
+	}
+	if p.Sha256 != "" && !sha256RE.MatchString(p.Sha256) {
+		return fmt.Errorf("sha256 %q is not a SHA-256 hex digest", p.Sha256)
+	}
+	return nil
+}
+
+// PreCheckSettings select the static blocks which are checked before a template is matched, to trade the
+// precision of the prechecks for recall on mangled inputs (e.g., with a garbled sentence in a license
+// which the template regex still matches). The zero value checks all the static blocks.
+type PreCheckSettings struct {
+	// MinBlockLength is the least number of characters of a static block to check (0 for all the blocks)
+	MinBlockLength int
+	// MaxBlocks is the most static blocks to check per template, the longest ones (0 for all the blocks)
+	MaxBlocks int
+	// RequiredBlocks is the number of the checked static blocks which must be in the input (0 for all
+	// of them). The candidate index is not used with RequiredBlocks, because it requires all the blocks.
+	RequiredBlocks int
+}
+
+// PreCheckSettingsFromConfig returns the settings of the precheck flags
+func PreCheckSettingsFromConfig(cfg *viper.Viper) (PreCheckSettings, error) {
+	s := PreCheckSettings{
+		MinBlockLength: cfg.GetInt(configurer.PreCheckMinLengthFlag),
+		MaxBlocks:      cfg.GetInt(configurer.PreCheckMaxBlocksFlag),
+		RequiredBlocks: cfg.GetInt(configurer.PreCheckRequiredFlag),
+	}
+	if s.MinBlockLength < 0 || s.MaxBlocks < 0 || s.RequiredBlocks < 0 {
+		return s, fmt.Errorf("the precheck settings cannot be negative: %+v", s)
+	}
+	return s, nil
+}
+
+// Select returns the static blocks to check: the blocks of at least MinBlockLength characters, and only
+// the MaxBlocks longest of those (in their original order)
+func (s PreCheckSettings) Select(staticBlocks []string) []string {
+	var ret []string
+	for _, block := range staticBlocks {
+		if len(block) >= s.MinBlockLength {
+			ret = append(ret, block)
+		}
+	}
+	if s.MaxBlocks == 0 || len(ret) <= s.MaxBlocks {
+		return ret
+	}
+	longest := append([]string(nil), ret...)
+	sort.SliceStable(longest, func(i, j int) bool { return len(longest[i]) > len(longest[j]) })
+	keep := make(map[string]int)
+	for _, block := range longest[:s.MaxBlocks] {
+		keep[block]++
+	}
+	selected := ret[:0]
+	for _, block := range ret {
+		if keep[block] > 0 {
+			keep[block]--
+			selected = append(selected, block)
+		}
+	}
+	return selected
+}
+
+// Passed is true when the normalized text has the required number of the static blocks (all of them
+// without RequiredBlocks, and all of them when there are fewer blocks than RequiredBlocks)
+func (s PreCheckSettings) Passed(staticBlocks []string, normalizedText string) bool {
+	required := len(staticBlocks)
+	if s.RequiredBlocks > 0 && s.RequiredBlocks < required {
+		required = s.RequiredBlocks
+	}
+	missing := 0
+	for _, block := range staticBlocks {
+		if !strings.Contains(normalizedText, block) {
+			// stop as soon as too many blocks are missing
+			if missing++; len(staticBlocks)-missing < required {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyPreCheckSettings selects the static blocks of the prechecks, and drops the candidate index when
+// the blocks are not all required
+func (ll *LicenseLibrary) applyPreCheckSettings() error {
+	s, err := PreCheckSettingsFromConfig(ll.Config)
+	if err != nil {
+		return err
+	}
+	ll.PreCheckSettings = s
+	if s.MinBlockLength > 0 || s.MaxBlocks > 0 {
+		for _, pc := range ll.PrimaryPatternPreCheckMap {
+			if pc != nil {
+				pc.StaticBlocks = s.Select(pc.StaticBlocks)
+			}
+		}
+		ll.CandidateIndex = NewCandidateIndex(ll.PrimaryPatternPreCheckMap)
+	}
+	if s.RequiredBlocks > 0 {
+		ll.CandidateIndex = nil
+	}
+	return nil
+}
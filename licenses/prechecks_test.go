@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLicensePreChecks_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		preChecks LicensePreChecks
+		wantErr   bool
+	}{
+		{name: "unversioned", preChecks: LicensePreChecks{StaticBlocks: []string{"permission is hereby granted"}}},
+		{name: "current version with hash", preChecks: LicensePreChecks{Version: PreChecksVersion, StaticBlocks: []string{"x y"}, Sha256: strings.Repeat("0a", 32)}},
+		{name: "newer version", preChecks: LicensePreChecks{Version: PreChecksVersion + 1}, wantErr: true},
+		{name: "empty static block", preChecks: LicensePreChecks{StaticBlocks: []string{"x y", " "}}, wantErr: true},
+		{name: "bad hash", preChecks: LicensePreChecks{Sha256: "not-a-hash"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.preChecks.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.preChecks.Stale() {
+				t.Errorf("Stale() = true for version %v", tt.preChecks.GetVersion())
+			}
+		})
+	}
+}
+
+func TestPreCheckSettings(t *testing.T) {
+	t.Parallel()
+	blocks := []string{"a short one", "the longest static block", "x", "a longer block"}
+	text := "here is the longest static block and a short one"
+	tests := []struct {
+		name       string
+		settings   PreCheckSettings
+		wantBlocks []string
+		wantPassed bool
+	}{
+		{name: "all blocks", wantBlocks: blocks, wantPassed: false},
+		{name: "min length", settings: PreCheckSettings{MinBlockLength: 12}, wantBlocks: []string{"the longest static block", "a longer block"}, wantPassed: false},
+		{name: "max blocks", settings: PreCheckSettings{MaxBlocks: 2}, wantBlocks: []string{"the longest static block", "a longer block"}, wantPassed: false},
+		{name: "required blocks", settings: PreCheckSettings{RequiredBlocks: 2}, wantBlocks: blocks, wantPassed: true},
+		{name: "more required than selected", settings: PreCheckSettings{MaxBlocks: 1, RequiredBlocks: 3}, wantBlocks: []string{"the longest static block"}, wantPassed: true},
+		{name: "too few found", settings: PreCheckSettings{MinBlockLength: 5, RequiredBlocks: 3}, wantBlocks: []string{"a short one", "the longest static block", "a longer block"}, wantPassed: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.settings.Select(blocks)
+			if d := cmp.Diff(tt.wantBlocks, got); d != "" {
+				t.Errorf("Select() mismatch (-want +got):\n%s", d)
+			}
+			if passed := tt.settings.Passed(got, text); passed != tt.wantPassed {
+				t.Errorf("Passed() = %v, want %v", passed, tt.wantPassed)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// resourceFiles reads the resource files by their OS paths: from the OS file system (the paths are in the
+// resources dir of the config), or from the ResourcesFS of the library (the paths are relative to its root)
+type resourceFiles struct {
+	fsys fs.FS
+}
+
+// resourceFiles returns the reader of the resource files of the library
+func (ll *LicenseLibrary) resourceFiles() resourceFiles {
+	return resourceFiles{fsys: ll.ResourcesFS}
+}
+
+// resourcesDir returns the dir to join with the resource paths: the resources dir of the config, or "" for the
+// root of the ResourcesFS
+func (ll *LicenseLibrary) resourcesDir() string {
+	if ll.ResourcesFS != nil {
+		return ""
+	}
+	return ll.Config.GetString(Resources)
+}
+
+func (r resourceFiles) ReadFile(name string) ([]byte, error) {
+	if r.fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(r.fsys, filepath.ToSlash(name))
+}
+
+// ReadDir reads the dir entries sorted by file name
+func (r resourceFiles) ReadDir(name string) ([]fs.DirEntry, error) {
+	if r.fsys == nil {
+		return os.ReadDir(name)
+	}
+	return fs.ReadDir(r.fsys, filepath.ToSlash(name))
+}
+
+// WalkDir walks the dir like filepath.WalkDir, with the OS paths
+func (r resourceFiles) WalkDir(root string, fn fs.WalkDirFunc) error {
+	if r.fsys == nil {
+		return filepath.WalkDir(root, fn)
+	}
+	return fs.WalkDir(r.fsys, filepath.ToSlash(root), func(p string, d fs.DirEntry, err error) error {
+		return fn(filepath.FromSlash(p), d, err)
+	})
+}
@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLicenseLibrary_AddAll_ResourcesFS(t *testing.T) {
+	t.Parallel()
+	ll, err := NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	// a curated dataset without SPDX resources
+	ll.ResourcesFS = fstest.MapFS{
+		"custom/default/license_patterns/Curated/license_info.json":   {Data: []byte(`{"name": "Curated License", "family": "Permissive"}`)},
+		"custom/default/license_patterns/Curated/license_Curated.txt": {Data: []byte("Permission is granted to use this curated software for any purpose.")},
+		"custom/default/classifications.json":                         {Data: []byte(`[{"pattern": "Curated", "family": "Curated family"}]`)},
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	l, ok := ll.LicenseMap["Curated"]
+	if !ok || len(ll.LicenseMap) != 1 {
+		t.Fatalf("AddAll() licenses = %v, want only Curated", ll.LicenseMap)
+	}
+	if d := cmp.Diff("custom/default/license_patterns/Curated/license_Curated.txt", l.PrimaryPatterns[0].FileName); d != "" {
+		t.Errorf("pattern file name mismatch (-want +got):\n%s", d)
+	}
+	if got := ll.ClassificationRules[0].Family; got != "Curated family" {
+		t.Errorf("first classification rule family = %q, want the rule of the FS", got)
+	}
+	if text, err := ll.CanonicalText("Curated"); err != nil || text != "Permission is granted to use this curated software for any purpose." {
+		t.Errorf("CanonicalText() = %q, %v, want the text of the primary pattern", text, err)
+	}
+}
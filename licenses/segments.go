@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+var (
+	tokenRE = regexp.MustCompile(`BEGIN_OMITABLE|END_OMITABLE|COPYRIGHT`)
+	wordRE  = regexp.MustCompile(`\S+\s*|\s+`)
+)
+
+// RegexSegment is a piece of the regex generated from a normalized template (a word, a <<tag>>, or the
+// start or end of an omitable section). The segments are used to find where a template stops matching.
+type RegexSegment struct {
+	// Template is the normalized template text of the segment
+	Template string
+	// Regex is the regex for the segment
+	Regex string
+	// Depth is +1 for the start of an omitable section, -1 for the end, otherwise 0
+	Depth int
+}
+
+// RegexSegments splits the regex generated from the normalized template text (as in GenerateRegexFromNormalizedText)
+// into segments. Joining all the segment regexes gives the regex for the whole template.
+func RegexSegments(normalizedText string) []RegexSegment {
+	text := tagReplacer.Replace(spaceTagReplacer.Replace(normalizedText))
+
+	var segments []RegexSegment
+	prev := 0
+	for _, ii := range pointyBracketSegmentRE.FindAllStringSubmatchIndex(text, -1) {
+		if ii[0] > prev {
+			segments = append(segments, literalSegments(text[prev:ii[0]])...)
+		}
+		segment := boundSegment(text[ii[2]:ii[3]], DefaultMaxVariableLength)
+		segments = append(segments, RegexSegment{Template: "<<" + segment + ">>", Regex: ` *(?:(` + segment + `) *)`})
+		prev = ii[1]
+	}
+	if prev < len(text) {
+		segments = append(segments, literalSegments(text[prev:])...)
+	}
+	return segments
+}
+
+// RegexPrefix returns the regex for the first n segments, with any open omitable sections closed so that it compiles
+func RegexPrefix(segments []RegexSegment, n int) string {
+	var sb strings.Builder
+	depth := 0
+	for _, s := range segments[:n] {
+		sb.WriteString(s.Regex)
+		depth += s.Depth
+	}
+	for ; depth > 0; depth-- {
+		sb.WriteString(tokenReplacer.Replace("END_OMITABLE"))
+	}
+	return sb.String()
+}
+
+// literalSegments splits the text outside the <<tags>> into the omitable tokens and the (escaped) words
+func literalSegments(text string) []RegexSegment {
+	var segments []RegexSegment
+	words := func(s string) {
+		for _, word := range wordRE.FindAllString(s, -1) {
+			segments = append(segments, RegexSegment{Template: word, Regex: RegexUnsafePattern.ReplaceAllString(word, `\${1}`)})
+		}
+	}
+	prev := 0
+	for _, ii := range tokenRE.FindAllStringIndex(text, -1) {
+		words(text[prev:ii[0]])
+		token := text[ii[0]:ii[1]]
+		s := RegexSegment{Regex: tokenReplacer.Replace(token)}
+		switch token {
+		case "BEGIN_OMITABLE":
+			s.Template, s.Depth = normalizer.Omitable, 1
+		case "END_OMITABLE":
+			s.Template, s.Depth = normalizer.ReplaceEndPattern, -1
+		default:
+			s.Template = "<<copyright>>"
+		}
+		segments = append(segments, s)
+		prev = ii[1]
+	}
+	words(text[prev:])
+	return segments
+}
@@ -0,0 +1,72 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// URLIndex maps the normalized reference URLs (the seeAlso URLs of the SPDX license list) to the license IDs
+// which they refer to. Some URLs refer to several licenses (e.g., GPL-2.0-only and GPL-2.0-or-later).
+type URLIndex map[string][]string
+
+var (
+	urlSchemeRE = regexp.MustCompile(`^[a-z][a-z0-9+.-]*://`)
+	// urlSuffixRE matches the endings which do not change the page: a file extension, a trailing slash, or
+	// the Creative Commons legal code (creativecommons.org/licenses/by/4.0/legalcode is the by/4.0 license)
+	urlSuffixRE = regexp.MustCompile(`(?:/legalcode(?:\.[a-z-]+)?|\.html?|\.php|\.txt|/)+$`)
+)
+
+// NewURLIndex indexes the seeAlso URLs of the licenses. The deprecated license IDs are only used for the
+// URLs which no current license refers to.
+func NewURLIndex(licenseMap LicenseMap) URLIndex {
+	current := make(map[string][]string)
+	deprecated := make(map[string][]string)
+	for id, l := range licenseMap {
+		for _, u := range l.LicenseInfo.SeeAlso {
+			key := NormalizeURL(u)
+			if key == "" {
+				continue
+			}
+			if l.LicenseInfo.IsDeprecated {
+				deprecated[key] = append(deprecated[key], id)
+			} else {
+				current[key] = append(current[key], id)
+			}
+		}
+	}
+	for key, ids := range deprecated {
+		if _, ok := current[key]; !ok {
+			current[key] = ids
+		}
+	}
+	for _, ids := range current {
+		sort.Strings(ids)
+	}
+	return current
+}
+
+// NormalizeURL returns the URL without the parts which do not change the page it refers to (the scheme,
+// "www.", the fragment, the case, and the endings matched by urlSuffixRE). The old and new opensource.org
+// license URLs (e.g., opensource.org/licenses/mit-license.php and opensource.org/license/mit) are the same.
+func NormalizeURL(u string) string {
+	u = strings.ToLower(strings.TrimSpace(u))
+	u = urlSchemeRE.ReplaceAllString(u, "")
+	u = strings.TrimPrefix(u, "www.")
+	u, _, _ = strings.Cut(u, "#")
+	u = urlSuffixRE.ReplaceAllString(u, "")
+	if strings.HasPrefix(u, "opensource.org/license/") {
+		u = "opensource.org/licenses/" + strings.TrimPrefix(u, "opensource.org/license/")
+	}
+	if strings.HasPrefix(u, "opensource.org/licenses/") {
+		u = strings.TrimSuffix(u, "-license")
+	}
+	return u
+}
+
+// Lookup returns the license IDs which the URL refers to (nil when it is not a license reference URL)
+func (ui URLIndex) Lookup(u string) []string {
+	return ui[NormalizeURL(u)]
+}
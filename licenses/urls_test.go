@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://opensource.org/licenses/MIT", want: "opensource.org/licenses/mit"},
+		{url: "http://www.opensource.org/licenses/mit-license.php", want: "opensource.org/licenses/mit"},
+		{url: "https://opensource.org/license/mit/", want: "opensource.org/licenses/mit"},
+		{url: "creativecommons.org/licenses/by/4.0/legalcode", want: "creativecommons.org/licenses/by/4.0"},
+		{url: "https://creativecommons.org/licenses/by/4.0/", want: "creativecommons.org/licenses/by/4.0"},
+		{url: "http://www.apache.org/licenses/LICENSE-2.0.txt", want: "apache.org/licenses/license-2.0"},
+		{url: "https://www.gnu.org/licenses/gpl-3.0.html#license-text", want: "gnu.org/licenses/gpl-3.0"},
+		{url: "https://www.openldap.org/devel/gitweb.cgi?p=openldap.git", want: "openldap.org/devel/gitweb.cgi?p=openldap.git"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			if got := NormalizeURL(tt.url); got != tt.want {
+				t.Errorf("NormalizeURL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestURLIndex(t *testing.T) {
+	t.Parallel()
+	licenseMap := LicenseMap{
+		"MIT":              {LicenseInfo: LicenseInfo{SeeAlso: []string{"https://opensource.org/licenses/MIT"}}},
+		"GPL-2.0-only":     {LicenseInfo: LicenseInfo{SeeAlso: []string{"https://opensource.org/licenses/GPL-2.0"}}},
+		"GPL-2.0-or-later": {LicenseInfo: LicenseInfo{SeeAlso: []string{"https://opensource.org/licenses/GPL-2.0"}}},
+		"GPL-2.0":          {LicenseInfo: LicenseInfo{IsDeprecated: true, SeeAlso: []string{"https://opensource.org/licenses/GPL-2.0"}}},
+		"eCos-2.0":         {LicenseInfo: LicenseInfo{IsDeprecated: true, SeeAlso: []string{"https://www.gnu.org/licenses/ecos-license.html"}}},
+	}
+	index := NewURLIndex(licenseMap)
+	tests := []struct {
+		url  string
+		want []string
+	}{
+		{url: "http://opensource.org/licenses/mit-license.php", want: []string{"MIT"}},
+		{url: "opensource.org/licenses/GPL-2.0", want: []string{"GPL-2.0-only", "GPL-2.0-or-later"}},
+		{url: "https://www.gnu.org/licenses/ecos-license.html", want: []string{"eCos-2.0"}},
+		{url: "https://example.com/licenses/MIT"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, index.Lookup(tt.url)); d != "" {
+				t.Errorf("Lookup() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
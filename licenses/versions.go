@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/version"
+)
+
+// LicenseLists returns the SPDX license list version (with the summary of its import manifest) of each SPDX
+// resources directory of the resources (sorted by directory), or of the --compiled library. The version of a
+// directory without a readable license list is "".
+func LicenseLists(cfg *viper.Viper) ([]version.LicenseList, error) {
+	if compiled := cfg.GetString(configurer.CompiledFlag); compiled != "" {
+		l, err := compiledLicenseList(compiled)
+		if err != nil {
+			return nil, err
+		}
+		return []version.LicenseList{l}, nil
+	}
+	spdxPath := filepath.Join(cfg.GetString(Resources), "spdx")
+	des, err := os.ReadDir(spdxPath)
+	if err != nil {
+		return nil, err
+	}
+	var ret []version.LicenseList
+	for _, de := range des {
+		if !de.IsDir() {
+			continue
+		}
+		l := version.LicenseList{SPDX: de.Name()}
+		if b, err := os.ReadFile(filepath.Join(spdxPath, de.Name(), jsonDir, "licenses.json")); err == nil {
+			var licenseList SPDXLicenceList
+			if json.Unmarshal(b, &licenseList) == nil {
+				l.Version = licenseList.LicenseListVersion
+			}
+		}
+		if m, err := readImportManifest(resourceFiles{}, filepath.Join(spdxPath, de.Name())); err == nil && m != nil {
+			l.Import = m.Summary()
+		}
+		ret = append(ret, l)
+	}
+	return ret, nil
+}
+
+// LicenseList returns the SPDX license list of the loaded library
+func (ll *LicenseLibrary) LicenseList() version.LicenseList {
+	return version.LicenseList{SPDX: ll.Config.GetString(configurer.SpdxFlag), Version: ll.SPDXVersion, Import: ll.SPDXImport}
+}
+
+// compiledLicenseList returns the SPDX license list of a compiled library file
+func compiledLicenseList(filePath string) (version.LicenseList, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return version.LicenseList{}, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	magic := make([]byte, len(compiledMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, compiledMagic) {
+		return version.LicenseList{}, fmt.Errorf("%v: not a compiled license library", filePath)
+	}
+	var c compiledLibrary
+	if err := gob.NewDecoder(br).Decode(&c); err != nil {
+		return version.LicenseList{}, fmt.Errorf("%v: cannot decode the compiled library: %w", filePath, err)
+	}
+	return version.LicenseList{SPDX: c.SPDX, Version: c.SPDXVersion, Import: c.SPDXImport}, nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/version"
+)
+
+func TestLicenseLists(t *testing.T) {
+	t.Parallel()
+	resources := t.TempDir()
+	for dir, licensesJSON := range map[string]string{
+		"3.17":    `{"licenseListVersion": "3.17", "licenses": []}`,
+		"default": `{"licenseListVersion": "3.21", "licenses": []}`,
+		"invalid": `{`,
+		"none":    "",
+	} {
+		jsonPath := filepath.Join(resources, "spdx", dir, jsonDir)
+		if err := os.MkdirAll(jsonPath, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if licensesJSON != "" {
+			if err := os.WriteFile(filepath.Join(jsonPath, "licenses.json"), []byte(licensesJSON), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := os.WriteFile(filepath.Join(resources, "spdx", "README.md"), []byte("not a directory"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"source": "/src/license-list-data", "licenseListVersion": "3.17", "finished": "2023-06-01T12:00:00Z",
+		"templates": [{"id": "MIT", "status": "valid"}, {"id": "0BSD", "status": "valid"}, {"id": "AAL", "status": "invalid"}]}`
+	if err := os.WriteFile(filepath.Join(resources, "spdx", "3.17", ImportManifestFile), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	imported := &version.Import{Source: "/src/license-list-data", Imported: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC), Valid: 2, Invalid: 1}
+
+	var compiled bytes.Buffer
+	compiled.Write(compiledMagic)
+	if err := gob.NewEncoder(&compiled).Encode(compiledLibrary{Version: CompiledLibraryVersion, SPDX: "default", SPDXVersion: "3.21"}); err != nil {
+		t.Fatal(err)
+	}
+	compiledFile := filepath.Join(t.TempDir(), "library.bin")
+	if err := os.WriteFile(compiledFile, compiled.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	notCompiled := filepath.Join(t.TempDir(), "library.txt")
+	if err := os.WriteFile(notCompiled, []byte("MIT"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings map[string]string
+		want     []version.LicenseList
+		wantErr  bool
+	}{
+		{
+			name:     "resources",
+			settings: map[string]string{Resources: resources},
+			want:     []version.LicenseList{{SPDX: "3.17", Version: "3.17", Import: imported}, {SPDX: "default", Version: "3.21"}, {SPDX: "invalid"}, {SPDX: "none"}},
+		},
+		{name: "missing resources", settings: map[string]string{Resources: filepath.Join(resources, "missing")}, wantErr: true},
+		{name: "compiled", settings: map[string]string{configurer.CompiledFlag: compiledFile}, want: []version.LicenseList{{SPDX: "default", Version: "3.21"}}},
+		{name: "not compiled", settings: map[string]string{configurer.CompiledFlag: notCompiled}, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := viper.New()
+			for k, v := range tt.settings {
+				cfg.Set(k, v)
+			}
+			got, err := LicenseLists(cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LicenseLists() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("LicenseLists() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
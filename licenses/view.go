@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pattern is a template or pattern of a license, as it is matched
+type Pattern struct {
+	// File is the template or pattern file
+	File string `json:"file"`
+	Text string `json:"text"`
+	// PreChecks are the static blocks which an input must have for the pattern to be checked, after the
+	// precheck settings (none when the pattern is always checked)
+	PreChecks []string `json:"prechecks,omitempty"`
+}
+
+// View is what the library has of a license: its metadata, and the templates and patterns which are matched
+type View struct {
+	Entry
+	SeeAlso     []string `json:"seeAlso,omitempty"`
+	Obligations []string `json:"obligations,omitempty"`
+	// Replacement is the current SPDX expression of a deprecated ID
+	Replacement string `json:"replacement,omitempty"`
+	// Aliases are the names and IDs which are matched as strings (lowercase)
+	Aliases []string `json:"aliases,omitempty"`
+	// URLs are the license URLs which are matched as strings (lowercase, without the scheme)
+	URLs []string `json:"urls,omitempty"`
+	// PrimaryPatterns are the SPDX templates and the custom license patterns
+	PrimaryPatterns    []Pattern `json:"primaryPatterns"`
+	AssociatedPatterns []Pattern `json:"associatedPatterns,omitempty"`
+	// HeaderPatterns are the standard license headers (only matched with the headers option)
+	HeaderPatterns []Pattern `json:"headerPatterns,omitempty"`
+	// CanonicalText is the SPDX license list text, or the text of the first primary pattern ("" when there is none)
+	CanonicalText string `json:"canonicalText"`
+}
+
+// View returns what the library has of the license ID (case-insensitive, like the SPDX IDs)
+func (ll *LicenseLibrary) View(id string) (View, error) {
+	if _, ok := ll.LicenseMap[id]; !ok {
+		found := false
+		for key := range ll.LicenseMap {
+			if strings.EqualFold(key, id) {
+				id, found = key, true
+				break
+			}
+		}
+		if !found {
+			return View{}, fmt.Errorf("license %v is not in the license library", id)
+		}
+	}
+	l := ll.LicenseMap[id]
+	v := View{
+		Entry:              ll.entry(id),
+		SeeAlso:            l.LicenseInfo.SeeAlso,
+		Obligations:        ll.Obligations(id),
+		Aliases:            l.Aliases,
+		URLs:               l.URLs,
+		PrimaryPatterns:    ll.patterns(l.PrimaryPatterns),
+		AssociatedPatterns: ll.patterns(l.AssociatedPatterns),
+		HeaderPatterns:     ll.patterns(l.HeaderPatterns),
+	}
+	v.Replacement, _ = ll.Replacement(id)
+	v.CanonicalText, _ = ll.CanonicalText(id)
+	return v, nil
+}
+
+// patterns returns the patterns with their precheck static blocks
+func (ll *LicenseLibrary) patterns(patterns []*PrimaryPatterns) []Pattern {
+	ret := []Pattern{}
+	for _, p := range patterns {
+		pattern := Pattern{File: p.FileName, Text: p.Text}
+		if preChecks := ll.PrimaryPatternPreCheckMap[LicensePatternKey{FilePath: p.FileName}]; preChecks != nil {
+			pattern.PreChecks = preChecks.StaticBlocks
+		}
+		ret = append(ret, pattern)
+	}
+	return ret
+}
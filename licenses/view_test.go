@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+)
+
+func TestLicenseLibrary_View(t *testing.T) {
+	t.Parallel()
+	ll := &LicenseLibrary{
+		LicenseMap: LicenseMap{
+			"Acme-Internal": {
+				LicenseInfo:        LicenseInfo{Name: "Acme Internal License", Family: "Acme", Category: "proprietary", Obligations: SliceOfStrings{"attribution"}},
+				PrimaryPatterns:    []*PrimaryPatterns{{Text: "Acme internal use only.", FileName: "license_patterns/Acme-Internal/license_acme.txt"}},
+				AssociatedPatterns: []*PrimaryPatterns{{Text: "Acme License", FileName: "license_patterns/Acme-Internal/associated_title.txt"}},
+				Aliases:            []string{"acme internal license"},
+			},
+		},
+		PrimaryPatternPreCheckMap: PrimaryPatternPreCheckMap{
+			{FilePath: "license_patterns/Acme-Internal/license_acme.txt"}: {StaticBlocks: []string{"acme internal use only."}},
+		},
+		Config: viper.New(),
+	}
+	want := View{
+		Entry:              Entry{ID: "Acme-Internal", Name: "Acme Internal License", Family: "Acme", Category: "proprietary", Custom: true, Templates: 1},
+		Obligations:        []string{"attribution"},
+		Aliases:            []string{"acme internal license"},
+		PrimaryPatterns:    []Pattern{{File: "license_patterns/Acme-Internal/license_acme.txt", Text: "Acme internal use only.", PreChecks: []string{"acme internal use only."}}},
+		AssociatedPatterns: []Pattern{{File: "license_patterns/Acme-Internal/associated_title.txt", Text: "Acme License"}},
+		HeaderPatterns:     []Pattern{},
+		CanonicalText:      "Acme internal use only.",
+	}
+	for _, id := range []string{"Acme-Internal", "acme-internal"} {
+		got, err := ll.View(id)
+		if err != nil {
+			t.Fatalf("View(%v) error = %v", id, err)
+		}
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("View(%v) mismatch (-want +got):\n%s", id, d)
+		}
+	}
+	if _, err := ll.View("Nope"); err == nil {
+		t.Error("View(Nope) expected an error")
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package licenses
+
+import (
+	"fmt"
+	"regexp/syntax"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+// DefaultMaxVariableLength is the most repetitions of a repeat (e.g., the characters of .+) in the regexp of a
+// template variable or wildcard (a <<segment>> of a template or a pattern). It is also the largest repeat
+// count of the Go regexps.
+const DefaultMaxVariableLength = 1000
+
+// boundSegment limits each unbounded (*, +, {n,}) or larger repeat of the regexp of a <<segment>> to max
+// repetitions (DefaultMaxVariableLength when 0), so that a template variable cannot match an absurdly long
+// span. A repeat of other repeats gets fewer, so that the product of the nested repeat counts is at most max
+// (as the Go regexps require). The segment is returned unchanged when it has no such repeat or cannot be
+// parsed (the error is returned when the whole regexp is compiled).
+func boundSegment(segment string, max int) string {
+	if max <= 0 {
+		max = DefaultMaxVariableLength
+	}
+	re, err := syntax.Parse(segment, syntax.Perl)
+	if err != nil {
+		return segment
+	}
+	if changed, _ := boundRepeats(re, max); !changed {
+		return segment
+	}
+	return re.String()
+}
+
+// boundRepeats bounds the unbounded or larger repeats of the parsed regexp (see boundSegment), and returns
+// true when any was changed, and the largest product of the nested repeat counts
+func boundRepeats(re *syntax.Regexp, max int) (bool, int) {
+	changed, inner := false, 1
+	for _, sub := range re.Sub {
+		c, product := boundRepeats(sub, max)
+		if c {
+			changed = true
+		}
+		if product > inner {
+			inner = product
+		}
+	}
+	limit := max / inner
+	if limit < 1 {
+		limit = 1
+	}
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus:
+		re.Min, re.Max = 0, limit
+		if re.Op == syntax.OpPlus {
+			re.Min = 1
+		}
+		re.Op = syntax.OpRepeat
+		return true, limit * inner
+	case syntax.OpRepeat:
+		if re.Max == -1 || re.Max > limit {
+			re.Max = limit
+			if re.Min > limit {
+				re.Min = limit
+			}
+			changed = true
+		}
+		if re.Max > 0 {
+			return changed, re.Max * inner
+		}
+	}
+	return changed, inner
+}
+
+// MaxVariableLength returns the --maxVariableLength of the library (DefaultMaxVariableLength when not set)
+func (ll *LicenseLibrary) MaxVariableLength() int {
+	if ll.Config == nil || ll.Config.GetInt(configurer.MaxVariableLengthFlag) == 0 {
+		return DefaultMaxVariableLength
+	}
+	return ll.Config.GetInt(configurer.MaxVariableLengthFlag)
+}
+
+// setMaxVariableLength sets the --maxVariableLength of the library on its patterns, before their regexps
+// are generated
+func (ll *LicenseLibrary) setMaxVariableLength() error {
+	max := ll.MaxVariableLength()
+	if max < 0 || max > DefaultMaxVariableLength {
+		return fmt.Errorf("invalid --%v %v (from 1 to %v)", configurer.MaxVariableLengthFlag, max, DefaultMaxVariableLength)
+	}
+	for _, l := range ll.LicenseMap {
+		for _, pp := range l.PrimaryPatterns {
+			pp.maxVariableLength = max
+		}
+		for _, pp := range l.AssociatedPatterns {
+			pp.maxVariableLength = max
+		}
+		for _, pp := range l.HeaderPatterns {
+			pp.maxVariableLength = max
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package licenses
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+)
+
+func TestBoundSegment(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		segment string
+		max     int
+		want    string
+	}{
+		{name: "bounded", segment: `.{1,144}?`, max: 1000, want: `.{1,144}?`},
+		{name: "no repeats", segment: `Copyright|\(c\)`, max: 1000, want: `Copyright|\(c\)`},
+		{name: "lazy plus", segment: `.+?`, max: 1000, want: `(?-s:.{1,1000}?)`},
+		{name: "star", segment: `-*`, max: 1000, want: `-{0,1000}`},
+		{name: "open repeat", segment: `x{3,}`, max: 10, want: `x{3,10}`},
+		{name: "larger repeat", segment: `x{20,30}`, max: 10, want: `x{10}`},
+		{name: "nested", segment: `(the name of.+)|(the names of.+)`, max: 50, want: `(?-s:(the name of.{1,50})|(the names of.{1,50}))`},
+		{name: "repeat of a repeat", segment: `([0-9]{4},\s)*[0-9]{4}`, max: 1000, want: `([0-9]{4},[\t\n\f\r ]){0,250}[0-9]{4}`},
+		{name: "repeat of a bounded repeat", segment: `(a{1,5}b+)*`, max: 100, want: `(a{1,5}b{1,100}){0,1}`},
+		{name: "default", segment: `a+`, want: `a{1,1000}`},
+		{name: "invalid", segment: `(a+`, max: 10, want: `(a+`},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := boundSegment(tt.segment, tt.max); got != tt.want {
+				t.Errorf("boundSegment() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLicenseLibrary_MaxVariableLength(t *testing.T) {
+	t.Parallel()
+	template := `Licensed by <<var;name="holder";original="the holder";match=".+">> under these terms.`
+	tests := []struct {
+		name      string
+		max       int
+		holder    int
+		wantMatch bool
+		wantErr   bool
+	}{
+		{name: "default", holder: 1000, wantMatch: true},
+		{name: "longer than the default", holder: 1001},
+		{name: "short enough", max: 20, holder: 20, wantMatch: true},
+		{name: "too long", max: 20, holder: 21},
+		{name: "invalid", max: 1001, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := viper.New()
+			cfg.Set(configurer.MaxVariableLengthFlag, tt.max)
+			ll := &LicenseLibrary{Config: cfg, LicenseMap: LicenseMap{"Test": {PrimaryPatterns: []*PrimaryPatterns{{Text: template}}}}}
+			err := ll.setMaxVariableLength()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setMaxVariableLength() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			re, err := GenerateMatchingPatternFromSourceText(ll.LicenseMap["Test"].PrimaryPatterns[0])
+			if err != nil {
+				t.Fatalf("GenerateMatchingPatternFromSourceText() error = %v", err)
+			}
+			input := "licensed by " + strings.Repeat("x", tt.holder) + " under these terms."
+			if got := re.MatchString(input); got != tt.wantMatch {
+				t.Errorf("MatchString() = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
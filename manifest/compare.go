@@ -0,0 +1,216 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"errors"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// expressionOperatorsRE splits an SPDX expression into license IDs
+var expressionOperatorsRE = regexp.MustCompile(`(?i)[()]|\s+(?:OR|AND|WITH)\s+`)
+
+// Comparison holds the declared and detected licenses for one manifest
+type Comparison struct {
+	Declared Declared
+	// DeclaredIDs are the license IDs resolved from the declared values
+	DeclaredIDs []string
+	// Unresolved are declared values which could not be resolved to a license ID
+	Unresolved []string
+	// Detected are the license IDs found in license text next to the manifest
+	Detected []string
+	// Missing are declared license IDs which were not detected (of a declared SPDX expression, only the IDs which
+	// make it unsatisfied: an OR is satisfied by any detected operand, and an AND needs every operand)
+	Missing []string
+	// Error is why the manifest could not be parsed (then nothing was compared)
+	Error string
+}
+
+// Agrees is true when every declared license was resolved and detected
+func (c Comparison) Agrees() bool {
+	return c.Error == "" && len(c.DeclaredIDs) > 0 && len(c.Missing) == 0 && len(c.Unresolved) == 0
+}
+
+// CompareWithResults finds the manifests in a directory scan and compares the declared licenses
+// with the licenses detected in the other files of the same directory. A manifest which cannot be parsed (e.g., a
+// test fixture) has a Comparison with its Error, so that it does not fail the scan.
+func CompareWithResults(results []identifier.IdentifierResults, ll *licenses.LicenseLibrary) []Comparison {
+	detectedByDir := make(map[string][]string)
+	var manifests []string
+	var dep5s []string
+	for _, r := range results {
+		if IsDebianCopyright(r.File) {
+			dep5s = append(dep5s, r.File)
+			continue
+		}
+		if IsManifest(r.File) {
+			manifests = append(manifests, r.File)
+			continue // the declaration is not evidence of itself
+		}
+		dir := filepath.Dir(r.File)
+		for id := range r.Matches {
+			if !slices.Contains(detectedByDir[dir], id) {
+				detectedByDir[dir] = append(detectedByDir[dir], id)
+			}
+		}
+	}
+	sort.Strings(manifests)
+
+	var ret []Comparison
+	for _, m := range manifests {
+		d, err := Parse(m)
+		if err != nil {
+			ret = append(ret, Comparison{Declared: Declared{File: m}, Error: err.Error()})
+			continue
+		}
+		ret = append(ret, Compare(*d, detectedByDir[filepath.Dir(m)], ll))
+	}
+
+	// A debian/copyright declares licenses per file (by pattern) rather than per directory
+	sort.Strings(dep5s)
+	for _, c := range dep5s {
+		comparisons, err := CompareWithDEP5(c, results, ll)
+		if errors.Is(err, ErrNotDEP5) {
+			continue // free-form copyright files declare nothing that can be compared
+		}
+		if err != nil {
+			ret = append(ret, Comparison{Declared: Declared{File: c, Ecosystem: Debian}, Error: err.Error()})
+			continue
+		}
+		ret = append(ret, comparisons...)
+	}
+	return ret
+}
+
+// Compare resolves the declared licenses and reports any declared license that was not detected
+func Compare(d Declared, detected []string, ll *licenses.LicenseLibrary) Comparison {
+	c := Comparison{Declared: d, Detected: append([]string(nil), detected...)}
+	sort.Strings(c.Detected)
+
+	for _, value := range d.Licenses {
+		ids := ResolveIDs(value, ll)
+		if len(ids) == 0 {
+			c.Unresolved = append(c.Unresolved, value)
+		}
+		for _, id := range ids {
+			if !slices.Contains(c.DeclaredIDs, id) {
+				c.DeclaredIDs = append(c.DeclaredIDs, id)
+			}
+		}
+		for _, id := range missingIDs(value, ids, c.Detected, ll) {
+			if !slices.Contains(c.Missing, id) {
+				c.Missing = append(c.Missing, id)
+			}
+		}
+	}
+	sort.Strings(c.DeclaredIDs)
+	sort.Strings(c.Missing)
+	return c
+}
+
+// missingIDs returns the IDs of a declared value which were not detected. An SPDX expression of known IDs is
+// evaluated like a policy (see licenses.Expression.Violations), with the detected licenses (and exceptions) allowed.
+// The IDs which another value (e.g., a license name) resolves to are all required.
+func missingIDs(value string, ids []string, detected []string, ll *licenses.LicenseLibrary) []string {
+	var ret []string
+	if e, err := licenses.ParseExpression(value); err == nil && knownIDs(e, ll) {
+		isDetected := func(s string) bool {
+			id, _ := lookupID(s, ll)
+			return slices.Contains(detected, id)
+		}
+		violations := e.Violations(func(license *licenses.Expression) bool {
+			return isDetected(license.License) && (license.Exception == "" || isDetected(license.Exception))
+		})
+		for _, v := range violations {
+			for _, s := range []string{v.License, v.Exception} {
+				if s != "" && !isDetected(s) {
+					id, _ := lookupID(s, ll)
+					ret = append(ret, id)
+				}
+			}
+		}
+		return ret
+	}
+	for _, id := range ids {
+		if !slices.Contains(detected, id) {
+			ret = append(ret, id)
+		}
+	}
+	return ret
+}
+
+// knownIDs is true when every license and exception ID of the expression is in the library
+func knownIDs(e *licenses.Expression, ll *licenses.LicenseLibrary) bool {
+	for _, l := range e.Licenses() {
+		for _, s := range []string{l.License, l.Exception} {
+			if _, ok := lookupID(s, ll); s != "" && !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ResolveIDs turns a declared license value into license IDs.
+// SPDX IDs and expressions are used as-is. Otherwise, names, aliases and URLs are identified
+// the same way they would be in license text.
+func ResolveIDs(value string, ll *licenses.LicenseLibrary) []string {
+	var ids []string
+	for _, token := range ExpressionIDs(value) {
+		id, ok := lookupID(token, ll)
+		if !ok {
+			ids = nil
+			break // not an expression of known IDs
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > 0 {
+		return ids
+	}
+
+	results, err := identifier.IdentifyLicensesInString(value, identifier.Options{}, ll)
+	if err != nil {
+		return nil
+	}
+	for id := range results.Matches {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ExpressionIDs returns the license (and exception) IDs in an SPDX expression, in order
+func ExpressionIDs(expression string) []string {
+	var ids []string
+	for _, token := range expressionOperatorsRE.Split(expression, -1) {
+		if token = strings.TrimSpace(token); token != "" {
+			ids = append(ids, token)
+		}
+	}
+	return ids
+}
+
+// lookupID finds a license by ID (or by full name) ignoring case
+func lookupID(s string, ll *licenses.LicenseLibrary) (string, bool) {
+	if _, ok := ll.LicenseMap[s]; ok {
+		return s, true
+	}
+	s = strings.TrimSuffix(s, "+") // GPL-2.0+ style "or later" is the same license text
+	if _, ok := ll.LicenseMap[s]; ok {
+		return s, true
+	}
+	for id, l := range ll.LicenseMap {
+		if strings.EqualFold(id, s) || strings.EqualFold(l.LicenseInfo.Name, s) {
+			return id, true
+		}
+	}
+	return "", false
+}
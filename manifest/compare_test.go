@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestCompareWithResults(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		dir         string
+		agrees      bool
+		declaredIDs []string
+		missing     []string
+	}{
+		{
+			name:        "npm declared MIT agrees",
+			dir:         "../testdata/manifest/npm",
+			agrees:      true,
+			declaredIDs: []string{"MIT"},
+		},
+		{
+			name:        "cargo declared MIT or Apache-2.0 agrees with MIT",
+			dir:         "../testdata/manifest/cargo",
+			agrees:      true,
+			declaredIDs: []string{"Apache-2.0", "MIT"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			results, err := identifier.IdentifyLicensesInDirectory(tt.dir, identifier.Options{}, ll)
+			if err != nil {
+				t.Fatalf("IdentifyLicensesInDirectory() error = %v", err)
+			}
+			comparisons := CompareWithResults(results, ll)
+			if len(comparisons) != 1 {
+				t.Fatalf("expected 1 comparison got %v", len(comparisons))
+			}
+			c := comparisons[0]
+			if c.Agrees() != tt.agrees {
+				t.Errorf("Agrees() expected %v got %v for %+v", tt.agrees, c.Agrees(), c)
+			}
+			if d := cmp.Diff(tt.declaredIDs, c.DeclaredIDs); d != "" {
+				t.Errorf("DeclaredIDs mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.missing, c.Missing); d != "" {
+				t.Errorf("Missing mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCompareWithResults_parseError(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	// A package.json with a comment (e.g., a test fixture) is not JSON
+	dir := t.TempDir()
+	mit, err := os.ReadFile("../testdata/manifest/npm/LICENSE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"LICENSE":      string(mit),
+		"package.json": "{\n  // a fixture\n  \"name\": \"fixture\",\n  \"license\": \"MIT\"\n}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	results, err := identifier.IdentifyLicensesInDirectory(dir, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInDirectory() error = %v", err)
+	}
+	comparisons := CompareWithResults(results, ll)
+	if len(comparisons) != 1 {
+		t.Fatalf("expected 1 comparison got %v", len(comparisons))
+	}
+	c := comparisons[0]
+	if c.Error == "" || c.Agrees() || c.Declared.File != filepath.Join(dir, "package.json") {
+		t.Errorf("CompareWithResults() = %+v, want the parse error of %v", c, filepath.Join(dir, "package.json"))
+	}
+}
+
+func TestCompare_expressions(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	tests := []struct {
+		declared string
+		detected []string
+		agrees   bool
+		missing  []string
+	}{
+		{declared: "MIT OR Apache-2.0", detected: []string{"MIT"}, agrees: true},
+		{declared: "mit or apache-2.0", detected: []string{"Apache-2.0"}, agrees: true},
+		{declared: "MIT OR Apache-2.0", agrees: false, missing: []string{"Apache-2.0", "MIT"}},
+		{declared: "MIT AND Apache-2.0", detected: []string{"MIT"}, agrees: false, missing: []string{"Apache-2.0"}},
+		{declared: "(MIT OR Apache-2.0) AND BSD-3-Clause", detected: []string{"BSD-3-Clause", "MIT"}, agrees: true},
+		{declared: "(MIT OR Apache-2.0) AND BSD-3-Clause", detected: []string{"MIT"}, agrees: false, missing: []string{"BSD-3-Clause"}},
+		{declared: "MIT License", detected: []string{"MIT"}, agrees: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.declared, func(t *testing.T) {
+			t.Parallel()
+			c := Compare(Declared{Licenses: []string{tt.declared}}, tt.detected, ll)
+			if c.Agrees() != tt.agrees {
+				t.Errorf("Agrees() expected %v got %v for %+v", tt.agrees, c.Agrees(), c)
+			}
+			if d := cmp.Diff(tt.missing, c.Missing); d != "" {
+				t.Errorf("Missing mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestResolveIDs(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{value: "MIT", expected: []string{"MIT"}},
+		{value: "mit", expected: []string{"MIT"}},
+		{value: "(MIT OR Apache-2.0)", expected: []string{"MIT", "Apache-2.0"}},
+		{value: "GPL-2.0-only WITH Classpath-exception-2.0", expected: []string{"GPL-2.0-only", "Classpath-exception-2.0"}},
+		{value: "MIT License", expected: []string{"MIT"}},
+		{value: "https://opensource.org/licenses/MIT", expected: []string{"MIT"}},
+		{value: "Some Proprietary Thing", expected: nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.expected, ResolveIDs(tt.value, ll)); d != "" {
+				t.Errorf("ResolveIDs(%v) mismatch (-want +got):\n%s", tt.value, d)
+			}
+		})
+	}
+}
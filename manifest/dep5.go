@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+const (
+	// DebianCopyright is the machine-readable (DEP-5) copyright file in a debian dir
+	DebianCopyright = "copyright"
+	debianDir       = "debian"
+	// DEP5Format is the Format of the machine-readable debian/copyright files that are written
+	DEP5Format = "https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/"
+)
+
+// ErrNotDEP5 is returned when a debian/copyright file is not machine-readable
+var ErrNotDEP5 = errors.New("not a machine-readable copyright file (no Format in the header)")
+
+var (
+	// dep5VersionedRE matches DEP-5 short names like GPL-2, LGPL-2.1+, GFDL-1.3
+	dep5VersionedRE = regexp.MustCompile(`^(A?GPL|LGPL|GFDL)-(\d)(\.\d)?(\+)?$`)
+	// dep5ExceptionRE matches ", with OpenSSL exception" style suffixes which have no license ID
+	dep5ExceptionRE = regexp.MustCompile(`(?i),?\s+with\s+.*?exception`)
+
+	// dep5Names are the DEP-5 short names which differ from the SPDX IDs
+	dep5Names = map[string]string{
+		"expat":    "MIT",
+		"perl":     "Artistic-1.0-Perl OR GPL-1.0-or-later",
+		"zope-2.1": "ZPL-2.1",
+		"zope-2.0": "ZPL-2.0",
+		"zope-1.1": "ZPL-1.1",
+	}
+)
+
+// DEP5 is a parsed machine-readable debian/copyright file
+type DEP5 struct {
+	Format       string
+	UpstreamName string
+	Source       string
+	Files        []DEP5Files
+	// Licenses are the stand-alone license paragraphs (license text referenced by short name)
+	Licenses []DEP5License
+}
+
+// DEP5Files is a Files paragraph which declares the license of the files matching the patterns
+type DEP5Files struct {
+	Files     []string
+	Copyright string
+	// License is the DEP-5 short name or expression (e.g., "GPL-2+ or Artistic-2.0")
+	License string
+	// LicenseText is the license text, if the paragraph includes it
+	LicenseText string
+}
+
+// DEP5License is a stand-alone License paragraph
+type DEP5License struct {
+	License string
+	Text    string
+}
+
+// IsDebianCopyright returns true for debian/copyright paths
+func IsDebianCopyright(filePath string) bool {
+	return filepath.Base(filePath) == DebianCopyright && filepath.Base(filepath.Dir(filePath)) == debianDir
+}
+
+// ParseDEP5File reads a machine-readable debian/copyright file
+func ParseDEP5File(filePath string) (*DEP5, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	d, err := ParseDEP5(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %v: %w", filePath, err)
+	}
+	return d, nil
+}
+
+// ParseDEP5 parses the paragraphs of a machine-readable debian/copyright file
+func ParseDEP5(r io.Reader) (*DEP5, error) {
+	paragraphs, err := parseDeb822(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(paragraphs) == 0 || paragraphs[0]["Format"] == "" {
+		return nil, ErrNotDEP5
+	}
+	header := paragraphs[0]
+	d := &DEP5{Format: header["Format"], UpstreamName: header["Upstream-Name"], Source: header["Source"]}
+	for _, p := range paragraphs[1:] {
+		license, text, _ := strings.Cut(p["License"], "\n")
+		license = strings.TrimSpace(license)
+		if files, ok := p["Files"]; ok {
+			d.Files = append(d.Files, DEP5Files{Files: strings.Fields(files), Copyright: p["Copyright"], License: license, LicenseText: text})
+		} else if license != "" {
+			d.Licenses = append(d.Licenses, DEP5License{License: license, Text: text})
+		}
+	}
+	return d, nil
+}
+
+// parseDeb822 returns the fields of each paragraph. Continuation lines are joined with "\n" and
+// a continuation line of "." is an empty line.
+func parseDeb822(r io.Reader) ([]map[string]string, error) {
+	var paragraphs []map[string]string
+	var p map[string]string
+	key := ""
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			p = nil
+			continue
+		case strings.HasPrefix(line, "#"):
+			continue
+		}
+		if p == nil {
+			p = make(map[string]string)
+			paragraphs = append(paragraphs, p)
+			key = ""
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if key == "" {
+				return nil, fmt.Errorf("continuation line without a field: %q", line)
+			}
+			value := strings.TrimSpace(line)
+			if value == "." {
+				value = ""
+			}
+			p[key] += "\n" + value
+			continue
+		}
+		k, v, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("expected a field: %q", line)
+		}
+		key = strings.TrimSpace(k)
+		p[key] = strings.TrimSpace(v)
+	}
+	return paragraphs, scanner.Err()
+}
+
+// FilesFor returns the Files paragraph for a path relative to the source root.
+// As specified by DEP-5, the last matching paragraph wins.
+func (d *DEP5) FilesFor(relPath string) (DEP5Files, bool) {
+	relPath = filepath.ToSlash(relPath)
+	for i := len(d.Files) - 1; i >= 0; i-- {
+		for _, pattern := range d.Files[i].Files {
+			if dep5Match(pattern, relPath) {
+				return d.Files[i], true
+			}
+		}
+	}
+	return DEP5Files{}, false
+}
+
+// dep5Match matches DEP-5 Files patterns where * (including /) and ? are the only wildcards
+func dep5Match(pattern string, relPath string) bool {
+	pattern = strings.TrimPrefix(pattern, "./")
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	re, err := regexp.Compile(sb.String())
+	return err == nil && re.MatchString(relPath)
+}
+
+// DEP5ToSPDX converts a DEP-5 license short name or expression to an SPDX expression
+func DEP5ToSPDX(expression string) string {
+	expression = dep5ExceptionRE.ReplaceAllString(expression, "")
+	var ret []string
+	for _, token := range strings.Fields(strings.ReplaceAll(expression, ",", " ")) {
+		switch lower := strings.ToLower(token); {
+		case lower == "or" || lower == "and":
+			ret = append(ret, strings.ToUpper(token))
+		case strings.Contains(dep5Names[lower], " "):
+			ret = append(ret, "("+dep5Names[lower]+")")
+		case dep5Names[lower] != "":
+			ret = append(ret, dep5Names[lower])
+		default:
+			if m := dep5VersionedRE.FindStringSubmatch(token); m != nil {
+				minor := m[3]
+				if minor == "" {
+					minor = ".0"
+				}
+				suffix := "-only"
+				if m[4] == "+" {
+					suffix = "-or-later"
+				}
+				token = m[1] + "-" + m[2] + minor + suffix
+			}
+			ret = append(ret, token)
+		}
+	}
+	return strings.Join(ret, " ")
+}
+
+// CompareWithDEP5 compares the license declared by the debian/copyright Files paragraphs with the
+// licenses detected in each file (under the parent of the debian dir) where licenses were detected
+func CompareWithDEP5(copyrightPath string, results []identifier.IdentifierResults, ll *licenses.LicenseLibrary) ([]Comparison, error) {
+	d, err := ParseDEP5File(copyrightPath)
+	if err != nil {
+		return nil, err
+	}
+	root := filepath.Dir(filepath.Dir(copyrightPath))
+
+	var ret []Comparison
+	for _, r := range results {
+		if len(r.Matches) == 0 || r.File == copyrightPath {
+			continue
+		}
+		rel, err := filepath.Rel(root, r.File)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		files, ok := d.FilesFor(rel)
+		if !ok {
+			continue
+		}
+		var detected []string
+		for id := range r.Matches {
+			detected = append(detected, id)
+		}
+		declared := Declared{File: copyrightPath, Ecosystem: Debian, Name: filepath.ToSlash(rel), Licenses: nonEmpty(DEP5ToSPDX(files.License))}
+		ret = append(ret, Compare(declared, detected, ll))
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Declared.Name < ret[j].Declared.Name })
+	return ret, nil
+}
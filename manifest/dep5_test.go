@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package manifest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestParseDEP5(t *testing.T) {
+	t.Parallel()
+	got, err := ParseDEP5File("../testdata/manifest/dep5/debian/copyright")
+	if err != nil {
+		t.Fatalf("ParseDEP5File() error = %v", err)
+	}
+	expected := &DEP5{
+		Format:       DEP5Format,
+		UpstreamName: "demo",
+		Source:       "https://example.com/demo",
+		Files: []DEP5Files{
+			{Files: []string{"*"}, Copyright: "2020 Someone", License: "Expat"},
+			{Files: []string{"vendor/*"}, Copyright: "2019 Someone Else", License: "BSD-3-clause"},
+		},
+		Licenses: []DEP5License{{License: "Expat", Text: "See the LICENSE file.\n\n(The text is not repeated here.)"}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("ParseDEP5File() mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := ParseDEP5(strings.NewReader("This is a free-form copyright file.\n")); err == nil {
+		t.Error("ParseDEP5() expected an error for a free-form file")
+	}
+}
+
+func TestDEP5FilesFor(t *testing.T) {
+	t.Parallel()
+	d := &DEP5{Files: []DEP5Files{
+		{Files: []string{"*"}, License: "Expat"},
+		{Files: []string{"src/*.c", "doc/???.txt"}, License: "GPL-2+"},
+		{Files: []string{`weird\*name`}, License: "ISC"},
+	}}
+	tests := map[string]string{
+		"README":       "Expat",
+		"src/a.c":      "GPL-2+",
+		"src/sub/b.c":  "GPL-2+",
+		"src/a.h":      "Expat",
+		"doc/abc.txt":  "GPL-2+",
+		"doc/abcd.txt": "Expat",
+		"weird*name":   "ISC",
+		"weirdXname":   "Expat",
+	}
+	for relPath, expected := range tests {
+		got, ok := d.FilesFor(relPath)
+		if !ok || got.License != expected {
+			t.Errorf("FilesFor(%v) expected %v got %v", relPath, expected, got.License)
+		}
+	}
+}
+
+func TestDEP5ToSPDX(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"Expat":                                "MIT",
+		"Perl":                                 "(Artistic-1.0-Perl OR GPL-1.0-or-later)",
+		"GPL-2+":                               "GPL-2.0-or-later",
+		"LGPL-2.1":                             "LGPL-2.1-only",
+		"GPL-2+ or Artistic-2.0":               "GPL-2.0-or-later OR Artistic-2.0",
+		"GPL-3+ with OpenSSL exception":        "GPL-3.0-or-later",
+		"Apache-2.0 and BSD-3-clause, and MIT": "Apache-2.0 AND BSD-3-clause AND MIT",
+	}
+	for dep5, expected := range tests {
+		if got := DEP5ToSPDX(dep5); got != expected {
+			t.Errorf("DEP5ToSPDX(%v) expected %v got %v", dep5, expected, got)
+		}
+	}
+}
+
+func TestCompareWithDEP5(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	results, err := identifier.IdentifyLicensesInDirectory("../testdata/manifest/dep5", identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyLicensesInDirectory() error = %v", err)
+	}
+	comparisons := CompareWithResults(results, ll)
+
+	type summary struct {
+		Name    string
+		Agrees  bool
+		Missing []string
+	}
+	var got []summary
+	for _, c := range comparisons {
+		got = append(got, summary{Name: c.Declared.Name, Agrees: c.Agrees(), Missing: c.Missing})
+	}
+	expected := []summary{
+		{Name: "LICENSE", Agrees: true},
+		{Name: "vendor/x/COPYING", Agrees: false, Missing: []string{"BSD-3-Clause"}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("CompareWithResults() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestWriteDEP5(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{File: "root/LICENSE", Matches: map[string][]identifier.Match{"MIT": nil}, CopyRightStatements: []identifier.PatternMatch{{Text: "Copyright 2020 Someone"}}},
+		{File: "root/main.go"},
+		{File: "root/vendor/x/COPYING", Matches: map[string][]identifier.Match{"ISC": nil, "MIT": nil}},
+		{File: "root/a*b", Matches: map[string][]identifier.Match{"MIT": nil}},
+		{File: "root/EULA", ExtractedLicense: &identifier.ExtractedLicense{ID: "LicenseRef-0123456789abcdef", Text: "ACME EULA\n\nNo warranty."}},
+	}
+	var buf bytes.Buffer
+	if err := WriteDEP5(&buf, "root", "demo", results); err != nil {
+		t.Fatalf("WriteDEP5() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"Upstream-Name: demo\n",
+		"\nFiles: LICENSE\n a\\*b\nCopyright: Copyright 2020 Someone\nLicense: MIT\n",
+		"\nFiles: vendor/x/COPYING\nCopyright: TODO\nLicense: ISC and MIT\n",
+		"\nLicense: ISC\n TODO: add the ISC license text\n",
+		"\nFiles: EULA\nCopyright: TODO\nLicense: LicenseRef-0123456789abcdef\n",
+		"\nLicense: LicenseRef-0123456789abcdef\n ACME EULA\n .\n No warranty.\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteDEP5() expected %q in:\n%s", want, got)
+		}
+	}
+
+	d, err := ParseDEP5(&buf)
+	if err != nil {
+		t.Fatalf("ParseDEP5() cannot read the written file: %v", err)
+	}
+	if f, ok := d.FilesFor("a*b"); !ok || f.License != "MIT" {
+		t.Errorf("FilesFor(a*b) expected MIT got %+v", f)
+	}
+	if f, ok := d.FilesFor("EULA"); !ok || f.License != "LicenseRef-0123456789abcdef" {
+		t.Errorf("FilesFor(EULA) expected LicenseRef-0123456789abcdef got %+v", f)
+	}
+}
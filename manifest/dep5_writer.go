@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// dep5Placeholder marks the values a packager needs to fill in
+const dep5Placeholder = "TODO"
+
+// WriteDEP5 writes a machine-readable debian/copyright skeleton from the scan results.
+// Files under root with the same detected licenses are grouped in one Files paragraph, and
+// a stand-alone License paragraph is written for each license (with the text left to fill in). The files of
+// license-like text which matched no license have its LicenseRef, with the extracted text in its paragraph.
+func WriteDEP5(w io.Writer, root string, upstreamName string, results []identifier.IdentifierResults) error {
+	type group struct {
+		files      []string
+		copyrights []string
+	}
+	groups := make(map[string]*group)
+	var ids []string
+	// texts are the extracted texts of the LicenseRefs of the license-like text
+	texts := make(map[string]string)
+	for _, r := range results {
+		if (len(r.Matches) == 0 && r.ExtractedLicense == nil) || IsDebianCopyright(r.File) {
+			continue
+		}
+		rel, err := filepath.Rel(root, r.File)
+		if err != nil {
+			return err
+		}
+		var found []string
+		for id := range r.Matches {
+			found = append(found, id)
+			if !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+		if e := r.ExtractedLicense; len(r.Matches) == 0 {
+			found = append(found, e.ID)
+			if !slices.Contains(ids, e.ID) {
+				ids = append(ids, e.ID)
+				texts[e.ID] = e.Text
+			}
+		}
+		sort.Strings(found)
+		license := strings.Join(found, " and ")
+		g := groups[license]
+		if g == nil {
+			g = &group{}
+			groups[license] = g
+		}
+		g.files = append(g.files, dep5Escape(filepath.ToSlash(rel)))
+		for _, c := range r.CopyRightStatements {
+			if text := strings.TrimSpace(c.Text); text != "" && !slices.Contains(g.copyrights, text) {
+				g.copyrights = append(g.copyrights, text)
+			}
+		}
+	}
+
+	if upstreamName == "" {
+		upstreamName = dep5Placeholder
+	}
+	if _, err := fmt.Fprintf(w, "Format: %v\nUpstream-Name: %v\nSource: %v\n", DEP5Format, upstreamName, dep5Placeholder); err != nil {
+		return err
+	}
+
+	var licenses []string
+	for license := range groups {
+		licenses = append(licenses, license)
+	}
+	sort.Strings(licenses)
+	for _, license := range licenses {
+		g := groups[license]
+		sort.Strings(g.files)
+		sort.Strings(g.copyrights)
+		if len(g.copyrights) == 0 {
+			g.copyrights = []string{dep5Placeholder}
+		}
+		if _, err := fmt.Fprintf(w, "\nFiles: %v\nCopyright: %v\nLicense: %v\n", dep5Field(g.files), dep5Field(g.copyrights), license); err != nil {
+			return err
+		}
+	}
+
+	sort.Strings(ids)
+	for _, id := range ids {
+		text, ok := texts[id]
+		if !ok {
+			text = fmt.Sprintf("%v: add the %v license text", dep5Placeholder, id)
+		}
+		if _, err := fmt.Fprintf(w, "\nLicense: %v\n %v\n", id, dep5Text(text)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dep5Text formats a text as the continuation lines of a field value (with the empty lines as ".")
+func dep5Text(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line = strings.TrimRight(line, " \t\r"); line == "" {
+			line = "."
+		}
+		lines[i] = line
+	}
+	return dep5Field(lines)
+}
+
+// dep5Field formats a multi-line field value with continuation lines
+func dep5Field(lines []string) string {
+	return strings.Join(lines, "\n ")
+}
+
+// dep5Escape escapes the DEP-5 wildcards (and backslash) in a file name
+func dep5Escape(name string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`).Replace(name)
+}
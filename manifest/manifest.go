@@ -0,0 +1,469 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PackageJSON = "package.json"
+	SetupCfg    = "setup.cfg"
+	PyProject   = "pyproject.toml"
+	PomXML      = "pom.xml"
+	CargoTOML   = "Cargo.toml"
+	Metadata    = "METADATA" // Python wheel (.dist-info) core metadata
+	PkgInfo     = "PKG-INFO" // Python sdist (and .egg-info) core metadata
+	Gemspec     = ".gemspec" // suffix of a Ruby gem specification (Ruby source)
+	NuSpec      = ".nuspec"  // suffix of a NuGet package specification
+
+	NPM      = "npm"
+	PyPI     = "pypi"
+	Maven    = "maven"
+	Cargo    = "cargo"
+	RubyGems = "rubygems"
+	NuGet    = "nuget"
+	Debian   = "debian"
+
+	classifierPrefix = "License ::"
+)
+
+// Declared holds the package identity and license values declared in a package manifest
+type Declared struct {
+	// File is the path to the manifest
+	File string
+	// Ecosystem is the package manager the manifest belongs to, for example, "npm"
+	Ecosystem string
+	Name      string
+	Version   string
+	// Licenses are the license values as declared (IDs, expressions, names, or classifiers)
+	Licenses []string
+	// LicenseFile is the path (relative to the package root) of a license file named by the manifest, if any
+	LicenseFile string
+}
+
+var (
+	// gemspec assignments like `spec.license = "MIT"` or `s.licenses = ["MIT", "Ruby"]`
+	gemspecLicenseRE = regexp.MustCompile(`\.licenses?\s*=\s*(.+)`)
+	gemspecNameRE    = regexp.MustCompile(`\.name\s*=\s*["']([^"']+)["']`)
+	gemspecVersionRE = regexp.MustCompile(`\.version\s*=\s*["']([^"']+)["']`)
+	quotedRE         = regexp.MustCompile(`["']([^"']+)["']`)
+)
+
+// IsManifest returns true when the file name is a supported package manifest
+func IsManifest(filePath string) bool {
+	switch filepath.Base(filePath) {
+	case PackageJSON, SetupCfg, PyProject, PomXML, CargoTOML, Metadata, PkgInfo:
+		return true
+	}
+	return strings.HasSuffix(filePath, Gemspec) || strings.HasSuffix(filePath, NuSpec)
+}
+
+// Parse reads the manifest file and returns its declared licenses
+func Parse(filePath string) (*Declared, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBytes(filePath, b)
+}
+
+// ParseBytes parses manifest content. The base name of filePath selects the parser.
+func ParseBytes(filePath string, b []byte) (*Declared, error) {
+	var d *Declared
+	var err error
+	switch filepath.Base(filePath) {
+	case PackageJSON:
+		d, err = parsePackageJSON(b)
+	case SetupCfg:
+		d, err = parseSetupCfg(b)
+	case PyProject:
+		d, err = parsePyProject(b)
+	case PomXML:
+		d, err = parsePomXML(b)
+	case CargoTOML:
+		d, err = parseCargoTOML(b)
+	case Metadata, PkgInfo:
+		d, err = parsePkgInfo(b)
+	default:
+		switch {
+		case strings.HasSuffix(filePath, Gemspec):
+			d, err = parseGemspec(b)
+		case strings.HasSuffix(filePath, NuSpec):
+			d, err = parseNuSpec(b)
+		default:
+			return nil, fmt.Errorf("unsupported manifest %v", filePath)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse manifest %v: %w", filePath, err)
+	}
+	d.File = filePath
+	return d, nil
+}
+
+type packageJSON struct {
+	Name     string          `json:"name"`
+	Version  string          `json:"version"`
+	License  json.RawMessage `json:"license"`
+	Licenses json.RawMessage `json:"licenses"`
+}
+
+// typedLicense is the legacy npm {"type": "MIT", "url": "..."} form
+type typedLicense struct {
+	Type string `json:"type"`
+}
+
+func parsePackageJSON(b []byte) (*Declared, error) {
+	var p packageJSON
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	d := &Declared{Ecosystem: NPM, Name: p.Name, Version: p.Version}
+	d.Licenses = append(d.Licenses, npmLicenseValues(p.License)...)
+	d.Licenses = append(d.Licenses, npmLicenseValues(p.Licenses)...)
+	return d, nil
+}
+
+// npmLicenseValues accepts a string, a {type} object, or an array of either
+func npmLicenseValues(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return nonEmpty(s)
+	}
+	var t typedLicense
+	if err := json.Unmarshal(raw, &t); err == nil {
+		return nonEmpty(t.Type)
+	}
+	var many []json.RawMessage
+	if err := json.Unmarshal(raw, &many); err == nil {
+		var ret []string
+		for _, m := range many {
+			ret = append(ret, npmLicenseValues(m)...)
+		}
+		return ret
+	}
+	return nil
+}
+
+func parseSetupCfg(b []byte) (*Declared, error) {
+	d := &Declared{Ecosystem: PyPI}
+	section := ""
+	key := ""
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+			continue
+		case strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]"):
+			section = strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			key = ""
+			continue
+		}
+		if section != "metadata" {
+			continue
+		}
+
+		// Indented lines continue a multi-line value (e.g., classifiers)
+		if line[0] == ' ' || line[0] == '\t' {
+			if key == "classifiers" {
+				d.Licenses = append(d.Licenses, classifierLicense(trimmed)...)
+			}
+			continue
+		}
+
+		k, v, found := strings.Cut(trimmed, "=")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		v = strings.TrimSpace(v)
+		switch key {
+		case "name":
+			d.Name = v
+		case "version":
+			d.Version = v
+		case "license":
+			d.Licenses = append(d.Licenses, nonEmpty(v)...)
+		case "classifiers":
+			d.Licenses = append(d.Licenses, classifierLicense(v)...)
+		}
+	}
+	return d, scanner.Err()
+}
+
+type pyProject struct {
+	Project struct {
+		Name        string      `toml:"name"`
+		Version     string      `toml:"version"`
+		License     interface{} `toml:"license"`
+		Classifiers []string    `toml:"classifiers"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			Name        string   `toml:"name"`
+			Version     string   `toml:"version"`
+			License     string   `toml:"license"`
+			Classifiers []string `toml:"classifiers"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+func parsePyProject(b []byte) (*Declared, error) {
+	var p pyProject
+	if err := toml.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	d := &Declared{Ecosystem: PyPI, Name: p.Project.Name, Version: p.Project.Version}
+	switch license := p.Project.License.(type) {
+	case string:
+		d.Licenses = append(d.Licenses, nonEmpty(license)...)
+	case map[string]interface{}:
+		// PEP 621 allows {text = "..."} or {file = "..."}. Only the text can be compared.
+		if text, ok := license["text"].(string); ok {
+			d.Licenses = append(d.Licenses, nonEmpty(text)...)
+		}
+		if file, ok := license["file"].(string); ok {
+			d.LicenseFile = file
+		}
+	}
+	for _, c := range p.Project.Classifiers {
+		d.Licenses = append(d.Licenses, classifierLicense(c)...)
+	}
+
+	poetry := p.Tool.Poetry
+	if d.Name == "" {
+		d.Name = poetry.Name
+	}
+	if d.Version == "" {
+		d.Version = poetry.Version
+	}
+	d.Licenses = append(d.Licenses, nonEmpty(poetry.License)...)
+	for _, c := range poetry.Classifiers {
+		d.Licenses = append(d.Licenses, classifierLicense(c)...)
+	}
+	return d, nil
+}
+
+type pomXML struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Parent     struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Licenses []struct {
+		Name string `xml:"name"`
+		URL  string `xml:"url"`
+	} `xml:"licenses>license"`
+}
+
+func parsePomXML(b []byte) (*Declared, error) {
+	var p pomXML
+	if err := xml.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	// groupId and version are inherited from the parent when not declared
+	groupID := p.GroupID
+	if groupID == "" {
+		groupID = p.Parent.GroupID
+	}
+	version := p.Version
+	if version == "" {
+		version = p.Parent.Version
+	}
+	d := &Declared{Ecosystem: Maven, Name: groupID + ":" + p.ArtifactID, Version: version}
+	for _, l := range p.Licenses {
+		// Prefer the name, but a URL is better than nothing
+		name := strings.TrimSpace(l.Name)
+		if name == "" {
+			name = strings.TrimSpace(l.URL)
+		}
+		d.Licenses = append(d.Licenses, nonEmpty(name)...)
+	}
+	return d, nil
+}
+
+type cargoTOML struct {
+	Package struct {
+		Name    string `toml:"name"`
+		Version string `toml:"version"`
+		License string `toml:"license"`
+	} `toml:"package"`
+}
+
+func parseCargoTOML(b []byte) (*Declared, error) {
+	var c cargoTOML
+	if err := toml.Unmarshal(b, &c); err != nil {
+		return nil, err
+	}
+	// Older crates use "/" as a separator, so "MIT/Apache-2.0" means "MIT OR Apache-2.0"
+	license := strings.ReplaceAll(c.Package.License, "/", " OR ")
+	return &Declared{Ecosystem: Cargo, Name: c.Package.Name, Version: c.Package.Version, Licenses: nonEmpty(license)}, nil
+}
+
+// parsePkgInfo reads the email-header style Python core metadata (METADATA or PKG-INFO)
+func parsePkgInfo(b []byte) (*Declared, error) {
+	d := &Declared{Ecosystem: PyPI}
+	key := ""
+	var license []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break // the headers end at the first blank line (the description may follow)
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			// Continuation lines are only expected for a multi-line License value
+			if key == "License" {
+				license = append(license, strings.TrimSpace(line))
+			}
+			continue
+		}
+		k, v, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(k)
+		v = strings.TrimSpace(v)
+		switch key {
+		case "Name":
+			d.Name = v
+		case "Version":
+			d.Version = v
+		case "License", "License-Expression":
+			if v != "" && v != "UNKNOWN" {
+				license = append(license, v)
+			}
+		case "Classifier":
+			d.Licenses = append(d.Licenses, classifierLicense(v)...)
+		}
+	}
+	if len(license) > 0 {
+		d.Licenses = append(nonEmpty(strings.Join(license, "\n")), d.Licenses...)
+	}
+	return d, scanner.Err()
+}
+
+// parseGemspec scans the Ruby source of a .gemspec for literal name, version, and license assignments.
+// Values that are not string literals (e.g., Foo::VERSION) are not evaluated.
+func parseGemspec(b []byte) (*Declared, error) {
+	d := &Declared{Ecosystem: RubyGems}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if m := gemspecNameRE.FindStringSubmatch(line); m != nil && d.Name == "" {
+			d.Name = m[1]
+		}
+		if m := gemspecVersionRE.FindStringSubmatch(line); m != nil && d.Version == "" {
+			d.Version = m[1]
+		}
+		if m := gemspecLicenseRE.FindStringSubmatch(line); m != nil {
+			for _, q := range quotedRE.FindAllStringSubmatch(m[1], -1) {
+				d.Licenses = append(d.Licenses, nonEmpty(q[1])...)
+			}
+		}
+	}
+	return d, scanner.Err()
+}
+
+type gemMetadata struct {
+	Name    string `yaml:"name"`
+	Version struct {
+		Version string `yaml:"version"`
+	} `yaml:"version"`
+	Licenses []string `yaml:"licenses"`
+}
+
+// ParseGemMetadata parses the YAML gem specification that is stored (gzipped) as metadata.gz in a .gem
+func ParseGemMetadata(filePath string, b []byte) (*Declared, error) {
+	var m gemMetadata
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("cannot parse manifest %v: %w", filePath, err)
+	}
+	d := &Declared{File: filePath, Ecosystem: RubyGems, Name: m.Name, Version: m.Version.Version}
+	for _, l := range m.Licenses {
+		d.Licenses = append(d.Licenses, nonEmpty(l)...)
+	}
+	return d, nil
+}
+
+type nuSpec struct {
+	Metadata struct {
+		ID      string `xml:"id"`
+		Version string `xml:"version"`
+		License struct {
+			Type  string `xml:"type,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"license"`
+		LicenseURL string `xml:"licenseUrl"`
+	} `xml:"metadata"`
+}
+
+// deprecatedNuGetLicenseURL is the placeholder licenseUrl written by tools when a license element is used
+const deprecatedNuGetLicenseURL = "https://aka.ms/deprecateLicenseUrl"
+
+func parseNuSpec(b []byte) (*Declared, error) {
+	var n nuSpec
+	if err := xml.Unmarshal(b, &n); err != nil {
+		return nil, err
+	}
+	m := n.Metadata
+	d := &Declared{Ecosystem: NuGet, Name: m.ID, Version: m.Version}
+	license := strings.TrimSpace(m.License.Value)
+	switch m.License.Type {
+	case "expression":
+		d.Licenses = append(d.Licenses, nonEmpty(license)...)
+	case "file":
+		d.LicenseFile = license
+	}
+	if len(d.Licenses) == 0 && d.LicenseFile == "" {
+		if url := strings.TrimSpace(m.LicenseURL); url != deprecatedNuGetLicenseURL {
+			d.Licenses = append(d.Licenses, nonEmpty(url)...)
+		}
+	}
+	return d, nil
+}
+
+// classifierLicense returns the license name from a trove classifier like "License :: OSI Approved :: MIT License"
+func classifierLicense(classifier string) []string {
+	classifier = strings.TrimSpace(classifier)
+	if !strings.HasPrefix(classifier, classifierPrefix) {
+		return nil
+	}
+	parts := strings.Split(classifier, "::")
+	last := strings.TrimSpace(parts[len(parts)-1])
+	if last == "OSI Approved" {
+		return nil // too vague to compare
+	}
+	return nonEmpty(last)
+}
+
+func nonEmpty(s string) []string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,204 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBytes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected *Declared
+		wantErr  bool
+	}{
+		{
+			name:     "package.json license string",
+			file:     "a/package.json",
+			content:  `{"name": "async", "version": "3.2.4", "license": "MIT"}`,
+			expected: &Declared{File: "a/package.json", Ecosystem: NPM, Name: "async", Version: "3.2.4", Licenses: []string{"MIT"}},
+		},
+		{
+			name:     "package.json legacy licenses array",
+			file:     "package.json",
+			content:  `{"name": "old", "licenses": [{"type": "MIT", "url": "x"}, {"type": "Apache-2.0"}]}`,
+			expected: &Declared{File: "package.json", Ecosystem: NPM, Name: "old", Licenses: []string{"MIT", "Apache-2.0"}},
+		},
+		{
+			name:     "package.json license object",
+			file:     "package.json",
+			content:  `{"name": "obj", "license": {"type": "ISC"}}`,
+			expected: &Declared{File: "package.json", Ecosystem: NPM, Name: "obj", Licenses: []string{"ISC"}},
+		},
+		{
+			name: "setup.cfg license and classifiers",
+			file: "setup.cfg",
+			content: `[metadata]
+name = requests
+version = 2.28.1
+license = Apache 2.0
+classifiers =
+    Programming Language :: Python
+    License :: OSI Approved :: Apache Software License
+
+[options]
+license = ignored
+`,
+			expected: &Declared{File: "setup.cfg", Ecosystem: PyPI, Name: "requests", Version: "2.28.1", Licenses: []string{"Apache 2.0", "Apache Software License"}},
+		},
+		{
+			name: "pyproject.toml PEP 621 text",
+			file: "pyproject.toml",
+			content: `[project]
+name = "spam"
+version = "1.0"
+license = {text = "BSD-3-Clause"}
+classifiers = ["License :: OSI Approved", "License :: OSI Approved :: BSD License"]
+`,
+			expected: &Declared{File: "pyproject.toml", Ecosystem: PyPI, Name: "spam", Version: "1.0", Licenses: []string{"BSD-3-Clause", "BSD License"}},
+		},
+		{
+			name: "pyproject.toml poetry",
+			file: "pyproject.toml",
+			content: `[tool.poetry]
+name = "eggs"
+version = "0.2"
+license = "MIT"
+`,
+			expected: &Declared{File: "pyproject.toml", Ecosystem: PyPI, Name: "eggs", Version: "0.2", Licenses: []string{"MIT"}},
+		},
+		{
+			name: "pom.xml with parent",
+			file: "pom.xml",
+			content: `<project>
+  <parent><groupId>org.example</groupId><version>1.2.3</version></parent>
+  <artifactId>child</artifactId>
+  <licenses>
+    <license><name>The Apache Software License, Version 2.0</name><url>https://www.apache.org/licenses/LICENSE-2.0.txt</url></license>
+    <license><url>https://opensource.org/licenses/MIT</url></license>
+  </licenses>
+</project>`,
+			expected: &Declared{File: "pom.xml", Ecosystem: Maven, Name: "org.example:child", Version: "1.2.3", Licenses: []string{"The Apache Software License, Version 2.0", "https://opensource.org/licenses/MIT"}},
+		},
+		{
+			name: "Cargo.toml legacy slash",
+			file: "Cargo.toml",
+			content: `[package]
+name = "serde"
+version = "1.0.0"
+license = "MIT/Apache-2.0"
+`,
+			expected: &Declared{File: "Cargo.toml", Ecosystem: Cargo, Name: "serde", Version: "1.0.0", Licenses: []string{"MIT OR Apache-2.0"}},
+		},
+		{
+			name: "METADATA license expression and classifiers",
+			file: "x.whl!/x-1.0.dist-info/METADATA",
+			content: `Metadata-Version: 2.4
+Name: x
+Version: 1.0
+License-Expression: MIT OR Apache-2.0
+Classifier: Programming Language :: Python
+Classifier: License :: OSI Approved :: MIT License
+
+License: this is the description, not a header
+`,
+			expected: &Declared{File: "x.whl!/x-1.0.dist-info/METADATA", Ecosystem: PyPI, Name: "x", Version: "1.0", Licenses: []string{"MIT OR Apache-2.0", "MIT License"}},
+		},
+		{
+			name: "PKG-INFO UNKNOWN license",
+			file: "PKG-INFO",
+			content: `Metadata-Version: 1.1
+Name: y
+Version: 2.0
+License: UNKNOWN
+`,
+			expected: &Declared{File: "PKG-INFO", Ecosystem: PyPI, Name: "y", Version: "2.0"},
+		},
+		{
+			name: "gemspec licenses",
+			file: "demo.gemspec",
+			content: `Gem::Specification.new do |spec|
+  spec.name    = "demo"
+  spec.version = Demo::VERSION
+  # spec.license = "GPL-2.0"
+  spec.licenses = ["MIT", 'Ruby']
+end
+`,
+			expected: &Declared{File: "demo.gemspec", Ecosystem: RubyGems, Name: "demo", Licenses: []string{"MIT", "Ruby"}},
+		},
+		{
+			name: "nuspec license file",
+			file: "Demo.nuspec",
+			content: `<?xml version="1.0"?>
+<package xmlns="http://schemas.microsoft.com/packaging/2013/05/nuspec.xsd">
+  <metadata>
+    <id>Demo</id>
+    <version>1.0.0</version>
+    <license type="file">LICENSE.txt</license>
+    <licenseUrl>https://aka.ms/deprecateLicenseUrl</licenseUrl>
+  </metadata>
+</package>`,
+			expected: &Declared{File: "Demo.nuspec", Ecosystem: NuGet, Name: "Demo", Version: "1.0.0", LicenseFile: "LICENSE.txt"},
+		},
+		{
+			name: "nuspec legacy licenseUrl",
+			file: "Old.nuspec",
+			content: `<package><metadata><id>Old</id><version>0.1</version>
+<licenseUrl>https://opensource.org/licenses/MIT</licenseUrl></metadata></package>`,
+			expected: &Declared{File: "Old.nuspec", Ecosystem: NuGet, Name: "Old", Version: "0.1", Licenses: []string{"https://opensource.org/licenses/MIT"}},
+		},
+		{
+			name:    "invalid json",
+			file:    "package.json",
+			content: `{`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported",
+			file:    "build.gradle",
+			content: ``,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseBytes(tt.file, []byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseBytes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.expected, got); d != "" {
+				t.Errorf("ParseBytes() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestParseGemMetadata(t *testing.T) {
+	t.Parallel()
+	content := `--- !ruby/object:Gem::Specification
+name: demo
+version: !ruby/object:Gem::Version
+  version: 0.3.0
+date: 2020-01-01 00:00:00.000000000 Z
+licenses:
+- MIT
+- Ruby
+`
+	got, err := ParseGemMetadata("demo.gem!/metadata.gz", []byte(content))
+	if err != nil {
+		t.Fatalf("ParseGemMetadata() error = %v", err)
+	}
+	expected := &Declared{File: "demo.gem!/metadata.gz", Ecosystem: RubyGems, Name: "demo", Version: "0.3.0", Licenses: []string{"MIT", "Ruby"}}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("ParseGemMetadata() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metadata describes a scan: the scanner and license list versions, the configuration and resources
+// it used, when it ran, and on which host, so that the results of a report can be audited later.
+//
+// The text report ends with a summary of the metadata, and the licensee, jsonl, template, and junit reports include
+// it (the GitHub Actions annotations do not). The library creates the metadata of a scan with New.
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/version"
+)
+
+// Metadata is the metadata of a scan
+type Metadata struct {
+	// Version is the build information of the scanner with the SPDX license list of the scan
+	Version version.Info `json:"version"`
+	// ConfigHash is the SHA-256 of the configuration (the settings of the flags and the config file, as JSON)
+	ConfigHash string `json:"configHash"`
+	// Resources are the checksums of the SPDX and custom resources (or of the compiled library) of the scan
+	Resources []Checksum `json:"resources"`
+	// Start and End are the times of the scan (UTC, nil with --deterministic)
+	Start *time.Time `json:"start,omitempty"`
+	End   *time.Time `json:"end,omitempty"`
+	// Host is the host of the scan (nil with --deterministic)
+	Host *Host `json:"host,omitempty"`
+	// Incomplete is true when the scan was interrupted, so the results are only of the files scanned until then
+	Incomplete bool `json:"incomplete,omitempty"`
+	// Skipped is the number of the binary and media files which the --dir scan skipped by their type, by MIME type
+	// (without --allFiles)
+	Skipped map[string]int `json:"skipped,omitempty"`
+	// Quick is true when the --dir scan only scanned the license files whole and the headers of the other files
+	// (without --thorough)
+	Quick bool `json:"quick,omitempty"`
+}
+
+// Checksum is the SHA-256 of a resource directory (of the relative paths and the contents of its files) or file
+type Checksum struct {
+	// Name is the resource directory relative to the resources (e.g., spdx/default), or the compiled library file
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+}
+
+// Host is the host of a scan
+type Host struct {
+	Hostname string `json:"hostname"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	CPUs     int    `json:"cpus"`
+}
+
+// New returns the metadata of a scan which started at start and ends now. With the --deterministic config,
+// the times and the host are left out, so that the reports of the same files are identical.
+func New(cfg *viper.Viper, info version.Info, start time.Time) (Metadata, error) {
+	m := Metadata{Version: info}
+	var err error
+	if m.ConfigHash, err = ConfigHash(cfg); err != nil {
+		return m, err
+	}
+	if m.Resources, err = ResourceChecksums(cfg); err != nil {
+		return m, err
+	}
+	if !cfg.GetBool(configurer.DeterministicFlag) {
+		start, end := start.UTC(), time.Now().UTC()
+		m.Start, m.End = &start, &end
+		hostname, _ := os.Hostname()
+		m.Host = &Host{Hostname: hostname, OS: runtime.GOOS, Arch: runtime.GOARCH, CPUs: runtime.NumCPU()}
+	}
+	return m, nil
+}
+
+// ConfigHash returns the SHA-256 of the settings of the config (as JSON, with the keys sorted)
+func ConfigHash(cfg *viper.Viper) (string, error) {
+	b, err := json.Marshal(cfg.AllSettings())
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ResourceChecksums returns the checksums of the --spdx and --custom resource directories (a missing custom
+// directory is left out), or of the --compiled library file
+func ResourceChecksums(cfg *viper.Viper) ([]Checksum, error) {
+	if compiled := cfg.GetString(configurer.CompiledFlag); compiled != "" {
+		sum, err := ChecksumFile(compiled)
+		if err != nil {
+			return nil, err
+		}
+		return []Checksum{{Name: filepath.Base(compiled), SHA256: sum}}, nil
+	}
+	resources := cfg.GetString(licenses.Resources)
+	var ret []Checksum
+	for _, dir := range []string{
+		filepath.Join("spdx", cfg.GetString(configurer.SpdxFlag)),
+		filepath.Join("custom", cfg.GetString(configurer.CustomFlag)),
+	} {
+		sum, err := ChecksumDir(filepath.Join(resources, dir))
+		if os.IsNotExist(err) && filepath.Dir(dir) == "custom" {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, Checksum{Name: filepath.ToSlash(dir), SHA256: sum})
+	}
+	return ret, nil
+}
+
+// ChecksumDir returns the SHA-256 of the relative paths (with forward slashes) and the SHA-256 of the
+// contents of the files of the directory, in the order of the paths
+func ChecksumDir(dir string) (string, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		sum, err := ChecksumFile(p)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(h, filepath.ToSlash(rel)+"\x00"+sum+"\n")
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ChecksumFile returns the SHA-256 of the contents of the file
+func ChecksumFile(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/viper"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/version"
+)
+
+// testConfig returns the config of the resources in a temporary directory with an spdx/test directory (and
+// no custom directory)
+func testConfig(t *testing.T) (*viper.Viper, string) {
+	t.Helper()
+	resources := t.TempDir()
+	dir := filepath.Join(resources, "spdx", "test", "template")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "MIT.template.txt"), []byte("MIT License"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg := viper.New()
+	cfg.Set(licenses.Resources, resources)
+	cfg.Set(configurer.SpdxFlag, "test")
+	cfg.Set(configurer.CustomFlag, "missing")
+	return cfg, dir
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	cfg, _ := testConfig(t)
+	info := version.Info{Version: "1.2.3"}
+	start := time.Now()
+
+	m, err := New(cfg, info, start)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if m.Start == nil || m.End == nil || m.Host == nil {
+		t.Fatalf("New() = %+v, want the times and the host", m)
+	}
+	if m.End.Before(*m.Start) {
+		t.Errorf("New() end %v is before the start %v", m.End, m.Start)
+	}
+
+	cfg.Set(configurer.DeterministicFlag, true)
+	first, err := New(cfg, info, start)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if first.Start != nil || first.End != nil || first.Host != nil {
+		t.Errorf("New() = %+v, want no times and no host with --deterministic", first)
+	}
+	second, err := New(cfg, info, time.Now())
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if d := cmp.Diff(first, second); d != "" {
+		t.Errorf("New() is not deterministic (-first +second):\n%s", d)
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	t.Parallel()
+	cfg, _ := testConfig(t)
+	before, err := ConfigHash(cfg)
+	if err != nil {
+		t.Fatalf("ConfigHash() error = %v", err)
+	}
+	again, _ := ConfigHash(cfg)
+	if before != again {
+		t.Errorf("ConfigHash() = %v then %v, want the same hash", before, again)
+	}
+	cfg.Set(configurer.SpdxFlag, "other")
+	if after, _ := ConfigHash(cfg); after == before {
+		t.Errorf("ConfigHash() = %v after a setting changed, want a different hash", after)
+	}
+}
+
+func TestResourceChecksums(t *testing.T) {
+	t.Parallel()
+	cfg, dir := testConfig(t)
+	before, err := ResourceChecksums(cfg)
+	if err != nil {
+		t.Fatalf("ResourceChecksums() error = %v", err)
+	}
+	// The missing custom directory is left out
+	if d := cmp.Diff([]string{"spdx/test"}, names(before)); d != "" {
+		t.Errorf("ResourceChecksums() names mismatch (-want +got):\n%s", d)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "MIT.template.txt"), []byte("MIT License (changed)"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	after, err := ResourceChecksums(cfg)
+	if err != nil {
+		t.Fatalf("ResourceChecksums() error = %v", err)
+	}
+	if before[0].SHA256 == after[0].SHA256 {
+		t.Errorf("ResourceChecksums() = %v after a file changed, want a different checksum", after[0].SHA256)
+	}
+
+	cfg.Set(configurer.SpdxFlag, "missing")
+	if _, err := ResourceChecksums(cfg); err == nil {
+		t.Errorf("ResourceChecksums() error = nil, want an error for a missing spdx directory")
+	}
+}
+
+// names returns the names of the checksums
+func names(checksums []Checksum) []string {
+	ret := []string{}
+	for _, c := range checksums {
+		ret = append(ret, c.Name)
+	}
+	return ret
+}
@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package monorepo
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// RootProject is the project of the files which are not in any subproject
+const RootProject = "."
+
+// Summary is the aggregated licenses of a project
+type Summary struct {
+	// Project is the project root relative to the scanned directory (RootProject for the other files)
+	Project string
+	// Files is the number of scanned files in the project, and FilesWithLicenses of those with license matches
+	Files             int
+	FilesWithLicenses int
+	// Licenses has the number of files with each license ID
+	Licenses map[string]int
+}
+
+// IDs returns the license IDs of the project (sorted)
+func (s Summary) IDs() []string {
+	ret := make([]string, 0, len(s.Licenses))
+	for id := range s.Licenses {
+		ret = append(ret, id)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Aggregate summarizes the results of the scanned directory per project. Each file belongs to the
+// deepest project root which contains it, or to the RootProject. The summaries are in the order of the
+// project roots, followed by the RootProject (when it has files).
+func Aggregate(roots []string, results []identifier.IdentifierResults, dir string) []Summary {
+	summaries := make(map[string]*Summary)
+	get := func(project string) *Summary {
+		s, ok := summaries[project]
+		if !ok {
+			s = &Summary{Project: project, Licenses: make(map[string]int)}
+			summaries[project] = s
+		}
+		return s
+	}
+	for _, root := range roots {
+		get(root)
+	}
+
+	for _, result := range results {
+		rel := result.File
+		if r, err := filepath.Rel(dir, result.File); err == nil {
+			rel = r
+		}
+		s := get(projectOf(filepath.ToSlash(rel), roots))
+		s.Files++
+		if len(result.Matches) > 0 {
+			s.FilesWithLicenses++
+		}
+		for id := range result.Matches {
+			s.Licenses[id]++
+		}
+	}
+
+	var ret []Summary
+	for _, root := range roots {
+		ret = append(ret, *summaries[root])
+	}
+	if s, ok := summaries[RootProject]; ok {
+		ret = append(ret, *s)
+	}
+	return ret
+}
+
+// projectOf returns the deepest project root which contains the path (or the RootProject)
+func projectOf(path string, roots []string) string {
+	ret := RootProject
+	for _, root := range roots {
+		if strings.HasPrefix(path, root+"/") && (ret == RootProject || len(root) > len(ret)) {
+			ret = root
+		}
+	}
+	return ret
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package monorepo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+func TestRoots(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		files      map[string]string
+		patterns   []string
+		workspaces bool
+		want       []string
+	}{
+		{
+			name:     "globs with exclusion",
+			files:    map[string]string{"packages/a/x": "", "packages/b/x": "", "packages/c/x": "", "packages/readme": "", "tools/x": ""},
+			patterns: []string{"packages/*", "!packages/c", "./tools"},
+			want:     []string{"packages/a", "packages/b", "tools"},
+		},
+		{
+			name:       "npm workspaces",
+			files:      map[string]string{"package.json": `{"workspaces": ["packages/*"]}`, "packages/a/x": "", "packages/b/x": ""},
+			workspaces: true,
+			want:       []string{"packages/a", "packages/b"},
+		},
+		{
+			name:       "yarn workspaces",
+			files:      map[string]string{"package.json": `{"workspaces": {"packages": ["apps/*"]}}`, "apps/web/x": ""},
+			workspaces: true,
+			want:       []string{"apps/web"},
+		},
+		{
+			name:       "pnpm and lerna",
+			files:      map[string]string{"pnpm-workspace.yaml": "packages:\n  - 'libs/*'\n  - '!libs/old'\n", "lerna.json": `{"packages": ["tools/*"]}`, "libs/a/x": "", "libs/old/x": "", "tools/t/x": ""},
+			workspaces: true,
+			want:       []string{"libs/a", "tools/t"},
+		},
+		{
+			name:       "go.work",
+			files:      map[string]string{"go.work": "go 1.18\n\nuse ./cmd // the CLI\nuse (\n\t./api\n\t\"./lib\"\n)\n", "cmd/x": "", "api/x": "", "lib/x": ""},
+			workspaces: true,
+			want:       []string{"api", "cmd", "lib"},
+		},
+		{
+			name:       "cargo workspace",
+			files:      map[string]string{"Cargo.toml": "[workspace]\nmembers = [\"crates/*\"]\nexclude = [\"crates/tmp\"]\n", "crates/core/x": "", "crates/tmp/x": ""},
+			workspaces: true,
+			want:       []string{"crates/core"},
+		},
+		{
+			name:  "workspace files ignored",
+			files: map[string]string{"package.json": `{"workspaces": ["packages/*"]}`, "packages/a/x": ""},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got, err := Roots(root, tt.patterns, tt.workspaces)
+			if err != nil {
+				t.Fatalf("Roots() error = %v", err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Roots() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+	match := []identifier.Match{{Begins: 0, Ends: 10}}
+	results := []identifier.IdentifierResults{
+		{File: "/repo/LICENSE", Matches: map[string][]identifier.Match{"Apache-2.0": match}},
+		{File: "/repo/packages/a/LICENSE", Matches: map[string][]identifier.Match{"MIT": match}},
+		{File: "/repo/packages/a/index.js", Matches: map[string][]identifier.Match{"MIT": match, "ISC": match}},
+		{File: "/repo/packages/a/vendor/lib/COPYING", Matches: map[string][]identifier.Match{"BSD-3-Clause": match}},
+		{File: "/repo/packages/ab/README"},
+	}
+	roots := []string{"packages/a", "packages/a/vendor/lib", "packages/b"}
+	want := []Summary{
+		{Project: "packages/a", Files: 2, FilesWithLicenses: 2, Licenses: map[string]int{"MIT": 2, "ISC": 1}},
+		{Project: "packages/a/vendor/lib", Files: 1, FilesWithLicenses: 1, Licenses: map[string]int{"BSD-3-Clause": 1}},
+		{Project: "packages/b", Licenses: map[string]int{}},
+		{Project: RootProject, Files: 2, FilesWithLicenses: 1, Licenses: map[string]int{"Apache-2.0": 1}},
+	}
+	got := Aggregate(roots, results, "/repo")
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Aggregate() mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"ISC", "MIT"}, got[0].IDs()); d != "" {
+		t.Errorf("IDs() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package monorepo aggregates the scan results of a directory per subproject (e.g., the packages of a workspace)
+package monorepo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// The workspace files which define the subprojects
+const (
+	PackageJSON   = "package.json"        // npm and yarn "workspaces"
+	PNPMWorkspace = "pnpm-workspace.yaml" // pnpm "packages"
+	LernaJSON     = "lerna.json"          // lerna "packages"
+	GoWork        = "go.work"             // Go "use" directives
+	CargoTOML     = "Cargo.toml"          // Cargo [workspace] "members"
+)
+
+// Roots returns the project roots (directories relative to the root directory, with forward slashes) of
+// the glob patterns (e.g., "packages/*") and, when workspaces is true, of the workspace files in the root
+// directory. The patterns are relative to the root directory. Patterns which start with "!" exclude the
+// matching directories (like npm and pnpm workspaces).
+func Roots(root string, patterns []string, workspaces bool) ([]string, error) {
+	if workspaces {
+		found, err := workspacePatterns(root)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(append([]string(nil), patterns...), found...)
+	}
+
+	include := make(map[string]bool)
+	var exclude []string
+	for _, pattern := range patterns {
+		pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+		if negated := strings.TrimPrefix(pattern, "!"); negated != pattern {
+			exclude = append(exclude, strings.TrimPrefix(negated, "./"))
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(root, filepath.FromSlash(pattern)))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || !info.IsDir() {
+				continue
+			}
+			rel, err := filepath.Rel(root, m)
+			if err != nil {
+				return nil, err
+			}
+			include[filepath.ToSlash(rel)] = true
+		}
+	}
+
+	var ret []string
+	for dir := range include {
+		excluded := false
+		for _, pattern := range exclude {
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				excluded = true
+			}
+		}
+		if !excluded && dir != "." {
+			ret = append(ret, dir)
+		}
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// workspacePatterns returns the project patterns in the workspace files of the root directory
+func workspacePatterns(root string) ([]string, error) {
+	var ret []string
+	parsers := []struct {
+		file  string
+		parse func([]byte) ([]string, error)
+	}{
+		{PackageJSON, parsePackageJSONWorkspaces},
+		{PNPMWorkspace, parsePNPMWorkspace},
+		{LernaJSON, parseLernaJSON},
+		{GoWork, parseGoWork},
+		{CargoTOML, parseCargoWorkspace},
+	}
+	for _, p := range parsers {
+		b, err := os.ReadFile(filepath.Join(root, p.file))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		patterns, err := p.parse(b)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, patterns...)
+	}
+	return ret, nil
+}
+
+// parsePackageJSONWorkspaces reads the "workspaces" array (or the yarn {"packages": [...]} object)
+func parsePackageJSONWorkspaces(b []byte) ([]string, error) {
+	var p struct {
+		Workspaces json.RawMessage `json:"workspaces"`
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	if len(p.Workspaces) == 0 {
+		return nil, nil
+	}
+	var patterns []string
+	if err := json.Unmarshal(p.Workspaces, &patterns); err == nil {
+		return patterns, nil
+	}
+	var yarn struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(p.Workspaces, &yarn); err != nil {
+		return nil, err
+	}
+	return yarn.Packages, nil
+}
+
+// parsePNPMWorkspace reads the "packages" list
+func parsePNPMWorkspace(b []byte) ([]string, error) {
+	var p struct {
+		Packages []string `yaml:"packages"`
+	}
+	if err := yaml.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return p.Packages, nil
+}
+
+// parseLernaJSON reads the "packages" list
+func parseLernaJSON(b []byte) ([]string, error) {
+	var p struct {
+		Packages []string `json:"packages"`
+	}
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	return p.Packages, nil
+}
+
+// parseGoWork reads the "use" directives (single line and blocks)
+func parseGoWork(b []byte) ([]string, error) {
+	var ret []string
+	inBlock := false
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case inBlock && line == ")":
+			inBlock = false
+		case inBlock && line != "":
+			ret = append(ret, strings.Trim(line, `"`))
+		case line == "use (":
+			inBlock = true
+		case strings.HasPrefix(line, "use "):
+			ret = append(ret, strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "use ")), `"`))
+		}
+	}
+	return ret, scanner.Err()
+}
+
+// parseCargoWorkspace reads the [workspace] "members" and "exclude" lists
+func parseCargoWorkspace(b []byte) ([]string, error) {
+	var p struct {
+		Workspace struct {
+			Members []string `toml:"members"`
+			Exclude []string `toml:"exclude"`
+		} `toml:"workspace"`
+	}
+	if err := toml.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+	ret := p.Workspace.Members
+	for _, e := range p.Workspace.Exclude {
+		ret = append(ret, "!"+e)
+	}
+	return ret, nil
+}
@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LinePrefixes are more comment-like line prefixes (e.g., "%" of TeX or "rem" of batch files) which are removed
+// like the built-in comment indicators (see CommentLinePattern and CommentBlockInsidePattern), compiled once to
+// normalize many texts
+type LinePrefixes struct {
+	prefixes []string
+	re       *regexp.Regexp
+}
+
+// NewLinePrefixes compiles the line prefixes (case-insensitive, each repeated up to 6 times like ## or **). A
+// prefix which ends with a letter or digit (e.g., "rem") is only removed before a word break, so it does not
+// remove the start of a word. It returns nil for no prefixes.
+func NewLinePrefixes(prefixes []string) (*LinePrefixes, error) {
+	if len(prefixes) == 0 {
+		return nil, nil
+	}
+	p := &LinePrefixes{}
+	for _, prefix := range prefixes {
+		if strings.TrimSpace(prefix) == "" || strings.TrimSpace(prefix) != prefix {
+			return nil, fmt.Errorf("invalid line prefix %q (empty or with spaces around it)", prefix)
+		}
+		p.prefixes = append(p.prefixes, strings.ToLower(prefix))
+	}
+	// the longest prefixes first, so "::" is removed before ":"
+	sorted := append([]string{}, p.prefixes...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	alternatives := make([]string, len(sorted))
+	for i, prefix := range sorted {
+		alternatives[i] = regexp.QuoteMeta(prefix)
+		if r, _ := utf8.DecodeLastRuneInString(prefix); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			alternatives[i] += `\b`
+		}
+	}
+	p.re = regexp.MustCompile(`(?m)^\s*(?:` + strings.Join(alternatives, "|") + `){1,6}`)
+	return p, nil
+}
+
+// Prefixes returns the (lower-cased) line prefixes in their order
+func (p *LinePrefixes) Prefixes() []string {
+	if p == nil {
+		return nil
+	}
+	return p.prefixes
+}
@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"testing"
+)
+
+func TestNewLinePrefixes(t *testing.T) {
+	t.Parallel()
+	linePrefixes, err := NewLinePrefixes([]string{"%", "REM", "::"})
+	if err != nil {
+		t.Fatalf("NewLinePrefixes() error = %v", err)
+	}
+	tests := []struct {
+		name         string
+		text         string
+		linePrefixes *LinePrefixes
+		want         string
+	}{
+		{name: "percent", text: "% Permission is granted\n%% to use it", linePrefixes: linePrefixes, want: "permission is granted to use it"},
+		{name: "word prefix", text: "REM Permission is granted\nrem to use it", linePrefixes: linePrefixes, want: "permission is granted to use it"},
+		{name: "not the start of a word", text: "Remedies are granted", linePrefixes: linePrefixes, want: "remedies are granted"},
+		{name: "longest prefix first", text: ":: Permission is granted", linePrefixes: linePrefixes, want: "permission is granted"},
+		{name: "not in the middle of a line", text: "granted 100 % of the time", linePrefixes: linePrefixes, want: "granted 100 % of the time"},
+		{name: "without line prefixes", text: "% Permission is granted", want: "% permission is granted"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n := NormalizationData{OriginalText: tt.text, LinePrefixes: tt.linePrefixes}
+			if err := n.NormalizeText(); err != nil {
+				t.Fatalf("NormalizeText() error = %v", err)
+			}
+			if n.NormalizedText != tt.want {
+				t.Errorf("NormalizeText() = %q, want %q", n.NormalizedText, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range [][]string{{""}, {" "}, {"% "}} {
+		if _, err := NewLinePrefixes(invalid); err == nil {
+			t.Errorf("NewLinePrefixes(%q) expected an error", invalid)
+		}
+	}
+	if p, err := NewLinePrefixes(nil); p != nil || err != nil {
+		t.Errorf("NewLinePrefixes(nil) = %v, %v, want nil, nil", p, err)
+	}
+}
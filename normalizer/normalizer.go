@@ -12,6 +12,8 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/mrutkows/sbom-utility/log"
 	"golang.org/x/exp/slices"
@@ -112,6 +114,8 @@ type NormalizationData struct {
 	CaptureGroups  []*CaptureGroup
 	Hash           Digest
 	IsTemplate     bool
+	// LinePrefixes are more comment-like line prefixes to remove with the comment indicators (none when nil)
+	LinePrefixes *LinePrefixes
 }
 
 type CaptureGroup struct {
@@ -119,6 +123,8 @@ type CaptureGroup struct {
 	Name        string
 	Original    string
 	Matches     string
+	// Offset is the position of the replaceable text section in the original text
+	Offset int
 }
 
 // Digest provides an option to store a combination of hashes of a given package
@@ -174,7 +180,7 @@ func (n *NormalizationData) NormalizeText() error {
 	n.standardizeOmitableTags()
 
 	// Convert the input text to all lower case. (Guideline 4.1.1)
-	n.NormalizedText = strings.ToLower(n.NormalizedText)
+	n.toLower()
 
 	// remove odd characters, such as TM, replacement character ?, etc
 	// NOTE! Remove these before any use of regexp2 because rune chars throw off the index map
@@ -261,6 +267,34 @@ func (n *NormalizationData) initialize() {
 	}
 }
 
+// toLower converts the normalized text to lower case and updates the index map. Unlike strings.ToLower, the
+// invalid UTF-8 bytes are kept, and the runes whose lower case has a different length (e.g., U+0130) keep
+// the index map in line with the text.
+func (n *NormalizationData) toLower() {
+	n.initialize() // initialize normalized text and index map if not set already
+	var b strings.Builder
+	b.Grow(len(n.NormalizedText))
+	newIndex := make([]int, 0, len(n.IndexMap))
+	for i := 0; i < len(n.NormalizedText); {
+		r, size := utf8.DecodeRuneInString(n.NormalizedText[i:])
+		lower := n.NormalizedText[i : i+size]
+		if r != utf8.RuneError || size > 1 {
+			lower = string(unicode.ToLower(r))
+		}
+		b.WriteString(lower)
+		for j := 0; j < len(lower); j++ {
+			if j < size {
+				newIndex = append(newIndex, n.IndexMap[i+j])
+			} else {
+				newIndex = append(newIndex, n.IndexMap[i+size-1])
+			}
+		}
+		i += size
+	}
+	n.NormalizedText = b.String()
+	n.IndexMap = newIndex
+}
+
 func (n *NormalizationData) removeNoteTags() {
 	n.regexpReplacePatternAndUpdateIndexMap(NoteTagPatternRE, " ")
 }
@@ -310,8 +344,8 @@ func (n *NormalizationData) captureReplaceableTextSections() {
 				submatches = append(submatches, n.NormalizedText[match[i-1]:match[i]])
 			}
 		}
-		name := submatches[0]
-		original := submatches[1]
+		name := trimDQuotes(submatches[0])
+		original := trimDQuotes(submatches[1])
 		regex := submatches[2]
 
 		// If the match="regex" is dquoted, trim the dquotes. SPDX templates look like match="regex",
@@ -319,12 +353,13 @@ func (n *NormalizationData) captureReplaceableTextSections() {
 		// just happened to start with an optional dqoute like <<match="?match this maybe quoted"?>>
 		// Notice when we start with this optional dquote we expect seeing and end with an optional dquote+questionmark
 		// so checking for suffix AND prefix works for this backwards compatibility
-		if strings.HasPrefix(regex, `"`) && strings.HasSuffix(regex, `"`) {
-			regex = regex[1 : len(regex)-1]
-		}
+		regex = trimDQuotes(regex)
+
+		// The SPDX templates use the regexp flavor of Java, and the template is lower-cased after this
+		regex = translateRegex(regex)
 
 		// If the regex ends in an unprotected greedy quantifier, make it lazy.
-		if strings.HasSuffix(regex, "+") || strings.HasSuffix(regex, "*") || strings.HasSuffix(regex, "}") {
+		if strings.HasSuffix(regex, "+") || strings.HasSuffix(regex, "*") || strings.HasSuffix(regex, "}") && !hexEscapeSuffixRE.MatchString(regex) {
 			regex += "?"
 		}
 
@@ -344,6 +379,7 @@ func (n *NormalizationData) captureReplaceableTextSections() {
 			Name:        name,
 			Original:    original,
 			Matches:     regex,
+			Offset:      n.IndexMap[match[0]],
 		}
 		n.CaptureGroups = append(n.CaptureGroups, c)
 	}
@@ -351,6 +387,14 @@ func (n *NormalizationData) captureReplaceableTextSections() {
 	n.replaceMatchesWithStringsAndUpdateIndexMap(allSubmatchIndex, replacements)
 }
 
+// trimDQuotes removes the dquotes around a template tag value (e.g., name="copyright")
+func trimDQuotes(value string) string {
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
 func (n *NormalizationData) standardizeOmitableTags() {
 	n.regexpReplacePatternAndUpdateIndexMap(BeginOptionalLinePatternRE, OmitableLine) // Allows other $(m)^ matches
 	n.regexpReplacePatternAndUpdateIndexMap(BeginOptionalPatternRE, Omitable)
@@ -369,6 +413,11 @@ func (n *NormalizationData) removeCodeCommentIndicators() {
 
 	// Remove comment line indicators
 	n.regexpReplacePatternAndUpdateIndexMap(CommentLineRE, " ")
+
+	// Remove the configured comment-like line prefixes
+	if n.LinePrefixes != nil {
+		n.regexpReplacePatternAndUpdateIndexMap(n.LinePrefixes.re, " ")
+	}
 }
 
 func (n *NormalizationData) removeHTMLTags() {
@@ -390,11 +439,8 @@ func (n *NormalizationData) removeHTMLTags() {
 		}
 
 		// move past contents until forbidden char or end char
-		j := i
-		if textLen > j+1 {
-			j++
-			for ; textLen > j && n.NormalizedText[j] != '<' && n.NormalizedText[j] != '>'; j++ {
-			}
+		j := i + 1
+		for ; textLen > j && n.NormalizedText[j] != '<' && n.NormalizedText[j] != '>'; j++ {
 		}
 
 		if textLen > j && n.NormalizedText[j] == '<' { // forbidden char. This is not the tag you are looking for.
@@ -402,6 +448,9 @@ func (n *NormalizationData) removeHTMLTags() {
 				j++
 			}
 			next = j + 1
+			if next > textLen { // the text ends with <<
+				break
+			}
 			continue
 		}
 
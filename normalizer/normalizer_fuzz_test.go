@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+// FuzzNormalizeText checks that any text (a license text or a template) is normalized without a panic, and
+// that the index map of the normalized text points into the original text (or is -1 inside a replacement).
+//
+// Run it with: go test -tags unit -run '^$' -fuzz FuzzNormalizeText ./normalizer
+// The inputs which failed are written to testdata/fuzz/FuzzNormalizeText, and are run by the unit tests.
+func FuzzNormalizeText(f *testing.F) {
+	for _, seed := range []string{
+		"Copyright (c) 2022 Someone\n\nPermission is hereby granted, free of charge",
+		"/*\n * Licensed under the Apache License, Version 2.0\n */",
+		"<!-- SPDX-License-Identifier: MIT -->",
+		"<<var;name=\"copyright\";original=\"Copyright (c) <year>\";match=\".{0,5000}\">>",
+		"<<beginOptional>>The <<match=.+>> License<<endOptional>>",
+		"<<match=.*>> <<note: a note>> 1. first\n2) second\n• bullet",
+		"<a href=\"https://example.com\">link</a> <http://example.com> << >> <<<",
+		"co-\n operate “quoted” — dash   ",
+	} {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+	f.Fuzz(func(t *testing.T, text string, isTemplate bool) {
+		n := NewNormalizationData(text, isTemplate)
+		if err := n.NormalizeText(); err != nil {
+			return
+		}
+		if len(n.IndexMap) != len(n.NormalizedText) {
+			t.Fatalf("index map length %v != normalized text length %v", len(n.IndexMap), len(n.NormalizedText))
+		}
+		for i, orig := range n.IndexMap {
+			if orig < -1 || orig >= len(text) {
+				t.Fatalf("index map[%v] = %v is not in the original text (length %v)", i, orig, len(text))
+			}
+		}
+		if utf8.ValidString(text) && !utf8.ValidString(n.NormalizedText) {
+			t.Fatalf("normalized text of valid UTF-8 is not valid UTF-8: %q", n.NormalizedText)
+		}
+	})
+}
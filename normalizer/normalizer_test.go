@@ -189,9 +189,39 @@ func TestNormalizationData_NormalizeText_CaptureReplaceableTextSections(t *testi
 				Name:        "replaceableSection",
 				Original:    "some text",
 				Matches:     ".+?",
+				Offset:      13,
 			}},
 			NormalizedText: "replaceable: <<.+?>> goes here",
 		},
+	}, {
+		name: "quoted",
+		n: &NormalizationData{
+			OriginalText: `<<var;name="copyright";original="Copyright (c) <year> <owner>";match=".{0,5000}">> All rights reserved.`,
+		},
+		e: &NormalizationData{
+			CaptureGroups: []*CaptureGroup{{
+				GroupNumber: 1,
+				Name:        "copyright",
+				Original:    "Copyright (c) <year> <owner>",
+				Matches:     ".{0,1000}?",
+			}},
+			NormalizedText: "<<.{0,1000}?>> All rights reserved.",
+		},
+	}, {
+		name: "java escapes",
+		n: &NormalizationData{
+			OriginalText: `Version <<var;name="version";original="1.0";match="\S+\u00A0(?=\d)">> here`,
+		},
+		e: &NormalizationData{
+			CaptureGroups: []*CaptureGroup{{
+				GroupNumber: 1,
+				Name:        "version",
+				Original:    "1.0",
+				Matches:     `[[:^space:]]+\x{00A0}`,
+				Offset:      8,
+			}},
+			NormalizedText: `Version <<[[:^space:]]+\x{00A0}>> here`,
+		},
 	}}
 
 	for _, tc := range tcs {
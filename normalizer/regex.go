@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package normalizer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// caseProofEscapes are the escapes whose meaning would change when the template is lower-cased (e.g., \S
+// would become \s), with the equivalents without a letter to lower-case, outside and inside a character class
+var caseProofEscapes = map[byte][2]string{
+	'S': {`[[:^space:]]`, `[:^space:]`},
+	'W': {`[[:^word:]]`, `[:^word:]`},
+	'D': {`[[:^digit:]]`, `[:^digit:]`},
+	'h': {`[\t ]`, `\t `},
+	'H': {`[^\t ]`, ``},
+	'A': {`^`, ``},
+	'Z': {`$`, ``},
+	'z': {`$`, ``},
+	'e': {`\x1b`, `\x1b`},
+}
+
+// translateRegex translates the regexp of a template variable from the flavor of the SPDX license list
+// (Java's) to the Go flavor: the escapes which would change meaning when the template is lower-cased (e.g.,
+// \S, \A) or which Go does not have (e.g., \u00A0, \0101, \Q...\E) are replaced with equivalents, the
+// possessive quantifiers (e.g., a++) and atomic or named groups become plain ones, and the lookarounds (e.g.,
+// (?=...)) are dropped, so that the template is not rejected (a lookaround only narrows a match).
+func translateRegex(regex string) string {
+	var sb strings.Builder
+	inClass := false
+	quantified := false // the previous token was a quantifier, so a + is possessive
+	for i := 0; i < len(regex); i++ {
+		c := regex[i]
+		wasQuantified := quantified
+		quantified = false
+		switch {
+		case c == '\\' && i+1 < len(regex):
+			escape, n := translateEscape(regex[i+1:], inClass)
+			sb.WriteString(escape)
+			i += n
+		case inClass:
+			if c == ']' {
+				inClass = false
+			}
+			sb.WriteByte(c)
+		case c == '[':
+			inClass = true
+			sb.WriteByte(c)
+			// a ] right after the [ (or [^) is a literal ]
+			if strings.HasPrefix(regex[i+1:], "^") {
+				sb.WriteByte('^')
+				i++
+			}
+			if strings.HasPrefix(regex[i+1:], "]") {
+				sb.WriteByte(']')
+				i++
+			}
+		case c == '+' && wasQuantified:
+			// possessive, the same matches without the backtracking
+		case c == '*' || c == '+' || c == '?' || c == '}':
+			sb.WriteByte(c)
+			quantified = true
+		case c == '(' && isLookaround(regex[i:]):
+			i = groupEnd(regex, i)
+		case c == '(' && strings.HasPrefix(regex[i:], "(?>"):
+			sb.WriteString("(?:")
+			i += len("(?>") - 1
+		case c == '(' && (strings.HasPrefix(regex[i:], "(?<") || strings.HasPrefix(regex[i:], "(?P<")):
+			// the names would collide with the names of the variable groups
+			if end := strings.IndexByte(regex[i:], '>'); end != -1 {
+				sb.WriteString("(?:")
+				i += end
+			} else {
+				sb.WriteByte(c)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+var (
+	unicodeEscapeRE = regexp.MustCompile(`^u[0-9A-Fa-f]{4}`)
+	octalEscapeRE   = regexp.MustCompile(`^0[0-3]?[0-7]{1,2}`)
+	// hexEscapeSuffixRE matches a regexp which ends with a \x{...} escape (not with a {n,m} quantifier)
+	hexEscapeSuffixRE = regexp.MustCompile(`\\x\{[0-9A-Fa-f]+\}$`)
+)
+
+// translateEscape returns the Go escape for the escape (without its \) at the start of the text, and how many
+// characters of the text it used
+func translateEscape(text string, inClass bool) (string, int) {
+	d := text[0]
+	switch {
+	case unicodeEscapeRE.MatchString(text):
+		return `\x{` + text[1:5] + `}`, 5
+	case octalEscapeRE.MatchString(text):
+		octal := octalEscapeRE.FindString(text)
+		n, _ := strconv.ParseInt(octal[1:], 8, 32)
+		return fmt.Sprintf(`\x{%x}`, n), len(octal)
+	case d == 'Q':
+		literal := text[1:]
+		if end := strings.Index(literal, `\E`); end != -1 {
+			return regexp.QuoteMeta(literal[:end]), end + 3
+		}
+		return regexp.QuoteMeta(literal), len(text)
+	}
+	if d >= utf8.RuneSelf {
+		return "", 0 // a character which is not ASCII is itself (Go only escapes the ASCII punctuation)
+	}
+	if equivalents, ok := caseProofEscapes[d]; ok {
+		if inClass {
+			if equivalents[1] != "" {
+				return equivalents[1], 1
+			}
+		} else {
+			return equivalents[0], 1
+		}
+	}
+	return `\` + string(d), 1
+}
+
+// isLookaround is true when the text starts with a lookahead or lookbehind group
+func isLookaround(text string) bool {
+	for _, prefix := range []string{"(?=", "(?!", "(?<=", "(?<!"} {
+		if strings.HasPrefix(text, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupEnd returns the position of the ) which closes the group starting at the ( of the regexp (the last
+// position when it is not closed)
+func groupEnd(regex string, begins int) int {
+	depth := 0
+	inClass := false
+	for i := begins; i < len(regex); i++ {
+		switch c := regex[i]; {
+		case c == '\\':
+			i++
+		case inClass:
+			inClass = c != ']'
+		case c == '[':
+			inClass = true
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(regex) - 1
+}
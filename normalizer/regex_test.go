@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package normalizer
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func Test_translateRegex(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		regex     string
+		want      string
+		match     string
+		wantMatch bool
+	}{
+		{name: "go flavor", regex: `(\(\<|\()?`, want: `(\(\<|\()?`, match: "(<", wantMatch: true},
+		{name: "upper-case classes", regex: `\S+ \W\D`, want: `[[:^space:]]+ [[:^word:]][[:^digit:]]`, match: "ab -x", wantMatch: true},
+		{name: "upper-case classes in a class", regex: `[\S\d]+`, want: `[[:^space:]\d]+`, match: " ", wantMatch: false},
+		{name: "anchors", regex: `\Afoo\Z`, want: `^foo$`, match: "foo", wantMatch: true},
+		{name: "unicode and octal", regex: "\\u00A0\\055", want: `\x{00A0}\x{2d}`, match: "\u00a0-", wantMatch: true},
+		{name: "quoted", regex: `\Q(c) 2.0\E+`, want: `\(c\) 2\.0+`, match: "(c) 2.00", wantMatch: true},
+		{name: "possessive", regex: `a++b*+c?+`, want: `a+b*c?`, match: "aab", wantMatch: true},
+		{name: "atomic and named groups", regex: `(?>a|b)(?<name>c)(?P<x>d)`, want: `(?:a|b)(?:c)(?:d)`, match: "bcd", wantMatch: true},
+		{name: "lookarounds", regex: `(?<!x)foo(?=[)]|\))(?!bar)`, want: `foo`, match: "foo", wantMatch: true},
+		{name: "literal bracket in a class", regex: `[]\]a]+`, want: `[]\]a]+`, match: "]a", wantMatch: true},
+		{name: "escaped non-ASCII", regex: `\é`, want: `é`, match: "é", wantMatch: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := translateRegex(tt.regex)
+			if got != tt.want {
+				t.Errorf("translateRegex() = %q, want %q", got, tt.want)
+			}
+			// the template is lower-cased after the translation
+			re, err := regexp.Compile(strings.ToLower(got))
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+			if matched := re.MatchString(tt.match); matched != tt.wantMatch {
+				t.Errorf("MatchString(%q) = %v, want %v", tt.match, matched, tt.wantMatch)
+			}
+		})
+	}
+}
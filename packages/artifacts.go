@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// IdentifyPackages reports the licenses for a package file (e.g., a Python wheel or a jar) or for
+// each package file found under a directory. Each package file is reported as one result.
+// Package files inside of archives (e.g., jars in a war or in a source tarball) are reported too,
+// as deep as the limits allow. The limits apply to the whole scan.
+// When the Context of the options is canceled, the packages scanned until then are returned with an error
+// wrapping the error of the Context.
+func IdentifyPackages(filePath string, limits extractor.Limits, options identifier.Options, ll *licenses.LicenseLibrary) ([]Package, error) {
+	ex := extractor.New(limits)
+	fi, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		ret := identifyArchive(ex, filePath, options, ll)
+		if err := canceled(options); err != nil {
+			return ret, fmt.Errorf("%w: the packages of %v may not all be scanned", err, filePath)
+		}
+		return ret, nil
+	}
+
+	var ret []Package
+	err = filepath.WalkDir(filePath, func(p string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := canceled(options); err != nil {
+			return fmt.Errorf("%w: the packages under %v may not all be scanned", err, filePath)
+		}
+		if de.IsDir() {
+			if p != filePath && strings.HasPrefix(de.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsPackageFile(p) {
+			ret = append(ret, identifyArchive(ex, p, options, ll)...)
+		}
+		return nil
+	})
+	return ret, err
+}
+
+// IsPackageFile returns true if the file name looks like a supported package file
+func IsPackageFile(name string) bool {
+	return IsPythonDistribution(name) || IsJavaArchive(name) || IsGem(name) || IsNuGetPackage(name)
+}
+
+// identifyArchive identifies the package file and then the package files nested inside of it
+func identifyArchive(ex *extractor.Extractor, archivePath string, options identifier.Options, ll *licenses.LicenseLibrary) []Package {
+	p, isPackage := identifyPackageFile(ex, archivePath, options, ll)
+
+	nested, err := ex.Extract(archivePath, IsPackageFile)
+	if err != nil {
+		notScanned := Package{Name: filepath.Base(archivePath), Path: archivePath, Error: fmt.Sprintf("cannot scan nested archives: %v", err)}
+		if !isPackage {
+			return []Package{notScanned}
+		}
+		return []Package{p, notScanned}
+	}
+
+	// A generic archive which is not a package is only a container (e.g., a source tarball, a tarball of jars, or
+	// the data.tar.gz in a gem), so it is not reported
+	var ret []Package
+	if isPackage {
+		ret = append(ret, p)
+	}
+	for _, n := range nested {
+		// The nested archives are released even when the scan is canceled
+		if canceled(options) == nil {
+			ret = append(ret, identifyArchive(ex, n, options, ll)...)
+		}
+		ex.Release(n)
+	}
+	return ret
+}
+
+// identifyPackageFile identifies the package file, or returns false for a generic archive which is not a package
+func identifyPackageFile(ex *extractor.Extractor, filePath string, options identifier.Options, ll *licenses.LicenseLibrary) (Package, bool) {
+	switch {
+	case IsJavaArchive(filePath):
+		return IdentifyJavaArchive(ex, filePath, options, ll), true
+	case IsGem(filePath):
+		return IdentifyGem(ex, filePath, options, ll), true
+	case IsNuGetPackage(filePath):
+		return IdentifyNuGetPackage(ex, filePath, options, ll), true
+	default:
+		return identifyPythonDistribution(ex, filePath, options, ll)
+	}
+}
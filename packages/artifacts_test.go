@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIdentifyNestedPackages(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	const tarball = "../testdata/nested/src-1.0.tar.gz"
+	type summary struct {
+		ID       string
+		Path     string
+		Licenses []string
+	}
+	tests := []struct {
+		name     string
+		limits   extractor.Limits
+		expected []summary
+	}{
+		{
+			name:   "jars in a war in a tarball",
+			limits: extractor.DefaultLimits,
+			expected: []summary{
+				// The tarball itself is not a package, so only the packages in it are reported
+				{ID: "org.example:commons-demo:1.2", Path: tarball + "!/src-1.0/lib/commons-demo-1.2.jar", Licenses: []string{"MIT"}},
+				{ID: "org.example:webapp:0.1", Path: tarball + "!/src-1.0/dist/webapp.war", Licenses: []string{"BSD-3-Clause"}},
+				{ID: "org.example:commons-demo:1.2", Path: tarball + "!/src-1.0/dist/webapp.war!/WEB-INF/lib/commons-demo-1.2.jar", Licenses: []string{"MIT"}},
+			},
+		},
+		{
+			name:   "max depth 1",
+			limits: extractor.Limits{MaxDepth: 1, MaxExtractedSize: 1 << 20, MaxCompressionRatio: 200},
+			expected: []summary{
+				{ID: "org.example:commons-demo:1.2", Path: tarball + "!/src-1.0/lib/commons-demo-1.2.jar", Licenses: []string{"MIT"}},
+				{ID: "org.example:webapp:0.1", Path: tarball + "!/src-1.0/dist/webapp.war", Licenses: []string{"BSD-3-Clause"}},
+			},
+		},
+		{
+			// The tarball is not reported without its nested packages either
+			name:   "max depth 0",
+			limits: extractor.Limits{MaxDepth: 0, MaxExtractedSize: 1 << 20, MaxCompressionRatio: 200},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			pkgs, err := IdentifyPackages(tarball, tt.limits, identifier.Options{}, ll)
+			if err != nil {
+				t.Fatalf("IdentifyPackages() error = %v", err)
+			}
+			var got []summary
+			for _, p := range pkgs {
+				got = append(got, summary{ID: p.ID(), Path: p.Path, Licenses: p.Licenses})
+			}
+			if d := cmp.Diff(tt.expected, got); d != "" {
+				t.Errorf("IdentifyPackages() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestIdentifyPackagesExtractedSizeLimit(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+
+	limits := extractor.Limits{MaxDepth: 3, MaxExtractedSize: 100, MaxCompressionRatio: 200}
+	pkgs, err := IdentifyPackages("../testdata/nested/src-1.0.tar.gz", limits, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyPackages() error = %v", err)
+	}
+	if len(pkgs) == 0 || pkgs[len(pkgs)-1].Error == "" {
+		t.Errorf("IdentifyPackages() expected a not scanned package for the exceeded limit, got %+v", pkgs)
+	}
+}
@@ -0,0 +1,262 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+const (
+	Go         = "golang"
+	goMod      = "go.mod"
+	vendorDir  = "vendor"
+	modulesTxt = "modules.txt"
+)
+
+// GoModule is a module path and version from go.mod or vendor/modules.txt
+type GoModule struct {
+	Path    string
+	Version string
+	// Replacement is the module path (with the Version) or the local directory (e.g., "../x", relative to the
+	// go.mod) which replaces the module, if any
+	Replacement string
+}
+
+// IdentifyGoModules scans the main module in dir and each module it requires.
+// Module sources are found under dir/vendor when vendored, otherwise in the module cache.
+// When the Context of the options is canceled, the modules scanned until then are returned with an error wrapping
+// the error of the Context.
+func IdentifyGoModules(dir string, options identifier.Options, ll *licenses.LicenseLibrary) ([]Package, error) {
+	goModBytes, err := os.ReadFile(filepath.Join(dir, goMod))
+	if err != nil {
+		return nil, err
+	}
+	mainModule, required := ParseGoMod(goModBytes)
+
+	vendored := false
+	if b, err := os.ReadFile(filepath.Join(dir, vendorDir, modulesTxt)); err == nil {
+		required = ParseVendorModules(b)
+		vendored = true
+	}
+
+	modCache := ""
+	if !vendored {
+		modCache = GoModCache()
+	}
+
+	main := Package{Ecosystem: Go, Name: mainModule, Path: dir}
+	if err := main.identifyLicenseFilesInDir(dir, options, ll); err != nil {
+		main.Error = err.Error()
+	}
+	ret := []Package{main}
+
+	for i, m := range required {
+		if err := canceled(options); err != nil {
+			return ret, fmt.Errorf("%w: %v of the %v required modules were not scanned", err, len(required)-i, len(required))
+		}
+		p := Package{Ecosystem: Go, Name: m.Path, Version: m.Version}
+		switch {
+		case vendored:
+			p.Path = filepath.Join(dir, vendorDir, filepath.FromSlash(m.Path))
+		case isLocalModulePath(m.Replacement):
+			p.Path = filepath.FromSlash(m.Replacement)
+			if !filepath.IsAbs(p.Path) {
+				p.Path = filepath.Join(dir, p.Path)
+			}
+		default:
+			modulePath := m.Path
+			if m.Replacement != "" {
+				modulePath = m.Replacement
+			}
+			escapedPath, err1 := escapeModulePath(modulePath)
+			escapedVersion, err2 := escapeModulePath(m.Version)
+			if err1 != nil || err2 != nil {
+				p.Error = fmt.Sprintf("cannot escape module %v", m)
+				ret = append(ret, p)
+				continue
+			}
+			p.Path = filepath.Join(modCache, filepath.FromSlash(escapedPath)+"@"+escapedVersion)
+		}
+		if err := p.identifyLicenseFilesInDir(p.Path, options, ll); err != nil {
+			p.Error = err.Error()
+		}
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
+
+// ParseGoMod returns the main module path and the required modules.
+// Replaced modules ("replace old v1 => new v2") are reported with the Replacement and its version, and the modules
+// replaced with a local directory ("replace old => ../new") without a version.
+func ParseGoMod(b []byte) (module string, required []GoModule) {
+	block := ""
+	var replaces []goReplace
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case block != "" && fields[0] == ")":
+			block = ""
+		case block == "require" && len(fields) >= 2:
+			required = append(required, GoModule{Path: unquote(fields[0]), Version: fields[1]})
+		case block == "replace":
+			if r, ok := parseGoReplace(fields); ok {
+				replaces = append(replaces, r)
+			}
+		case fields[0] == "module" && len(fields) >= 2:
+			module = unquote(fields[1])
+		case (fields[0] == "require" || fields[0] == "replace") && len(fields) >= 2 && fields[1] == "(":
+			block = fields[0]
+		case fields[0] == "require" && len(fields) >= 3:
+			required = append(required, GoModule{Path: unquote(fields[1]), Version: fields[2]})
+		case fields[0] == "replace":
+			if r, ok := parseGoReplace(fields[1:]); ok {
+				replaces = append(replaces, r)
+			}
+		}
+	}
+	for i, m := range required {
+		required[i] = replaceGoModule(m, replaces)
+	}
+	return module, required
+}
+
+// goReplace is a replace directive of a go.mod. An Old without a Version replaces every version.
+type goReplace struct {
+	Old GoModule
+	New GoModule
+}
+
+// parseGoReplace parses the fields of a replace directive (old [version] => new [version])
+func parseGoReplace(fields []string) (goReplace, bool) {
+	arrow := -1
+	for i, f := range fields {
+		if f == "=>" {
+			arrow = i
+			break
+		}
+	}
+	if arrow < 1 || arrow > 2 || len(fields)-arrow-1 < 1 || len(fields)-arrow-1 > 2 {
+		return goReplace{}, false
+	}
+	before, after := fields[:arrow], fields[arrow+1:]
+	var r goReplace
+	r.Old.Path = unquote(before[0])
+	if len(before) == 2 {
+		r.Old.Version = before[1]
+	}
+	r.New.Path = unquote(after[0])
+	if len(after) == 2 {
+		r.New.Version = after[1]
+	}
+	return r, true
+}
+
+// replaceGoModule applies the replace directive of the module version, or else of the module (like the go command)
+func replaceGoModule(m GoModule, replaces []goReplace) GoModule {
+	var replace *goReplace
+	for i, r := range replaces {
+		if r.Old.Path != m.Path || r.Old.Version != "" && r.Old.Version != m.Version {
+			continue
+		}
+		if replace == nil || r.Old.Version != "" {
+			replace = &replaces[i]
+		}
+	}
+	if replace == nil {
+		return m
+	}
+	m.Replacement = replace.New.Path
+	m.Version = replace.New.Version // "" for a local directory
+	return m
+}
+
+// isLocalModulePath returns true for a replacement which is a local directory (a path starting with ./ or ../, or an
+// absolute path) rather than a module path
+func isLocalModulePath(p string) bool {
+	return p == "." || p == ".." || strings.HasPrefix(p, "./") || strings.HasPrefix(p, "../") ||
+		strings.HasPrefix(p, `.\`) || strings.HasPrefix(p, `..\`) || filepath.IsAbs(p) || strings.HasPrefix(p, "/")
+}
+
+// ParseVendorModules returns the modules listed in vendor/modules.txt.
+// Replaced modules ("# old v1 => new v2") are reported with the replacement version.
+func ParseVendorModules(b []byte) []GoModule {
+	var ret []GoModule
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "# ") {
+			continue // package lines and "## explicit" annotations
+		}
+		before, after, replaced := strings.Cut(strings.TrimPrefix(line, "# "), "=>")
+		fields := strings.Fields(before)
+		if len(fields) == 0 {
+			continue
+		}
+		m := GoModule{Path: fields[0]}
+		if len(fields) > 1 {
+			m.Version = fields[1]
+		}
+		if replaced {
+			r := strings.Fields(after)
+			if len(r) > 0 {
+				m.Replacement = r[0]
+			}
+			if len(r) > 1 {
+				m.Version = r[1]
+			} else {
+				m.Version = "" // replaced with a local directory
+			}
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// GoModCache returns $GOMODCACHE, or the default $GOPATH/pkg/mod
+func GoModCache() string {
+	if c := os.Getenv("GOMODCACHE"); c != "" {
+		return c
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		gopath = filepath.Join(home, "go")
+	}
+	// GOPATH may be a list. The module cache is in the first entry.
+	gopath = filepath.SplitList(gopath)[0]
+	return filepath.Join(gopath, "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache case-encoding (uppercase is "!" + lowercase)
+func escapeModulePath(s string) (string, error) {
+	var sb strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '!' || r > unicode.MaxASCII:
+			return "", fmt.Errorf("invalid module path %q", s)
+		case unicode.IsUpper(r):
+			sb.WriteRune('!')
+			sb.WriteRune(unicode.ToLower(r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String(), nil
+}
+
+func unquote(s string) string {
+	return strings.Trim(s, "\"`")
+}
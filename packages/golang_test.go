@@ -0,0 +1,169 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestParseGoMod(t *testing.T) {
+	t.Parallel()
+	goMod := `module "example.com/main" // comment
+
+go 1.18
+
+require example.com/single v1.2.3
+
+require (
+	example.com/a v0.0.1
+	// comment line
+	example.com/b v2.0.0+incompatible // indirect
+)
+
+require (
+	example.com/c v1.0.0
+	example.com/d v1.0.0
+)
+
+replace example.com/a => ../a
+
+replace (
+	example.com/c => example.com/c-fork v1.1.0
+	example.com/c v1.0.0 => example.com/c-fixed v1.0.1 // the version is replaced first
+	example.com/d v0.9.0 => example.com/d-old v0.9.1
+)
+`
+	module, required := ParseGoMod([]byte(goMod))
+	if module != "example.com/main" {
+		t.Errorf("expected module example.com/main got %v", module)
+	}
+	expected := []GoModule{
+		{Path: "example.com/single", Version: "v1.2.3"},
+		{Path: "example.com/a", Replacement: "../a"},
+		{Path: "example.com/b", Version: "v2.0.0+incompatible"},
+		{Path: "example.com/c", Version: "v1.0.1", Replacement: "example.com/c-fixed"},
+		{Path: "example.com/d", Version: "v1.0.0"},
+	}
+	if d := cmp.Diff(expected, required); d != "" {
+		t.Errorf("ParseGoMod() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestParseVendorModules(t *testing.T) {
+	t.Parallel()
+	modulesTxt := `# example.com/a v0.0.1
+## explicit; go 1.17
+example.com/a
+example.com/a/sub
+# example.com/b v1.0.0 => example.com/fork v1.0.1
+example.com/b
+# example.com/c v1.0.0 => ../c
+`
+	expected := []GoModule{
+		{Path: "example.com/a", Version: "v0.0.1"},
+		{Path: "example.com/b", Version: "v1.0.1", Replacement: "example.com/fork"},
+		{Path: "example.com/c", Version: "", Replacement: "../c"},
+	}
+	if d := cmp.Diff(expected, ParseVendorModules([]byte(modulesTxt))); d != "" {
+		t.Errorf("ParseVendorModules() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		in       string
+		expected string
+		wantErr  bool
+	}{
+		{in: "github.com/BurntSushi/toml", expected: "github.com/!burnt!sushi/toml"},
+		{in: "v1.0.0-RC1", expected: "v1.0.0-!r!c1"},
+		{in: "bad!path", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := escapeModulePath(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("escapeModulePath(%v) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.expected {
+			t.Errorf("escapeModulePath(%v) expected %v got %v", tt.in, tt.expected, got)
+		}
+	}
+}
+
+func TestIdentifyGoModules_vendor(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyGoModules("../testdata/gomod", identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyGoModules() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Licenses []string
+		Error    string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		got = append(got, summary{ID: p.ID(), Licenses: p.Licenses, Error: p.Error})
+	}
+	expected := []summary{
+		{ID: "example.com/main", Licenses: []string{"MIT"}},
+		{ID: "example.com/foo@v1.0.0", Licenses: []string{"BSD-2-Clause"}},
+		{ID: "example.com/Bar@v0.2.1", Licenses: []string{"ISC"}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyGoModules() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestIdentifyGoModules_replace(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyGoModules("../testdata/gomodreplace", identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyGoModules() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Path     string
+		Licenses []string
+		Error    string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		got = append(got, summary{ID: p.ID(), Path: filepath.ToSlash(p.Path), Licenses: p.Licenses, Error: p.Error})
+	}
+	expected := []summary{
+		{ID: "example.com/main", Path: "../testdata/gomodreplace", Licenses: []string{"MIT"}},
+		// The module replaced with a local directory is scanned in the directory (not the module cache)
+		{ID: "example.com/local", Path: "../testdata/gomodreplace/local", Licenses: []string{"BSD-2-Clause"}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyGoModules() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+const (
+	metaInf       = "META-INF"
+	metaInfMaven  = "META-INF/maven/"
+	pomProperties = "pom.properties"
+)
+
+var javaArchiveSuffixes = []string{".jar", ".war", ".ear", ".aar"}
+
+// javaCoordinates are the Maven coordinates found under META-INF/maven/<groupId>/<artifactId>/
+type javaCoordinates struct {
+	GroupID    string
+	ArtifactID string
+	Version    string
+	// Declared are the licenses from the pom.xml next to the pom.properties
+	Declared []string
+}
+
+// IsJavaArchive returns true for .jar, .war, .ear, and .aar file names
+func IsJavaArchive(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range javaArchiveSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentifyJavaArchive reports the licenses for a Java archive as one groupId:artifactId:version artifact.
+// The META-INF license files (LICENSE*, NOTICE*, etc.) are the best evidence.
+// Otherwise, the licenses declared in the embedded META-INF/maven pom.xml are used.
+func IdentifyJavaArchive(ex *extractor.Extractor, archivePath string, options identifier.Options, ll *licenses.LicenseLibrary) Package {
+	p := Package{Ecosystem: manifest.Maven, Name: filepath.Base(archivePath), Path: archivePath}
+
+	// A shaded (uber) jar contains the coordinates of every artifact it bundles, so collect them all
+	coordinates := make(map[string]*javaCoordinates)
+	getCoordinates := func(name string) *javaCoordinates {
+		dir := path.Dir(name)
+		if coordinates[dir] == nil {
+			coordinates[dir] = &javaCoordinates{}
+		}
+		return coordinates[dir]
+	}
+
+	err := ex.Walk(archivePath, func(name string, size int64, r io.Reader) error {
+		if path.Dir(name) == metaInf && IsLicenseFile(name) {
+			p.identifyEntry(archivePath, name, r, options, ll)
+			return nil
+		}
+		isPom := strings.HasPrefix(name, metaInfMaven) && (path.Base(name) == pomProperties || path.Base(name) == manifest.PomXML)
+		if !isPom {
+			return nil
+		}
+		b, err := extractor.ReadEntry(name, r)
+		if err != nil {
+			Logger.Debugf("skipping %v: %v", name, err)
+			return nil
+		}
+		if path.Base(name) == pomProperties {
+			parsePomProperties(b, getCoordinates(name))
+			return nil
+		}
+		d, err := manifest.ParseBytes(extractor.EntryPath(archivePath, name), b)
+		if err != nil {
+			Logger.Debugf("skipping %v: %v", name, err)
+			return nil
+		}
+		getCoordinates(name).Declared = d.Licenses
+		return nil
+	})
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+
+	if c := mainCoordinates(coordinates, filepath.Base(archivePath)); c != nil {
+		if c.GroupID != "" || c.ArtifactID != "" {
+			p.Name = c.GroupID + ":" + c.ArtifactID
+		}
+		p.Version = c.Version
+		p.Declared = c.Declared
+	}
+	if len(p.Licenses) > 0 {
+		return p
+	}
+	for _, value := range p.Declared {
+		p.addLicenses(manifest.ResolveIDs(value, ll), EvidenceManifest)
+	}
+	return p
+}
+
+// mainCoordinates picks the artifact's own coordinates. With more than one (a shaded jar), prefer
+// the longest artifactId that the archive file name starts with, then the first dir.
+func mainCoordinates(coordinates map[string]*javaCoordinates, archiveName string) *javaCoordinates {
+	score := func(c *javaCoordinates) int {
+		if c.ArtifactID != "" && strings.HasPrefix(archiveName, c.ArtifactID) {
+			return len(c.ArtifactID)
+		}
+		return -1
+	}
+	var ret *javaCoordinates
+	var retDir string
+	for dir, c := range coordinates {
+		if ret == nil || score(c) > score(ret) || score(c) == score(ret) && dir < retDir {
+			ret, retDir = c, dir
+		}
+	}
+	return ret
+}
+
+// parsePomProperties reads the groupId, artifactId, and version from a Maven pom.properties file
+func parsePomProperties(b []byte, c *javaCoordinates) {
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		k, v, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		switch strings.TrimSpace(k) {
+		case "groupId":
+			c.GroupID = v
+		case "artifactId":
+			c.ArtifactID = v
+		case "version":
+			c.Version = v
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIdentifyJavaArchives(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyPackages("../testdata/java", extractor.DefaultLimits, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyPackages() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Licenses []string
+		Evidence string
+		Declared []string
+		Files    []string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		s := summary{ID: p.ID(), Licenses: p.Licenses, Evidence: p.Evidence, Declared: p.Declared}
+		for _, f := range p.Files {
+			s.Files = append(s.Files, f.File)
+		}
+		got = append(got, s)
+	}
+	expected := []summary{
+		{
+			// The shaded com.other:commons:9.9 coordinates are not the artifact's own
+			ID: "org.example:commons-demo:1.2", Licenses: []string{"MIT"}, Evidence: EvidenceLicenseFile, Declared: []string{"MIT"},
+			Files: []string{"../testdata/java/commons-demo-1.2.jar!/META-INF/LICENSE.txt"},
+		},
+		{ID: "library.aar"},
+		{ID: "org.example:webapp:0.1", Licenses: []string{"BSD-3-Clause"}, Evidence: EvidenceManifest, Declared: []string{"BSD-3-Clause"}},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyPackages() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+const nodeModules = "node_modules"
+
+// IdentifyNodeModules treats each package under dir/node_modules (including scoped packages and
+// nested node_modules) as a unit and reports one result per package@version.
+// The best license evidence is used: LICENSE files, then the package.json license, then the README.
+// When the Context of the options is canceled, the packages scanned until then are returned with an error wrapping
+// the error of the Context.
+func IdentifyNodeModules(dir string, options identifier.Options, ll *licenses.LicenseLibrary) ([]Package, error) {
+	var pkgDirs []string
+	if err := collectNodePackageDirs(filepath.Join(dir, nodeModules), &pkgDirs); err != nil {
+		return nil, err
+	}
+
+	// errGroup to do the work in parallel until error
+	pkgs := make([]Package, len(pkgDirs))
+	workers := errgroup.Group{}
+	workers.SetLimit(10)
+	scanned := len(pkgDirs)
+	for i, pkgDir := range pkgDirs {
+		if canceled(options) != nil {
+			scanned = i
+			break
+		}
+		i, pkgDir := i, pkgDir
+		workers.Go(func() error {
+			pkgs[i] = identifyNodePackage(pkgDir, options, ll)
+			return nil
+		})
+	}
+	if err := workers.Wait(); err != nil {
+		return nil, err
+	}
+
+	// The same package@version is often installed in more than one place. Report it once.
+	var ret []Package
+	seen := make(map[string]bool)
+	for _, p := range pkgs[:scanned] {
+		if seen[p.ID()] {
+			continue
+		}
+		seen[p.ID()] = true
+		ret = append(ret, p)
+	}
+	if scanned < len(pkgDirs) {
+		return ret, fmt.Errorf("%w: %v of the %v packages were not scanned", canceled(options), len(pkgDirs)-scanned, len(pkgDirs))
+	}
+	return ret, nil
+}
+
+// collectNodePackageDirs appends the package dirs found in a node_modules dir (recursively)
+func collectNodePackageDirs(nm string, pkgDirs *[]string) error {
+	des, err := os.ReadDir(nm)
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		name := de.Name()
+		if !de.IsDir() || strings.HasPrefix(name, ".") {
+			continue // skip files, symlinks, and .bin, .cache, etc.
+		}
+		pkgDir := filepath.Join(nm, name)
+		if strings.HasPrefix(name, "@") {
+			// A scope dir contains the packages
+			if err := collectNodePackageDirs(pkgDir, pkgDirs); err != nil {
+				return err
+			}
+			continue
+		}
+		*pkgDirs = append(*pkgDirs, pkgDir)
+
+		nested := filepath.Join(pkgDir, nodeModules)
+		if err := collectNodePackageDirs(nested, pkgDirs); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func identifyNodePackage(pkgDir string, options identifier.Options, ll *licenses.LicenseLibrary) Package {
+	p := Package{Ecosystem: manifest.NPM, Name: filepath.Base(pkgDir), Path: pkgDir}
+
+	d, err := manifest.Parse(filepath.Join(pkgDir, manifest.PackageJSON))
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	if d.Name != "" {
+		p.Name = d.Name
+	}
+	p.Version = d.Version
+	p.Declared = d.Licenses
+
+	if err := p.identifyLicenseFilesInDir(pkgDir, options, ll); err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	if len(p.Licenses) > 0 {
+		return p
+	}
+
+	for _, value := range p.Declared {
+		p.addLicenses(manifest.ResolveIDs(value, ll), EvidenceManifest)
+	}
+	if len(p.Licenses) > 0 {
+		return p
+	}
+
+	if err := p.identifyFilesInDir(pkgDir, IsReadmeFile, EvidenceReadme, options, ll); err != nil {
+		p.Error = err.Error()
+	}
+	return p
+}
@@ -0,0 +1,51 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIdentifyNodeModules(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyNodeModules("../testdata/npm", identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyNodeModules() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Licenses []string
+		Evidence string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		got = append(got, summary{ID: p.ID(), Licenses: p.Licenses, Evidence: p.Evidence})
+	}
+	expected := []summary{
+		{ID: "@scope/b@2.0.0", Licenses: []string{"ISC"}, Evidence: EvidenceManifest},
+		{ID: "a@1.0.0", Licenses: []string{"MIT"}, Evidence: EvidenceLicenseFile},
+		{ID: "d@3.0.0", Licenses: []string{"Apache-2.0", "MIT"}, Evidence: EvidenceManifest},
+		{ID: "c@0.1.0", Licenses: []string{"Apache-2.0"}, Evidence: EvidenceReadme},
+		{ID: "e@1.1.0", Licenses: nil, Evidence: ""},
+		// e/node_modules/a is the same a@1.0.0, so it is only reported once
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyNodeModules() mismatch (-want +got):\n%s", d)
+	}
+}
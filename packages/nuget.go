@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+const nupkgSuffix = ".nupkg"
+
+// IsNuGetPackage returns true for .nupkg file names
+func IsNuGetPackage(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), nupkgSuffix)
+}
+
+// IdentifyNuGetPackage reports the licenses for a .nupkg package.
+// The license files at the top of the package, and the file named by the nuspec <license type="file">,
+// are the best evidence. Otherwise, the nuspec <license type="expression"> (or <licenseUrl>) is used.
+func IdentifyNuGetPackage(ex *extractor.Extractor, nupkgPath string, options identifier.Options, ll *licenses.LicenseLibrary) Package {
+	p := Package{Ecosystem: manifest.NuGet, Name: filepath.Base(nupkgPath), Path: nupkgPath}
+	var d *manifest.Declared
+	scanned := make(map[string]bool)
+	err := ex.Walk(nupkgPath, func(name string, size int64, r io.Reader) error {
+		if path.Dir(name) != "." {
+			return nil
+		}
+		if strings.HasSuffix(name, manifest.NuSpec) {
+			b, err := extractor.ReadEntry(name, r)
+			if err != nil {
+				return err
+			}
+			d, err = manifest.ParseBytes(extractor.EntryPath(nupkgPath, name), b)
+			return err
+		}
+		if IsLicenseFile(name) {
+			scanned[name] = true
+			p.identifyEntry(nupkgPath, name, r, options, ll)
+		}
+		return nil
+	})
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	if d == nil {
+		p.Error = fmt.Sprintf("no %v found in %v", manifest.NuSpec, nupkgPath)
+		return p
+	}
+	p.Name = d.Name
+	p.Version = d.Version
+	p.Declared = d.Licenses
+
+	// The nuspec may name a license file anywhere in the package (and the nuspec may come after it)
+	if licenseFile := strings.TrimPrefix(filepath.ToSlash(d.LicenseFile), "/"); licenseFile != "" && !scanned[licenseFile] {
+		err := ex.Walk(nupkgPath, func(name string, size int64, r io.Reader) error {
+			if name == licenseFile {
+				p.identifyEntry(nupkgPath, name, r, options, ll)
+			}
+			return nil
+		})
+		if err != nil {
+			p.Error = err.Error()
+			return p
+		}
+	}
+	if len(p.Licenses) > 0 {
+		return p
+	}
+	for _, value := range p.Declared {
+		p.addLicenses(manifest.ResolveIDs(value, ll), EvidenceManifest)
+	}
+	return p
+}
@@ -0,0 +1,149 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/mrutkows/sbom-utility/log"
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+var (
+	Logger = log.NewLogger(log.INFO)
+
+	// licenseFileRE recognizes the files which are usually the license evidence for a package
+	// (including prefixed names like MIT-LICENSE or apache_license.txt, but not sources like check_license.go)
+	licenseFileRE = regexp.MustCompile(`(?i)^(?:un)?licen[cs]e|^copying|^notice|^copyright|^legal|[-_]licen[cs]e(?:\.(?:txt|md|rst))?$`)
+	readmeFileRE  = regexp.MustCompile(`(?i)^readme`)
+)
+
+// Evidence describes where the license IDs for a package came from
+const (
+	EvidenceLicenseFile = "license file"
+	EvidenceManifest    = "manifest"
+	EvidenceReadme      = "readme"
+)
+
+// Package holds the license findings for one package (module, node package, distribution, or artifact)
+type Package struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	// Path is the directory (or archive) containing the package
+	Path string
+	// Licenses are the license IDs detected for the package, sorted
+	Licenses []string
+	// Evidence is the kind of evidence the licenses were taken from
+	Evidence string
+	// Declared are the license values declared in the package manifest, if any
+	Declared []string
+	// Files are the results for the files used as license evidence
+	Files []identifier.IdentifierResults
+	// Error describes why the package could not be scanned, if it could not
+	Error string
+}
+
+// ID returns name@version (or just name without a version).
+// Maven artifacts use groupId:artifactId:version.
+func (p Package) ID() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	if p.Ecosystem == manifest.Maven {
+		return p.Name + ":" + p.Version
+	}
+	return p.Name + "@" + p.Version
+}
+
+// IsLicenseFile returns true for file names like LICENSE, COPYING.txt, NOTICE.md
+func IsLicenseFile(name string) bool {
+	return licenseFileRE.MatchString(filepath.Base(name))
+}
+
+// IsReadmeFile returns true for file names like README, readme.md
+func IsReadmeFile(name string) bool {
+	return readmeFileRE.MatchString(filepath.Base(name))
+}
+
+// addResult adds a file result to the package and merges the license IDs
+func (p *Package) addResult(r identifier.IdentifierResults, evidence string) {
+	p.Files = append(p.Files, r)
+	var ids []string
+	for id := range r.Matches {
+		ids = append(ids, id)
+	}
+	p.addLicenses(ids, evidence)
+}
+
+// addLicenses merges license IDs from the given kind of evidence
+func (p *Package) addLicenses(ids []string, evidence string) {
+	for _, id := range ids {
+		if !slices.Contains(p.Licenses, id) {
+			p.Licenses = append(p.Licenses, id)
+		}
+		p.Evidence = evidence
+	}
+	sort.Strings(p.Licenses)
+}
+
+// canceled returns the error of the canceled Context of the options, or nil when the scan goes on
+func canceled(options identifier.Options) error {
+	if options.Context == nil {
+		return nil
+	}
+	return options.Context.Err()
+}
+
+// identifyEntry identifies a license file read from an archive entry
+func (p *Package) identifyEntry(archivePath string, name string, r io.Reader, options identifier.Options, ll *licenses.LicenseLibrary) {
+	b, err := extractor.ReadEntry(name, r)
+	if err != nil {
+		Logger.Debugf("skipping %v: %v", name, err)
+		return
+	}
+	result, err := identifier.IdentifyLicensesInString(string(b), options, ll)
+	if err != nil {
+		Logger.Debugf("skipping %v: %v", name, err)
+		return
+	}
+	result.File = extractor.EntryPath(archivePath, name)
+	p.addResult(result, EvidenceLicenseFile)
+}
+
+// identifyLicenseFilesInDir identifies the license files at the top of a package directory
+func (p *Package) identifyLicenseFilesInDir(dir string, options identifier.Options, ll *licenses.LicenseLibrary) error {
+	return p.identifyFilesInDir(dir, IsLicenseFile, EvidenceLicenseFile, options, ll)
+}
+
+// identifyFilesInDir identifies the files at the top of a directory which are selected by isEvidence
+func (p *Package) identifyFilesInDir(dir string, isEvidence func(string) bool, evidence string, options identifier.Options, ll *licenses.LicenseLibrary) error {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, de := range des {
+		if canceled(options) != nil {
+			break
+		}
+		if de.IsDir() || !isEvidence(de.Name()) {
+			continue
+		}
+		r, err := identifier.IdentifyLicensesInFile(filepath.Join(dir, de.Name()), options, ll)
+		if err != nil {
+			Logger.Debugf("skipping %v: %v", de.Name(), err)
+			continue
+		}
+		p.addResult(r, evidence)
+	}
+	return nil
+}
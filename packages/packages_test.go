@@ -0,0 +1,81 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIsLicenseFile(t *testing.T) {
+	t.Parallel()
+	tests := map[string]bool{
+		"LICENSE":             true,
+		"License.txt":         true,
+		"LICENCE-MIT":         true,
+		"UNLICENSE":           true,
+		"COPYING.LESSER":      true,
+		"NOTICE.md":           true,
+		"vendor/x/COPYRIGHT":  true,
+		"README.md":           false,
+		"main.go":             false,
+		"licensed_product.go": true,
+		"MIT-LICENSE":         true,
+		"apache_license.txt":  true,
+		"sublicenses.go":      false,
+		"BSD-License.md":      true,
+		"gpl_licence.rst":     true,
+		"check_license.go":    false,
+		"validate-license.rb": false,
+		"x-license.txt.bak":   false,
+	}
+	for name, expected := range tests {
+		if got := IsLicenseFile(name); got != expected {
+			t.Errorf("IsLicenseFile(%v) expected %v got %v", name, expected, got)
+		}
+	}
+}
+
+func TestIdentify_canceled(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	options := identifier.Options{Context: ctx}
+
+	tests := map[string]func() ([]Package, error){
+		"go modules":   func() ([]Package, error) { return IdentifyGoModules("../testdata/gomod", options, ll) },
+		"node modules": func() ([]Package, error) { return IdentifyNodeModules("../testdata/npm", options, ll) },
+		"packages": func() ([]Package, error) {
+			return IdentifyPackages("../testdata/python", extractor.DefaultLimits, options, ll)
+		},
+	}
+	for name, identify := range tests {
+		name, identify := name, identify
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			pkgs, err := identify()
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("error = %v, want %v", err, context.Canceled)
+			}
+			for _, p := range pkgs {
+				if len(p.Licenses) > 0 {
+					t.Errorf("%v licenses = %v, want none after the cancellation", p.ID(), p.Licenses)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+const (
+	wheelSuffix     = ".whl"
+	distInfo        = ".dist-info"
+	distLicensesDir = "licenses" // PEP 639 puts license files under .dist-info/licenses/
+)
+
+var sdistSuffixes = []string{".tar.gz", ".tgz", ".zip"}
+
+// IsPythonDistribution returns true for wheel (.whl) and sdist (.tar.gz, .tgz, .zip) file names. An archive with an
+// sdist name is only an sdist when it has a */PKG-INFO (otherwise it is a generic archive, e.g., a source tarball).
+func IsPythonDistribution(name string) bool {
+	lower := strings.ToLower(name)
+	if strings.HasSuffix(lower, wheelSuffix) {
+		return true
+	}
+	for _, suffix := range sdistSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IdentifyPythonDistribution reports the licenses for a wheel or sdist archive.
+// The license files in the .dist-info dir (wheel) or at the top of the sdist are the best evidence.
+// Otherwise, the METADATA or PKG-INFO License, License-Expression, and license classifiers are used.
+func IdentifyPythonDistribution(ex *extractor.Extractor, archivePath string, options identifier.Options, ll *licenses.LicenseLibrary) Package {
+	p, _ := identifyPythonDistribution(ex, archivePath, options, ll)
+	return p
+}
+
+// identifyPythonDistribution is IdentifyPythonDistribution, which also returns false for an archive with an sdist
+// name without a */PKG-INFO, which is not a Python distribution
+func identifyPythonDistribution(ex *extractor.Extractor, archivePath string, options identifier.Options, ll *licenses.LicenseLibrary) (Package, bool) {
+	p := Package{Ecosystem: manifest.PyPI, Name: filepath.Base(archivePath), Path: archivePath}
+	isWheel := strings.HasSuffix(strings.ToLower(archivePath), wheelSuffix)
+	var d *manifest.Declared
+	hasMetadata := false
+	err := ex.Walk(archivePath, func(name string, size int64, r io.Reader) error {
+		isMetadata, isLicense := classifyPythonEntry(name)
+		if isLicense {
+			p.identifyEntry(archivePath, name, r, options, ll)
+		}
+		if !isMetadata {
+			return nil
+		}
+		hasMetadata = true
+		b, err := extractor.ReadEntry(name, r)
+		if err != nil {
+			return err
+		}
+		d, err = manifest.ParseBytes(extractor.EntryPath(archivePath, name), b)
+		return err
+	})
+	if err != nil {
+		p.Error = err.Error()
+		return p, isWheel || hasMetadata
+	}
+	if d == nil {
+		p.Error = fmt.Sprintf("no %v or %v found in %v", manifest.Metadata, manifest.PkgInfo, archivePath)
+		return p, isWheel
+	}
+
+	p.Name = d.Name
+	p.Version = d.Version
+	p.Declared = d.Licenses
+	if len(p.Licenses) > 0 {
+		return p, true
+	}
+	for _, value := range p.Declared {
+		p.addLicenses(manifest.ResolveIDs(value, ll), EvidenceManifest)
+	}
+	return p, true
+}
+
+// classifyPythonEntry decides if an archive entry is the core metadata or a license file.
+// Wheels have name-version.dist-info/METADATA. Sdists have name-version/PKG-INFO at the top.
+func classifyPythonEntry(name string) (isMetadata bool, isLicense bool) {
+	dir, base := path.Split(strings.TrimPrefix(name, "./"))
+	dir = strings.TrimSuffix(dir, "/")
+	parts := strings.Split(dir, "/")
+
+	if strings.HasSuffix(parts[0], distInfo) {
+		if len(parts) == 1 {
+			return base == manifest.Metadata, IsLicenseFile(base)
+		}
+		return false, parts[1] == distLicensesDir
+	}
+	if len(parts) == 1 && parts[0] != "" {
+		return base == manifest.PkgInfo, IsLicenseFile(base)
+	}
+	return false, false
+}
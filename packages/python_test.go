@@ -0,0 +1,115 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIdentifyPackages(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyPackages("../testdata/python", extractor.DefaultLimits, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyPackages() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Licenses []string
+		Evidence string
+		Files    []string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		s := summary{ID: p.ID(), Licenses: p.Licenses, Evidence: p.Evidence}
+		for _, f := range p.Files {
+			s.Files = append(s.Files, f.File)
+		}
+		got = append(got, s)
+	}
+	expected := []summary{
+		{ID: "declared-only@0.2", Licenses: []string{"Apache-2.0", "BSD-3-Clause"}, Evidence: EvidenceManifest},
+		{
+			ID: "demo@1.0", Licenses: []string{"MIT"}, Evidence: EvidenceLicenseFile,
+			Files: []string{"../testdata/python/demo-1.0-py3-none-any.whl!/demo-1.0.dist-info/LICENSE"},
+		},
+		{
+			ID: "sdist@3.0", Licenses: []string{"ISC"}, Evidence: EvidenceLicenseFile,
+			Files: []string{"../testdata/python/sdist-3.0.tar.gz!/sdist-3.0/COPYING"},
+		},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyPackages() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestClassifyPythonEntry(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		isMetadata bool
+		isLicense  bool
+	}{
+		{name: "x-1.0.dist-info/METADATA", isMetadata: true},
+		{name: "x-1.0.dist-info/LICENSE.txt", isLicense: true},
+		{name: "x-1.0.dist-info/licenses/vendored/NOTICE", isLicense: true},
+		{name: "x-1.0.dist-info/RECORD"},
+		{name: "x-1.0/PKG-INFO", isMetadata: true},
+		{name: "./x-1.0/COPYING", isLicense: true},
+		{name: "x-1.0/x.egg-info/PKG-INFO"},
+		{name: "x-1.0/docs/LICENSE"},
+		{name: "LICENSE"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			isMetadata, isLicense := classifyPythonEntry(tt.name)
+			if isMetadata != tt.isMetadata || isLicense != tt.isLicense {
+				t.Errorf("classifyPythonEntry() = %v, %v want %v, %v", isMetadata, isLicense, tt.isMetadata, tt.isLicense)
+			}
+		})
+	}
+}
+
+func TestIdentifyPythonDistributionSdist(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	tests := []struct {
+		name     string
+		archive  string
+		expected bool
+	}{
+		{name: "sdist with a PKG-INFO", archive: "../testdata/python/sdist-3.0.tar.gz", expected: true},
+		{name: "source tarball", archive: "../testdata/nested/src-1.0.tar.gz"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			p, ok := identifyPythonDistribution(extractor.New(extractor.DefaultLimits), tt.archive, identifier.Options{}, ll)
+			if ok != tt.expected {
+				t.Errorf("identifyPythonDistribution() = %+v, %v want %v", p, ok, tt.expected)
+			}
+		})
+	}
+}
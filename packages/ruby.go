@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package packages
+
+import (
+	"compress/gzip"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+const (
+	gemSuffix     = ".gem"
+	gemMetadataGz = "metadata.gz"
+	gemDataTarGz  = "data.tar.gz"
+)
+
+// IsGem returns true for .gem file names
+func IsGem(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), gemSuffix)
+}
+
+// IdentifyGem reports the licenses for a .gem package (a tar of metadata.gz and data.tar.gz).
+// The license files at the top of the gem's data are the best evidence.
+// Otherwise, the licenses in the gem specification (metadata.gz) are used.
+func IdentifyGem(ex *extractor.Extractor, gemPath string, options identifier.Options, ll *licenses.LicenseLibrary) Package {
+	p := Package{Ecosystem: manifest.RubyGems, Name: filepath.Base(gemPath), Path: gemPath}
+	var d *manifest.Declared
+	err := ex.Walk(gemPath, func(name string, size int64, r io.Reader) error {
+		switch name {
+		case gemMetadataGz:
+			gz, err := gzip.NewReader(r)
+			if err != nil {
+				return err
+			}
+			defer gz.Close()
+			b, err := extractor.ReadEntry(name, gz)
+			if err != nil {
+				return err
+			}
+			d, err = manifest.ParseGemMetadata(extractor.EntryPath(gemPath, name), b)
+			return err
+		case gemDataTarGz:
+			dataPath := extractor.EntryPath(gemPath, name)
+			return ex.WalkTar(r, true, func(name string, size int64, r io.Reader) error {
+				name = strings.TrimPrefix(name, "./")
+				if path.Dir(name) != "." || !IsLicenseFile(name) {
+					return nil
+				}
+				p.identifyEntry(dataPath, name, r, options, ll)
+				return nil
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	if d != nil {
+		p.Name = d.Name
+		p.Version = d.Version
+		p.Declared = d.Licenses
+	}
+	if len(p.Licenses) > 0 {
+		return p
+	}
+	for _, value := range p.Declared {
+		p.addLicenses(manifest.ResolveIDs(value, ll), EvidenceManifest)
+	}
+	return p
+}
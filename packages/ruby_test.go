@@ -0,0 +1,57 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package packages
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/extractor"
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestIdentifyGems(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+
+	pkgs, err := IdentifyPackages("../testdata/ruby", extractor.DefaultLimits, identifier.Options{}, ll)
+	if err != nil {
+		t.Fatalf("IdentifyPackages() error = %v", err)
+	}
+
+	type summary struct {
+		ID       string
+		Licenses []string
+		Evidence string
+		Declared []string
+		Files    []string
+	}
+	var got []summary
+	for _, p := range pkgs {
+		s := summary{ID: p.ID(), Licenses: p.Licenses, Evidence: p.Evidence, Declared: p.Declared}
+		for _, f := range p.Files {
+			s.Files = append(s.Files, f.File)
+		}
+		got = append(got, s)
+	}
+	expected := []summary{
+		{ID: "declared@1.0", Licenses: []string{"BSD-2-Clause", "Ruby"}, Evidence: EvidenceManifest, Declared: []string{"Ruby", "BSD-2-Clause"}},
+		{
+			ID: "demo@0.3.0", Licenses: []string{"MIT"}, Evidence: EvidenceLicenseFile, Declared: []string{"MIT"},
+			Files: []string{"../testdata/ruby/demo-0.3.0.gem!/data.tar.gz!/MIT-LICENSE"},
+		},
+	}
+	if d := cmp.Diff(expected, got); d != "" {
+		t.Errorf("IdentifyPackages() mismatch (-want +got):\n%s", d)
+	}
+}
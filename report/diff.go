@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package report compares the JSON scan reports of two scans, renders custom reports of the scan results with Go templates,
+// and summarizes the obligations of the detected licenses
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/baseline"
+	"github.com/IBM/license-scanner/jsonl"
+	"github.com/IBM/license-scanner/licensee"
+)
+
+// ErrNotReport is returned for JSON which is not a scan report
+var ErrNotReport = errors.New("not a licensee (--format licensee), JSON Lines (--format jsonl), or baseline (--writeBaseline) scan report")
+
+// Report has the license IDs detected in each file of a scan
+type Report struct {
+	Files map[string][]string
+}
+
+// FileDiff is a file whose detected licenses changed. Old is nil for a new file and New is nil for a
+// removed file.
+type FileDiff struct {
+	File string
+	Old  []string
+	New  []string
+}
+
+// Diff is the difference between two scan reports
+type Diff struct {
+	// NewLicenses are detected in the new report but not in the old report
+	NewLicenses []string
+	// RemovedLicenses are detected in the old report but not in the new report
+	RemovedLicenses []string
+	// Files are the files whose detected licenses changed (sorted by path)
+	Files []FileDiff
+}
+
+// Empty is true when the detected licenses did not change
+func (d Diff) Empty() bool {
+	return len(d.NewLicenses) == 0 && len(d.RemovedLicenses) == 0 && len(d.Files) == 0
+}
+
+// Load reads a JSON scan report: the licensee output of --format licensee, the JSON Lines of --format jsonl,
+// or a baseline file
+func Load(filePath string) (*Report, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	r, err := Parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the scan report %v: %w", filePath, err)
+	}
+	return r, nil
+}
+
+// Parse reads a JSON scan report: the licensee output of --format licensee, the JSON Lines of --format jsonl,
+// or a baseline file
+func Parse(b []byte) (*Report, error) {
+	var keys map[string]json.RawMessage
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if err := dec.Decode(&keys); err != nil {
+		return nil, err
+	}
+	r := &Report{Files: make(map[string][]string)}
+	add := func(file string, id string) {
+		if !slices.Contains(r.Files[file], id) {
+			r.Files[file] = append(r.Files[file], id)
+			sort.Strings(r.Files[file])
+		}
+	}
+	if keys["file"] != nil && keys["licenses"] != nil {
+		dec = json.NewDecoder(bytes.NewReader(b))
+		for {
+			var record jsonl.Record
+			if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, err
+			}
+			for _, id := range record.Licenses {
+				add(record.File, id)
+			}
+		}
+		return r, nil
+	}
+	if dec.More() {
+		return nil, ErrNotReport
+	}
+	switch {
+	case keys["matched_files"] != nil:
+		var out licensee.Output
+		if err := json.Unmarshal(b, &out); err != nil {
+			return nil, err
+		}
+		for _, f := range out.MatchedFiles {
+			add(f.Filename, f.MatchedLicense)
+		}
+	case keys["findings"] != nil:
+		var out baseline.Baseline
+		if err := json.Unmarshal(b, &out); err != nil {
+			return nil, err
+		}
+		for _, f := range out.Findings {
+			add(f.File, f.License)
+		}
+	default:
+		return nil, ErrNotReport
+	}
+	return r, nil
+}
+
+// Compare returns the licenses and files which changed from the report before to the report after
+func Compare(before *Report, after *Report) Diff {
+	var d Diff
+	oldIDs, newIDs := before.licenses(), after.licenses()
+	for id := range newIDs {
+		if !oldIDs[id] {
+			d.NewLicenses = append(d.NewLicenses, id)
+		}
+	}
+	for id := range oldIDs {
+		if !newIDs[id] {
+			d.RemovedLicenses = append(d.RemovedLicenses, id)
+		}
+	}
+	for file, ids := range before.Files {
+		if newFileIDs, ok := after.Files[file]; !ok {
+			d.Files = append(d.Files, FileDiff{File: file, Old: ids})
+		} else if !slices.Equal(ids, newFileIDs) {
+			d.Files = append(d.Files, FileDiff{File: file, Old: ids, New: newFileIDs})
+		}
+	}
+	for file, ids := range after.Files {
+		if _, ok := before.Files[file]; !ok {
+			d.Files = append(d.Files, FileDiff{File: file, New: ids})
+		}
+	}
+	sort.Strings(d.NewLicenses)
+	sort.Strings(d.RemovedLicenses)
+	sort.Slice(d.Files, func(i, j int) bool { return d.Files[i].File < d.Files[j].File })
+	return d
+}
+
+// licenses returns the license IDs detected in any file
+func (r *Report) licenses() map[string]bool {
+	ret := make(map[string]bool)
+	for _, ids := range r.Files {
+		for _, id := range ids {
+			ret[id] = true
+		}
+	}
+	return ret
+}
@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const licenseeReport = `{
+  "licenses": [{"key": "mit", "spdx_id": "MIT"}],
+  "matched_files": [
+    {"filename": "LICENSE", "matched_license": "MIT", "matcher": {"name": "exact", "confidence": 100}},
+    {"filename": "vendor/lib/COPYING", "matched_license": "BSD-3-Clause"},
+    {"filename": "NOTICE", "matched_license": "NOASSERTION"}
+  ]
+}`
+
+const baselineReport = `{
+  "findings": [
+    {"file": "LICENSE", "hash": "aaa", "license": "MIT"},
+    {"file": "vendor/lib/COPYING", "hash": "bbb", "license": "GPL-2.0-only"},
+    {"file": "vendor/lib/COPYING", "hash": "bbb", "license": "MIT"},
+    {"file": "src/util.c", "hash": "ccc", "license": "Apache-2.0"}
+  ]
+}`
+
+const jsonlReport = `{"file":"LICENSE","hash":"aaa","licenses":["MIT"],"matches":{"MIT":[{"begins":0,"ends":1077}]}}
+{"file":"src/main.go","hash":"ddd","licenses":[],"matches":{}}
+{"file":"vendor/lib/COPYING","hash":"bbb","licenses":["BSD-3-Clause","MIT"],"matches":{}}
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		json    string
+		want    *Report
+		wantErr error
+	}{
+		{
+			name: "licensee",
+			json: licenseeReport,
+			want: &Report{Files: map[string][]string{"LICENSE": {"MIT"}, "vendor/lib/COPYING": {"BSD-3-Clause"}, "NOTICE": {"NOASSERTION"}}},
+		},
+		{
+			name: "baseline",
+			json: baselineReport,
+			want: &Report{Files: map[string][]string{"LICENSE": {"MIT"}, "vendor/lib/COPYING": {"GPL-2.0-only", "MIT"}, "src/util.c": {"Apache-2.0"}}},
+		},
+		{
+			name: "JSON Lines",
+			json: jsonlReport,
+			want: &Report{Files: map[string][]string{"LICENSE": {"MIT"}, "vendor/lib/COPYING": {"BSD-3-Clause", "MIT"}}},
+		},
+		{
+			name:    "not a report",
+			json:    `{"packages": []}`,
+			wantErr: ErrNotReport,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Parse([]byte(tt.json))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	before, err := Parse([]byte(baselineReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := Parse([]byte(licenseeReport))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Diff{
+		NewLicenses:     []string{"BSD-3-Clause", "NOASSERTION"},
+		RemovedLicenses: []string{"Apache-2.0", "GPL-2.0-only"},
+		Files: []FileDiff{
+			{File: "NOTICE", New: []string{"NOASSERTION"}},
+			{File: "src/util.c", Old: []string{"Apache-2.0"}},
+			{File: "vendor/lib/COPYING", Old: []string{"GPL-2.0-only", "MIT"}, New: []string{"BSD-3-Clause"}},
+		},
+	}
+	got := Compare(before, after)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Compare() mismatch (-want +got):\n%s", d)
+	}
+	if got.Empty() {
+		t.Errorf("Empty() = true")
+	}
+	if d := Compare(after, after); !d.Empty() {
+		t.Errorf("Compare() of the same report = %+v", d)
+	}
+}
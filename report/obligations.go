@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"sort"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// Obligation is what the detected licenses require of the user, with the licenses which require it
+type Obligation struct {
+	// Name is the obligation (e.g., attribution, source-disclosure)
+	Name string
+	// Description is the obligation in words ("" for a custom obligation without a description)
+	Description string
+	// Licenses are the detected license IDs with the obligation (sorted)
+	Licenses []string
+}
+
+// Obligations summarizes the obligations of the licenses detected in the results (sorted by name).
+// The detected licenses which have no obligation rule are returned as unknown (sorted), since what
+// they require must be reviewed.
+func Obligations(results []identifier.IdentifierResults) (obligations []Obligation, unknown []string) {
+	byName := make(map[string]map[string]bool)
+	unknownIDs := make(map[string]bool)
+	for _, result := range results {
+		for id := range result.Matches {
+			names, ok := result.Obligations[id]
+			if !ok {
+				unknownIDs[id] = true
+				continue
+			}
+			for _, name := range names {
+				if byName[name] == nil {
+					byName[name] = make(map[string]bool)
+				}
+				byName[name][id] = true
+			}
+		}
+	}
+	obligations = []Obligation{}
+	for name, ids := range byName {
+		o := Obligation{Name: name, Description: licenses.ObligationDescriptions[name], Licenses: []string{}}
+		for id := range ids {
+			o.Licenses = append(o.Licenses, id)
+		}
+		sort.Strings(o.Licenses)
+		obligations = append(obligations, o)
+	}
+	sort.Slice(obligations, func(i, j int) bool { return obligations[i].Name < obligations[j].Name })
+	unknown = []string{}
+	for id := range unknownIDs {
+		unknown = append(unknown, id)
+	}
+	sort.Strings(unknown)
+	return obligations, unknown
+}
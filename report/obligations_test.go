@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestObligations(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{
+			File:        "LICENSE",
+			Matches:     map[string][]identifier.Match{"MIT": {{}}, "GPL-2.0-only": {{}}},
+			Obligations: map[string][]string{"MIT": {licenses.Attribution, licenses.IncludeLicense}, "GPL-2.0-only": {licenses.Attribution, licenses.SourceDisclosure}},
+		},
+		{File: "README.md"},
+		{
+			File:        "src/main.go",
+			Matches:     map[string][]identifier.Match{"MIT": {{}}, "Acme-1.0": {{}}, "CC0-1.0": {{}}},
+			Obligations: map[string][]string{"MIT": {licenses.Attribution, licenses.IncludeLicense}, "CC0-1.0": {}},
+		},
+	}
+	wantObligations := []Obligation{
+		{Name: licenses.Attribution, Description: licenses.ObligationDescriptions[licenses.Attribution], Licenses: []string{"GPL-2.0-only", "MIT"}},
+		{Name: licenses.IncludeLicense, Description: licenses.ObligationDescriptions[licenses.IncludeLicense], Licenses: []string{"MIT"}},
+		{Name: licenses.SourceDisclosure, Description: licenses.ObligationDescriptions[licenses.SourceDisclosure], Licenses: []string{"GPL-2.0-only"}},
+	}
+	obligations, unknown := Obligations(results)
+	if d := cmp.Diff(wantObligations, obligations); d != "" {
+		t.Errorf("Obligations() mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"Acme-1.0"}, unknown); d != "" {
+		t.Errorf("Obligations() unknown mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+	"gopkg.in/yaml.v3"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+// The risk levels (from the lowest to the highest)
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// The linking contexts (how the licensed code is linked with the user's code)
+const (
+	StaticLinking  = "static"
+	DynamicLinking = "dynamic"
+)
+
+// The distribution contexts (how the user's product is delivered)
+const (
+	InternalDistribution = "internal"
+	NetworkDistribution  = "network"
+	Distributed          = "distributed"
+)
+
+// riskLevels ranks the risk levels
+var riskLevels = map[string]int{RiskLow: 1, RiskMedium: 2, RiskHigh: 3}
+
+// RiskContext is how the scanned code is used, which changes the risk of some licenses (e.g., copyleft
+// is not triggered by internal use)
+type RiskContext struct {
+	// Linking is static or dynamic
+	Linking string
+	// Distribution is internal, network (e.g., SaaS), or distributed
+	Distribution string
+}
+
+// DefaultRiskContext is dynamic linking in a distributed product
+var DefaultRiskContext = RiskContext{Linking: DynamicLinking, Distribution: Distributed}
+
+// Validate returns an error for an unknown linking or distribution
+func (c RiskContext) Validate() error {
+	if c.Linking != StaticLinking && c.Linking != DynamicLinking {
+		return fmt.Errorf("invalid linking %q (expected %v or %v)", c.Linking, StaticLinking, DynamicLinking)
+	}
+	if c.Distribution != InternalDistribution && c.Distribution != NetworkDistribution && c.Distribution != Distributed {
+		return fmt.Errorf("invalid distribution %q (expected %v, %v, or %v)", c.Distribution, InternalDistribution, NetworkDistribution, Distributed)
+	}
+	return nil
+}
+
+// RiskRule is the risk level of the licenses with the category or the obligation (WITH the exception) in a context.
+// The empty fields match anything. The first matching rule wins.
+type RiskRule struct {
+	Category   string `json:"category,omitempty" yaml:"category,omitempty"`
+	Obligation string `json:"obligation,omitempty" yaml:"obligation,omitempty"`
+	// Exception only matches the licenses WITH the exception ID (e.g., Classpath-exception-2.0, which lifts the
+	// copyleft of linking)
+	Exception    string `json:"exception,omitempty" yaml:"exception,omitempty"`
+	Linking      string `json:"linking,omitempty" yaml:"linking,omitempty"`
+	Distribution string `json:"distribution,omitempty" yaml:"distribution,omitempty"`
+	Level        string `json:"level" yaml:"level"`
+}
+
+// RiskModel maps the license categories and the contexts to risk levels (a YAML or JSON file)
+type RiskModel struct {
+	// Rules are checked first, in order
+	Rules []RiskRule `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// Categories are the risk levels of the license categories when no rule matches
+	Categories map[string]string `json:"categories,omitempty" yaml:"categories,omitempty"`
+	// Default is the risk level of the licenses without a category (or a category without a level)
+	Default string `json:"default" yaml:"default"`
+}
+
+// DefaultRiskModel is the built-in risk model
+var DefaultRiskModel = RiskModel{
+	Rules: []RiskRule{
+		// Network copyleft is triggered by network use, but not by internal use
+		{Obligation: licenses.NetworkCopyleft, Distribution: InternalDistribution, Level: RiskLow},
+		{Obligation: licenses.NetworkCopyleft, Level: RiskHigh},
+		// Copyleft is only triggered by distribution
+		{Category: licenses.StrongCopyleft, Distribution: InternalDistribution, Level: RiskLow},
+		{Category: licenses.StrongCopyleft, Distribution: NetworkDistribution, Level: RiskMedium},
+		// A linking exception (e.g., GPL-2.0-only WITH Classpath-exception-2.0) makes strong copyleft like weak copyleft
+		{Category: licenses.StrongCopyleft, Exception: "Classpath-exception-2.0", Linking: StaticLinking, Level: RiskHigh},
+		{Category: licenses.StrongCopyleft, Exception: "Classpath-exception-2.0", Level: RiskMedium},
+		{Category: licenses.WeakCopyleft, Distribution: InternalDistribution, Level: RiskLow},
+		// Static linking with weak copyleft requires the relinkable objects or the source of the whole
+		{Category: licenses.WeakCopyleft, Linking: StaticLinking, Level: RiskHigh},
+	},
+	Categories: map[string]string{
+		licenses.PublicDomain:   RiskLow,
+		licenses.Permissive:     RiskLow,
+		licenses.WeakCopyleft:   RiskMedium,
+		licenses.StrongCopyleft: RiskHigh,
+		licenses.Proprietary:    RiskHigh,
+	},
+	Default: RiskHigh,
+}
+
+// LoadRiskModel reads a risk model file (YAML or JSON)
+func LoadRiskModel(filePath string) (*RiskModel, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	m := &RiskModel{}
+	if err := yaml.Unmarshal(b, m); err != nil {
+		return nil, fmt.Errorf("cannot parse the risk model %v: %w", filePath, err)
+	}
+	if err := m.validate(); err != nil {
+		return nil, fmt.Errorf("invalid risk model %v: %w", filePath, err)
+	}
+	return m, nil
+}
+
+// validate returns an error for an unknown risk level
+func (m *RiskModel) validate() error {
+	if _, ok := riskLevels[m.Default]; !ok {
+		return fmt.Errorf("unknown default level %q (expected %v, %v, or %v)", m.Default, RiskLow, RiskMedium, RiskHigh)
+	}
+	for category, level := range m.Categories {
+		if _, ok := riskLevels[level]; !ok {
+			return fmt.Errorf("unknown level %q of category %v", level, category)
+		}
+	}
+	for i, rule := range m.Rules {
+		if _, ok := riskLevels[rule.Level]; !ok {
+			return fmt.Errorf("unknown level %q of rule %v", rule.Level, i+1)
+		}
+	}
+	return nil
+}
+
+// Level returns the risk level of a license with the category and obligations in the context
+func (m *RiskModel) Level(category string, obligations []string, context RiskContext) string {
+	return m.LevelWith(category, obligations, "", context)
+}
+
+// LevelWith returns the risk level of a license WITH the exception ("" for none) with the category and obligations
+// in the context. The rules with an Exception only match the licenses with that exception.
+func (m *RiskModel) LevelWith(category string, obligations []string, exception string, context RiskContext) string {
+	for _, rule := range m.Rules {
+		if rule.Category != "" && rule.Category != category {
+			continue
+		}
+		if rule.Exception != "" && rule.Exception != exception {
+			continue
+		}
+		if rule.Obligation != "" && !slices.Contains(obligations, rule.Obligation) {
+			continue
+		}
+		if rule.Linking != "" && rule.Linking != context.Linking {
+			continue
+		}
+		if rule.Distribution != "" && rule.Distribution != context.Distribution {
+			continue
+		}
+		return rule.Level
+	}
+	if level, ok := m.Categories[category]; ok {
+		return level
+	}
+	return m.Default
+}
+
+// LicenseRisk is the risk level of a detected license
+type LicenseRisk struct {
+	ID       string
+	Category string
+	Level    string
+	// Files are the files in which the license was detected (sorted), except for the Choices
+	Files []string
+	// Choices are the files in which the license is only a choice of an allowed expression (sorted), e.g.,
+	// GPL-3.0-only in MIT OR GPL-3.0-only, so its risk is avoided by choosing the other license
+	Choices []string
+}
+
+// RiskSummary is the risk of the licenses detected in a scan
+type RiskSummary struct {
+	// Overall is the highest risk level of the detected licenses (low when no license was detected)
+	Overall string
+	// Context is the context of the assessment
+	Context RiskContext
+	// Licenses are the detected licenses (from the highest risk level, then by ID)
+	Licenses []LicenseRisk
+	// Counts are the number of detected licenses by risk level (without the licenses which are only Choices)
+	Counts map[string]int
+}
+
+// Avoided returns the matched license IDs of the result with the high risk level in the context which are only a
+// choice of an allowed expression (see IdentifierResults.Expression), e.g., GPL-3.0-only in MIT OR GPL-3.0-only,
+// or which are allowed with their exception, e.g., GPL-2.0-only WITH Classpath-exception-2.0. The expressions are
+// evaluated with licenses.Expression.Allowed, so an OR is allowed when any choice is not high risk, an AND when every
+// license is not, and a license WITH an exception with the level of the rules of the exception (see LevelWith).
+func (m *RiskModel) Avoided(result identifier.IdentifierResults, context RiskContext) map[string]bool {
+	// A license ID which is mapped to another ID (e.g., a LicenseRef) has the classification of the matched ID
+	original := make(map[string]string)
+	for id := range result.Matches {
+		if mapped := result.Mapped(id); mapped != id && !strings.Contains(mapped, " ") {
+			original[mapped] = id
+		}
+	}
+	level := func(id string, exception string) string {
+		if o, ok := original[id]; ok {
+			id = o
+		}
+		return m.LevelWith(result.Classifications[id].Category, result.Obligations[id], exception, context)
+	}
+	allowed := func(license *licenses.Expression) bool {
+		return level(license.License, license.Exception) != RiskHigh
+	}
+	ret := make(map[string]bool)
+	for id := range result.Matches {
+		if level(id, "") != RiskHigh {
+			continue
+		}
+		if e, err := licenses.ParseExpression(result.Expression(id)); err == nil && e.Allowed(allowed) {
+			ret[id] = true
+		}
+	}
+	return ret
+}
+
+// Assess returns the risk summary of the licenses detected in the results. The file paths are relative
+// to the root. The high risk licenses which are only a choice of an allowed expression, or allowed with their
+// exception, in a file (see Avoided) are not counted for that file.
+func (m *RiskModel) Assess(results []identifier.IdentifierResults, root string, context RiskContext) RiskSummary {
+	summary := RiskSummary{Overall: RiskLow, Context: context, Licenses: []LicenseRisk{}, Counts: make(map[string]int)}
+	byID := make(map[string]*LicenseRisk)
+	for _, result := range results {
+		file := relativePath(result.File, root)
+		avoided := m.Avoided(result, context)
+		for id := range result.Matches {
+			r, ok := byID[id]
+			if !ok {
+				category := result.Classifications[id].Category
+				r = &LicenseRisk{ID: id, Category: category, Level: m.Level(category, result.Obligations[id], context), Files: []string{}}
+				byID[id] = r
+			}
+			if avoided[id] {
+				if !slices.Contains(r.Choices, file) {
+					r.Choices = append(r.Choices, file)
+				}
+			} else if !slices.Contains(r.Files, file) {
+				r.Files = append(r.Files, file)
+			}
+		}
+	}
+	for _, r := range byID {
+		sort.Strings(r.Files)
+		sort.Strings(r.Choices)
+		summary.Licenses = append(summary.Licenses, *r)
+		if len(r.Files) == 0 {
+			continue // only a choice of allowed expressions
+		}
+		summary.Counts[r.Level]++
+		if riskLevels[r.Level] > riskLevels[summary.Overall] {
+			summary.Overall = r.Level
+		}
+	}
+	sort.Slice(summary.Licenses, func(i, j int) bool {
+		li, lj := summary.Licenses[i], summary.Licenses[j]
+		if riskLevels[li.Level] != riskLevels[lj.Level] {
+			return riskLevels[li.Level] > riskLevels[lj.Level]
+		}
+		return li.ID < lj.ID
+	})
+	return summary
+}
@@ -0,0 +1,232 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestRiskModel_Level(t *testing.T) {
+	t.Parallel()
+	agpl := []string{licenses.SourceDisclosure, licenses.NetworkCopyleft}
+	tests := []struct {
+		name        string
+		category    string
+		obligations []string
+		context     RiskContext
+		want        string
+	}{
+		{name: "permissive", category: licenses.Permissive, context: DefaultRiskContext, want: RiskLow},
+		{name: "weak copyleft dynamic", category: licenses.WeakCopyleft, context: DefaultRiskContext, want: RiskMedium},
+		{name: "weak copyleft static", category: licenses.WeakCopyleft, context: RiskContext{Linking: StaticLinking, Distribution: Distributed}, want: RiskHigh},
+		{name: "weak copyleft internal", category: licenses.WeakCopyleft, context: RiskContext{Linking: StaticLinking, Distribution: InternalDistribution}, want: RiskLow},
+		{name: "strong copyleft distributed", category: licenses.StrongCopyleft, context: DefaultRiskContext, want: RiskHigh},
+		{name: "strong copyleft network", category: licenses.StrongCopyleft, context: RiskContext{Linking: DynamicLinking, Distribution: NetworkDistribution}, want: RiskMedium},
+		{name: "network copyleft network", category: licenses.StrongCopyleft, obligations: agpl, context: RiskContext{Linking: DynamicLinking, Distribution: NetworkDistribution}, want: RiskHigh},
+		{name: "network copyleft internal", category: licenses.StrongCopyleft, obligations: agpl, context: RiskContext{Linking: DynamicLinking, Distribution: InternalDistribution}, want: RiskLow},
+		{name: "unclassified", context: DefaultRiskContext, want: RiskHigh},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := DefaultRiskModel.Level(tt.category, tt.obligations, tt.context); got != tt.want {
+				t.Errorf("Level() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRiskModel_Assess(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{
+			File:            "/repo/LICENSE",
+			Matches:         map[string][]identifier.Match{"MIT": {{}}},
+			Classifications: map[string]licenses.Classification{"MIT": {Category: licenses.Permissive}},
+		},
+		{File: "/repo/README.md"},
+		{
+			File:            "/repo/lib/COPYING",
+			Matches:         map[string][]identifier.Match{"LGPL-2.1-only": {{}}, "MIT": {{}}},
+			Classifications: map[string]licenses.Classification{"LGPL-2.1-only": {Category: licenses.WeakCopyleft}, "MIT": {Category: licenses.Permissive}},
+		},
+	}
+	want := RiskSummary{
+		Overall: RiskMedium,
+		Context: DefaultRiskContext,
+		Licenses: []LicenseRisk{
+			{ID: "LGPL-2.1-only", Category: licenses.WeakCopyleft, Level: RiskMedium, Files: []string{"lib/COPYING"}},
+			{ID: "MIT", Category: licenses.Permissive, Level: RiskLow, Files: []string{"LICENSE", "lib/COPYING"}},
+		},
+		Counts: map[string]int{RiskLow: 1, RiskMedium: 1},
+	}
+	if d := cmp.Diff(want, DefaultRiskModel.Assess(results, "/repo", DefaultRiskContext)); d != "" {
+		t.Errorf("Assess() mismatch (-want +got):\n%s", d)
+	}
+
+	empty := DefaultRiskModel.Assess(nil, "/repo", DefaultRiskContext)
+	if empty.Overall != RiskLow || len(empty.Licenses) != 0 {
+		t.Errorf("Assess(nil) = %+v, want low without licenses", empty)
+	}
+}
+
+func TestRiskModel_AssessChoices(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{
+			File:     "/repo/LICENSE",
+			Matches:  map[string][]identifier.Match{"GPL-3.0-only": {{}}, "MIT": {{}}},
+			Mappings: map[string]string{"GPL-3.0-only": "MIT OR GPL-3.0-only"},
+			Classifications: map[string]licenses.Classification{
+				"GPL-3.0-only": {Category: licenses.StrongCopyleft},
+				"MIT":          {Category: licenses.Permissive},
+			},
+		},
+	}
+	want := RiskSummary{
+		Overall: RiskLow,
+		Context: DefaultRiskContext,
+		Licenses: []LicenseRisk{
+			{ID: "GPL-3.0-only", Category: licenses.StrongCopyleft, Level: RiskHigh, Files: []string{}, Choices: []string{"LICENSE"}},
+			{ID: "MIT", Category: licenses.Permissive, Level: RiskLow, Files: []string{"LICENSE"}},
+		},
+		Counts: map[string]int{RiskLow: 1},
+	}
+	if d := cmp.Diff(want, DefaultRiskModel.Assess(results, "/repo", DefaultRiskContext)); d != "" {
+		t.Errorf("Assess() mismatch (-want +got):\n%s", d)
+	}
+
+	results[0].Mappings = nil
+	if got := DefaultRiskModel.Assess(results, "/repo", DefaultRiskContext); got.Overall != RiskHigh || got.Counts[RiskHigh] != 1 {
+		t.Errorf("Assess() without the choice = %+v, want a high risk license", got)
+	}
+}
+
+func TestRiskModel_Avoided(t *testing.T) {
+	t.Parallel()
+	gpl := map[string]licenses.Classification{"GPL-2.0-only": {Category: licenses.StrongCopyleft}}
+	tests := []struct {
+		name    string
+		result  identifier.IdentifierResults
+		context RiskContext
+		want    map[string]bool
+	}{
+		{
+			name:    "without exception",
+			result:  identifier.IdentifierResults{Matches: map[string][]identifier.Match{"GPL-2.0-only": {{}}}, Classifications: gpl},
+			context: DefaultRiskContext,
+			want:    map[string]bool{},
+		},
+		{
+			name: "exception lowers the level",
+			result: identifier.IdentifierResults{
+				Matches:         map[string][]identifier.Match{"GPL-2.0-only": {{}}, "Classpath-exception-2.0": {{}}},
+				Exceptions:      map[string]string{"GPL-2.0-only": "Classpath-exception-2.0"},
+				Classifications: gpl,
+			},
+			context: DefaultRiskContext,
+			want:    map[string]bool{"GPL-2.0-only": true, "Classpath-exception-2.0": true},
+		},
+		{
+			name: "exception with static linking",
+			result: identifier.IdentifierResults{
+				Matches:         map[string][]identifier.Match{"GPL-2.0-only": {{}}, "Classpath-exception-2.0": {{}}},
+				Exceptions:      map[string]string{"GPL-2.0-only": "Classpath-exception-2.0"},
+				Classifications: gpl,
+			},
+			context: RiskContext{Linking: StaticLinking, Distribution: Distributed},
+			want:    map[string]bool{},
+		},
+		{
+			name: "choice of a mapped license",
+			result: identifier.IdentifierResults{
+				Matches:  map[string][]identifier.Match{"GPL-2.0-only": {{}}, "Acme-Public": {{}}},
+				Mappings: map[string]string{"GPL-2.0-only": "GPL-2.0-only OR LicenseRef-Acme-Public", "Acme-Public": "LicenseRef-Acme-Public"},
+				Classifications: map[string]licenses.Classification{
+					"GPL-2.0-only": {Category: licenses.StrongCopyleft},
+					"Acme-Public":  {Category: licenses.Permissive},
+				},
+			},
+			context: DefaultRiskContext,
+			want:    map[string]bool{"GPL-2.0-only": true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, DefaultRiskModel.Avoided(tt.result, tt.context)); d != "" {
+				t.Errorf("Avoided() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestLoadRiskModel(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	file := filepath.Join(dir, "risk.yaml")
+	model := `
+rules:
+  - category: strong-copyleft
+    distribution: internal
+    level: medium
+categories:
+  permissive: low
+  weak-copyleft: high
+default: medium
+`
+	if err := os.WriteFile(file, []byte(model), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadRiskModel(file)
+	if err != nil {
+		t.Fatalf("LoadRiskModel() error = %v", err)
+	}
+	internal := RiskContext{Linking: DynamicLinking, Distribution: InternalDistribution}
+	for _, tt := range []struct {
+		category string
+		context  RiskContext
+		want     string
+	}{
+		{category: licenses.StrongCopyleft, context: internal, want: RiskMedium},
+		{category: licenses.StrongCopyleft, context: DefaultRiskContext, want: RiskMedium},
+		{category: licenses.WeakCopyleft, context: internal, want: RiskHigh},
+		{category: licenses.Permissive, context: DefaultRiskContext, want: RiskLow},
+	} {
+		if got := m.Level(tt.category, nil, tt.context); got != tt.want {
+			t.Errorf("Level(%v, %+v) = %v, want %v", tt.category, tt.context, got, tt.want)
+		}
+	}
+
+	invalid := filepath.Join(dir, "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"categories": {"permissive": "none"}, "default": "high"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRiskModel(invalid); err == nil {
+		t.Errorf("LoadRiskModel() expected an error for an unknown level")
+	}
+}
+
+func TestRiskContext_Validate(t *testing.T) {
+	t.Parallel()
+	if err := DefaultRiskContext.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (RiskContext{Linking: "shared", Distribution: Distributed}).Validate(); err == nil {
+		t.Errorf("Validate() expected an error for an unknown linking")
+	}
+	if err := (RiskContext{Linking: StaticLinking, Distribution: "saas"}).Validate(); err == nil {
+		t.Errorf("Validate() expected an error for an unknown distribution")
+	}
+}
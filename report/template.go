@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/metadata"
+)
+
+// TemplateData is the results model of the scan which a report template renders
+type TemplateData struct {
+	// Root is the scanned directory (or the directory of the scanned file)
+	Root string
+	// Files are the scanned files (sorted by path)
+	Files []TemplateFile
+	// Licenses are the license IDs detected in any file (sorted)
+	Licenses []string
+	// Obligations are what the detected licenses require of the user (sorted by name)
+	Obligations []Obligation
+	// UnknownObligations are the detected license IDs without an obligation rule (sorted)
+	UnknownObligations []string
+	// Risk is the risk summary of the detected licenses (with the DefaultRiskModel in the DefaultRiskContext
+	// unless it is set with the configured model and context)
+	Risk RiskSummary
+	// Metadata is the metadata of the scan: the scanner and license list versions, the config hash, the
+	// resource checksums, the times, and the host (when it is set)
+	Metadata metadata.Metadata
+}
+
+// TemplateFile is a scanned file
+type TemplateFile struct {
+	// Path is relative to the root (with forward slashes)
+	Path string
+	// Licenses are the license IDs detected in the file (sorted)
+	Licenses []string
+	// Expressions are the detected licenses with the exceptions WITH the licenses they apply to (sorted)
+	Expressions []string
+	// Result is the full result (matches, copyrights, hash, metadata, etc.)
+	Result identifier.IdentifierResults
+}
+
+// templateFuncs are the functions available to report templates, in addition to the text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":    strings.Join,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"replace": strings.ReplaceAll,
+	// coverage returns the percentage of the file text which the matches of the license ID cover
+	"coverage": func(result identifier.IdentifierResults, id string) int {
+		return int(identifier.Coverage(result.OriginalText, result.Matches[id])*100 + 0.5)
+	},
+}
+
+// LoadTemplate reads a Go text/template report file
+func LoadTemplate(filePath string) (*template.Template, error) {
+	b, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	t, err := template.New(filepath.Base(filePath)).Funcs(templateFuncs).Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the report template %v: %w", filePath, err)
+	}
+	return t, nil
+}
+
+// NewTemplateData returns the results model of the results. The file paths are relative to the root.
+func NewTemplateData(results []identifier.IdentifierResults, root string) TemplateData {
+	data := TemplateData{Root: root, Files: []TemplateFile{}, Licenses: []string{}}
+	all := make(map[string]bool)
+	for _, result := range results {
+		f := TemplateFile{Path: relativePath(result.File, root), Licenses: []string{}, Expressions: result.Expressions(), Result: result}
+		for id := range result.Matches {
+			f.Licenses = append(f.Licenses, id)
+			all[id] = true
+		}
+		sort.Strings(f.Licenses)
+		data.Files = append(data.Files, f)
+	}
+	for id := range all {
+		data.Licenses = append(data.Licenses, id)
+	}
+	sort.Strings(data.Licenses)
+	data.Obligations, data.UnknownObligations = Obligations(results)
+	data.Risk = DefaultRiskModel.Assess(results, root, DefaultRiskContext)
+	sort.Slice(data.Files, func(i, j int) bool { return data.Files[i].Path < data.Files[j].Path })
+	return data
+}
+
+// relativePath returns the path of the file relative to the root (with forward slashes)
+func relativePath(file string, root string) string {
+	if rel, err := filepath.Rel(root, file); err == nil {
+		file = rel
+	}
+	return filepath.ToSlash(file)
+}
+
+// Render writes the report of the results with the template
+func Render(w io.Writer, t *template.Template, results []identifier.IdentifierResults, root string) error {
+	return t.Execute(w, NewTemplateData(results, root))
+}
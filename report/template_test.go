@@ -0,0 +1,60 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	results := []identifier.IdentifierResults{
+		{File: "/repo/src/main.go", OriginalText: strings.Repeat("x", 100), Matches: map[string][]identifier.Match{"Apache-2.0": {{Begins: 0, Ends: 24}}}},
+		{File: "/repo/README.md"},
+		{File: "/repo/LICENSE", OriginalText: strings.Repeat("x", 100), Matches: map[string][]identifier.Match{"MIT": {{Begins: 0, Ends: 99}}, "Apache-2.0": {{Begins: 0, Ends: 9}}}},
+	}
+	tmpl := `{{ range $f := .Files }}{{ $f.Path }}:{{ range $id := $f.Licenses }} {{ lower $id }}={{ coverage $f.Result $id }}%{{ end }}
+{{ end }}all: {{ join .Licenses " OR " }}
+`
+	want := `LICENSE: apache-2.0=10% mit=100%
+README.md:
+src/main.go: apache-2.0=25%
+all: Apache-2.0 OR MIT
+`
+	file := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(file, []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := LoadTemplate(file)
+	if err != nil {
+		t.Fatalf("LoadTemplate() error = %v", err)
+	}
+	var out bytes.Buffer
+	if err := Render(&out, parsed, results, "/repo"); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if d := cmp.Diff(want, out.String()); d != "" {
+		t.Errorf("Render() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestLoadTemplateError(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "report.tmpl")
+	if err := os.WriteFile(file, []byte("{{ range .Files }}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTemplate(file); err == nil {
+		t.Errorf("LoadTemplate() expected an error")
+	}
+}
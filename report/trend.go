@@ -0,0 +1,312 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/store"
+)
+
+// Trend is the license counts and the policy violations of the scans recorded in a results database (the runs),
+// oldest first
+type Trend struct {
+	Runs []store.Run
+	// Licenses are the licenses found in any run (from the highest risk level, then by ID)
+	Licenses []LicenseTrend
+	// Violations are the number of files with a high risk license in each run
+	Violations []int
+	// Context is the context of the risk levels
+	Context RiskContext
+}
+
+// LicenseTrend is the number of files with a license in each run
+type LicenseTrend struct {
+	ID    string
+	Level string
+	Files []int
+}
+
+// NewTrend returns the trend of the runs of the database (only the last runs when last > 0). The risk levels of
+// the licenses are assessed with the model in the context, from their categories and obligations in the library,
+// so that the violations of the older runs are of the current policy.
+func NewTrend(db *store.Store, last int, model *RiskModel, context RiskContext, licenseLibrary *licenses.LicenseLibrary) (Trend, error) {
+	runs, err := db.Runs()
+	if err != nil {
+		return Trend{}, err
+	}
+	if len(runs) == 0 {
+		return Trend{}, store.ErrNoRuns
+	}
+	if last > 0 && len(runs) > last {
+		runs = runs[len(runs)-last:]
+	}
+	t := Trend{Runs: runs, Licenses: []LicenseTrend{}, Violations: make([]int, len(runs)), Context: context}
+	byID := make(map[string]*LicenseTrend)
+	for i, run := range runs {
+		files, err := db.Files(run.ID)
+		if err != nil {
+			return Trend{}, err
+		}
+		for _, f := range files {
+			violation := false
+			for _, id := range f.Licenses {
+				l, ok := byID[id]
+				if !ok {
+					level := model.Level(licenseLibrary.Classify(id).Category, licenseLibrary.Obligations(id), context)
+					l = &LicenseTrend{ID: id, Level: level, Files: make([]int, len(runs))}
+					byID[id] = l
+				}
+				l.Files[i]++
+				violation = violation || l.Level == RiskHigh
+			}
+			if violation {
+				t.Violations[i]++
+			}
+		}
+	}
+	for _, l := range byID {
+		t.Licenses = append(t.Licenses, *l)
+	}
+	sort.Slice(t.Licenses, func(i, j int) bool {
+		li, lj := t.Licenses[i], t.Licenses[j]
+		if riskLevels[li.Level] != riskLevels[lj.Level] {
+			return riskLevels[li.Level] > riskLevels[lj.Level]
+		}
+		return li.ID < lj.ID
+	})
+	return t, nil
+}
+
+// sparkBlocks are the bars of the sparklines, from the lowest to the highest
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline charts the counts as a line of bars, from 0 to the largest count
+func sparkline(counts []int) string {
+	largest := 0
+	for _, n := range counts {
+		if n > largest {
+			largest = n
+		}
+	}
+	var b strings.Builder
+	for _, n := range counts {
+		i := 0
+		if largest > 0 {
+			i = n * (len(sparkBlocks) - 1) / largest
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
+
+// change returns the difference between the last and the first count, with its sign
+func change(counts []int) string {
+	d := counts[len(counts)-1] - counts[0]
+	if d > 0 {
+		return fmt.Sprintf("+%v", d)
+	}
+	return fmt.Sprint(d)
+}
+
+// licenseRow is a row of the table of the license counts
+type licenseRow struct {
+	ID        string
+	Level     string
+	Sparkline string
+	First     int
+	Last      int
+	Change    string
+}
+
+// licenseRows returns the rows of the table of the license counts
+func (t Trend) licenseRows() []licenseRow {
+	rows := make([]licenseRow, len(t.Licenses))
+	for i, l := range t.Licenses {
+		rows[i] = licenseRow{ID: l.ID, Level: l.Level, Sparkline: sparkline(l.Files), First: l.Files[0], Last: l.Files[len(l.Files)-1], Change: change(l.Files)}
+	}
+	return rows
+}
+
+// period describes the runs (their number and the times of the first and the last) and the risk context
+func (t Trend) period() string {
+	first, last := t.Runs[0].Time.Format(time.RFC3339), t.Runs[len(t.Runs)-1].Time.Format(time.RFC3339)
+	return fmt.Sprintf("%v runs from %v to %v, with the risk levels of %v linking in a %v product", len(t.Runs), first, last, t.Context.Linking, t.Context.Distribution)
+}
+
+// WriteMarkdown writes the trend as Markdown: the sparklines of the violations and of the license counts, and a
+// table of the runs
+func (t Trend) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# License trend\n\n%v.\n\n", t.period())
+	fmt.Fprintf(&b, "## Policy violations\n\nFiles with a high risk license: %v (%v to %v, %v)\n\n",
+		sparkline(t.Violations), t.Violations[0], t.Violations[len(t.Violations)-1], change(t.Violations))
+	b.WriteString("## License counts\n\n| License | Risk | Trend | First | Last | Change |\n|---|---|---|---:|---:|---:|\n")
+	for _, row := range t.licenseRows() {
+		fmt.Fprintf(&b, "| %v | %v | %v | %v | %v | %v |\n", row.ID, row.Level, row.Sparkline, row.First, row.Last, row.Change)
+	}
+	b.WriteString("\n## Runs\n\n| Run | Time | Commit | Files | Licenses | Violations |\n|---:|---|---|---:|---:|---:|\n")
+	for i, r := range t.Runs {
+		commit := ""
+		if r.Commit != "" {
+			commit = "`" + r.Commit + "`"
+		}
+		fmt.Fprintf(&b, "| %v | %v | %v | %v | %v | %v |\n", r.ID, r.Time.Format(time.RFC3339), commit, r.Files, r.Licenses, t.Violations[i])
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// The size of the SVG charts of the HTML trend, and the space for the axes
+const (
+	chartWidth  = 720
+	chartHeight = 240
+	chartMargin = 40
+)
+
+// chartColors are the colors of the lines of the charts (repeated when there are more lines)
+var chartColors = []string{"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd", "#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf"}
+
+// chart is an SVG line chart of counts over the runs
+type chart struct {
+	Width, Height int
+	// Left, Right, Top, and Bottom are the edges of the plot area
+	Left, Right, Top, Bottom int
+	Largest                  int
+	Lines                    []chartLine
+	Runs                     []chartLabel
+}
+
+// chartLine is a line of a chart, with its points as "x,y x,y ..."
+type chartLine struct {
+	Name   string
+	Color  string
+	Points string
+}
+
+// chartLabel is a label of the x axis
+type chartLabel struct {
+	X    int
+	Text string
+}
+
+// newChart returns the chart of the named series of counts over the runs
+func newChart(runs []store.Run, names []string, series [][]int) chart {
+	c := chart{Width: chartWidth, Height: chartHeight, Left: chartMargin, Right: chartWidth - chartMargin/2, Top: chartMargin / 2, Bottom: chartHeight - chartMargin}
+	for _, counts := range series {
+		for _, n := range counts {
+			if n > c.Largest {
+				c.Largest = n
+			}
+		}
+	}
+	x := func(i int) int {
+		if len(runs) == 1 {
+			return (c.Left + c.Right) / 2
+		}
+		return c.Left + i*(c.Right-c.Left)/(len(runs)-1)
+	}
+	y := func(n int) int {
+		if c.Largest == 0 {
+			return c.Bottom
+		}
+		return c.Bottom - n*(c.Bottom-c.Top)/c.Largest
+	}
+	for i, r := range runs {
+		c.Runs = append(c.Runs, chartLabel{X: x(i), Text: fmt.Sprint(r.ID)})
+	}
+	for i, counts := range series {
+		points := make([]string, len(counts))
+		for j, n := range counts {
+			points[j] = fmt.Sprintf("%v,%v", x(j), y(n))
+		}
+		c.Lines = append(c.Lines, chartLine{Name: names[i], Color: chartColors[i%len(chartColors)], Points: strings.Join(points, " ")})
+	}
+	return c
+}
+
+// trendPage is the model of the HTML trend
+type trendPage struct {
+	Period          string
+	ViolationsChart chart
+	LicensesChart   chart
+	Licenses        []licenseRow
+	Runs            []runRow
+}
+
+// runRow is a row of the table of the runs
+type runRow struct {
+	store.Run
+	Violations int
+}
+
+// WriteHTML writes the trend as an HTML page: the SVG line charts of the violations and of the license counts,
+// with the tables of the license counts and of the runs
+func (t Trend) WriteHTML(w io.Writer) error {
+	page := trendPage{Period: t.period(), Licenses: t.licenseRows()}
+	page.ViolationsChart = newChart(t.Runs, []string{"Violations"}, [][]int{t.Violations})
+	names := make([]string, len(t.Licenses))
+	series := make([][]int, len(t.Licenses))
+	for i, l := range t.Licenses {
+		names[i], series[i] = l.ID, l.Files
+	}
+	page.LicensesChart = newChart(t.Runs, names, series)
+	for i, r := range t.Runs {
+		page.Runs = append(page.Runs, runRow{Run: r, Violations: t.Violations[i]})
+	}
+	return trendTemplate.Execute(w, page)
+}
+
+var trendTemplate = template.Must(template.New("trend").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>License trend</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+td.n { text-align: right; }
+.high { color: #d62728; font-weight: bold; }
+.medium { color: #ff7f0e; }
+svg text { font-size: 11px; fill: #555; }
+.legend span { margin-right: 1em; white-space: nowrap; }
+</style>
+</head>
+<body>
+<h1>License trend</h1>
+<p>{{.Period}}.</p>
+{{define "chart"}}<svg width="{{.Width}}" height="{{.Height}}" viewBox="0 0 {{.Width}} {{.Height}}" role="img">
+<line x1="{{.Left}}" y1="{{.Bottom}}" x2="{{.Right}}" y2="{{.Bottom}}" stroke="#999"/>
+<line x1="{{.Left}}" y1="{{.Top}}" x2="{{.Left}}" y2="{{.Bottom}}" stroke="#999"/>
+<text x="{{.Left}}" y="{{.Top}}" dx="-6" dy="4" text-anchor="end">{{.Largest}}</text>
+<text x="{{.Left}}" y="{{.Bottom}}" dx="-6" dy="4" text-anchor="end">0</text>
+{{$bottom := .Bottom}}{{range .Runs}}<text x="{{.X}}" y="{{$bottom}}" dy="16" text-anchor="middle">{{.Text}}</text>
+{{end}}{{range .Lines}}<polyline points="{{.Points}}" fill="none" stroke="{{.Color}}" stroke-width="2"><title>{{.Name}}</title></polyline>
+{{end}}</svg>
+<p class="legend">{{range .Lines}}<span><svg width="12" height="12"><rect width="12" height="12" fill="{{.Color}}"/></svg> {{.Name}}</span>{{end}}</p>
+{{end}}
+<h2>Policy violations</h2>
+<p>Files with a high risk license, by run:</p>
+{{template "chart" .ViolationsChart}}
+<h2>License counts</h2>
+<p>Files with each license, by run:</p>
+{{template "chart" .LicensesChart}}
+<table>
+<tr><th>License</th><th>Risk</th><th>Trend</th><th>First</th><th>Last</th><th>Change</th></tr>
+{{range .Licenses}}<tr><td>{{.ID}}</td><td class="{{.Level}}">{{.Level}}</td><td>{{.Sparkline}}</td><td class="n">{{.First}}</td><td class="n">{{.Last}}</td><td class="n">{{.Change}}</td></tr>
+{{end}}</table>
+<h2>Runs</h2>
+<table>
+<tr><th>Run</th><th>Time</th><th>Commit</th><th>Files</th><th>Licenses</th><th>Violations</th></tr>
+{{range .Runs}}<tr><td class="n">{{.ID}}</td><td>{{.Time.Format "2006-01-02T15:04:05Z07:00"}}</td><td><code>{{.Commit}}</code></td><td class="n">{{.Files}}</td><td class="n">{{.Licenses}}</td><td class="n">{{.Violations}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
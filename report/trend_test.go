@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package report
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/store"
+)
+
+// newTrendStore returns a results database with a run of each of the scans (the license IDs of each file)
+func newTrendStore(t *testing.T, scans ...map[string][]string) *store.Store {
+	t.Helper()
+	if !store.Available() {
+		t.Skip(store.ErrNoSQLite)
+	}
+	db, err := store.Open(filepath.Join(t.TempDir(), "results.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for i, scan := range scans {
+		var results []identifier.IdentifierResults
+		for file, ids := range scan {
+			result := identifier.IdentifierResults{File: file, Matches: map[string][]identifier.Match{}}
+			for _, id := range ids {
+				result.Matches[id] = []identifier.Match{{Begins: 0, Ends: 10}}
+			}
+			results = append(results, result)
+		}
+		end := time.Date(2023, 5, i+1, 12, 0, 0, 0, time.UTC)
+		if _, err := db.AddRun(".", "", ".", results, metadata.Metadata{End: &end}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+func TestNewTrend(t *testing.T) {
+	t.Parallel()
+	db := newTrendStore(t,
+		map[string][]string{"LICENSE": {"MIT"}, "a.go": nil},
+		map[string][]string{"LICENSE": {"MIT"}, "a.go": {"MIT"}, "lib/gpl.c": {"GPL-2.0-only", "MIT"}},
+		map[string][]string{"LICENSE": {"MIT"}, "lib/gpl.c": {"GPL-2.0-only"}, "lib/agpl.c": {"AGPL-3.0-only"}},
+	)
+	library := &licenses.LicenseLibrary{}
+
+	got, err := NewTrend(db, 0, &DefaultRiskModel, DefaultRiskContext, library)
+	if err != nil {
+		t.Fatalf("NewTrend() error = %v", err)
+	}
+	want := []LicenseTrend{
+		{ID: "AGPL-3.0-only", Level: RiskHigh, Files: []int{0, 0, 1}},
+		{ID: "GPL-2.0-only", Level: RiskHigh, Files: []int{0, 1, 1}},
+		{ID: "MIT", Level: RiskLow, Files: []int{1, 3, 1}},
+	}
+	if d := cmp.Diff(want, got.Licenses); d != "" {
+		t.Errorf("NewTrend() licenses mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]int{0, 1, 2}, got.Violations); d != "" {
+		t.Errorf("NewTrend() violations mismatch (-want +got):\n%s", d)
+	}
+
+	// Copyleft is not triggered by internal use
+	internal := RiskContext{Linking: DynamicLinking, Distribution: InternalDistribution}
+	if got, err = NewTrend(db, 2, &DefaultRiskModel, internal, library); err != nil {
+		t.Fatalf("NewTrend() error = %v", err)
+	}
+	if len(got.Runs) != 2 || got.Runs[0].ID != 2 {
+		t.Errorf("NewTrend() runs = %+v, want the last 2 runs", got.Runs)
+	}
+	if d := cmp.Diff([]int{0, 0}, got.Violations); d != "" {
+		t.Errorf("NewTrend() internal violations mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := NewTrend(newTrendStore(t), 0, &DefaultRiskModel, DefaultRiskContext, library); !errors.Is(err, store.ErrNoRuns) {
+		t.Errorf("NewTrend() without runs error = %v, want %v", err, store.ErrNoRuns)
+	}
+}
+
+func TestTrend_Write(t *testing.T) {
+	t.Parallel()
+	db := newTrendStore(t,
+		map[string][]string{"LICENSE": {"MIT"}},
+		map[string][]string{"LICENSE": {"MIT"}, "lib/gpl.c": {"GPL-2.0-only"}},
+	)
+	trend, err := NewTrend(db, 0, &DefaultRiskModel, DefaultRiskContext, &licenses.LicenseLibrary{})
+	if err != nil {
+		t.Fatalf("NewTrend() error = %v", err)
+	}
+
+	var md bytes.Buffer
+	if err := trend.WriteMarkdown(&md); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	for _, want := range []string{
+		"2 runs from 2023-05-01T12:00:00Z to 2023-05-02T12:00:00Z",
+		"Files with a high risk license: ▁█ (0 to 1, +1)",
+		"| GPL-2.0-only | high | ▁█ | 0 | 1 | +1 |",
+		"| MIT | low | ██ | 1 | 1 | 0 |",
+		"| 2 | 2023-05-02T12:00:00Z |  | 2 | 2 | 1 |",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Errorf("WriteMarkdown() = %v, want %q", md.String(), want)
+		}
+	}
+
+	var html bytes.Buffer
+	if err := trend.WriteHTML(&html); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	for _, want := range []string{
+		`<polyline points="40,200 700,20" fill="none" stroke="#1f77b4"`,
+		`<title>GPL-2.0-only</title>`,
+		`<td class="high">high</td>`,
+	} {
+		if !strings.Contains(html.String(), want) {
+			t.Errorf("WriteHTML() = %v, want %q", html.String(), want)
+		}
+	}
+}
+
+func Test_sparkline(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		counts []int
+		want   string
+	}{
+		{counts: []int{0, 0, 0}, want: "▁▁▁"},
+		{counts: []int{0, 7, 14}, want: "▁▄█"},
+		{counts: []int{3, 3}, want: "██"},
+	}
+	for _, tt := range tests {
+		if got := sparkline(tt.counts); got != tt.want {
+			t.Errorf("sparkline(%v) = %v, want %v", tt.counts, got, tt.want)
+		}
+	}
+}
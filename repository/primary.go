@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package repository determines the primary license of a repository from the scan results of its directory
+package repository
+
+import (
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// NoAssertion is the expression when no primary license was determined
+const NoAssertion = "NOASSERTION"
+
+// The sources of a primary license, in order of precedence
+const (
+	SourceLicenseFile = "license file"
+	SourceReadme      = "readme"
+)
+
+// secondaryCoverage is the least coverage of a license in a file, relative to the license with the most
+// coverage, for both licenses to be in the primary license (e.g., a file with two full license texts).
+// Licenses with less coverage are notices or references (e.g., of third party code).
+const secondaryCoverage = 0.5
+
+var (
+	// licenseFileRE matches the root license file names (e.g., LICENSE, LICENSE.md, LICENSE-MIT, COPYING.LESSER, UNLICENSE)
+	licenseFileRE = regexp.MustCompile(`^(?i:licen[cs]e|copying|unlicense)([-_.].*)?$`)
+	// readmeRE matches the root README file names
+	readmeRE = regexp.MustCompile(`^(?i:readme)(\..*)?$`)
+)
+
+// Primary is the primary license of a repository
+type Primary struct {
+	// Expression is the SPDX expression of the primary license (NoAssertion when it was not determined)
+	Expression string
+	// Source is where the license was found: SourceLicenseFile, SourceReadme, or none
+	Source string
+	// Files are the root files the license was determined from
+	Files []string
+}
+
+// PrimaryLicense determines the primary license of the repository in the root directory, like GitHub's
+// license detection. Only the files in the root directory are used (so licenses of vendored or third party
+// code are not included):
+//
+//   - The license files (LICENSE, LICENCE, COPYING, or UNLICENSE, with any extension or suffix) take
+//     precedence. In each file, the license with the most coverage of the file is used, and the licenses
+//     with at least half as much coverage (e.g., a file with two full license texts) are combined with AND.
+//     The licenses of separate license files (e.g., LICENSE-MIT and LICENSE-APACHE) are dual licenses,
+//     combined with OR. The GPL text in the COPYING file of a LGPL project is not a separate license.
+//   - Without license files, the licenses stated in the README are used (combined with AND).
+func PrimaryLicense(results []identifier.IdentifierResults, root string) Primary {
+	var licenseFiles, readmes []identifier.IdentifierResults
+	for _, result := range results {
+		if len(result.Matches) == 0 {
+			continue
+		}
+		rel, err := filepath.Rel(root, result.File)
+		if err != nil || strings.ContainsRune(filepath.ToSlash(rel), '/') {
+			continue
+		}
+		switch name := filepath.Base(result.File); {
+		case licenseFileRE.MatchString(name):
+			licenseFiles = append(licenseFiles, result)
+		case readmeRE.MatchString(name):
+			readmes = append(readmes, result)
+		}
+	}
+
+	if len(licenseFiles) > 0 {
+		sort.Slice(licenseFiles, func(i, j int) bool { return licenseFiles[i].File < licenseFiles[j].File })
+		p := Primary{Source: SourceLicenseFile}
+		var alternatives []string
+		for _, result := range licenseFiles {
+			p.Files = append(p.Files, filepath.Base(result.File))
+			if ids := mainLicenses(result); !slices.Contains(alternatives, and(ids)) {
+				alternatives = append(alternatives, and(ids))
+			}
+		}
+		p.Expression = or(lesserGPL(alternatives))
+		return p
+	}
+
+	if len(readmes) > 0 {
+		p := Primary{Source: SourceReadme}
+		var ids []string
+		for _, result := range readmes {
+			p.Files = append(p.Files, filepath.Base(result.File))
+			for id := range result.Matches {
+				if !slices.Contains(ids, id) {
+					ids = append(ids, id)
+				}
+			}
+		}
+		sort.Strings(p.Files)
+		sort.Strings(ids)
+		p.Expression = and(ids)
+		return p
+	}
+	return Primary{Expression: NoAssertion}
+}
+
+// mainLicenses returns the license IDs with the most coverage of the file (sorted)
+func mainLicenses(result identifier.IdentifierResults) []string {
+	coverage := make(map[string]float64, len(result.Matches))
+	best := 0.0
+	for id, matches := range result.Matches {
+		coverage[id] = identifier.Coverage(result.OriginalText, matches)
+		if coverage[id] > best {
+			best = coverage[id]
+		}
+	}
+	var ret []string
+	for id, c := range coverage {
+		if c >= best*secondaryCoverage {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// lesserGPL removes the GPL alternatives of a LGPL project (the LGPL is a set of additional permissions
+// on top of the GPL, so the GPL text is in its COPYING file)
+func lesserGPL(alternatives []string) []string {
+	lgpl := false
+	for _, a := range alternatives {
+		lgpl = lgpl || strings.HasPrefix(a, "LGPL-")
+	}
+	if !lgpl {
+		return alternatives
+	}
+	var ret []string
+	for _, a := range alternatives {
+		if !strings.HasPrefix(a, "GPL-") {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+// and combines the license IDs with AND
+func and(ids []string) string {
+	return strings.Join(ids, " AND ")
+}
+
+// or combines the expressions with OR (with parentheses around AND expressions)
+func or(expressions []string) string {
+	if len(expressions) == 1 {
+		return expressions[0]
+	}
+	sort.Strings(expressions)
+	parts := make([]string, len(expressions))
+	for i, e := range expressions {
+		if strings.Contains(e, " AND ") {
+			e = "(" + e + ")"
+		}
+		parts[i] = e
+	}
+	return strings.Join(parts, " OR ")
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/identifier"
+)
+
+// result returns a result for a file of 100 characters, with each license matching the given number of characters
+func result(file string, coverage map[string]int) identifier.IdentifierResults {
+	r := identifier.IdentifierResults{File: file, OriginalText: strings.Repeat("x", 100), Matches: make(map[string][]identifier.Match)}
+	for id, n := range coverage {
+		r.Matches[id] = []identifier.Match{{Begins: 0, Ends: n - 1}}
+	}
+	return r
+}
+
+func TestPrimaryLicense(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		results []identifier.IdentifierResults
+		want    Primary
+	}{
+		{
+			name: "root license file",
+			results: []identifier.IdentifierResults{
+				result("/repo/LICENSE", map[string]int{"MIT": 100}),
+				result("/repo/vendor/lib/LICENSE", map[string]int{"GPL-3.0-only": 100}),
+				result("/repo/README.md", map[string]int{"Apache-2.0": 10}),
+			},
+			want: Primary{Expression: "MIT", Source: SourceLicenseFile, Files: []string{"LICENSE"}},
+		},
+		{
+			name: "third party notice in the license file",
+			results: []identifier.IdentifierResults{
+				result("/repo/LICENSE.txt", map[string]int{"Apache-2.0": 90, "MIT": 10}),
+			},
+			want: Primary{Expression: "Apache-2.0", Source: SourceLicenseFile, Files: []string{"LICENSE.txt"}},
+		},
+		{
+			name: "two licenses in one file",
+			results: []identifier.IdentifierResults{
+				result("/repo/LICENSE", map[string]int{"BSD-3-Clause": 50, "MIT": 40}),
+			},
+			want: Primary{Expression: "BSD-3-Clause AND MIT", Source: SourceLicenseFile, Files: []string{"LICENSE"}},
+		},
+		{
+			name: "dual license files",
+			results: []identifier.IdentifierResults{
+				result("/repo/LICENSE-MIT", map[string]int{"MIT": 100}),
+				result("/repo/LICENSE-APACHE", map[string]int{"Apache-2.0": 100}),
+				result("/repo/COPYING", map[string]int{"MIT": 100}),
+			},
+			want: Primary{Expression: "Apache-2.0 OR MIT", Source: SourceLicenseFile, Files: []string{"COPYING", "LICENSE-APACHE", "LICENSE-MIT"}},
+		},
+		{
+			name: "lesser GPL",
+			results: []identifier.IdentifierResults{
+				result("/repo/COPYING", map[string]int{"GPL-3.0-only": 100}),
+				result("/repo/COPYING.LESSER", map[string]int{"LGPL-3.0-only": 100}),
+			},
+			want: Primary{Expression: "LGPL-3.0-only", Source: SourceLicenseFile, Files: []string{"COPYING", "COPYING.LESSER"}},
+		},
+		{
+			name: "readme statement",
+			results: []identifier.IdentifierResults{
+				result("/repo/README.md", map[string]int{"Apache-2.0": 5}),
+				result("/repo/src/main.go", map[string]int{"MIT": 5}),
+			},
+			want: Primary{Expression: "Apache-2.0", Source: SourceReadme, Files: []string{"README.md"}},
+		},
+		{
+			name: "no license",
+			results: []identifier.IdentifierResults{
+				result("/repo/LICENSE", nil),
+				result("/repo/docs/LICENSE", map[string]int{"CC-BY-4.0": 100}),
+			},
+			want: Primary{Expression: NoAssertion},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, PrimaryLicense(tt.results, "/repo")); d != "" {
+				t.Errorf("PrimaryLicense() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reuse
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+// HeaderWidth is the width at which the standard license header text is wrapped (with the comment markers)
+const HeaderWidth = 80
+
+// headerScanSize is how much of the beginning of a file is scanned for an existing license header
+const headerScanSize = 8 * 1024
+
+// commentStyle is how a header is commented in a language: each line starts with the Prefix, and the Start
+// and End (if any) are the lines which open and close a block comment
+type commentStyle struct {
+	Start  string
+	Prefix string
+	End    string
+}
+
+var (
+	blockStyle = commentStyle{Start: "/*", Prefix: " * ", End: " */"}
+	slashStyle = commentStyle{Prefix: "// "}
+	hashStyle  = commentStyle{Prefix: "# "}
+	dashStyle  = commentStyle{Prefix: "-- "}
+	semiStyle  = commentStyle{Prefix: ";; "}
+	pctStyle   = commentStyle{Prefix: "% "}
+	htmlStyle  = commentStyle{Start: "<!--", Prefix: "  ", End: "-->"}
+
+	// commentStyles are the comment styles of the languages by file extension
+	commentStyles = map[string]commentStyle{
+		".c": blockStyle, ".h": blockStyle, ".css": blockStyle, ".scss": blockStyle, ".less": blockStyle, ".php": blockStyle,
+		".go": slashStyle, ".rs": slashStyle, ".java": slashStyle, ".js": slashStyle, ".jsx": slashStyle, ".mjs": slashStyle,
+		".cjs": slashStyle, ".ts": slashStyle, ".tsx": slashStyle, ".kt": slashStyle, ".kts": slashStyle, ".swift": slashStyle,
+		".scala": slashStyle, ".cs": slashStyle, ".cc": slashStyle, ".cpp": slashStyle, ".cxx": slashStyle, ".hpp": slashStyle,
+		".dart": slashStyle, ".groovy": slashStyle, ".gradle": slashStyle, ".proto": slashStyle,
+		".py": hashStyle, ".sh": hashStyle, ".bash": hashStyle, ".zsh": hashStyle, ".rb": hashStyle, ".pl": hashStyle,
+		".pm": hashStyle, ".r": hashStyle, ".yaml": hashStyle, ".yml": hashStyle, ".toml": hashStyle, ".tf": hashStyle,
+		".ps1": hashStyle, ".cmake": hashStyle, ".mk": hashStyle, ".properties": hashStyle, ".nix": hashStyle,
+		".sql": dashStyle, ".lua": dashStyle, ".hs": dashStyle, ".ada": dashStyle,
+		".el": semiStyle, ".lisp": semiStyle, ".clj": semiStyle, ".scm": semiStyle,
+		".tex": pctStyle, ".sty": pctStyle, ".erl": pctStyle,
+		".html": htmlStyle, ".htm": htmlStyle, ".xml": htmlStyle, ".svg": htmlStyle, ".vue": htmlStyle, ".md": htmlStyle,
+	}
+	// commentStylesByName are the comment styles of the files without a telling extension
+	commentStylesByName = map[string]commentStyle{
+		"Makefile": hashStyle, "Dockerfile": hashStyle, "Containerfile": hashStyle, "CMakeLists.txt": hashStyle,
+		"Gemfile": hashStyle, "Rakefile": hashStyle, "Jenkinsfile": slashStyle, ".gitignore": hashStyle,
+	}
+
+	// firstLineRE matches the lines which must stay at the top of a file, before the header: a shebang, an
+	// XML declaration, a PHP open tag, an HTML doctype, and a Python or Ruby encoding declaration
+	firstLineRE = regexp.MustCompile(`^(#!|<\?xml|<\?php|(?i:<!doctype)|#.*coding[:=])`)
+)
+
+// Header is a license header to insert into the source files: the SPDX-FileCopyrightText and
+// SPDX-License-Identifier tags, and the standard license header text, if any
+type Header struct {
+	// Expression is the SPDX license expression of the SPDX-License-Identifier (e.g., MIT)
+	Expression string
+	// Year and Holder are the year and holder of the copyright
+	Year   string
+	Holder string
+	// Text is the standard license header text of the license ("" for only the tags), e.g., from
+	// licenses.LicenseLibrary.HeaderText
+	Text string
+}
+
+// Lines returns the lines of the header (without the comment markers), with its text wrapped at the width
+func (h Header) Lines(width int) []string {
+	lines := []string{
+		strings.TrimSpace("SPDX-FileCopyrightText: " + strings.TrimSpace(h.Year+" "+h.Holder)),
+		"SPDX-License-Identifier: " + h.Expression,
+	}
+	for _, paragraph := range strings.Split(strings.TrimSpace(h.Text), "\n\n") {
+		if strings.TrimSpace(paragraph) == "" {
+			continue
+		}
+		lines = append(lines, "")
+		lines = append(lines, wrap(paragraph, width)...)
+	}
+	return lines
+}
+
+// AnnotateResult is what was done to a file by Annotate
+type AnnotateResult struct {
+	File string
+	// Skipped is why the header was not inserted ("" when it was inserted), e.g., the license IDs of the
+	// header which the file already carries
+	Skipped string
+}
+
+// Annotate inserts the header at the top of the files, and of the files under the directories (except in
+// the version control directories), in the comment style of each file's language. The files which already
+// carry a license header (an SPDX-License-Identifier, or a license or standard license header match at the
+// top of the file) and the files of an unknown language are skipped. With dryRun, no file is written. The
+// license IDs of the expression must be SPDX IDs or LicenseRef- IDs.
+func Annotate(paths []string, h Header, licenseLibrary *licenses.LicenseLibrary, dryRun bool) ([]AnnotateResult, error) {
+	ids := manifest.ExpressionIDs(h.Expression)
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("no license in %q", h.Expression)
+	}
+	for _, id := range ids {
+		if !valid(id, licenseLibrary) {
+			return nil, fmt.Errorf("%v is neither an SPDX license ID nor a LicenseRef- ID", id)
+		}
+	}
+
+	var files []string
+	for _, p := range paths {
+		err := filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != p && vcsDirs[d.Name()] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.Type().IsRegular() {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(files)
+
+	var ret []AnnotateResult
+	for _, f := range files {
+		r, err := annotateFile(f, h, licenseLibrary, dryRun)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
+
+// annotateFile inserts the header into the file, unless it is skipped
+func annotateFile(f string, h Header, licenseLibrary *licenses.LicenseLibrary, dryRun bool) (AnnotateResult, error) {
+	r := AnnotateResult{File: f}
+	style, ok := commentStyleFor(f)
+	if !ok {
+		r.Skipped = "unknown comment style"
+		return r, nil
+	}
+	fi, err := os.Stat(f)
+	if err != nil {
+		return r, err
+	}
+	b, err := os.ReadFile(f)
+	if err != nil {
+		return r, err
+	}
+	content := string(b)
+	ids, err := existingHeader(content, licenseLibrary)
+	if err != nil {
+		return r, err
+	}
+	if len(ids) > 0 {
+		r.Skipped = "has a license header: " + strings.Join(ids, ", ")
+		return r, nil
+	}
+	if dryRun {
+		return r, nil
+	}
+	return r, os.WriteFile(f, []byte(insertHeader(content, comment(h.Lines(HeaderWidth-len(style.Prefix)), style))), fi.Mode().Perm())
+}
+
+// commentStyleFor returns the comment style of the file, from its name or extension
+func commentStyleFor(f string) (commentStyle, bool) {
+	name := filepath.Base(f)
+	if style, ok := commentStylesByName[name]; ok {
+		return style, true
+	}
+	style, ok := commentStyles[strings.ToLower(filepath.Ext(name))]
+	return style, ok
+}
+
+// existingHeader returns the license IDs of the license header at the top of the text (none when there is no
+// header): the SPDX-License-Identifier tags, or the license and standard license header matches
+func existingHeader(text string, licenseLibrary *licenses.LicenseLibrary) ([]string, error) {
+	if len(text) > headerScanSize {
+		text = text[:headerScanSize]
+	}
+	if ids, _ := Tags(text); len(ids) > 0 {
+		return ids, nil
+	}
+	results, err := identifier.IdentifyLicensesInString(text, identifier.Options{OmitBlocks: true, Headers: true}, licenseLibrary)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, byID := range []map[string][]identifier.Match{results.Matches, results.HeaderMatches} {
+		for id := range byID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// comment returns the lines commented in the style (with a newline after each line)
+func comment(lines []string, style commentStyle) string {
+	var b strings.Builder
+	if style.Start != "" {
+		b.WriteString(style.Start + "\n")
+	}
+	for _, line := range lines {
+		b.WriteString(strings.TrimRight(style.Prefix+line, " ") + "\n")
+	}
+	if style.End != "" {
+		b.WriteString(style.End + "\n")
+	}
+	return b.String()
+}
+
+// insertHeader returns the content with the commented header (and a blank line) inserted after the lines
+// which must stay at the top (e.g., a shebang), with the line endings of the content
+func insertHeader(content string, header string) string {
+	newline := "\n"
+	if strings.Contains(content, "\r\n") {
+		newline = "\r\n"
+		header = strings.ReplaceAll(header, "\n", newline)
+	}
+	at := 0
+	for at < len(content) && firstLineRE.MatchString(content[at:]) {
+		end := strings.IndexByte(content[at:], '\n')
+		if end == -1 {
+			return content + newline + header
+		}
+		at += end + 1
+	}
+	rest := content[at:]
+	if strings.TrimSpace(rest) == "" {
+		return content[:at] + header
+	}
+	return content[:at] + header + newline + rest
+}
+
+// wrap returns the lines of the paragraph wrapped at the width (the lines of the paragraph are joined)
+func wrap(paragraph string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(paragraph) {
+		if line != "" && len(line)+1+len(word) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
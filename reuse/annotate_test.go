@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package reuse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func Test_insertHeader(t *testing.T) {
+	t.Parallel()
+	header := "// SPDX-License-Identifier: MIT\n"
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "top", content: "package main\n", want: header + "\npackage main\n"},
+		{name: "shebang", content: "#!/bin/sh\necho\n", want: "#!/bin/sh\n" + header + "\necho\n"},
+		{name: "shebang and encoding", content: "#!/usr/bin/python\n# -*- coding: utf-8 -*-\nx = 1\n", want: "#!/usr/bin/python\n# -*- coding: utf-8 -*-\n" + header + "\nx = 1\n"},
+		{name: "only a shebang", content: "#!/bin/sh", want: "#!/bin/sh\n" + header},
+		{name: "empty", content: "", want: header},
+		{name: "CRLF", content: "int x;\r\n", want: "// SPDX-License-Identifier: MIT\r\n\r\nint x;\r\n"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, insertHeader(tt.content, header)); d != "" {
+				t.Errorf("insertHeader() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	text, ok := ll.HeaderText("MPL-2.0")
+	if !ok {
+		t.Fatal("HeaderText(MPL-2.0) has no header")
+	}
+
+	root := t.TempDir()
+	files := map[string]string{
+		"main.go":           "package main\n",
+		"lib/util.c":        "int x;\n",
+		"run.sh":            "#!/bin/sh\necho\n",
+		"tagged.py":         "# SPDX-License-Identifier: MIT\n",
+		"notice.py":         "# This Source Code Form is subject to the terms of the Mozilla Public\n# License, v. 2.0. If a copy of the MPL was not distributed with this\n# file, You can obtain one at http://mozilla.org/MPL/2.0/.\n",
+		"README":            "no comment style\n",
+		".git/hooks/pre.sh": "#!/bin/sh\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := Header{Expression: "MPL-2.0", Year: "2024", Holder: "Acme", Text: text}
+	got, err := Annotate([]string{root}, h, ll, false)
+	if err != nil {
+		t.Fatalf("Annotate() error = %v", err)
+	}
+	want := []AnnotateResult{
+		{File: filepath.Join(root, "README"), Skipped: "unknown comment style"},
+		{File: filepath.Join(root, "lib", "util.c")},
+		{File: filepath.Join(root, "main.go")},
+		{File: filepath.Join(root, "notice.py"), Skipped: "has a license header: MPL-2.0"},
+		{File: filepath.Join(root, "run.sh")},
+		{File: filepath.Join(root, "tagged.py"), Skipped: "has a license header: MIT"},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Annotate() mismatch (-want +got):\n%s", d)
+	}
+
+	wantFiles := map[string]string{
+		"main.go": `// SPDX-FileCopyrightText: 2024 Acme
+// SPDX-License-Identifier: MPL-2.0
+//
+// This Source Code Form is subject to the terms of the Mozilla Public License,
+// v. 2.0. If a copy of the MPL was not distributed with this file, You can
+// obtain one at http://mozilla.org/MPL/2.0/.
+
+package main
+`,
+		"lib/util.c": `/*
+ * SPDX-FileCopyrightText: 2024 Acme
+ * SPDX-License-Identifier: MPL-2.0
+ *
+ * This Source Code Form is subject to the terms of the Mozilla Public License,
+ * v. 2.0. If a copy of the MPL was not distributed with this file, You can
+ * obtain one at http://mozilla.org/MPL/2.0/.
+ */
+
+int x;
+`,
+		"tagged.py": files["tagged.py"],
+	}
+	for name, want := range wantFiles {
+		b, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := cmp.Diff(want, string(b)); d != "" {
+			t.Errorf("Annotate() %v mismatch (-want +got):\n%s", name, d)
+		}
+	}
+
+	// the annotated files now carry a header
+	again, err := Annotate([]string{filepath.Join(root, "main.go")}, h, ll, false)
+	if err != nil {
+		t.Fatalf("Annotate() again error = %v", err)
+	}
+	if len(again) != 1 || again[0].Skipped == "" {
+		t.Errorf("Annotate() again = %v, want skipped", again)
+	}
+
+	if _, err := Annotate([]string{root}, Header{Expression: "MIT OR Acme-1.0"}, ll, true); err == nil {
+		t.Error("Annotate() with an invalid license ID error = nil")
+	}
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reuse
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+)
+
+// SpecVersion is the version of the REUSE Specification which is checked
+const SpecVersion = "3.0"
+
+const (
+	licensesDir   = "LICENSES"
+	reuseDir      = ".reuse"
+	dep5File      = "dep5"
+	licenseSuffix = ".license"
+)
+
+var (
+	licenseTagRE   = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*(.*)`)
+	copyrightTagRE = regexp.MustCompile(`(?i)(SPDX-FileCopyrightText:|SPDX-SnippetCopyrightText:|\bCopyright\b|©)[ \t]*\S`)
+	ignoreRE       = regexp.MustCompile(`(?s)REUSE-IgnoreStart.*?(REUSE-IgnoreEnd|$)`)
+	// commentEnds are the comment closing markers which may follow a license expression on the same line
+	commentEnds = []string{"*/", "-->", "--%>", "#}", "%>", "*)", "\"\"\"", "'''"}
+	// vcsDirs are the version control directories which are not part of the project
+	vcsDirs = map[string]bool{".git": true, ".hg": true, ".svn": true, ".sl": true}
+	// licenseFileRE matches the LICENSE and COPYING files which the REUSE Specification ignores
+	licenseFileRE = regexp.MustCompile(`^(?i:LICEN[CS]E|COPYING)([-.].*)?$`)
+	// licenseTextExts are the extensions of the license texts in LICENSES/ (e.g., LICENSES/MIT.txt)
+	licenseTextExts = map[string]bool{".txt": true, ".md": true, ".rst": true, ".html": true}
+)
+
+// Report is the REUSE compliance of a project.
+// The file paths are relative to the project root and use forward slashes.
+type Report struct {
+	// Files are the files which need copyright and licensing information
+	Files []string
+	// MissingLicenseInfo are the files without an SPDX-License-Identifier (in the file, a .license file, or .reuse/dep5)
+	MissingLicenseInfo []string
+	// MissingCopyrightInfo are the files without a copyright notice (in the file, a .license file, or .reuse/dep5)
+	MissingCopyrightInfo []string
+	// UsedLicenses has the files which use each license ID
+	UsedLicenses map[string][]string
+	// MissingLicenses are the used license IDs without a text in LICENSES/
+	MissingLicenses []string
+	// UnusedLicenses are the license IDs with a text in LICENSES/ which are not used
+	UnusedLicenses []string
+	// BadLicenses are the license IDs (used or in LICENSES/) which are neither SPDX IDs nor LicenseRef-
+	BadLicenses []string
+}
+
+// Compliant is true when the project conforms to the REUSE Specification
+func (r *Report) Compliant() bool {
+	return len(r.MissingLicenseInfo) == 0 && len(r.MissingCopyrightInfo) == 0 &&
+		len(r.MissingLicenses) == 0 && len(r.UnusedLicenses) == 0 && len(r.BadLicenses) == 0
+}
+
+// Lint checks the files under the root directory for REUSE compliance: every file has SPDX tags (in
+// the file, in a <file>.license file, or in .reuse/dep5), and the text of every license which is used
+// (and only those) is in LICENSES/.
+func Lint(root string, licenseLibrary *licenses.LicenseLibrary) (*Report, error) {
+	dep5, err := manifest.ParseDEP5File(filepath.Join(root, reuseDir, dep5File))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	r := &Report{UsedLicenses: make(map[string][]string)}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (vcsDirs[d.Name()] || rel == licensesDir || rel == reuseDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || ignored(d.Name()) {
+			return nil
+		}
+		r.Files = append(r.Files, rel)
+
+		tagged := path
+		if _, err := os.Stat(path + licenseSuffix); err == nil {
+			tagged = path + licenseSuffix
+		}
+		b, err := os.ReadFile(tagged)
+		if err != nil {
+			return err
+		}
+		ids, copyrighted := Tags(string(b))
+		if dep5 != nil {
+			if p, ok := dep5.FilesFor(rel); ok {
+				ids = append(ids, manifest.ExpressionIDs(p.License)...)
+				copyrighted = copyrighted || strings.TrimSpace(p.Copyright) != ""
+			}
+		}
+		if len(ids) == 0 {
+			r.MissingLicenseInfo = append(r.MissingLicenseInfo, rel)
+		}
+		if !copyrighted {
+			r.MissingCopyrightInfo = append(r.MissingCopyrightInfo, rel)
+		}
+		for _, id := range ids {
+			if files := r.UsedLicenses[id]; len(files) == 0 || files[len(files)-1] != rel {
+				r.UsedLicenses[id] = append(files, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	texts, err := licenseTexts(filepath.Join(root, licensesDir))
+	if err != nil {
+		return nil, err
+	}
+	bad := make(map[string]bool)
+	for id := range r.UsedLicenses {
+		if !texts[id] {
+			r.MissingLicenses = append(r.MissingLicenses, id)
+		}
+		if !valid(id, licenseLibrary) {
+			bad[id] = true
+		}
+	}
+	for id := range texts {
+		if _, ok := r.UsedLicenses[id]; !ok {
+			r.UnusedLicenses = append(r.UnusedLicenses, id)
+		}
+		if !valid(id, licenseLibrary) {
+			bad[id] = true
+		}
+	}
+	for id := range bad {
+		r.BadLicenses = append(r.BadLicenses, id)
+	}
+	sort.Strings(r.MissingLicenses)
+	sort.Strings(r.UnusedLicenses)
+	sort.Strings(r.BadLicenses)
+	return r, nil
+}
+
+// Tags returns the license IDs of the SPDX-License-Identifier tags in the text, and whether it has a
+// copyright notice. Text between REUSE-IgnoreStart and REUSE-IgnoreEnd is ignored.
+func Tags(text string) (ids []string, copyrighted bool) {
+	text = ignoreRE.ReplaceAllString(text, "")
+	for _, m := range licenseTagRE.FindAllStringSubmatch(text, -1) {
+		expression := strings.TrimSpace(m[1])
+		for trimmed := true; trimmed; {
+			trimmed = false
+			for _, end := range commentEnds {
+				if strings.HasSuffix(expression, end) {
+					expression = strings.TrimSpace(strings.TrimSuffix(expression, end))
+					trimmed = true
+				}
+			}
+		}
+		ids = append(ids, manifest.ExpressionIDs(expression)...)
+	}
+	return ids, copyrightTagRE.MatchString(text)
+}
+
+// ignored is true for the files which do not need copyright and licensing information
+func ignored(name string) bool {
+	return licenseFileRE.MatchString(name) || strings.HasSuffix(name, licenseSuffix) || strings.HasSuffix(name, ".spdx")
+}
+
+// licenseTexts returns the license IDs with a text in the LICENSES directory
+func licenseTexts(dir string) (map[string]bool, error) {
+	ret := make(map[string]bool)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ret, nil
+		}
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		id := e.Name()
+		if ext := filepath.Ext(id); licenseTextExts[ext] {
+			id = strings.TrimSuffix(id, ext)
+		}
+		ret[id] = true
+	}
+	return ret, nil
+}
+
+// valid is true for SPDX license and exception IDs and for LicenseRef- IDs
+func valid(id string, licenseLibrary *licenses.LicenseLibrary) bool {
+	if strings.HasPrefix(id, "LicenseRef-") {
+		return true
+	}
+	_, ok := licenseLibrary.LicenseMap[strings.TrimSuffix(id, "+")]
+	return ok
+}
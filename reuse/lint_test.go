@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package reuse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestTags(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		text        string
+		ids         []string
+		copyrighted bool
+	}{
+		{
+			name:        "line comment",
+			text:        "// SPDX-FileCopyrightText: 2023 Acme\n// SPDX-License-Identifier: Apache-2.0 OR MIT\n",
+			ids:         []string{"Apache-2.0", "MIT"},
+			copyrighted: true,
+		},
+		{
+			name:        "block comment end",
+			text:        "/* SPDX-License-Identifier: GPL-2.0-only WITH Classpath-exception-2.0 */\n/* Copyright (c) Acme */",
+			ids:         []string{"GPL-2.0-only", "Classpath-exception-2.0"},
+			copyrighted: true,
+		},
+		{
+			name: "html comment end",
+			text: "<!-- SPDX-License-Identifier: (MIT AND LicenseRef-Acme) -->",
+			ids:  []string{"MIT", "LicenseRef-Acme"},
+		},
+		{
+			name:        "ignored",
+			text:        "© Acme\nREUSE-IgnoreStart\nSPDX-License-Identifier: MIT\nREUSE-IgnoreEnd\n",
+			copyrighted: true,
+		},
+		{
+			name: "no tags",
+			text: "package main\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ids, copyrighted := Tags(tt.text)
+			if d := cmp.Diff(tt.ids, ids); d != "" {
+				t.Errorf("Tags() IDs mismatch (-want +got):\n%s", d)
+			}
+			if copyrighted != tt.copyrighted {
+				t.Errorf("Tags() copyrighted = %v, want %v", copyrighted, tt.copyrighted)
+			}
+		})
+	}
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+
+	write := func(t *testing.T, root string, files map[string]string) {
+		t.Helper()
+		for name, content := range files {
+			path := filepath.Join(root, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	header := "# SPDX-FileCopyrightText: Acme\n# SPDX-License-Identifier: MIT\n"
+
+	t.Run("compliant", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		write(t, root, map[string]string{
+			"main.py":                header,
+			"logo.png":               "\x89PNG",
+			"logo.png.license":       "SPDX-FileCopyrightText: Acme\nSPDX-License-Identifier: CC0-1.0\n",
+			"docs/guide.md":          "no tags here",
+			".reuse/dep5":            "Format: https://www.debian.org/doc/packaging-manuals/copyright-format/1.0/\n\nFiles: docs/*\nCopyright: Acme\nLicense: CC-BY-4.0\n",
+			"LICENSES/MIT.txt":       "MIT",
+			"LICENSES/CC0-1.0.txt":   "CC0",
+			"LICENSES/CC-BY-4.0.txt": "CC-BY",
+			"LICENSE":                "MIT",
+			".git/config":            "",
+		})
+		r, err := Lint(root, ll)
+		if err != nil {
+			t.Fatalf("Lint() error = %v", err)
+		}
+		want := &Report{
+			Files:        []string{"docs/guide.md", "logo.png", "main.py"},
+			UsedLicenses: map[string][]string{"MIT": {"main.py"}, "CC0-1.0": {"logo.png"}, "CC-BY-4.0": {"docs/guide.md"}},
+		}
+		if d := cmp.Diff(want, r); d != "" {
+			t.Errorf("Lint() mismatch (-want +got):\n%s", d)
+		}
+		if !r.Compliant() {
+			t.Errorf("Compliant() = false")
+		}
+	})
+
+	t.Run("not compliant", func(t *testing.T) {
+		t.Parallel()
+		root := t.TempDir()
+		write(t, root, map[string]string{
+			"main.py":               header,
+			"util.py":               "# SPDX-License-Identifier: Apache-2.0 OR Acme-1.0\n",
+			"setup.cfg":             "[metadata]\n",
+			"LICENSES/MIT.txt":      "MIT",
+			"LICENSES/BSD-2-Clause": "BSD",
+		})
+		r, err := Lint(root, ll)
+		if err != nil {
+			t.Fatalf("Lint() error = %v", err)
+		}
+		want := &Report{
+			Files:                []string{"main.py", "setup.cfg", "util.py"},
+			MissingLicenseInfo:   []string{"setup.cfg"},
+			MissingCopyrightInfo: []string{"setup.cfg", "util.py"},
+			UsedLicenses:         map[string][]string{"MIT": {"main.py"}, "Apache-2.0": {"util.py"}, "Acme-1.0": {"util.py"}},
+			MissingLicenses:      []string{"Acme-1.0", "Apache-2.0"},
+			UnusedLicenses:       []string{"BSD-2-Clause"},
+			BadLicenses:          []string{"Acme-1.0"},
+		}
+		if d := cmp.Diff(want, r); d != "" {
+			t.Errorf("Lint() mismatch (-want +got):\n%s", d)
+		}
+		if r.Compliant() {
+			t.Errorf("Compliant() = true")
+		}
+	})
+}
@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package reuse
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/manifest"
+	"github.com/IBM/license-scanner/repository"
+)
+
+// The sources of the detected licenses a declaration is verified against
+const (
+	SourceFile       = "file"
+	SourceRepository = "repository"
+)
+
+// Mismatch is a file whose declared licenses (SPDX-License-Identifier tags) are not the detected licenses
+type Mismatch struct {
+	// File is relative to the project root and uses forward slashes
+	File string
+	// Declared are the license IDs of the tags
+	Declared []string
+	// Detected are the license IDs found in the file, or of the repository license (see Source)
+	Detected []string
+	// Source is SourceFile or SourceRepository
+	Source string
+}
+
+// Verification is the result of verifying the SPDX tags of a project
+type Verification struct {
+	// Tagged are the files with an SPDX-License-Identifier tag (relative, with forward slashes)
+	Tagged []string
+	// Repository is the primary license of the project
+	Repository repository.Primary
+	Mismatches []Mismatch
+}
+
+// Verified is true when every tag agrees with the detected licenses
+func (v *Verification) Verified() bool {
+	return len(v.Mismatches) == 0
+}
+
+// Verify cross-checks the SPDX-License-Identifier tags of the files under the root directory (in the file or
+// in a <file>.license file) against the licenses detected in the same file: the license texts, notices, and
+// standard license headers. A file without any detected license is checked against the primary license of
+// the repository instead (if any). A tag is a mismatch when none of its license IDs was detected (e.g., a
+// header copied from a file of another license). The -only and -or-later (or +) variants of a license
+// are not told apart, since they have the same text.
+func Verify(root string, licenseLibrary *licenses.LicenseLibrary) (*Verification, error) {
+	v := &Verification{}
+	declared := make(map[string][]string)
+	var files []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && (vcsDirs[d.Name()] || rel == licensesDir || rel == reuseDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || strings.HasSuffix(rel, licenseSuffix) {
+			return nil
+		}
+		tagged := path
+		if _, err := os.Stat(path + licenseSuffix); err == nil {
+			tagged = path + licenseSuffix
+		}
+		b, err := os.ReadFile(tagged)
+		if err != nil {
+			return err
+		}
+		if ids, _ := Tags(string(b)); len(ids) > 0 {
+			declared[rel] = ids
+			v.Tagged = append(v.Tagged, rel)
+			files = append(files, rel)
+		} else if !strings.ContainsRune(rel, '/') {
+			files = append(files, rel) // the root files (e.g., LICENSE) for the repository license
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []identifier.IdentifierResults
+	detected := make(map[string][]string)
+	options := identifier.Options{OmitBlocks: true, Headers: true}
+	for _, rel := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		result, err := identifier.IdentifyLicensesInString(string(b), options, licenseLibrary)
+		if err != nil {
+			return nil, err
+		}
+		result.File = path
+		results = append(results, result)
+		for _, byID := range []map[string][]identifier.Match{result.Matches, result.HeaderMatches} {
+			for id := range byID {
+				if !slices.Contains(detected[rel], id) {
+					detected[rel] = append(detected[rel], id)
+				}
+			}
+		}
+		sort.Strings(detected[rel])
+	}
+	v.Repository = repository.PrimaryLicense(results, root)
+
+	for _, rel := range v.Tagged {
+		m := Mismatch{File: rel, Declared: declared[rel], Detected: detected[rel], Source: SourceFile}
+		if len(m.Detected) == 0 {
+			if v.Repository.Expression == repository.NoAssertion {
+				continue // nothing to verify against
+			}
+			m.Detected = manifest.ExpressionIDs(v.Repository.Expression)
+			m.Source = SourceRepository
+		}
+		if !agrees(m.Declared, m.Detected) {
+			v.Mismatches = append(v.Mismatches, m)
+		}
+	}
+	return v, nil
+}
+
+// agrees is true when any of the declared license IDs was detected
+func agrees(declared []string, detected []string) bool {
+	for _, d := range declared {
+		for _, id := range detected {
+			if strings.EqualFold(licenseFamily(d), licenseFamily(id)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// licenseFamily returns the license ID without the -only, -or-later, or + suffix (e.g., GPL-2.0)
+func licenseFamily(id string) string {
+	for _, suffix := range []string{"+", "-only", "-or-later"} {
+		id = strings.TrimSuffix(id, suffix)
+	}
+	return id
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package reuse
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+	ll, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	if err := ll.AddAllSPDX(); err != nil {
+		t.Fatalf("AddAllSPDX() error = %v", err)
+	}
+	mit, err := os.ReadFile(filepath.Join("..", "resources", "spdx", "default", "testdata", "MIT.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gplHeader := `// SPDX-License-Identifier: GPL-2.0-only
+//
+// This program is free software; you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation; either version 2 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program; if not, write to the Free Software
+// Foundation, Inc., 51 Franklin Street, Fifth Floor, Boston, MA 02110-1301 USA.
+`
+	mplHeader := `# SPDX-License-Identifier: MIT
+#
+# This Source Code Form is subject to the terms of the Mozilla Public
+# License, v. 2.0. If a copy of the MPL was not distributed with this
+# file, You can obtain one at https://mozilla.org/MPL/2.0/.
+`
+
+	root := t.TempDir()
+	files := map[string]string{
+		"LICENSE":           string(mit),
+		"main.go":           "// SPDX-License-Identifier: MIT\n\npackage main\n",
+		"dual.go":           "// SPDX-License-Identifier: Apache-2.0 OR MIT\n\npackage main\n",
+		"other.go":          "// SPDX-License-Identifier: Apache-2.0\n\npackage main\n",
+		"lib/gpl.c":         gplHeader,
+		"lib/copied.py":     mplHeader,
+		"logo.png":          "PNG",
+		"logo.png.license":  "SPDX-License-Identifier: CC0-1.0\n",
+		"untagged.go":       "package main\n",
+		".git/hooks/pre.sh": "# SPDX-License-Identifier: Apache-2.0\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := Verify(root, ll)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if got.Repository.Expression != "MIT" {
+		t.Errorf("Verify() Repository = %v, want MIT", got.Repository.Expression)
+	}
+	if d := cmp.Diff([]string{"dual.go", "lib/copied.py", "lib/gpl.c", "logo.png", "main.go", "other.go"}, got.Tagged); d != "" {
+		t.Errorf("Verify() Tagged mismatch (-want +got):\n%s", d)
+	}
+	want := []Mismatch{
+		{File: "lib/copied.py", Declared: []string{"MIT"}, Detected: []string{"MPL-2.0"}, Source: SourceFile},
+		{File: "logo.png", Declared: []string{"CC0-1.0"}, Detected: []string{"MIT"}, Source: SourceRepository},
+		{File: "other.go", Declared: []string{"Apache-2.0"}, Detected: []string{"MIT"}, Source: SourceRepository},
+	}
+	if d := cmp.Diff(want, got.Mismatches); d != "" {
+		t.Errorf("Verify() Mismatches mismatch (-want +got):\n%s", d)
+	}
+	if got.Verified() {
+		t.Error("Verify() Verified() = true, want false")
+	}
+}
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selftest verifies a license library at runtime: the templates of each license must match its own
+// SPDX license list text, e.g., to check a deployment (with its resources) before trusting the scan results.
+package selftest
+
+import (
+	"os"
+	"sort"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/licenses"
+	"github.com/IBM/license-scanner/normalizer"
+)
+
+// Failure is a license whose templates did not match its own text
+type Failure struct {
+	ID string `json:"id"`
+	// File is the SPDX license list text of the license
+	File string `json:"file"`
+	// Templates are the primary patterns which were tried (none when the license has no templates)
+	Templates []string `json:"templates"`
+	// Error is the error of a template which cannot be generated or matched ("" when they did not match)
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the result of the self-test of a license library
+type Report struct {
+	Passed int `json:"passed"`
+	// Failures are the licenses whose templates did not match their own text (sorted by ID)
+	Failures []Failure `json:"failures"`
+	// Skipped are the licenses without an SPDX license list text, e.g., the custom licenses (sorted)
+	Skipped []string `json:"skipped"`
+}
+
+// Run matches the primary patterns (the templates) of each license of the library with its SPDX license list
+// text (testdata), and reports the licenses which no template matched. Only the templates are matched (not
+// the exact hashes, aliases, or URLs of the licenses), so that a broken template is not hidden.
+func Run(licenseLibrary *licenses.LicenseLibrary) (Report, error) {
+	r := Report{Failures: []Failure{}, Skipped: []string{}}
+	var mu sync.Mutex
+	workers := errgroup.Group{}
+	workers.SetLimit(10)
+	for id, lic := range licenseLibrary.LicenseMap {
+		id, lic := id, lic
+		f := licenseLibrary.TestdataFile(id)
+		if _, err := os.Stat(f); err != nil {
+			r.Skipped = append(r.Skipped, id)
+			continue
+		}
+		workers.Go(func() error {
+			failure, err := check(id, lic, f, licenseLibrary.LinePrefixes)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if failure != nil {
+				r.Failures = append(r.Failures, *failure)
+			} else {
+				r.Passed++
+			}
+			return nil
+		})
+	}
+	if err := workers.Wait(); err != nil {
+		return r, err
+	}
+	sort.Slice(r.Failures, func(i, j int) bool { return r.Failures[i].ID < r.Failures[j].ID })
+	sort.Strings(r.Skipped)
+	return r, nil
+}
+
+// check returns a failure when no primary pattern of the license matches the text file
+func check(id string, lic licenses.License, file string, linePrefixes *normalizer.LinePrefixes) (*Failure, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	nd := normalizer.NormalizationData{OriginalText: string(b), LinePrefixes: linePrefixes}
+	if err := nd.NormalizeText(); err != nil {
+		return nil, err
+	}
+	failure := &Failure{ID: id, File: file, Templates: []string{}}
+	for _, p := range lic.PrimaryPatterns {
+		failure.Templates = append(failure.Templates, p.FileName)
+		matches, err := identifier.FindMatchingPatternInNormalizedData(p, nd)
+		if err != nil {
+			failure.Error = err.Error()
+			continue
+		}
+		if len(matches) > 0 {
+			return nil, nil
+		}
+	}
+	return failure, nil
+}
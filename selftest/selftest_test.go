@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package selftest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/configurer"
+	"github.com/IBM/license-scanner/licenses"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	licenseLibrary, err := licenses.NewLicenseLibrary(nil)
+	if err != nil {
+		t.Fatalf("NewLicenseLibrary() error = %v", err)
+	}
+	licenseLibrary.Config.Set(configurer.OnlyFlag, []string{"MIT", "0BSD", "GPL-2.0", "ISC"})
+	if err := licenseLibrary.AddAll(); err != nil {
+		t.Fatalf("AddAll() error = %v", err)
+	}
+	// A license without an SPDX license list text is skipped, and a license with the templates of another
+	// license fails (even though its text has its name, which is an alias)
+	licenseLibrary.LicenseMap["Custom-1.0"] = licenses.License{}
+	isc := licenseLibrary.LicenseMap["ISC"]
+	isc.PrimaryPatterns = licenseLibrary.LicenseMap["GPL-2.0"].PrimaryPatterns
+	licenseLibrary.LicenseMap["ISC"] = isc
+
+	got, err := Run(licenseLibrary)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := Report{
+		Passed:   3,
+		Failures: []Failure{{ID: "ISC", File: licenseLibrary.TestdataFile("ISC"), Templates: templates(isc.PrimaryPatterns)}},
+		Skipped:  []string{"Custom-1.0"},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Run() mismatch (-want +got):\n%s", d)
+	}
+}
+
+// templates returns the file names of the patterns
+func templates(patterns []*licenses.PrimaryPatterns) []string {
+	ret := []string{}
+	for _, p := range patterns {
+		ret = append(ret, p.FileName)
+	}
+	return ret
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/license-scanner/httpclient"
+)
+
+// S3Options are the region, the endpoint, and the credentials of the S3 uploads
+type S3Options struct {
+	// Region is the region of the bucket (us-east-1 when it is "")
+	Region string
+	// Endpoint is the URL of an S3-compatible service (e.g., MinIO), with the bucket in the path. The objects are
+	// uploaded to https://<bucket>.s3.<region>.amazonaws.com when it is "".
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is the token of temporary credentials ("" for long-term credentials)
+	SessionToken string
+	// Client sends the requests, with its timeout and retries (httpclient.Default() when it is nil)
+	Client *httpclient.Client
+}
+
+// S3OptionsFromEnv returns the S3 options of the standard AWS environment variables: AWS_REGION (or
+// AWS_DEFAULT_REGION), AWS_ENDPOINT_URL_S3 (or AWS_ENDPOINT_URL), AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and
+// AWS_SESSION_TOKEN
+func S3OptionsFromEnv() S3Options {
+	opts := S3Options{
+		Region:          os.Getenv("AWS_REGION"),
+		Endpoint:        os.Getenv("AWS_ENDPOINT_URL_S3"),
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if opts.Region == "" {
+		opts.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = os.Getenv("AWS_ENDPOINT_URL")
+	}
+	return opts
+}
+
+// PutS3 uploads the body as the object of the bucket with the key, signed with AWS Signature Version 4
+func PutS3(ctx context.Context, bucket string, key string, body []byte, opts S3Options) error {
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" {
+		return fmt.Errorf("cannot upload to s3://%v/%v: no AWS credentials (set AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY)", bucket, key)
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	objectURL := fmt.Sprintf("https://%v.s3.%v.amazonaws.com%v", bucket, opts.Region, uriEncode("/"+key, false))
+	if opts.Endpoint != "" {
+		objectURL = strings.TrimSuffix(opts.Endpoint, "/") + uriEncode("/"+bucket+"/"+key, false)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	sum := sha256.Sum256(body)
+	signV4(req, hex.EncodeToString(sum[:]), opts, time.Now())
+
+	client := opts.Client
+	if client == nil {
+		client = httpclient.Default()
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot upload to s3://%v/%v: %w", bucket, key, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// signV4 adds the AWS Signature Version 4 headers of the S3 request to it, signing the host and the headers of the
+// request with the SHA-256 of the payload
+func signV4(req *http.Request, payloadHash string, opts S3Options, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if opts.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", opts.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + strings.TrimSpace(headers[k]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + opts.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+opts.SecretAccessKey), date)
+	for _, s := range []string{opts.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, s)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v", opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery returns the query parameters sorted by name, URI-encoded
+func canonicalQuery(query url.Values) string {
+	params := make([]string, 0, len(query))
+	for k, values := range query {
+		for _, v := range values {
+			params = append(params, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// uriEncode encodes the characters of s other than the unreserved characters (and the slashes, unless
+// encodeSlash), like AWS Signature Version 4
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the data with the key
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
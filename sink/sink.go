@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sink writes the reports of a scan to their destinations: the standard output, a file, a webhook (an HTTP
+// POST), an S3 object, or a database table, so that one scan can write several reports, e.g., a human summary to
+// the standard output and the full JSON to a file.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/IBM/license-scanner/httpclient"
+	"github.com/IBM/license-scanner/webhook"
+)
+
+// Stdout is the destination of the standard output
+const Stdout = "-"
+
+// ErrOffline is returned by Open for the destinations which need network access when the sinks are offline
+var ErrOffline = httpclient.ErrOffline
+
+// Sink is the destination of a report. The report is written with Write, and it is complete (e.g., uploaded)
+// when Close returns nil.
+type Sink interface {
+	io.WriteCloser
+	// Name is the destination of the sink (e.g., the file path or the URL)
+	Name() string
+}
+
+// Options configure the sinks which send the report to a service
+type Options struct {
+	// Webhook configures the POST of the http and https destinations
+	Webhook webhook.Options
+	// S3 configures the upload of the s3 destinations
+	S3 S3Options
+	// Offline fails the http, https, and s3 destinations (with ErrOffline) instead of accessing the network
+	Offline bool
+}
+
+// DefaultOptions are the options of the sinks without credentials
+var DefaultOptions = Options{Webhook: webhook.DefaultOptions}
+
+// Open returns the sink of the destination: "-" for the standard output, an http:// or https:// URL to POST the
+// report to (a webhook), an s3://<bucket>/<key> URL to upload the report to, or else a file path (which is created
+// or truncated). The webhook and S3 sinks send the report when they are closed.
+func Open(ctx context.Context, dest string, opts Options) (Sink, error) {
+	if opts.Offline && Remote(dest) {
+		return nil, fmt.Errorf("cannot send the report to %v: %w", dest, ErrOffline)
+	}
+	switch {
+	case dest == Stdout:
+		return &stdout{}, nil
+	case strings.HasPrefix(dest, "http://") || strings.HasPrefix(dest, "https://"):
+		return &buffered{name: dest, send: func(b []byte) error {
+			return webhook.Post(ctx, dest, b, opts.Webhook)
+		}}, nil
+	case strings.HasPrefix(dest, "s3://"):
+		bucket, key, ok := strings.Cut(strings.TrimPrefix(dest, "s3://"), "/")
+		if !ok || bucket == "" || key == "" {
+			return nil, fmt.Errorf("invalid S3 destination %q (expected s3://<bucket>/<key>)", dest)
+		}
+		return &buffered{name: dest, send: func(b []byte) error {
+			return PutS3(ctx, bucket, key, b, opts.S3)
+		}}, nil
+	}
+	f, err := os.Create(dest)
+	if err != nil {
+		return nil, err
+	}
+	return &file{File: f}, nil
+}
+
+// Remote returns whether the report is sent to the destination over the network (an http, https, or s3 URL)
+func Remote(dest string) bool {
+	for _, scheme := range []string{"http://", "https://", "s3://"} {
+		if strings.HasPrefix(dest, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSQL returns a sink which executes the query with the report (as a string) as its only argument when it is
+// closed, e.g., "INSERT INTO scans (report) VALUES ($1)" with the placeholder of the database driver. The program
+// opens the database with its driver.
+func NewSQL(ctx context.Context, db *sql.DB, query string) Sink {
+	return &buffered{name: "sql", send: func(b []byte) error {
+		if _, err := db.ExecContext(ctx, query, string(b)); err != nil {
+			return fmt.Errorf("cannot insert the report: %w", err)
+		}
+		return nil
+	}}
+}
+
+// stdout writes to the standard output, which is not closed
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+func (stdout) Close() error                { return nil }
+func (stdout) Name() string                { return Stdout }
+
+// file writes to a file
+type file struct {
+	*os.File
+}
+
+// buffered keeps the report in memory, and sends it when it is closed
+type buffered struct {
+	name string
+	buf  bytes.Buffer
+	send func([]byte) error
+}
+
+func (b *buffered) Write(p []byte) (int, error) { return b.buf.Write(p) }
+func (b *buffered) Close() error                { return b.send(b.buf.Bytes()) }
+func (b *buffered) Name() string                { return b.name }
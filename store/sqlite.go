@@ -0,0 +1,10 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build cgo
+
+package store
+
+import (
+	// The SQLite driver, which needs cgo
+	_ "github.com/mattn/go-sqlite3"
+)
@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package store keeps the findings of the scans in a SQLite database (a run per scan, with the status and the
+// licenses of each scanned file), so that the license inventory can be tracked over time without a service.
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/jsonl"
+	"github.com/IBM/license-scanner/metadata"
+)
+
+// ErrNoRuns is returned for the latest run of a database without runs
+var ErrNoRuns = errors.New("no scans were recorded in the database")
+
+// ErrNoSQLite is returned by Open when the scanner was built without the SQLite driver, which needs cgo
+var ErrNoSQLite = errors.New("the results database is not available in this build of the scanner (SQLite needs a build with CGO_ENABLED=1)")
+
+// driver is the name of the SQLite driver, which is registered by sqlite.go in the builds with cgo
+const driver = "sqlite3"
+
+// Available reports whether the scanner was built with the SQLite driver of the results database
+func Available() bool {
+	for _, d := range sql.Drivers() {
+		if d == driver {
+			return true
+		}
+	}
+	return false
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	scanned TEXT NOT NULL,
+	git_commit TEXT NOT NULL DEFAULT '',
+	time TEXT NOT NULL,
+	version TEXT NOT NULL,
+	config_hash TEXT NOT NULL,
+	metadata TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS files (
+	run INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	path TEXT NOT NULL,
+	hash TEXT NOT NULL,
+	status TEXT NOT NULL,
+	PRIMARY KEY (run, path)
+);
+CREATE TABLE IF NOT EXISTS findings (
+	run INTEGER NOT NULL REFERENCES runs (id) ON DELETE CASCADE,
+	path TEXT NOT NULL,
+	license TEXT NOT NULL,
+	PRIMARY KEY (run, path, license)
+);
+CREATE INDEX IF NOT EXISTS files_path ON files (path);
+CREATE INDEX IF NOT EXISTS findings_license ON findings (license);
+`
+
+// Store is a SQLite database of the findings of the scans
+type Store struct {
+	db *sql.DB
+}
+
+// Run is a recorded scan
+type Run struct {
+	ID int64 `json:"id"`
+	// Scanned is the scanned file or directory
+	Scanned string `json:"scanned"`
+	// Commit is the git commit of the scanned directory ("" when it is not in a git repository)
+	Commit string `json:"commit,omitempty"`
+	// Time is when the scan ended (UTC, when it was recorded with --deterministic)
+	Time time.Time `json:"time"`
+	// Version is the version of the scanner
+	Version string `json:"version"`
+	// ConfigHash is the SHA-256 of the configuration of the scan (see metadata.ConfigHash)
+	ConfigHash string `json:"configHash"`
+	// Files is the number of scanned files, and Licenses is the number of distinct licenses found in them
+	Files    int `json:"files"`
+	Licenses int `json:"licenses"`
+}
+
+// File is the finding of a scanned file in a run
+type File struct {
+	Run int64 `json:"run"`
+	// Path is relative to the scanned directory (with forward slashes)
+	Path string `json:"path"`
+	// Hash is the SHA-256 of the normalized file text
+	Hash string `json:"hash"`
+	// Status is the license state of the file (see jsonl.Record)
+	Status string `json:"status"`
+	// Licenses are the detected license IDs (sorted)
+	Licenses []string `json:"licenses"`
+}
+
+// LicenseCount is the number of files of a run with a license
+type LicenseCount struct {
+	License string `json:"license"`
+	Files   int    `json:"files"`
+}
+
+// Open opens the SQLite database file, and creates it and its tables when they do not exist
+func Open(file string) (*Store, error) {
+	if !Available() {
+		return nil, ErrNoSQLite
+	}
+	db, err := sql.Open(driver, "file:"+file+"?_foreign_keys=on&_busy_timeout=5000")
+	if err != nil {
+		return nil, err
+	}
+	if err := migrate(db); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("cannot open the results database %v: %w", file, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// migrate creates the tables, and adds the columns which the tables of an older database do not have
+func migrate(db *sql.DB) error {
+	if _, err := db.Exec(schema); err != nil {
+		return err
+	}
+	var commitColumns int
+	if err := db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('runs') WHERE name = 'git_commit'").Scan(&commitColumns); err != nil {
+		return err
+	}
+	if commitColumns == 0 {
+		_, err := db.Exec("ALTER TABLE runs ADD COLUMN git_commit TEXT NOT NULL DEFAULT ''")
+		return err
+	}
+	return nil
+}
+
+// Close closes the database
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// AddRun records the results of the scanned file or directory as a run, with the git commit of the scanned
+// directory, the file paths relative to the root, and the metadata of the scan
+func (s *Store) AddRun(scanned string, commit string, root string, results []identifier.IdentifierResults, m metadata.Metadata) (Run, error) {
+	run := Run{Scanned: scanned, Commit: commit, Time: time.Now().UTC().Truncate(time.Second), Version: m.Version.Version, ConfigHash: m.ConfigHash, Files: len(results)}
+	if m.End != nil {
+		run.Time = m.End.UTC().Truncate(time.Second)
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return Run{}, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Run{}, err
+	}
+	defer func() { _ = tx.Rollback() }()
+	res, err := tx.Exec("INSERT INTO runs (scanned, git_commit, time, version, config_hash, metadata) VALUES (?, ?, ?, ?, ?, ?)",
+		run.Scanned, run.Commit, run.Time.Format(time.RFC3339), run.Version, run.ConfigHash, string(b))
+	if err != nil {
+		return Run{}, fmt.Errorf("cannot record the run: %w", err)
+	}
+	if run.ID, err = res.LastInsertId(); err != nil {
+		return Run{}, err
+	}
+	licenses := map[string]bool{}
+	for _, result := range results {
+		r := jsonl.FromResult(result, root)
+		if _, err := tx.Exec("INSERT INTO files (run, path, hash, status) VALUES (?, ?, ?, ?)", run.ID, r.File, r.Hash, r.Status); err != nil {
+			return Run{}, fmt.Errorf("cannot record the file %v: %w", r.File, err)
+		}
+		for _, id := range r.Licenses {
+			if _, err := tx.Exec("INSERT INTO findings (run, path, license) VALUES (?, ?, ?)", run.ID, r.File, id); err != nil {
+				return Run{}, fmt.Errorf("cannot record the license %v of the file %v: %w", id, r.File, err)
+			}
+			licenses[id] = true
+		}
+	}
+	run.Licenses = len(licenses)
+	return run, tx.Commit()
+}
+
+// Runs returns the recorded runs, oldest first
+func (s *Store) Runs() ([]Run, error) {
+	return s.runs("")
+}
+
+// Run returns the recorded run with the ID, or the latest run when the ID is 0
+func (s *Store) Run(id int64) (Run, error) {
+	where := "WHERE r.id = COALESCE(NULLIF(?, 0), (SELECT MAX(id) FROM runs))"
+	runs, err := s.runs(where, id)
+	switch {
+	case err != nil:
+		return Run{}, err
+	case len(runs) > 0:
+		return runs[0], nil
+	case id == 0:
+		return Run{}, ErrNoRuns
+	}
+	return Run{}, fmt.Errorf("no run %v was recorded in the database", id)
+}
+
+func (s *Store) runs(where string, args ...interface{}) ([]Run, error) {
+	rows, err := s.db.Query(`SELECT r.id, r.scanned, r.git_commit, r.time, r.version, r.config_hash,
+	(SELECT COUNT(*) FROM files f WHERE f.run = r.id),
+	(SELECT COUNT(DISTINCT license) FROM findings l WHERE l.run = r.id)
+FROM runs r `+where+` ORDER BY r.id`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := []Run{}
+	for rows.Next() {
+		var r Run
+		var t string
+		if err := rows.Scan(&r.ID, &r.Scanned, &r.Commit, &t, &r.Version, &r.ConfigHash, &r.Files, &r.Licenses); err != nil {
+			return nil, err
+		}
+		if r.Time, err = time.Parse(time.RFC3339, t); err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, rows.Err()
+}
+
+// Files returns the files of the run with their licenses, sorted by path
+func (s *Store) Files(run int64) ([]File, error) {
+	return s.files("WHERE f.run = ?", run)
+}
+
+// FilesWithLicense returns the files of the run with a license matching the pattern (case-insensitive, wildcards
+// like GPL-* allowed), sorted by path
+func (s *Store) FilesWithLicense(run int64, pattern string) ([]File, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid license ID pattern %q: %w", pattern, err)
+	}
+	files, err := s.Files(run)
+	if err != nil {
+		return nil, err
+	}
+	ret := []File{}
+	for _, f := range files {
+		for _, id := range f.Licenses {
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(id)); matched {
+				ret = append(ret, f)
+				break
+			}
+		}
+	}
+	return ret, nil
+}
+
+// FileHistory returns the file (relative to the scanned directory) in each run which scanned it, oldest first
+func (s *Store) FileHistory(file string) ([]File, error) {
+	return s.files("WHERE f.path = ?", file)
+}
+
+func (s *Store) files(where string, arg interface{}) ([]File, error) {
+	rows, err := s.db.Query(`SELECT f.run, f.path, f.hash, f.status, COALESCE(GROUP_CONCAT(l.license, ' '), '')
+FROM files f LEFT JOIN findings l ON l.run = f.run AND l.path = f.path `+where+`
+GROUP BY f.run, f.path ORDER BY f.run, f.path`, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := []File{}
+	for rows.Next() {
+		var f File
+		var licenses string
+		if err := rows.Scan(&f.Run, &f.Path, &f.Hash, &f.Status, &licenses); err != nil {
+			return nil, err
+		}
+		f.Licenses = strings.Fields(licenses)
+		sort.Strings(f.Licenses)
+		ret = append(ret, f)
+	}
+	return ret, rows.Err()
+}
+
+// Inventory returns the licenses found in the run with the number of files of each, sorted by license
+func (s *Store) Inventory(run int64) ([]LicenseCount, error) {
+	rows, err := s.db.Query("SELECT license, COUNT(*) FROM findings WHERE run = ? GROUP BY license ORDER BY license", run)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := []LicenseCount{}
+	for rows.Next() {
+		var c LicenseCount
+		if err := rows.Scan(&c.License, &c.Files); err != nil {
+			return nil, err
+		}
+		ret = append(ret, c)
+	}
+	return ret, rows.Err()
+}
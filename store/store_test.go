@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit && cgo
+
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/IBM/license-scanner/identifier"
+	"github.com/IBM/license-scanner/metadata"
+	"github.com/IBM/license-scanner/normalizer"
+	"github.com/IBM/license-scanner/version"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "results.sqlite")
+	s, err := Open(file)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if _, err := s.Run(0); !errors.Is(err, ErrNoRuns) {
+		t.Errorf("Run(0) error = %v, want %v", err, ErrNoRuns)
+	}
+
+	root := filepath.FromSlash("/src/project")
+	match := []identifier.Match{{Begins: 0, Ends: 10}}
+	first := []identifier.IdentifierResults{
+		{File: filepath.Join(root, "LICENSE"), Hash: normalizer.Digest{Sha256: "aaa"}, Matches: map[string][]identifier.Match{"MIT": match}},
+		{File: filepath.Join(root, "lib", "gpl.c"), Hash: normalizer.Digest{Sha256: "bbb"}, Matches: map[string][]identifier.Match{"GPL-2.0-only": match, "MIT": match}},
+	}
+	end := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	m := metadata.Metadata{Version: version.Info{Version: "1.2.3"}, ConfigHash: "ccc", End: &end}
+	run, err := s.AddRun(root, "c0ffee", root, first, m)
+	if err != nil {
+		t.Fatalf("AddRun() error = %v", err)
+	}
+	wantRun := Run{ID: 1, Scanned: root, Commit: "c0ffee", Time: end, Version: "1.2.3", ConfigHash: "ccc", Files: 2, Licenses: 2}
+	if d := cmp.Diff(wantRun, run); d != "" {
+		t.Errorf("AddRun() mismatch (-want +got):\n%s", d)
+	}
+	// The relicensed file of the second run (with --deterministic metadata)
+	second := []identifier.IdentifierResults{
+		first[0],
+		{File: filepath.Join(root, "lib", "gpl.c"), Hash: normalizer.Digest{Sha256: "ddd"}, Matches: map[string][]identifier.Match{"Apache-2.0": match}},
+	}
+	if _, err := s.AddRun(root, "", root, second, metadata.Metadata{Version: version.Info{Version: "1.2.4"}}); err != nil {
+		t.Fatalf("AddRun() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The runs are kept in the database file
+	if s, err = Open(file); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer s.Close()
+	runs, err := s.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	wantRuns := []Run{wantRun, {ID: 2, Scanned: root, Version: "1.2.4", Files: 2, Licenses: 2}}
+	if d := cmp.Diff(wantRuns, runs, cmpopts.IgnoreFields(Run{}, "Time")); d != "" {
+		t.Errorf("Runs() mismatch (-want +got):\n%s", d)
+	}
+	latest, err := s.Run(0)
+	if err != nil || latest.ID != 2 {
+		t.Errorf("Run(0) = %v, %v, want the run 2", latest, err)
+	}
+	if _, err := s.Run(3); err == nil {
+		t.Error("Run(3) error = nil, want an error for a missing run")
+	}
+
+	inventory, err := s.Inventory(1)
+	if err != nil {
+		t.Fatalf("Inventory() error = %v", err)
+	}
+	if d := cmp.Diff([]LicenseCount{{License: "GPL-2.0-only", Files: 1}, {License: "MIT", Files: 2}}, inventory); d != "" {
+		t.Errorf("Inventory() mismatch (-want +got):\n%s", d)
+	}
+
+	gpl := File{Run: 1, Path: "lib/gpl.c", Hash: "bbb", Status: "licensed", Licenses: []string{"GPL-2.0-only", "MIT"}}
+	files, err := s.FilesWithLicense(1, "gpl-*")
+	if err != nil {
+		t.Fatalf("FilesWithLicense() error = %v", err)
+	}
+	if d := cmp.Diff([]File{gpl}, files); d != "" {
+		t.Errorf("FilesWithLicense() mismatch (-want +got):\n%s", d)
+	}
+	if files, err = s.FilesWithLicense(2, "GPL-*"); err != nil || len(files) != 0 {
+		t.Errorf("FilesWithLicense(2) = %v, %v, want no files", files, err)
+	}
+	if _, err := s.FilesWithLicense(1, "GPL-["); err == nil {
+		t.Error("FilesWithLicense() error = nil, want an error for a bad pattern")
+	}
+
+	history, err := s.FileHistory("lib/gpl.c")
+	if err != nil {
+		t.Fatalf("FileHistory() error = %v", err)
+	}
+	wantHistory := []File{gpl, {Run: 2, Path: "lib/gpl.c", Hash: "ddd", Status: "licensed", Licenses: []string{"Apache-2.0"}}}
+	if d := cmp.Diff(wantHistory, history); d != "" {
+		t.Errorf("FileHistory() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestOpen_migrate(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "results.sqlite")
+	db, err := sql.Open("sqlite3", file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The runs of a database without the git commits
+	if _, err := db.Exec(`CREATE TABLE runs (id INTEGER PRIMARY KEY AUTOINCREMENT, scanned TEXT NOT NULL, time TEXT NOT NULL,
+	version TEXT NOT NULL, config_hash TEXT NOT NULL, metadata TEXT NOT NULL);
+INSERT INTO runs (scanned, time, version, config_hash, metadata) VALUES ('.', '2023-05-01T12:00:00Z', '1.2.3', 'ccc', '{}')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := Open(file)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer s.Close()
+	if _, err := s.AddRun(".", "c0ffee", ".", nil, metadata.Metadata{}); err != nil {
+		t.Fatalf("AddRun() error = %v", err)
+	}
+	runs, err := s.Runs()
+	if err != nil {
+		t.Fatalf("Runs() error = %v", err)
+	}
+	if len(runs) != 2 || runs[0].Commit != "" || runs[1].Commit != "c0ffee" {
+		t.Errorf("Runs() = %+v, want the old run without a commit and the new run with its commit", runs)
+	}
+}
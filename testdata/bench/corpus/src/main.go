@@ -0,0 +1,6 @@
+package main
+
+// main prints a greeting
+func main() {
+	println("hello")
+}
@@ -0,0 +1,8 @@
+module example.com/main
+
+go 1.18
+
+require (
+	example.com/foo v1.0.0
+	example.com/Bar v0.2.0 // indirect
+)
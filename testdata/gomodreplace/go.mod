@@ -0,0 +1,7 @@
+module example.com/main
+
+go 1.18
+
+require example.com/local v1.0.0
+
+replace example.com/local => ./local
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package version has the build information of the binary (its version, git commit, and Go version) and the
+// SPDX license list versions of the resources, so the reports can be reproduced and support requests answered.
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Version and Commit are set when building a release, e.g.,
+//
+//	go build -ldflags "-X github.com/IBM/license-scanner/version.Version=1.2.3 -X github.com/IBM/license-scanner/version.Commit=$(git rev-parse HEAD)"
+//
+// Otherwise, they are read from the build information of the binary (the module version of go install, and
+// the VCS revision of go build in a clone).
+var (
+	Version = ""
+	Commit  = ""
+)
+
+// DevVersion is the version of a build without a version (e.g., go build in a clone)
+const DevVersion = "0.0.0"
+
+// Info is the build information of the binary and the SPDX license lists of the resources
+type Info struct {
+	Version string `json:"version"`
+	// Commit is the git commit of the build (with a -dirty suffix when the tree was modified), "" when unknown
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"goVersion"`
+	// LicenseLists are the SPDX license lists of the resources (the loaded one in a report)
+	LicenseLists []LicenseList `json:"licenseLists,omitempty"`
+}
+
+// LicenseList is the SPDX license list version of the SPDX resources
+type LicenseList struct {
+	// SPDX is the directory of the SPDX resources (the --spdx)
+	SPDX string `json:"spdx"`
+	// Version is the SPDX license list version ("" when unknown)
+	Version string `json:"version,omitempty"`
+	// Import is the provenance of the resources imported by the importer (nil without an import manifest)
+	Import *Import `json:"import,omitempty"`
+}
+
+// Import is the summary of the import manifest of the SPDX resources
+type Import struct {
+	// Source is the license-list-data or license-list-XML directory of the import
+	Source string `json:"source"`
+	// Imported is when the import finished (UTC)
+	Imported time.Time `json:"imported"`
+	// Valid and Invalid are the numbers of the templates which were (not) validated and imported
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+}
+
+// String returns the source, time, and template counts of the import
+func (i Import) String() string {
+	return fmt.Sprintf("imported from %v at %v, %v valid and %v invalid templates", i.Source, i.Imported.UTC().Format(time.RFC3339), i.Valid, i.Invalid)
+}
+
+// Get returns the build information of the binary with the license lists
+func Get(licenseLists ...LicenseList) Info {
+	bi, _ := debug.ReadBuildInfo()
+	return fromBuildInfo(Version, Commit, bi, licenseLists)
+}
+
+// fromBuildInfo returns the build information with the version and commit (of the ldflags) when they are
+// set, or else from the build information of the binary (nil when there is none)
+func fromBuildInfo(version string, commit string, bi *debug.BuildInfo, licenseLists []LicenseList) Info {
+	i := Info{Version: version, Commit: commit, GoVersion: runtime.Version(), LicenseLists: licenseLists}
+	if bi != nil {
+		if i.Version == "" && bi.Main.Version != "(devel)" {
+			i.Version = bi.Main.Version
+		}
+		if bi.GoVersion != "" {
+			i.GoVersion = bi.GoVersion
+		}
+		if i.Commit == "" {
+			var modified bool
+			for _, s := range bi.Settings {
+				switch s.Key {
+				case "vcs.revision":
+					i.Commit = s.Value
+				case "vcs.modified":
+					modified = s.Value == "true"
+				}
+			}
+			if i.Commit != "" && modified {
+				i.Commit += "-dirty"
+			}
+		}
+	}
+	if i.Version == "" {
+		i.Version = DevVersion
+	}
+	return i
+}
+
+// String returns the version output, a line for the version, the commit, the Go version, and each license list
+func (i Info) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "license-scanner version %v\n", i.Version)
+	commit := i.Commit
+	if commit == "" {
+		commit = "unknown"
+	}
+	fmt.Fprintf(&b, "commit: %v\n", commit)
+	fmt.Fprintf(&b, "go version: %v\n", i.GoVersion)
+	for _, l := range i.LicenseLists {
+		v := l.Version
+		if v == "" {
+			v = "unknown"
+		}
+		if l.Import != nil {
+			fmt.Fprintf(&b, "SPDX license list: %v (spdx/%v, %v)\n", v, l.SPDX, l.Import)
+			continue
+		}
+		fmt.Fprintf(&b, "SPDX license list: %v (spdx/%v)\n", v, l.SPDX)
+	}
+	return b.String()
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	t.Parallel()
+	vcs := func(revision string, modified string) []debug.BuildSetting {
+		return []debug.BuildSetting{{Key: "vcs", Value: "git"}, {Key: "vcs.revision", Value: revision}, {Key: "vcs.modified", Value: modified}}
+	}
+	tests := []struct {
+		name    string
+		version string
+		commit  string
+		bi      *debug.BuildInfo
+		want    Info
+	}{
+		{name: "no build info", want: Info{Version: DevVersion, GoVersion: runtime.Version()}},
+		{name: "ldflags", version: "1.2.3", commit: "abc", bi: &debug.BuildInfo{GoVersion: "go1.21.0", Main: debug.Module{Version: "v1.0.0"}, Settings: vcs("def", "true")}, want: Info{Version: "1.2.3", Commit: "abc", GoVersion: "go1.21.0"}},
+		{name: "go install", bi: &debug.BuildInfo{GoVersion: "go1.21.0", Main: debug.Module{Version: "v1.0.0"}}, want: Info{Version: "v1.0.0", GoVersion: "go1.21.0"}},
+		{name: "clone", bi: &debug.BuildInfo{GoVersion: "go1.21.0", Main: debug.Module{Version: "(devel)"}, Settings: vcs("def", "false")}, want: Info{Version: DevVersion, Commit: "def", GoVersion: "go1.21.0"}},
+		{name: "modified clone", bi: &debug.BuildInfo{GoVersion: "go1.21.0", Main: debug.Module{Version: "(devel)"}, Settings: vcs("def", "true")}, want: Info{Version: DevVersion, Commit: "def-dirty", GoVersion: "go1.21.0"}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, fromBuildInfo(tt.version, tt.commit, tt.bi, nil)); d != "" {
+				t.Errorf("fromBuildInfo() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		info Info
+		want string
+	}{
+		{
+			name: "unknown commit",
+			info: Info{Version: DevVersion, GoVersion: "go1.21.0"},
+			want: "license-scanner version 0.0.0\ncommit: unknown\ngo version: go1.21.0\n",
+		},
+		{
+			name: "license lists",
+			info: Info{Version: "1.2.3", Commit: "abc", GoVersion: "go1.21.0", LicenseLists: []LicenseList{{SPDX: "3.17", Version: "3.17"}, {SPDX: "default", Version: "3.21"}, {SPDX: "mine"}}},
+			want: "license-scanner version 1.2.3\ncommit: abc\ngo version: go1.21.0\nSPDX license list: 3.17 (spdx/3.17)\nSPDX license list: 3.21 (spdx/default)\nSPDX license list: unknown (spdx/mine)\n",
+		},
+		{
+			name: "imported license list",
+			info: Info{Version: "1.2.3", Commit: "abc", GoVersion: "go1.21.0", LicenseLists: []LicenseList{{SPDX: "3.21", Version: "3.21", Import: &Import{Source: "/src/license-list-data", Imported: time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC), Valid: 600, Invalid: 2}}}},
+			want: "license-scanner version 1.2.3\ncommit: abc\ngo version: go1.21.0\nSPDX license list: 3.21 (spdx/3.21, imported from /src/license-list-data at 2023-06-01T12:00:00Z, 600 valid and 2 invalid templates)\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if d := cmp.Diff(tt.want, tt.info.String()); d != "" {
+				t.Errorf("String() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
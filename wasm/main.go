@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build js && wasm
+
+// Command wasm is the license detector for WebAssembly (GOOS=js GOARCH=wasm), e.g., to identify licenses in a
+// browser. The compiled license library is embedded, so the detector reads no files (build it with make wasm).
+// It sets the licenseScannerScanText(text) function of JavaScript, which returns the JSON of the scan result.
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"syscall/js"
+
+	"github.com/IBM/license-scanner/api/scanner"
+)
+
+//go:embed license-library.bin
+var compiledLibrary []byte
+
+// result is the JSON of a scan result for JavaScript
+type result struct {
+	Licenses scanner.Licenses `json:"licenses"`
+	TimedOut bool             `json:"timedOut,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+func main() {
+	sc, err := scanner.NewScannerFromCompiled(bytes.NewReader(compiledLibrary), nil)
+	js.Global().Set("licenseScannerScanText", js.FuncOf(func(this js.Value, args []js.Value) any {
+		var r result
+		switch {
+		case err != nil:
+			r.Error = err.Error()
+		case len(args) != 1 || args[0].Type() != js.TypeString:
+			r.Error = "licenseScannerScanText takes the license text (a string)"
+		default:
+			sr := sc.ScanText(args[0].String())
+			r.Licenses, r.TimedOut = sr.CycloneDXLicenses, sr.TimedOut
+			if sr.Error != nil {
+				r.Error = sr.Error.Error()
+			}
+		}
+		b, _ := json.Marshal(r)
+		return string(b)
+	}))
+	// keep the functions of the Go program available to JavaScript
+	select {}
+}
@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+
+// Package webhook posts the JSON report of a scan to a compliance service when the scan completes, retrying with
+// a backoff when the service is unavailable.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/IBM/license-scanner/httpclient"
+)
+
+// Options configure the post of a report
+type Options struct {
+	// Token is sent as the bearer token of the Authorization header (none when it is "")
+	Token string
+	// Client sends the requests, with its timeout and retries (httpclient.Default() when it is nil)
+	Client *httpclient.Client
+}
+
+// DefaultOptions are the options of a post without a token, with the default client
+var DefaultOptions = Options{}
+
+// StatusError is the response of the service to a post which it did not accept
+type StatusError = httpclient.StatusError
+
+// Post posts the JSON body to the URL with the client of the options. A network error or a 5xx, 429, or 408 response
+// is retried after the backoff of the client (doubled for each retry), and any other response which is not 2xx fails
+// at once. The error of the last attempt is returned when all the attempts failed.
+func Post(ctx context.Context, url string, body []byte, opts Options) error {
+	client := opts.Client
+	if client == nil {
+		client = httpclient.Default()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if opts.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot post the results to %v: %w", url, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
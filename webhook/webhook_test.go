@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/IBM/license-scanner/httpclient"
+)
+
+// newClient returns a client with the retries and a short backoff
+func newClient(t *testing.T, retries int) *httpclient.Client {
+	t.Helper()
+	client, err := httpclient.New(httpclient.Options{Timeout: httpclient.DefaultTimeout, Retries: retries, Backoff: time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestPost(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		// statuses are the responses of the service to the attempts (the last one is repeated)
+		statuses     []int
+		retries      int
+		wantAttempts int32
+		wantStatus   int
+	}{
+		{name: "accepted", statuses: []int{http.StatusCreated}, retries: 3, wantAttempts: 1},
+		{name: "retried until accepted", statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, retries: 3, wantAttempts: 3},
+		{name: "retries exhausted", statuses: []int{http.StatusBadGateway}, retries: 2, wantAttempts: 3, wantStatus: http.StatusBadGateway},
+		{name: "not retried", statuses: []int{http.StatusUnauthorized}, retries: 3, wantAttempts: 1, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(atomic.AddInt32(&attempts, 1))
+				if r.Method != http.MethodPost || r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Content-Type") != "application/json" {
+					t.Errorf("got request %v %v", r.Method, r.Header)
+				}
+				if b, _ := io.ReadAll(r.Body); string(b) != `{"status":"licensed"}` {
+					t.Errorf("got body %s", b)
+				}
+				status := tt.statuses[len(tt.statuses)-1]
+				if n <= len(tt.statuses) {
+					status = tt.statuses[n-1]
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			err := Post(context.Background(), srv.URL, []byte(`{"status":"licensed"}`), Options{Token: "secret", Client: newClient(t, tt.retries)})
+			var statusErr *StatusError
+			switch {
+			case tt.wantStatus == 0 && err != nil:
+				t.Errorf("Post() error = %v", err)
+			case tt.wantStatus != 0 && !errors.As(err, &statusErr):
+				t.Errorf("Post() error = %v, want the status %v", err, tt.wantStatus)
+			case tt.wantStatus != 0 && statusErr.StatusCode != tt.wantStatus:
+				t.Errorf("Post() status = %v, want %v", statusErr.StatusCode, tt.wantStatus)
+			}
+			if d := cmp.Diff(tt.wantAttempts, atomic.LoadInt32(&attempts)); d != "" {
+				t.Errorf("Post() attempts mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestPost_unreachable(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+	if err := Post(context.Background(), url, []byte("{}"), Options{Client: newClient(t, 1)}); err == nil {
+		t.Error("Post() error = nil, want an error for an unreachable service")
+	}
+}